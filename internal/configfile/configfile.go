@@ -14,7 +14,7 @@ const ConfigFileName = "metadata.json"
 type Config struct {
 	Database    string `json:"database"`
 	JSONLExport string `json:"jsonl_export,omitempty"`
-	Backend     string `json:"backend,omitempty"` // "sqlite" (default), "dolt", or "mariadb"
+	Backend     string `json:"backend,omitempty"` // "sqlite" (default), "dolt", "mariadb", or "postgres"
 
 	// Deletions configuration
 	DeletionsRetentionDays int `json:"deletions_retention_days,omitempty"` // 0 means use default (3 days)
@@ -36,6 +36,14 @@ type Config struct {
 	MariaDBDatabase string `json:"mariadb_database,omitempty"` // Database name (default: beads)
 	// Note: Password should be set via BEADS_MARIADB_PASSWORD env var for security
 
+	// Postgres connection configuration
+	PostgresHost     string `json:"postgres_host,omitempty"`     // Server host (default: 127.0.0.1)
+	PostgresPort     int    `json:"postgres_port,omitempty"`     // Server port (default: 5432)
+	PostgresUser     string `json:"postgres_user,omitempty"`     // Postgres user (default: postgres)
+	PostgresDatabase string `json:"postgres_database,omitempty"` // Database name (default: beads)
+	PostgresSchema   string `json:"postgres_schema,omitempty"`   // Schema name (default: public)
+	// Note: Password should be set via BEADS_POSTGRES_PASSWORD env var for security
+
 	// Stale closed issues check configuration
 	// 0 = disabled (default), positive = threshold in days
 	StaleClosedIssuesDays int `json:"stale_closed_issues_days,omitempty"`
@@ -139,6 +147,11 @@ func (c *Config) DatabasePath(beadsDir string) string {
 		return DefaultMariaDBDatabase
 	}
 
+	if backend == BackendPostgres {
+		// Like MariaDB, Postgres stores data on the server: return the database name.
+		return c.GetPostgresDatabase()
+	}
+
 	// SQLite (default)
 	db := strings.TrimSpace(c.Database)
 	if db == "" {
@@ -179,9 +192,11 @@ func (c *Config) GetStaleClosedIssuesDays() int {
 
 // Backend constants
 const (
-	BackendSQLite  = "sqlite"
-	BackendDolt    = "dolt"
-	BackendMariaDB = "mariadb"
+	BackendSQLite   = "sqlite"
+	BackendDolt     = "dolt"
+	BackendMariaDB  = "mariadb"
+	BackendPostgres = "postgres"
+	BackendMemory   = "memory" // In-process maps, for tests; nothing is persisted
 )
 
 // BackendCapabilities describes behavioral constraints for a storage backend.
@@ -207,6 +222,12 @@ func CapabilitiesForBackend(backend string) BackendCapabilities {
 	case BackendMariaDB:
 		// MariaDB uses server mode which supports multi-writer access
 		return BackendCapabilities{SingleProcessOnly: false}
+	case BackendPostgres:
+		// Postgres is a server database which supports multi-writer access
+		return BackendCapabilities{SingleProcessOnly: false}
+	case BackendMemory:
+		// Memory stores live inside a single process
+		return BackendCapabilities{SingleProcessOnly: true}
 	default:
 		return BackendCapabilities{SingleProcessOnly: true}
 	}
@@ -357,3 +378,74 @@ func (c *Config) GetMariaDBDatabase() string {
 	}
 	return DefaultMariaDBDatabase
 }
+
+// Default Postgres server settings
+const (
+	DefaultPostgresHost     = "127.0.0.1"
+	DefaultPostgresPort     = 5432 // Standard Postgres port
+	DefaultPostgresUser     = "postgres"
+	DefaultPostgresDatabase = "beads"
+	DefaultPostgresSchema   = "public"
+)
+
+// GetPostgresHost returns the Postgres server host.
+// Checks BEADS_POSTGRES_HOST env var first, then config, then default.
+func (c *Config) GetPostgresHost() string {
+	if h := os.Getenv("BEADS_POSTGRES_HOST"); h != "" {
+		return h
+	}
+	if c.PostgresHost != "" {
+		return c.PostgresHost
+	}
+	return DefaultPostgresHost
+}
+
+// GetPostgresPort returns the Postgres server port.
+// Checks BEADS_POSTGRES_PORT env var first, then config, then default.
+func (c *Config) GetPostgresPort() int {
+	if p := os.Getenv("BEADS_POSTGRES_PORT"); p != "" {
+		if port, err := strconv.Atoi(p); err == nil {
+			return port
+		}
+	}
+	if c.PostgresPort > 0 {
+		return c.PostgresPort
+	}
+	return DefaultPostgresPort
+}
+
+// GetPostgresUser returns the Postgres server user.
+// Checks BEADS_POSTGRES_USER env var first, then config, then default.
+func (c *Config) GetPostgresUser() string {
+	if u := os.Getenv("BEADS_POSTGRES_USER"); u != "" {
+		return u
+	}
+	if c.PostgresUser != "" {
+		return c.PostgresUser
+	}
+	return DefaultPostgresUser
+}
+
+// GetPostgresDatabase returns the Postgres database name.
+// Checks BEADS_POSTGRES_DATABASE env var first, then config, then default.
+func (c *Config) GetPostgresDatabase() string {
+	if d := os.Getenv("BEADS_POSTGRES_DATABASE"); d != "" {
+		return d
+	}
+	if c.PostgresDatabase != "" {
+		return c.PostgresDatabase
+	}
+	return DefaultPostgresDatabase
+}
+
+// GetPostgresSchema returns the Postgres schema holding the beads tables.
+// Checks BEADS_POSTGRES_SCHEMA env var first, then config, then default.
+func (c *Config) GetPostgresSchema() string {
+	if s := os.Getenv("BEADS_POSTGRES_SCHEMA"); s != "" {
+		return s
+	}
+	if c.PostgresSchema != "" {
+		return c.PostgresSchema
+	}
+	return DefaultPostgresSchema
+}
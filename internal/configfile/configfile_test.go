@@ -104,6 +104,51 @@ func TestDatabasePath_MariaDB_Default(t *testing.T) {
 	}
 }
 
+func TestDatabasePath_Postgres(t *testing.T) {
+	t.Setenv("BEADS_POSTGRES_DATABASE", "")
+	beadsDir := "/home/user/project/.beads"
+
+	cfg := &Config{Backend: BackendPostgres, PostgresDatabase: "mybeads"}
+	if got := cfg.DatabasePath(beadsDir); got != "mybeads" {
+		t.Errorf("DatabasePath() = %q, want %q", got, "mybeads")
+	}
+
+	cfg = &Config{Backend: BackendPostgres}
+	if got := cfg.DatabasePath(beadsDir); got != DefaultPostgresDatabase {
+		t.Errorf("DatabasePath() = %q, want %q", got, DefaultPostgresDatabase)
+	}
+}
+
+func TestPostgresGettersEnvOverride(t *testing.T) {
+	cfg := &Config{PostgresHost: "db.example.com", PostgresPort: 6543, PostgresSchema: "team_a"}
+
+	t.Setenv("BEADS_POSTGRES_HOST", "")
+	t.Setenv("BEADS_POSTGRES_PORT", "")
+	t.Setenv("BEADS_POSTGRES_SCHEMA", "")
+	if got := cfg.GetPostgresHost(); got != "db.example.com" {
+		t.Errorf("GetPostgresHost() = %q, want config value", got)
+	}
+	if got := cfg.GetPostgresPort(); got != 6543 {
+		t.Errorf("GetPostgresPort() = %d, want 6543", got)
+	}
+	if got := cfg.GetPostgresUser(); got != DefaultPostgresUser {
+		t.Errorf("GetPostgresUser() = %q, want %q", got, DefaultPostgresUser)
+	}
+
+	t.Setenv("BEADS_POSTGRES_HOST", "env-host")
+	t.Setenv("BEADS_POSTGRES_PORT", "15432")
+	t.Setenv("BEADS_POSTGRES_SCHEMA", "team_b")
+	if got := cfg.GetPostgresHost(); got != "env-host" {
+		t.Errorf("GetPostgresHost() = %q, want env value", got)
+	}
+	if got := cfg.GetPostgresPort(); got != 15432 {
+		t.Errorf("GetPostgresPort() = %d, want 15432", got)
+	}
+	if got := cfg.GetPostgresSchema(); got != "team_b" {
+		t.Errorf("GetPostgresSchema() = %q, want env value", got)
+	}
+}
+
 func TestDatabasePath_Dolt(t *testing.T) {
 	beadsDir := "/home/user/project/.beads"
 
@@ -432,6 +477,11 @@ func TestGetCapabilities(t *testing.T) {
 			cfg:            &Config{Backend: BackendDolt},
 			wantSingleProc: false,
 		},
+		{
+			name:           "postgres is multi-process",
+			cfg:            &Config{Backend: BackendPostgres},
+			wantSingleProc: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,174 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewClient creates a new Jira client for the site at baseURL.
+func NewClient(baseURL, username, token string) *Client {
+	return &Client{
+		BaseURL:  strings.TrimSuffix(baseURL, "/"),
+		Username: username,
+		Token:    token,
+		HTTPClient: &http.Client{
+			Timeout: DefaultTimeout,
+		},
+	}
+}
+
+// WithHTTPClient returns a new client configured to use the specified HTTP client.
+// This is useful for testing or customizing timeouts and transport settings.
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	clone := *c
+	clone.HTTPClient = httpClient
+	return &clone
+}
+
+// WithEndpoint returns a new client configured to use a custom site URL.
+// This is useful for testing with mock servers.
+func (c *Client) WithEndpoint(endpoint string) *Client {
+	clone := *c
+	clone.BaseURL = strings.TrimSuffix(endpoint, "/")
+	return &clone
+}
+
+// authHeader returns the Authorization header value: Basic auth with
+// username:token when a username is set (Jira Cloud, or Server with a
+// password), and a bearer personal access token otherwise.
+func (c *Client) authHeader() string {
+	if c.Username != "" {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.Username+":"+c.Token))
+	}
+	return "Bearer " + c.Token
+}
+
+// doRequest performs an HTTP request with authentication and retry logic,
+// decoding a JSON response into out when out is non-nil.
+func (c *Client) doRequest(ctx context.Context, method, path string, body, out interface{}) error {
+	var jsonBody []byte
+	if body != nil {
+		var err error
+		if jsonBody, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= MaxRetries; attempt++ {
+		var reqBody io.Reader
+		if jsonBody != nil {
+			reqBody = bytes.NewReader(jsonBody)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+DefaultAPIPath+path, reqBody)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", c.authHeader())
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", "bd-jira-export/1.0")
+		if jsonBody != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("request failed (attempt %d/%d): %w", attempt+1, MaxRetries+1, err)
+			continue
+		}
+
+		// Limit response body to 50MB to prevent OOM from malformed responses.
+		const maxResponseSize = 50 * 1024 * 1024
+		respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		_ = resp.Body.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("failed to read response (attempt %d/%d): %w", attempt+1, MaxRetries+1, err)
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			delay := RetryDelay * time.Duration(1<<attempt)
+			if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+				delay = time.Duration(secs) * time.Second
+			}
+			lastErr = fmt.Errorf("rate limited (attempt %d/%d)", attempt+1, MaxRetries+1)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+				continue
+			}
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("API error: %s (status %d)", string(respBody), resp.StatusCode)
+		}
+
+		if out != nil && len(respBody) > 0 {
+			if err := json.Unmarshal(respBody, out); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("max retries (%d) exceeded: %w", MaxRetries+1, lastErr)
+}
+
+// CreateIssue creates a Jira issue.
+func (c *Client) CreateIssue(ctx context.Context, payload *IssuePayload) (*CreatedIssue, error) {
+	var created CreatedIssue
+	if err := c.doRequest(ctx, http.MethodPost, "/issue", payload, &created); err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+	if created.Key == "" {
+		return nil, fmt.Errorf("failed to create issue: response has no issue key")
+	}
+	return &created, nil
+}
+
+// UpdateIssue replaces the fields of the Jira issue key.
+func (c *Client) UpdateIssue(ctx context.Context, key string, payload *IssuePayload) error {
+	if err := c.doRequest(ctx, http.MethodPut, "/issue/"+url.PathEscape(key), payload, nil); err != nil {
+		return fmt.Errorf("failed to update issue %s: %w", key, err)
+	}
+	return nil
+}
+
+// GetTransitions returns the workflow transitions available on issue key.
+func (c *Client) GetTransitions(ctx context.Context, key string) ([]Transition, error) {
+	var result struct {
+		Transitions []Transition `json:"transitions"`
+	}
+	if err := c.doRequest(ctx, http.MethodGet, "/issue/"+url.PathEscape(key)+"/transitions", nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to get transitions of issue %s: %w", key, err)
+	}
+	return result.Transitions, nil
+}
+
+// TransitionIssue moves issue key through the transition with transitionID.
+func (c *Client) TransitionIssue(ctx context.Context, key, transitionID string) error {
+	payload := &TransitionPayload{Transition: Ref{ID: transitionID}}
+	if err := c.doRequest(ctx, http.MethodPost, "/issue/"+url.PathEscape(key)+"/transitions", payload, nil); err != nil {
+		return fmt.Errorf("failed to transition issue %s: %w", key, err)
+	}
+	return nil
+}
+
+// CreateIssueLink links two Jira issues.
+func (c *Client) CreateIssueLink(ctx context.Context, link *LinkPayload) error {
+	if err := c.doRequest(ctx, http.MethodPost, "/issueLink", link, nil); err != nil {
+		return fmt.Errorf("failed to link %s to %s: %w", link.OutwardIssue.Key, link.InwardIssue.Key, err)
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package jira
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// MetadataKeyPrefix prefixes the metadata keys that map exported beads
+// issues to Jira issues ("jira.export.<issue-id>").
+const MetadataKeyPrefix = "jira.export."
+
+// Store is the part of the beads store the exporter reads and writes through.
+type Store interface {
+	GetDependencyRecordsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Dependency, error)
+	GetMetadata(ctx context.Context, key string) (string, error)
+	SetMetadata(ctx context.Context, key, value string) error
+}
+
+// Exporter pushes beads issues to a Jira project.
+type Exporter struct {
+	Client  *Client
+	Store   Store
+	Project string         // Jira project key
+	Mapping *MappingConfig // Field mapping (default: DefaultMappingConfig)
+	DryRun  bool           // Print requests to Out instead of sending them
+	Out     io.Writer      // Dry-run output (default: os.Stdout)
+}
+
+// ExportStats summarizes an export.
+type ExportStats struct {
+	Created      int      // Jira issues created
+	Updated      int      // Jira issues whose fields were updated
+	Unchanged    int      // Previously exported issues with nothing to update
+	Transitioned int      // Jira issues moved to a new status
+	Links        int      // Issue links created
+	Warnings     []string // Statuses and links that could not be applied
+}
+
+// exportRecord is the metadata stored for each exported issue.
+type exportRecord struct {
+	Key    string   `json:"key"`             // Jira issue key
+	Hash   string   `json:"hash"`            // Hash of the last fields sent
+	Status string   `json:"status"`          // Jira status last applied
+	Links  []string `json:"links,omitempty"` // Exported dependencies ("<type>:<depends-on-id>")
+}
+
+// Export creates or updates a Jira issue for each issue, then links them.
+// The Jira key of each exported issue is recorded in the store's metadata,
+// so exporting again updates the same Jira issue, and skips it when its
+// fields and status are unchanged. Dependencies become issue links when both
+// ends have been exported; each link is created once. A status with no
+// matching workflow transition is reported as a warning.
+//
+// With DryRun set, the requests are written to Out and nothing is sent or
+// recorded; issues not yet exported are shown with their beads ID as key.
+func (ex *Exporter) Export(ctx context.Context, issues []*types.Issue) (*ExportStats, error) {
+	if ex.Store == nil || ex.Project == "" || (ex.Client == nil && !ex.DryRun) {
+		return nil, errors.New("Jira exporter requires Client, Store and Project")
+	}
+	config := ex.Mapping
+	if config == nil {
+		config = DefaultMappingConfig()
+	}
+
+	stats := &ExportStats{}
+	records := make(map[string]*exportRecord, len(issues))
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		record, err := ex.exportIssue(ctx, issue, config, stats)
+		if err != nil {
+			return stats, err
+		}
+		records[issue.ID] = record
+		ids = append(ids, issue.ID)
+	}
+
+	deps, err := ex.Store.GetDependencyRecordsForIssues(ctx, ids)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get dependencies: %w", err)
+	}
+	for _, id := range ids {
+		if err := ex.exportLinks(ctx, id, records, deps[id], config, stats); err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+// exportIssue creates or updates the Jira issue for issue and applies its status.
+func (ex *Exporter) exportIssue(ctx context.Context, issue *types.Issue, config *MappingConfig, stats *ExportStats) (*exportRecord, error) {
+	record, err := ex.loadRecord(ctx, issue.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := BeadsIssueToJira(issue, config)
+	hash, err := payloadHash(payload)
+	if err != nil {
+		return nil, err
+	}
+	status := StatusName(issue.Status, config)
+
+	changed := false
+	switch {
+	case record == nil:
+		payload.Fields.Project = &Ref{Key: ex.Project}
+		record = &exportRecord{Key: issue.ID}
+		if err := ex.dryRun(http.MethodPost, "/issue", payload); err != nil {
+			return nil, err
+		}
+		if !ex.DryRun {
+			created, err := ex.Client.CreateIssue(ctx, payload)
+			if err != nil {
+				return nil, fmt.Errorf("failed to export %s: %w", issue.ID, err)
+			}
+			record.Key = created.Key
+		}
+		// A new issue starts in the workflow's initial status
+		record.Status = config.StatusMap[types.StatusOpen]
+		stats.Created++
+		changed = true
+	case record.Hash != hash:
+		if err := ex.dryRun(http.MethodPut, "/issue/"+record.Key, payload); err != nil {
+			return nil, err
+		}
+		if !ex.DryRun {
+			if err := ex.Client.UpdateIssue(ctx, record.Key, payload); err != nil {
+				return nil, fmt.Errorf("failed to export %s: %w", issue.ID, err)
+			}
+		}
+		stats.Updated++
+		changed = true
+	}
+	record.Hash = hash
+	// Record the Jira issue before transitioning it, so that if the
+	// transition fails the next export updates it instead of creating another
+	if changed {
+		if err := ex.saveRecord(ctx, issue.ID, record); err != nil {
+			return nil, err
+		}
+	}
+
+	if status != "" && !strings.EqualFold(status, record.Status) {
+		applied, err := ex.transition(ctx, record.Key, status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", issue.ID, err)
+		}
+		if !applied {
+			stats.Warnings = append(stats.Warnings, fmt.Sprintf("%s (%s): no transition to status %q", issue.ID, record.Key, status))
+		} else {
+			record.Status = status
+			stats.Transitioned++
+			if err := ex.saveRecord(ctx, issue.ID, record); err != nil {
+				return nil, err
+			}
+			changed = true
+		}
+	}
+
+	if !changed {
+		stats.Unchanged++
+	}
+	return record, nil
+}
+
+// transition moves issue key to the named status, reporting whether the
+// workflow offers a transition to it.
+func (ex *Exporter) transition(ctx context.Context, key, status string) (bool, error) {
+	if ex.DryRun {
+		return true, ex.dryRun(http.MethodPost, "/issue/"+key+"/transitions", map[string]string{"status": status})
+	}
+	transitions, err := ex.Client.GetTransitions(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	for _, t := range transitions {
+		if strings.EqualFold(t.To.Name, status) {
+			return true, ex.Client.TransitionIssue(ctx, key, t.ID)
+		}
+	}
+	return false, nil
+}
+
+// exportLinks creates the Jira links for id's dependencies that have not
+// been exported yet.
+func (ex *Exporter) exportLinks(ctx context.Context, id string, records map[string]*exportRecord, deps []*types.Dependency, config *MappingConfig, stats *ExportStats) error {
+	record := records[id]
+	exported := make(map[string]bool, len(record.Links))
+	for _, link := range record.Links {
+		exported[link] = true
+	}
+
+	added := false
+	for _, dep := range deps {
+		linkType := LinkTypeName(dep.Type, config)
+		name := string(dep.Type) + ":" + dep.DependsOnID
+		if linkType == "" || exported[name] {
+			continue
+		}
+		target, ok := records[dep.DependsOnID]
+		if !ok {
+			var err error
+			if target, err = ex.loadRecord(ctx, dep.DependsOnID); err != nil {
+				return err
+			}
+		}
+		if target == nil {
+			stats.Warnings = append(stats.Warnings, fmt.Sprintf("%s: skipped %s link to %s, which has not been exported", id, linkType, dep.DependsOnID))
+			continue
+		}
+
+		// The depended-on issue is the outward side: it blocks the dependent
+		link := &LinkPayload{
+			Type:         Ref{Name: linkType},
+			InwardIssue:  Ref{Key: record.Key},
+			OutwardIssue: Ref{Key: target.Key},
+		}
+		if err := ex.dryRun(http.MethodPost, "/issueLink", link); err != nil {
+			return err
+		}
+		if !ex.DryRun {
+			if err := ex.Client.CreateIssueLink(ctx, link); err != nil {
+				return fmt.Errorf("failed to export %s: %w", id, err)
+			}
+		}
+		record.Links = append(record.Links, name)
+		exported[name] = true
+		stats.Links++
+		added = true
+	}
+
+	if !added {
+		return nil
+	}
+	return ex.saveRecord(ctx, id, record)
+}
+
+// loadRecord returns the export record of issue id, or nil if it has not
+// been exported.
+func (ex *Exporter) loadRecord(ctx context.Context, id string) (*exportRecord, error) {
+	value, err := ex.Store.GetMetadata(ctx, MetadataKeyPrefix+id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Jira mapping for %s: %w", id, err)
+	}
+	if value == "" {
+		return nil, nil
+	}
+	var record exportRecord
+	if err := json.Unmarshal([]byte(value), &record); err != nil {
+		return nil, fmt.Errorf("failed to parse Jira mapping for %s: %w", id, err)
+	}
+	return &record, nil
+}
+
+// saveRecord records the export of issue id. It does nothing in dry-run mode.
+func (ex *Exporter) saveRecord(ctx context.Context, id string, record *exportRecord) error {
+	if ex.DryRun {
+		return nil
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode Jira mapping for %s: %w", id, err)
+	}
+	if err := ex.Store.SetMetadata(ctx, MetadataKeyPrefix+id, string(data)); err != nil {
+		return fmt.Errorf("failed to record Jira mapping for %s: %w", id, err)
+	}
+	return nil
+}
+
+// dryRun writes the request to Out in dry-run mode.
+func (ex *Exporter) dryRun(method, path string, payload interface{}) error {
+	if !ex.DryRun {
+		return nil
+	}
+	out := ex.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	_, err = fmt.Fprintf(out, "%s %s%s\n%s\n\n", method, DefaultAPIPath, path, data)
+	return err
+}
+
+// payloadHash returns a hash of the fields sent for an issue, used to skip
+// updates when nothing changed.
+func payloadHash(payload *IssuePayload) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
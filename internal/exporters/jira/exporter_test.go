@@ -0,0 +1,285 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// fakeStore holds metadata and dependencies in memory.
+type fakeStore struct {
+	metadata map[string]string
+	deps     map[string][]*types.Dependency
+}
+
+func (f *fakeStore) GetDependencyRecordsForIssues(_ context.Context, ids []string) (map[string][]*types.Dependency, error) {
+	result := make(map[string][]*types.Dependency)
+	for _, id := range ids {
+		if deps, ok := f.deps[id]; ok {
+			result[id] = deps
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeStore) GetMetadata(_ context.Context, key string) (string, error) {
+	return f.metadata[key], nil
+}
+
+func (f *fakeStore) SetMetadata(_ context.Context, key, value string) error {
+	f.metadata[key] = value
+	return nil
+}
+
+// request is a call received by the mock Jira server.
+type request struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+// mockJira is a minimal Jira REST API that assigns keys PROJ-1, PROJ-2, ...
+type mockJira struct {
+	mu       sync.Mutex
+	requests []request
+	next     int
+
+	failTransitions bool // Reject transition requests with a server error
+}
+
+func (m *mockJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, request{Method: r.Method, Path: strings.TrimPrefix(r.URL.Path, DefaultAPIPath), Body: body})
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == DefaultAPIPath+"/issue":
+		m.next++
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"id": "%d", "key": "PROJ-%d", "self": "x"}`, 10000+m.next, m.next)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/transitions") && m.failTransitions:
+		w.WriteHeader(http.StatusInternalServerError)
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/transitions"):
+		_, _ = w.Write([]byte(`{"transitions": [
+			{"id": "21", "name": "Start", "to": {"name": "In Progress"}},
+			{"id": "31", "name": "Resolve", "to": {"name": "Done"}}]}`))
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// calls returns the requests received since the last call, as "METHOD path".
+func (m *mockJira) calls() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []string
+	for _, r := range m.requests {
+		out = append(out, r.Method+" "+r.Path)
+	}
+	m.requests = nil
+	return out
+}
+
+func testIssues() []*types.Issue {
+	return []*types.Issue{
+		{ID: "bd-1", Title: "Fix login", Description: "Fails on Safari.\n\nSee logs.", Status: types.StatusClosed, Priority: 0, IssueType: types.TypeBug, Labels: []string{"web ui"}},
+		{ID: "bd-2", Title: "Add SSO", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeFeature},
+	}
+}
+
+func newTestExporter(t *testing.T) (*Exporter, *mockJira, *fakeStore) {
+	t.Helper()
+	mock := &mockJira{}
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+	store := &fakeStore{
+		metadata: map[string]string{},
+		deps: map[string][]*types.Dependency{
+			"bd-2": {{IssueID: "bd-2", DependsOnID: "bd-1", Type: types.DepBlocks}},
+		},
+	}
+	ex := &Exporter{
+		Client:  NewClient(server.URL, "me@example.com", "secret"),
+		Store:   store,
+		Project: "PROJ",
+	}
+	return ex, mock, store
+}
+
+// TestExportCreatesIssues verifies the payloads of a first export.
+func TestExportCreatesIssues(t *testing.T) {
+	ex, mock, store := newTestExporter(t)
+
+	stats, err := ex.Export(context.Background(), testIssues())
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if stats.Created != 2 || stats.Transitioned != 1 || stats.Links != 1 || len(stats.Warnings) != 0 {
+		t.Errorf("stats = %+v, want 2 created, 1 transitioned, 1 link", stats)
+	}
+
+	var creates []IssuePayload
+	var link LinkPayload
+	var transition TransitionPayload
+	for _, r := range mock.requests {
+		switch r.Method + " " + r.Path {
+		case "POST /issue":
+			var p IssuePayload
+			if err := json.Unmarshal(r.Body, &p); err != nil {
+				t.Fatalf("bad create payload: %v", err)
+			}
+			creates = append(creates, p)
+		case "POST /issueLink":
+			_ = json.Unmarshal(r.Body, &link)
+		case "POST /issue/PROJ-1/transitions":
+			_ = json.Unmarshal(r.Body, &transition)
+		}
+	}
+	if len(creates) != 2 {
+		t.Fatalf("received %d create requests, want 2", len(creates))
+	}
+	bug := creates[0].Fields
+	if bug.Project == nil || bug.Project.Key != "PROJ" || bug.Summary != "Fix login" {
+		t.Errorf("project, summary = %+v, %q; want PROJ, Fix login", bug.Project, bug.Summary)
+	}
+	if bug.IssueType == nil || bug.IssueType.Name != "Bug" || bug.Priority == nil || bug.Priority.Name != "Highest" {
+		t.Errorf("issuetype, priority = %+v, %+v; want Bug, Highest", bug.IssueType, bug.Priority)
+	}
+	if bug.Description == nil || len(bug.Description.Content) != 2 || bug.Description.Content[1].Content[0].Text != "See logs." {
+		t.Errorf("description = %+v, want two paragraphs", bug.Description)
+	}
+	if len(bug.Labels) != 1 || bug.Labels[0] != "web-ui" {
+		t.Errorf("labels = %v, want [web-ui]", bug.Labels)
+	}
+	if creates[1].Fields.IssueType.Name != "Story" || creates[1].Fields.Priority.Name != "Medium" {
+		t.Errorf("feature issuetype, priority = %+v, %+v; want Story, Medium", creates[1].Fields.IssueType, creates[1].Fields.Priority)
+	}
+
+	// The closed bug is resolved through the "Done" transition
+	if transition.Transition.ID != "31" {
+		t.Errorf("transition = %+v, want id 31", transition.Transition)
+	}
+	// bd-1 blocks bd-2
+	if link.Type.Name != "Blocks" || link.OutwardIssue.Key != "PROJ-1" || link.InwardIssue.Key != "PROJ-2" {
+		t.Errorf("link = %+v, want PROJ-1 blocks PROJ-2", link)
+	}
+
+	if !strings.Contains(store.metadata[MetadataKeyPrefix+"bd-1"], `"key":"PROJ-1"`) {
+		t.Errorf("mapping for bd-1 = %q, want key PROJ-1", store.metadata[MetadataKeyPrefix+"bd-1"])
+	}
+}
+
+// TestExportIsIdempotent verifies re-exports update the recorded Jira issues
+// instead of creating new ones.
+func TestExportIsIdempotent(t *testing.T) {
+	ex, mock, _ := newTestExporter(t)
+	issues := testIssues()
+	if _, err := ex.Export(context.Background(), issues); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	mock.calls()
+
+	// Nothing changed: no requests at all
+	stats, err := ex.Export(context.Background(), issues)
+	if err != nil {
+		t.Fatalf("second Export failed: %v", err)
+	}
+	if calls := mock.calls(); len(calls) != 0 || stats.Unchanged != 2 {
+		t.Errorf("unchanged re-export made requests %v (unchanged %d), want none", calls, stats.Unchanged)
+	}
+
+	// An edit and a status change update and transition the same issue
+	issues[1].Title = "Add SAML SSO"
+	issues[1].Status = types.StatusInProgress
+	stats, err = ex.Export(context.Background(), issues)
+	if err != nil {
+		t.Fatalf("third Export failed: %v", err)
+	}
+	want := []string{"PUT /issue/PROJ-2", "GET /issue/PROJ-2/transitions", "POST /issue/PROJ-2/transitions"}
+	if calls := mock.calls(); strings.Join(calls, ", ") != strings.Join(want, ", ") {
+		t.Errorf("re-export made requests %v, want %v", calls, want)
+	}
+	if stats.Created != 0 || stats.Updated != 1 || stats.Links != 0 {
+		t.Errorf("stats = %+v, want only 1 update", stats)
+	}
+}
+
+// TestExportRecordsCreateBeforeTransition verifies an issue created before
+// its transition failed is updated, not created again, by the next export.
+func TestExportRecordsCreateBeforeTransition(t *testing.T) {
+	ex, mock, store := newTestExporter(t)
+	issues := testIssues()[:1]
+	mock.failTransitions = true
+	if _, err := ex.Export(context.Background(), issues); err == nil {
+		t.Fatal("expected the failed transition to fail the export")
+	}
+	if !strings.Contains(store.metadata[MetadataKeyPrefix+"bd-1"], `"key":"PROJ-1"`) {
+		t.Fatalf("mapping for bd-1 = %q, want key PROJ-1 recorded despite the failure", store.metadata[MetadataKeyPrefix+"bd-1"])
+	}
+	mock.calls()
+
+	mock.failTransitions = false
+	stats, err := ex.Export(context.Background(), issues)
+	if err != nil {
+		t.Fatalf("second Export failed: %v", err)
+	}
+	want := []string{"GET /issue/PROJ-1/transitions", "POST /issue/PROJ-1/transitions"}
+	if calls := mock.calls(); strings.Join(calls, ", ") != strings.Join(want, ", ") {
+		t.Errorf("retried export made requests %v, want %v", calls, want)
+	}
+	if stats.Created != 0 || stats.Transitioned != 1 {
+		t.Errorf("stats = %+v, want only 1 transition", stats)
+	}
+}
+
+// TestExportDryRun verifies dry-run prints the payloads and sends nothing.
+func TestExportDryRun(t *testing.T) {
+	ex, mock, store := newTestExporter(t)
+	var out bytes.Buffer
+	ex.DryRun = true
+	ex.Out = &out
+
+	stats, err := ex.Export(context.Background(), testIssues())
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if calls := mock.calls(); len(calls) != 0 {
+		t.Errorf("dry-run made requests %v", calls)
+	}
+	if len(store.metadata) != 0 {
+		t.Errorf("dry-run recorded mappings %v", store.metadata)
+	}
+	if stats.Created != 2 || stats.Links != 1 {
+		t.Errorf("stats = %+v, want 2 created and 1 link", stats)
+	}
+	for _, want := range []string{"POST /rest/api/3/issue\n", `"summary": "Fix login"`, "POST /rest/api/3/issueLink\n", `"key": "bd-1"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("dry-run output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+// TestExportWarnsWithoutTransition verifies an unreachable status is reported.
+func TestExportWarnsWithoutTransition(t *testing.T) {
+	ex, _, _ := newTestExporter(t)
+	ex.Mapping = DefaultMappingConfig()
+	ex.Mapping.StatusMap[types.StatusClosed] = "Closed"
+
+	stats, err := ex.Export(context.Background(), testIssues()[:1])
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if stats.Transitioned != 0 || len(stats.Warnings) != 1 {
+		t.Errorf("Transitioned, Warnings = %d, %v; want 0 and one warning", stats.Transitioned, stats.Warnings)
+	}
+}
@@ -0,0 +1,112 @@
+package jira
+
+import (
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// MappingConfig configures how beads fields map to Jira fields.
+type MappingConfig struct {
+	PriorityMap map[int]string                  // beads priority (0-4) → Jira priority name
+	TypeMap     map[types.IssueType]string      // beads issue type → Jira issue type name
+	StatusMap   map[types.Status]string         // beads status → Jira status name
+	LinkTypeMap map[types.DependencyType]string // beads dependency type → Jira link type name
+}
+
+// DefaultMappingConfig returns the default mapping configuration, matching
+// the names of a stock Jira Software project.
+func DefaultMappingConfig() *MappingConfig {
+	return &MappingConfig{
+		PriorityMap: map[int]string{
+			0: "Highest",
+			1: "High",
+			2: "Medium",
+			3: "Low",
+			4: "Lowest",
+		},
+		TypeMap: map[types.IssueType]string{
+			types.TypeBug:     "Bug",
+			types.TypeFeature: "Story",
+			types.TypeTask:    "Task",
+			types.TypeEpic:    "Epic",
+			types.TypeChore:   "Task",
+		},
+		StatusMap: map[types.Status]string{
+			types.StatusOpen:       "To Do",
+			types.StatusInProgress: "In Progress",
+			types.StatusBlocked:    "To Do",
+			types.StatusDeferred:   "To Do",
+			types.StatusClosed:     "Done",
+		},
+		LinkTypeMap: map[types.DependencyType]string{
+			types.DepBlocks:     "Blocks",
+			types.DepRelated:    "Relates",
+			types.DepRelatesTo:  "Relates",
+			types.DepDuplicates: "Duplicate",
+		},
+	}
+}
+
+// BeadsIssueToJira converts a beads issue to the fields of a Jira issue.
+// The project is left unset; the exporter adds it on create.
+func BeadsIssueToJira(issue *types.Issue, config *MappingConfig) *IssuePayload {
+	fields := IssueFields{
+		Summary:     issue.Title,
+		Description: descriptionDoc(issue),
+		IssueType:   &Ref{Name: "Task"},
+		Labels:      jiraLabels(issue.Labels),
+	}
+	if name, ok := config.TypeMap[issue.IssueType]; ok {
+		fields.IssueType = &Ref{Name: name}
+	}
+	if name, ok := config.PriorityMap[issue.Priority]; ok {
+		fields.Priority = &Ref{Name: name}
+	}
+	return &IssuePayload{Fields: fields}
+}
+
+// StatusName returns the Jira status for a beads status, or "" when the
+// status has no mapping and the Jira workflow should be left alone.
+func StatusName(status types.Status, config *MappingConfig) string {
+	return config.StatusMap[status]
+}
+
+// LinkTypeName returns the Jira link type for a beads dependency type, or ""
+// when the dependency type is not exported.
+func LinkTypeName(depType types.DependencyType, config *MappingConfig) string {
+	return config.LinkTypeMap[depType]
+}
+
+// descriptionDoc renders the issue's description and acceptance criteria as
+// an ADF document with one paragraph per blank-line separated block.
+func descriptionDoc(issue *types.Issue) *Doc {
+	text := issue.Description
+	if issue.AcceptanceCriteria != "" {
+		text = strings.TrimSpace(text + "\n\nAcceptance criteria:\n" + issue.AcceptanceCriteria)
+	}
+	doc := &Doc{Type: "doc", Version: 1}
+	for _, block := range strings.Split(text, "\n\n") {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+		doc.Content = append(doc.Content, Doc{
+			Type:    "paragraph",
+			Content: []Doc{{Type: "text", Text: block}},
+		})
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	return doc
+}
+
+// jiraLabels converts beads labels to Jira labels, which cannot contain spaces.
+func jiraLabels(labels []string) []string {
+	out := make([]string, 0, len(labels))
+	for _, label := range labels {
+		out = append(out, strings.ReplaceAll(strings.TrimSpace(label), " ", "-"))
+	}
+	return out
+}
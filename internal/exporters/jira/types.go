@@ -0,0 +1,94 @@
+// Package jira exports Beads issues to a Jira project through the Jira REST API.
+//
+// Each exported issue's Jira key is recorded in the Beads metadata table, so
+// exporting again updates the Jira issue instead of creating a duplicate, and
+// unchanged issues are skipped. Status is applied with a workflow transition,
+// priority and type are mapped by name, and dependencies become issue links.
+package jira
+
+import (
+	"net/http"
+	"time"
+)
+
+// API configuration constants.
+const (
+	// DefaultAPIPath is the Jira REST API v3 path suffix.
+	DefaultAPIPath = "/rest/api/3"
+
+	// DefaultTimeout is the default HTTP request timeout.
+	DefaultTimeout = 30 * time.Second
+
+	// MaxRetries is the maximum number of retries for rate-limited requests.
+	MaxRetries = 3
+
+	// RetryDelay is the base delay between retries when Jira sends no
+	// Retry-After (exponential backoff).
+	RetryDelay = time.Second
+)
+
+// Client provides methods to write issues to the Jira REST API.
+type Client struct {
+	BaseURL    string       // Jira site URL (e.g., "https://company.atlassian.net")
+	Username   string       // Account email (Jira Cloud) or username; empty uses Token as a bearer PAT
+	Token      string       // API token or personal access token
+	HTTPClient *http.Client // Optional custom HTTP client
+}
+
+// Ref identifies a Jira project, issue type, priority, transition, or issue
+// in a payload.
+type Ref struct {
+	ID   string `json:"id,omitempty"`
+	Key  string `json:"key,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// Doc is an Atlassian Document Format node, the rich text format of API v3.
+type Doc struct {
+	Type    string `json:"type"`
+	Version int    `json:"version,omitempty"`
+	Text    string `json:"text,omitempty"`
+	Content []Doc  `json:"content,omitempty"`
+}
+
+// IssueFields are the fields sent when creating or updating a Jira issue.
+type IssueFields struct {
+	Project     *Ref     `json:"project,omitempty"` // Only sent on create
+	Summary     string   `json:"summary"`
+	Description *Doc     `json:"description,omitempty"`
+	IssueType   *Ref     `json:"issuetype,omitempty"`
+	Priority    *Ref     `json:"priority,omitempty"`
+	Labels      []string `json:"labels"`
+}
+
+// IssuePayload is the body of a create or update issue request.
+type IssuePayload struct {
+	Fields IssueFields `json:"fields"`
+}
+
+// CreatedIssue is the response to a create issue request.
+type CreatedIssue struct {
+	ID   string `json:"id"`
+	Key  string `json:"key"`
+	Self string `json:"self"`
+}
+
+// Transition is a workflow transition available on an issue.
+type Transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   Ref    `json:"to"` // Status the transition moves the issue to
+}
+
+// TransitionPayload is the body of a transition request.
+type TransitionPayload struct {
+	Transition Ref `json:"transition"`
+}
+
+// LinkPayload is the body of a create issue link request. For the Blocks
+// link type, the outward issue blocks the inward issue.
+type LinkPayload struct {
+	Type         Ref `json:"type"`
+	InwardIssue  Ref `json:"inwardIssue"`
+	OutwardIssue Ref `json:"outwardIssue"`
+}
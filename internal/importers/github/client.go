@@ -0,0 +1,226 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// NewClient creates a new GitHub client for the owner/repo repository.
+func NewClient(token, owner, repo string) *Client {
+	return &Client{
+		Token:   token,
+		BaseURL: DefaultBaseURL,
+		Owner:   owner,
+		Repo:    repo,
+		HTTPClient: &http.Client{
+			Timeout: DefaultTimeout,
+		},
+	}
+}
+
+// WithHTTPClient returns a new client configured to use the specified HTTP client.
+// This is useful for testing or customizing timeouts and transport settings.
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	clone := *c
+	clone.HTTPClient = httpClient
+	return &clone
+}
+
+// WithEndpoint returns a new client configured to use a custom API endpoint.
+// This is useful for testing with mock servers or GitHub Enterprise instances.
+func (c *Client) WithEndpoint(endpoint string) *Client {
+	clone := *c
+	clone.BaseURL = endpoint
+	return &clone
+}
+
+// buildURL constructs a full API URL from path and optional query parameters.
+func (c *Client) buildURL(path string, params map[string]string) string {
+	u := c.BaseURL + path
+
+	if len(params) > 0 {
+		values := url.Values{}
+		for k, v := range params {
+			values.Set(k, v)
+		}
+		u += "?" + values.Encode()
+	}
+
+	return u
+}
+
+// doRequest performs a GET request with authentication, waiting out rate limits.
+func (c *Client) doRequest(ctx context.Context, urlStr string) ([]byte, http.Header, error) {
+	var lastErr error
+	for attempt := 0; attempt <= MaxRetries; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", APIVersion)
+		if c.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("request failed (attempt %d/%d): %w", attempt+1, MaxRetries+1, err)
+			continue
+		}
+
+		// Limit response body to 50MB to prevent OOM from malformed responses.
+		const maxResponseSize = 50 * 1024 * 1024
+		respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		_ = resp.Body.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("failed to read response (attempt %d/%d): %w", attempt+1, MaxRetries+1, err)
+			continue
+		}
+
+		if delay, limited := rateLimitDelay(resp, attempt, time.Now()); limited {
+			lastErr = fmt.Errorf("rate limited (attempt %d/%d)", attempt+1, MaxRetries+1)
+			if delay > MaxRateLimitWait {
+				return nil, nil, fmt.Errorf("rate limited for %s, longer than %s: %w", delay.Round(time.Second), MaxRateLimitWait, lastErr)
+			}
+			select {
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			case <-time.After(delay):
+				continue
+			}
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, nil, fmt.Errorf("API error: %s (status %d)", string(respBody), resp.StatusCode)
+		}
+
+		return respBody, resp.Header, nil
+	}
+
+	return nil, nil, fmt.Errorf("max retries (%d) exceeded: %w", MaxRetries+1, lastErr)
+}
+
+// rateLimitDelay reports whether resp is a rate-limit rejection and how long
+// to wait before retrying. GitHub signals the primary limit with a 403 or 429
+// and X-RateLimit-Remaining: 0, waiting until X-RateLimit-Reset; secondary
+// limits send Retry-After instead.
+func rateLimitDelay(resp *http.Response, attempt int, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if secs, err := strconv.Atoi(s); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return max(time.Unix(reset, 0).Sub(now), 0), true
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return RetryDelay * time.Duration(1<<attempt), true
+	}
+	// A 403 without rate limit headers is a permission error
+	return 0, false
+}
+
+// nextLinkPattern extracts the rel="next" URL from a Link header.
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextPageURL returns the URL of the next page from the Link header, or "".
+func nextPageURL(headers http.Header) string {
+	if m := nextLinkPattern.FindStringSubmatch(headers.Get("Link")); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// FetchIssues retrieves the repository's issues, skipping pull requests.
+// state can be: "open", "closed", or "all".
+func (c *Client) FetchIssues(ctx context.Context, state string) ([]Issue, error) {
+	return c.fetchIssues(ctx, state, time.Time{})
+}
+
+// FetchIssuesSince retrieves issues updated at or after since, skipping pull
+// requests. This enables incremental imports of issues changed since the last run.
+func (c *Client) FetchIssuesSince(ctx context.Context, state string, since time.Time) ([]Issue, error) {
+	return c.fetchIssues(ctx, state, since)
+}
+
+func (c *Client) fetchIssues(ctx context.Context, state string, since time.Time) ([]Issue, error) {
+	if c.Owner == "" || c.Repo == "" {
+		return nil, errors.New("GitHub owner and repo are required")
+	}
+	if state == "" {
+		state = "all"
+	}
+	params := map[string]string{
+		"state":     state,
+		"per_page":  strconv.Itoa(MaxPageSize),
+		"direction": "asc",
+	}
+	if !since.IsZero() {
+		params["since"] = since.UTC().Format(time.RFC3339)
+	}
+	urlStr := c.buildURL("/repos/"+url.PathEscape(c.Owner)+"/"+url.PathEscape(c.Repo)+"/issues", params)
+
+	var allIssues []Issue
+	for page := 1; urlStr != ""; page++ {
+		// Guard against infinite pagination loops from malformed responses
+		if page > MaxPages {
+			return nil, fmt.Errorf("pagination limit exceeded: stopped after %d pages", MaxPages)
+		}
+
+		respBody, headers, err := c.doRequest(ctx, urlStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch issues: %w", err)
+		}
+
+		var issues []Issue
+		if err := json.Unmarshal(respBody, &issues); err != nil {
+			return nil, fmt.Errorf("failed to parse issues response: %w", err)
+		}
+		for _, issue := range issues {
+			if !issue.IsPullRequest() {
+				allIssues = append(allIssues, issue)
+			}
+		}
+
+		urlStr = nextPageURL(headers)
+	}
+
+	return allIssues, nil
+}
+
+// ReadExport reads issues saved from the REST API: a JSON array of issue
+// objects, or several arrays one after another as written by
+// `gh api --paginate`. Pull requests are skipped.
+func ReadExport(r io.Reader) ([]Issue, error) {
+	dec := json.NewDecoder(r)
+	var allIssues []Issue
+	for page := 1; ; page++ {
+		var issues []Issue
+		if err := dec.Decode(&issues); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse GitHub export (array %d): %w", page, err)
+		}
+		for _, issue := range issues {
+			if !issue.IsPullRequest() {
+				allIssues = append(allIssues, issue)
+			}
+		}
+	}
+	return allIssues, nil
+}
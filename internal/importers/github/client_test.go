@@ -0,0 +1,159 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fixture returns the contents of a recorded API response in testdata.
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+// TestFetchIssuesPaginates verifies the client follows Link headers, sends
+// the token, and drops pull requests.
+func TestFetchIssuesPaginates(t *testing.T) {
+	page1, page2 := fixture(t, "issues_page1.json"), fixture(t, "issues_page2.json")
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q, want Bearer test-token", got)
+		}
+		if r.URL.Path != "/repos/acme/widgets/issues" {
+			t.Errorf("path = %q, want /repos/acme/widgets/issues", r.URL.Path)
+		}
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = w.Write(page2)
+			return
+		}
+		if got := r.URL.Query().Get("state"); got != "all" {
+			t.Errorf("state = %q, want all", got)
+		}
+		w.Header().Set("Link", `<`+server.URL+`/repos/acme/widgets/issues?page=2>; rel="next", <`+server.URL+`/repos/acme/widgets/issues?page=2>; rel="last"`)
+		_, _ = w.Write(page1)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", "acme", "widgets").WithEndpoint(server.URL)
+	issues, err := client.FetchIssues(context.Background(), "")
+	if err != nil {
+		t.Fatalf("FetchIssues failed: %v", err)
+	}
+
+	var numbers []string
+	for _, issue := range issues {
+		numbers = append(numbers, strconv.Itoa(issue.Number))
+	}
+	if got := strings.Join(numbers, ","); got != "1,3,4" {
+		t.Errorf("fetched issues %s, want 1,3,4 (pull request 2 skipped)", got)
+	}
+}
+
+// TestFetchIssuesWaitsForRateLimit verifies a rate-limited request is retried
+// after the reset time instead of failing.
+func TestFetchIssuesWaitsForRateLimit(t *testing.T) {
+	page2 := fixture(t, "issues_page2.json")
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+			return
+		}
+		_, _ = w.Write(page2)
+	}))
+	defer server.Close()
+
+	client := NewClient("", "acme", "widgets").WithEndpoint(server.URL)
+	issues, err := client.FetchIssues(context.Background(), "open")
+	if err != nil {
+		t.Fatalf("FetchIssues failed: %v", err)
+	}
+	if len(issues) != 2 || calls.Load() != 2 {
+		t.Errorf("got %d issues after %d requests, want 2 after 2", len(issues), calls.Load())
+	}
+}
+
+// TestFetchIssuesForbidden verifies a 403 without rate limit headers fails at once.
+func TestFetchIssuesForbidden(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token", "acme", "widgets").WithEndpoint(server.URL)
+	if _, err := client.FetchIssues(context.Background(), "all"); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("FetchIssues = %v, want status 403 error", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("made %d requests, want 1", calls.Load())
+	}
+}
+
+// TestRateLimitDelay verifies the wait derived from GitHub's rate limit headers.
+func TestRateLimitDelay(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name    string
+		status  int
+		headers map[string]string
+		want    time.Duration
+		limited bool
+	}{
+		{"ok", http.StatusOK, nil, 0, false},
+		{"primary limit", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000042"}, 42 * time.Second, true},
+		{"reset passed", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1699999990"}, 0, true},
+		{"secondary limit", http.StatusForbidden, map[string]string{"Retry-After": "60"}, time.Minute, true},
+		{"too many requests", http.StatusTooManyRequests, nil, RetryDelay * 2, true},
+		{"permission denied", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "4999"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+			got, limited := rateLimitDelay(resp, 1, now)
+			if got != tt.want || limited != tt.limited {
+				t.Errorf("rateLimitDelay = %v, %v; want %v, %v", got, limited, tt.want, tt.limited)
+			}
+		})
+	}
+}
+
+// TestReadExport verifies concatenated pages from `gh api --paginate` are read.
+func TestReadExport(t *testing.T) {
+	data := string(fixture(t, "issues_page1.json")) + string(fixture(t, "issues_page2.json"))
+	issues, err := ReadExport(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadExport failed: %v", err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("ReadExport returned %d issues, want 3", len(issues))
+	}
+	if issues[0].Number != 1 || issues[1].Number != 3 || issues[2].Number != 4 {
+		t.Errorf("ReadExport numbers = %d,%d,%d; want 1,3,4", issues[0].Number, issues[1].Number, issues[2].Number)
+	}
+
+	if _, err := ReadExport(strings.NewReader(`{"message": "Not Found"}`)); err == nil {
+		t.Error("expected error for a non-array export")
+	}
+}
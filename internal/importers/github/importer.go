@@ -0,0 +1,135 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/mariadb"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// DefaultBatchSize is the number of issues written per transaction.
+const DefaultBatchSize = 500
+
+// Store is the part of the MariaDB store the importer writes through.
+type Store interface {
+	CreateIssuesOnConflict(ctx context.Context, issues []*types.Issue, actor string, opts storage.BatchCreateOptions, onConflict mariadb.OnConflict) error
+	AddDependencies(ctx context.Context, deps []*types.Dependency, actor string) error
+}
+
+// Importer loads GitHub issues into a store.
+type Importer struct {
+	Store     Store
+	Prefix    string         // Issue ID prefix; must match the database's issue_prefix
+	Owner     string         // Repository owner, used for IDs and reference resolution
+	Repo      string         // Repository name
+	Actor     string         // Recorded as the creator of issues and dependencies (default: "github-import")
+	Mapping   *MappingConfig // Field mapping (default: DefaultMappingConfig)
+	BatchSize int            // Issues per transaction (default: DefaultBatchSize)
+}
+
+// ImportStats summarizes an import.
+type ImportStats struct {
+	Issues       int      // GitHub issues processed
+	Dependencies int      // Dependencies written
+	Warnings     []string // Issues and dependencies that could not be added
+}
+
+// Import writes issues to the store. Issues whose ID already exists are
+// skipped (mariadb.ConflictSkip), so re-running an import leaves previously
+// imported issues, and any local edits to them, unchanged. Dependencies
+// between imported issues are added afterwards; references to issues outside
+// issues are dropped. If the blocking references form a cycle, the
+// dependencies closing it are skipped with a warning. So is an issue listed
+// twice, or whose ID collides with another's, along with its dependencies,
+// rather than being taken for an issue imported before.
+func (im *Importer) Import(ctx context.Context, issues []Issue) (*ImportStats, error) {
+	if im.Store == nil || im.Prefix == "" || im.Owner == "" || im.Repo == "" {
+		return nil, errors.New("GitHub importer requires Store, Prefix, Owner and Repo")
+	}
+	config := im.Mapping
+	if config == nil {
+		config = DefaultMappingConfig()
+	}
+	actor := im.Actor
+	if actor == "" {
+		actor = "github-import"
+	}
+	batchSize := im.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	stats := &ImportStats{}
+	byNumber := make(map[int]string, len(issues))
+	var converted []*types.Issue
+	var deps []DependencyInfo
+	numberOf := make(map[string]int, len(issues)) // Inverse of byNumber
+	for i := range issues {
+		if issues[i].IsPullRequest() {
+			continue
+		}
+		conv := GitHubIssueToBeads(&issues[i], im.Prefix, im.Owner, im.Repo, config)
+		if other, dup := numberOf[conv.Issue.ID]; dup {
+			if other == issues[i].Number {
+				stats.Warnings = append(stats.Warnings, fmt.Sprintf("skipped duplicate GitHub issue #%d", other))
+			} else {
+				stats.Warnings = append(stats.Warnings, fmt.Sprintf("skipped GitHub issue #%d: its ID %s is already used by #%d", issues[i].Number, conv.Issue.ID, other))
+			}
+			continue
+		}
+		numberOf[conv.Issue.ID] = issues[i].Number
+		byNumber[issues[i].Number] = conv.Issue.ID
+		converted = append(converted, conv.Issue)
+		deps = append(deps, conv.Dependencies...)
+	}
+
+	opts := storage.BatchCreateOptions{OrphanHandling: storage.OrphanAllow}
+	for start := 0; start < len(converted); start += batchSize {
+		batch := converted[start:min(start+batchSize, len(converted))]
+		if err := im.Store.CreateIssuesOnConflict(ctx, batch, actor, opts, mariadb.ConflictSkip); err != nil {
+			return stats, fmt.Errorf("failed to import GitHub issues: %w", err)
+		}
+		stats.Issues += len(batch)
+	}
+
+	var links []*types.Dependency
+	for _, dep := range deps {
+		from, fromOK := byNumber[dep.FromNumber]
+		to, toOK := byNumber[dep.ToNumber]
+		if !fromOK || !toOK {
+			continue
+		}
+		links = append(links, &types.Dependency{
+			IssueID:     from,
+			DependsOnID: to,
+			Type:        dep.Type,
+			CreatedBy:   actor,
+		})
+	}
+	if len(links) == 0 {
+		return stats, nil
+	}
+	err := im.Store.AddDependencies(ctx, links, actor)
+	if err == nil {
+		stats.Dependencies = len(links)
+		return stats, nil
+	}
+	if !errors.Is(err, mariadb.ErrDependencyCycle) {
+		return stats, fmt.Errorf("failed to import GitHub issue dependencies: %w", err)
+	}
+	// Add them one at a time, so only the ones closing a cycle are lost
+	for _, link := range links {
+		if err := im.Store.AddDependencies(ctx, []*types.Dependency{link}, actor); err != nil {
+			if !errors.Is(err, mariadb.ErrDependencyCycle) {
+				return stats, fmt.Errorf("failed to import GitHub issue dependencies: %w", err)
+			}
+			stats.Warnings = append(stats.Warnings, fmt.Sprintf("skipped dependency %s -> %s: %v", link.IssueID, link.DependsOnID, err))
+			continue
+		}
+		stats.Dependencies++
+	}
+	return stats, nil
+}
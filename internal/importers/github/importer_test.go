@@ -0,0 +1,141 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/mariadb"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// fakeStore records what the importer writes and emulates ConflictSkip.
+type fakeStore struct {
+	issues    map[string]*types.Issue
+	deps      []*types.Dependency
+	conflicts []mariadb.OnConflict
+	cyclic    map[string]bool // Dependencies (issue->depends_on) rejected as cycles
+}
+
+func (f *fakeStore) CreateIssuesOnConflict(_ context.Context, issues []*types.Issue, _ string, _ storage.BatchCreateOptions, onConflict mariadb.OnConflict) error {
+	f.conflicts = append(f.conflicts, onConflict)
+	for _, issue := range issues {
+		if _, ok := f.issues[issue.ID]; ok && onConflict == mariadb.ConflictSkip {
+			continue
+		}
+		f.issues[issue.ID] = issue
+	}
+	return nil
+}
+
+func (f *fakeStore) AddDependencies(_ context.Context, deps []*types.Dependency, _ string) error {
+	for _, dep := range deps {
+		if f.cyclic[dep.IssueID+"->"+dep.DependsOnID] {
+			return fmt.Errorf("adding %s: %w", dep.IssueID, mariadb.ErrDependencyCycle)
+		}
+	}
+	f.deps = append(f.deps, deps...)
+	return nil
+}
+
+func fixtureList(t *testing.T) []Issue {
+	t.Helper()
+	var issues []Issue
+	for _, number := range []int{1, 3, 4} {
+		issues = append(issues, *fixtureIssues(t)[number])
+	}
+	return issues
+}
+
+// TestImportIsIdempotent verifies issues are written with ConflictSkip, so a
+// second run keeps the first run's issues.
+func TestImportIsIdempotent(t *testing.T) {
+	store := &fakeStore{issues: map[string]*types.Issue{}}
+	im := &Importer{Store: store, Prefix: "bd", Owner: "acme", Repo: "widgets", BatchSize: 2}
+
+	stats, err := im.Import(context.Background(), fixtureList(t))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if stats.Issues != 3 || len(store.issues) != 3 {
+		t.Errorf("imported %d issues (%d stored), want 3", stats.Issues, len(store.issues))
+	}
+	if len(store.conflicts) != 2 {
+		t.Errorf("made %d batch calls, want 2 with BatchSize 2", len(store.conflicts))
+	}
+	for _, mode := range store.conflicts {
+		if mode != mariadb.ConflictSkip {
+			t.Errorf("OnConflict = %v, want ConflictSkip", mode)
+		}
+	}
+
+	// #1 relates to #3; #3 is blocked by #1 and #4
+	if stats.Dependencies != 3 || len(store.deps) != 3 {
+		t.Fatalf("imported %d dependencies, want 3: %+v", stats.Dependencies, store.deps)
+	}
+	id1, id3, id4 := IssueID("bd", "acme", "widgets", 1), IssueID("bd", "acme", "widgets", 3), IssueID("bd", "acme", "widgets", 4)
+	want := map[string]types.DependencyType{
+		id1 + "->" + id3: types.DepRelated,
+		id3 + "->" + id1: types.DepBlocks,
+		id3 + "->" + id4: types.DepBlocks,
+	}
+	for _, dep := range store.deps {
+		if got := want[dep.IssueID+"->"+dep.DependsOnID]; got != dep.Type {
+			t.Errorf("unexpected dependency %s -> %s (%s)", dep.IssueID, dep.DependsOnID, dep.Type)
+		}
+	}
+
+	// A local edit survives re-running the import
+	store.issues[id1].Title = "Edited locally"
+	if _, err := im.Import(context.Background(), fixtureList(t)); err != nil {
+		t.Fatalf("second Import failed: %v", err)
+	}
+	if len(store.issues) != 3 || store.issues[id1].Title != "Edited locally" {
+		t.Errorf("second import changed existing issues: %d stored, title %q", len(store.issues), store.issues[id1].Title)
+	}
+}
+
+// TestImportSkipsCyclicDependencies verifies a cycle only drops the
+// dependencies that close it.
+func TestImportSkipsCyclicDependencies(t *testing.T) {
+	id3, id4 := IssueID("bd", "acme", "widgets", 3), IssueID("bd", "acme", "widgets", 4)
+	store := &fakeStore{issues: map[string]*types.Issue{}, cyclic: map[string]bool{id3 + "->" + id4: true}}
+	im := &Importer{Store: store, Prefix: "bd", Owner: "acme", Repo: "widgets"}
+
+	stats, err := im.Import(context.Background(), fixtureList(t))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if stats.Dependencies != 2 || len(stats.Warnings) != 1 {
+		t.Errorf("Dependencies, Warnings = %d, %v; want 2 and one warning", stats.Dependencies, stats.Warnings)
+	}
+}
+
+// TestImportWarnsOnDuplicateIDs verifies an issue whose ID is already taken
+// is reported instead of being skipped silently.
+func TestImportWarnsOnDuplicateIDs(t *testing.T) {
+	store := &fakeStore{issues: map[string]*types.Issue{}}
+	im := &Importer{Store: store, Prefix: "bd", Owner: "acme", Repo: "widgets"}
+
+	issues := fixtureList(t)
+	issues = append(issues, issues[0])
+	stats, err := im.Import(context.Background(), issues)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if stats.Issues != 3 || len(store.issues) != 3 {
+		t.Errorf("imported %d issues (%d stored), want 3", stats.Issues, len(store.issues))
+	}
+	if len(stats.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want one for the duplicate #1", stats.Warnings)
+	}
+}
+
+// TestImportRequiresRepository verifies the importer checks its configuration.
+func TestImportRequiresRepository(t *testing.T) {
+	im := &Importer{Store: &fakeStore{issues: map[string]*types.Issue{}}, Prefix: "bd"}
+	if _, err := im.Import(context.Background(), nil); err == nil {
+		t.Error("expected error without Owner and Repo")
+	}
+}
@@ -0,0 +1,265 @@
+package github
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/idgen"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// idHashLength is the number of base36 characters in imported issue IDs:
+// about 62 bits, so that IDs of different issues practically never collide,
+// even across repositories imported into one database.
+const idHashLength = 12
+
+// MappingConfig configures how GitHub fields map to beads fields.
+type MappingConfig struct {
+	PriorityMap  map[string]int    // priority label value → beads priority (0-4)
+	LabelTypeMap map[string]string // type label value → beads issue type
+}
+
+// DefaultMappingConfig returns the default mapping configuration.
+func DefaultMappingConfig() *MappingConfig {
+	return &MappingConfig{
+		PriorityMap: map[string]int{
+			"critical": 0,
+			"p0":       0,
+			"high":     1,
+			"p1":       1,
+			"medium":   2,
+			"p2":       2,
+			"low":      3,
+			"p3":       3,
+			"none":     4,
+			"p4":       4,
+		},
+		LabelTypeMap: map[string]string{
+			"bug":         "bug",
+			"feature":     "feature",
+			"enhancement": "feature",
+			"task":        "task",
+			"epic":        "epic",
+			"chore":       "chore",
+		},
+	}
+}
+
+// IssueConversion holds the result of converting a GitHub issue to Beads.
+// It includes the issue and any dependencies that should be created.
+type IssueConversion struct {
+	Issue        *types.Issue
+	Dependencies []DependencyInfo
+}
+
+// DependencyInfo represents a dependency to be created after issue import.
+// Stored separately since we need all issues imported before linking dependencies.
+type DependencyInfo struct {
+	FromNumber int                  // GitHub number of the dependent issue
+	ToNumber   int                  // GitHub number of the dependency target
+	Type       types.DependencyType // blocks or related
+}
+
+// IssueID returns the beads ID for issue number of the owner/repo repository.
+// It is derived from the repository and number alone, so importing the same
+// issue again yields the same ID.
+func IssueID(prefix, owner, repo string, number int) string {
+	key := strings.ToLower(owner+"/"+repo) + "#" + strconv.Itoa(number)
+	hash := sha256.Sum256([]byte(key))
+	return prefix + "-" + idgen.EncodeBase36(hash[:8], idHashLength)
+}
+
+// PriorityFromLabels extracts priority from "priority: <value>" labels or bare
+// P0-P4 labels. Returns default priority (2 = medium) if no priority label found.
+func PriorityFromLabels(labels []string, config *MappingConfig) int {
+	for _, label := range labels {
+		prefix, value := ParseLabelPrefix(label)
+		if prefix != "priority" && prefix != "" {
+			continue
+		}
+		p, ok := config.PriorityMap[strings.ToLower(value)]
+		// Bare labels only count as P0-P4; "low" alone is too ambiguous
+		if ok && (prefix == "priority" || strings.HasPrefix(strings.ToLower(value), "p")) {
+			return p
+		}
+	}
+	return 2 // Default to medium
+}
+
+// TypeFromLabels extracts issue type from GitHub labels.
+// Checks both prefixed ("type: bug") and bare ("bug") labels.
+// Returns "task" if no type label found.
+func TypeFromLabels(labels []string, config *MappingConfig) string {
+	for _, label := range labels {
+		prefix, value := ParseLabelPrefix(label)
+		if prefix != "type" && prefix != "" {
+			continue
+		}
+		if t, ok := config.LabelTypeMap[strings.ToLower(value)]; ok {
+			return t
+		}
+	}
+	return "task" // Default to task
+}
+
+// WispTypeFromLabels extracts the wisp type from a "wisp: <type>" label.
+// Returns "" if there is none or its value is not a known wisp type.
+func WispTypeFromLabels(labels []string) types.WispType {
+	for _, label := range labels {
+		prefix, value := ParseLabelPrefix(label)
+		if prefix != "wisp" {
+			continue
+		}
+		if w := types.WispType(strings.ToLower(value)); w != "" && w.IsValid() {
+			return w
+		}
+	}
+	return ""
+}
+
+// FilterMappedLabels returns the labels that are not consumed by the mapping:
+// priority, type, and wisp labels are dropped.
+func FilterMappedLabels(labels []string, config *MappingConfig) []string {
+	var filtered []string
+	for _, label := range labels {
+		prefix, value := ParseLabelPrefix(label)
+		switch prefix {
+		case "priority", "type", "wisp":
+			continue
+		case "":
+			lower := strings.ToLower(value)
+			if _, ok := config.LabelTypeMap[lower]; ok {
+				continue
+			}
+			if _, ok := config.PriorityMap[lower]; ok && strings.HasPrefix(lower, "p") {
+				continue
+			}
+		}
+		filtered = append(filtered, label)
+	}
+	return filtered
+}
+
+// blockingRefPattern matches "blocked by #12" and "depends on owner/repo#12"
+// references in an issue body.
+var blockingRefPattern = regexp.MustCompile(`(?i)\b(?:blocked\s+by|depends\s+on)\s+((?:[\w.-]+/[\w.-]+)?#\d+)`)
+
+// issueRefPattern matches "#12" and "owner/repo#12" references.
+var issueRefPattern = regexp.MustCompile(`(?:^|[^\w/#])((?:[\w.-]+/[\w.-]+)?#(\d+))\b`)
+
+// ReferencesToDependencies extracts dependencies from the issue references in
+// body. "Blocked by #N" and "depends on #N" make the issue depend on #N
+// (blocks); any other mention of #N relates the two. References to other
+// repositories and to the issue itself are ignored.
+func ReferencesToDependencies(number int, body, owner, repo string) []DependencyInfo {
+	blocking := make(map[int]bool)
+	for _, m := range blockingRefPattern.FindAllStringSubmatch(body, -1) {
+		if n, ok := localIssueNumber(m[1], owner, repo); ok {
+			blocking[n] = true
+		}
+	}
+
+	seen := make(map[int]bool)
+	var deps []DependencyInfo
+	for _, m := range issueRefPattern.FindAllStringSubmatch(body, -1) {
+		n, ok := localIssueNumber(m[1], owner, repo)
+		if !ok || n == number || seen[n] {
+			continue
+		}
+		seen[n] = true
+		depType := types.DepRelated
+		if blocking[n] {
+			depType = types.DepBlocks
+		}
+		deps = append(deps, DependencyInfo{FromNumber: number, ToNumber: n, Type: depType})
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].ToNumber < deps[j].ToNumber })
+	return deps
+}
+
+// localIssueNumber parses a "#N" or "owner/repo#N" reference, reporting
+// whether it points into the owner/repo repository.
+func localIssueNumber(ref, owner, repo string) (int, bool) {
+	path, num, _ := strings.Cut(ref, "#")
+	if path != "" && !strings.EqualFold(path, owner+"/"+repo) {
+		return 0, false
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// GitHubIssueToBeads converts a GitHub Issue of the owner/repo repository to
+// a beads Issue with an ID from IssueID.
+func GitHubIssueToBeads(gh *Issue, prefix, owner, repo string, config *MappingConfig) *IssueConversion {
+	webURL := gh.HTMLURL
+	labels := gh.LabelNames()
+
+	issue := &types.Issue{
+		ID:           IssueID(prefix, owner, repo, gh.Number),
+		Title:        gh.Title,
+		Description:  gh.Body,
+		SourceSystem: fmt.Sprintf("github:%s/%s:%d", owner, repo, gh.Number),
+		IssueType:    types.IssueType(TypeFromLabels(labels, config)),
+		Priority:     PriorityFromLabels(labels, config),
+		Status:       types.StatusOpen,
+		Labels:       FilterMappedLabels(labels, config),
+	}
+	if webURL != "" {
+		issue.ExternalRef = &webURL
+	}
+	if wisp := WispTypeFromLabels(labels); wisp != "" {
+		issue.WispType = wisp
+		issue.Ephemeral = true
+	}
+
+	if gh.User != nil {
+		issue.CreatedBy = gh.User.Login
+	}
+	if gh.Assignee != nil {
+		issue.Assignee = gh.Assignee.Login
+	} else if len(gh.Assignees) > 0 {
+		issue.Assignee = gh.Assignees[0].Login
+	}
+
+	// Set timestamps
+	if gh.CreatedAt != nil {
+		issue.CreatedAt = *gh.CreatedAt
+	}
+	if gh.UpdatedAt != nil {
+		issue.UpdatedAt = *gh.UpdatedAt
+	}
+	if gh.State == "closed" {
+		issue.Status = types.StatusClosed
+		closedAt := closedTime(gh)
+		issue.ClosedAt = &closedAt
+		if gh.StateReason == "not_planned" {
+			issue.CloseReason = "not planned"
+		}
+	}
+
+	return &IssueConversion{
+		Issue:        issue,
+		Dependencies: ReferencesToDependencies(gh.Number, gh.Body, owner, repo),
+	}
+}
+
+// closedTime returns when gh was closed, falling back to its last update for
+// exports that lack closed_at.
+func closedTime(gh *Issue) time.Time {
+	switch {
+	case gh.ClosedAt != nil:
+		return *gh.ClosedAt
+	case gh.UpdatedAt != nil:
+		return *gh.UpdatedAt
+	default:
+		return time.Now().UTC()
+	}
+}
@@ -0,0 +1,141 @@
+package github
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// fixtureIssues returns the issues recorded in testdata, by number.
+func fixtureIssues(t *testing.T) map[int]*Issue {
+	t.Helper()
+	data := append(fixture(t, "issues_page1.json"), fixture(t, "issues_page2.json")...)
+	issues, err := ReadExport(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadExport failed: %v", err)
+	}
+	byNumber := make(map[int]*Issue, len(issues))
+	for i := range issues {
+		byNumber[issues[i].Number] = &issues[i]
+	}
+	return byNumber
+}
+
+// TestGitHubIssueToBeadsClosedBug verifies the field mapping of a closed bug.
+func TestGitHubIssueToBeadsClosedBug(t *testing.T) {
+	gh := fixtureIssues(t)[1]
+	conv := GitHubIssueToBeads(gh, "bd", "acme", "widgets", DefaultMappingConfig())
+	issue := conv.Issue
+
+	if issue.ID != IssueID("bd", "acme", "widgets", 1) {
+		t.Errorf("ID = %q, want IssueID for acme/widgets#1", issue.ID)
+	}
+	if issue.Title != "Crash when config file is empty" {
+		t.Errorf("Title = %q", issue.Title)
+	}
+	if issue.Description != gh.Body {
+		t.Errorf("Description = %q, want the issue body", issue.Description)
+	}
+	if issue.IssueType != types.TypeBug {
+		t.Errorf("IssueType = %q, want bug", issue.IssueType)
+	}
+	if issue.Priority != 1 {
+		t.Errorf("Priority = %d, want 1 from \"priority: high\"", issue.Priority)
+	}
+	if issue.Status != types.StatusClosed {
+		t.Errorf("Status = %q, want closed", issue.Status)
+	}
+	wantClosed := time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)
+	if issue.ClosedAt == nil || !issue.ClosedAt.Equal(wantClosed) {
+		t.Errorf("ClosedAt = %v, want %v", issue.ClosedAt, wantClosed)
+	}
+	if issue.Assignee != "hubot" || issue.CreatedBy != "octocat" {
+		t.Errorf("Assignee, CreatedBy = %q, %q; want hubot, octocat", issue.Assignee, issue.CreatedBy)
+	}
+	if issue.ExternalRef == nil || *issue.ExternalRef != "https://github.com/acme/widgets/issues/1" {
+		t.Errorf("ExternalRef = %v, want the issue URL", issue.ExternalRef)
+	}
+	if issue.SourceSystem != "github:acme/widgets:1" {
+		t.Errorf("SourceSystem = %q, want github:acme/widgets:1", issue.SourceSystem)
+	}
+	if !reflect.DeepEqual(issue.Labels, []string{"area/config"}) {
+		t.Errorf("Labels = %v, want only the unmapped area/config", issue.Labels)
+	}
+	if err := issue.Validate(); err != nil {
+		t.Errorf("mapped issue is invalid: %v", err)
+	}
+
+	wantDeps := []DependencyInfo{{FromNumber: 1, ToNumber: 3, Type: types.DepRelated}}
+	if !reflect.DeepEqual(conv.Dependencies, wantDeps) {
+		t.Errorf("Dependencies = %+v, want %+v", conv.Dependencies, wantDeps)
+	}
+}
+
+// TestGitHubIssueToBeadsOpenFeature verifies bare P labels, enhancement, and
+// blocking references.
+func TestGitHubIssueToBeadsOpenFeature(t *testing.T) {
+	conv := GitHubIssueToBeads(fixtureIssues(t)[3], "bd", "acme", "widgets", DefaultMappingConfig())
+	issue := conv.Issue
+
+	if issue.IssueType != types.TypeFeature || issue.Priority != 3 || issue.Status != types.StatusOpen {
+		t.Errorf("type, priority, status = %q, %d, %q; want feature, 3, open", issue.IssueType, issue.Priority, issue.Status)
+	}
+	if issue.ClosedAt != nil || issue.Assignee != "" || len(issue.Labels) != 0 {
+		t.Errorf("ClosedAt, Assignee, Labels = %v, %q, %v; want unset", issue.ClosedAt, issue.Assignee, issue.Labels)
+	}
+
+	// Blocked by #1 and acme/widgets#4; other/repo#9 is another repository
+	wantDeps := []DependencyInfo{
+		{FromNumber: 3, ToNumber: 1, Type: types.DepBlocks},
+		{FromNumber: 3, ToNumber: 4, Type: types.DepBlocks},
+	}
+	if !reflect.DeepEqual(conv.Dependencies, wantDeps) {
+		t.Errorf("Dependencies = %+v, want %+v", conv.Dependencies, wantDeps)
+	}
+}
+
+// TestGitHubIssueToBeadsWisp verifies "wisp:" labels set the wisp type.
+func TestGitHubIssueToBeadsWisp(t *testing.T) {
+	issue := GitHubIssueToBeads(fixtureIssues(t)[4], "bd", "acme", "widgets", DefaultMappingConfig()).Issue
+
+	if issue.WispType != types.WispTypeHeartbeat || !issue.Ephemeral {
+		t.Errorf("WispType, Ephemeral = %q, %v; want heartbeat, true", issue.WispType, issue.Ephemeral)
+	}
+	if issue.IssueType != types.TypeChore {
+		t.Errorf("IssueType = %q, want chore from \"type: chore\"", issue.IssueType)
+	}
+}
+
+// TestIssueID verifies IDs are stable per repository and issue number.
+func TestIssueID(t *testing.T) {
+	id := IssueID("bd", "acme", "widgets", 1)
+	if id != IssueID("bd", "Acme", "Widgets", 1) {
+		t.Error("IssueID should not depend on owner/repo case")
+	}
+	if id == IssueID("bd", "acme", "widgets", 2) || id == IssueID("bd", "acme", "gadgets", 1) {
+		t.Error("IssueID should differ across numbers and repositories")
+	}
+	if len(id) != len("bd-")+idHashLength {
+		t.Errorf("IssueID = %q, want bd- plus %d characters", id, idHashLength)
+	}
+}
+
+// TestParseLabelPrefix verifies prefixed labels with and without spaces.
+func TestParseLabelPrefix(t *testing.T) {
+	tests := []struct {
+		label, prefix, value string
+	}{
+		{"priority: high", "priority", "high"},
+		{"Type:Bug", "type", "Bug"},
+		{"good first issue", "", "good first issue"},
+	}
+	for _, tt := range tests {
+		prefix, value := ParseLabelPrefix(tt.label)
+		if prefix != tt.prefix || value != tt.value {
+			t.Errorf("ParseLabelPrefix(%q) = %q, %q; want %q, %q", tt.label, prefix, value, tt.prefix, tt.value)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Package github imports GitHub Issues into Beads.
+//
+// Issues are read either from a saved REST API export (for example the output
+// of `gh api --paginate repos/OWNER/REPO/issues?state=all`) or fetched from
+// the API with a token. They are mapped to Beads issues with IDs derived from
+// the repository and issue number, so re-running an import skips issues that
+// were already imported instead of duplicating them.
+package github
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// API configuration constants.
+const (
+	// DefaultBaseURL is the GitHub REST API root.
+	DefaultBaseURL = "https://api.github.com"
+
+	// DefaultTimeout is the default HTTP request timeout.
+	DefaultTimeout = 30 * time.Second
+
+	// MaxRetries is the maximum number of retries for rate-limited requests.
+	MaxRetries = 3
+
+	// RetryDelay is the base delay between retries when GitHub gives no
+	// reset time (exponential backoff).
+	RetryDelay = time.Second
+
+	// MaxRateLimitWait caps how long a request waits for a rate limit to reset.
+	MaxRateLimitWait = 15 * time.Minute
+
+	// MaxPageSize is the maximum number of issues to fetch per page.
+	MaxPageSize = 100
+
+	// MaxPages is the maximum number of pages to fetch before stopping.
+	// This prevents infinite loops from malformed Link headers.
+	MaxPages = 1000
+
+	// APIVersion is the REST API version requested by the client.
+	APIVersion = "2022-11-28"
+)
+
+// Client provides methods to fetch issues from the GitHub REST API.
+type Client struct {
+	Token      string       // Personal access token; empty for anonymous access to public repos
+	BaseURL    string       // API root (e.g., "https://api.github.com" or a GitHub Enterprise "/api/v3" URL)
+	Owner      string       // Repository owner (user or organization)
+	Repo       string       // Repository name
+	HTTPClient *http.Client // Optional custom HTTP client
+}
+
+// Issue represents an issue from the GitHub REST API.
+type Issue struct {
+	ID          int64      `json:"id"`     // Global issue ID
+	Number      int        `json:"number"` // Repository-scoped issue number
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	State       string     `json:"state"`                  // "open" or "closed"
+	StateReason string     `json:"state_reason,omitempty"` // "completed", "not_planned", "reopened"
+	Labels      []Label    `json:"labels"`
+	User        *User      `json:"user,omitempty"`
+	Assignee    *User      `json:"assignee,omitempty"`
+	Assignees   []User     `json:"assignees,omitempty"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+	HTMLURL     string     `json:"html_url"`
+
+	// PullRequest is set when the item is a pull request; the issues
+	// endpoint returns both.
+	PullRequest *PullRequestRef `json:"pull_request,omitempty"`
+}
+
+// IsPullRequest reports whether the item is a pull request rather than an issue.
+func (i *Issue) IsPullRequest() bool {
+	return i.PullRequest != nil
+}
+
+// LabelNames returns the names of the issue's labels.
+func (i *Issue) LabelNames() []string {
+	names := make([]string, 0, len(i.Labels))
+	for _, label := range i.Labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+// Label represents a GitHub label.
+type Label struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// User represents a GitHub user.
+type User struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Type  string `json:"type,omitempty"` // "User", "Bot", "Organization"
+}
+
+// PullRequestRef marks an issues-endpoint item as a pull request.
+type PullRequestRef struct {
+	URL     string `json:"url"`
+	HTMLURL string `json:"html_url"`
+}
+
+// ParseLabelPrefix splits a label into prefix and value.
+// GitHub labels like "priority: high" or "type:bug" are split into
+// ("priority", "high") and ("type", "bug"). Labels without ":" return an
+// empty prefix and the original label as value.
+func ParseLabelPrefix(label string) (prefix, value string) {
+	prefix, value, ok := strings.Cut(label, ":")
+	if !ok {
+		return "", label
+	}
+	return strings.ToLower(strings.TrimSpace(prefix)), strings.TrimSpace(value)
+}
@@ -0,0 +1,464 @@
+package storage
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// DefaultCacheSize is the number of issues WithCache keeps when size is not
+// positive.
+const DefaultCacheSize = 1000
+
+// CacheStats counts the GetIssue calls served by a WithCache store.
+type CacheStats struct {
+	Hits   uint64 // Calls answered from the cache
+	Misses uint64 // Calls passed on to the wrapped store
+}
+
+// WithCache wraps s so that GetIssue answers repeated lookups of the same
+// issue from memory. Up to size issues (DefaultCacheSize if size is not
+// positive) are kept, least recently used first out, each for at most ttl
+// (no limit if ttl is not positive). Issues that were not found are not
+// cached. Every other method goes to s.
+//
+// Writes through the returned store drop the issues they change: updates,
+// claims, closes, deletes, label, dependency and comment changes, and ID
+// renames drop the issues named, and RunInTransaction, dependency prefix
+// renames and Close drop everything. Writes that bypass it, through s
+// directly, UnderlyingDB, or another process, are seen once ttl expires.
+//
+// The returned store is safe for concurrent use. Each GetIssue caller gets
+// its own copy of the Issue and its Labels; other slices and pointers in it
+// are shared with the cache and must not be modified. Use CacheStatsOf for
+// the hit and miss counts, and Unwrap to reach s.
+func WithCache(s Storage, ttl time.Duration, size int) Storage {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &cacheStorage{
+		s:       s,
+		ttl:     ttl,
+		size:    size,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		now:     time.Now,
+	}
+}
+
+// CacheStatsOf returns the hit and miss counts of a store returned by
+// WithCache, or false if s is not one.
+func CacheStatsOf(s Storage) (CacheStats, bool) {
+	c, ok := s.(*cacheStorage)
+	if !ok {
+		return CacheStats{}, false
+	}
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load()}, true
+}
+
+// cacheStorage implements WithCache.
+type cacheStorage struct {
+	s    Storage
+	ttl  time.Duration
+	size int
+	now  func() time.Time // Replaced by tests
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // Values are *cacheEntry
+	lru     *list.List               // Most recently used at the front
+	gen     uint64                   // Bumped by every invalidation
+
+	hits, misses atomic.Uint64
+}
+
+var _ Storage = (*cacheStorage)(nil)
+
+// cacheEntry is a cached issue.
+type cacheEntry struct {
+	id      string
+	issue   *types.Issue
+	expires time.Time // Zero if the cache has no ttl
+}
+
+// lookup returns the cached issue id, if present and not expired, and the
+// current generation for a later store.
+func (c *cacheStorage) lookup(id string) (*types.Issue, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[id]
+	if !ok {
+		return nil, c.gen
+	}
+	entry := el.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && !c.now().Before(entry.expires) {
+		c.lru.Remove(el)
+		delete(c.entries, id)
+		return nil, c.gen
+	}
+	c.lru.MoveToFront(el)
+	return entry.issue, c.gen
+}
+
+// store caches issue under id, unless something was invalidated since gen
+// was read: issue may have been read before that write.
+func (c *cacheStorage) store(id string, issue *types.Issue, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	entry := &cacheEntry{id: id, issue: issue}
+	if c.ttl > 0 {
+		entry.expires = c.now().Add(c.ttl)
+	}
+	if el, ok := c.entries[id]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[id] = c.lru.PushFront(entry)
+	if c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).id)
+	}
+}
+
+// invalidate drops the issues ids from the cache.
+func (c *cacheStorage) invalidate(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	for _, id := range ids {
+		if el, ok := c.entries[id]; ok {
+			c.lru.Remove(el)
+			delete(c.entries, id)
+		}
+	}
+}
+
+// purge drops every issue from the cache.
+func (c *cacheStorage) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	clear(c.entries)
+	c.lru.Init()
+}
+
+// copyIssue returns a copy of issue for one caller.
+func copyIssue(issue *types.Issue) *types.Issue {
+	cp := *issue
+	cp.Labels = slices.Clone(issue.Labels)
+	return &cp
+}
+
+func (c *cacheStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	return c.s.CreateIssue(ctx, issue, actor)
+}
+
+func (c *cacheStorage) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	return c.s.CreateIssues(ctx, issues, actor)
+}
+
+func (c *cacheStorage) CreateIssuesWithFullOptions(ctx context.Context, issues []*types.Issue, actor string, opts BatchCreateOptions) error {
+	return c.s.CreateIssuesWithFullOptions(ctx, issues, actor, opts)
+}
+
+func (c *cacheStorage) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	issue, gen := c.lookup(id)
+	if issue != nil {
+		c.hits.Add(1)
+		return copyIssue(issue), nil
+	}
+	c.misses.Add(1)
+	issue, err := c.s.GetIssue(ctx, id)
+	if err != nil || issue == nil {
+		return issue, err
+	}
+	c.store(id, copyIssue(issue), gen)
+	return issue, nil
+}
+
+func (c *cacheStorage) GetIssueByExternalRef(ctx context.Context, externalRef string) (*types.Issue, error) {
+	return c.s.GetIssueByExternalRef(ctx, externalRef)
+}
+
+func (c *cacheStorage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	defer c.invalidate(id)
+	return c.s.UpdateIssue(ctx, id, updates, actor)
+}
+
+func (c *cacheStorage) ClaimIssue(ctx context.Context, id string, actor string) error {
+	defer c.invalidate(id)
+	return c.s.ClaimIssue(ctx, id, actor)
+}
+
+func (c *cacheStorage) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
+	defer c.invalidate(id)
+	return c.s.CloseIssue(ctx, id, reason, actor, session)
+}
+
+func (c *cacheStorage) DeleteIssue(ctx context.Context, id string) error {
+	defer c.invalidate(id)
+	return c.s.DeleteIssue(ctx, id)
+}
+
+func (c *cacheStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	return c.s.SearchIssues(ctx, query, filter)
+}
+
+func (c *cacheStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	if dep != nil {
+		defer c.invalidate(dep.IssueID)
+	}
+	return c.s.AddDependency(ctx, dep, actor)
+}
+
+func (c *cacheStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	defer c.invalidate(issueID)
+	return c.s.RemoveDependency(ctx, issueID, dependsOnID, actor)
+}
+
+func (c *cacheStorage) GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	return c.s.GetDependencies(ctx, issueID)
+}
+
+func (c *cacheStorage) GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	return c.s.GetDependents(ctx, issueID)
+}
+
+func (c *cacheStorage) GetDependenciesWithMetadata(ctx context.Context, issueID string) ([]*types.IssueWithDependencyMetadata, error) {
+	return c.s.GetDependenciesWithMetadata(ctx, issueID)
+}
+
+func (c *cacheStorage) GetDependentsWithMetadata(ctx context.Context, issueID string) ([]*types.IssueWithDependencyMetadata, error) {
+	return c.s.GetDependentsWithMetadata(ctx, issueID)
+}
+
+func (c *cacheStorage) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
+	return c.s.GetDependencyRecords(ctx, issueID)
+}
+
+func (c *cacheStorage) GetAllDependencyRecords(ctx context.Context) (map[string][]*types.Dependency, error) {
+	return c.s.GetAllDependencyRecords(ctx)
+}
+
+func (c *cacheStorage) GetDependencyRecordsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Dependency, error) {
+	return c.s.GetDependencyRecordsForIssues(ctx, issueIDs)
+}
+
+func (c *cacheStorage) GetDependencyCounts(ctx context.Context, issueIDs []string) (map[string]*types.DependencyCounts, error) {
+	return c.s.GetDependencyCounts(ctx, issueIDs)
+}
+
+func (c *cacheStorage) GetDependencyTree(ctx context.Context, issueID string, maxDepth int, showAllPaths bool, reverse bool) ([]*types.TreeNode, error) {
+	return c.s.GetDependencyTree(ctx, issueID, maxDepth, showAllPaths, reverse)
+}
+
+func (c *cacheStorage) DetectCycles(ctx context.Context) ([][]*types.Issue, error) {
+	return c.s.DetectCycles(ctx)
+}
+
+func (c *cacheStorage) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	defer c.invalidate(issueID)
+	return c.s.AddLabel(ctx, issueID, label, actor)
+}
+
+func (c *cacheStorage) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	defer c.invalidate(issueID)
+	return c.s.RemoveLabel(ctx, issueID, label, actor)
+}
+
+func (c *cacheStorage) GetLabels(ctx context.Context, issueID string) ([]string, error) {
+	return c.s.GetLabels(ctx, issueID)
+}
+
+func (c *cacheStorage) GetLabelsForIssues(ctx context.Context, issueIDs []string) (map[string][]string, error) {
+	return c.s.GetLabelsForIssues(ctx, issueIDs)
+}
+
+func (c *cacheStorage) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
+	return c.s.GetIssuesByLabel(ctx, label)
+}
+
+func (c *cacheStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	return c.s.GetReadyWork(ctx, filter)
+}
+
+func (c *cacheStorage) GetBlockedIssues(ctx context.Context, filter types.WorkFilter) ([]*types.BlockedIssue, error) {
+	return c.s.GetBlockedIssues(ctx, filter)
+}
+
+func (c *cacheStorage) IsBlocked(ctx context.Context, issueID string) (bool, []string, error) {
+	return c.s.IsBlocked(ctx, issueID)
+}
+
+func (c *cacheStorage) GetEpicsEligibleForClosure(ctx context.Context) ([]*types.EpicStatus, error) {
+	return c.s.GetEpicsEligibleForClosure(ctx)
+}
+
+func (c *cacheStorage) GetStaleIssues(ctx context.Context, filter types.StaleFilter) ([]*types.Issue, error) {
+	return c.s.GetStaleIssues(ctx, filter)
+}
+
+func (c *cacheStorage) GetNewlyUnblockedByClose(ctx context.Context, closedIssueID string) ([]*types.Issue, error) {
+	return c.s.GetNewlyUnblockedByClose(ctx, closedIssueID)
+}
+
+func (c *cacheStorage) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	defer c.invalidate(issueID)
+	return c.s.AddComment(ctx, issueID, actor, comment)
+}
+
+func (c *cacheStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
+	return c.s.GetEvents(ctx, issueID, limit)
+}
+
+func (c *cacheStorage) GetAllEventsSince(ctx context.Context, sinceID int64) ([]*types.Event, error) {
+	return c.s.GetAllEventsSince(ctx, sinceID)
+}
+
+func (c *cacheStorage) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
+	defer c.invalidate(issueID)
+	return c.s.AddIssueComment(ctx, issueID, author, text)
+}
+
+func (c *cacheStorage) ImportIssueComment(ctx context.Context, issueID, author, text string, createdAt time.Time) (*types.Comment, error) {
+	defer c.invalidate(issueID)
+	return c.s.ImportIssueComment(ctx, issueID, author, text, createdAt)
+}
+
+func (c *cacheStorage) GetIssueComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
+	return c.s.GetIssueComments(ctx, issueID)
+}
+
+func (c *cacheStorage) GetCommentsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Comment, error) {
+	return c.s.GetCommentsForIssues(ctx, issueIDs)
+}
+
+func (c *cacheStorage) GetCommentCounts(ctx context.Context, issueIDs []string) (map[string]int, error) {
+	return c.s.GetCommentCounts(ctx, issueIDs)
+}
+
+func (c *cacheStorage) GetStatistics(ctx context.Context) (*types.Statistics, error) {
+	return c.s.GetStatistics(ctx)
+}
+
+func (c *cacheStorage) GetMoleculeProgress(ctx context.Context, moleculeID string) (*types.MoleculeProgressStats, error) {
+	return c.s.GetMoleculeProgress(ctx, moleculeID)
+}
+
+func (c *cacheStorage) GetDirtyIssues(ctx context.Context) ([]string, error) {
+	return c.s.GetDirtyIssues(ctx)
+}
+
+func (c *cacheStorage) GetDirtyIssueHash(ctx context.Context, issueID string) (string, error) {
+	return c.s.GetDirtyIssueHash(ctx, issueID)
+}
+
+func (c *cacheStorage) ClearDirtyIssuesByID(ctx context.Context, issueIDs []string) error {
+	return c.s.ClearDirtyIssuesByID(ctx, issueIDs)
+}
+
+func (c *cacheStorage) GetExportHash(ctx context.Context, issueID string) (string, error) {
+	return c.s.GetExportHash(ctx, issueID)
+}
+
+func (c *cacheStorage) SetExportHash(ctx context.Context, issueID, contentHash string) error {
+	return c.s.SetExportHash(ctx, issueID, contentHash)
+}
+
+func (c *cacheStorage) ClearAllExportHashes(ctx context.Context) error {
+	return c.s.ClearAllExportHashes(ctx)
+}
+
+func (c *cacheStorage) GetJSONLFileHash(ctx context.Context) (string, error) {
+	return c.s.GetJSONLFileHash(ctx)
+}
+
+func (c *cacheStorage) SetJSONLFileHash(ctx context.Context, fileHash string) error {
+	return c.s.SetJSONLFileHash(ctx, fileHash)
+}
+
+func (c *cacheStorage) GetNextChildID(ctx context.Context, parentID string) (string, error) {
+	return c.s.GetNextChildID(ctx, parentID)
+}
+
+func (c *cacheStorage) SetConfig(ctx context.Context, key, value string) error {
+	return c.s.SetConfig(ctx, key, value)
+}
+
+func (c *cacheStorage) GetConfig(ctx context.Context, key string) (string, error) {
+	return c.s.GetConfig(ctx, key)
+}
+
+func (c *cacheStorage) GetAllConfig(ctx context.Context) (map[string]string, error) {
+	return c.s.GetAllConfig(ctx)
+}
+
+func (c *cacheStorage) DeleteConfig(ctx context.Context, key string) error {
+	return c.s.DeleteConfig(ctx, key)
+}
+
+func (c *cacheStorage) GetCustomStatuses(ctx context.Context) ([]string, error) {
+	return c.s.GetCustomStatuses(ctx)
+}
+
+func (c *cacheStorage) GetCustomTypes(ctx context.Context) ([]string, error) {
+	return c.s.GetCustomTypes(ctx)
+}
+
+func (c *cacheStorage) SetMetadata(ctx context.Context, key, value string) error {
+	return c.s.SetMetadata(ctx, key, value)
+}
+
+func (c *cacheStorage) GetMetadata(ctx context.Context, key string) (string, error) {
+	return c.s.GetMetadata(ctx, key)
+}
+
+func (c *cacheStorage) UpdateIssueID(ctx context.Context, oldID, newID string, issue *types.Issue, actor string) error {
+	defer c.invalidate(oldID, newID)
+	return c.s.UpdateIssueID(ctx, oldID, newID, issue, actor)
+}
+
+func (c *cacheStorage) RenameDependencyPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
+	defer c.purge()
+	return c.s.RenameDependencyPrefix(ctx, oldPrefix, newPrefix)
+}
+
+func (c *cacheStorage) RenameCounterPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
+	return c.s.RenameCounterPrefix(ctx, oldPrefix, newPrefix)
+}
+
+func (c *cacheStorage) RunInTransaction(ctx context.Context, fn func(tx Transaction) error) error {
+	defer c.purge()
+	return c.s.RunInTransaction(ctx, fn)
+}
+
+func (c *cacheStorage) Close() error {
+	defer c.purge()
+	return c.s.Close()
+}
+
+func (c *cacheStorage) Path() string {
+	return c.s.Path()
+}
+
+func (c *cacheStorage) UnderlyingDB() *sql.DB {
+	return c.s.UnderlyingDB()
+}
+
+func (c *cacheStorage) UnderlyingConn(ctx context.Context) (*sql.Conn, error) {
+	return c.s.UnderlyingConn(ctx)
+}
+
+func (c *cacheStorage) Capabilities() Capabilities {
+	return c.s.Capabilities()
+}
@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// issueMockStorage serves GetIssue from a map and counts the calls.
+type issueMockStorage struct {
+	mockStorage
+	mu     sync.Mutex
+	issues map[string]*types.Issue
+	gets   atomic.Int64
+}
+
+func (m *issueMockStorage) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	m.gets.Add(1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	issue, ok := m.issues[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *issue
+	return &cp, nil
+}
+
+func (m *issueMockStorage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if title, ok := updates["title"].(string); ok {
+		m.issues[id].Title = title
+	}
+	return nil
+}
+
+func newIssueMock(ids ...string) *issueMockStorage {
+	m := &issueMockStorage{issues: make(map[string]*types.Issue)}
+	for _, id := range ids {
+		m.issues[id] = &types.Issue{ID: id, Title: "Title of " + id, Labels: []string{"cached"}}
+	}
+	return m
+}
+
+func TestWithCacheHits(t *testing.T) {
+	ctx := context.Background()
+	backend := newIssueMock("bd-1", "bd-2")
+	store := WithCache(backend, time.Minute, 10)
+
+	for i := 0; i < 3; i++ {
+		issue, err := store.GetIssue(ctx, "bd-1")
+		if err != nil || issue == nil || issue.Title != "Title of bd-1" {
+			t.Fatalf("GetIssue(bd-1) = %+v, %v", issue, err)
+		}
+		// Callers get their own copy
+		issue.Title = "changed by caller"
+		issue.Labels[0] = "changed by caller"
+	}
+	if got := backend.gets.Load(); got != 1 {
+		t.Errorf("backend GetIssue called %d times, want 1", got)
+	}
+	if issue, _ := store.GetIssue(ctx, "bd-1"); issue.Title != "Title of bd-1" || issue.Labels[0] != "cached" {
+		t.Errorf("cached issue = %+v, changed by a caller", issue)
+	}
+
+	// Missing issues are not cached
+	for i := 0; i < 2; i++ {
+		if issue, err := store.GetIssue(ctx, "bd-missing"); err != nil || issue != nil {
+			t.Fatalf("GetIssue(bd-missing) = %+v, %v; want nil", issue, err)
+		}
+	}
+	if got := backend.gets.Load(); got != 3 {
+		t.Errorf("backend GetIssue called %d times, want 3", got)
+	}
+
+	stats, ok := CacheStatsOf(store)
+	if !ok || stats != (CacheStats{Hits: 3, Misses: 3}) {
+		t.Errorf("CacheStatsOf = %+v, %v; want 3 hits and 3 misses", stats, ok)
+	}
+	if _, ok := CacheStatsOf(backend); ok {
+		t.Error("CacheStatsOf(backend) reported stats for a store without a cache")
+	}
+	if Unwrap(store) != Storage(backend) {
+		t.Error("Unwrap did not return the wrapped store")
+	}
+}
+
+func TestWithCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	backend := newIssueMock("bd-1", "bd-2")
+	store := WithCache(backend, time.Minute, 10)
+
+	if _, err := store.GetIssue(ctx, "bd-1"); err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if _, err := store.GetIssue(ctx, "bd-2"); err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, "bd-1", map[string]interface{}{"title": "Renamed"}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if issue, _ := store.GetIssue(ctx, "bd-1"); issue.Title != "Renamed" {
+		t.Errorf("GetIssue after UpdateIssue = %q, want Renamed", issue.Title)
+	}
+	if _, err := store.GetIssue(ctx, "bd-2"); err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	// bd-1 twice and bd-2 once; the update left bd-2 cached
+	if got := backend.gets.Load(); got != 3 {
+		t.Errorf("backend GetIssue called %d times, want 3", got)
+	}
+
+	writes := map[string]func() error{
+		"ClaimIssue":  func() error { return store.ClaimIssue(ctx, "bd-1", "tester") },
+		"CloseIssue":  func() error { return store.CloseIssue(ctx, "bd-1", "done", "tester", "") },
+		"DeleteIssue": func() error { return store.DeleteIssue(ctx, "bd-1") },
+		"AddLabel":    func() error { return store.AddLabel(ctx, "bd-1", "new", "tester") },
+		"RemoveLabel": func() error { return store.RemoveLabel(ctx, "bd-1", "cached", "tester") },
+		"AddComment":  func() error { return store.AddComment(ctx, "bd-1", "tester", "hi") },
+		"AddDependency": func() error {
+			return store.AddDependency(ctx, &types.Dependency{IssueID: "bd-1", DependsOnID: "bd-2"}, "tester")
+		},
+		"RemoveDependency": func() error { return store.RemoveDependency(ctx, "bd-1", "bd-2", "tester") },
+		"UpdateIssueID":    func() error { return store.UpdateIssueID(ctx, "bd-1", "bd-9", nil, "tester") },
+		"RunInTransaction": func() error {
+			return store.RunInTransaction(ctx, func(Transaction) error { return nil })
+		},
+	}
+	for name, write := range writes {
+		if _, err := store.GetIssue(ctx, "bd-1"); err != nil {
+			t.Fatalf("GetIssue failed: %v", err)
+		}
+		before := backend.gets.Load()
+		if err := write(); err != nil {
+			t.Fatalf("%s failed: %v", name, err)
+		}
+		if _, err := store.GetIssue(ctx, "bd-1"); err != nil {
+			t.Fatalf("GetIssue failed: %v", err)
+		}
+		if got := backend.gets.Load() - before; got != 1 {
+			t.Errorf("after %s, GetIssue(bd-1) went to the backend %d times, want 1", name, got)
+		}
+	}
+}
+
+func TestWithCacheExpiryAndEviction(t *testing.T) {
+	ctx := context.Background()
+	backend := newIssueMock("bd-1", "bd-2", "bd-3")
+	store := WithCache(backend, time.Minute, 2)
+	now := time.Now()
+	store.(*cacheStorage).now = func() time.Time { return now }
+
+	for _, id := range []string{"bd-1", "bd-2", "bd-1", "bd-3"} {
+		if _, err := store.GetIssue(ctx, id); err != nil {
+			t.Fatalf("GetIssue(%s) failed: %v", id, err)
+		}
+	}
+	// bd-2 was least recently used when bd-3 arrived
+	// Checked in order: fetching bd-2 evicts another entry
+	for _, tc := range []struct {
+		id   string
+		want int64
+	}{{"bd-1", 0}, {"bd-3", 0}, {"bd-2", 1}} {
+		before := backend.gets.Load()
+		if _, err := store.GetIssue(ctx, tc.id); err != nil {
+			t.Fatalf("GetIssue(%s) failed: %v", tc.id, err)
+		}
+		if got := backend.gets.Load() - before; got != tc.want {
+			t.Errorf("GetIssue(%s) went to the backend %d times, want %d", tc.id, got, tc.want)
+		}
+	}
+
+	now = now.Add(time.Minute)
+	before := backend.gets.Load()
+	if _, err := store.GetIssue(ctx, "bd-2"); err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got := backend.gets.Load() - before; got != 1 {
+		t.Errorf("GetIssue after the ttl went to the backend %d times, want 1", got)
+	}
+}
+
+func TestWithCacheConcurrent(t *testing.T) {
+	ctx := context.Background()
+	ids := make([]string, 20)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("bd-%d", i)
+	}
+	backend := newIssueMock(ids...)
+	store := WithCache(backend, time.Minute, 10)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				id := ids[(w*7+i)%len(ids)]
+				if i%10 == 0 {
+					if err := store.UpdateIssue(ctx, id, map[string]interface{}{"title": "Title of " + id}, "tester"); err != nil {
+						t.Errorf("UpdateIssue failed: %v", err)
+					}
+					continue
+				}
+				if issue, err := store.GetIssue(ctx, id); err != nil || issue == nil || issue.ID != id {
+					t.Errorf("GetIssue(%s) = %+v, %v", id, issue, err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	stats, _ := CacheStatsOf(store)
+	if stats.Hits+stats.Misses != 8*180 || stats.Misses != uint64(backend.gets.Load()) {
+		t.Errorf("CacheStatsOf = %+v with %d backend calls, want 1440 lookups with a miss per backend call", stats, backend.gets.Load())
+	}
+}
@@ -447,6 +447,15 @@ func (s *DoltStore) UnderlyingConn(ctx context.Context) (*sql.Conn, error) {
 	return s.db.Conn(ctx)
 }
 
+// Capabilities reports the MySQL features Dolt implements. Dolt has no
+// full-text search or SKIP LOCKED.
+func (s *DoltStore) Capabilities() storage.Capabilities {
+	return storage.Capabilities{
+		RecursiveCTE:    true,
+		WindowFunctions: true,
+	}
+}
+
 // =============================================================================
 // Version Control Operations (Dolt-specific extensions)
 // =============================================================================
@@ -3,6 +3,7 @@ package factory
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -32,11 +33,27 @@ type Options struct {
 	ServerPort int    // Server port (default: 3307)
 	ServerUser string // MySQL user (default: root)
 	Database   string // Database name for Dolt (default: beads)
+
+	// ServerSocket is a Unix socket path for MariaDB; it takes precedence over ServerHost/ServerPort
+	ServerSocket string
+
+	// Schema is the Postgres schema holding the beads tables (default: public)
+	Schema string
+
+	// Connection pool options (MariaDB, Postgres; zero means backend default)
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// IsolationLevel of the transactions a MariaDB store begins (zero means the
+	// server default); see mariadb.Config.IsolationLevel for the tradeoffs
+	IsolationLevel sql.IsolationLevel
 }
 
 // New creates a storage backend based on the backend type.
 // For SQLite, path should be the full path to the .db file.
 // For Dolt, path should be the directory containing the Dolt database.
+// For memory, path is only recorded as the store's JSONL path and may be empty.
 func New(ctx context.Context, backend, path string) (storage.Storage, error) {
 	return NewWithOptions(ctx, backend, path, Options{})
 }
@@ -71,7 +88,7 @@ func NewWithOptions(ctx context.Context, backend, path string, opts Options) (st
 		if backend == configfile.BackendDolt {
 			return nil, fmt.Errorf("dolt backend is not registered; ensure the dolt storage package is imported")
 		}
-		return nil, fmt.Errorf("unknown storage backend: %s (supported: mariadb, sqlite, dolt)", backend)
+		return nil, fmt.Errorf("unknown storage backend: %s (supported: mariadb, postgres, sqlite, dolt, memory)", backend)
 	}
 }
 
@@ -110,6 +127,24 @@ func NewFromConfigWithOptions(ctx context.Context, beadsDir string, opts Options
 			opts.Database = cfg.GetMariaDBDatabase()
 		}
 		return NewWithOptions(ctx, backend, cfg.DatabasePath(beadsDir), opts)
+	case configfile.BackendPostgres:
+		// Merge Postgres server config into options (config provides defaults, opts can override)
+		if opts.ServerHost == "" {
+			opts.ServerHost = cfg.GetPostgresHost()
+		}
+		if opts.ServerPort == 0 {
+			opts.ServerPort = cfg.GetPostgresPort()
+		}
+		if opts.ServerUser == "" {
+			opts.ServerUser = cfg.GetPostgresUser()
+		}
+		if opts.Database == "" {
+			opts.Database = cfg.GetPostgresDatabase()
+		}
+		if opts.Schema == "" {
+			opts.Schema = cfg.GetPostgresSchema()
+		}
+		return NewWithOptions(ctx, backend, cfg.DatabasePath(beadsDir), opts)
 	case configfile.BackendDolt:
 		// Merge Dolt server config into options (config provides defaults, opts can override)
 		if opts.ServerHost == "" {
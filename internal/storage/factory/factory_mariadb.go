@@ -2,6 +2,8 @@ package factory
 
 import (
 	"context"
+	"fmt"
+	"os"
 
 	"github.com/steveyegge/beads/internal/configfile"
 	"github.com/steveyegge/beads/internal/storage"
@@ -10,16 +12,38 @@ import (
 
 func init() {
 	RegisterBackend(configfile.BackendMariaDB, func(ctx context.Context, path string, opts Options) (storage.Storage, error) {
-		store, err := mariadb.New(ctx, &mariadb.Config{
-			Host:     opts.ServerHost,
-			Port:     opts.ServerPort,
-			User:     opts.ServerUser,
-			Database: opts.Database,
-			ReadOnly: opts.ReadOnly,
-		})
+		cfg, err := mariadbConfig(opts)
+		if err != nil {
+			return nil, err
+		}
+		store, err := mariadb.New(ctx, cfg)
 		if err != nil {
 			return nil, err
 		}
 		return store, nil
 	})
 }
+
+// mariadbConfig builds the MariaDB config for opts. When BEADS_MARIADB_URL is
+// set, it supplies the connection settings in place of the server options.
+func mariadbConfig(opts Options) (*mariadb.Config, error) {
+	cfg := &mariadb.Config{
+		Host:     opts.ServerHost,
+		Port:     opts.ServerPort,
+		Socket:   opts.ServerSocket,
+		User:     opts.ServerUser,
+		Database: opts.Database,
+	}
+	if dsn := os.Getenv("BEADS_MARIADB_URL"); dsn != "" {
+		var err error
+		if cfg, err = mariadb.ConfigFromDSN(dsn); err != nil {
+			return nil, fmt.Errorf("failed to parse BEADS_MARIADB_URL: %w", err)
+		}
+	}
+	cfg.ReadOnly = opts.ReadOnly
+	cfg.MaxOpenConns = opts.MaxOpenConns
+	cfg.MaxIdleConns = opts.MaxIdleConns
+	cfg.ConnMaxLifetime = opts.ConnMaxLifetime
+	cfg.IsolationLevel = opts.IsolationLevel
+	return cfg, nil
+}
@@ -0,0 +1,15 @@
+package factory
+
+import (
+	"context"
+
+	"github.com/steveyegge/beads/internal/configfile"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/memory"
+)
+
+func init() {
+	RegisterBackend(configfile.BackendMemory, func(ctx context.Context, path string, opts Options) (storage.Storage, error) {
+		return memory.New(path), nil
+	})
+}
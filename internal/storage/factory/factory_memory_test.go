@@ -0,0 +1,20 @@
+package factory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/configfile"
+	"github.com/steveyegge/beads/internal/storage/memory"
+)
+
+func TestNewMemoryBackend(t *testing.T) {
+	store, err := New(context.Background(), configfile.BackendMemory, "issues.jsonl")
+	if err != nil {
+		t.Fatalf("New(memory) failed: %v", err)
+	}
+	defer store.Close()
+	if _, ok := store.(*memory.MemoryStorage); !ok {
+		t.Errorf("New(memory) = %T, want *memory.MemoryStorage", store)
+	}
+}
@@ -0,0 +1,30 @@
+package factory
+
+import (
+	"context"
+
+	"github.com/steveyegge/beads/internal/configfile"
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/postgres"
+)
+
+func init() {
+	RegisterBackend(configfile.BackendPostgres, func(ctx context.Context, path string, opts Options) (storage.Storage, error) {
+		store, err := postgres.New(ctx, &postgres.Config{
+			Host:     opts.ServerHost,
+			Port:     opts.ServerPort,
+			User:     opts.ServerUser,
+			Database: opts.Database,
+			Schema:   opts.Schema,
+			ReadOnly: opts.ReadOnly,
+
+			MaxOpenConns:    opts.MaxOpenConns,
+			MaxIdleConns:    opts.MaxIdleConns,
+			ConnMaxLifetime: opts.ConnMaxLifetime,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	})
+}
@@ -0,0 +1,598 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/grpc/storagepb"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Client implements storage.Storage by calling a Beads storage service over
+// gRPC. Errors the server tags as storage sentinels (such as
+// storage.ErrStoreClosed and storage.ErrVersionConflict) match them with
+// errors.Is; other errors are gRPC status errors.
+type Client struct {
+	conn   *gogrpc.ClientConn
+	rpc    storagepb.StorageClient
+	target string
+	owned  bool // Whether Close closes conn
+}
+
+var _ storage.Storage = (*Client)(nil)
+
+// Dial returns a client for the storage service at target. opts must include
+// transport credentials, e.g. insecure.NewCredentials() for a trusted network.
+func Dial(target string, opts ...gogrpc.DialOption) (*Client, error) {
+	conn, err := gogrpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to storage service at %s: %w", target, err)
+	}
+	return &Client{conn: conn, rpc: storagepb.NewStorageClient(conn), target: target, owned: true}, nil
+}
+
+// NewClient returns a client that uses conn. Close does not close conn.
+func NewClient(conn *gogrpc.ClientConn) *Client {
+	return &Client{conn: conn, rpc: storagepb.NewStorageClient(conn), target: conn.Target()}
+}
+
+// copyCreated overwrites each of issues with the server's copy of it after
+// method created them, so the caller sees the ID, timestamps and content
+// hash the store filled in, as it would with a local store.
+func copyCreated(method string, issues []*types.Issue, msgs []*storagepb.Issue) error {
+	if len(msgs) != len(issues) {
+		return fmt.Errorf("%s returned %d issues, want %d", method, len(msgs), len(issues))
+	}
+	for i, msg := range msgs {
+		if msg == nil {
+			return fmt.Errorf("%s returned no issue for %s", method, issues[i].ID)
+		}
+		*issues[i] = *fromIssue(msg)
+	}
+	return nil
+}
+
+// errNotRemote is returned by Client methods that cannot work over gRPC.
+func errNotRemote(method string) error {
+	return fmt.Errorf("%s is not supported over gRPC", method)
+}
+
+// RunInTransaction is not supported: a transaction cannot span RPCs.
+func (c *Client) RunInTransaction(ctx context.Context, fn func(tx storage.Transaction) error) error {
+	return errNotRemote("RunInTransaction")
+}
+
+// Close closes the connection, if the client was created by Dial.
+func (c *Client) Close() error {
+	if !c.owned {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// Path returns the target address of the service.
+func (c *Client) Path() string {
+	return c.target
+}
+
+// UnderlyingDB returns nil: the database is only reachable by the server.
+func (c *Client) UnderlyingDB() *sql.DB {
+	return nil
+}
+
+// UnderlyingConn is not supported: the database is only reachable by the server.
+func (c *Client) UnderlyingConn(ctx context.Context) (*sql.Conn, error) {
+	return nil, errNotRemote("UnderlyingConn")
+}
+
+// Capabilities reports no optional features: queries are planned by the
+// server, against its own backend.
+func (c *Client) Capabilities() storage.Capabilities {
+	return storage.Capabilities{}
+}
+
+func (c *Client) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	resp, err := c.rpc.CreateIssue(ctx, &storagepb.CreateIssueRequest{Issue: toIssue(issue), Actor: actor})
+	if err != nil {
+		return fromStatus(err)
+	}
+	return copyCreated("CreateIssue", []*types.Issue{issue}, []*storagepb.Issue{resp.GetIssue()})
+}
+
+func (c *Client) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	resp, err := c.rpc.CreateIssues(ctx, &storagepb.CreateIssuesRequest{Issues: toIssues(issues), Actor: actor})
+	if err != nil {
+		return fromStatus(err)
+	}
+	return copyCreated("CreateIssues", issues, resp.GetIssues())
+}
+
+func (c *Client) CreateIssuesWithFullOptions(ctx context.Context, issues []*types.Issue, actor string, opts storage.BatchCreateOptions) error {
+	resp, err := c.rpc.CreateIssuesWithFullOptions(ctx, &storagepb.CreateIssuesWithFullOptionsRequest{
+		Issues:               toIssues(issues),
+		Actor:                actor,
+		OrphanHandling:       string(opts.OrphanHandling),
+		SkipPrefixValidation: opts.SkipPrefixValidation,
+	})
+	if err != nil {
+		return fromStatus(err)
+	}
+	return copyCreated("CreateIssuesWithFullOptions", issues, resp.GetIssues())
+}
+
+func (c *Client) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	resp, err := c.rpc.GetIssue(ctx, &storagepb.GetIssueRequest{Id: id})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromIssue(resp.GetIssue()), nil
+}
+
+func (c *Client) GetIssueByExternalRef(ctx context.Context, externalRef string) (*types.Issue, error) {
+	resp, err := c.rpc.GetIssueByExternalRef(ctx, &storagepb.GetIssueByExternalRefRequest{ExternalRef: externalRef})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromIssue(resp.GetIssue()), nil
+}
+
+func (c *Client) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	msg, err := toIssueUpdates(updates)
+	if err != nil {
+		return err
+	}
+	_, err = c.rpc.UpdateIssue(ctx, &storagepb.UpdateIssueRequest{Id: id, Updates: msg, Actor: actor})
+	return fromStatus(err)
+}
+
+func (c *Client) ClaimIssue(ctx context.Context, id string, actor string) error {
+	_, err := c.rpc.ClaimIssue(ctx, &storagepb.ClaimIssueRequest{Id: id, Actor: actor})
+	return fromStatus(err)
+}
+
+func (c *Client) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
+	_, err := c.rpc.CloseIssue(ctx, &storagepb.CloseIssueRequest{Id: id, Reason: reason, Actor: actor, Session: session})
+	return fromStatus(err)
+}
+
+func (c *Client) DeleteIssue(ctx context.Context, id string) error {
+	_, err := c.rpc.DeleteIssue(ctx, &storagepb.DeleteIssueRequest{Id: id})
+	return fromStatus(err)
+}
+
+func (c *Client) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	resp, err := c.rpc.SearchIssues(ctx, &storagepb.SearchIssuesRequest{Query: query, Filter: toIssueFilter(filter)})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromIssues(resp.GetIssues()), nil
+}
+
+func (c *Client) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	_, err := c.rpc.AddDependency(ctx, &storagepb.AddDependencyRequest{Dependency: toDependency(dep), Actor: actor})
+	return fromStatus(err)
+}
+
+func (c *Client) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	_, err := c.rpc.RemoveDependency(ctx, &storagepb.RemoveDependencyRequest{IssueId: issueID, DependsOnId: dependsOnID, Actor: actor})
+	return fromStatus(err)
+}
+
+func (c *Client) GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	resp, err := c.rpc.GetDependencies(ctx, &storagepb.GetDependenciesRequest{IssueId: issueID})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromIssues(resp.GetIssues()), nil
+}
+
+func (c *Client) GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	resp, err := c.rpc.GetDependents(ctx, &storagepb.GetDependentsRequest{IssueId: issueID})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromIssues(resp.GetIssues()), nil
+}
+
+func (c *Client) GetDependenciesWithMetadata(ctx context.Context, issueID string) ([]*types.IssueWithDependencyMetadata, error) {
+	resp, err := c.rpc.GetDependenciesWithMetadata(ctx, &storagepb.GetDependenciesWithMetadataRequest{IssueId: issueID})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromList(resp.GetIssues(), fromIssueWithDependencyMetadata), nil
+}
+
+func (c *Client) GetDependentsWithMetadata(ctx context.Context, issueID string) ([]*types.IssueWithDependencyMetadata, error) {
+	resp, err := c.rpc.GetDependentsWithMetadata(ctx, &storagepb.GetDependentsWithMetadataRequest{IssueId: issueID})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromList(resp.GetIssues(), fromIssueWithDependencyMetadata), nil
+}
+
+func (c *Client) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
+	resp, err := c.rpc.GetDependencyRecords(ctx, &storagepb.GetDependencyRecordsRequest{IssueId: issueID})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromList(resp.GetDependencies(), fromDependency), nil
+}
+
+// fromDependencyMap converts dependency records keyed by issue ID from
+// their wire form.
+func fromDependencyMap(lists map[string]*storagepb.DependencyList) map[string][]*types.Dependency {
+	records := make(map[string][]*types.Dependency, len(lists))
+	for issueID, list := range lists {
+		records[issueID] = fromList(list.GetDependencies(), fromDependency)
+	}
+	return records
+}
+
+func (c *Client) GetAllDependencyRecords(ctx context.Context) (map[string][]*types.Dependency, error) {
+	resp, err := c.rpc.GetAllDependencyRecords(ctx, &storagepb.GetAllDependencyRecordsRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromDependencyMap(resp.GetDependencies()), nil
+}
+
+func (c *Client) GetDependencyRecordsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Dependency, error) {
+	resp, err := c.rpc.GetDependencyRecordsForIssues(ctx, &storagepb.GetDependencyRecordsForIssuesRequest{IssueIds: issueIDs})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromDependencyMap(resp.GetDependencies()), nil
+}
+
+func (c *Client) GetDependencyCounts(ctx context.Context, issueIDs []string) (map[string]*types.DependencyCounts, error) {
+	resp, err := c.rpc.GetDependencyCounts(ctx, &storagepb.GetDependencyCountsRequest{IssueIds: issueIDs})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	counts := make(map[string]*types.DependencyCounts, len(resp.GetCounts()))
+	for issueID, c := range resp.GetCounts() {
+		counts[issueID] = &types.DependencyCounts{DependencyCount: int(c.GetDependencyCount()), DependentCount: int(c.GetDependentCount())}
+	}
+	return counts, nil
+}
+
+func (c *Client) GetDependencyTree(ctx context.Context, issueID string, maxDepth int, showAllPaths bool, reverse bool) ([]*types.TreeNode, error) {
+	resp, err := c.rpc.GetDependencyTree(ctx, &storagepb.GetDependencyTreeRequest{
+		IssueId:      issueID,
+		MaxDepth:     int32(maxDepth),
+		ShowAllPaths: showAllPaths,
+		Reverse:      reverse,
+	})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromList(resp.GetNodes(), fromTreeNode), nil
+}
+
+func (c *Client) DetectCycles(ctx context.Context) ([][]*types.Issue, error) {
+	resp, err := c.rpc.DetectCycles(ctx, &storagepb.DetectCyclesRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	cycles := make([][]*types.Issue, len(resp.GetCycles()))
+	for i, cycle := range resp.GetCycles() {
+		cycles[i] = fromIssues(cycle.GetIssues())
+	}
+	return cycles, nil
+}
+
+func (c *Client) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	_, err := c.rpc.AddLabel(ctx, &storagepb.AddLabelRequest{IssueId: issueID, Label: label, Actor: actor})
+	return fromStatus(err)
+}
+
+func (c *Client) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	_, err := c.rpc.RemoveLabel(ctx, &storagepb.RemoveLabelRequest{IssueId: issueID, Label: label, Actor: actor})
+	return fromStatus(err)
+}
+
+func (c *Client) GetLabels(ctx context.Context, issueID string) ([]string, error) {
+	resp, err := c.rpc.GetLabels(ctx, &storagepb.GetLabelsRequest{IssueId: issueID})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return resp.GetLabels(), nil
+}
+
+func (c *Client) GetLabelsForIssues(ctx context.Context, issueIDs []string) (map[string][]string, error) {
+	resp, err := c.rpc.GetLabelsForIssues(ctx, &storagepb.GetLabelsForIssuesRequest{IssueIds: issueIDs})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	labels := make(map[string][]string, len(resp.GetLabels()))
+	for issueID, list := range resp.GetLabels() {
+		labels[issueID] = list.GetValues()
+	}
+	return labels, nil
+}
+
+func (c *Client) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
+	resp, err := c.rpc.GetIssuesByLabel(ctx, &storagepb.GetIssuesByLabelRequest{Label: label})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromIssues(resp.GetIssues()), nil
+}
+
+func (c *Client) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	resp, err := c.rpc.GetReadyWork(ctx, &storagepb.GetReadyWorkRequest{Filter: toWorkFilter(filter)})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromIssues(resp.GetIssues()), nil
+}
+
+func (c *Client) GetBlockedIssues(ctx context.Context, filter types.WorkFilter) ([]*types.BlockedIssue, error) {
+	resp, err := c.rpc.GetBlockedIssues(ctx, &storagepb.GetBlockedIssuesRequest{Filter: toWorkFilter(filter)})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromList(resp.GetIssues(), fromBlockedIssue), nil
+}
+
+func (c *Client) IsBlocked(ctx context.Context, issueID string) (bool, []string, error) {
+	resp, err := c.rpc.IsBlocked(ctx, &storagepb.IsBlockedRequest{IssueId: issueID})
+	if err != nil {
+		return false, nil, fromStatus(err)
+	}
+	return resp.GetBlocked(), resp.GetBlockers(), nil
+}
+
+func (c *Client) GetEpicsEligibleForClosure(ctx context.Context) ([]*types.EpicStatus, error) {
+	resp, err := c.rpc.GetEpicsEligibleForClosure(ctx, &storagepb.GetEpicsEligibleForClosureRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromList(resp.GetEpics(), fromEpicStatus), nil
+}
+
+func (c *Client) GetStaleIssues(ctx context.Context, filter types.StaleFilter) ([]*types.Issue, error) {
+	resp, err := c.rpc.GetStaleIssues(ctx, &storagepb.GetStaleIssuesRequest{Filter: toStaleFilter(filter)})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromIssues(resp.GetIssues()), nil
+}
+
+func (c *Client) GetNewlyUnblockedByClose(ctx context.Context, closedIssueID string) ([]*types.Issue, error) {
+	resp, err := c.rpc.GetNewlyUnblockedByClose(ctx, &storagepb.GetNewlyUnblockedByCloseRequest{ClosedIssueId: closedIssueID})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromIssues(resp.GetIssues()), nil
+}
+
+func (c *Client) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	_, err := c.rpc.AddComment(ctx, &storagepb.AddCommentRequest{IssueId: issueID, Actor: actor, Comment: comment})
+	return fromStatus(err)
+}
+
+func (c *Client) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
+	resp, err := c.rpc.GetEvents(ctx, &storagepb.GetEventsRequest{IssueId: issueID, Limit: int32(limit)})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromList(resp.GetEvents(), fromEvent), nil
+}
+
+func (c *Client) GetAllEventsSince(ctx context.Context, sinceID int64) ([]*types.Event, error) {
+	resp, err := c.rpc.GetAllEventsSince(ctx, &storagepb.GetAllEventsSinceRequest{SinceId: sinceID})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromList(resp.GetEvents(), fromEvent), nil
+}
+
+func (c *Client) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
+	resp, err := c.rpc.AddIssueComment(ctx, &storagepb.AddIssueCommentRequest{IssueId: issueID, Author: author, Text: text})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromComment(resp.GetComment()), nil
+}
+
+func (c *Client) ImportIssueComment(ctx context.Context, issueID, author, text string, createdAt time.Time) (*types.Comment, error) {
+	resp, err := c.rpc.ImportIssueComment(ctx, &storagepb.ImportIssueCommentRequest{
+		IssueId:   issueID,
+		Author:    author,
+		Text:      text,
+		CreatedAt: timestamppb.New(createdAt),
+	})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromComment(resp.GetComment()), nil
+}
+
+func (c *Client) GetIssueComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
+	resp, err := c.rpc.GetIssueComments(ctx, &storagepb.GetIssueCommentsRequest{IssueId: issueID})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromList(resp.GetComments(), fromComment), nil
+}
+
+func (c *Client) GetCommentsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Comment, error) {
+	resp, err := c.rpc.GetCommentsForIssues(ctx, &storagepb.GetCommentsForIssuesRequest{IssueIds: issueIDs})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	comments := make(map[string][]*types.Comment, len(resp.GetComments()))
+	for issueID, list := range resp.GetComments() {
+		comments[issueID] = fromList(list.GetComments(), fromComment)
+	}
+	return comments, nil
+}
+
+func (c *Client) GetCommentCounts(ctx context.Context, issueIDs []string) (map[string]int, error) {
+	resp, err := c.rpc.GetCommentCounts(ctx, &storagepb.GetCommentCountsRequest{IssueIds: issueIDs})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	counts := make(map[string]int, len(resp.GetCounts()))
+	for issueID, n := range resp.GetCounts() {
+		counts[issueID] = int(n)
+	}
+	return counts, nil
+}
+
+func (c *Client) GetStatistics(ctx context.Context) (*types.Statistics, error) {
+	resp, err := c.rpc.GetStatistics(ctx, &storagepb.GetStatisticsRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromStatistics(resp.GetStatistics()), nil
+}
+
+func (c *Client) GetMoleculeProgress(ctx context.Context, moleculeID string) (*types.MoleculeProgressStats, error) {
+	resp, err := c.rpc.GetMoleculeProgress(ctx, &storagepb.GetMoleculeProgressRequest{MoleculeId: moleculeID})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromMoleculeProgress(resp.GetProgress()), nil
+}
+
+func (c *Client) GetDirtyIssues(ctx context.Context) ([]string, error) {
+	resp, err := c.rpc.GetDirtyIssues(ctx, &storagepb.GetDirtyIssuesRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return resp.GetIssueIds(), nil
+}
+
+func (c *Client) GetDirtyIssueHash(ctx context.Context, issueID string) (string, error) {
+	resp, err := c.rpc.GetDirtyIssueHash(ctx, &storagepb.GetDirtyIssueHashRequest{IssueId: issueID})
+	if err != nil {
+		return "", fromStatus(err)
+	}
+	return resp.GetContentHash(), nil
+}
+
+func (c *Client) ClearDirtyIssuesByID(ctx context.Context, issueIDs []string) error {
+	_, err := c.rpc.ClearDirtyIssuesByID(ctx, &storagepb.ClearDirtyIssuesByIDRequest{IssueIds: issueIDs})
+	return fromStatus(err)
+}
+
+func (c *Client) GetExportHash(ctx context.Context, issueID string) (string, error) {
+	resp, err := c.rpc.GetExportHash(ctx, &storagepb.GetExportHashRequest{IssueId: issueID})
+	if err != nil {
+		return "", fromStatus(err)
+	}
+	return resp.GetContentHash(), nil
+}
+
+func (c *Client) SetExportHash(ctx context.Context, issueID, contentHash string) error {
+	_, err := c.rpc.SetExportHash(ctx, &storagepb.SetExportHashRequest{IssueId: issueID, ContentHash: contentHash})
+	return fromStatus(err)
+}
+
+func (c *Client) ClearAllExportHashes(ctx context.Context) error {
+	_, err := c.rpc.ClearAllExportHashes(ctx, &storagepb.ClearAllExportHashesRequest{})
+	return fromStatus(err)
+}
+
+func (c *Client) GetJSONLFileHash(ctx context.Context) (string, error) {
+	resp, err := c.rpc.GetJSONLFileHash(ctx, &storagepb.GetJSONLFileHashRequest{})
+	if err != nil {
+		return "", fromStatus(err)
+	}
+	return resp.GetFileHash(), nil
+}
+
+func (c *Client) SetJSONLFileHash(ctx context.Context, fileHash string) error {
+	_, err := c.rpc.SetJSONLFileHash(ctx, &storagepb.SetJSONLFileHashRequest{FileHash: fileHash})
+	return fromStatus(err)
+}
+
+func (c *Client) GetNextChildID(ctx context.Context, parentID string) (string, error) {
+	resp, err := c.rpc.GetNextChildID(ctx, &storagepb.GetNextChildIDRequest{ParentId: parentID})
+	if err != nil {
+		return "", fromStatus(err)
+	}
+	return resp.GetId(), nil
+}
+
+func (c *Client) SetConfig(ctx context.Context, key, value string) error {
+	_, err := c.rpc.SetConfig(ctx, &storagepb.SetConfigRequest{Key: key, Value: value})
+	return fromStatus(err)
+}
+
+func (c *Client) GetConfig(ctx context.Context, key string) (string, error) {
+	resp, err := c.rpc.GetConfig(ctx, &storagepb.GetConfigRequest{Key: key})
+	if err != nil {
+		return "", fromStatus(err)
+	}
+	return resp.GetValue(), nil
+}
+
+func (c *Client) GetAllConfig(ctx context.Context) (map[string]string, error) {
+	resp, err := c.rpc.GetAllConfig(ctx, &storagepb.GetAllConfigRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	config := resp.GetConfig()
+	if config == nil {
+		config = map[string]string{}
+	}
+	return config, nil
+}
+
+func (c *Client) DeleteConfig(ctx context.Context, key string) error {
+	_, err := c.rpc.DeleteConfig(ctx, &storagepb.DeleteConfigRequest{Key: key})
+	return fromStatus(err)
+}
+
+func (c *Client) GetCustomStatuses(ctx context.Context) ([]string, error) {
+	resp, err := c.rpc.GetCustomStatuses(ctx, &storagepb.GetCustomStatusesRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return resp.GetStatuses(), nil
+}
+
+func (c *Client) GetCustomTypes(ctx context.Context) ([]string, error) {
+	resp, err := c.rpc.GetCustomTypes(ctx, &storagepb.GetCustomTypesRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return resp.GetTypes(), nil
+}
+
+func (c *Client) SetMetadata(ctx context.Context, key, value string) error {
+	_, err := c.rpc.SetMetadata(ctx, &storagepb.SetMetadataRequest{Key: key, Value: value})
+	return fromStatus(err)
+}
+
+func (c *Client) GetMetadata(ctx context.Context, key string) (string, error) {
+	resp, err := c.rpc.GetMetadata(ctx, &storagepb.GetMetadataRequest{Key: key})
+	if err != nil {
+		return "", fromStatus(err)
+	}
+	return resp.GetValue(), nil
+}
+
+func (c *Client) UpdateIssueID(ctx context.Context, oldID, newID string, issue *types.Issue, actor string) error {
+	_, err := c.rpc.UpdateIssueID(ctx, &storagepb.UpdateIssueIDRequest{OldId: oldID, NewId: newID, Issue: toIssue(issue), Actor: actor})
+	return fromStatus(err)
+}
+
+func (c *Client) RenameDependencyPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
+	_, err := c.rpc.RenameDependencyPrefix(ctx, &storagepb.RenameDependencyPrefixRequest{OldPrefix: oldPrefix, NewPrefix: newPrefix})
+	return fromStatus(err)
+}
+
+func (c *Client) RenameCounterPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
+	_, err := c.rpc.RenameCounterPrefix(ctx, &storagepb.RenameCounterPrefixRequest{OldPrefix: oldPrefix, NewPrefix: newPrefix})
+	return fromStatus(err)
+}
@@ -0,0 +1,309 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/memory"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// startServer serves store in-process and returns a client connected to it.
+func startServer(t *testing.T, store storage.Storage) *Client {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := NewServer(store)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	client, err := Dial("passthrough:///bufnet",
+		gogrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		gogrpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestClientRoundTrip(t *testing.T) {
+	ctx := testContext(t)
+	backend := memory.New("")
+	client := startServer(t, backend)
+
+	if err := client.SetConfig(ctx, "issue_prefix", "test"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	for _, id := range []string{"test-a", "test-b"} {
+		issue := &types.Issue{ID: id, Title: "Issue " + id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := client.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	if err := client.AddDependency(ctx, &types.Dependency{IssueID: "test-a", DependsOnID: "test-b", Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := client.AddLabel(ctx, "test-a", "backend", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := client.UpdateIssue(ctx, "test-a", map[string]interface{}{"title": "Renamed"}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	// Reads through the client match the backend
+	got, err := client.GetIssue(ctx, "test-a")
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got == nil || got.Title != "Renamed" || !reflect.DeepEqual(got.Labels, []string{"backend"}) {
+		t.Errorf("GetIssue = %+v, want renamed issue labeled backend", got)
+	}
+	if missing, err := client.GetIssue(ctx, "test-missing"); err != nil || missing != nil {
+		t.Errorf("GetIssue(missing) = %v, %v; want nil, nil", missing, err)
+	}
+	blocked, blockers, err := client.IsBlocked(ctx, "test-a")
+	if err != nil || !blocked || !reflect.DeepEqual(blockers, []string{"test-b"}) {
+		t.Errorf("IsBlocked = %v, %v, %v; want true, [test-b], nil", blocked, blockers, err)
+	}
+	found, err := client.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil || len(found) != 2 {
+		t.Errorf("SearchIssues = %d issues, %v; want 2", len(found), err)
+	}
+	config, err := client.GetAllConfig(ctx)
+	if err != nil || config["issue_prefix"] != "test" {
+		t.Errorf("GetAllConfig = %v, %v", config, err)
+	}
+
+	if client.UnderlyingDB() != nil {
+		t.Error("UnderlyingDB should be nil over gRPC")
+	}
+	if err := client.RunInTransaction(ctx, func(storage.Transaction) error { return nil }); err == nil {
+		t.Error("RunInTransaction should fail over gRPC")
+	}
+}
+
+// TestClientCreateReturnsStoredIssues checks that creating issues through
+// the client fills in the fields the server's store sets, as a local store
+// would.
+func TestClientCreateReturnsStoredIssues(t *testing.T) {
+	ctx := testContext(t)
+	backend, err := sqlite.New(ctx, filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatalf("failed to open sqlite store: %v", err)
+	}
+	t.Cleanup(func() { _ = backend.Close() })
+	client := startServer(t, backend)
+	if err := client.SetConfig(ctx, "issue_prefix", "test"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	issue := &types.Issue{Title: "Generated", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := client.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	stored, err := backend.GetIssue(ctx, issue.ID)
+	if err != nil || stored == nil {
+		t.Fatalf("GetIssue(%q) = %v, %v; want the created issue", issue.ID, stored, err)
+	}
+	if issue.ContentHash == "" || issue.ContentHash != stored.ContentHash {
+		t.Errorf("ContentHash = %q, want stored %q", issue.ContentHash, stored.ContentHash)
+	}
+	if issue.CreatedAt.IsZero() || !issue.CreatedAt.Equal(stored.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want stored %v", issue.CreatedAt, stored.CreatedAt)
+	}
+
+	batch := []*types.Issue{
+		{Title: "First", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug},
+		{Title: "Second", Status: types.StatusOpen, Priority: 3, IssueType: types.TypeTask},
+	}
+	if err := client.CreateIssues(ctx, batch, "tester"); err != nil {
+		t.Fatalf("CreateIssues failed: %v", err)
+	}
+	for _, issue := range batch {
+		if issue.ID == "" || issue.ContentHash == "" || issue.CreatedAt.IsZero() {
+			t.Errorf("CreateIssues left %q without server fields: %+v", issue.Title, issue)
+			continue
+		}
+		if got, err := client.GetIssue(ctx, issue.ID); err != nil || got == nil || got.Title != issue.Title {
+			t.Errorf("GetIssue(%s) = %v, %v; want %q", issue.ID, got, err, issue.Title)
+		}
+	}
+
+	opts := storage.BatchCreateOptions{OrphanHandling: storage.OrphanAllow}
+	full := []*types.Issue{{Title: "Third", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}}
+	if err := client.CreateIssuesWithFullOptions(ctx, full, "tester", opts); err != nil {
+		t.Fatalf("CreateIssuesWithFullOptions failed: %v", err)
+	}
+	if full[0].ID == "" || full[0].ContentHash == "" {
+		t.Errorf("CreateIssuesWithFullOptions left the issue without server fields: %+v", full[0])
+	}
+}
+
+// TestClientUpdateIssueTypes checks that update values keep the Go types the
+// store expects over the wire, whichever of the accepted forms the caller
+// used.
+func TestClientUpdateIssueTypes(t *testing.T) {
+	ctx := testContext(t)
+	backend, err := sqlite.New(ctx, filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatalf("failed to open sqlite store: %v", err)
+	}
+	t.Cleanup(func() { _ = backend.Close() })
+	client := startServer(t, backend)
+	if err := client.SetConfig(ctx, "issue_prefix", "test"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	ref := "gh-7"
+	issue := &types.Issue{ID: "test-a", Title: "Typed", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, ExternalRef: &ref}
+	if err := client.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	due := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	deferUntil := due.Add(-48 * time.Hour)
+	minutes := 90
+	err = client.UpdateIssue(ctx, "test-a", map[string]interface{}{
+		"priority":          1,
+		"status":            types.StatusInProgress,
+		"due_at":            due,
+		"defer_until":       &deferUntil,
+		"estimated_minutes": &minutes,
+		"external_ref":      nil,
+		"pinned":            true,
+		"waiters":           []string{"alice@example.com"},
+		"metadata":          json.RawMessage(`{"files":["a.go"]}`),
+	}, "tester")
+	if err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	got, err := backend.GetIssue(ctx, "test-a")
+	if err != nil || got == nil {
+		t.Fatalf("GetIssue = %v, %v; want the issue", got, err)
+	}
+	if got.Priority != 1 || got.Status != types.StatusInProgress || !got.Pinned {
+		t.Errorf("priority, status, pinned = %d, %q, %v; want 1, in_progress, true", got.Priority, got.Status, got.Pinned)
+	}
+	if got.DueAt == nil || !got.DueAt.Equal(due) {
+		t.Errorf("due_at = %v, want %v", got.DueAt, due)
+	}
+	if got.DeferUntil == nil || !got.DeferUntil.Equal(deferUntil) {
+		t.Errorf("defer_until = %v, want %v", got.DeferUntil, deferUntil)
+	}
+	if got.EstimatedMinutes == nil || *got.EstimatedMinutes != 90 {
+		t.Errorf("estimated_minutes = %v, want 90", got.EstimatedMinutes)
+	}
+	if got.ExternalRef != nil {
+		t.Errorf("external_ref = %q, want it cleared", *got.ExternalRef)
+	}
+	if !reflect.DeepEqual(got.Waiters, []string{"alice@example.com"}) {
+		t.Errorf("waiters = %v, want [alice@example.com]", got.Waiters)
+	}
+	if string(got.Metadata) != `{"files":["a.go"]}` {
+		t.Errorf("metadata = %s, want the update", got.Metadata)
+	}
+
+	// Reads through the client see the same values
+	remote, err := client.GetIssue(ctx, "test-a")
+	if err != nil || remote == nil {
+		t.Fatalf("client GetIssue = %v, %v; want the issue", remote, err)
+	}
+	if remote.DueAt == nil || !remote.DueAt.Equal(due) || remote.ContentHash != got.ContentHash || string(remote.Metadata) != string(got.Metadata) {
+		t.Errorf("client GetIssue = %+v, want it to match the store's %+v", remote, got)
+	}
+
+	if err := client.UpdateIssue(ctx, "test-a", map[string]interface{}{"no_such_field": "x"}, "tester"); err == nil {
+		t.Error("UpdateIssue with an unknown field should fail")
+	}
+	if err := client.UpdateIssue(ctx, "test-a", map[string]interface{}{"priority": "high"}, "tester"); err == nil {
+		t.Error("UpdateIssue with a non-integer priority should fail")
+	}
+}
+
+// panickingStorage panics in GetIssue.
+type panickingStorage struct {
+	storage.Storage
+}
+
+func (p *panickingStorage) GetIssue(context.Context, string) (*types.Issue, error) {
+	panic("interface conversion")
+}
+
+func TestServerRecoversFromPanics(t *testing.T) {
+	ctx := testContext(t)
+	client := startServer(t, &panickingStorage{Storage: memory.New("")})
+
+	if _, err := client.GetIssue(ctx, "test-a"); status.Code(err) != codes.Internal {
+		t.Fatalf("GetIssue error = %v, want an Internal status", err)
+	}
+	// The server is still up after the panic
+	if err := client.SetConfig(ctx, "issue_prefix", "test"); err != nil {
+		t.Errorf("SetConfig after a panicking call failed: %v", err)
+	}
+}
+
+// failingStorage returns err from UpdateIssue and ClaimIssue.
+type failingStorage struct {
+	storage.Storage
+	err error
+}
+
+func (f *failingStorage) UpdateIssue(context.Context, string, map[string]interface{}, string) error {
+	return f.err
+}
+
+func (f *failingStorage) ClaimIssue(context.Context, string, string) error {
+	return f.err
+}
+
+func TestClientErrorMapping(t *testing.T) {
+	ctx := testContext(t)
+	tests := []struct {
+		name     string
+		err      error
+		sentinel error
+	}{
+		{"store closed", storage.ErrStoreClosed, storage.ErrStoreClosed},
+		{"version conflict", fmt.Errorf("%w: issue test-a is at version 3, not 2", storage.ErrVersionConflict), storage.ErrVersionConflict},
+		{"already claimed", fmt.Errorf("%w by alice", storage.ErrAlreadyClaimed), storage.ErrAlreadyClaimed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := startServer(t, &failingStorage{Storage: memory.New(""), err: tt.err})
+			err := client.UpdateIssue(ctx, "test-a", map[string]interface{}{"title": "x"}, "tester")
+			if !errors.Is(err, tt.sentinel) {
+				t.Fatalf("UpdateIssue error = %v, want it to match %v", err, tt.sentinel)
+			}
+			if err.Error() != tt.err.Error() {
+				t.Errorf("error message = %q, want %q", err.Error(), tt.err.Error())
+			}
+		})
+	}
+
+	client := startServer(t, &failingStorage{Storage: memory.New(""), err: errors.New("disk on fire")})
+	err := client.ClaimIssue(ctx, "test-a", "tester")
+	if status.Code(err) != codes.Unknown || errors.Is(err, storage.ErrStoreClosed) {
+		t.Errorf("ClaimIssue error = %v, want a plain Unknown status", err)
+	}
+}
@@ -0,0 +1,31 @@
+package grpc
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/storage/storagetest"
+)
+
+// newConformanceStore returns a client for an empty SQLite store served
+// in-process, for the storagetest suites.
+func newConformanceStore(t *testing.T) storage.Storage {
+	backend, err := sqlite.New(context.Background(), filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatalf("failed to create SQLite store: %v", err)
+	}
+	t.Cleanup(func() { _ = backend.Close() })
+	return startServer(t, backend)
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.RunConformance(t, newConformanceStore)
+}
+
+func TestDependencyValidation(t *testing.T) {
+	storagetest.RunDependencyValidation(t, newConformanceStore)
+	storagetest.RunMissingTargetValidation(t, newConformanceStore)
+}
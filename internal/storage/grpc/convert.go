@@ -0,0 +1,856 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/steveyegge/beads/internal/storage/grpc/storagepb"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// toTimestamp converts t to its wire form. A zero time is left unset.
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// fromTimestamp converts a timestamp from its wire form. An unset timestamp
+// gives a zero time.
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// toTimestampPtr is toTimestamp for an optional time. Only a nil time is
+// left unset.
+func toTimestampPtr(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// fromTimestampPtr is fromTimestamp for an optional time.
+func fromTimestampPtr(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// toInt32Ptr converts an optional int to its wire form.
+func toInt32Ptr(n *int) *int32 {
+	if n == nil {
+		return nil
+	}
+	v := int32(*n)
+	return &v
+}
+
+// fromInt32Ptr converts an optional int from its wire form.
+func fromInt32Ptr(n *int32) *int {
+	if n == nil {
+		return nil
+	}
+	v := int(*n)
+	return &v
+}
+
+// toStringPtr converts an optional value of a named string type to its wire
+// form.
+func toStringPtr[T ~string](s *T) *string {
+	if s == nil {
+		return nil
+	}
+	v := string(*s)
+	return &v
+}
+
+// fromStringPtr converts an optional value of a named string type from its
+// wire form.
+func fromStringPtr[T ~string](s *string) *T {
+	if s == nil {
+		return nil
+	}
+	v := T(*s)
+	return &v
+}
+
+// toStrings converts values of a named string type to their wire form.
+func toStrings[T ~string](values []T) []string {
+	if values == nil {
+		return nil
+	}
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = string(v)
+	}
+	return s
+}
+
+// fromStrings converts values of a named string type from their wire form.
+func fromStrings[T ~string](s []string) []T {
+	if s == nil {
+		return nil
+	}
+	values := make([]T, len(s))
+	for i, v := range s {
+		values[i] = T(v)
+	}
+	return values
+}
+
+// toList converts each of values with convert.
+func toList[T, M any](values []T, convert func(T) M) []M {
+	msgs := make([]M, len(values))
+	for i, v := range values {
+		msgs[i] = convert(v)
+	}
+	return msgs
+}
+
+// fromList converts each of msgs with convert. The result is nil only if
+// msgs is.
+func fromList[M, T any](msgs []M, convert func(M) T) []T {
+	if msgs == nil {
+		return nil
+	}
+	values := make([]T, len(msgs))
+	for i, msg := range msgs {
+		values[i] = convert(msg)
+	}
+	return values
+}
+
+// toIssue converts issue to its wire form. A nil issue stays nil.
+func toIssue(issue *types.Issue) *storagepb.Issue {
+	if issue == nil {
+		return nil
+	}
+	msg := &storagepb.Issue{
+		Id:                 issue.ID,
+		ContentHash:        issue.ContentHash,
+		Title:              issue.Title,
+		Description:        issue.Description,
+		Design:             issue.Design,
+		AcceptanceCriteria: issue.AcceptanceCriteria,
+		Notes:              issue.Notes,
+		SpecId:             issue.SpecID,
+		Status:             string(issue.Status),
+		Priority:           int32(issue.Priority),
+		IssueType:          string(issue.IssueType),
+		Assignee:           issue.Assignee,
+		Owner:              issue.Owner,
+		EstimatedMinutes:   toInt32Ptr(issue.EstimatedMinutes),
+		CreatedAt:          toTimestamp(issue.CreatedAt),
+		CreatedBy:          issue.CreatedBy,
+		UpdatedAt:          toTimestamp(issue.UpdatedAt),
+		ClosedAt:           toTimestampPtr(issue.ClosedAt),
+		CloseReason:        issue.CloseReason,
+		ClosedBySession:    issue.ClosedBySession,
+		DueAt:              toTimestampPtr(issue.DueAt),
+		DeferUntil:         toTimestampPtr(issue.DeferUntil),
+		ExternalRef:        issue.ExternalRef,
+		SourceSystem:       issue.SourceSystem,
+		Metadata:           string(issue.Metadata),
+		CompactionLevel:    int32(issue.CompactionLevel),
+		CompactedAt:        toTimestampPtr(issue.CompactedAt),
+		CompactedAtCommit:  issue.CompactedAtCommit,
+		OriginalSize:       int64(issue.OriginalSize),
+		SourceRepo:         issue.SourceRepo,
+		IdPrefix:           issue.IDPrefix,
+		PrefixOverride:     issue.PrefixOverride,
+		Labels:             issue.Labels,
+		Dependencies:       toList(issue.Dependencies, toDependency),
+		Comments:           toList(issue.Comments, toComment),
+		DeletedAt:          toTimestampPtr(issue.DeletedAt),
+		DeletedBy:          issue.DeletedBy,
+		DeleteReason:       issue.DeleteReason,
+		OriginalType:       issue.OriginalType,
+		Sender:             issue.Sender,
+		Ephemeral:          issue.Ephemeral,
+		WispType:           string(issue.WispType),
+		Pinned:             issue.Pinned,
+		IsTemplate:         issue.IsTemplate,
+		Creator:            toEntityRef(issue.Creator),
+		QualityScore:       issue.QualityScore,
+		Crystallizes:       issue.Crystallizes,
+		AwaitType:          issue.AwaitType,
+		AwaitId:            issue.AwaitID,
+		Waiters:            issue.Waiters,
+		Holder:             issue.Holder,
+		SourceFormula:      issue.SourceFormula,
+		SourceLocation:     issue.SourceLocation,
+		HookBead:           issue.HookBead,
+		RoleBead:           issue.RoleBead,
+		AgentState:         string(issue.AgentState),
+		LastActivity:       toTimestampPtr(issue.LastActivity),
+		RoleType:           issue.RoleType,
+		Rig:                issue.Rig,
+		MolType:            string(issue.MolType),
+		WorkType:           string(issue.WorkType),
+		EventKind:          issue.EventKind,
+		Actor:              issue.Actor,
+		Target:             issue.Target,
+		Payload:            issue.Payload,
+	}
+	for _, b := range issue.BondedFrom {
+		msg.BondedFrom = append(msg.BondedFrom, &storagepb.BondRef{SourceId: b.SourceID, BondType: b.BondType, BondPoint: b.BondPoint})
+	}
+	for _, v := range issue.Validations {
+		msg.Validations = append(msg.Validations, &storagepb.Validation{
+			Validator: toEntityRef(v.Validator),
+			Outcome:   v.Outcome,
+			Timestamp: toTimestamp(v.Timestamp),
+			Score:     v.Score,
+		})
+	}
+	if issue.Timeout != 0 {
+		msg.Timeout = durationpb.New(issue.Timeout)
+	}
+	return msg
+}
+
+// fromIssue converts an issue from its wire form. A nil message gives a nil
+// issue.
+func fromIssue(msg *storagepb.Issue) *types.Issue {
+	if msg == nil {
+		return nil
+	}
+	issue := &types.Issue{
+		ID:                 msg.GetId(),
+		ContentHash:        msg.GetContentHash(),
+		Title:              msg.GetTitle(),
+		Description:        msg.GetDescription(),
+		Design:             msg.GetDesign(),
+		AcceptanceCriteria: msg.GetAcceptanceCriteria(),
+		Notes:              msg.GetNotes(),
+		SpecID:             msg.GetSpecId(),
+		Status:             types.Status(msg.GetStatus()),
+		Priority:           int(msg.GetPriority()),
+		IssueType:          types.IssueType(msg.GetIssueType()),
+		Assignee:           msg.GetAssignee(),
+		Owner:              msg.GetOwner(),
+		EstimatedMinutes:   fromInt32Ptr(msg.EstimatedMinutes),
+		CreatedAt:          fromTimestamp(msg.GetCreatedAt()),
+		CreatedBy:          msg.GetCreatedBy(),
+		UpdatedAt:          fromTimestamp(msg.GetUpdatedAt()),
+		ClosedAt:           fromTimestampPtr(msg.GetClosedAt()),
+		CloseReason:        msg.GetCloseReason(),
+		ClosedBySession:    msg.GetClosedBySession(),
+		DueAt:              fromTimestampPtr(msg.GetDueAt()),
+		DeferUntil:         fromTimestampPtr(msg.GetDeferUntil()),
+		ExternalRef:        msg.ExternalRef,
+		SourceSystem:       msg.GetSourceSystem(),
+		CompactionLevel:    int(msg.GetCompactionLevel()),
+		CompactedAt:        fromTimestampPtr(msg.GetCompactedAt()),
+		CompactedAtCommit:  msg.CompactedAtCommit,
+		OriginalSize:       int(msg.GetOriginalSize()),
+		SourceRepo:         msg.GetSourceRepo(),
+		IDPrefix:           msg.GetIdPrefix(),
+		PrefixOverride:     msg.GetPrefixOverride(),
+		Labels:             msg.GetLabels(),
+		Dependencies:       fromList(msg.GetDependencies(), fromDependency),
+		Comments:           fromList(msg.GetComments(), fromComment),
+		DeletedAt:          fromTimestampPtr(msg.GetDeletedAt()),
+		DeletedBy:          msg.GetDeletedBy(),
+		DeleteReason:       msg.GetDeleteReason(),
+		OriginalType:       msg.GetOriginalType(),
+		Sender:             msg.GetSender(),
+		Ephemeral:          msg.GetEphemeral(),
+		WispType:           types.WispType(msg.GetWispType()),
+		Pinned:             msg.GetPinned(),
+		IsTemplate:         msg.GetIsTemplate(),
+		Creator:            fromEntityRef(msg.GetCreator()),
+		QualityScore:       msg.QualityScore,
+		Crystallizes:       msg.GetCrystallizes(),
+		AwaitType:          msg.GetAwaitType(),
+		AwaitID:            msg.GetAwaitId(),
+		Timeout:            msg.GetTimeout().AsDuration(),
+		Waiters:            msg.GetWaiters(),
+		Holder:             msg.GetHolder(),
+		SourceFormula:      msg.GetSourceFormula(),
+		SourceLocation:     msg.GetSourceLocation(),
+		HookBead:           msg.GetHookBead(),
+		RoleBead:           msg.GetRoleBead(),
+		AgentState:         types.AgentState(msg.GetAgentState()),
+		LastActivity:       fromTimestampPtr(msg.GetLastActivity()),
+		RoleType:           msg.GetRoleType(),
+		Rig:                msg.GetRig(),
+		MolType:            types.MolType(msg.GetMolType()),
+		WorkType:           types.WorkType(msg.GetWorkType()),
+		EventKind:          msg.GetEventKind(),
+		Actor:              msg.GetActor(),
+		Target:             msg.GetTarget(),
+		Payload:            msg.GetPayload(),
+	}
+	if msg.GetMetadata() != "" {
+		issue.Metadata = json.RawMessage(msg.GetMetadata())
+	}
+	for _, b := range msg.GetBondedFrom() {
+		issue.BondedFrom = append(issue.BondedFrom, types.BondRef{SourceID: b.GetSourceId(), BondType: b.GetBondType(), BondPoint: b.GetBondPoint()})
+	}
+	for _, v := range msg.GetValidations() {
+		issue.Validations = append(issue.Validations, types.Validation{
+			Validator: fromEntityRef(v.GetValidator()),
+			Outcome:   v.GetOutcome(),
+			Timestamp: fromTimestamp(v.GetTimestamp()),
+			Score:     v.Score,
+		})
+	}
+	return issue
+}
+
+// toIssues converts each of issues with toIssue.
+func toIssues(issues []*types.Issue) []*storagepb.Issue {
+	return toList(issues, toIssue)
+}
+
+// fromIssues converts each of msgs with fromIssue. The result is never nil.
+func fromIssues(msgs []*storagepb.Issue) []*types.Issue {
+	issues := make([]*types.Issue, len(msgs))
+	for i, msg := range msgs {
+		issues[i] = fromIssue(msg)
+	}
+	return issues
+}
+
+// toEntityRef converts ref to its wire form. A nil ref stays nil.
+func toEntityRef(ref *types.EntityRef) *storagepb.EntityRef {
+	if ref == nil {
+		return nil
+	}
+	return &storagepb.EntityRef{Name: ref.Name, Platform: ref.Platform, Org: ref.Org, Id: ref.ID}
+}
+
+// fromEntityRef converts a ref from its wire form. A nil message stays nil.
+func fromEntityRef(msg *storagepb.EntityRef) *types.EntityRef {
+	if msg == nil {
+		return nil
+	}
+	return &types.EntityRef{Name: msg.GetName(), Platform: msg.GetPlatform(), Org: msg.GetOrg(), ID: msg.GetId()}
+}
+
+// toDependency converts dep to its wire form. A nil dependency stays nil.
+func toDependency(dep *types.Dependency) *storagepb.Dependency {
+	if dep == nil {
+		return nil
+	}
+	return &storagepb.Dependency{
+		IssueId:     dep.IssueID,
+		DependsOnId: dep.DependsOnID,
+		Type:        string(dep.Type),
+		CreatedAt:   toTimestamp(dep.CreatedAt),
+		CreatedBy:   dep.CreatedBy,
+		Metadata:    dep.Metadata,
+		ThreadId:    dep.ThreadID,
+	}
+}
+
+// fromDependency converts a dependency from its wire form. A nil message
+// stays nil.
+func fromDependency(msg *storagepb.Dependency) *types.Dependency {
+	if msg == nil {
+		return nil
+	}
+	return &types.Dependency{
+		IssueID:     msg.GetIssueId(),
+		DependsOnID: msg.GetDependsOnId(),
+		Type:        types.DependencyType(msg.GetType()),
+		CreatedAt:   fromTimestamp(msg.GetCreatedAt()),
+		CreatedBy:   msg.GetCreatedBy(),
+		Metadata:    msg.GetMetadata(),
+		ThreadID:    msg.GetThreadId(),
+	}
+}
+
+// toComment converts c to its wire form. A nil comment stays nil.
+func toComment(c *types.Comment) *storagepb.Comment {
+	if c == nil {
+		return nil
+	}
+	return &storagepb.Comment{Id: c.ID, IssueId: c.IssueID, Author: c.Author, Text: c.Text, CreatedAt: toTimestamp(c.CreatedAt)}
+}
+
+// fromComment converts a comment from its wire form. A nil message stays
+// nil.
+func fromComment(msg *storagepb.Comment) *types.Comment {
+	if msg == nil {
+		return nil
+	}
+	return &types.Comment{
+		ID:        msg.GetId(),
+		IssueID:   msg.GetIssueId(),
+		Author:    msg.GetAuthor(),
+		Text:      msg.GetText(),
+		CreatedAt: fromTimestamp(msg.GetCreatedAt()),
+	}
+}
+
+// toEvent converts e to its wire form. A nil event stays nil.
+func toEvent(e *types.Event) *storagepb.Event {
+	if e == nil {
+		return nil
+	}
+	return &storagepb.Event{
+		Id:        e.ID,
+		IssueId:   e.IssueID,
+		EventType: string(e.EventType),
+		Actor:     e.Actor,
+		OldValue:  e.OldValue,
+		NewValue:  e.NewValue,
+		Comment:   e.Comment,
+		CreatedAt: toTimestamp(e.CreatedAt),
+	}
+}
+
+// fromEvent converts an event from its wire form. A nil message stays nil.
+func fromEvent(msg *storagepb.Event) *types.Event {
+	if msg == nil {
+		return nil
+	}
+	return &types.Event{
+		ID:        msg.GetId(),
+		IssueID:   msg.GetIssueId(),
+		EventType: types.EventType(msg.GetEventType()),
+		Actor:     msg.GetActor(),
+		OldValue:  msg.OldValue,
+		NewValue:  msg.NewValue,
+		Comment:   msg.Comment,
+		CreatedAt: fromTimestamp(msg.GetCreatedAt()),
+	}
+}
+
+// toIssueFilter converts f to its wire form.
+func toIssueFilter(f types.IssueFilter) *storagepb.IssueFilter {
+	return &storagepb.IssueFilter{
+		Status:              toStringPtr(f.Status),
+		Priority:            toInt32Ptr(f.Priority),
+		IssueType:           toStringPtr(f.IssueType),
+		Assignee:            f.Assignee,
+		Labels:              f.Labels,
+		LabelsAny:           f.LabelsAny,
+		LabelPattern:        f.LabelPattern,
+		LabelRegex:          f.LabelRegex,
+		TitleSearch:         f.TitleSearch,
+		Ids:                 f.IDs,
+		IdPrefix:            f.IDPrefix,
+		SpecIdPrefix:        f.SpecIDPrefix,
+		Limit:               int32(f.Limit),
+		TitleContains:       f.TitleContains,
+		DescriptionContains: f.DescriptionContains,
+		NotesContains:       f.NotesContains,
+		CreatedAfter:        toTimestampPtr(f.CreatedAfter),
+		CreatedBefore:       toTimestampPtr(f.CreatedBefore),
+		UpdatedAfter:        toTimestampPtr(f.UpdatedAfter),
+		UpdatedBefore:       toTimestampPtr(f.UpdatedBefore),
+		ClosedAfter:         toTimestampPtr(f.ClosedAfter),
+		ClosedBefore:        toTimestampPtr(f.ClosedBefore),
+		EmptyDescription:    f.EmptyDescription,
+		NoAssignee:          f.NoAssignee,
+		NoLabels:            f.NoLabels,
+		PriorityMin:         toInt32Ptr(f.PriorityMin),
+		PriorityMax:         toInt32Ptr(f.PriorityMax),
+		IncludeTombstones:   f.IncludeTombstones,
+		IncludeDeleted:      f.IncludeDeleted,
+		Ephemeral:           f.Ephemeral,
+		Pinned:              f.Pinned,
+		IsTemplate:          f.IsTemplate,
+		ParentId:            f.ParentID,
+		MolType:             toStringPtr(f.MolType),
+		WispType:            toStringPtr(f.WispType),
+		ExcludeStatus:       toStrings(f.ExcludeStatus),
+		ExcludeTypes:        toStrings(f.ExcludeTypes),
+		Deferred:            f.Deferred,
+		DeferAfter:          toTimestampPtr(f.DeferAfter),
+		DeferBefore:         toTimestampPtr(f.DeferBefore),
+		DueAfter:            toTimestampPtr(f.DueAfter),
+		DueBefore:           toTimestampPtr(f.DueBefore),
+		Overdue:             f.Overdue,
+	}
+}
+
+// fromIssueFilter converts a filter from its wire form. A nil message gives
+// the zero filter.
+func fromIssueFilter(msg *storagepb.IssueFilter) types.IssueFilter {
+	return types.IssueFilter{
+		Status:              fromStringPtr[types.Status](msg.Status),
+		Priority:            fromInt32Ptr(msg.Priority),
+		IssueType:           fromStringPtr[types.IssueType](msg.IssueType),
+		Assignee:            msg.Assignee,
+		Labels:              msg.GetLabels(),
+		LabelsAny:           msg.GetLabelsAny(),
+		LabelPattern:        msg.GetLabelPattern(),
+		LabelRegex:          msg.GetLabelRegex(),
+		TitleSearch:         msg.GetTitleSearch(),
+		IDs:                 msg.GetIds(),
+		IDPrefix:            msg.GetIdPrefix(),
+		SpecIDPrefix:        msg.GetSpecIdPrefix(),
+		Limit:               int(msg.GetLimit()),
+		TitleContains:       msg.GetTitleContains(),
+		DescriptionContains: msg.GetDescriptionContains(),
+		NotesContains:       msg.GetNotesContains(),
+		CreatedAfter:        fromTimestampPtr(msg.GetCreatedAfter()),
+		CreatedBefore:       fromTimestampPtr(msg.GetCreatedBefore()),
+		UpdatedAfter:        fromTimestampPtr(msg.GetUpdatedAfter()),
+		UpdatedBefore:       fromTimestampPtr(msg.GetUpdatedBefore()),
+		ClosedAfter:         fromTimestampPtr(msg.GetClosedAfter()),
+		ClosedBefore:        fromTimestampPtr(msg.GetClosedBefore()),
+		EmptyDescription:    msg.GetEmptyDescription(),
+		NoAssignee:          msg.GetNoAssignee(),
+		NoLabels:            msg.GetNoLabels(),
+		PriorityMin:         fromInt32Ptr(msg.PriorityMin),
+		PriorityMax:         fromInt32Ptr(msg.PriorityMax),
+		IncludeTombstones:   msg.GetIncludeTombstones(),
+		IncludeDeleted:      msg.GetIncludeDeleted(),
+		Ephemeral:           msg.Ephemeral,
+		Pinned:              msg.Pinned,
+		IsTemplate:          msg.IsTemplate,
+		ParentID:            msg.ParentId,
+		MolType:             fromStringPtr[types.MolType](msg.MolType),
+		WispType:            fromStringPtr[types.WispType](msg.WispType),
+		ExcludeStatus:       fromStrings[types.Status](msg.GetExcludeStatus()),
+		ExcludeTypes:        fromStrings[types.IssueType](msg.GetExcludeTypes()),
+		Deferred:            msg.GetDeferred(),
+		DeferAfter:          fromTimestampPtr(msg.GetDeferAfter()),
+		DeferBefore:         fromTimestampPtr(msg.GetDeferBefore()),
+		DueAfter:            fromTimestampPtr(msg.GetDueAfter()),
+		DueBefore:           fromTimestampPtr(msg.GetDueBefore()),
+		Overdue:             msg.GetOverdue(),
+	}
+}
+
+// toWorkFilter converts f to its wire form.
+func toWorkFilter(f types.WorkFilter) *storagepb.WorkFilter {
+	return &storagepb.WorkFilter{
+		Status:          string(f.Status),
+		Type:            f.Type,
+		Priority:        toInt32Ptr(f.Priority),
+		Assignee:        f.Assignee,
+		Unassigned:      f.Unassigned,
+		Labels:          f.Labels,
+		LabelsAny:       f.LabelsAny,
+		LabelPattern:    f.LabelPattern,
+		LabelRegex:      f.LabelRegex,
+		Limit:           int32(f.Limit),
+		SortPolicy:      string(f.SortPolicy),
+		ParentId:        f.ParentID,
+		MolType:         toStringPtr(f.MolType),
+		WispType:        toStringPtr(f.WispType),
+		IncludeDeferred: f.IncludeDeferred,
+		IncludeMolSteps: f.IncludeMolSteps,
+	}
+}
+
+// fromWorkFilter converts a filter from its wire form. A nil message gives
+// the zero filter.
+func fromWorkFilter(msg *storagepb.WorkFilter) types.WorkFilter {
+	return types.WorkFilter{
+		Status:          types.Status(msg.GetStatus()),
+		Type:            msg.GetType(),
+		Priority:        fromInt32Ptr(msg.Priority),
+		Assignee:        msg.Assignee,
+		Unassigned:      msg.GetUnassigned(),
+		Labels:          msg.GetLabels(),
+		LabelsAny:       msg.GetLabelsAny(),
+		LabelPattern:    msg.GetLabelPattern(),
+		LabelRegex:      msg.GetLabelRegex(),
+		Limit:           int(msg.GetLimit()),
+		SortPolicy:      types.SortPolicy(msg.GetSortPolicy()),
+		ParentID:        msg.ParentId,
+		MolType:         fromStringPtr[types.MolType](msg.MolType),
+		WispType:        fromStringPtr[types.WispType](msg.WispType),
+		IncludeDeferred: msg.GetIncludeDeferred(),
+		IncludeMolSteps: msg.GetIncludeMolSteps(),
+	}
+}
+
+// toStaleFilter converts f to its wire form.
+func toStaleFilter(f types.StaleFilter) *storagepb.StaleFilter {
+	return &storagepb.StaleFilter{Days: int32(f.Days), Status: f.Status, Limit: int32(f.Limit)}
+}
+
+// fromStaleFilter converts a filter from its wire form. A nil message gives
+// the zero filter.
+func fromStaleFilter(msg *storagepb.StaleFilter) types.StaleFilter {
+	return types.StaleFilter{Days: int(msg.GetDays()), Status: msg.GetStatus(), Limit: int(msg.GetLimit())}
+}
+
+// toIssueWithDependencyMetadata converts issue to its wire form.
+func toIssueWithDependencyMetadata(issue *types.IssueWithDependencyMetadata) *storagepb.IssueWithDependencyMetadata {
+	if issue == nil {
+		return nil
+	}
+	return &storagepb.IssueWithDependencyMetadata{Issue: toIssue(&issue.Issue), DependencyType: string(issue.DependencyType)}
+}
+
+// fromIssueWithDependencyMetadata converts an issue from its wire form.
+func fromIssueWithDependencyMetadata(msg *storagepb.IssueWithDependencyMetadata) *types.IssueWithDependencyMetadata {
+	if msg == nil {
+		return nil
+	}
+	issue := &types.IssueWithDependencyMetadata{DependencyType: types.DependencyType(msg.GetDependencyType())}
+	if i := fromIssue(msg.GetIssue()); i != nil {
+		issue.Issue = *i
+	}
+	return issue
+}
+
+// toTreeNode converts node to its wire form.
+func toTreeNode(node *types.TreeNode) *storagepb.TreeNode {
+	if node == nil {
+		return nil
+	}
+	return &storagepb.TreeNode{Issue: toIssue(&node.Issue), Depth: int32(node.Depth), ParentId: node.ParentID, Truncated: node.Truncated}
+}
+
+// fromTreeNode converts a node from its wire form.
+func fromTreeNode(msg *storagepb.TreeNode) *types.TreeNode {
+	if msg == nil {
+		return nil
+	}
+	node := &types.TreeNode{Depth: int(msg.GetDepth()), ParentID: msg.GetParentId(), Truncated: msg.GetTruncated()}
+	if i := fromIssue(msg.GetIssue()); i != nil {
+		node.Issue = *i
+	}
+	return node
+}
+
+// toBlockedIssue converts issue to its wire form.
+func toBlockedIssue(issue *types.BlockedIssue) *storagepb.BlockedIssue {
+	if issue == nil {
+		return nil
+	}
+	return &storagepb.BlockedIssue{Issue: toIssue(&issue.Issue), BlockedByCount: int64(issue.BlockedByCount), BlockedBy: issue.BlockedBy}
+}
+
+// fromBlockedIssue converts an issue from its wire form.
+func fromBlockedIssue(msg *storagepb.BlockedIssue) *types.BlockedIssue {
+	if msg == nil {
+		return nil
+	}
+	issue := &types.BlockedIssue{BlockedByCount: int(msg.GetBlockedByCount()), BlockedBy: msg.GetBlockedBy()}
+	if i := fromIssue(msg.GetIssue()); i != nil {
+		issue.Issue = *i
+	}
+	return issue
+}
+
+// toEpicStatus converts epic to its wire form.
+func toEpicStatus(epic *types.EpicStatus) *storagepb.EpicStatus {
+	if epic == nil {
+		return nil
+	}
+	return &storagepb.EpicStatus{
+		Epic:             toIssue(epic.Epic),
+		TotalChildren:    int64(epic.TotalChildren),
+		ClosedChildren:   int64(epic.ClosedChildren),
+		EligibleForClose: epic.EligibleForClose,
+	}
+}
+
+// fromEpicStatus converts an epic from its wire form.
+func fromEpicStatus(msg *storagepb.EpicStatus) *types.EpicStatus {
+	if msg == nil {
+		return nil
+	}
+	return &types.EpicStatus{
+		Epic:             fromIssue(msg.GetEpic()),
+		TotalChildren:    int(msg.GetTotalChildren()),
+		ClosedChildren:   int(msg.GetClosedChildren()),
+		EligibleForClose: msg.GetEligibleForClose(),
+	}
+}
+
+// toStatistics converts stats to its wire form.
+func toStatistics(stats *types.Statistics) *storagepb.Statistics {
+	if stats == nil {
+		return nil
+	}
+	return &storagepb.Statistics{
+		TotalIssues:             int64(stats.TotalIssues),
+		OpenIssues:              int64(stats.OpenIssues),
+		InProgressIssues:        int64(stats.InProgressIssues),
+		ClosedIssues:            int64(stats.ClosedIssues),
+		BlockedIssues:           int64(stats.BlockedIssues),
+		DeferredIssues:          int64(stats.DeferredIssues),
+		ReadyIssues:             int64(stats.ReadyIssues),
+		TombstoneIssues:         int64(stats.TombstoneIssues),
+		PinnedIssues:            int64(stats.PinnedIssues),
+		EpicsEligibleForClosure: int64(stats.EpicsEligibleForClosure),
+		AverageLeadTimeHours:    stats.AverageLeadTime,
+	}
+}
+
+// fromStatistics converts statistics from their wire form.
+func fromStatistics(msg *storagepb.Statistics) *types.Statistics {
+	if msg == nil {
+		return nil
+	}
+	return &types.Statistics{
+		TotalIssues:             int(msg.GetTotalIssues()),
+		OpenIssues:              int(msg.GetOpenIssues()),
+		InProgressIssues:        int(msg.GetInProgressIssues()),
+		ClosedIssues:            int(msg.GetClosedIssues()),
+		BlockedIssues:           int(msg.GetBlockedIssues()),
+		DeferredIssues:          int(msg.GetDeferredIssues()),
+		ReadyIssues:             int(msg.GetReadyIssues()),
+		TombstoneIssues:         int(msg.GetTombstoneIssues()),
+		PinnedIssues:            int(msg.GetPinnedIssues()),
+		EpicsEligibleForClosure: int(msg.GetEpicsEligibleForClosure()),
+		AverageLeadTime:         msg.GetAverageLeadTimeHours(),
+	}
+}
+
+// toMoleculeProgress converts progress to its wire form.
+func toMoleculeProgress(progress *types.MoleculeProgressStats) *storagepb.MoleculeProgressStats {
+	if progress == nil {
+		return nil
+	}
+	return &storagepb.MoleculeProgressStats{
+		MoleculeId:    progress.MoleculeID,
+		MoleculeTitle: progress.MoleculeTitle,
+		Total:         int64(progress.Total),
+		Completed:     int64(progress.Completed),
+		InProgress:    int64(progress.InProgress),
+		CurrentStepId: progress.CurrentStepID,
+		FirstClosed:   toTimestampPtr(progress.FirstClosed),
+		LastClosed:    toTimestampPtr(progress.LastClosed),
+	}
+}
+
+// fromMoleculeProgress converts progress from its wire form.
+func fromMoleculeProgress(msg *storagepb.MoleculeProgressStats) *types.MoleculeProgressStats {
+	if msg == nil {
+		return nil
+	}
+	return &types.MoleculeProgressStats{
+		MoleculeID:    msg.GetMoleculeId(),
+		MoleculeTitle: msg.GetMoleculeTitle(),
+		Total:         int(msg.GetTotal()),
+		Completed:     int(msg.GetCompleted()),
+		InProgress:    int(msg.GetInProgress()),
+		CurrentStepID: msg.GetCurrentStepId(),
+		FirstClosed:   fromTimestampPtr(msg.GetFirstClosed()),
+		LastClosed:    fromTimestampPtr(msg.GetLastClosed()),
+	}
+}
+
+// clearedField names the IssueUpdates field listing keys set to nil.
+const clearedField = "cleared"
+
+// toIssueUpdates converts the updates of an UpdateIssue call to their wire
+// form. Each key is stored in the IssueUpdates field named after it, so only
+// keys with a field can be sent; a nil value, including a nil pointer or
+// slice, is listed as cleared. Pointers are followed and named string types
+// are sent as strings, so a value may be given in any of the forms the
+// stores accept for its key.
+func toIssueUpdates(updates map[string]interface{}) (*storagepb.IssueUpdates, error) {
+	msg := &storagepb.IssueUpdates{}
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	for key, value := range updates {
+		fd := fields.ByName(protoreflect.Name(key))
+		if fd == nil || key == clearedField {
+			return nil, fmt.Errorf("invalid field for update: %s", key)
+		}
+		rv := reflect.ValueOf(value)
+		for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		if !rv.IsValid() || ((rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Slice) && rv.IsNil()) {
+			msg.Cleared = append(msg.Cleared, key)
+			continue
+		}
+		v, ok := toUpdateValue(fd, rv)
+		if !ok {
+			return nil, fmt.Errorf("invalid value for update of %s: %T", key, value)
+		}
+		m.Set(fd, v)
+	}
+	sort.Strings(msg.Cleared)
+	return msg, nil
+}
+
+// toUpdateValue converts the value of an update to the kind of fd, reporting
+// whether it has a form that converts.
+func toUpdateValue(fd protoreflect.FieldDescriptor, rv reflect.Value) (protoreflect.Value, bool) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		if rv.Kind() == reflect.String {
+			return protoreflect.ValueOfString(rv.String()), true
+		}
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return protoreflect.ValueOfString(string(rv.Bytes())), true
+		}
+	case protoreflect.Int32Kind:
+		if rv.CanInt() {
+			return protoreflect.ValueOfInt32(int32(rv.Int())), true
+		}
+	case protoreflect.BoolKind:
+		if rv.Kind() == reflect.Bool {
+			return protoreflect.ValueOfBool(rv.Bool()), true
+		}
+	case protoreflect.MessageKind:
+		switch msg := fd.Message().FullName(); {
+		case msg == timestampName && rv.Type() == reflect.TypeOf(time.Time{}):
+			return protoreflect.ValueOfMessage(timestamppb.New(rv.Interface().(time.Time)).ProtoReflect()), true
+		case msg == stringListName && rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.String:
+			list := &storagepb.StringList{Values: make([]string, rv.Len())}
+			for i := range list.Values {
+				list.Values[i] = rv.Index(i).String()
+			}
+			return protoreflect.ValueOfMessage(list.ProtoReflect()), true
+		}
+	}
+	return protoreflect.Value{}, false
+}
+
+var (
+	timestampName  = (&timestamppb.Timestamp{}).ProtoReflect().Descriptor().FullName()
+	stringListName = (&storagepb.StringList{}).ProtoReflect().Descriptor().FullName()
+)
+
+// fromIssueUpdates converts the updates of an UpdateIssue call from their
+// wire form, giving each value the Go type the stores expect for its key:
+// string, int, bool, time.Time or []string, or nil for cleared keys.
+func fromIssueUpdates(msg *storagepb.IssueUpdates) map[string]interface{} {
+	updates := make(map[string]interface{})
+	for _, key := range msg.GetCleared() {
+		updates[key] = nil
+	}
+	msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		key := string(fd.Name())
+		switch {
+		case key == clearedField:
+		case fd.Kind() == protoreflect.StringKind:
+			updates[key] = v.String()
+		case fd.Kind() == protoreflect.Int32Kind:
+			updates[key] = int(v.Int())
+		case fd.Kind() == protoreflect.BoolKind:
+			updates[key] = v.Bool()
+		case fd.Message().FullName() == timestampName:
+			updates[key] = v.Message().Interface().(*timestamppb.Timestamp).AsTime()
+		case fd.Message().FullName() == stringListName:
+			values := v.Message().Interface().(*storagepb.StringList).GetValues()
+			if values == nil {
+				values = []string{}
+			}
+			updates[key] = values
+		}
+		return true
+	})
+	return updates
+}
@@ -0,0 +1,636 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/grpc/storagepb"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// NewServer returns a gRPC server with store registered on it, as Register
+// does, and RecoverUnary installed ahead of any interceptors in opts.
+func NewServer(store storage.Storage, opts ...gogrpc.ServerOption) *gogrpc.Server {
+	opts = append([]gogrpc.ServerOption{gogrpc.ChainUnaryInterceptor(RecoverUnary)}, opts...)
+	s := gogrpc.NewServer(opts...)
+	Register(s, store)
+	return s
+}
+
+// Register serves store on s under ServiceName. gRPC does not recover from
+// panics in handlers, so a server built without NewServer should install
+// RecoverUnary.
+func Register(s *gogrpc.Server, store storage.Storage) {
+	storagepb.RegisterStorageServer(s, &server{store: store})
+}
+
+// RecoverUnary is a unary server interceptor that turns a panic in a handler
+// into an Internal error, so a bad request cannot bring down the server.
+func RecoverUnary(ctx context.Context, req any, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "PANIC in %s: %v\n", info.FullMethod, r)
+			fmt.Fprintf(os.Stderr, "Stack trace:\n%s\n", debug.Stack())
+			resp, err = nil, status.Errorf(codes.Internal, "panic in %s: %v", info.FullMethod, r)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// server implements the Storage service by calling the wrapped storage.
+type server struct {
+	storagepb.UnimplementedStorageServer
+	store storage.Storage
+}
+
+// argIssue converts an issue from a request, which must contain one.
+func argIssue(msg *storagepb.Issue) (*types.Issue, error) {
+	if msg == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing issue")
+	}
+	return fromIssue(msg), nil
+}
+
+// argIssues converts the issues of a request with argIssue.
+func argIssues(msgs []*storagepb.Issue) ([]*types.Issue, error) {
+	issues := make([]*types.Issue, len(msgs))
+	for i, msg := range msgs {
+		issue, err := argIssue(msg)
+		if err != nil {
+			return nil, err
+		}
+		issues[i] = issue
+	}
+	return issues, nil
+}
+
+// issuesResult converts the issues returned by a storage method, or its
+// error.
+func issuesResult(issues []*types.Issue, err error) ([]*storagepb.Issue, error) {
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toIssues(issues), nil
+}
+
+// listResult converts the values returned by a storage method with convert,
+// or converts its error.
+func listResult[T, M any](convert func(T) M) func([]T, error) ([]M, error) {
+	return func(values []T, err error) ([]M, error) {
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		return toList(values, convert), nil
+	}
+}
+
+func (s *server) CreateIssue(ctx context.Context, req *storagepb.CreateIssueRequest) (*storagepb.CreateIssueResponse, error) {
+	issue, err := argIssue(req.GetIssue())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.CreateIssue(ctx, issue, req.GetActor()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.CreateIssueResponse{Issue: toIssue(issue)}, nil
+}
+
+func (s *server) CreateIssues(ctx context.Context, req *storagepb.CreateIssuesRequest) (*storagepb.CreateIssuesResponse, error) {
+	issues, err := argIssues(req.GetIssues())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.CreateIssues(ctx, issues, req.GetActor()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.CreateIssuesResponse{Issues: toIssues(issues)}, nil
+}
+
+func (s *server) CreateIssuesWithFullOptions(ctx context.Context, req *storagepb.CreateIssuesWithFullOptionsRequest) (*storagepb.CreateIssuesWithFullOptionsResponse, error) {
+	issues, err := argIssues(req.GetIssues())
+	if err != nil {
+		return nil, err
+	}
+	opts := storage.BatchCreateOptions{
+		OrphanHandling:       storage.OrphanHandling(req.GetOrphanHandling()),
+		SkipPrefixValidation: req.GetSkipPrefixValidation(),
+	}
+	if err := s.store.CreateIssuesWithFullOptions(ctx, issues, req.GetActor(), opts); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.CreateIssuesWithFullOptionsResponse{Issues: toIssues(issues)}, nil
+}
+
+func (s *server) GetIssue(ctx context.Context, req *storagepb.GetIssueRequest) (*storagepb.GetIssueResponse, error) {
+	issue, err := s.store.GetIssue(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetIssueResponse{Issue: toIssue(issue)}, nil
+}
+
+func (s *server) GetIssueByExternalRef(ctx context.Context, req *storagepb.GetIssueByExternalRefRequest) (*storagepb.GetIssueByExternalRefResponse, error) {
+	issue, err := s.store.GetIssueByExternalRef(ctx, req.GetExternalRef())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetIssueByExternalRefResponse{Issue: toIssue(issue)}, nil
+}
+
+func (s *server) UpdateIssue(ctx context.Context, req *storagepb.UpdateIssueRequest) (*storagepb.UpdateIssueResponse, error) {
+	if err := s.store.UpdateIssue(ctx, req.GetId(), fromIssueUpdates(req.GetUpdates()), req.GetActor()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.UpdateIssueResponse{}, nil
+}
+
+func (s *server) ClaimIssue(ctx context.Context, req *storagepb.ClaimIssueRequest) (*storagepb.ClaimIssueResponse, error) {
+	if err := s.store.ClaimIssue(ctx, req.GetId(), req.GetActor()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.ClaimIssueResponse{}, nil
+}
+
+func (s *server) CloseIssue(ctx context.Context, req *storagepb.CloseIssueRequest) (*storagepb.CloseIssueResponse, error) {
+	if err := s.store.CloseIssue(ctx, req.GetId(), req.GetReason(), req.GetActor(), req.GetSession()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.CloseIssueResponse{}, nil
+}
+
+func (s *server) DeleteIssue(ctx context.Context, req *storagepb.DeleteIssueRequest) (*storagepb.DeleteIssueResponse, error) {
+	if err := s.store.DeleteIssue(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.DeleteIssueResponse{}, nil
+}
+
+func (s *server) SearchIssues(ctx context.Context, req *storagepb.SearchIssuesRequest) (*storagepb.SearchIssuesResponse, error) {
+	issues, err := issuesResult(s.store.SearchIssues(ctx, req.GetQuery(), fromIssueFilter(req.GetFilter())))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.SearchIssuesResponse{Issues: issues}, nil
+}
+
+func (s *server) AddDependency(ctx context.Context, req *storagepb.AddDependencyRequest) (*storagepb.AddDependencyResponse, error) {
+	if req.GetDependency() == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing dependency")
+	}
+	if err := s.store.AddDependency(ctx, fromDependency(req.GetDependency()), req.GetActor()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.AddDependencyResponse{}, nil
+}
+
+func (s *server) RemoveDependency(ctx context.Context, req *storagepb.RemoveDependencyRequest) (*storagepb.RemoveDependencyResponse, error) {
+	if err := s.store.RemoveDependency(ctx, req.GetIssueId(), req.GetDependsOnId(), req.GetActor()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.RemoveDependencyResponse{}, nil
+}
+
+func (s *server) GetDependencies(ctx context.Context, req *storagepb.GetDependenciesRequest) (*storagepb.GetDependenciesResponse, error) {
+	issues, err := issuesResult(s.store.GetDependencies(ctx, req.GetIssueId()))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetDependenciesResponse{Issues: issues}, nil
+}
+
+func (s *server) GetDependents(ctx context.Context, req *storagepb.GetDependentsRequest) (*storagepb.GetDependentsResponse, error) {
+	issues, err := issuesResult(s.store.GetDependents(ctx, req.GetIssueId()))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetDependentsResponse{Issues: issues}, nil
+}
+
+func (s *server) GetDependenciesWithMetadata(ctx context.Context, req *storagepb.GetDependenciesWithMetadataRequest) (*storagepb.GetDependenciesWithMetadataResponse, error) {
+	issues, err := listResult(toIssueWithDependencyMetadata)(s.store.GetDependenciesWithMetadata(ctx, req.GetIssueId()))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetDependenciesWithMetadataResponse{Issues: issues}, nil
+}
+
+func (s *server) GetDependentsWithMetadata(ctx context.Context, req *storagepb.GetDependentsWithMetadataRequest) (*storagepb.GetDependentsWithMetadataResponse, error) {
+	issues, err := listResult(toIssueWithDependencyMetadata)(s.store.GetDependentsWithMetadata(ctx, req.GetIssueId()))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetDependentsWithMetadataResponse{Issues: issues}, nil
+}
+
+func (s *server) GetDependencyRecords(ctx context.Context, req *storagepb.GetDependencyRecordsRequest) (*storagepb.GetDependencyRecordsResponse, error) {
+	deps, err := listResult(toDependency)(s.store.GetDependencyRecords(ctx, req.GetIssueId()))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetDependencyRecordsResponse{Dependencies: deps}, nil
+}
+
+// dependencyMapResult converts dependency records keyed by issue ID, or the
+// error of the storage method that returned them.
+func dependencyMapResult(records map[string][]*types.Dependency, err error) (map[string]*storagepb.DependencyList, error) {
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	result := make(map[string]*storagepb.DependencyList, len(records))
+	for issueID, deps := range records {
+		result[issueID] = &storagepb.DependencyList{Dependencies: toList(deps, toDependency)}
+	}
+	return result, nil
+}
+
+func (s *server) GetAllDependencyRecords(ctx context.Context, req *storagepb.GetAllDependencyRecordsRequest) (*storagepb.GetAllDependencyRecordsResponse, error) {
+	deps, err := dependencyMapResult(s.store.GetAllDependencyRecords(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetAllDependencyRecordsResponse{Dependencies: deps}, nil
+}
+
+func (s *server) GetDependencyRecordsForIssues(ctx context.Context, req *storagepb.GetDependencyRecordsForIssuesRequest) (*storagepb.GetDependencyRecordsForIssuesResponse, error) {
+	deps, err := dependencyMapResult(s.store.GetDependencyRecordsForIssues(ctx, req.GetIssueIds()))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetDependencyRecordsForIssuesResponse{Dependencies: deps}, nil
+}
+
+func (s *server) GetDependencyCounts(ctx context.Context, req *storagepb.GetDependencyCountsRequest) (*storagepb.GetDependencyCountsResponse, error) {
+	counts, err := s.store.GetDependencyCounts(ctx, req.GetIssueIds())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &storagepb.GetDependencyCountsResponse{Counts: make(map[string]*storagepb.DependencyCounts, len(counts))}
+	for issueID, c := range counts {
+		if c == nil {
+			continue
+		}
+		resp.Counts[issueID] = &storagepb.DependencyCounts{DependencyCount: int64(c.DependencyCount), DependentCount: int64(c.DependentCount)}
+	}
+	return resp, nil
+}
+
+func (s *server) GetDependencyTree(ctx context.Context, req *storagepb.GetDependencyTreeRequest) (*storagepb.GetDependencyTreeResponse, error) {
+	nodes, err := listResult(toTreeNode)(s.store.GetDependencyTree(ctx, req.GetIssueId(), int(req.GetMaxDepth()), req.GetShowAllPaths(), req.GetReverse()))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetDependencyTreeResponse{Nodes: nodes}, nil
+}
+
+func (s *server) DetectCycles(ctx context.Context, req *storagepb.DetectCyclesRequest) (*storagepb.DetectCyclesResponse, error) {
+	cycles, err := s.store.DetectCycles(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &storagepb.DetectCyclesResponse{Cycles: make([]*storagepb.IssueList, len(cycles))}
+	for i, cycle := range cycles {
+		resp.Cycles[i] = &storagepb.IssueList{Issues: toIssues(cycle)}
+	}
+	return resp, nil
+}
+
+func (s *server) AddLabel(ctx context.Context, req *storagepb.AddLabelRequest) (*storagepb.AddLabelResponse, error) {
+	if err := s.store.AddLabel(ctx, req.GetIssueId(), req.GetLabel(), req.GetActor()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.AddLabelResponse{}, nil
+}
+
+func (s *server) RemoveLabel(ctx context.Context, req *storagepb.RemoveLabelRequest) (*storagepb.RemoveLabelResponse, error) {
+	if err := s.store.RemoveLabel(ctx, req.GetIssueId(), req.GetLabel(), req.GetActor()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.RemoveLabelResponse{}, nil
+}
+
+func (s *server) GetLabels(ctx context.Context, req *storagepb.GetLabelsRequest) (*storagepb.GetLabelsResponse, error) {
+	labels, err := s.store.GetLabels(ctx, req.GetIssueId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetLabelsResponse{Labels: labels}, nil
+}
+
+func (s *server) GetLabelsForIssues(ctx context.Context, req *storagepb.GetLabelsForIssuesRequest) (*storagepb.GetLabelsForIssuesResponse, error) {
+	labels, err := s.store.GetLabelsForIssues(ctx, req.GetIssueIds())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &storagepb.GetLabelsForIssuesResponse{Labels: make(map[string]*storagepb.StringList, len(labels))}
+	for issueID, l := range labels {
+		resp.Labels[issueID] = &storagepb.StringList{Values: l}
+	}
+	return resp, nil
+}
+
+func (s *server) GetIssuesByLabel(ctx context.Context, req *storagepb.GetIssuesByLabelRequest) (*storagepb.GetIssuesByLabelResponse, error) {
+	issues, err := issuesResult(s.store.GetIssuesByLabel(ctx, req.GetLabel()))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetIssuesByLabelResponse{Issues: issues}, nil
+}
+
+func (s *server) GetReadyWork(ctx context.Context, req *storagepb.GetReadyWorkRequest) (*storagepb.GetReadyWorkResponse, error) {
+	issues, err := issuesResult(s.store.GetReadyWork(ctx, fromWorkFilter(req.GetFilter())))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetReadyWorkResponse{Issues: issues}, nil
+}
+
+func (s *server) GetBlockedIssues(ctx context.Context, req *storagepb.GetBlockedIssuesRequest) (*storagepb.GetBlockedIssuesResponse, error) {
+	issues, err := listResult(toBlockedIssue)(s.store.GetBlockedIssues(ctx, fromWorkFilter(req.GetFilter())))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetBlockedIssuesResponse{Issues: issues}, nil
+}
+
+func (s *server) IsBlocked(ctx context.Context, req *storagepb.IsBlockedRequest) (*storagepb.IsBlockedResponse, error) {
+	blocked, blockers, err := s.store.IsBlocked(ctx, req.GetIssueId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.IsBlockedResponse{Blocked: blocked, Blockers: blockers}, nil
+}
+
+func (s *server) GetEpicsEligibleForClosure(ctx context.Context, req *storagepb.GetEpicsEligibleForClosureRequest) (*storagepb.GetEpicsEligibleForClosureResponse, error) {
+	epics, err := listResult(toEpicStatus)(s.store.GetEpicsEligibleForClosure(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetEpicsEligibleForClosureResponse{Epics: epics}, nil
+}
+
+func (s *server) GetStaleIssues(ctx context.Context, req *storagepb.GetStaleIssuesRequest) (*storagepb.GetStaleIssuesResponse, error) {
+	issues, err := issuesResult(s.store.GetStaleIssues(ctx, fromStaleFilter(req.GetFilter())))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetStaleIssuesResponse{Issues: issues}, nil
+}
+
+func (s *server) GetNewlyUnblockedByClose(ctx context.Context, req *storagepb.GetNewlyUnblockedByCloseRequest) (*storagepb.GetNewlyUnblockedByCloseResponse, error) {
+	issues, err := issuesResult(s.store.GetNewlyUnblockedByClose(ctx, req.GetClosedIssueId()))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetNewlyUnblockedByCloseResponse{Issues: issues}, nil
+}
+
+func (s *server) AddComment(ctx context.Context, req *storagepb.AddCommentRequest) (*storagepb.AddCommentResponse, error) {
+	if err := s.store.AddComment(ctx, req.GetIssueId(), req.GetActor(), req.GetComment()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.AddCommentResponse{}, nil
+}
+
+func (s *server) GetEvents(ctx context.Context, req *storagepb.GetEventsRequest) (*storagepb.GetEventsResponse, error) {
+	events, err := listResult(toEvent)(s.store.GetEvents(ctx, req.GetIssueId(), int(req.GetLimit())))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetEventsResponse{Events: events}, nil
+}
+
+func (s *server) GetAllEventsSince(ctx context.Context, req *storagepb.GetAllEventsSinceRequest) (*storagepb.GetAllEventsSinceResponse, error) {
+	events, err := listResult(toEvent)(s.store.GetAllEventsSince(ctx, req.GetSinceId()))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetAllEventsSinceResponse{Events: events}, nil
+}
+
+func (s *server) AddIssueComment(ctx context.Context, req *storagepb.AddIssueCommentRequest) (*storagepb.AddIssueCommentResponse, error) {
+	comment, err := s.store.AddIssueComment(ctx, req.GetIssueId(), req.GetAuthor(), req.GetText())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.AddIssueCommentResponse{Comment: toComment(comment)}, nil
+}
+
+func (s *server) ImportIssueComment(ctx context.Context, req *storagepb.ImportIssueCommentRequest) (*storagepb.ImportIssueCommentResponse, error) {
+	if err := req.GetCreatedAt().CheckValid(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid created_at: %v", err)
+	}
+	comment, err := s.store.ImportIssueComment(ctx, req.GetIssueId(), req.GetAuthor(), req.GetText(), req.GetCreatedAt().AsTime())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.ImportIssueCommentResponse{Comment: toComment(comment)}, nil
+}
+
+func (s *server) GetIssueComments(ctx context.Context, req *storagepb.GetIssueCommentsRequest) (*storagepb.GetIssueCommentsResponse, error) {
+	comments, err := listResult(toComment)(s.store.GetIssueComments(ctx, req.GetIssueId()))
+	if err != nil {
+		return nil, err
+	}
+	return &storagepb.GetIssueCommentsResponse{Comments: comments}, nil
+}
+
+func (s *server) GetCommentsForIssues(ctx context.Context, req *storagepb.GetCommentsForIssuesRequest) (*storagepb.GetCommentsForIssuesResponse, error) {
+	comments, err := s.store.GetCommentsForIssues(ctx, req.GetIssueIds())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &storagepb.GetCommentsForIssuesResponse{Comments: make(map[string]*storagepb.CommentList, len(comments))}
+	for issueID, c := range comments {
+		resp.Comments[issueID] = &storagepb.CommentList{Comments: toList(c, toComment)}
+	}
+	return resp, nil
+}
+
+func (s *server) GetCommentCounts(ctx context.Context, req *storagepb.GetCommentCountsRequest) (*storagepb.GetCommentCountsResponse, error) {
+	counts, err := s.store.GetCommentCounts(ctx, req.GetIssueIds())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &storagepb.GetCommentCountsResponse{Counts: make(map[string]int64, len(counts))}
+	for issueID, n := range counts {
+		resp.Counts[issueID] = int64(n)
+	}
+	return resp, nil
+}
+
+func (s *server) GetStatistics(ctx context.Context, req *storagepb.GetStatisticsRequest) (*storagepb.GetStatisticsResponse, error) {
+	stats, err := s.store.GetStatistics(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetStatisticsResponse{Statistics: toStatistics(stats)}, nil
+}
+
+func (s *server) GetMoleculeProgress(ctx context.Context, req *storagepb.GetMoleculeProgressRequest) (*storagepb.GetMoleculeProgressResponse, error) {
+	progress, err := s.store.GetMoleculeProgress(ctx, req.GetMoleculeId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetMoleculeProgressResponse{Progress: toMoleculeProgress(progress)}, nil
+}
+
+func (s *server) GetDirtyIssues(ctx context.Context, req *storagepb.GetDirtyIssuesRequest) (*storagepb.GetDirtyIssuesResponse, error) {
+	ids, err := s.store.GetDirtyIssues(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetDirtyIssuesResponse{IssueIds: ids}, nil
+}
+
+func (s *server) GetDirtyIssueHash(ctx context.Context, req *storagepb.GetDirtyIssueHashRequest) (*storagepb.GetDirtyIssueHashResponse, error) {
+	hash, err := s.store.GetDirtyIssueHash(ctx, req.GetIssueId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetDirtyIssueHashResponse{ContentHash: hash}, nil
+}
+
+func (s *server) ClearDirtyIssuesByID(ctx context.Context, req *storagepb.ClearDirtyIssuesByIDRequest) (*storagepb.ClearDirtyIssuesByIDResponse, error) {
+	if err := s.store.ClearDirtyIssuesByID(ctx, req.GetIssueIds()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.ClearDirtyIssuesByIDResponse{}, nil
+}
+
+func (s *server) GetExportHash(ctx context.Context, req *storagepb.GetExportHashRequest) (*storagepb.GetExportHashResponse, error) {
+	hash, err := s.store.GetExportHash(ctx, req.GetIssueId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetExportHashResponse{ContentHash: hash}, nil
+}
+
+func (s *server) SetExportHash(ctx context.Context, req *storagepb.SetExportHashRequest) (*storagepb.SetExportHashResponse, error) {
+	if err := s.store.SetExportHash(ctx, req.GetIssueId(), req.GetContentHash()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.SetExportHashResponse{}, nil
+}
+
+func (s *server) ClearAllExportHashes(ctx context.Context, req *storagepb.ClearAllExportHashesRequest) (*storagepb.ClearAllExportHashesResponse, error) {
+	if err := s.store.ClearAllExportHashes(ctx); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.ClearAllExportHashesResponse{}, nil
+}
+
+func (s *server) GetJSONLFileHash(ctx context.Context, req *storagepb.GetJSONLFileHashRequest) (*storagepb.GetJSONLFileHashResponse, error) {
+	hash, err := s.store.GetJSONLFileHash(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetJSONLFileHashResponse{FileHash: hash}, nil
+}
+
+func (s *server) SetJSONLFileHash(ctx context.Context, req *storagepb.SetJSONLFileHashRequest) (*storagepb.SetJSONLFileHashResponse, error) {
+	if err := s.store.SetJSONLFileHash(ctx, req.GetFileHash()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.SetJSONLFileHashResponse{}, nil
+}
+
+func (s *server) GetNextChildID(ctx context.Context, req *storagepb.GetNextChildIDRequest) (*storagepb.GetNextChildIDResponse, error) {
+	id, err := s.store.GetNextChildID(ctx, req.GetParentId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetNextChildIDResponse{Id: id}, nil
+}
+
+func (s *server) SetConfig(ctx context.Context, req *storagepb.SetConfigRequest) (*storagepb.SetConfigResponse, error) {
+	if err := s.store.SetConfig(ctx, req.GetKey(), req.GetValue()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.SetConfigResponse{}, nil
+}
+
+func (s *server) GetConfig(ctx context.Context, req *storagepb.GetConfigRequest) (*storagepb.GetConfigResponse, error) {
+	value, err := s.store.GetConfig(ctx, req.GetKey())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetConfigResponse{Value: value}, nil
+}
+
+func (s *server) GetAllConfig(ctx context.Context, req *storagepb.GetAllConfigRequest) (*storagepb.GetAllConfigResponse, error) {
+	config, err := s.store.GetAllConfig(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetAllConfigResponse{Config: config}, nil
+}
+
+func (s *server) DeleteConfig(ctx context.Context, req *storagepb.DeleteConfigRequest) (*storagepb.DeleteConfigResponse, error) {
+	if err := s.store.DeleteConfig(ctx, req.GetKey()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.DeleteConfigResponse{}, nil
+}
+
+func (s *server) GetCustomStatuses(ctx context.Context, req *storagepb.GetCustomStatusesRequest) (*storagepb.GetCustomStatusesResponse, error) {
+	statuses, err := s.store.GetCustomStatuses(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetCustomStatusesResponse{Statuses: statuses}, nil
+}
+
+func (s *server) GetCustomTypes(ctx context.Context, req *storagepb.GetCustomTypesRequest) (*storagepb.GetCustomTypesResponse, error) {
+	customTypes, err := s.store.GetCustomTypes(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetCustomTypesResponse{Types: customTypes}, nil
+}
+
+func (s *server) SetMetadata(ctx context.Context, req *storagepb.SetMetadataRequest) (*storagepb.SetMetadataResponse, error) {
+	if err := s.store.SetMetadata(ctx, req.GetKey(), req.GetValue()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.SetMetadataResponse{}, nil
+}
+
+func (s *server) GetMetadata(ctx context.Context, req *storagepb.GetMetadataRequest) (*storagepb.GetMetadataResponse, error) {
+	value, err := s.store.GetMetadata(ctx, req.GetKey())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.GetMetadataResponse{Value: value}, nil
+}
+
+func (s *server) UpdateIssueID(ctx context.Context, req *storagepb.UpdateIssueIDRequest) (*storagepb.UpdateIssueIDResponse, error) {
+	issue, err := argIssue(req.GetIssue())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.UpdateIssueID(ctx, req.GetOldId(), req.GetNewId(), issue, req.GetActor()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.UpdateIssueIDResponse{}, nil
+}
+
+func (s *server) RenameDependencyPrefix(ctx context.Context, req *storagepb.RenameDependencyPrefixRequest) (*storagepb.RenameDependencyPrefixResponse, error) {
+	if err := s.store.RenameDependencyPrefix(ctx, req.GetOldPrefix(), req.GetNewPrefix()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.RenameDependencyPrefixResponse{}, nil
+}
+
+func (s *server) RenameCounterPrefix(ctx context.Context, req *storagepb.RenameCounterPrefixRequest) (*storagepb.RenameCounterPrefixResponse, error) {
+	if err := s.store.RenameCounterPrefix(ctx, req.GetOldPrefix(), req.GetNewPrefix()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &storagepb.RenameCounterPrefixResponse{}, nil
+}
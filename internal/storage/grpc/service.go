@@ -0,0 +1,99 @@
+// Package grpc serves a storage.Storage over gRPC, so thin clients can share
+// a central Beads store without direct database access.
+//
+// The service, beads.storage.v1.Storage, is defined in storagepb/storage.proto
+// with one unary RPC per Storage method, named after it. Each types package
+// struct has a message with the same fields, and the updates of UpdateIssue
+// travel as an IssueUpdates message with one typed field per update key, so
+// the server hands the wrapped store values of the Go types it expects.
+//
+// RunInTransaction, UnderlyingDB, and UnderlyingConn cannot cross the wire
+// and are not served; Client returns errors for them.
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/steveyegge/beads/internal/storage"
+)
+
+// ServiceName is the full name of the gRPC service.
+const ServiceName = "beads.storage.v1.Storage"
+
+// errorDomain identifies the ErrorInfo details attached by the server.
+const errorDomain = "beads.storage"
+
+// sentinelErrors are the storage errors that keep their identity over the
+// wire: the client's errors match them with errors.Is.
+var sentinelErrors = []struct {
+	reason string
+	code   codes.Code
+	err    error
+}{
+	{"STORE_CLOSED", codes.Unavailable, storage.ErrStoreClosed},
+	{"CIRCUIT_OPEN", codes.Unavailable, storage.ErrCircuitOpen},
+	{"READ_ONLY", codes.FailedPrecondition, storage.ErrReadOnly},
+	{"VERSION_CONFLICT", codes.Aborted, storage.ErrVersionConflict},
+	{"DEPENDENCY_CYCLE", codes.FailedPrecondition, storage.ErrDependencyCycle},
+	{"ALREADY_CLAIMED", codes.FailedPrecondition, storage.ErrAlreadyClaimed},
+}
+
+// toStatus converts an error returned by the wrapped storage into a gRPC
+// status error, tagging sentinel errors with their reason.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	for _, s := range sentinelErrors {
+		if errors.Is(err, s.err) {
+			st, detailErr := status.New(s.code, err.Error()).WithDetails(&errdetails.ErrorInfo{Reason: s.reason, Domain: errorDomain})
+			if detailErr != nil {
+				return status.Error(s.code, err.Error())
+			}
+			return st.Err()
+		}
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+// fromStatus converts a gRPC error back into a storage error. Errors tagged
+// with a sentinel reason wrap that sentinel; others are returned as is.
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || err == nil {
+		return err
+	}
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.Domain != errorDomain {
+			continue
+		}
+		for _, s := range sentinelErrors {
+			if s.reason == info.Reason {
+				return &remoteError{msg: st.Message(), err: s.err}
+			}
+		}
+	}
+	return err
+}
+
+// remoteError is a sentinel storage error received from the server, carrying
+// the server's full message.
+type remoteError struct {
+	msg string
+	err error
+}
+
+func (e *remoteError) Error() string { return e.msg }
+func (e *remoteError) Unwrap() error { return e.err }
@@ -0,0 +1,6 @@
+// Package storagepb holds the protocol buffer definition of the Beads
+// storage service, beads.storage.v1.Storage, and the Go code generated from
+// it. Edit storage.proto and run go generate to update the generated files.
+package storagepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative storage.proto
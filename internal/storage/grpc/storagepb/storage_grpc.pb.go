@@ -0,0 +1,2478 @@
+// Storage service for thin Beads clients sharing a central store.
+//
+// There is one RPC per method of storage.Storage, named after it, with its
+// arguments after ctx in the request and its results before error in the
+// response. Each struct from the Go types package has a message of the same
+// name with the same fields; named string types (statuses, issue types and
+// so on) are plain strings, and unset timestamps stand for zero times.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: storage.proto
+
+package storagepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	Storage_CreateIssue_FullMethodName                   = "/beads.storage.v1.Storage/CreateIssue"
+	Storage_CreateIssues_FullMethodName                  = "/beads.storage.v1.Storage/CreateIssues"
+	Storage_CreateIssuesWithFullOptions_FullMethodName   = "/beads.storage.v1.Storage/CreateIssuesWithFullOptions"
+	Storage_GetIssue_FullMethodName                      = "/beads.storage.v1.Storage/GetIssue"
+	Storage_GetIssueByExternalRef_FullMethodName         = "/beads.storage.v1.Storage/GetIssueByExternalRef"
+	Storage_UpdateIssue_FullMethodName                   = "/beads.storage.v1.Storage/UpdateIssue"
+	Storage_ClaimIssue_FullMethodName                    = "/beads.storage.v1.Storage/ClaimIssue"
+	Storage_CloseIssue_FullMethodName                    = "/beads.storage.v1.Storage/CloseIssue"
+	Storage_DeleteIssue_FullMethodName                   = "/beads.storage.v1.Storage/DeleteIssue"
+	Storage_SearchIssues_FullMethodName                  = "/beads.storage.v1.Storage/SearchIssues"
+	Storage_AddDependency_FullMethodName                 = "/beads.storage.v1.Storage/AddDependency"
+	Storage_RemoveDependency_FullMethodName              = "/beads.storage.v1.Storage/RemoveDependency"
+	Storage_GetDependencies_FullMethodName               = "/beads.storage.v1.Storage/GetDependencies"
+	Storage_GetDependents_FullMethodName                 = "/beads.storage.v1.Storage/GetDependents"
+	Storage_GetDependenciesWithMetadata_FullMethodName   = "/beads.storage.v1.Storage/GetDependenciesWithMetadata"
+	Storage_GetDependentsWithMetadata_FullMethodName     = "/beads.storage.v1.Storage/GetDependentsWithMetadata"
+	Storage_GetDependencyRecords_FullMethodName          = "/beads.storage.v1.Storage/GetDependencyRecords"
+	Storage_GetAllDependencyRecords_FullMethodName       = "/beads.storage.v1.Storage/GetAllDependencyRecords"
+	Storage_GetDependencyRecordsForIssues_FullMethodName = "/beads.storage.v1.Storage/GetDependencyRecordsForIssues"
+	Storage_GetDependencyCounts_FullMethodName           = "/beads.storage.v1.Storage/GetDependencyCounts"
+	Storage_GetDependencyTree_FullMethodName             = "/beads.storage.v1.Storage/GetDependencyTree"
+	Storage_DetectCycles_FullMethodName                  = "/beads.storage.v1.Storage/DetectCycles"
+	Storage_AddLabel_FullMethodName                      = "/beads.storage.v1.Storage/AddLabel"
+	Storage_RemoveLabel_FullMethodName                   = "/beads.storage.v1.Storage/RemoveLabel"
+	Storage_GetLabels_FullMethodName                     = "/beads.storage.v1.Storage/GetLabels"
+	Storage_GetLabelsForIssues_FullMethodName            = "/beads.storage.v1.Storage/GetLabelsForIssues"
+	Storage_GetIssuesByLabel_FullMethodName              = "/beads.storage.v1.Storage/GetIssuesByLabel"
+	Storage_GetReadyWork_FullMethodName                  = "/beads.storage.v1.Storage/GetReadyWork"
+	Storage_GetBlockedIssues_FullMethodName              = "/beads.storage.v1.Storage/GetBlockedIssues"
+	Storage_IsBlocked_FullMethodName                     = "/beads.storage.v1.Storage/IsBlocked"
+	Storage_GetEpicsEligibleForClosure_FullMethodName    = "/beads.storage.v1.Storage/GetEpicsEligibleForClosure"
+	Storage_GetStaleIssues_FullMethodName                = "/beads.storage.v1.Storage/GetStaleIssues"
+	Storage_GetNewlyUnblockedByClose_FullMethodName      = "/beads.storage.v1.Storage/GetNewlyUnblockedByClose"
+	Storage_AddComment_FullMethodName                    = "/beads.storage.v1.Storage/AddComment"
+	Storage_GetEvents_FullMethodName                     = "/beads.storage.v1.Storage/GetEvents"
+	Storage_GetAllEventsSince_FullMethodName             = "/beads.storage.v1.Storage/GetAllEventsSince"
+	Storage_AddIssueComment_FullMethodName               = "/beads.storage.v1.Storage/AddIssueComment"
+	Storage_ImportIssueComment_FullMethodName            = "/beads.storage.v1.Storage/ImportIssueComment"
+	Storage_GetIssueComments_FullMethodName              = "/beads.storage.v1.Storage/GetIssueComments"
+	Storage_GetCommentsForIssues_FullMethodName          = "/beads.storage.v1.Storage/GetCommentsForIssues"
+	Storage_GetCommentCounts_FullMethodName              = "/beads.storage.v1.Storage/GetCommentCounts"
+	Storage_GetStatistics_FullMethodName                 = "/beads.storage.v1.Storage/GetStatistics"
+	Storage_GetMoleculeProgress_FullMethodName           = "/beads.storage.v1.Storage/GetMoleculeProgress"
+	Storage_GetDirtyIssues_FullMethodName                = "/beads.storage.v1.Storage/GetDirtyIssues"
+	Storage_GetDirtyIssueHash_FullMethodName             = "/beads.storage.v1.Storage/GetDirtyIssueHash"
+	Storage_ClearDirtyIssuesByID_FullMethodName          = "/beads.storage.v1.Storage/ClearDirtyIssuesByID"
+	Storage_GetExportHash_FullMethodName                 = "/beads.storage.v1.Storage/GetExportHash"
+	Storage_SetExportHash_FullMethodName                 = "/beads.storage.v1.Storage/SetExportHash"
+	Storage_ClearAllExportHashes_FullMethodName          = "/beads.storage.v1.Storage/ClearAllExportHashes"
+	Storage_GetJSONLFileHash_FullMethodName              = "/beads.storage.v1.Storage/GetJSONLFileHash"
+	Storage_SetJSONLFileHash_FullMethodName              = "/beads.storage.v1.Storage/SetJSONLFileHash"
+	Storage_GetNextChildID_FullMethodName                = "/beads.storage.v1.Storage/GetNextChildID"
+	Storage_SetConfig_FullMethodName                     = "/beads.storage.v1.Storage/SetConfig"
+	Storage_GetConfig_FullMethodName                     = "/beads.storage.v1.Storage/GetConfig"
+	Storage_GetAllConfig_FullMethodName                  = "/beads.storage.v1.Storage/GetAllConfig"
+	Storage_DeleteConfig_FullMethodName                  = "/beads.storage.v1.Storage/DeleteConfig"
+	Storage_GetCustomStatuses_FullMethodName             = "/beads.storage.v1.Storage/GetCustomStatuses"
+	Storage_GetCustomTypes_FullMethodName                = "/beads.storage.v1.Storage/GetCustomTypes"
+	Storage_SetMetadata_FullMethodName                   = "/beads.storage.v1.Storage/SetMetadata"
+	Storage_GetMetadata_FullMethodName                   = "/beads.storage.v1.Storage/GetMetadata"
+	Storage_UpdateIssueID_FullMethodName                 = "/beads.storage.v1.Storage/UpdateIssueID"
+	Storage_RenameDependencyPrefix_FullMethodName        = "/beads.storage.v1.Storage/RenameDependencyPrefix"
+	Storage_RenameCounterPrefix_FullMethodName           = "/beads.storage.v1.Storage/RenameCounterPrefix"
+)
+
+// StorageClient is the client API for Storage service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Storage serves the methods of a storage.Storage backend.
+type StorageClient interface {
+	CreateIssue(ctx context.Context, in *CreateIssueRequest, opts ...grpc.CallOption) (*CreateIssueResponse, error)
+	CreateIssues(ctx context.Context, in *CreateIssuesRequest, opts ...grpc.CallOption) (*CreateIssuesResponse, error)
+	CreateIssuesWithFullOptions(ctx context.Context, in *CreateIssuesWithFullOptionsRequest, opts ...grpc.CallOption) (*CreateIssuesWithFullOptionsResponse, error)
+	GetIssue(ctx context.Context, in *GetIssueRequest, opts ...grpc.CallOption) (*GetIssueResponse, error)
+	GetIssueByExternalRef(ctx context.Context, in *GetIssueByExternalRefRequest, opts ...grpc.CallOption) (*GetIssueByExternalRefResponse, error)
+	UpdateIssue(ctx context.Context, in *UpdateIssueRequest, opts ...grpc.CallOption) (*UpdateIssueResponse, error)
+	ClaimIssue(ctx context.Context, in *ClaimIssueRequest, opts ...grpc.CallOption) (*ClaimIssueResponse, error)
+	CloseIssue(ctx context.Context, in *CloseIssueRequest, opts ...grpc.CallOption) (*CloseIssueResponse, error)
+	DeleteIssue(ctx context.Context, in *DeleteIssueRequest, opts ...grpc.CallOption) (*DeleteIssueResponse, error)
+	SearchIssues(ctx context.Context, in *SearchIssuesRequest, opts ...grpc.CallOption) (*SearchIssuesResponse, error)
+	AddDependency(ctx context.Context, in *AddDependencyRequest, opts ...grpc.CallOption) (*AddDependencyResponse, error)
+	RemoveDependency(ctx context.Context, in *RemoveDependencyRequest, opts ...grpc.CallOption) (*RemoveDependencyResponse, error)
+	GetDependencies(ctx context.Context, in *GetDependenciesRequest, opts ...grpc.CallOption) (*GetDependenciesResponse, error)
+	GetDependents(ctx context.Context, in *GetDependentsRequest, opts ...grpc.CallOption) (*GetDependentsResponse, error)
+	GetDependenciesWithMetadata(ctx context.Context, in *GetDependenciesWithMetadataRequest, opts ...grpc.CallOption) (*GetDependenciesWithMetadataResponse, error)
+	GetDependentsWithMetadata(ctx context.Context, in *GetDependentsWithMetadataRequest, opts ...grpc.CallOption) (*GetDependentsWithMetadataResponse, error)
+	GetDependencyRecords(ctx context.Context, in *GetDependencyRecordsRequest, opts ...grpc.CallOption) (*GetDependencyRecordsResponse, error)
+	GetAllDependencyRecords(ctx context.Context, in *GetAllDependencyRecordsRequest, opts ...grpc.CallOption) (*GetAllDependencyRecordsResponse, error)
+	GetDependencyRecordsForIssues(ctx context.Context, in *GetDependencyRecordsForIssuesRequest, opts ...grpc.CallOption) (*GetDependencyRecordsForIssuesResponse, error)
+	GetDependencyCounts(ctx context.Context, in *GetDependencyCountsRequest, opts ...grpc.CallOption) (*GetDependencyCountsResponse, error)
+	GetDependencyTree(ctx context.Context, in *GetDependencyTreeRequest, opts ...grpc.CallOption) (*GetDependencyTreeResponse, error)
+	DetectCycles(ctx context.Context, in *DetectCyclesRequest, opts ...grpc.CallOption) (*DetectCyclesResponse, error)
+	AddLabel(ctx context.Context, in *AddLabelRequest, opts ...grpc.CallOption) (*AddLabelResponse, error)
+	RemoveLabel(ctx context.Context, in *RemoveLabelRequest, opts ...grpc.CallOption) (*RemoveLabelResponse, error)
+	GetLabels(ctx context.Context, in *GetLabelsRequest, opts ...grpc.CallOption) (*GetLabelsResponse, error)
+	GetLabelsForIssues(ctx context.Context, in *GetLabelsForIssuesRequest, opts ...grpc.CallOption) (*GetLabelsForIssuesResponse, error)
+	GetIssuesByLabel(ctx context.Context, in *GetIssuesByLabelRequest, opts ...grpc.CallOption) (*GetIssuesByLabelResponse, error)
+	GetReadyWork(ctx context.Context, in *GetReadyWorkRequest, opts ...grpc.CallOption) (*GetReadyWorkResponse, error)
+	GetBlockedIssues(ctx context.Context, in *GetBlockedIssuesRequest, opts ...grpc.CallOption) (*GetBlockedIssuesResponse, error)
+	IsBlocked(ctx context.Context, in *IsBlockedRequest, opts ...grpc.CallOption) (*IsBlockedResponse, error)
+	GetEpicsEligibleForClosure(ctx context.Context, in *GetEpicsEligibleForClosureRequest, opts ...grpc.CallOption) (*GetEpicsEligibleForClosureResponse, error)
+	GetStaleIssues(ctx context.Context, in *GetStaleIssuesRequest, opts ...grpc.CallOption) (*GetStaleIssuesResponse, error)
+	GetNewlyUnblockedByClose(ctx context.Context, in *GetNewlyUnblockedByCloseRequest, opts ...grpc.CallOption) (*GetNewlyUnblockedByCloseResponse, error)
+	AddComment(ctx context.Context, in *AddCommentRequest, opts ...grpc.CallOption) (*AddCommentResponse, error)
+	GetEvents(ctx context.Context, in *GetEventsRequest, opts ...grpc.CallOption) (*GetEventsResponse, error)
+	GetAllEventsSince(ctx context.Context, in *GetAllEventsSinceRequest, opts ...grpc.CallOption) (*GetAllEventsSinceResponse, error)
+	AddIssueComment(ctx context.Context, in *AddIssueCommentRequest, opts ...grpc.CallOption) (*AddIssueCommentResponse, error)
+	ImportIssueComment(ctx context.Context, in *ImportIssueCommentRequest, opts ...grpc.CallOption) (*ImportIssueCommentResponse, error)
+	GetIssueComments(ctx context.Context, in *GetIssueCommentsRequest, opts ...grpc.CallOption) (*GetIssueCommentsResponse, error)
+	GetCommentsForIssues(ctx context.Context, in *GetCommentsForIssuesRequest, opts ...grpc.CallOption) (*GetCommentsForIssuesResponse, error)
+	GetCommentCounts(ctx context.Context, in *GetCommentCountsRequest, opts ...grpc.CallOption) (*GetCommentCountsResponse, error)
+	GetStatistics(ctx context.Context, in *GetStatisticsRequest, opts ...grpc.CallOption) (*GetStatisticsResponse, error)
+	GetMoleculeProgress(ctx context.Context, in *GetMoleculeProgressRequest, opts ...grpc.CallOption) (*GetMoleculeProgressResponse, error)
+	GetDirtyIssues(ctx context.Context, in *GetDirtyIssuesRequest, opts ...grpc.CallOption) (*GetDirtyIssuesResponse, error)
+	GetDirtyIssueHash(ctx context.Context, in *GetDirtyIssueHashRequest, opts ...grpc.CallOption) (*GetDirtyIssueHashResponse, error)
+	ClearDirtyIssuesByID(ctx context.Context, in *ClearDirtyIssuesByIDRequest, opts ...grpc.CallOption) (*ClearDirtyIssuesByIDResponse, error)
+	GetExportHash(ctx context.Context, in *GetExportHashRequest, opts ...grpc.CallOption) (*GetExportHashResponse, error)
+	SetExportHash(ctx context.Context, in *SetExportHashRequest, opts ...grpc.CallOption) (*SetExportHashResponse, error)
+	ClearAllExportHashes(ctx context.Context, in *ClearAllExportHashesRequest, opts ...grpc.CallOption) (*ClearAllExportHashesResponse, error)
+	GetJSONLFileHash(ctx context.Context, in *GetJSONLFileHashRequest, opts ...grpc.CallOption) (*GetJSONLFileHashResponse, error)
+	SetJSONLFileHash(ctx context.Context, in *SetJSONLFileHashRequest, opts ...grpc.CallOption) (*SetJSONLFileHashResponse, error)
+	GetNextChildID(ctx context.Context, in *GetNextChildIDRequest, opts ...grpc.CallOption) (*GetNextChildIDResponse, error)
+	SetConfig(ctx context.Context, in *SetConfigRequest, opts ...grpc.CallOption) (*SetConfigResponse, error)
+	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigResponse, error)
+	GetAllConfig(ctx context.Context, in *GetAllConfigRequest, opts ...grpc.CallOption) (*GetAllConfigResponse, error)
+	DeleteConfig(ctx context.Context, in *DeleteConfigRequest, opts ...grpc.CallOption) (*DeleteConfigResponse, error)
+	GetCustomStatuses(ctx context.Context, in *GetCustomStatusesRequest, opts ...grpc.CallOption) (*GetCustomStatusesResponse, error)
+	GetCustomTypes(ctx context.Context, in *GetCustomTypesRequest, opts ...grpc.CallOption) (*GetCustomTypesResponse, error)
+	SetMetadata(ctx context.Context, in *SetMetadataRequest, opts ...grpc.CallOption) (*SetMetadataResponse, error)
+	GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error)
+	UpdateIssueID(ctx context.Context, in *UpdateIssueIDRequest, opts ...grpc.CallOption) (*UpdateIssueIDResponse, error)
+	RenameDependencyPrefix(ctx context.Context, in *RenameDependencyPrefixRequest, opts ...grpc.CallOption) (*RenameDependencyPrefixResponse, error)
+	RenameCounterPrefix(ctx context.Context, in *RenameCounterPrefixRequest, opts ...grpc.CallOption) (*RenameCounterPrefixResponse, error)
+}
+
+type storageClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStorageClient(cc grpc.ClientConnInterface) StorageClient {
+	return &storageClient{cc}
+}
+
+func (c *storageClient) CreateIssue(ctx context.Context, in *CreateIssueRequest, opts ...grpc.CallOption) (*CreateIssueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateIssueResponse)
+	err := c.cc.Invoke(ctx, Storage_CreateIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) CreateIssues(ctx context.Context, in *CreateIssuesRequest, opts ...grpc.CallOption) (*CreateIssuesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateIssuesResponse)
+	err := c.cc.Invoke(ctx, Storage_CreateIssues_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) CreateIssuesWithFullOptions(ctx context.Context, in *CreateIssuesWithFullOptionsRequest, opts ...grpc.CallOption) (*CreateIssuesWithFullOptionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateIssuesWithFullOptionsResponse)
+	err := c.cc.Invoke(ctx, Storage_CreateIssuesWithFullOptions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetIssue(ctx context.Context, in *GetIssueRequest, opts ...grpc.CallOption) (*GetIssueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIssueResponse)
+	err := c.cc.Invoke(ctx, Storage_GetIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetIssueByExternalRef(ctx context.Context, in *GetIssueByExternalRefRequest, opts ...grpc.CallOption) (*GetIssueByExternalRefResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIssueByExternalRefResponse)
+	err := c.cc.Invoke(ctx, Storage_GetIssueByExternalRef_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) UpdateIssue(ctx context.Context, in *UpdateIssueRequest, opts ...grpc.CallOption) (*UpdateIssueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateIssueResponse)
+	err := c.cc.Invoke(ctx, Storage_UpdateIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) ClaimIssue(ctx context.Context, in *ClaimIssueRequest, opts ...grpc.CallOption) (*ClaimIssueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClaimIssueResponse)
+	err := c.cc.Invoke(ctx, Storage_ClaimIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) CloseIssue(ctx context.Context, in *CloseIssueRequest, opts ...grpc.CallOption) (*CloseIssueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseIssueResponse)
+	err := c.cc.Invoke(ctx, Storage_CloseIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) DeleteIssue(ctx context.Context, in *DeleteIssueRequest, opts ...grpc.CallOption) (*DeleteIssueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteIssueResponse)
+	err := c.cc.Invoke(ctx, Storage_DeleteIssue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SearchIssues(ctx context.Context, in *SearchIssuesRequest, opts ...grpc.CallOption) (*SearchIssuesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchIssuesResponse)
+	err := c.cc.Invoke(ctx, Storage_SearchIssues_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) AddDependency(ctx context.Context, in *AddDependencyRequest, opts ...grpc.CallOption) (*AddDependencyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddDependencyResponse)
+	err := c.cc.Invoke(ctx, Storage_AddDependency_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) RemoveDependency(ctx context.Context, in *RemoveDependencyRequest, opts ...grpc.CallOption) (*RemoveDependencyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveDependencyResponse)
+	err := c.cc.Invoke(ctx, Storage_RemoveDependency_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetDependencies(ctx context.Context, in *GetDependenciesRequest, opts ...grpc.CallOption) (*GetDependenciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDependenciesResponse)
+	err := c.cc.Invoke(ctx, Storage_GetDependencies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetDependents(ctx context.Context, in *GetDependentsRequest, opts ...grpc.CallOption) (*GetDependentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDependentsResponse)
+	err := c.cc.Invoke(ctx, Storage_GetDependents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetDependenciesWithMetadata(ctx context.Context, in *GetDependenciesWithMetadataRequest, opts ...grpc.CallOption) (*GetDependenciesWithMetadataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDependenciesWithMetadataResponse)
+	err := c.cc.Invoke(ctx, Storage_GetDependenciesWithMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetDependentsWithMetadata(ctx context.Context, in *GetDependentsWithMetadataRequest, opts ...grpc.CallOption) (*GetDependentsWithMetadataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDependentsWithMetadataResponse)
+	err := c.cc.Invoke(ctx, Storage_GetDependentsWithMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetDependencyRecords(ctx context.Context, in *GetDependencyRecordsRequest, opts ...grpc.CallOption) (*GetDependencyRecordsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDependencyRecordsResponse)
+	err := c.cc.Invoke(ctx, Storage_GetDependencyRecords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetAllDependencyRecords(ctx context.Context, in *GetAllDependencyRecordsRequest, opts ...grpc.CallOption) (*GetAllDependencyRecordsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAllDependencyRecordsResponse)
+	err := c.cc.Invoke(ctx, Storage_GetAllDependencyRecords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetDependencyRecordsForIssues(ctx context.Context, in *GetDependencyRecordsForIssuesRequest, opts ...grpc.CallOption) (*GetDependencyRecordsForIssuesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDependencyRecordsForIssuesResponse)
+	err := c.cc.Invoke(ctx, Storage_GetDependencyRecordsForIssues_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetDependencyCounts(ctx context.Context, in *GetDependencyCountsRequest, opts ...grpc.CallOption) (*GetDependencyCountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDependencyCountsResponse)
+	err := c.cc.Invoke(ctx, Storage_GetDependencyCounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetDependencyTree(ctx context.Context, in *GetDependencyTreeRequest, opts ...grpc.CallOption) (*GetDependencyTreeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDependencyTreeResponse)
+	err := c.cc.Invoke(ctx, Storage_GetDependencyTree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) DetectCycles(ctx context.Context, in *DetectCyclesRequest, opts ...grpc.CallOption) (*DetectCyclesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DetectCyclesResponse)
+	err := c.cc.Invoke(ctx, Storage_DetectCycles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) AddLabel(ctx context.Context, in *AddLabelRequest, opts ...grpc.CallOption) (*AddLabelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddLabelResponse)
+	err := c.cc.Invoke(ctx, Storage_AddLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) RemoveLabel(ctx context.Context, in *RemoveLabelRequest, opts ...grpc.CallOption) (*RemoveLabelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveLabelResponse)
+	err := c.cc.Invoke(ctx, Storage_RemoveLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetLabels(ctx context.Context, in *GetLabelsRequest, opts ...grpc.CallOption) (*GetLabelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLabelsResponse)
+	err := c.cc.Invoke(ctx, Storage_GetLabels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetLabelsForIssues(ctx context.Context, in *GetLabelsForIssuesRequest, opts ...grpc.CallOption) (*GetLabelsForIssuesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLabelsForIssuesResponse)
+	err := c.cc.Invoke(ctx, Storage_GetLabelsForIssues_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetIssuesByLabel(ctx context.Context, in *GetIssuesByLabelRequest, opts ...grpc.CallOption) (*GetIssuesByLabelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIssuesByLabelResponse)
+	err := c.cc.Invoke(ctx, Storage_GetIssuesByLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetReadyWork(ctx context.Context, in *GetReadyWorkRequest, opts ...grpc.CallOption) (*GetReadyWorkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetReadyWorkResponse)
+	err := c.cc.Invoke(ctx, Storage_GetReadyWork_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetBlockedIssues(ctx context.Context, in *GetBlockedIssuesRequest, opts ...grpc.CallOption) (*GetBlockedIssuesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBlockedIssuesResponse)
+	err := c.cc.Invoke(ctx, Storage_GetBlockedIssues_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) IsBlocked(ctx context.Context, in *IsBlockedRequest, opts ...grpc.CallOption) (*IsBlockedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IsBlockedResponse)
+	err := c.cc.Invoke(ctx, Storage_IsBlocked_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetEpicsEligibleForClosure(ctx context.Context, in *GetEpicsEligibleForClosureRequest, opts ...grpc.CallOption) (*GetEpicsEligibleForClosureResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEpicsEligibleForClosureResponse)
+	err := c.cc.Invoke(ctx, Storage_GetEpicsEligibleForClosure_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetStaleIssues(ctx context.Context, in *GetStaleIssuesRequest, opts ...grpc.CallOption) (*GetStaleIssuesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStaleIssuesResponse)
+	err := c.cc.Invoke(ctx, Storage_GetStaleIssues_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetNewlyUnblockedByClose(ctx context.Context, in *GetNewlyUnblockedByCloseRequest, opts ...grpc.CallOption) (*GetNewlyUnblockedByCloseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetNewlyUnblockedByCloseResponse)
+	err := c.cc.Invoke(ctx, Storage_GetNewlyUnblockedByClose_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) AddComment(ctx context.Context, in *AddCommentRequest, opts ...grpc.CallOption) (*AddCommentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddCommentResponse)
+	err := c.cc.Invoke(ctx, Storage_AddComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetEvents(ctx context.Context, in *GetEventsRequest, opts ...grpc.CallOption) (*GetEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEventsResponse)
+	err := c.cc.Invoke(ctx, Storage_GetEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetAllEventsSince(ctx context.Context, in *GetAllEventsSinceRequest, opts ...grpc.CallOption) (*GetAllEventsSinceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAllEventsSinceResponse)
+	err := c.cc.Invoke(ctx, Storage_GetAllEventsSince_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) AddIssueComment(ctx context.Context, in *AddIssueCommentRequest, opts ...grpc.CallOption) (*AddIssueCommentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddIssueCommentResponse)
+	err := c.cc.Invoke(ctx, Storage_AddIssueComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) ImportIssueComment(ctx context.Context, in *ImportIssueCommentRequest, opts ...grpc.CallOption) (*ImportIssueCommentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportIssueCommentResponse)
+	err := c.cc.Invoke(ctx, Storage_ImportIssueComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetIssueComments(ctx context.Context, in *GetIssueCommentsRequest, opts ...grpc.CallOption) (*GetIssueCommentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetIssueCommentsResponse)
+	err := c.cc.Invoke(ctx, Storage_GetIssueComments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetCommentsForIssues(ctx context.Context, in *GetCommentsForIssuesRequest, opts ...grpc.CallOption) (*GetCommentsForIssuesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCommentsForIssuesResponse)
+	err := c.cc.Invoke(ctx, Storage_GetCommentsForIssues_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetCommentCounts(ctx context.Context, in *GetCommentCountsRequest, opts ...grpc.CallOption) (*GetCommentCountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCommentCountsResponse)
+	err := c.cc.Invoke(ctx, Storage_GetCommentCounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetStatistics(ctx context.Context, in *GetStatisticsRequest, opts ...grpc.CallOption) (*GetStatisticsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatisticsResponse)
+	err := c.cc.Invoke(ctx, Storage_GetStatistics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetMoleculeProgress(ctx context.Context, in *GetMoleculeProgressRequest, opts ...grpc.CallOption) (*GetMoleculeProgressResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMoleculeProgressResponse)
+	err := c.cc.Invoke(ctx, Storage_GetMoleculeProgress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetDirtyIssues(ctx context.Context, in *GetDirtyIssuesRequest, opts ...grpc.CallOption) (*GetDirtyIssuesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDirtyIssuesResponse)
+	err := c.cc.Invoke(ctx, Storage_GetDirtyIssues_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetDirtyIssueHash(ctx context.Context, in *GetDirtyIssueHashRequest, opts ...grpc.CallOption) (*GetDirtyIssueHashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDirtyIssueHashResponse)
+	err := c.cc.Invoke(ctx, Storage_GetDirtyIssueHash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) ClearDirtyIssuesByID(ctx context.Context, in *ClearDirtyIssuesByIDRequest, opts ...grpc.CallOption) (*ClearDirtyIssuesByIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearDirtyIssuesByIDResponse)
+	err := c.cc.Invoke(ctx, Storage_ClearDirtyIssuesByID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetExportHash(ctx context.Context, in *GetExportHashRequest, opts ...grpc.CallOption) (*GetExportHashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetExportHashResponse)
+	err := c.cc.Invoke(ctx, Storage_GetExportHash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SetExportHash(ctx context.Context, in *SetExportHashRequest, opts ...grpc.CallOption) (*SetExportHashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetExportHashResponse)
+	err := c.cc.Invoke(ctx, Storage_SetExportHash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) ClearAllExportHashes(ctx context.Context, in *ClearAllExportHashesRequest, opts ...grpc.CallOption) (*ClearAllExportHashesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearAllExportHashesResponse)
+	err := c.cc.Invoke(ctx, Storage_ClearAllExportHashes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetJSONLFileHash(ctx context.Context, in *GetJSONLFileHashRequest, opts ...grpc.CallOption) (*GetJSONLFileHashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJSONLFileHashResponse)
+	err := c.cc.Invoke(ctx, Storage_GetJSONLFileHash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SetJSONLFileHash(ctx context.Context, in *SetJSONLFileHashRequest, opts ...grpc.CallOption) (*SetJSONLFileHashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetJSONLFileHashResponse)
+	err := c.cc.Invoke(ctx, Storage_SetJSONLFileHash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetNextChildID(ctx context.Context, in *GetNextChildIDRequest, opts ...grpc.CallOption) (*GetNextChildIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetNextChildIDResponse)
+	err := c.cc.Invoke(ctx, Storage_GetNextChildID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SetConfig(ctx context.Context, in *SetConfigRequest, opts ...grpc.CallOption) (*SetConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetConfigResponse)
+	err := c.cc.Invoke(ctx, Storage_SetConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetConfigResponse)
+	err := c.cc.Invoke(ctx, Storage_GetConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetAllConfig(ctx context.Context, in *GetAllConfigRequest, opts ...grpc.CallOption) (*GetAllConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAllConfigResponse)
+	err := c.cc.Invoke(ctx, Storage_GetAllConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) DeleteConfig(ctx context.Context, in *DeleteConfigRequest, opts ...grpc.CallOption) (*DeleteConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteConfigResponse)
+	err := c.cc.Invoke(ctx, Storage_DeleteConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetCustomStatuses(ctx context.Context, in *GetCustomStatusesRequest, opts ...grpc.CallOption) (*GetCustomStatusesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCustomStatusesResponse)
+	err := c.cc.Invoke(ctx, Storage_GetCustomStatuses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetCustomTypes(ctx context.Context, in *GetCustomTypesRequest, opts ...grpc.CallOption) (*GetCustomTypesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCustomTypesResponse)
+	err := c.cc.Invoke(ctx, Storage_GetCustomTypes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SetMetadata(ctx context.Context, in *SetMetadataRequest, opts ...grpc.CallOption) (*SetMetadataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetMetadataResponse)
+	err := c.cc.Invoke(ctx, Storage_SetMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMetadataResponse)
+	err := c.cc.Invoke(ctx, Storage_GetMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) UpdateIssueID(ctx context.Context, in *UpdateIssueIDRequest, opts ...grpc.CallOption) (*UpdateIssueIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateIssueIDResponse)
+	err := c.cc.Invoke(ctx, Storage_UpdateIssueID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) RenameDependencyPrefix(ctx context.Context, in *RenameDependencyPrefixRequest, opts ...grpc.CallOption) (*RenameDependencyPrefixResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenameDependencyPrefixResponse)
+	err := c.cc.Invoke(ctx, Storage_RenameDependencyPrefix_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) RenameCounterPrefix(ctx context.Context, in *RenameCounterPrefixRequest, opts ...grpc.CallOption) (*RenameCounterPrefixResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenameCounterPrefixResponse)
+	err := c.cc.Invoke(ctx, Storage_RenameCounterPrefix_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StorageServer is the server API for Storage service.
+// All implementations must embed UnimplementedStorageServer
+// for forward compatibility
+//
+// Storage serves the methods of a storage.Storage backend.
+type StorageServer interface {
+	CreateIssue(context.Context, *CreateIssueRequest) (*CreateIssueResponse, error)
+	CreateIssues(context.Context, *CreateIssuesRequest) (*CreateIssuesResponse, error)
+	CreateIssuesWithFullOptions(context.Context, *CreateIssuesWithFullOptionsRequest) (*CreateIssuesWithFullOptionsResponse, error)
+	GetIssue(context.Context, *GetIssueRequest) (*GetIssueResponse, error)
+	GetIssueByExternalRef(context.Context, *GetIssueByExternalRefRequest) (*GetIssueByExternalRefResponse, error)
+	UpdateIssue(context.Context, *UpdateIssueRequest) (*UpdateIssueResponse, error)
+	ClaimIssue(context.Context, *ClaimIssueRequest) (*ClaimIssueResponse, error)
+	CloseIssue(context.Context, *CloseIssueRequest) (*CloseIssueResponse, error)
+	DeleteIssue(context.Context, *DeleteIssueRequest) (*DeleteIssueResponse, error)
+	SearchIssues(context.Context, *SearchIssuesRequest) (*SearchIssuesResponse, error)
+	AddDependency(context.Context, *AddDependencyRequest) (*AddDependencyResponse, error)
+	RemoveDependency(context.Context, *RemoveDependencyRequest) (*RemoveDependencyResponse, error)
+	GetDependencies(context.Context, *GetDependenciesRequest) (*GetDependenciesResponse, error)
+	GetDependents(context.Context, *GetDependentsRequest) (*GetDependentsResponse, error)
+	GetDependenciesWithMetadata(context.Context, *GetDependenciesWithMetadataRequest) (*GetDependenciesWithMetadataResponse, error)
+	GetDependentsWithMetadata(context.Context, *GetDependentsWithMetadataRequest) (*GetDependentsWithMetadataResponse, error)
+	GetDependencyRecords(context.Context, *GetDependencyRecordsRequest) (*GetDependencyRecordsResponse, error)
+	GetAllDependencyRecords(context.Context, *GetAllDependencyRecordsRequest) (*GetAllDependencyRecordsResponse, error)
+	GetDependencyRecordsForIssues(context.Context, *GetDependencyRecordsForIssuesRequest) (*GetDependencyRecordsForIssuesResponse, error)
+	GetDependencyCounts(context.Context, *GetDependencyCountsRequest) (*GetDependencyCountsResponse, error)
+	GetDependencyTree(context.Context, *GetDependencyTreeRequest) (*GetDependencyTreeResponse, error)
+	DetectCycles(context.Context, *DetectCyclesRequest) (*DetectCyclesResponse, error)
+	AddLabel(context.Context, *AddLabelRequest) (*AddLabelResponse, error)
+	RemoveLabel(context.Context, *RemoveLabelRequest) (*RemoveLabelResponse, error)
+	GetLabels(context.Context, *GetLabelsRequest) (*GetLabelsResponse, error)
+	GetLabelsForIssues(context.Context, *GetLabelsForIssuesRequest) (*GetLabelsForIssuesResponse, error)
+	GetIssuesByLabel(context.Context, *GetIssuesByLabelRequest) (*GetIssuesByLabelResponse, error)
+	GetReadyWork(context.Context, *GetReadyWorkRequest) (*GetReadyWorkResponse, error)
+	GetBlockedIssues(context.Context, *GetBlockedIssuesRequest) (*GetBlockedIssuesResponse, error)
+	IsBlocked(context.Context, *IsBlockedRequest) (*IsBlockedResponse, error)
+	GetEpicsEligibleForClosure(context.Context, *GetEpicsEligibleForClosureRequest) (*GetEpicsEligibleForClosureResponse, error)
+	GetStaleIssues(context.Context, *GetStaleIssuesRequest) (*GetStaleIssuesResponse, error)
+	GetNewlyUnblockedByClose(context.Context, *GetNewlyUnblockedByCloseRequest) (*GetNewlyUnblockedByCloseResponse, error)
+	AddComment(context.Context, *AddCommentRequest) (*AddCommentResponse, error)
+	GetEvents(context.Context, *GetEventsRequest) (*GetEventsResponse, error)
+	GetAllEventsSince(context.Context, *GetAllEventsSinceRequest) (*GetAllEventsSinceResponse, error)
+	AddIssueComment(context.Context, *AddIssueCommentRequest) (*AddIssueCommentResponse, error)
+	ImportIssueComment(context.Context, *ImportIssueCommentRequest) (*ImportIssueCommentResponse, error)
+	GetIssueComments(context.Context, *GetIssueCommentsRequest) (*GetIssueCommentsResponse, error)
+	GetCommentsForIssues(context.Context, *GetCommentsForIssuesRequest) (*GetCommentsForIssuesResponse, error)
+	GetCommentCounts(context.Context, *GetCommentCountsRequest) (*GetCommentCountsResponse, error)
+	GetStatistics(context.Context, *GetStatisticsRequest) (*GetStatisticsResponse, error)
+	GetMoleculeProgress(context.Context, *GetMoleculeProgressRequest) (*GetMoleculeProgressResponse, error)
+	GetDirtyIssues(context.Context, *GetDirtyIssuesRequest) (*GetDirtyIssuesResponse, error)
+	GetDirtyIssueHash(context.Context, *GetDirtyIssueHashRequest) (*GetDirtyIssueHashResponse, error)
+	ClearDirtyIssuesByID(context.Context, *ClearDirtyIssuesByIDRequest) (*ClearDirtyIssuesByIDResponse, error)
+	GetExportHash(context.Context, *GetExportHashRequest) (*GetExportHashResponse, error)
+	SetExportHash(context.Context, *SetExportHashRequest) (*SetExportHashResponse, error)
+	ClearAllExportHashes(context.Context, *ClearAllExportHashesRequest) (*ClearAllExportHashesResponse, error)
+	GetJSONLFileHash(context.Context, *GetJSONLFileHashRequest) (*GetJSONLFileHashResponse, error)
+	SetJSONLFileHash(context.Context, *SetJSONLFileHashRequest) (*SetJSONLFileHashResponse, error)
+	GetNextChildID(context.Context, *GetNextChildIDRequest) (*GetNextChildIDResponse, error)
+	SetConfig(context.Context, *SetConfigRequest) (*SetConfigResponse, error)
+	GetConfig(context.Context, *GetConfigRequest) (*GetConfigResponse, error)
+	GetAllConfig(context.Context, *GetAllConfigRequest) (*GetAllConfigResponse, error)
+	DeleteConfig(context.Context, *DeleteConfigRequest) (*DeleteConfigResponse, error)
+	GetCustomStatuses(context.Context, *GetCustomStatusesRequest) (*GetCustomStatusesResponse, error)
+	GetCustomTypes(context.Context, *GetCustomTypesRequest) (*GetCustomTypesResponse, error)
+	SetMetadata(context.Context, *SetMetadataRequest) (*SetMetadataResponse, error)
+	GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error)
+	UpdateIssueID(context.Context, *UpdateIssueIDRequest) (*UpdateIssueIDResponse, error)
+	RenameDependencyPrefix(context.Context, *RenameDependencyPrefixRequest) (*RenameDependencyPrefixResponse, error)
+	RenameCounterPrefix(context.Context, *RenameCounterPrefixRequest) (*RenameCounterPrefixResponse, error)
+	mustEmbedUnimplementedStorageServer()
+}
+
+// UnimplementedStorageServer must be embedded to have forward compatible implementations.
+type UnimplementedStorageServer struct {
+}
+
+func (UnimplementedStorageServer) CreateIssue(context.Context, *CreateIssueRequest) (*CreateIssueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateIssue not implemented")
+}
+func (UnimplementedStorageServer) CreateIssues(context.Context, *CreateIssuesRequest) (*CreateIssuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateIssues not implemented")
+}
+func (UnimplementedStorageServer) CreateIssuesWithFullOptions(context.Context, *CreateIssuesWithFullOptionsRequest) (*CreateIssuesWithFullOptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateIssuesWithFullOptions not implemented")
+}
+func (UnimplementedStorageServer) GetIssue(context.Context, *GetIssueRequest) (*GetIssueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIssue not implemented")
+}
+func (UnimplementedStorageServer) GetIssueByExternalRef(context.Context, *GetIssueByExternalRefRequest) (*GetIssueByExternalRefResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIssueByExternalRef not implemented")
+}
+func (UnimplementedStorageServer) UpdateIssue(context.Context, *UpdateIssueRequest) (*UpdateIssueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateIssue not implemented")
+}
+func (UnimplementedStorageServer) ClaimIssue(context.Context, *ClaimIssueRequest) (*ClaimIssueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClaimIssue not implemented")
+}
+func (UnimplementedStorageServer) CloseIssue(context.Context, *CloseIssueRequest) (*CloseIssueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseIssue not implemented")
+}
+func (UnimplementedStorageServer) DeleteIssue(context.Context, *DeleteIssueRequest) (*DeleteIssueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteIssue not implemented")
+}
+func (UnimplementedStorageServer) SearchIssues(context.Context, *SearchIssuesRequest) (*SearchIssuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchIssues not implemented")
+}
+func (UnimplementedStorageServer) AddDependency(context.Context, *AddDependencyRequest) (*AddDependencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddDependency not implemented")
+}
+func (UnimplementedStorageServer) RemoveDependency(context.Context, *RemoveDependencyRequest) (*RemoveDependencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveDependency not implemented")
+}
+func (UnimplementedStorageServer) GetDependencies(context.Context, *GetDependenciesRequest) (*GetDependenciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDependencies not implemented")
+}
+func (UnimplementedStorageServer) GetDependents(context.Context, *GetDependentsRequest) (*GetDependentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDependents not implemented")
+}
+func (UnimplementedStorageServer) GetDependenciesWithMetadata(context.Context, *GetDependenciesWithMetadataRequest) (*GetDependenciesWithMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDependenciesWithMetadata not implemented")
+}
+func (UnimplementedStorageServer) GetDependentsWithMetadata(context.Context, *GetDependentsWithMetadataRequest) (*GetDependentsWithMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDependentsWithMetadata not implemented")
+}
+func (UnimplementedStorageServer) GetDependencyRecords(context.Context, *GetDependencyRecordsRequest) (*GetDependencyRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDependencyRecords not implemented")
+}
+func (UnimplementedStorageServer) GetAllDependencyRecords(context.Context, *GetAllDependencyRecordsRequest) (*GetAllDependencyRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllDependencyRecords not implemented")
+}
+func (UnimplementedStorageServer) GetDependencyRecordsForIssues(context.Context, *GetDependencyRecordsForIssuesRequest) (*GetDependencyRecordsForIssuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDependencyRecordsForIssues not implemented")
+}
+func (UnimplementedStorageServer) GetDependencyCounts(context.Context, *GetDependencyCountsRequest) (*GetDependencyCountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDependencyCounts not implemented")
+}
+func (UnimplementedStorageServer) GetDependencyTree(context.Context, *GetDependencyTreeRequest) (*GetDependencyTreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDependencyTree not implemented")
+}
+func (UnimplementedStorageServer) DetectCycles(context.Context, *DetectCyclesRequest) (*DetectCyclesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DetectCycles not implemented")
+}
+func (UnimplementedStorageServer) AddLabel(context.Context, *AddLabelRequest) (*AddLabelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddLabel not implemented")
+}
+func (UnimplementedStorageServer) RemoveLabel(context.Context, *RemoveLabelRequest) (*RemoveLabelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveLabel not implemented")
+}
+func (UnimplementedStorageServer) GetLabels(context.Context, *GetLabelsRequest) (*GetLabelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLabels not implemented")
+}
+func (UnimplementedStorageServer) GetLabelsForIssues(context.Context, *GetLabelsForIssuesRequest) (*GetLabelsForIssuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLabelsForIssues not implemented")
+}
+func (UnimplementedStorageServer) GetIssuesByLabel(context.Context, *GetIssuesByLabelRequest) (*GetIssuesByLabelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIssuesByLabel not implemented")
+}
+func (UnimplementedStorageServer) GetReadyWork(context.Context, *GetReadyWorkRequest) (*GetReadyWorkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReadyWork not implemented")
+}
+func (UnimplementedStorageServer) GetBlockedIssues(context.Context, *GetBlockedIssuesRequest) (*GetBlockedIssuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockedIssues not implemented")
+}
+func (UnimplementedStorageServer) IsBlocked(context.Context, *IsBlockedRequest) (*IsBlockedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsBlocked not implemented")
+}
+func (UnimplementedStorageServer) GetEpicsEligibleForClosure(context.Context, *GetEpicsEligibleForClosureRequest) (*GetEpicsEligibleForClosureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEpicsEligibleForClosure not implemented")
+}
+func (UnimplementedStorageServer) GetStaleIssues(context.Context, *GetStaleIssuesRequest) (*GetStaleIssuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStaleIssues not implemented")
+}
+func (UnimplementedStorageServer) GetNewlyUnblockedByClose(context.Context, *GetNewlyUnblockedByCloseRequest) (*GetNewlyUnblockedByCloseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNewlyUnblockedByClose not implemented")
+}
+func (UnimplementedStorageServer) AddComment(context.Context, *AddCommentRequest) (*AddCommentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddComment not implemented")
+}
+func (UnimplementedStorageServer) GetEvents(context.Context, *GetEventsRequest) (*GetEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEvents not implemented")
+}
+func (UnimplementedStorageServer) GetAllEventsSince(context.Context, *GetAllEventsSinceRequest) (*GetAllEventsSinceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllEventsSince not implemented")
+}
+func (UnimplementedStorageServer) AddIssueComment(context.Context, *AddIssueCommentRequest) (*AddIssueCommentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddIssueComment not implemented")
+}
+func (UnimplementedStorageServer) ImportIssueComment(context.Context, *ImportIssueCommentRequest) (*ImportIssueCommentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportIssueComment not implemented")
+}
+func (UnimplementedStorageServer) GetIssueComments(context.Context, *GetIssueCommentsRequest) (*GetIssueCommentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIssueComments not implemented")
+}
+func (UnimplementedStorageServer) GetCommentsForIssues(context.Context, *GetCommentsForIssuesRequest) (*GetCommentsForIssuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCommentsForIssues not implemented")
+}
+func (UnimplementedStorageServer) GetCommentCounts(context.Context, *GetCommentCountsRequest) (*GetCommentCountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCommentCounts not implemented")
+}
+func (UnimplementedStorageServer) GetStatistics(context.Context, *GetStatisticsRequest) (*GetStatisticsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatistics not implemented")
+}
+func (UnimplementedStorageServer) GetMoleculeProgress(context.Context, *GetMoleculeProgressRequest) (*GetMoleculeProgressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMoleculeProgress not implemented")
+}
+func (UnimplementedStorageServer) GetDirtyIssues(context.Context, *GetDirtyIssuesRequest) (*GetDirtyIssuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDirtyIssues not implemented")
+}
+func (UnimplementedStorageServer) GetDirtyIssueHash(context.Context, *GetDirtyIssueHashRequest) (*GetDirtyIssueHashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDirtyIssueHash not implemented")
+}
+func (UnimplementedStorageServer) ClearDirtyIssuesByID(context.Context, *ClearDirtyIssuesByIDRequest) (*ClearDirtyIssuesByIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearDirtyIssuesByID not implemented")
+}
+func (UnimplementedStorageServer) GetExportHash(context.Context, *GetExportHashRequest) (*GetExportHashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExportHash not implemented")
+}
+func (UnimplementedStorageServer) SetExportHash(context.Context, *SetExportHashRequest) (*SetExportHashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetExportHash not implemented")
+}
+func (UnimplementedStorageServer) ClearAllExportHashes(context.Context, *ClearAllExportHashesRequest) (*ClearAllExportHashesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearAllExportHashes not implemented")
+}
+func (UnimplementedStorageServer) GetJSONLFileHash(context.Context, *GetJSONLFileHashRequest) (*GetJSONLFileHashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJSONLFileHash not implemented")
+}
+func (UnimplementedStorageServer) SetJSONLFileHash(context.Context, *SetJSONLFileHashRequest) (*SetJSONLFileHashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetJSONLFileHash not implemented")
+}
+func (UnimplementedStorageServer) GetNextChildID(context.Context, *GetNextChildIDRequest) (*GetNextChildIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNextChildID not implemented")
+}
+func (UnimplementedStorageServer) SetConfig(context.Context, *SetConfigRequest) (*SetConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetConfig not implemented")
+}
+func (UnimplementedStorageServer) GetConfig(context.Context, *GetConfigRequest) (*GetConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
+}
+func (UnimplementedStorageServer) GetAllConfig(context.Context, *GetAllConfigRequest) (*GetAllConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllConfig not implemented")
+}
+func (UnimplementedStorageServer) DeleteConfig(context.Context, *DeleteConfigRequest) (*DeleteConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteConfig not implemented")
+}
+func (UnimplementedStorageServer) GetCustomStatuses(context.Context, *GetCustomStatusesRequest) (*GetCustomStatusesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCustomStatuses not implemented")
+}
+func (UnimplementedStorageServer) GetCustomTypes(context.Context, *GetCustomTypesRequest) (*GetCustomTypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCustomTypes not implemented")
+}
+func (UnimplementedStorageServer) SetMetadata(context.Context, *SetMetadataRequest) (*SetMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMetadata not implemented")
+}
+func (UnimplementedStorageServer) GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetadata not implemented")
+}
+func (UnimplementedStorageServer) UpdateIssueID(context.Context, *UpdateIssueIDRequest) (*UpdateIssueIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateIssueID not implemented")
+}
+func (UnimplementedStorageServer) RenameDependencyPrefix(context.Context, *RenameDependencyPrefixRequest) (*RenameDependencyPrefixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameDependencyPrefix not implemented")
+}
+func (UnimplementedStorageServer) RenameCounterPrefix(context.Context, *RenameCounterPrefixRequest) (*RenameCounterPrefixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameCounterPrefix not implemented")
+}
+func (UnimplementedStorageServer) mustEmbedUnimplementedStorageServer() {}
+
+// UnsafeStorageServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StorageServer will
+// result in compilation errors.
+type UnsafeStorageServer interface {
+	mustEmbedUnimplementedStorageServer()
+}
+
+func RegisterStorageServer(s grpc.ServiceRegistrar, srv StorageServer) {
+	s.RegisterService(&Storage_ServiceDesc, srv)
+}
+
+func _Storage_CreateIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).CreateIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_CreateIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).CreateIssue(ctx, req.(*CreateIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_CreateIssues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateIssuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).CreateIssues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_CreateIssues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).CreateIssues(ctx, req.(*CreateIssuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_CreateIssuesWithFullOptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateIssuesWithFullOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).CreateIssuesWithFullOptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_CreateIssuesWithFullOptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).CreateIssuesWithFullOptions(ctx, req.(*CreateIssuesWithFullOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetIssue(ctx, req.(*GetIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetIssueByExternalRef_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIssueByExternalRefRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetIssueByExternalRef(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetIssueByExternalRef_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetIssueByExternalRef(ctx, req.(*GetIssueByExternalRefRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_UpdateIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).UpdateIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_UpdateIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).UpdateIssue(ctx, req.(*UpdateIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_ClaimIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClaimIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).ClaimIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_ClaimIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).ClaimIssue(ctx, req.(*ClaimIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_CloseIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).CloseIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_CloseIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).CloseIssue(ctx, req.(*CloseIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_DeleteIssue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteIssueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).DeleteIssue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_DeleteIssue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).DeleteIssue(ctx, req.(*DeleteIssueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_SearchIssues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchIssuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).SearchIssues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_SearchIssues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SearchIssues(ctx, req.(*SearchIssuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_AddDependency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddDependencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).AddDependency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_AddDependency_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).AddDependency(ctx, req.(*AddDependencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_RemoveDependency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveDependencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).RemoveDependency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_RemoveDependency_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).RemoveDependency(ctx, req.(*RemoveDependencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetDependencies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDependenciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetDependencies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetDependencies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetDependencies(ctx, req.(*GetDependenciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetDependents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDependentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetDependents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetDependents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetDependents(ctx, req.(*GetDependentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetDependenciesWithMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDependenciesWithMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetDependenciesWithMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetDependenciesWithMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetDependenciesWithMetadata(ctx, req.(*GetDependenciesWithMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetDependentsWithMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDependentsWithMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetDependentsWithMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetDependentsWithMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetDependentsWithMetadata(ctx, req.(*GetDependentsWithMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetDependencyRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDependencyRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetDependencyRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetDependencyRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetDependencyRecords(ctx, req.(*GetDependencyRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetAllDependencyRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllDependencyRecordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetAllDependencyRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetAllDependencyRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetAllDependencyRecords(ctx, req.(*GetAllDependencyRecordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetDependencyRecordsForIssues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDependencyRecordsForIssuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetDependencyRecordsForIssues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetDependencyRecordsForIssues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetDependencyRecordsForIssues(ctx, req.(*GetDependencyRecordsForIssuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetDependencyCounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDependencyCountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetDependencyCounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetDependencyCounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetDependencyCounts(ctx, req.(*GetDependencyCountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetDependencyTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDependencyTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetDependencyTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetDependencyTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetDependencyTree(ctx, req.(*GetDependencyTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_DetectCycles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetectCyclesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).DetectCycles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_DetectCycles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).DetectCycles(ctx, req.(*DetectCyclesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_AddLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).AddLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_AddLabel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).AddLabel(ctx, req.(*AddLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_RemoveLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).RemoveLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_RemoveLabel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).RemoveLabel(ctx, req.(*RemoveLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLabelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetLabels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetLabels(ctx, req.(*GetLabelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetLabelsForIssues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLabelsForIssuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetLabelsForIssues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetLabelsForIssues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetLabelsForIssues(ctx, req.(*GetLabelsForIssuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetIssuesByLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIssuesByLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetIssuesByLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetIssuesByLabel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetIssuesByLabel(ctx, req.(*GetIssuesByLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetReadyWork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReadyWorkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetReadyWork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetReadyWork_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetReadyWork(ctx, req.(*GetReadyWorkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetBlockedIssues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockedIssuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetBlockedIssues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetBlockedIssues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetBlockedIssues(ctx, req.(*GetBlockedIssuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_IsBlocked_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsBlockedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).IsBlocked(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_IsBlocked_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).IsBlocked(ctx, req.(*IsBlockedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetEpicsEligibleForClosure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEpicsEligibleForClosureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetEpicsEligibleForClosure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetEpicsEligibleForClosure_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetEpicsEligibleForClosure(ctx, req.(*GetEpicsEligibleForClosureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetStaleIssues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStaleIssuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetStaleIssues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetStaleIssues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetStaleIssues(ctx, req.(*GetStaleIssuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetNewlyUnblockedByClose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNewlyUnblockedByCloseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetNewlyUnblockedByClose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetNewlyUnblockedByClose_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetNewlyUnblockedByClose(ctx, req.(*GetNewlyUnblockedByCloseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_AddComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).AddComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_AddComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).AddComment(ctx, req.(*AddCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetEvents(ctx, req.(*GetEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetAllEventsSince_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllEventsSinceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetAllEventsSince(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetAllEventsSince_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetAllEventsSince(ctx, req.(*GetAllEventsSinceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_AddIssueComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddIssueCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).AddIssueComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_AddIssueComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).AddIssueComment(ctx, req.(*AddIssueCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_ImportIssueComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportIssueCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).ImportIssueComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_ImportIssueComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).ImportIssueComment(ctx, req.(*ImportIssueCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetIssueComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIssueCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetIssueComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetIssueComments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetIssueComments(ctx, req.(*GetIssueCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetCommentsForIssues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCommentsForIssuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetCommentsForIssues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetCommentsForIssues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetCommentsForIssues(ctx, req.(*GetCommentsForIssuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetCommentCounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCommentCountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetCommentCounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetCommentCounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetCommentCounts(ctx, req.(*GetCommentCountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatisticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetStatistics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetStatistics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetStatistics(ctx, req.(*GetStatisticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetMoleculeProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMoleculeProgressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetMoleculeProgress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetMoleculeProgress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetMoleculeProgress(ctx, req.(*GetMoleculeProgressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetDirtyIssues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDirtyIssuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetDirtyIssues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetDirtyIssues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetDirtyIssues(ctx, req.(*GetDirtyIssuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetDirtyIssueHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDirtyIssueHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetDirtyIssueHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetDirtyIssueHash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetDirtyIssueHash(ctx, req.(*GetDirtyIssueHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_ClearDirtyIssuesByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearDirtyIssuesByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).ClearDirtyIssuesByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_ClearDirtyIssuesByID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).ClearDirtyIssuesByID(ctx, req.(*ClearDirtyIssuesByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetExportHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExportHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetExportHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetExportHash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetExportHash(ctx, req.(*GetExportHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_SetExportHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetExportHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).SetExportHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_SetExportHash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SetExportHash(ctx, req.(*SetExportHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_ClearAllExportHashes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearAllExportHashesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).ClearAllExportHashes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_ClearAllExportHashes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).ClearAllExportHashes(ctx, req.(*ClearAllExportHashesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetJSONLFileHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJSONLFileHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetJSONLFileHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetJSONLFileHash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetJSONLFileHash(ctx, req.(*GetJSONLFileHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_SetJSONLFileHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetJSONLFileHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).SetJSONLFileHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_SetJSONLFileHash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SetJSONLFileHash(ctx, req.(*SetJSONLFileHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetNextChildID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNextChildIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetNextChildID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetNextChildID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetNextChildID(ctx, req.(*GetNextChildIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_SetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).SetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_SetConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SetConfig(ctx, req.(*SetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetConfig(ctx, req.(*GetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetAllConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetAllConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetAllConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetAllConfig(ctx, req.(*GetAllConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_DeleteConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).DeleteConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_DeleteConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).DeleteConfig(ctx, req.(*DeleteConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetCustomStatuses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCustomStatusesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetCustomStatuses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetCustomStatuses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetCustomStatuses(ctx, req.(*GetCustomStatusesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetCustomTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCustomTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetCustomTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetCustomTypes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetCustomTypes(ctx, req.(*GetCustomTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_SetMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).SetMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_SetMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SetMetadata(ctx, req.(*SetMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetMetadata(ctx, req.(*GetMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_UpdateIssueID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateIssueIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).UpdateIssueID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_UpdateIssueID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).UpdateIssueID(ctx, req.(*UpdateIssueIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_RenameDependencyPrefix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameDependencyPrefixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).RenameDependencyPrefix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_RenameDependencyPrefix_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).RenameDependencyPrefix(ctx, req.(*RenameDependencyPrefixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_RenameCounterPrefix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameCounterPrefixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).RenameCounterPrefix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_RenameCounterPrefix_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).RenameCounterPrefix(ctx, req.(*RenameCounterPrefixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Storage_ServiceDesc is the grpc.ServiceDesc for Storage service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Storage_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "beads.storage.v1.Storage",
+	HandlerType: (*StorageServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateIssue",
+			Handler:    _Storage_CreateIssue_Handler,
+		},
+		{
+			MethodName: "CreateIssues",
+			Handler:    _Storage_CreateIssues_Handler,
+		},
+		{
+			MethodName: "CreateIssuesWithFullOptions",
+			Handler:    _Storage_CreateIssuesWithFullOptions_Handler,
+		},
+		{
+			MethodName: "GetIssue",
+			Handler:    _Storage_GetIssue_Handler,
+		},
+		{
+			MethodName: "GetIssueByExternalRef",
+			Handler:    _Storage_GetIssueByExternalRef_Handler,
+		},
+		{
+			MethodName: "UpdateIssue",
+			Handler:    _Storage_UpdateIssue_Handler,
+		},
+		{
+			MethodName: "ClaimIssue",
+			Handler:    _Storage_ClaimIssue_Handler,
+		},
+		{
+			MethodName: "CloseIssue",
+			Handler:    _Storage_CloseIssue_Handler,
+		},
+		{
+			MethodName: "DeleteIssue",
+			Handler:    _Storage_DeleteIssue_Handler,
+		},
+		{
+			MethodName: "SearchIssues",
+			Handler:    _Storage_SearchIssues_Handler,
+		},
+		{
+			MethodName: "AddDependency",
+			Handler:    _Storage_AddDependency_Handler,
+		},
+		{
+			MethodName: "RemoveDependency",
+			Handler:    _Storage_RemoveDependency_Handler,
+		},
+		{
+			MethodName: "GetDependencies",
+			Handler:    _Storage_GetDependencies_Handler,
+		},
+		{
+			MethodName: "GetDependents",
+			Handler:    _Storage_GetDependents_Handler,
+		},
+		{
+			MethodName: "GetDependenciesWithMetadata",
+			Handler:    _Storage_GetDependenciesWithMetadata_Handler,
+		},
+		{
+			MethodName: "GetDependentsWithMetadata",
+			Handler:    _Storage_GetDependentsWithMetadata_Handler,
+		},
+		{
+			MethodName: "GetDependencyRecords",
+			Handler:    _Storage_GetDependencyRecords_Handler,
+		},
+		{
+			MethodName: "GetAllDependencyRecords",
+			Handler:    _Storage_GetAllDependencyRecords_Handler,
+		},
+		{
+			MethodName: "GetDependencyRecordsForIssues",
+			Handler:    _Storage_GetDependencyRecordsForIssues_Handler,
+		},
+		{
+			MethodName: "GetDependencyCounts",
+			Handler:    _Storage_GetDependencyCounts_Handler,
+		},
+		{
+			MethodName: "GetDependencyTree",
+			Handler:    _Storage_GetDependencyTree_Handler,
+		},
+		{
+			MethodName: "DetectCycles",
+			Handler:    _Storage_DetectCycles_Handler,
+		},
+		{
+			MethodName: "AddLabel",
+			Handler:    _Storage_AddLabel_Handler,
+		},
+		{
+			MethodName: "RemoveLabel",
+			Handler:    _Storage_RemoveLabel_Handler,
+		},
+		{
+			MethodName: "GetLabels",
+			Handler:    _Storage_GetLabels_Handler,
+		},
+		{
+			MethodName: "GetLabelsForIssues",
+			Handler:    _Storage_GetLabelsForIssues_Handler,
+		},
+		{
+			MethodName: "GetIssuesByLabel",
+			Handler:    _Storage_GetIssuesByLabel_Handler,
+		},
+		{
+			MethodName: "GetReadyWork",
+			Handler:    _Storage_GetReadyWork_Handler,
+		},
+		{
+			MethodName: "GetBlockedIssues",
+			Handler:    _Storage_GetBlockedIssues_Handler,
+		},
+		{
+			MethodName: "IsBlocked",
+			Handler:    _Storage_IsBlocked_Handler,
+		},
+		{
+			MethodName: "GetEpicsEligibleForClosure",
+			Handler:    _Storage_GetEpicsEligibleForClosure_Handler,
+		},
+		{
+			MethodName: "GetStaleIssues",
+			Handler:    _Storage_GetStaleIssues_Handler,
+		},
+		{
+			MethodName: "GetNewlyUnblockedByClose",
+			Handler:    _Storage_GetNewlyUnblockedByClose_Handler,
+		},
+		{
+			MethodName: "AddComment",
+			Handler:    _Storage_AddComment_Handler,
+		},
+		{
+			MethodName: "GetEvents",
+			Handler:    _Storage_GetEvents_Handler,
+		},
+		{
+			MethodName: "GetAllEventsSince",
+			Handler:    _Storage_GetAllEventsSince_Handler,
+		},
+		{
+			MethodName: "AddIssueComment",
+			Handler:    _Storage_AddIssueComment_Handler,
+		},
+		{
+			MethodName: "ImportIssueComment",
+			Handler:    _Storage_ImportIssueComment_Handler,
+		},
+		{
+			MethodName: "GetIssueComments",
+			Handler:    _Storage_GetIssueComments_Handler,
+		},
+		{
+			MethodName: "GetCommentsForIssues",
+			Handler:    _Storage_GetCommentsForIssues_Handler,
+		},
+		{
+			MethodName: "GetCommentCounts",
+			Handler:    _Storage_GetCommentCounts_Handler,
+		},
+		{
+			MethodName: "GetStatistics",
+			Handler:    _Storage_GetStatistics_Handler,
+		},
+		{
+			MethodName: "GetMoleculeProgress",
+			Handler:    _Storage_GetMoleculeProgress_Handler,
+		},
+		{
+			MethodName: "GetDirtyIssues",
+			Handler:    _Storage_GetDirtyIssues_Handler,
+		},
+		{
+			MethodName: "GetDirtyIssueHash",
+			Handler:    _Storage_GetDirtyIssueHash_Handler,
+		},
+		{
+			MethodName: "ClearDirtyIssuesByID",
+			Handler:    _Storage_ClearDirtyIssuesByID_Handler,
+		},
+		{
+			MethodName: "GetExportHash",
+			Handler:    _Storage_GetExportHash_Handler,
+		},
+		{
+			MethodName: "SetExportHash",
+			Handler:    _Storage_SetExportHash_Handler,
+		},
+		{
+			MethodName: "ClearAllExportHashes",
+			Handler:    _Storage_ClearAllExportHashes_Handler,
+		},
+		{
+			MethodName: "GetJSONLFileHash",
+			Handler:    _Storage_GetJSONLFileHash_Handler,
+		},
+		{
+			MethodName: "SetJSONLFileHash",
+			Handler:    _Storage_SetJSONLFileHash_Handler,
+		},
+		{
+			MethodName: "GetNextChildID",
+			Handler:    _Storage_GetNextChildID_Handler,
+		},
+		{
+			MethodName: "SetConfig",
+			Handler:    _Storage_SetConfig_Handler,
+		},
+		{
+			MethodName: "GetConfig",
+			Handler:    _Storage_GetConfig_Handler,
+		},
+		{
+			MethodName: "GetAllConfig",
+			Handler:    _Storage_GetAllConfig_Handler,
+		},
+		{
+			MethodName: "DeleteConfig",
+			Handler:    _Storage_DeleteConfig_Handler,
+		},
+		{
+			MethodName: "GetCustomStatuses",
+			Handler:    _Storage_GetCustomStatuses_Handler,
+		},
+		{
+			MethodName: "GetCustomTypes",
+			Handler:    _Storage_GetCustomTypes_Handler,
+		},
+		{
+			MethodName: "SetMetadata",
+			Handler:    _Storage_SetMetadata_Handler,
+		},
+		{
+			MethodName: "GetMetadata",
+			Handler:    _Storage_GetMetadata_Handler,
+		},
+		{
+			MethodName: "UpdateIssueID",
+			Handler:    _Storage_UpdateIssueID_Handler,
+		},
+		{
+			MethodName: "RenameDependencyPrefix",
+			Handler:    _Storage_RenameDependencyPrefix_Handler,
+		},
+		{
+			MethodName: "RenameCounterPrefix",
+			Handler:    _Storage_RenameCounterPrefix_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "storage.proto",
+}
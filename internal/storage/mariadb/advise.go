@@ -0,0 +1,138 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// adviseMinRows is the number of rows a scanned table must have, by the
+// optimizer's estimate, for AdviseIndexes to suggest an index for it. It is
+// a variable so tests can exercise the advisor on small tables.
+var adviseMinRows int64 = 1000
+
+// IndexSuggestion is an index AdviseIndexes recommends creating.
+type IndexSuggestion struct {
+	Table     string   // Table name, including any TablePrefix
+	Index     string   // Index name, including any TablePrefix
+	Columns   []string // Indexed columns, in order
+	Query     string   // The query that scanned the table
+	Rows      int64    // Rows the optimizer expects the scan to read
+	Statement string   // CREATE INDEX statement adding the index
+}
+
+// advisedQuery is a query the store issues and the schema index that serves
+// it.
+type advisedQuery struct {
+	query   string
+	args    []interface{}
+	table   string
+	index   string
+	columns []string
+}
+
+// adviseSince is the time the advised date range queries start from.
+var adviseSince = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// advisedQueries are the filters and orderings the store's list, ready,
+// dependency, label, and history queries run on each table. Lookups served
+// by a primary key are left out.
+var advisedQueries = []advisedQuery{
+	{"SELECT id FROM issues WHERE status = ?", []interface{}{"open"}, "issues", "idx_issues_status", []string{"status"}},
+	{"SELECT id FROM issues WHERE priority = ?", []interface{}{1}, "issues", "idx_issues_priority", []string{"priority"}},
+	{"SELECT id FROM issues WHERE issue_type = ?", []interface{}{"task"}, "issues", "idx_issues_issue_type", []string{"issue_type"}},
+	{"SELECT id FROM issues WHERE assignee = ?", []interface{}{"someone"}, "issues", "idx_issues_assignee", []string{"assignee"}},
+	{"SELECT id FROM issues WHERE created_at >= ?", []interface{}{adviseSince}, "issues", "idx_issues_created_at", []string{"created_at"}},
+	{"SELECT id FROM issues WHERE updated_at > ? ORDER BY updated_at, id", []interface{}{adviseSince}, "issues", "idx_issues_updated_at", []string{"updated_at", "id"}},
+	{"SELECT id FROM issues WHERE spec_id = ?", []interface{}{"spec"}, "issues", "idx_issues_spec_id", []string{"spec_id"}},
+	{"SELECT id FROM issues WHERE external_ref = ?", []interface{}{"ref"}, "issues", "idx_issues_external_ref", []string{"external_ref"}},
+	{"SELECT issue_id FROM dependencies WHERE depends_on_id = ? AND type = ?", []interface{}{"id", "blocks"}, "dependencies", "idx_dependencies_depends_on_type", []string{"depends_on_id", "type"}},
+	{"SELECT issue_id FROM dependencies WHERE thread_id = ?", []interface{}{"thread"}, "dependencies", "idx_dependencies_thread", []string{"thread_id"}},
+	{"SELECT issue_id FROM issue_labels WHERE label_id = ?", []interface{}{1}, "issue_labels", "idx_issue_labels_label", []string{"label_id"}},
+	{"SELECT id FROM comments WHERE issue_id = ? ORDER BY created_at", []interface{}{"id"}, "comments", "idx_comments_issue", []string{"issue_id"}},
+	{"SELECT id FROM events WHERE issue_id = ? ORDER BY created_at DESC", []interface{}{"id"}, "events", "idx_events_issue", []string{"issue_id"}},
+	{"SELECT id FROM issue_snapshots WHERE issue_id = ?", []interface{}{"id"}, "issue_snapshots", "idx_snapshots_issue", []string{"issue_id"}},
+	{"SELECT id FROM interactions WHERE issue_id = ?", []interface{}{"id"}, "interactions", "idx_interactions_issue_id", []string{"issue_id"}},
+	{"SELECT id FROM issue_audit WHERE issue_id = ? ORDER BY changed_at", []interface{}{"id"}, "issue_audit", "idx_issue_audit_issue", []string{"issue_id", "changed_at"}},
+}
+
+// AdviseIndexes runs the queries the store issues under EXPLAIN and suggests
+// an index for each one that would scan a whole table of at least 1000 rows
+// with no index to choose from. The suggestions are the indexes Beads
+// defines for those queries, so they usually point at indexes dropped or
+// never created; nothing is changed. Unlike ValidateSchema, which compares
+// index names, it reports only what the optimizer would actually miss.
+func (s *MariaDBStore) AdviseIndexes(ctx context.Context) ([]IndexSuggestion, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+
+	var suggestions []IndexSuggestion
+	for _, q := range advisedQueries {
+		var rows int64
+		var scanned bool
+		err := s.withReadRetry(ctx, func() error {
+			var err error
+			rows, scanned, err = explainScan(ctx, s.dbOrTx(), q, s.tablePrefix+q.table)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to explain %q: %w", q.query, err)
+		}
+		if !scanned || rows < adviseMinRows {
+			continue
+		}
+		table, index := s.tablePrefix+q.table, s.prefixIndexNames([]string{q.index})[0]
+		quoted := make([]string, len(q.columns))
+		for i, col := range q.columns {
+			quoted[i] = quoteIdentifier(col)
+		}
+		suggestions = append(suggestions, IndexSuggestion{
+			Table:     table,
+			Index:     index,
+			Columns:   q.columns,
+			Query:     q.query,
+			Rows:      rows,
+			Statement: fmt.Sprintf("CREATE INDEX %s ON %s (%s)", quoteIdentifier(index), quoteIdentifier(table), strings.Join(quoted, ", ")),
+		})
+	}
+	return suggestions, nil
+}
+
+// explainScan runs q under EXPLAIN and reports whether its plan reads all of
+// table with no possible index, and the rows the optimizer expects to read.
+func explainScan(ctx context.Context, db querier, q advisedQuery, table string) (int64, bool, error) {
+	rows, err := db.QueryContext(ctx, "EXPLAIN "+q.query, q.args...)
+	if err != nil {
+		return 0, false, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, false, err
+	}
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return 0, false, err
+		}
+		plan := make(map[string]sql.NullString, len(columns))
+		for i, col := range columns {
+			plan[col] = values[i]
+		}
+		if plan["table"].String != table || plan["type"].String != "ALL" || plan["possible_keys"].String != "" {
+			continue
+		}
+		n, _ := strconv.ParseInt(plan["rows"].String, 10, 64)
+		return n, true, rows.Err()
+	}
+	return 0, false, rows.Err()
+}
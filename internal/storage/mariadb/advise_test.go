@@ -0,0 +1,65 @@
+package mariadb
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestAdviseIndexes(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	defer func(n int64) { adviseMinRows = n }(adviseMinRows)
+	adviseMinRows = 0
+
+	for i := 0; i < 20; i++ {
+		issue := &types.Issue{ID: fmt.Sprintf("test-%d", i), Title: "Scan me", Status: types.StatusOpen, Priority: i % 4, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	// Every advised query has its index in the full schema
+	suggestions, err := store.AdviseIndexes(ctx)
+	if err != nil {
+		t.Fatalf("AdviseIndexes failed: %v", err)
+	}
+	if len(suggestions) != 0 {
+		t.Errorf("AdviseIndexes on the full schema = %+v, want none", suggestions)
+	}
+
+	if _, err := store.UnderlyingDB().ExecContext(ctx, "DROP INDEX idx_issues_issue_type ON issues"); err != nil {
+		t.Fatalf("DROP INDEX failed: %v", err)
+	}
+	suggestions, err = store.AdviseIndexes(ctx)
+	if err != nil {
+		t.Fatalf("AdviseIndexes failed: %v", err)
+	}
+	if len(suggestions) != 1 {
+		t.Fatalf("AdviseIndexes = %+v, want idx_issues_issue_type only", suggestions)
+	}
+	got := suggestions[0]
+	if got.Table != "issues" || got.Index != "idx_issues_issue_type" || !reflect.DeepEqual(got.Columns, []string{"issue_type"}) {
+		t.Errorf("AdviseIndexes = %+v, want idx_issues_issue_type on issues (issue_type)", got)
+	}
+	if want := "CREATE INDEX `idx_issues_issue_type` ON `issues` (`issue_type`)"; got.Statement != want {
+		t.Errorf("Statement = %q, want %q", got.Statement, want)
+	}
+
+	// The suggestion recreates the index
+	if _, err := store.UnderlyingDB().ExecContext(ctx, got.Statement); err != nil {
+		t.Fatalf("%s failed: %v", got.Statement, err)
+	}
+	if diffs, err := store.ValidateSchema(ctx); err != nil || len(diffs) != 0 {
+		t.Errorf("ValidateSchema after applying the suggestion = %v, %v; want no differences", diffs, err)
+	}
+	if suggestions, err := store.AdviseIndexes(ctx); err != nil || len(suggestions) != 0 {
+		t.Errorf("AdviseIndexes after applying the suggestion = %+v, %v; want none", suggestions, err)
+	}
+}
@@ -0,0 +1,135 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AuditEntry is one field change recorded in issue_audit.
+type AuditEntry struct {
+	ID        int64
+	IssueID   string
+	Field     string  // Column name, e.g. "status"
+	OldValue  *string // nil if the field was NULL
+	NewValue  *string // nil if the field is now NULL
+	ChangedAt time.Time
+	Actor     string
+}
+
+// auditColumns returns the issues columns written by updates, sorted so
+// audit rows for one update are recorded in a stable order.
+func auditColumns(updates map[string]interface{}) []string {
+	columns := make([]string, 0, len(updates))
+	for key := range updates {
+		if key == "wisp" {
+			key = "ephemeral"
+		}
+		columns = append(columns, key)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// Columns audited by claims and by CloseIssue, sorted as auditColumns sorts
+var (
+	claimAuditColumns = []string{"assignee", "status"}
+	closeAuditColumns = []string{"close_reason", "status"}
+)
+
+// readAuditValues reads columns of issue id as text, locking the row so the
+// values stay current until the transaction ends.
+func readAuditValues(ctx context.Context, tx *sql.Tx, id string, columns []string) ([]sql.NullString, error) {
+	values := make([]sql.NullString, len(columns))
+	if len(columns) == 0 {
+		return values, nil
+	}
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	// nolint:gosec // G201: columns are checked by isAllowedUpdateField
+	query := fmt.Sprintf("SELECT `%s` FROM issues WHERE id = ? FOR UPDATE", strings.Join(columns, "`, `"))
+	if err := tx.QueryRowContext(ctx, query, id).Scan(dest...); err != nil {
+		return nil, fmt.Errorf("failed to read audited fields: %w", err)
+	}
+	return values, nil
+}
+
+// recordAudit writes an issue_audit row for each column whose value differs
+// between before and after. Unchanged columns are not logged.
+func recordAudit(ctx context.Context, tx *sql.Tx, id, actor string, columns []string, before, after []sql.NullString) error {
+	now := time.Now().UTC()
+	for i, column := range columns {
+		if before[i] == after[i] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO issue_audit (issue_id, field, old_value, new_value, changed_at, actor)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, id, column, before[i], after[i], now, actor); err != nil {
+			return fmt.Errorf("failed to record audit for %s: %w", column, err)
+		}
+	}
+	return nil
+}
+
+// updateAudited runs update, which changes the columns of issue id, and logs
+// the fields it changed to issue_audit in the same transaction.
+func updateAudited(ctx context.Context, tx *sql.Tx, id, actor string, columns []string, update func() error) error {
+	before, err := readAuditValues(ctx, tx, id, columns)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Nothing to audit; let the update report the missing issue
+		return update()
+	}
+	if err != nil {
+		return err
+	}
+	if err := update(); err != nil {
+		return err
+	}
+	after, err := readAuditValues(ctx, tx, id, columns)
+	if err != nil {
+		return err
+	}
+	return recordAudit(ctx, tx, id, actor, columns, before, after)
+}
+
+// GetIssueHistory returns the audited field changes of an issue, oldest
+// first. History outlives the issue: rows are kept after DeleteIssue.
+func (s *MariaDBStore) GetIssueHistory(ctx context.Context, id string) ([]*AuditEntry, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.readQueryContext(ctx, `
+		SELECT id, issue_id, field, old_value, new_value, changed_at, actor
+		FROM issue_audit
+		WHERE issue_id = ?
+		ORDER BY changed_at ASC, id ASC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*AuditEntry
+	for rows.Next() {
+		var entry AuditEntry
+		var oldValue, newValue sql.NullString
+		if err := rows.Scan(&entry.ID, &entry.IssueID, &entry.Field, &oldValue, &newValue, &entry.ChangedAt, &entry.Actor); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if oldValue.Valid {
+			entry.OldValue = &oldValue.String
+		}
+		if newValue.Valid {
+			entry.NewValue = &newValue.String
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,108 @@
+package mariadb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestGetIssueHistory(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	issue := &types.Issue{ID: "test-audit", Title: "Audited", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	// Unchanged fields are not logged
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"title": "Audited"}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusBlocked), "title": "Audited"}, "bob"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	history, err := store.GetIssueHistory(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssueHistory failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("GetIssueHistory returned %d entries, want 2: %+v", len(history), history)
+	}
+	want := []struct{ old, new, actor string }{
+		{"open", "in_progress", "alice"},
+		{"in_progress", "blocked", "bob"},
+	}
+	for i, w := range want {
+		got := history[i]
+		if got.Field != "status" || got.OldValue == nil || *got.OldValue != w.old ||
+			got.NewValue == nil || *got.NewValue != w.new || got.Actor != w.actor {
+			t.Errorf("history[%d] = %s %v -> %v by %s, want status %s -> %s by %s",
+				i, got.Field, got.OldValue, got.NewValue, got.Actor, w.old, w.new, w.actor)
+		}
+	}
+	if history[1].ChangedAt.Before(history[0].ChangedAt) {
+		t.Errorf("history not ordered by time: %v before %v", history[0].ChangedAt, history[1].ChangedAt)
+	}
+
+	if history, err := store.GetIssueHistory(ctx, "test-missing"); err != nil || len(history) != 0 {
+		t.Errorf("GetIssueHistory(missing) = %v, %v; want empty", history, err)
+	}
+}
+
+func TestIssueHistoryClaimAndClose(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for _, id := range []string{"test-claim", "test-next"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	if err := store.ClaimIssue(ctx, "test-claim", "alice"); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	if err := store.CloseIssue(ctx, "test-claim", "done", "alice", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	if issue, err := store.ClaimNextReadyIssue(ctx, "worker-1"); err != nil || issue == nil || issue.ID != "test-next" {
+		t.Fatalf("ClaimNextReadyIssue = %v, %v; want test-next", issue, err)
+	}
+
+	for id, want := range map[string][]string{
+		"test-claim": {"assignee  -> alice by alice", "status open -> in_progress by alice",
+			"close_reason  -> done by alice", "status in_progress -> closed by alice"},
+		"test-next": {"assignee  -> worker-1 by worker-1", "status open -> in_progress by worker-1"},
+	} {
+		history, err := store.GetIssueHistory(ctx, id)
+		if err != nil {
+			t.Fatalf("GetIssueHistory(%s) failed: %v", id, err)
+		}
+		var got []string
+		for _, e := range history {
+			var oldValue, newValue string
+			if e.OldValue != nil {
+				oldValue = *e.OldValue
+			}
+			if e.NewValue != nil {
+				newValue = *e.NewValue
+			}
+			got = append(got, e.Field+" "+oldValue+" -> "+newValue+" by "+e.Actor)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("history of %s = %q, want %q", id, got, want)
+		}
+	}
+}
@@ -0,0 +1,567 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+const (
+	// maxPlaceholders is the limit on ? parameters in one prepared statement.
+	maxPlaceholders = 65535
+
+	// defaultBatchStatementBytes caps the estimated size of one multi-row
+	// INSERT when the server's max_allowed_packet is unknown. It stays well
+	// below the smallest max_allowed_packet in common use (4 MiB).
+	defaultBatchStatementBytes = 1 << 20
+
+	// maxBatchStatementBytes caps the estimated size of one multi-row INSERT
+	// however large max_allowed_packet is, to bound the memory one statement
+	// takes on both ends.
+	maxBatchStatementBytes = 16 << 20
+)
+
+// CreateIssuesBatch creates issues with multi-row INSERT statements in a
+// single transaction, instead of one round trip per issue. Issues get the
+// same defaults, validation, ID generation, creation events and dirty marking
+// as CreateIssue. An ID the caller chose that already exists fails the whole
+// batch, and nothing is written; a generated ID that another create takes
+// first is replaced, as in CreateIssue. When the batch fails, the generated
+// IDs are cleared from issues.
+func (s *MariaDBStore) CreateIssuesBatch(ctx context.Context, issues []*types.Issue, actor string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+
+	// Fetch custom statuses and types for validation
+	customStatuses, err := s.GetCustomStatuses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get custom statuses: %w", err)
+	}
+	customTypes, err := s.GetCustomTypes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get custom types: %w", err)
+	}
+
+	for _, issue := range issues {
+		setCreateDefaults(issue)
+		if err := checkPriority(issue.Priority); err != nil {
+			return fmt.Errorf("validation failed for issue %s: %w", issue.ID, err)
+		}
+		if err := issue.ValidateWithCustom(customStatuses, customTypes); err != nil {
+			return fmt.Errorf("validation failed for issue %s: %w", issue.ID, err)
+		}
+		if issue.ContentHash == "" {
+			issue.ContentHash = issue.ComputeContentHash()
+		}
+	}
+
+	// Issues without an ID get a generated one. If a concurrent create
+	// inserts one of them first, the batch is rolled back and run again with
+	// other IDs for those issues, as insertWithGeneratedID does for one issue.
+	// The transaction's snapshot may not show the other issue, so the IDs
+	// that clashed are remembered.
+	var generated []*types.Issue
+	for _, issue := range issues {
+		if issue.ID == "" {
+			generated = append(generated, issue)
+		}
+	}
+	taken := make(map[string]bool)
+	for retries := 0; ; retries++ {
+		err := s.withRetryTx(ctx, func(tx *sql.Tx) error {
+			return s.insertIssuesBatch(ctx, tx, issues, generated, taken, actor)
+		})
+		if err == nil {
+			return nil
+		}
+		ids := make([]string, 0, len(generated))
+		for _, issue := range generated {
+			if issue.ID != "" {
+				ids = append(ids, issue.ID)
+			}
+			issue.ID = ""
+		}
+		// Inside WithTx, the enclosing transaction may already hold some of
+		// the rows, so the batch cannot be run again.
+		if s.tx != nil || !isDuplicateKeyError(err) || retries == maxIDAttempts {
+			return err
+		}
+		clashed, cerr := s.existingIssueIDs(ctx, ids)
+		if cerr != nil {
+			return errors.Join(err, cerr)
+		}
+		if len(clashed) == 0 {
+			return err // An ID the caller chose exists
+		}
+		for _, id := range clashed {
+			taken[id] = true
+		}
+	}
+}
+
+// existingIssueIDs returns the IDs in ids that exist.
+func (s *MariaDBStore) existingIssueIDs(ctx context.Context, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	// nolint:gosec // G201: placeholders contains only ? markers
+	rows, err := s.queryContext(ctx, fmt.Sprintf("SELECT id FROM issues WHERE id IN (%s)", placeholders), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for ID collisions: %w", err)
+	}
+	defer rows.Close()
+	var existing []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to check for ID collisions: %w", err)
+		}
+		existing = append(existing, id)
+	}
+	return existing, rows.Err()
+}
+
+// insertIssuesBatch is one attempt of CreateIssuesBatch: it gives the issues
+// in generated IDs that are neither in use nor in taken, and inserts issues.
+// It may run again when its transaction is replayed, so it generates the IDs
+// afresh each time.
+func (s *MariaDBStore) insertIssuesBatch(ctx context.Context, tx *sql.Tx, issues, generated []*types.Issue, taken map[string]bool, actor string) error {
+	for _, issue := range generated {
+		issue.ID = ""
+	}
+
+	var configPrefix string
+	err := tx.QueryRowContext(ctx, "SELECT value FROM config WHERE `key` = ?", "issue_prefix").Scan(&configPrefix)
+	if err == sql.ErrNoRows || configPrefix == "" {
+		return fmt.Errorf("database not initialized: issue_prefix config is missing (run 'bd init --prefix <prefix>' first)")
+	} else if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	// Generated IDs are not inserted until the end, so track them to
+	// keep two issues in the batch from getting the same ID
+	reserved := make(map[string]bool, len(issues)+len(taken))
+	for _, issue := range issues {
+		if issue.ID != "" {
+			reserved[issue.ID] = true
+		}
+	}
+	for id := range taken {
+		reserved[id] = true
+	}
+	for _, issue := range generated {
+		prefix := configPrefix
+		if issue.PrefixOverride != "" {
+			prefix = issue.PrefixOverride
+		} else if issue.IDPrefix != "" {
+			prefix = configPrefix + "-" + issue.IDPrefix
+		}
+		generatedID, err := generateIssueID(ctx, tx, s.idGenerator(), prefix, issue, actor, reserved)
+		if err != nil {
+			return fmt.Errorf("failed to generate issue ID: %w", err)
+		}
+		issue.ID = generatedID
+		reserved[generatedID] = true
+	}
+
+	issueRows := make([][]interface{}, len(issues))
+	eventRows := make([][]interface{}, len(issues))
+	dirtyRows := make([][]interface{}, len(issues))
+	now := time.Now().UTC()
+	for i, issue := range issues {
+		issueRows[i] = issueInsertArgs(issue)
+		eventRows[i] = []interface{}{issue.ID, types.EventCreated, actor, "", ""}
+		dirtyRows[i] = []interface{}{issue.ID, now}
+	}
+
+	if err := execBatchInsert(ctx, tx, s.maxPacket,
+		"INSERT INTO issues ("+strings.Join(issueInsertColumns, ", ")+")", "", issueRows); err != nil {
+		return fmt.Errorf("failed to insert issues: %w", err)
+	}
+	if err := execBatchInsert(ctx, tx, s.maxPacket,
+		"INSERT INTO events (issue_id, event_type, actor, old_value, new_value)", "", eventRows); err != nil {
+		return fmt.Errorf("failed to record creation events: %w", err)
+	}
+	if err := execBatchInsert(ctx, tx, s.maxPacket,
+		"INSERT INTO dirty_issues (issue_id, marked_at)",
+		" ON DUPLICATE KEY UPDATE marked_at = VALUES(marked_at)", dirtyRows); err != nil {
+		return fmt.Errorf("failed to mark issues dirty: %w", err)
+	}
+	return nil
+}
+
+// bulkUpdateChunkSize is the number of IDs BulkUpdateStatus puts in one
+// IN (...) list, well under maxPlaceholders. It is a variable so tests can
+// exercise chunking with a few issues.
+var bulkUpdateChunkSize = 1000
+
+// bulkUpdateActor is recorded as the actor of BulkUpdateStatus changes.
+const bulkUpdateActor = "bulk-update"
+
+// BulkUpdateStatus moves the issues in ids to newStatus in a single
+// transaction, with one UPDATE per bulkUpdateChunkSize IDs, and returns the
+// number of issues whose status changed. IDs that do not exist, soft-deleted
+// and tombstoned issues, and issues already in newStatus are skipped. Changed
+// issues get a new updated_at and version, closed_at is set or cleared as
+// UpdateIssue does, and each change is recorded in the events and
+// issue_audit tables and marks the issue dirty.
+//
+// newStatus must be a built-in or custom status; tombstone is rejected, since
+// deletion goes through DeleteIssue and SoftDeleteIssue.
+func (s *MariaDBStore) BulkUpdateStatus(ctx context.Context, ids []string, newStatus string) (int, error) {
+	if err := s.checkWritable(); err != nil {
+		return 0, err
+	}
+	customStatuses, err := s.GetCustomStatuses(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get custom statuses: %w", err)
+	}
+	status := types.Status(newStatus)
+	if status == types.StatusTombstone || !status.IsValidWithCustom(customStatuses) {
+		return 0, fmt.Errorf("invalid status: %s", newStatus)
+	}
+
+	// Duplicates would otherwise be counted once per chunk they land in
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	var updated int
+	err = s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		updated = 0
+		for start := 0; start < len(unique); start += bulkUpdateChunkSize {
+			n, err := bulkUpdateStatusChunk(ctx, tx, s.maxPacket, unique[start:min(start+bulkUpdateChunkSize, len(unique))], status)
+			if err != nil {
+				return err
+			}
+			updated += n
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to bulk update status: %w", err)
+	}
+	return updated, nil
+}
+
+// ReassignIssues moves every issue assigned to from over to to, in a single
+// transaction, and returns how many issues moved; it is meant for when
+// someone leaves the team. Soft-deleted and tombstoned issues keep their
+// assignee. Moved issues get a new updated_at and version, and each move is
+// recorded by actor in the events and issue_audit tables and marks the issue
+// dirty. to must not be empty; use UpdateIssue to unassign issues.
+func (s *MariaDBStore) ReassignIssues(ctx context.Context, from, to, actor string) (int, error) {
+	if err := s.checkWritable(); err != nil {
+		return 0, err
+	}
+	if to == "" {
+		return 0, errors.New("reassign target must not be empty")
+	}
+	if from == to {
+		return 0, nil
+	}
+
+	var moved int
+	err := s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		moved = 0
+		// The locking read also locks the assignee index range, so no issue
+		// is assigned to from between it and the UPDATE
+		rows, err := tx.QueryContext(ctx, `
+			SELECT id FROM issues
+			WHERE assignee = ? AND deleted_at IS NULL
+			ORDER BY id
+			FOR UPDATE
+		`, from)
+		if err != nil {
+			return fmt.Errorf("failed to read assigned issues: %w", err)
+		}
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				_ = rows.Close()
+				return fmt.Errorf("failed to scan issue id: %w", err)
+			}
+			ids = append(ids, id)
+		}
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to read assigned issues: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		result, err := tx.ExecContext(ctx, `
+			UPDATE issues
+			SET assignee = ?, updated_at = ?, version = version + 1
+			WHERE assignee = ? AND deleted_at IS NULL
+		`, to, now, from)
+		if err != nil {
+			return fmt.Errorf("failed to update assignees: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		oldData, _ := json.Marshal(map[string]interface{}{"assignee": from})
+		newData, _ := json.Marshal(map[string]interface{}{"assignee": to})
+		var auditRows, eventRows, dirtyRows [][]interface{}
+		for _, id := range ids {
+			auditRows = append(auditRows, []interface{}{id, "assignee", from, to, now, actor})
+			eventRows = append(eventRows, []interface{}{id, types.EventUpdated, actor, string(oldData), string(newData)})
+			dirtyRows = append(dirtyRows, []interface{}{id, now})
+		}
+		if err := execBatchInsert(ctx, tx, s.maxPacket,
+			"INSERT INTO issue_audit (issue_id, field, old_value, new_value, changed_at, actor)", "", auditRows); err != nil {
+			return fmt.Errorf("failed to record audit: %w", err)
+		}
+		if err := execBatchInsert(ctx, tx, s.maxPacket,
+			"INSERT INTO events (issue_id, event_type, actor, old_value, new_value)", "", eventRows); err != nil {
+			return fmt.Errorf("failed to record reassign events: %w", err)
+		}
+		if err := execBatchInsert(ctx, tx, s.maxPacket,
+			"INSERT INTO dirty_issues (issue_id, marked_at)",
+			" ON DUPLICATE KEY UPDATE marked_at = VALUES(marked_at)", dirtyRows); err != nil {
+			return fmt.Errorf("failed to mark issues dirty: %w", err)
+		}
+		moved = int(affected)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to reassign issues: %w", err)
+	}
+	return moved, nil
+}
+
+// bulkUpdateStatusChunk updates the status of the issues in ids within tx,
+// returning the number changed. maxPacket is passed to execBatchInsert.
+func bulkUpdateStatusChunk(ctx context.Context, tx *sql.Tx, maxPacket int, ids []string, status types.Status) (int, error) {
+	args := make([]interface{}, 0, len(ids)+1)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	args = append(args, status)
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+
+	// Lock the rows that will change and keep their old status for the audit
+	// nolint:gosec // G201: placeholders contains only ? markers
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT id, status FROM issues
+		WHERE id IN (%s) AND status <> ? AND status <> 'tombstone' AND deleted_at IS NULL
+		FOR UPDATE
+	`, placeholders), args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read issue statuses: %w", err)
+	}
+	oldStatus := make(map[string]types.Status, len(ids))
+	var changing []interface{}
+	for rows.Next() {
+		var id string
+		var old types.Status
+		if err := rows.Scan(&id, &old); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("failed to scan issue status: %w", err)
+		}
+		oldStatus[id] = old
+		changing = append(changing, id)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read issue statuses: %w", err)
+	}
+	if len(changing) == 0 {
+		return 0, nil
+	}
+
+	// closed_at and close_reason are assigned before status, so their CASE
+	// expressions see the old status
+	now := time.Now().UTC()
+	closedAt := "CASE WHEN status = 'closed' THEN NULL ELSE closed_at END"
+	var updateArgs []interface{}
+	if status == types.StatusClosed {
+		closedAt = "?"
+		updateArgs = append(updateArgs, now)
+	}
+	updateArgs = append(updateArgs, status, status, now)
+	updateArgs = append(updateArgs, changing...)
+	// nolint:gosec // G201: closedAt is a constant expression, placeholders contains only ? markers
+	result, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE issues
+		SET closed_at = %s,
+		    close_reason = CASE WHEN status = 'closed' AND ? <> 'closed' THEN '' ELSE close_reason END,
+		    status = ?, updated_at = ?, version = version + 1
+		WHERE id IN (%s)
+	`, closedAt, strings.TrimSuffix(strings.Repeat("?,", len(changing)), ",")), updateArgs...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update statuses: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	var auditRows, eventRows, dirtyRows [][]interface{}
+	for _, id := range changing {
+		id := id.(string)
+		old := oldStatus[id]
+		eventType := types.EventStatusChanged
+		if status == types.StatusClosed {
+			eventType = types.EventClosed
+		} else if old == types.StatusClosed {
+			eventType = types.EventReopened
+		}
+		oldData, _ := json.Marshal(map[string]interface{}{"status": old})
+		newData, _ := json.Marshal(map[string]interface{}{"status": status})
+		auditRows = append(auditRows, []interface{}{id, "status", string(old), string(status), now, bulkUpdateActor})
+		eventRows = append(eventRows, []interface{}{id, eventType, bulkUpdateActor, string(oldData), string(newData)})
+		dirtyRows = append(dirtyRows, []interface{}{id, now})
+	}
+	if err := execBatchInsert(ctx, tx, maxPacket,
+		"INSERT INTO issue_audit (issue_id, field, old_value, new_value, changed_at, actor)", "", auditRows); err != nil {
+		return 0, fmt.Errorf("failed to record audit: %w", err)
+	}
+	if err := execBatchInsert(ctx, tx, maxPacket,
+		"INSERT INTO events (issue_id, event_type, actor, old_value, new_value)", "", eventRows); err != nil {
+		return 0, fmt.Errorf("failed to record status events: %w", err)
+	}
+	if err := execBatchInsert(ctx, tx, maxPacket,
+		"INSERT INTO dirty_issues (issue_id, marked_at)",
+		" ON DUPLICATE KEY UPDATE marked_at = VALUES(marked_at)", dirtyRows); err != nil {
+		return 0, fmt.Errorf("failed to mark issues dirty: %w", err)
+	}
+	return int(affected), nil
+}
+
+// execBatchInsert runs insert (an INSERT ... (columns) clause) followed by a
+// VALUES list for rows and then suffix, splitting rows across as many
+// statements as batchChunks requires for a server whose max_allowed_packet
+// is maxPacket (0 if unknown). If a row cannot fit in a packet on its own,
+// nothing is sent and the error wraps ErrPacketTooLarge.
+func execBatchInsert(ctx context.Context, tx *sql.Tx, maxPacket int, insert, suffix string, rows [][]interface{}) error {
+	if err := checkRowSizes(rows, len(insert)+len(suffix), maxPacket); err != nil {
+		return err
+	}
+	for _, chunk := range batchChunks(rows, batchStatementBytes(maxPacket)) {
+		placeholders := rowPlaceholders(len(chunk[0]))
+		var b strings.Builder
+		b.WriteString(insert)
+		b.WriteString(" VALUES ")
+		args := make([]interface{}, 0, len(chunk)*len(chunk[0]))
+		for i, row := range chunk {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(placeholders)
+			args = append(args, row...)
+		}
+		b.WriteString(suffix)
+		if _, err := tx.ExecContext(ctx, b.String(), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchChunks splits rows into groups that fit in one statement: at most
+// maxPlaceholders parameters and roughly maxBytes of parameter data. A row
+// larger than maxBytes gets a chunk of its own.
+func batchChunks(rows [][]interface{}, maxBytes int) [][][]interface{} {
+	if len(rows) == 0 {
+		return nil
+	}
+	maxRows := maxPlaceholders / len(rows[0])
+
+	var chunks [][][]interface{}
+	start, size := 0, 0
+	for i, row := range rows {
+		rowSize := estimateRowSize(row)
+		if i > start && (i-start >= maxRows || size+rowSize > maxBytes) {
+			chunks = append(chunks, rows[start:i])
+			start, size = i, 0
+		}
+		size += rowSize
+	}
+	return append(chunks, rows[start:])
+}
+
+// batchStatementBytes returns the estimated size batchChunks should keep one
+// statement under for a server whose max_allowed_packet is maxPacket: half
+// the packet, leaving room for the statement text and estimation error, up
+// to maxBatchStatementBytes.
+func batchStatementBytes(maxPacket int) int {
+	if maxPacket <= 0 {
+		return defaultBatchStatementBytes
+	}
+	return min(maxPacket/2, maxBatchStatementBytes)
+}
+
+// checkRowSizes returns an error wrapping ErrPacketTooLarge if a statement
+// of overhead bytes plus any one of rows would exceed maxPacket. A maxPacket
+// of 0 means the limit is unknown, and nothing is checked.
+func checkRowSizes(rows [][]interface{}, overhead, maxPacket int) error {
+	if maxPacket <= 0 {
+		return nil
+	}
+	for i, row := range rows {
+		if size := overhead + estimateRowSize(row); size > maxPacket {
+			return fmt.Errorf("%w: row %d needs about %d bytes, max_allowed_packet is %d", ErrPacketTooLarge, i, size, maxPacket)
+		}
+	}
+	return nil
+}
+
+// probeMaxPacket reads the server's max_allowed_packet into s.maxPacket. On
+// failure it stays 0, and batches fall back to defaultBatchStatementBytes.
+func (s *MariaDBStore) probeMaxPacket(ctx context.Context) {
+	var maxPacket int
+	if err := s.primaryDB().QueryRowContext(ctx, "SELECT @@max_allowed_packet").Scan(&maxPacket); err != nil {
+		s.log().Warn("failed to read max_allowed_packet; using default batch size", "database", s.dbName, "error", err)
+		return
+	}
+	s.maxPacket = maxPacket
+	s.log().Debug("MariaDB max_allowed_packet", "database", s.dbName, "bytes", maxPacket)
+}
+
+// estimateRowSize approximates the bytes a row adds to a statement.
+func estimateRowSize(row []interface{}) int {
+	// Fixed per-value overhead covers numbers, times, NULLs and quoting
+	size := 0
+	for _, v := range row {
+		size += 16
+		switch v := v.(type) {
+		case string:
+			size += len(v)
+		case []byte:
+			size += len(v)
+		}
+	}
+	return size
+}
+
+// rowPlaceholders returns "(?, ?, ...)" with n placeholders.
+func rowPlaceholders(n int) string {
+	return "(" + strings.TrimSuffix(strings.Repeat("?, ", n), ", ") + ")"
+}
@@ -0,0 +1,438 @@
+package mariadb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func newBatchIssues(n int, title string) []*types.Issue {
+	issues := make([]*types.Issue, n)
+	for i := range issues {
+		issues[i] = &types.Issue{
+			Title:     fmt.Sprintf("%s %d", title, i),
+			Status:    types.StatusOpen,
+			Priority:  2,
+			IssueType: types.TypeTask,
+		}
+	}
+	return issues
+}
+
+func TestCreateIssuesBatch(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	issues := newBatchIssues(3, "Batch issue")
+	issues[0].ID = "test-explicit"
+	issues[2].Status = types.StatusClosed
+	if err := store.CreateIssuesBatch(ctx, issues, "tester"); err != nil {
+		t.Fatalf("CreateIssuesBatch failed: %v", err)
+	}
+
+	dirty, err := store.GetDirtyIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetDirtyIssues failed: %v", err)
+	}
+	isDirty := make(map[string]bool, len(dirty))
+	for _, id := range dirty {
+		isDirty[id] = true
+	}
+
+	for _, issue := range issues {
+		got, err := store.GetIssue(ctx, issue.ID)
+		if err != nil || got == nil {
+			t.Fatalf("GetIssue(%q) = %v, %v", issue.ID, got, err)
+		}
+		if got.Title != issue.Title {
+			t.Errorf("GetIssue(%q).Title = %q, want %q", issue.ID, got.Title, issue.Title)
+		}
+		events, err := store.GetEvents(ctx, issue.ID, 0)
+		if err != nil {
+			t.Fatalf("GetEvents failed: %v", err)
+		}
+		if len(events) != 1 || events[0].EventType != types.EventCreated || events[0].Actor != "tester" {
+			t.Errorf("events for %s = %+v, want one creation event by tester", issue.ID, events)
+		}
+		if !isDirty[issue.ID] {
+			t.Errorf("%s not marked dirty", issue.ID)
+		}
+	}
+	if issues[0].ID != "test-explicit" {
+		t.Errorf("explicit ID replaced with %q", issues[0].ID)
+	}
+	if issues[2].ClosedAt == nil {
+		t.Error("closed issue should get closed_at like CreateIssue")
+	}
+}
+
+func TestCreateIssuesBatchDuplicateID(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	existing := &types.Issue{ID: "test-dup", Title: "Existing", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, existing, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	// As with CreateIssue, an existing ID is an error; the batch is all or nothing
+	issues := newBatchIssues(2, "Batch issue")
+	issues[0].ID = "test-new"
+	issues[1].ID = "test-dup"
+	if err := store.CreateIssuesBatch(ctx, issues, "tester"); err == nil {
+		t.Fatal("expected error for duplicate ID")
+	}
+	if got, err := store.GetIssue(ctx, "test-new"); err != nil || got != nil {
+		t.Errorf("GetIssue(test-new) = %v, %v; want nothing persisted", got, err)
+	}
+}
+
+func TestCreateIssuesBatchFailureClearsGeneratedIDs(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	existing := &types.Issue{ID: "test-dup", Title: "Existing", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, existing, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	issues := newBatchIssues(3, "Batch issue")
+	issues[1].ID = "test-dup"
+	if err := store.CreateIssuesBatch(ctx, issues, "tester"); err == nil {
+		t.Fatal("expected error for duplicate ID")
+	}
+	if issues[0].ID != "" || issues[2].ID != "" {
+		t.Errorf("generated IDs %q, %q kept after a failed batch", issues[0].ID, issues[2].ID)
+	}
+	if issues[1].ID != "test-dup" {
+		t.Errorf("explicit ID changed to %q", issues[1].ID)
+	}
+}
+
+func TestCreateIssuesBatchGeneratedIDCollision(t *testing.T) {
+	const workers = 4
+	store, cleanup := setupTestStoreWithConfig(t, &Config{IDGenerator: attemptIDs{}})
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	// Every batch starts from the same candidates, so concurrent batches
+	// collide on generated IDs and must pick others
+	batches := make([][]*types.Issue, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := range batches {
+		batches[w] = newBatchIssues(3, "Batch issue")
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			errs[w] = store.CreateIssuesBatch(ctx, batches[w], "tester")
+		}(w)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for w, batch := range batches {
+		if errs[w] != nil {
+			t.Fatalf("CreateIssuesBatch failed: %v", errs[w])
+		}
+		for _, issue := range batch {
+			if seen[issue.ID] {
+				t.Errorf("ID %s generated twice", issue.ID)
+			}
+			seen[issue.ID] = true
+			if got, err := store.GetIssue(ctx, issue.ID); err != nil || got == nil || got.Title != issue.Title {
+				t.Errorf("GetIssue(%s) = %v, %v", issue.ID, got, err)
+			}
+		}
+	}
+}
+
+func TestBulkUpdateStatus(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	// Page through the IDs two at a time
+	original := bulkUpdateChunkSize
+	bulkUpdateChunkSize = 2
+	defer func() { bulkUpdateChunkSize = original }()
+
+	issues := newBatchIssues(4, "Bulk issue")
+	for i, issue := range issues {
+		issue.ID = fmt.Sprintf("test-bulk-%d", i)
+	}
+	issues[2].Status = types.StatusClosed
+	if err := store.CreateIssuesBatch(ctx, issues, "tester"); err != nil {
+		t.Fatalf("CreateIssuesBatch failed: %v", err)
+	}
+	if err := store.SoftDeleteIssue(ctx, "test-bulk-3"); err != nil {
+		t.Fatalf("SoftDeleteIssue failed: %v", err)
+	}
+
+	// Missing, already closed, soft-deleted and repeated IDs are not counted
+	ids := []string{"test-bulk-0", "test-missing", "test-bulk-1", "test-bulk-2", "test-bulk-3", "test-bulk-0"}
+	updated, err := store.BulkUpdateStatus(ctx, ids, "closed")
+	if err != nil {
+		t.Fatalf("BulkUpdateStatus failed: %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("BulkUpdateStatus = %d, want 2", updated)
+	}
+	for _, id := range []string{"test-bulk-0", "test-bulk-1"} {
+		got, err := store.GetIssue(ctx, id)
+		if err != nil || got == nil {
+			t.Fatalf("GetIssue(%s) = %v, %v", id, got, err)
+		}
+		if got.Status != types.StatusClosed || got.ClosedAt == nil {
+			t.Errorf("%s status, closed_at = %q, %v; want closed with closed_at", id, got.Status, got.ClosedAt)
+		}
+		history, err := store.GetIssueHistory(ctx, id)
+		if err != nil {
+			t.Fatalf("GetIssueHistory failed: %v", err)
+		}
+		if len(history) != 1 || history[0].Field != "status" || *history[0].OldValue != "open" || *history[0].NewValue != "closed" {
+			t.Errorf("%s history = %+v, want one open -> closed entry", id, history)
+		}
+	}
+
+	// Reopening clears closed_at
+	updated, err = store.BulkUpdateStatus(ctx, []string{"test-bulk-0", "test-bulk-2"}, "open")
+	if err != nil || updated != 2 {
+		t.Fatalf("BulkUpdateStatus(open) = %d, %v; want 2", updated, err)
+	}
+	if got, _ := store.GetIssue(ctx, "test-bulk-2"); got == nil || got.Status != types.StatusOpen || got.ClosedAt != nil {
+		t.Errorf("reopened issue = %+v, want open without closed_at", got)
+	}
+
+	for _, status := range []string{"bogus", "tombstone"} {
+		if _, err := store.BulkUpdateStatus(ctx, ids, status); err == nil || !strings.Contains(err.Error(), "invalid status") {
+			t.Errorf("BulkUpdateStatus(%q) = %v, want invalid status error", status, err)
+		}
+	}
+}
+
+func TestReassignIssues(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	issues := newBatchIssues(5, "Reassign")
+	for i, assignee := range []string{"alice", "alice", "alice", "bob", ""} {
+		issues[i].ID = fmt.Sprintf("test-reassign-%d", i)
+		issues[i].Assignee = assignee
+	}
+	issues[1].Status = types.StatusClosed
+	if err := store.CreateIssuesBatch(ctx, issues, "tester"); err != nil {
+		t.Fatalf("CreateIssuesBatch failed: %v", err)
+	}
+	if err := store.SoftDeleteIssue(ctx, "test-reassign-2"); err != nil {
+		t.Fatalf("SoftDeleteIssue failed: %v", err)
+	}
+
+	if _, err := store.ReassignIssues(ctx, "alice", "", "admin"); err == nil {
+		t.Error("ReassignIssues to nobody succeeded")
+	}
+
+	moved, err := store.ReassignIssues(ctx, "alice", "carol", "admin")
+	if err != nil {
+		t.Fatalf("ReassignIssues failed: %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("ReassignIssues = %d, want 2", moved)
+	}
+	// The soft-deleted issue is only visible with IncludeDeleted
+	found, err := store.SearchIssues(ctx, "", types.IssueFilter{IDPrefix: "test-reassign-", IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	assignees := make(map[string]string, len(found))
+	for _, issue := range found {
+		assignees[issue.ID] = issue.Assignee
+	}
+	want := map[string]string{
+		"test-reassign-0": "carol", "test-reassign-1": "carol",
+		"test-reassign-2": "alice", "test-reassign-3": "bob", "test-reassign-4": "",
+	}
+	if !reflect.DeepEqual(assignees, want) {
+		t.Errorf("assignees = %v, want %v", assignees, want)
+	}
+
+	history, err := store.GetIssueHistory(ctx, "test-reassign-0")
+	if err != nil {
+		t.Fatalf("GetIssueHistory failed: %v", err)
+	}
+	if len(history) != 1 || history[0].Field != "assignee" || *history[0].OldValue != "alice" ||
+		*history[0].NewValue != "carol" || history[0].Actor != "admin" {
+		t.Errorf("history = %+v, want one alice -> carol entry by admin", history)
+	}
+	if history, _ := store.GetIssueHistory(ctx, "test-reassign-3"); len(history) != 0 {
+		t.Errorf("untouched issue has history %+v", history)
+	}
+
+	// Nothing left to move
+	if moved, err := store.ReassignIssues(ctx, "alice", "carol", "admin"); err != nil || moved != 0 {
+		t.Errorf("second ReassignIssues = %d, %v; want 0", moved, err)
+	}
+}
+
+func TestBatchChunks(t *testing.T) {
+	row := func(s string) []interface{} { return []interface{}{s} }
+
+	rows := [][]interface{}{row("a"), row("b"), row("c")}
+	if got := batchChunks(rows, 1<<20); len(got) != 1 || len(got[0]) != 3 {
+		t.Errorf("small rows: got %d chunks, want 1 of 3", len(got))
+	}
+
+	// Each row is ~116 bytes; a 250 byte budget fits two per chunk
+	big := strings.Repeat("x", 100)
+	rows = [][]interface{}{row(big), row(big), row(big)}
+	if got := batchChunks(rows, 250); len(got) != 2 || len(got[0]) != 2 || len(got[1]) != 1 {
+		t.Errorf("byte limit: got chunk sizes %v, want [2 1]", chunkSizes(got))
+	}
+
+	// A row over the budget still goes out, alone
+	if got := batchChunks(rows, 10); len(got) != 3 {
+		t.Errorf("oversized rows: got %d chunks, want 3", len(got))
+	}
+
+	// The placeholder limit applies regardless of size
+	wide := make([]interface{}, len(issueInsertColumns))
+	rows = make([][]interface{}, maxPlaceholders/len(wide)+1)
+	for i := range rows {
+		rows[i] = wide
+	}
+	if got := batchChunks(rows, 1<<30); len(got) != 2 || len(got[1]) != 1 {
+		t.Errorf("placeholder limit: got chunk sizes %v", chunkSizes(got))
+	}
+
+	if got := batchChunks(nil, 1<<20); got != nil {
+		t.Errorf("no rows: got %v, want nil", got)
+	}
+}
+
+func chunkSizes(chunks [][][]interface{}) []int {
+	sizes := make([]int, len(chunks))
+	for i, c := range chunks {
+		sizes[i] = len(c)
+	}
+	return sizes
+}
+
+func TestBatchStatementBytes(t *testing.T) {
+	tests := []struct {
+		maxPacket, want int
+	}{
+		{0, defaultBatchStatementBytes},
+		{4096, 2048},
+		{16 << 20, 8 << 20},
+		{1 << 30, maxBatchStatementBytes},
+	}
+	for _, tt := range tests {
+		if got := batchStatementBytes(tt.maxPacket); got != tt.want {
+			t.Errorf("batchStatementBytes(%d) = %d, want %d", tt.maxPacket, got, tt.want)
+		}
+	}
+
+	// A stubbed 1 KiB packet puts four ~116 byte rows in each statement
+	row := []interface{}{strings.Repeat("x", 100)}
+	rows := [][]interface{}{row, row, row, row, row, row, row, row, row}
+	if got := batchChunks(rows, batchStatementBytes(1024)); !reflect.DeepEqual(chunkSizes(got), []int{4, 4, 1}) {
+		t.Errorf("1 KiB packet: got chunk sizes %v, want [4 4 1]", chunkSizes(got))
+	}
+}
+
+func TestCheckRowSizes(t *testing.T) {
+	rows := [][]interface{}{{"small"}, {strings.Repeat("x", 1000)}}
+	if err := checkRowSizes(rows, 100, 0); err != nil {
+		t.Errorf("unknown packet: got %v, want nil", err)
+	}
+	if err := checkRowSizes(rows, 100, 2048); err != nil {
+		t.Errorf("2 KiB packet: got %v, want nil", err)
+	}
+	err := checkRowSizes(rows, 100, 1024)
+	if !errors.Is(err, ErrPacketTooLarge) || !strings.Contains(err.Error(), "row 1") {
+		t.Errorf("1 KiB packet: got %v, want ErrPacketTooLarge for row 1", err)
+	}
+}
+
+func TestCreateIssuesBatchSmallPacket(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	// Stub a small limit: batches split into many statements, and an issue
+	// that cannot fit fails before anything is sent
+	store.maxPacket = 4096
+	issues := newBatchIssues(50, "Small packet")
+	if err := store.CreateIssuesBatch(ctx, issues, "tester"); err != nil {
+		t.Fatalf("CreateIssuesBatch failed: %v", err)
+	}
+	for _, issue := range issues {
+		if got, err := store.GetIssue(ctx, issue.ID); err != nil || got == nil {
+			t.Errorf("GetIssue(%s) = %v, %v; want the issue", issue.ID, got, err)
+		}
+	}
+
+	huge := newBatchIssues(2, "Huge")
+	huge[1].Description = strings.Repeat("x", 8192)
+	if err := store.CreateIssuesBatch(ctx, huge, "tester"); !errors.Is(err, ErrPacketTooLarge) {
+		t.Errorf("CreateIssuesBatch(huge) = %v, want ErrPacketTooLarge", err)
+	}
+	if err := store.CreateIssue(ctx, huge[1], "tester"); !errors.Is(err, ErrPacketTooLarge) {
+		t.Errorf("CreateIssue(huge) = %v, want ErrPacketTooLarge", err)
+	}
+}
+
+func BenchmarkCreateIssuesBatch(b *testing.B) {
+	store, cleanup := setupTestStore(b)
+	defer cleanup()
+
+	ctx, cancel := testContext(b)
+	defer cancel()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := store.CreateIssuesBatch(ctx, newBatchIssues(100, fmt.Sprintf("Batch %d", i)), "bench"); err != nil {
+			b.Fatalf("CreateIssuesBatch failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkCreateIssueLoop(b *testing.B) {
+	store, cleanup := setupTestStore(b)
+	defer cleanup()
+
+	ctx, cancel := testContext(b)
+	defer cancel()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, issue := range newBatchIssues(100, fmt.Sprintf("Loop %d", i)) {
+			if err := store.CreateIssue(ctx, issue, "bench"); err != nil {
+				b.Fatalf("CreateIssue failed: %v", err)
+			}
+		}
+	}
+}
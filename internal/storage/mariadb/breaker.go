@@ -0,0 +1,120 @@
+package mariadb
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+)
+
+// ErrCircuitOpen is returned without contacting the server while the circuit
+// breaker is open (see Config.BreakerThreshold).
+var ErrCircuitOpen = storage.ErrCircuitOpen
+
+// Defaults for Config.BreakerWindow and Config.BreakerCooldown
+const (
+	DefaultBreakerWindow   = 10 * time.Second
+	DefaultBreakerCooldown = 5 * time.Second
+)
+
+// circuitBreaker stops retried operations from reaching a server that keeps
+// failing. It is closed while the server answers. After threshold
+// consecutive connection failures within window it opens, and operations
+// fail fast for cooldown. Then it is half-open: one probe operation is let
+// through, and its outcome closes the breaker or opens it again.
+type circuitBreaker struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+	now       func() time.Time // time.Now; replaced in tests
+
+	mu           sync.Mutex
+	failures     int       // Consecutive failures since firstFailure
+	firstFailure time.Time // Start of the current run of failures
+	openUntil    time.Time // Zero while closed
+	probing      bool      // A half-open probe is in flight
+}
+
+// newCircuitBreaker returns the breaker configured by cfg, or nil if it is
+// disabled.
+func newCircuitBreaker(cfg *Config) *circuitBreaker {
+	if cfg.BreakerThreshold == 0 {
+		return nil
+	}
+	b := &circuitBreaker{
+		threshold: cfg.BreakerThreshold,
+		window:    cfg.BreakerWindow,
+		cooldown:  cfg.BreakerCooldown,
+		now:       time.Now,
+	}
+	if b.window == 0 {
+		b.window = DefaultBreakerWindow
+	}
+	if b.cooldown == 0 {
+		b.cooldown = DefaultBreakerCooldown
+	}
+	return b
+}
+
+// validateBreakerConfig rejects negative breaker settings.
+func validateBreakerConfig(cfg *Config) error {
+	if cfg.BreakerThreshold < 0 || cfg.BreakerWindow < 0 || cfg.BreakerCooldown < 0 {
+		return fmt.Errorf("invalid MariaDB circuit breaker config: values must not be negative")
+	}
+	return nil
+}
+
+// allow reports whether an attempt may reach the server, returning
+// ErrCircuitOpen if not. A nil breaker allows everything.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openUntil.IsZero() {
+		return nil
+	}
+	if b.now().Before(b.openUntil) || b.probing {
+		return fmt.Errorf("%w: retrying after %s", ErrCircuitOpen, b.openUntil.Format(time.RFC3339))
+	}
+	b.probing = true
+	return nil
+}
+
+// record updates the breaker with the outcome of an attempt let through by
+// allow. Only connection failures count against the server; any other
+// result, including a lock conflict, shows it is answering. It reports
+// whether this attempt opened the breaker.
+func (b *circuitBreaker) record(err error) (opened bool) {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil || !isRetryableError(err) || isLockConflictError(err) {
+		b.failures = 0
+		b.openUntil = time.Time{}
+		b.probing = false
+		return false
+	}
+
+	now := b.now()
+	if b.probing {
+		b.probing = false
+		b.openUntil = now.Add(b.cooldown)
+		return true
+	}
+	if b.failures == 0 || now.Sub(b.firstFailure) > b.window {
+		b.failures = 0
+		b.firstFailure = now
+	}
+	b.failures++
+	if b.failures < b.threshold {
+		return false
+	}
+	b.failures = 0
+	b.openUntil = now.Add(b.cooldown)
+	return true
+}
@@ -0,0 +1,132 @@
+package mariadb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for circuitBreaker.now.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestBreaker(threshold int) (*circuitBreaker, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := newCircuitBreaker(&Config{BreakerThreshold: threshold})
+	b.now = clock.now
+	return b, clock
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	b, clock := newTestBreaker(3)
+	errConn := errors.New("driver: bad connection")
+
+	for i := 0; i < 2; i++ {
+		if err := b.allow(); err != nil {
+			t.Fatalf("allow before threshold = %v", err)
+		}
+		if b.record(errConn) {
+			t.Fatalf("breaker opened after %d failures, want 3", i+1)
+		}
+	}
+	if !b.record(errConn) {
+		t.Fatal("breaker should open on the third consecutive failure")
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow while open = %v, want ErrCircuitOpen", err)
+	}
+
+	// After the cooldown a single probe goes through; a failed probe reopens
+	clock.advance(DefaultBreakerCooldown)
+	if err := b.allow(); err != nil {
+		t.Fatalf("probe allow = %v", err)
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second caller during probe = %v, want ErrCircuitOpen", err)
+	}
+	if !b.record(errConn) {
+		t.Error("failed probe should reopen the breaker")
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow after failed probe = %v, want ErrCircuitOpen", err)
+	}
+
+	// A successful probe closes it
+	clock.advance(DefaultBreakerCooldown)
+	if err := b.allow(); err != nil {
+		t.Fatalf("probe allow = %v", err)
+	}
+	b.record(nil)
+	for i := 0; i < 3; i++ {
+		if err := b.allow(); err != nil {
+			t.Fatalf("allow after recovery = %v", err)
+		}
+	}
+}
+
+func TestCircuitBreakerCountsOnlyConsecutiveFailuresInWindow(t *testing.T) {
+	b, clock := newTestBreaker(2)
+	errConn := errors.New("driver: bad connection")
+
+	// A success or a server error resets the run
+	b.record(errConn)
+	b.record(nil)
+	if b.record(errConn) {
+		t.Error("failures separated by a success should not open the breaker")
+	}
+	b.record(errors.New("syntax error"))
+	if b.record(errConn) {
+		t.Error("failures separated by a server error should not open the breaker")
+	}
+
+	// Failures further apart than the window start a new run
+	clock.advance(DefaultBreakerWindow + time.Second)
+	if b.record(errConn) {
+		t.Error("a failure outside the window should start a new run")
+	}
+	if !b.record(errConn) {
+		t.Error("two failures within the window should open the breaker")
+	}
+}
+
+func TestWithRetry_CircuitBreaker(t *testing.T) {
+	b, clock := newTestBreaker(3)
+	store := &MariaDBStore{storeState: newStoreState(nil, ""), breaker: b, retryCfg: retrySettings{initialInterval: time.Millisecond, maxInterval: time.Millisecond}}
+
+	calls := 0
+	failing := func() error {
+		calls++
+		return errors.New("driver: bad connection")
+	}
+	err := store.withRetry(context.Background(), failing)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("withRetry error = %v, want ErrCircuitOpen", err)
+	}
+	if calls != 3 {
+		t.Errorf("op ran %d times, want 3 before the breaker opened", calls)
+	}
+
+	// While open, operations fail without reaching the server
+	calls = 0
+	if err := store.withRetry(context.Background(), failing); !errors.Is(err, ErrCircuitOpen) || calls != 0 {
+		t.Errorf("withRetry while open = %v after %d calls, want ErrCircuitOpen after none", err, calls)
+	}
+
+	clock.advance(DefaultBreakerCooldown)
+	if err := store.withRetry(context.Background(), func() error { return nil }); err != nil {
+		t.Fatalf("probe failed: %v", err)
+	}
+	if err := store.withRetry(context.Background(), func() error { return nil }); err != nil {
+		t.Errorf("withRetry after recovery = %v", err)
+	}
+}
+
+func TestNewRejectsNegativeBreakerConfig(t *testing.T) {
+	// Rejected before connecting, so no server is needed
+	if _, err := New(context.Background(), &Config{BreakerThreshold: -1}); err == nil {
+		t.Error("expected error for negative BreakerThreshold")
+	}
+}
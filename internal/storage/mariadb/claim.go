@@ -0,0 +1,257 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// claimCandidates is the number of ready issues ClaimNextReadyIssue tries per
+// round. Workers racing for the top issue fall through to the next ones
+// instead of re-reading the queue after every lost race.
+const claimCandidates = 10
+
+// ClaimNextReadyIssue turns the ready_issues view into a work queue: it claims
+// the unassigned ready issue with the highest priority (then lowest ID) for
+// workerID, setting its assignee and moving it to in_progress as ClaimIssue
+// does, and returns it. It returns nil if no unassigned issue is ready.
+//
+// Concurrent workers, in this process or others, never claim the same issue.
+// On servers with SKIP LOCKED (see Capabilities) the next issue is locked with
+// SELECT ... FOR UPDATE SKIP LOCKED, so workers pass over issues being
+// claimed by others. Otherwise each candidate is claimed with a conditional
+// UPDATE that only matches an issue still open and unassigned, and a worker
+// that loses the race for one candidate moves on to the next.
+// With SKIP LOCKED, nil is also returned when every ready issue is being
+// claimed by another worker at that moment. Inside WithTx, nil is also
+// returned when other workers have claimed every candidate the transaction
+// can see.
+func (s *MariaDBStore) ClaimNextReadyIssue(ctx context.Context, workerID string) (*types.Issue, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
+	if workerID == "" {
+		return nil, errors.New("worker ID must not be empty")
+	}
+
+	var id string
+	var err error
+	if s.caps.SkipLocked {
+		id, err = s.claimNextSkipLocked(ctx, workerID)
+	} else {
+		id, err = s.claimNextByUpdate(ctx, "", workerID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim ready issue: %w", err)
+	}
+	if id == "" {
+		return nil, nil
+	}
+	return s.GetIssue(ctx, id)
+}
+
+// NextReadyForAssignee returns the ready issue assigned to assignee with the
+// highest priority (then lowest ID): what assignee should work on next. It
+// returns nil if none of their issues is ready. Issues blocked by open
+// dependencies are never returned, since they are not in the ready_issues
+// view.
+//
+// With claim, the issue is also moved to in_progress with a conditional
+// UPDATE, as ClaimNextReadyIssue does, so two agents acting for the same
+// assignee never both start it. Inside WithTx, nil is returned when other
+// agents have started every issue the transaction can see.
+func (s *MariaDBStore) NextReadyForAssignee(ctx context.Context, assignee string, claim bool) (*types.Issue, error) {
+	if claim {
+		if err := s.checkWritable(); err != nil {
+			return nil, err
+		}
+	} else if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	if assignee == "" {
+		return nil, errors.New("assignee must not be empty")
+	}
+
+	var id string
+	if claim {
+		var err error
+		if id, err = s.claimNextByUpdate(ctx, assignee, assignee); err != nil {
+			return nil, fmt.Errorf("failed to claim ready issue: %w", err)
+		}
+	} else {
+		err := s.withReadRetry(ctx, func() error {
+			err := s.dbOrTx().QueryRowContext(ctx, `
+				SELECT id FROM ready_issues
+				WHERE assignee = ?
+				ORDER BY priority ASC, id ASC
+				LIMIT 1
+			`, assignee).Scan(&id)
+			if err == sql.ErrNoRows {
+				id = ""
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next ready issue: %w", err)
+		}
+	}
+	if id == "" {
+		return nil, nil
+	}
+	return s.GetIssue(ctx, id)
+}
+
+// claimNextSkipLocked claims the next ready issue that no other transaction
+// has locked, returning its ID, or "" if there is none.
+func (s *MariaDBStore) claimNextSkipLocked(ctx context.Context, workerID string) (string, error) {
+	var claimed string
+	err := s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		claimed = ""
+		// The locking read applies to issues only, not to the rows the
+		// ready_issues subquery reads
+		var id string
+		err := tx.QueryRowContext(ctx, `
+			SELECT i.id FROM issues i
+			WHERE i.status = 'open' AND (i.assignee = '' OR i.assignee IS NULL) AND i.deleted_at IS NULL
+			  AND i.id IN (SELECT id FROM ready_issues)
+			ORDER BY i.priority ASC, i.id ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		`).Scan(&id)
+		if err == sql.ErrNoRows {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to lock next ready issue: %w", err)
+		}
+		ok, err := claimReadyIssue(ctx, tx, id, "", workerID)
+		if ok {
+			claimed = id
+		}
+		return err
+	})
+	return claimed, err
+}
+
+// claimNextByUpdate claims the next ready issue assigned to owner ("" for
+// unassigned) with conditional UPDATEs, for servers without SKIP LOCKED,
+// returning its ID, or "" if there is none.
+func (s *MariaDBStore) claimNextByUpdate(ctx context.Context, owner, workerID string) (string, error) {
+	for {
+		var claimed string
+		var candidates int
+		err := s.withRetryTx(ctx, func(tx *sql.Tx) error {
+			claimed = ""
+			ids, err := readyCandidates(ctx, tx, owner)
+			if err != nil {
+				return err
+			}
+			candidates = len(ids)
+			for _, id := range ids {
+				ok, err := claimReadyIssue(ctx, tx, id, owner, workerID)
+				if err != nil {
+					return err
+				}
+				if ok {
+					claimed = id
+					return nil
+				}
+			}
+			return nil
+		})
+		// Inside WithTx, the enclosing transaction keeps reading the snapshot
+		// it started with, so reading the queue again finds the same
+		// candidates: give up instead.
+		if err != nil || claimed != "" || candidates == 0 || s.tx != nil {
+			return claimed, err
+		}
+		// Other workers took every candidate; read the queue again
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+	}
+}
+
+// readyCandidates returns the IDs of the first claimCandidates ready issues
+// assigned to owner ("" for unassigned), in queue order.
+func readyCandidates(ctx context.Context, tx *sql.Tx, owner string) ([]string, error) {
+	filter, args := assigneeFilter(owner)
+	// nolint:gosec // G201: filter is one of two constant conditions
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT id FROM ready_issues
+		WHERE %s
+		ORDER BY priority ASC, id ASC
+		LIMIT ?
+	`, filter), append(args, claimCandidates)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ready issues: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan issue id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// claimReadyIssue assigns issue id to workerID if it is still open and
+// assigned to owner ("" for unassigned), reporting whether it did. The UPDATE
+// takes the row lock and re-checks the condition on the latest committed
+// row, so of two workers claiming the same issue only one matches it.
+func claimReadyIssue(ctx context.Context, tx *sql.Tx, id, owner, workerID string) (bool, error) {
+	filter, args := assigneeFilter(owner)
+	var rowsAffected int64
+	err := updateAudited(ctx, tx, id, workerID, claimAuditColumns, func() error {
+		// nolint:gosec // G201: filter is one of two constant conditions
+		result, err := tx.ExecContext(ctx, fmt.Sprintf(`
+			UPDATE issues
+			SET assignee = ?, status = 'in_progress', updated_at = ?, version = version + 1
+			WHERE id = ? AND status = 'open' AND %s AND deleted_at IS NULL
+		`, filter), append([]interface{}{workerID, time.Now().UTC(), id}, args...)...)
+		if err != nil {
+			return fmt.Errorf("failed to claim issue %s: %w", id, err)
+		}
+		rowsAffected, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	newData, _ := json.Marshal(map[string]interface{}{
+		"assignee": workerID,
+		"status":   "in_progress",
+	})
+	if err := recordEvent(ctx, tx, id, "claimed", workerID, "", string(newData)); err != nil {
+		return false, fmt.Errorf("failed to record claim event: %w", err)
+	}
+	if err := markDirty(ctx, tx, id); err != nil {
+		return false, fmt.Errorf("failed to mark dirty: %w", err)
+	}
+	return true, nil
+}
+
+// assigneeFilter returns the condition matching issues assigned to owner, or
+// unassigned issues if owner is "", and its arguments.
+func assigneeFilter(owner string) (string, []interface{}) {
+	if owner == "" {
+		return "(assignee = '' OR assignee IS NULL)", nil
+	}
+	return "assignee = ?", []interface{}{owner}
+}
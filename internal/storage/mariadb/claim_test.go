@@ -0,0 +1,232 @@
+package mariadb
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// claimPaths runs fn against both claim implementations, forcing the
+// capability flag the probe set. The SKIP LOCKED path is skipped on servers
+// without it.
+func claimPaths(t *testing.T, fn func(t *testing.T, store *MariaDBStore)) {
+	for _, skipLocked := range []bool{false, true} {
+		name := "update"
+		if skipLocked {
+			name = "skip_locked"
+		}
+		t.Run(name, func(t *testing.T) {
+			store, cleanup := setupTestStore(t)
+			defer cleanup()
+			if skipLocked && !store.Capabilities().SkipLocked {
+				t.Skip("server does not support SKIP LOCKED")
+			}
+			store.caps.SkipLocked = skipLocked
+			fn(t, store)
+		})
+	}
+}
+
+func TestClaimNextReadyIssueOrder(t *testing.T) {
+	claimPaths(t, testClaimNextReadyIssueOrder)
+}
+
+func testClaimNextReadyIssueOrder(t *testing.T, store *MariaDBStore) {
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for _, issue := range []*types.Issue{
+		{ID: "test-low", Title: "Low", Status: types.StatusOpen, Priority: 3, IssueType: types.TypeTask},
+		{ID: "test-high", Title: "High", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+		{ID: "test-blocked", Title: "Blocked", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeTask},
+		{ID: "test-taken", Title: "Taken", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeTask, Assignee: "bob"},
+	} {
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", issue.ID, err)
+		}
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: "test-blocked", DependsOnID: "test-low", Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	for _, want := range []string{"test-high", "test-low"} {
+		issue, err := store.ClaimNextReadyIssue(ctx, "worker-1")
+		if err != nil {
+			t.Fatalf("ClaimNextReadyIssue failed: %v", err)
+		}
+		if issue == nil || issue.ID != want {
+			t.Fatalf("claimed %v, want %s", issue, want)
+		}
+		if issue.Assignee != "worker-1" || issue.Status != types.StatusInProgress {
+			t.Errorf("claimed issue has assignee %q, status %q; want worker-1, in_progress", issue.Assignee, issue.Status)
+		}
+	}
+
+	// test-blocked is still blocked by the in-progress test-low
+	issue, err := store.ClaimNextReadyIssue(ctx, "worker-1")
+	if err != nil || issue != nil {
+		t.Errorf("ClaimNextReadyIssue on an empty queue = %v, %v; want nil, nil", issue, err)
+	}
+}
+
+func TestClaimNextReadyIssueConcurrent(t *testing.T) {
+	claimPaths(t, testClaimNextReadyIssueConcurrent)
+}
+
+func testClaimNextReadyIssueConcurrent(t *testing.T, store *MariaDBStore) {
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	const issues, workers = 40, 16
+	for i := 0; i < issues; i++ {
+		issue := &types.Issue{ID: fmt.Sprintf("test-%03d", i), Title: "Work", Status: types.StatusOpen, Priority: i % 3, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", issue.ID, err)
+		}
+	}
+
+	var mu sync.Mutex
+	claimedBy := make(map[string]string)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		worker := fmt.Sprintf("worker-%d", w)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				issue, err := store.ClaimNextReadyIssue(ctx, worker)
+				if err != nil {
+					t.Errorf("%s: ClaimNextReadyIssue failed: %v", worker, err)
+					return
+				}
+				if issue == nil {
+					return
+				}
+				mu.Lock()
+				if other, dup := claimedBy[issue.ID]; dup {
+					t.Errorf("%s claimed by both %s and %s", issue.ID, other, worker)
+				}
+				claimedBy[issue.ID] = worker
+				mu.Unlock()
+				if issue.Assignee != worker {
+					t.Errorf("%s returned with assignee %q, want %s", issue.ID, issue.Assignee, worker)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(claimedBy) != issues {
+		t.Errorf("claimed %d issues, want each of the %d exactly once", len(claimedBy), issues)
+	}
+	ready, err := store.ListReadyIssues(ctx, ReadyFilter{})
+	if err != nil {
+		t.Fatalf("ListReadyIssues failed: %v", err)
+	}
+	if len(ready) != 0 {
+		t.Errorf("%d issues still ready after draining the queue", len(ready))
+	}
+}
+
+func TestNextReadyForAssignee(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for _, issue := range []*types.Issue{
+		{ID: "test-mine-low", Title: "Mine, low", Priority: 3, Assignee: "alice"},
+		{ID: "test-mine-high", Title: "Mine, high", Priority: 1, Assignee: "alice"},
+		{ID: "test-mine-blocked", Title: "Mine, blocked", Priority: 0, Assignee: "alice"},
+		{ID: "test-theirs", Title: "Theirs", Priority: 0, Assignee: "bob"},
+		{ID: "test-nobody", Title: "Nobody's", Priority: 0},
+	} {
+		issue.Status, issue.IssueType = types.StatusOpen, types.TypeTask
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", issue.ID, err)
+		}
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: "test-mine-blocked", DependsOnID: "test-theirs", Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	// Peeking leaves the issue alone
+	for i := 0; i < 2; i++ {
+		issue, err := store.NextReadyForAssignee(ctx, "alice", false)
+		if err != nil || issue == nil || issue.ID != "test-mine-high" || issue.Status != types.StatusOpen {
+			t.Fatalf("NextReadyForAssignee(alice) = %v, %v; want open test-mine-high", issue, err)
+		}
+	}
+
+	for _, want := range []string{"test-mine-high", "test-mine-low"} {
+		issue, err := store.NextReadyForAssignee(ctx, "alice", true)
+		if err != nil || issue == nil || issue.ID != want {
+			t.Fatalf("NextReadyForAssignee(alice, claim) = %v, %v; want %s", issue, err, want)
+		}
+		if issue.Status != types.StatusInProgress || issue.Assignee != "alice" {
+			t.Errorf("claimed issue has assignee %q, status %q; want alice, in_progress", issue.Assignee, issue.Status)
+		}
+	}
+
+	// test-mine-blocked waits on bob's issue
+	for _, claim := range []bool{false, true} {
+		if issue, err := store.NextReadyForAssignee(ctx, "alice", claim); err != nil || issue != nil {
+			t.Errorf("NextReadyForAssignee(alice, %v) with only blocked work = %v, %v; want nil", claim, issue, err)
+		}
+	}
+	if issue, err := store.NextReadyForAssignee(ctx, "carol", false); err != nil || issue != nil {
+		t.Errorf("NextReadyForAssignee(carol) = %v, %v; want nil", issue, err)
+	}
+	if _, err := store.NextReadyForAssignee(ctx, "", false); err == nil {
+		t.Error("NextReadyForAssignee with no assignee succeeded")
+	}
+}
+
+// TestClaimInsideWithTxStaleSnapshot checks that a claim inside WithTx gives
+// up when others have claimed every issue its snapshot still shows as ready,
+// rather than reading the same snapshot again until ctx expires.
+func TestClaimInsideWithTxStaleSnapshot(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	store.caps.SkipLocked = false
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for _, issue := range []*types.Issue{
+		{ID: "test-mine", Title: "Mine", Priority: 1, Assignee: "alice"},
+		{ID: "test-free", Title: "Free", Priority: 1},
+	} {
+		issue.Status, issue.IssueType = types.StatusOpen, types.TypeTask
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", issue.ID, err)
+		}
+	}
+
+	err := store.WithTx(ctx, func(tx *MariaDBStore) error {
+		// Take the transaction's snapshot while both issues are ready
+		if _, err := tx.ListReadyIssues(ctx, ReadyFilter{}); err != nil {
+			return err
+		}
+		if issue, err := store.NextReadyForAssignee(ctx, "alice", true); err != nil || issue == nil {
+			return fmt.Errorf("NextReadyForAssignee(alice, claim) outside the transaction = %v, %v", issue, err)
+		}
+		if issue, err := store.ClaimNextReadyIssue(ctx, "worker-1"); err != nil || issue == nil {
+			return fmt.Errorf("ClaimNextReadyIssue outside the transaction = %v, %v", issue, err)
+		}
+
+		if issue, err := tx.NextReadyForAssignee(ctx, "alice", true); err != nil || issue != nil {
+			t.Errorf("NextReadyForAssignee(alice, claim) in WithTx = %v, %v; want nil", issue, err)
+		}
+		if issue, err := tx.ClaimNextReadyIssue(ctx, "worker-2"); err != nil || issue != nil {
+			t.Errorf("ClaimNextReadyIssue in WithTx = %v, %v; want nil", issue, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// cloneBatchRows is how many rows CloneTo reads before inserting them into
+// the target; execBatchInsert splits each batch further to fit the packet.
+const cloneBatchRows = 1000
+
+// CloneTo copies the Beads dataset into the database targetDB on the same
+// server, for "staging from production" workflows. It creates targetDB,
+// initializes the schema there (with the store's table prefix, charset and
+// collation), and copies every row of every Beads table: issues,
+// dependencies, config, and the rest. The rows are read from one consistent
+// snapshot of the source and inserted in batches, one transaction per table.
+//
+// targetDB must be a valid database name other than the store's own. If it
+// already holds tables, CloneTo refuses unless force is set, in which case
+// the whole target database is dropped and recreated first. A clone that
+// fails partway leaves the target partially populated; run it again with
+// force to start over.
+func (s *MariaDBStore) CloneTo(ctx context.Context, targetDB string, force bool) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	if s.tx != nil {
+		return fmt.Errorf("cannot clone the database inside a WithTx transaction")
+	}
+	if err := validateDatabaseName(targetDB); err != nil {
+		return err
+	}
+	if targetDB == s.dbName {
+		return fmt.Errorf("cannot clone database %s onto itself", targetDB)
+	}
+	db, done, err := s.acquire()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	var existing int
+	err = db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM information_schema.tables
+		WHERE table_schema = ?
+	`, targetDB).Scan(&existing)
+	if err != nil {
+		return fmt.Errorf("failed to check clone target %s: %w", targetDB, err)
+	}
+	if existing > 0 {
+		if !force {
+			return fmt.Errorf("clone target %s already has %d tables; use force to overwrite it", targetDB, existing)
+		}
+		if _, err := db.ExecContext(ctx, "DROP DATABASE "+quoteIdentifier(targetDB)); err != nil {
+			return fmt.Errorf("failed to drop clone target %s: %w", targetDB, err)
+		}
+	}
+	_, err = db.ExecContext(ctx, "CREATE DATABASE IF NOT EXISTS "+quoteIdentifier(targetDB)+tableOptions(s.charset, s.collation))
+	if err != nil {
+		return fmt.Errorf("failed to create clone target %s: %w", targetDB, err)
+	}
+
+	target, err := s.openClonePool(targetDB)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = target.Close() }()
+	if err := initSchemaOnDB(ctx, target, s.charset, s.collation); err != nil {
+		return fmt.Errorf("failed to initialize clone target %s: %w", targetDB, err)
+	}
+
+	// One read-only transaction gives every table the same snapshot
+	src, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to start clone snapshot: %w", err)
+	}
+	defer func() { _ = src.Rollback() }()
+
+	total := 0
+	for _, table := range tableNames {
+		exists, err := tableExists(ctx, db, table)
+		if err != nil {
+			return fmt.Errorf("failed to check table %s: %w", s.tablePrefix+table, err)
+		}
+		if !exists {
+			continue
+		}
+		n, err := s.cloneTable(ctx, src, target, table)
+		if err != nil {
+			return fmt.Errorf("failed to clone table %s: %w", s.tablePrefix+table, err)
+		}
+		total += n
+	}
+	s.log().Info("MariaDB database cloned", "database", s.dbName, "target", targetDB, "rows", total)
+	return nil
+}
+
+// openClonePool opens a pool on targetDB with the store's credentials and
+// connection options, including its table prefix.
+func (s *MariaDBStore) openClonePool(targetDB string) (*sql.DB, error) {
+	mc, err := mysql.ParseDSN(s.currentConnStr())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse connection string: %w", err)
+	}
+	mc.DBName = targetDB
+	opts := s.conn
+	opts.slowQuery.database = targetDB
+	target, err := openDB(mc.FormatDSN(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open clone target %s: %w", targetDB, err)
+	}
+	return target, nil
+}
+
+// cloneTable replaces the rows of table in target with those read through
+// src, in one target transaction, and returns how many rows it copied. The
+// rows schema init put in the target (such as the default config) are
+// removed first.
+func (s *MariaDBStore) cloneTable(ctx context.Context, src *sql.Tx, target *sql.DB, table string) (int, error) {
+	rows, err := src.QueryContext(ctx, "SELECT * FROM "+table)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = quoteIdentifier(c)
+	}
+	insert := "INSERT INTO " + table + " (" + strings.Join(quoted, ", ") + ")"
+
+	tx, err := target.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	batch := make([][]interface{}, 0, cloneBatchRows)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return 0, err
+		}
+		batch = append(batch, values)
+		if len(batch) == cloneBatchRows {
+			if err := execBatchInsert(ctx, tx, s.maxPacket, insert, "", batch); err != nil {
+				return 0, err
+			}
+			count += len(batch)
+			batch = batch[:0]
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(batch) > 0 {
+		if err := execBatchInsert(ctx, tx, s.maxPacket, insert, "", batch); err != nil {
+			return 0, err
+		}
+		count += len(batch)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,84 @@
+package mariadb
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestCloneTo(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	issues := newBatchIssues(25, "Clone issue")
+	if err := store.CreateIssuesBatch(ctx, issues, "tester"); err != nil {
+		t.Fatalf("CreateIssuesBatch failed: %v", err)
+	}
+	for i := 1; i < len(issues); i++ {
+		dep := &types.Dependency{IssueID: issues[i].ID, DependsOnID: issues[i-1].ID, Type: types.DepBlocks}
+		if err := store.AddDependency(ctx, dep, "tester"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	if err := store.AddLabel(ctx, issues[0].ID, "staging", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := store.SetConfig(ctx, "clone.marker", "copied"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	target := store.dbName + "_c"
+	db := store.UnderlyingDB()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+		defer cancel()
+		_, _ = db.ExecContext(ctx, "DROP DATABASE IF EXISTS "+quoteIdentifier(target))
+	}()
+
+	if err := store.CloneTo(ctx, target, false); err != nil {
+		t.Fatalf("CloneTo failed: %v", err)
+	}
+	for _, table := range []string{"issues", "dependencies", "labels", "events", "config", "dirty_issues"} {
+		var want, got int
+		if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&want); err != nil {
+			t.Fatalf("counting source %s failed: %v", table, err)
+		}
+		if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+quoteIdentifier(target)+"."+table).Scan(&got); err != nil {
+			t.Fatalf("counting clone %s failed: %v", table, err)
+		}
+		if got != want || want == 0 {
+			t.Errorf("%s: clone has %d rows, source has %d", table, got, want)
+		}
+	}
+
+	clone, err := New(ctx, &Config{Database: target})
+	if err != nil {
+		t.Fatalf("New(clone) failed: %v", err)
+	}
+	defer clone.Close()
+	if got, err := clone.GetConfig(ctx, "clone.marker"); err != nil || got != "copied" {
+		t.Errorf("clone config = %q, %v; want copied", got, err)
+	}
+	if got, err := clone.GetIssue(ctx, issues[3].ID); err != nil || got == nil || got.Title != issues[3].Title {
+		t.Errorf("clone GetIssue(%s) = %v, %v", issues[3].ID, got, err)
+	}
+
+	// A populated target is only overwritten with force
+	if err := store.CloneTo(ctx, target, false); err == nil || !strings.Contains(err.Error(), "force") {
+		t.Errorf("CloneTo over populated target = %v, want refusal", err)
+	}
+	if err := store.CloneTo(ctx, target, true); err != nil {
+		t.Errorf("CloneTo with force failed: %v", err)
+	}
+
+	for _, name := range []string{store.dbName, "bad-name", ""} {
+		if err := store.CloneTo(ctx, name, true); err == nil {
+			t.Errorf("CloneTo(%q) succeeded, want error", name)
+		}
+	}
+}
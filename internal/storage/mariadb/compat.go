@@ -0,0 +1,165 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/storage"
+)
+
+// Minimum server versions. The schema uses expression defaults on JSON
+// columns, which MySQL added in 8.0.13 and MariaDB in 10.2.1, and the
+// ready_issues view uses WITH RECURSIVE, which MariaDB added in 10.2.2.
+var (
+	minMySQLVersion   = serverVersion{8, 0, 13}
+	minMariaDBVersion = serverVersion{10, 2, 2}
+)
+
+// serverVersion is a major.minor.patch server version.
+type serverVersion [3]int
+
+func (v serverVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+func (v serverVersion) less(o serverVersion) bool {
+	for i := range v {
+		if v[i] != o[i] {
+			return v[i] < o[i]
+		}
+	}
+	return false
+}
+
+// serverInfo is what checkServerCompatibility learns about the server.
+type serverInfo struct {
+	version          string // SELECT VERSION()
+	defaultCharset   string // @@character_set_server
+	charsetAvailable bool   // whether the configured charset is installed
+}
+
+// queryServerInfo reads the server version and charset support over db;
+// tests replace it to simulate other servers.
+var queryServerInfo = func(ctx context.Context, db *sql.DB, charset string) (serverInfo, error) {
+	var info serverInfo
+	if err := db.QueryRowContext(ctx, "SELECT VERSION(), @@character_set_server").Scan(&info.version, &info.defaultCharset); err != nil {
+		return info, fmt.Errorf("failed to query server version: %w", err)
+	}
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.CHARACTER_SETS WHERE CHARACTER_SET_NAME = ?", charset).Scan(&count); err != nil {
+		return info, fmt.Errorf("failed to query character sets: %w", err)
+	}
+	info.charsetAvailable = count > 0
+	return info, nil
+}
+
+// checkServerCompatibility verifies that the server behind db is new enough
+// for the schema and supports charset, so an unsupported server fails with a
+// clear message instead of a DDL error partway through schema creation. It
+// reports whether the server is MariaDB rather than MySQL.
+func checkServerCompatibility(ctx context.Context, db *sql.DB, charset string) (mariadb bool, err error) {
+	info, err := queryServerInfo(ctx, db, charset)
+	if err != nil {
+		return false, err
+	}
+
+	version, mariadb, err := parseServerVersion(info.version)
+	if err != nil {
+		return false, err
+	}
+	server, minVersion := "MySQL", minMySQLVersion
+	if mariadb {
+		server, minVersion = "MariaDB", minMariaDBVersion
+	}
+	if version.less(minVersion) {
+		return false, fmt.Errorf("unsupported %s server version %s: beads requires %s %s or newer", server, info.version, server, minVersion)
+	}
+
+	if charset != "" && !info.charsetAvailable {
+		return false, fmt.Errorf("%s server %s does not support character set %s (server default: %s); enable it on the server or set Config.Charset",
+			server, info.version, charset, info.defaultCharset)
+	}
+	return mariadb, nil
+}
+
+// serverVersionPattern matches the leading numeric part of VERSION().
+var serverVersionPattern = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)`)
+
+// parseServerVersion parses a VERSION() string such as "8.0.33" or
+// "10.6.12-MariaDB-1:10.6.12+maria~ubu2004" and reports whether it is MariaDB.
+func parseServerVersion(s string) (serverVersion, bool, error) {
+	mariadb := strings.Contains(strings.ToLower(s), "mariadb")
+	// Replication-era MariaDB servers prefix the version with "5.5.5-"
+	if mariadb {
+		s = strings.TrimPrefix(s, "5.5.5-")
+	}
+	m := serverVersionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return serverVersion{}, false, fmt.Errorf("failed to parse server version %q", s)
+	}
+	var v serverVersion
+	for i := range v {
+		v[i], _ = strconv.Atoi(m[i+1])
+	}
+	return v, mariadb, nil
+}
+
+// Server versions that introduced the optional features in storage.Capabilities.
+// InnoDB full-text indexes predate the minimum supported versions.
+var (
+	cteMySQLVersion          = serverVersion{8, 0, 1}
+	cteMariaDBVersion        = serverVersion{10, 2, 2}
+	windowMySQLVersion       = serverVersion{8, 0, 2}
+	windowMariaDBVersion     = serverVersion{10, 2, 0}
+	skipLockedMySQLVersion   = serverVersion{8, 0, 1}
+	skipLockedMariaDBVersion = serverVersion{10, 6, 0}
+)
+
+// Capabilities returns the optional server features detected by New.
+// ClaimNextReadyIssue uses SkipLocked to pass over issues other workers are
+// claiming instead of racing for them.
+func (s *MariaDBStore) Capabilities() storage.Capabilities {
+	return s.caps
+}
+
+// capabilitiesForVersion returns the capabilities of a server with the given
+// VERSION() string.
+func capabilitiesForVersion(version string) (storage.Capabilities, error) {
+	v, mariadb, err := parseServerVersion(version)
+	if err != nil {
+		return storage.Capabilities{}, err
+	}
+	atLeast := func(mysqlVersion, mariadbVersion serverVersion) bool {
+		if mariadb {
+			return !v.less(mariadbVersion)
+		}
+		return !v.less(mysqlVersion)
+	}
+	return storage.Capabilities{
+		FullTextSearch:  true,
+		RecursiveCTE:    atLeast(cteMySQLVersion, cteMariaDBVersion),
+		WindowFunctions: atLeast(windowMySQLVersion, windowMariaDBVersion),
+		SkipLocked:      atLeast(skipLockedMySQLVersion, skipLockedMariaDBVersion),
+	}, nil
+}
+
+// probeCapabilities detects the capabilities of the server behind db. A
+// failed probe disables the optional features rather than failing New,
+// since each has a fallback.
+func (s *MariaDBStore) probeCapabilities(ctx context.Context) {
+	var version string
+	err := s.primaryDB().QueryRowContext(ctx, "SELECT VERSION()").Scan(&version)
+	if err == nil {
+		s.caps, err = capabilitiesForVersion(version)
+	}
+	if err != nil {
+		s.log().Warn("failed to probe MariaDB server capabilities; optional features disabled", "database", s.dbName, "error", err)
+		return
+	}
+	s.log().Debug("MariaDB server capabilities", "database", s.dbName, "version", version,
+		"fulltext", s.caps.FullTextSearch, "cte", s.caps.RecursiveCTE, "window_functions", s.caps.WindowFunctions, "skip_locked", s.caps.SkipLocked)
+}
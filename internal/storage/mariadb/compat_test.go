@@ -0,0 +1,166 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+)
+
+// stubServerInfo makes queryServerInfo report info for the rest of the test.
+func stubServerInfo(t *testing.T, info serverInfo) {
+	t.Helper()
+	original := queryServerInfo
+	t.Cleanup(func() { queryServerInfo = original })
+	queryServerInfo = func(ctx context.Context, db *sql.DB, charset string) (serverInfo, error) {
+		return info, nil
+	}
+}
+
+func TestParseServerVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    serverVersion
+		mariadb bool
+	}{
+		{"8.0.33", serverVersion{8, 0, 33}, false},
+		{"5.7.44-log", serverVersion{5, 7, 44}, false},
+		{"10.6.12-MariaDB-1:10.6.12+maria~ubu2004", serverVersion{10, 6, 12}, true},
+		{"5.5.5-10.11.6-MariaDB", serverVersion{10, 11, 6}, true},
+	}
+	for _, tt := range tests {
+		got, mariadb, err := parseServerVersion(tt.in)
+		if err != nil {
+			t.Errorf("parseServerVersion(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want || mariadb != tt.mariadb {
+			t.Errorf("parseServerVersion(%q) = %v, %v; want %v, %v", tt.in, got, mariadb, tt.want, tt.mariadb)
+		}
+	}
+
+	if _, _, err := parseServerVersion("unknown"); err == nil {
+		t.Error("expected error for unparseable version")
+	}
+}
+
+func TestCheckServerCompatibility(t *testing.T) {
+	tests := []struct {
+		name    string
+		info    serverInfo
+		wantErr []string
+	}{
+		{"mysql 8", serverInfo{version: "8.0.33", defaultCharset: "utf8mb4", charsetAvailable: true}, nil},
+		{"mariadb 10.6", serverInfo{version: "10.6.12-MariaDB", defaultCharset: "utf8mb4", charsetAvailable: true}, nil},
+		{"mysql 5.5", serverInfo{version: "5.5.62-log", defaultCharset: "latin1", charsetAvailable: true}, []string{"5.5.62-log", "MySQL " + minMySQLVersion.String()}},
+		{"mariadb 10.1", serverInfo{version: "10.1.48-MariaDB", defaultCharset: "utf8mb4", charsetAvailable: true}, []string{"10.1.48-MariaDB", "MariaDB " + minMariaDBVersion.String()}},
+		{"mariadb 10.2.1", serverInfo{version: "10.2.1-MariaDB", defaultCharset: "utf8mb4", charsetAvailable: true}, []string{"10.2.1-MariaDB", "MariaDB 10.2.2"}},
+		{"charset missing", serverInfo{version: "8.0.33", defaultCharset: "latin1"}, []string{"MySQL server", "utf8mb4", "latin1"}},
+		{"mariadb charset missing", serverInfo{version: "10.6.12-MariaDB", defaultCharset: "latin1"}, []string{"MariaDB server", "utf8mb4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubServerInfo(t, tt.info)
+			mariadb, err := checkServerCompatibility(context.Background(), nil, DefaultCharset)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("checkServerCompatibility failed: %v", err)
+				}
+				if want := strings.Contains(tt.info.version, "MariaDB"); mariadb != want {
+					t.Errorf("checkServerCompatibility reported MariaDB = %v, want %v", mariadb, want)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q should mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRejectsOldServer(t *testing.T) {
+	// Only runs when a server is available
+	existing, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	stubServerInfo(t, serverInfo{version: "5.5.62", defaultCharset: "latin1", charsetAvailable: true})
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	dbName := testDatabaseName(t)
+	store, err := New(ctx, &Config{Database: dbName})
+	if err == nil {
+		_ = store.Close()
+		t.Fatal("expected New to reject a MySQL 5.5 server")
+	}
+	if !strings.Contains(err.Error(), "unsupported MySQL server version 5.5.62") {
+		t.Errorf("New error = %v, want the detected version", err)
+	}
+
+	// The check runs before the database is created
+	var count int
+	if err := existing.UnderlyingDB().QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ?", dbName).Scan(&count); err != nil {
+		t.Fatalf("failed to query schemata: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("database %s was created despite the failed check", dbName)
+	}
+}
+
+func TestNewChecksCompatibilityWithoutCreateDatabase(t *testing.T) {
+	existing, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	stubServerInfo(t, serverInfo{version: "10.1.48-MariaDB", defaultCharset: "utf8mb4", charsetAvailable: true})
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	createDatabase := false
+	store, err := New(ctx, &Config{Database: existing.Path(), CreateDatabase: &createDatabase})
+	if err == nil {
+		_ = store.Close()
+		t.Fatal("expected New to reject a MariaDB 10.1 server")
+	}
+	if !strings.Contains(err.Error(), "MariaDB "+minMariaDBVersion.String()) {
+		t.Errorf("New error = %v, want the minimum version", err)
+	}
+}
+
+func TestCapabilitiesForVersion(t *testing.T) {
+	all := storage.Capabilities{FullTextSearch: true, RecursiveCTE: true, WindowFunctions: true, SkipLocked: true}
+	tests := []struct {
+		version string
+		want    storage.Capabilities
+	}{
+		{"8.0.33", all},
+		{"8.0.1", storage.Capabilities{FullTextSearch: true, RecursiveCTE: true, SkipLocked: true}},
+		{"5.7.44-log", storage.Capabilities{FullTextSearch: true}},
+		{"10.6.12-MariaDB-1:10.6.12+maria~ubu2004", all},
+		{"5.5.5-10.11.6-MariaDB", all},
+		{"10.5.23-MariaDB", storage.Capabilities{FullTextSearch: true, RecursiveCTE: true, WindowFunctions: true}},
+		{"10.2.1-MariaDB", storage.Capabilities{FullTextSearch: true, WindowFunctions: true}},
+	}
+	for _, tt := range tests {
+		got, err := capabilitiesForVersion(tt.version)
+		if err != nil {
+			t.Errorf("capabilitiesForVersion(%q) failed: %v", tt.version, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("capabilitiesForVersion(%q) = %+v, want %+v", tt.version, got, tt.want)
+		}
+	}
+
+	if _, err := capabilitiesForVersion("unknown"); err == nil {
+		t.Error("expected error for unparseable version")
+	}
+}
@@ -4,17 +4,25 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/steveyegge/beads/internal/config"
 )
 
 // SetConfig sets a configuration value
 func (s *MariaDBStore) SetConfig(ctx context.Context, key, value string) error {
-	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO config (`+"`key`"+`, value) VALUES (?, ?)
-		ON DUPLICATE KEY UPDATE value = VALUES(value)
-	`, key, value)
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	err := s.withReplayableRetry(ctx, func() error {
+		_, err := s.dbOrTx().ExecContext(ctx, `
+			INSERT INTO config (`+"`key`"+`, value) VALUES (?, ?)
+			ON DUPLICATE KEY UPDATE value = VALUES(value)
+		`, key, value)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to set config %s: %w", key, err)
 	}
@@ -23,15 +31,12 @@ func (s *MariaDBStore) SetConfig(ctx context.Context, key, value string) error {
 
 // GetConfig retrieves a configuration value
 func (s *MariaDBStore) GetConfig(ctx context.Context, key string) (string, error) {
+	if s.IsClosed() {
+		return "", ErrStoreClosed
+	}
 	var value string
-	var scanErr error
-
-	err := s.withRetry(ctx, func() error {
-		scanErr = s.db.QueryRowContext(ctx, "SELECT value FROM config WHERE `key` = ?", key).Scan(&value)
-		return scanErr
-	})
-
-	if err == sql.ErrNoRows || scanErr == sql.ErrNoRows {
+	err := s.queryRowContext(ctx, "SELECT value FROM config WHERE `key` = ?", key).Scan(&value)
+	if err == sql.ErrNoRows {
 		return "", nil
 	}
 	if err != nil {
@@ -40,9 +45,98 @@ func (s *MariaDBStore) GetConfig(ctx context.Context, key string) (string, error
 	return value, nil
 }
 
+// GetConfigInt returns the configuration value of key as an integer, or def
+// if the key is not set or empty. A value that is not an integer is an error.
+func (s *MariaDBStore) GetConfigInt(ctx context.Context, key string, def int) (int, error) {
+	value, err := s.GetConfig(ctx, key)
+	if err != nil || value == "" {
+		return def, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return def, fmt.Errorf("config %s is not an integer: %q", key, value)
+	}
+	return n, nil
+}
+
+// GetConfigBool returns the configuration value of key as a boolean, or def
+// if the key is not set or empty. It accepts the values strconv.ParseBool
+// does (true/false, 1/0, t/f); anything else is an error.
+func (s *MariaDBStore) GetConfigBool(ctx context.Context, key string, def bool) (bool, error) {
+	value, err := s.GetConfig(ctx, key)
+	if err != nil || value == "" {
+		return def, err
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return def, fmt.Errorf("config %s is not a boolean: %q", key, value)
+	}
+	return b, nil
+}
+
+// checkpointKeyPrefix prefixes the config keys SetCheckpoint stores
+// checkpoints under.
+const checkpointKeyPrefix = "checkpoint."
+
+// maxConfigKeyLength is the length of the config table's key column.
+const maxConfigKeyLength = 255
+
+// checkpointKey returns the config key of the checkpoint name.
+func checkpointKey(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("checkpoint name must not be empty")
+	}
+	key := checkpointKeyPrefix + name
+	if len(key) > maxConfigKeyLength {
+		return "", fmt.Errorf("checkpoint name %q is longer than %d bytes", name, maxConfigKeyLength-len(checkpointKeyPrefix))
+	}
+	return key, nil
+}
+
+// GetCheckpoint returns the time last stored for the checkpoint name by
+// SetCheckpoint, or the zero time if it was never set.
+func (s *MariaDBStore) GetCheckpoint(ctx context.Context, name string) (time.Time, error) {
+	if s.IsClosed() {
+		return time.Time{}, ErrStoreClosed
+	}
+	key, err := checkpointKey(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	value, err := s.GetConfig(ctx, key)
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("checkpoint %s is not a timestamp: %q", name, value)
+	}
+	return t, nil
+}
+
+// SetCheckpoint stores t, in UTC with nanosecond precision, as the
+// checkpoint name: a durable high-water mark, such as the updated_at up to
+// which an exporter has synced. Each integration can keep its own under a
+// different name. Checkpoints live in the config table under
+// "checkpoint.<name>", and the write is a single upsert, so a concurrent
+// reader sees either the old time or the new one.
+func (s *MariaDBStore) SetCheckpoint(ctx context.Context, name string, t time.Time) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	key, err := checkpointKey(name)
+	if err != nil {
+		return err
+	}
+	return s.SetConfig(ctx, key, t.UTC().Format(time.RFC3339Nano))
+}
+
 // GetAllConfig retrieves all configuration values
 func (s *MariaDBStore) GetAllConfig(ctx context.Context) (map[string]string, error) {
-	rows, err := s.db.QueryContext(ctx, "SELECT `key`, value FROM config")
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, "SELECT `key`, value FROM config")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all config: %w", err)
 	}
@@ -61,7 +155,13 @@ func (s *MariaDBStore) GetAllConfig(ctx context.Context) (map[string]string, err
 
 // DeleteConfig removes a configuration value
 func (s *MariaDBStore) DeleteConfig(ctx context.Context, key string) error {
-	_, err := s.db.ExecContext(ctx, "DELETE FROM config WHERE `key` = ?", key)
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	err := s.withReplayableRetry(ctx, func() error {
+		_, err := s.dbOrTx().ExecContext(ctx, "DELETE FROM config WHERE `key` = ?", key)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to delete config %s: %w", key, err)
 	}
@@ -70,10 +170,16 @@ func (s *MariaDBStore) DeleteConfig(ctx context.Context, key string) error {
 
 // SetMetadata sets a metadata value
 func (s *MariaDBStore) SetMetadata(ctx context.Context, key, value string) error {
-	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO metadata (`+"`key`"+`, value) VALUES (?, ?)
-		ON DUPLICATE KEY UPDATE value = VALUES(value)
-	`, key, value)
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	err := s.withReplayableRetry(ctx, func() error {
+		_, err := s.dbOrTx().ExecContext(ctx, `
+			INSERT INTO metadata (`+"`key`"+`, value) VALUES (?, ?)
+			ON DUPLICATE KEY UPDATE value = VALUES(value)
+		`, key, value)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to set metadata %s: %w", key, err)
 	}
@@ -82,8 +188,11 @@ func (s *MariaDBStore) SetMetadata(ctx context.Context, key, value string) error
 
 // GetMetadata retrieves a metadata value
 func (s *MariaDBStore) GetMetadata(ctx context.Context, key string) (string, error) {
+	if s.IsClosed() {
+		return "", ErrStoreClosed
+	}
 	var value string
-	err := s.db.QueryRowContext(ctx, "SELECT value FROM metadata WHERE `key` = ?", key).Scan(&value)
+	err := s.queryRowContext(ctx, "SELECT value FROM metadata WHERE `key` = ?", key).Scan(&value)
 	if err == sql.ErrNoRows {
 		return "", nil
 	}
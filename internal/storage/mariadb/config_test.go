@@ -0,0 +1,123 @@
+package mariadb
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if value, err := store.GetConfig(ctx, "test.missing"); err != nil || value != "" {
+		t.Errorf("GetConfig(missing) = %q, %v; want empty", value, err)
+	}
+	if err := store.SetConfig(ctx, "test.key", "one"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	// A second write replaces the value
+	if err := store.SetConfig(ctx, "test.key", "two"); err != nil {
+		t.Fatalf("SetConfig (upsert) failed: %v", err)
+	}
+	if value, err := store.GetConfig(ctx, "test.key"); err != nil || value != "two" {
+		t.Errorf("GetConfig = %q, %v; want two", value, err)
+	}
+}
+
+func TestGetConfigInt(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if n, err := store.GetConfigInt(ctx, "test.missing", 2); err != nil || n != 2 {
+		t.Errorf("GetConfigInt(missing) = %d, %v; want default 2", n, err)
+	}
+	if err := store.SetConfig(ctx, "test.priority", "3"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	if n, err := store.GetConfigInt(ctx, "test.priority", 2); err != nil || n != 3 {
+		t.Errorf("GetConfigInt = %d, %v; want 3", n, err)
+	}
+	if err := store.SetConfig(ctx, "test.priority", "high"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	if n, err := store.GetConfigInt(ctx, "test.priority", 2); err == nil || n != 2 {
+		t.Errorf("GetConfigInt(\"high\") = %d, %v; want default 2 and a parse error", n, err)
+	}
+}
+
+func TestGetConfigBool(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if b, err := store.GetConfigBool(ctx, "test.missing", true); err != nil || !b {
+		t.Errorf("GetConfigBool(missing) = %v, %v; want default true", b, err)
+	}
+	// Seeded by the schema as 'false'
+	if b, err := store.GetConfigBool(ctx, "auto_compact_enabled", true); err != nil || b {
+		t.Errorf("GetConfigBool(auto_compact_enabled) = %v, %v; want false", b, err)
+	}
+	if err := store.SetConfig(ctx, "test.flag", "maybe"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	if _, err := store.GetConfigBool(ctx, "test.flag", false); err == nil {
+		t.Error("GetConfigBool(\"maybe\") should fail")
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if got, err := store.GetCheckpoint(ctx, "export.jira"); err != nil || !got.IsZero() {
+		t.Errorf("GetCheckpoint(missing) = %v, %v; want the zero time", got, err)
+	}
+
+	// Checkpoints come back in UTC, to the nanosecond, and independently
+	jira := time.Date(2026, 3, 1, 12, 30, 45, 123456789, time.FixedZone("CET", 3600))
+	github := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	if err := store.SetCheckpoint(ctx, "export.jira", jira); err != nil {
+		t.Fatalf("SetCheckpoint(jira) failed: %v", err)
+	}
+	if err := store.SetCheckpoint(ctx, "export.github", github); err != nil {
+		t.Fatalf("SetCheckpoint(github) failed: %v", err)
+	}
+	if got, err := store.GetCheckpoint(ctx, "export.jira"); err != nil || !got.Equal(jira) || got.Location() != time.UTC {
+		t.Errorf("GetCheckpoint(jira) = %v, %v; want %v in UTC", got, err, jira.UTC())
+	}
+	if got, err := store.GetCheckpoint(ctx, "export.github"); err != nil || !got.Equal(github) {
+		t.Errorf("GetCheckpoint(github) = %v, %v; want %v", got, err, github)
+	}
+
+	// Setting again moves the checkpoint, back as well as forward
+	if err := store.SetCheckpoint(ctx, "export.jira", github); err != nil {
+		t.Fatalf("SetCheckpoint(jira) again failed: %v", err)
+	}
+	if got, err := store.GetCheckpoint(ctx, "export.jira"); err != nil || !got.Equal(github) {
+		t.Errorf("GetCheckpoint(jira) after update = %v, %v; want %v", got, err, github)
+	}
+
+	if err := store.SetConfig(ctx, "checkpoint.broken", "yesterday"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	if _, err := store.GetCheckpoint(ctx, "broken"); err == nil {
+		t.Error("GetCheckpoint(broken) succeeded, want an error for a value that is not a timestamp")
+	}
+	for _, name := range []string{"", strings.Repeat("x", 250)} {
+		if err := store.SetCheckpoint(ctx, name, github); err == nil {
+			t.Errorf("SetCheckpoint(%.10q...) succeeded, want an error", name)
+		}
+	}
+}
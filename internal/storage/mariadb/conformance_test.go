@@ -0,0 +1,23 @@
+package mariadb
+
+import (
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/storagetest"
+)
+
+// newConformanceStore returns an empty store for the storagetest suites.
+func newConformanceStore(t *testing.T) storage.Storage {
+	store, cleanup := setupTestStore(t)
+	t.Cleanup(cleanup)
+	return store
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.RunConformance(t, newConformanceStore)
+}
+
+func TestDependencyValidation(t *testing.T) {
+	storagetest.RunDependencyValidation(t, newConformanceStore)
+}
@@ -0,0 +1,60 @@
+package mariadb
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"runtime/debug"
+	"time"
+)
+
+// DefaultConnLeakThreshold is the default for Config.ConnLeakThreshold
+const DefaultConnLeakThreshold = time.Minute
+
+// connTracker warns about connections handed out by UnderlyingConn that are
+// not returned to the pool within threshold (Config.TrackConns).
+type connTracker struct {
+	threshold time.Duration
+	logger    *slog.Logger
+	database  string
+}
+
+// newConnTracker returns the tracker for cfg, or nil if TrackConns is off.
+func newConnTracker(cfg *Config) *connTracker {
+	if !cfg.TrackConns {
+		return nil
+	}
+	threshold := cfg.ConnLeakThreshold
+	if threshold <= 0 {
+		threshold = DefaultConnLeakThreshold
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = discardLogger
+	}
+	return &connTracker{threshold: threshold, logger: logger, database: cfg.Database}
+}
+
+// track records the caller's stack and, if conn is still open after the
+// threshold, logs a warning with that stack. Each connection is reported
+// at most once.
+func (t *connTracker) track(conn *sql.Conn) {
+	stack := debug.Stack()
+	acquired := time.Now()
+	time.AfterFunc(t.threshold, func() {
+		if connReturned(conn) {
+			return
+		}
+		t.logger.Warn("MariaDB connection from UnderlyingConn held past leak threshold; is Close missing?",
+			"database", t.database,
+			"held", time.Since(acquired).Round(time.Millisecond),
+			"threshold", t.threshold,
+			"stack", string(stack))
+	})
+}
+
+// connReturned reports whether conn has been closed, returning it to the pool.
+func connReturned(conn *sql.Conn) bool {
+	err := conn.Raw(func(interface{}) error { return nil })
+	return errors.Is(err, sql.ErrConnDone)
+}
@@ -0,0 +1,85 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubConnector hands out connections that support nothing but Close, so
+// UnderlyingConn can be exercised without a server.
+type stubConnector struct{}
+
+func (stubConnector) Connect(context.Context) (driver.Conn, error) { return stubConn{}, nil }
+func (stubConnector) Driver() driver.Driver                        { return nil }
+
+type stubConn struct{}
+
+func (stubConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (stubConn) Close() error                        { return nil }
+func (stubConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func TestTrackConnsWarnsAboutHeldConnection(t *testing.T) {
+	h := &captureHandler{}
+	db := sql.OpenDB(stubConnector{})
+	defer db.Close()
+	store := &MariaDBStore{
+		storeState: newStoreState(db, ""),
+		conns: newConnTracker(&Config{
+			Database: "beads", TrackConns: true, ConnLeakThreshold: 20 * time.Millisecond, Logger: slog.New(h),
+		}),
+	}
+
+	warnings := func() []slog.Record {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		return append([]slog.Record(nil), h.records...)
+	}
+
+	// A connection closed in time is not reported
+	returned, err := store.UnderlyingConn(context.Background())
+	if err != nil {
+		t.Fatalf("UnderlyingConn failed: %v", err)
+	}
+	_ = returned.Close()
+
+	leaked, err := store.UnderlyingConn(context.Background())
+	if err != nil {
+		t.Fatalf("UnderlyingConn failed: %v", err)
+	}
+	defer leaked.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(warnings()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	records := warnings()
+	if len(records) != 1 {
+		t.Fatalf("got %d warnings, want 1 for the held connection", len(records))
+	}
+	r := records[0]
+	if r.Level != slog.LevelWarn || !strings.Contains(r.Message, "leak threshold") {
+		t.Errorf("warning = %s %q, want a WARN about the leak threshold", r.Level, r.Message)
+	}
+	a := attrs(r)
+	if stack := a["stack"].String(); !strings.Contains(stack, "TestTrackConnsWarnsAboutHeldConnection") {
+		t.Errorf("stack does not name the caller:\n%s", stack)
+	}
+	if a["database"].String() != "beads" {
+		t.Errorf("database = %v, want beads", a["database"])
+	}
+}
+
+func TestNewConnTracker(t *testing.T) {
+	if tr := newConnTracker(&Config{}); tr != nil {
+		t.Errorf("tracker without TrackConns = %+v, want nil", tr)
+	}
+	if tr := newConnTracker(&Config{TrackConns: true}); tr == nil || tr.threshold != DefaultConnLeakThreshold {
+		t.Errorf("tracker = %+v, want default threshold", tr)
+	}
+}
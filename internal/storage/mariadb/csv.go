@@ -0,0 +1,406 @@
+package mariadb
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// DefaultCSVColumns are the columns ExportCSV writes when none are given.
+var DefaultCSVColumns = []string{"id", "title", "description", "status", "priority", "issue_type", "assignee", "labels"}
+
+// csvImportActor is recorded as the actor of issues created and updated by ImportCSV.
+const csvImportActor = "csv-import"
+
+// csvColumn maps a CSV column to an issue field.
+type csvColumn struct {
+	get    func(issue *types.Issue) string
+	set    func(issue *types.Issue, value string) error // nil for export-only columns
+	update func(issue *types.Issue) interface{}         // UpdateIssue value; nil if not updatable
+}
+
+// csvColumns are the columns ExportCSV and ImportCSV support. Labels are
+// comma-separated within their cell; times are RFC 3339 in UTC. Text fields
+// are imported verbatim, other fields are trimmed.
+var csvColumns = map[string]csvColumn{
+	"id": {
+		get: func(i *types.Issue) string { return i.ID },
+		set: func(i *types.Issue, v string) error { i.ID = strings.TrimSpace(v); return nil },
+	},
+	"title":               csvStringColumn(func(i *types.Issue) *string { return &i.Title }),
+	"description":         csvStringColumn(func(i *types.Issue) *string { return &i.Description }),
+	"design":              csvStringColumn(func(i *types.Issue) *string { return &i.Design }),
+	"acceptance_criteria": csvStringColumn(func(i *types.Issue) *string { return &i.AcceptanceCriteria }),
+	"notes":               csvStringColumn(func(i *types.Issue) *string { return &i.Notes }),
+	"assignee":            csvStringColumn(func(i *types.Issue) *string { return &i.Assignee }),
+	"status": {
+		get:    func(i *types.Issue) string { return string(i.Status) },
+		set:    func(i *types.Issue, v string) error { i.Status = types.Status(strings.TrimSpace(v)); return nil },
+		update: func(i *types.Issue) interface{} { return string(i.Status) },
+	},
+	"issue_type": {
+		get:    func(i *types.Issue) string { return string(i.IssueType) },
+		set:    func(i *types.Issue, v string) error { i.IssueType = types.IssueType(strings.TrimSpace(v)); return nil },
+		update: func(i *types.Issue) interface{} { return string(i.IssueType) },
+	},
+	"priority": {
+		get: func(i *types.Issue) string { return strconv.Itoa(i.Priority) },
+		set: func(i *types.Issue, v string) error {
+			// Accept the "P2" form used in the CLI as well as "2"
+			p, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(v)), "P"))
+			if err != nil || p < 0 || p > 4 {
+				return fmt.Errorf("invalid priority %q (want 0-4)", v)
+			}
+			i.Priority = p
+			return nil
+		},
+		update: func(i *types.Issue) interface{} { return i.Priority },
+	},
+	"labels": {
+		get: func(i *types.Issue) string { return strings.Join(i.Labels, ", ") },
+		set: func(i *types.Issue, v string) error {
+			i.Labels = nil
+			for _, label := range strings.Split(v, ",") {
+				if label = strings.TrimSpace(label); label != "" {
+					i.Labels = append(i.Labels, label)
+				}
+			}
+			return nil
+		},
+	},
+	"external_ref": {
+		get: func(i *types.Issue) string {
+			if i.ExternalRef == nil {
+				return ""
+			}
+			return *i.ExternalRef
+		},
+		set: func(i *types.Issue, v string) error {
+			i.ExternalRef = nil
+			if v = strings.TrimSpace(v); v != "" {
+				i.ExternalRef = &v
+			}
+			return nil
+		},
+		update: func(i *types.Issue) interface{} { return nullStringPtr(i.ExternalRef) },
+	},
+	"due_at": {
+		get: func(i *types.Issue) string { return csvTime(i.DueAt) },
+		set: func(i *types.Issue, v string) error {
+			t, err := parseCSVTime(strings.TrimSpace(v))
+			if err != nil {
+				return err
+			}
+			i.DueAt = t
+			return nil
+		},
+		update: func(i *types.Issue) interface{} {
+			if i.DueAt == nil {
+				return nil
+			}
+			return *i.DueAt
+		},
+	},
+	"created_at": {get: func(i *types.Issue) string { return csvTime(&i.CreatedAt) }},
+	"updated_at": {get: func(i *types.Issue) string { return csvTime(&i.UpdatedAt) }},
+	"closed_at":  {get: func(i *types.Issue) string { return csvTime(i.ClosedAt) }},
+}
+
+// csvStringColumn returns a column for an updatable string field.
+func csvStringColumn(field func(*types.Issue) *string) csvColumn {
+	return csvColumn{
+		get:    func(i *types.Issue) string { return *field(i) },
+		set:    func(i *types.Issue, v string) error { *field(i) = v; return nil },
+		update: func(i *types.Issue) interface{} { return *field(i) },
+	}
+}
+
+// csvTime formats an optional time for a CSV cell.
+func csvTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// parseCSVTime parses an RFC 3339 time or a YYYY-MM-DD date (midnight UTC).
+// An empty cell is nil.
+func parseCSVTime(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			t = t.UTC()
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid time %q (want RFC 3339 or YYYY-MM-DD)", v)
+}
+
+// CSVRowError is an error in one row of a CSV import.
+type CSVRowError struct {
+	Line int // Line of the file the row starts on (1 is the header)
+	Err  error
+}
+
+func (e *CSVRowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *CSVRowError) Unwrap() error {
+	return e.Err
+}
+
+// CSVImportError is returned by ImportCSV when some rows could not be
+// imported. The other rows were imported.
+type CSVImportError struct {
+	Rows []*CSVRowError
+}
+
+func (e *CSVImportError) Error() string {
+	msgs := make([]string, len(e.Rows))
+	for i, row := range e.Rows {
+		msgs[i] = row.Error()
+	}
+	return fmt.Sprintf("failed to import %d CSV rows: %s", len(e.Rows), strings.Join(msgs, "; "))
+}
+
+// ExportCSV writes the open and closed issues to w as CSV, with a header row
+// naming the columns and one row per issue, sorted by ID. columns selects and
+// orders the columns (default: DefaultCSVColumns); an unknown column is an
+// error. Fields containing commas, quotes, or newlines are quoted.
+func (s *MariaDBStore) ExportCSV(ctx context.Context, w io.Writer, columns []string) error {
+	if s.IsClosed() {
+		return ErrStoreClosed
+	}
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+	for _, name := range columns {
+		if _, ok := csvColumns[name]; !ok {
+			return fmt.Errorf("unknown CSV column %q", name)
+		}
+	}
+
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return fmt.Errorf("failed to export issues: %w", err)
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].ID < issues[j].ID })
+	if slices.Contains(columns, "labels") {
+		ids := make([]string, len(issues))
+		for i, issue := range issues {
+			ids[i] = issue.ID
+		}
+		labels, err := s.GetLabelsForIssues(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to export labels: %w", err)
+		}
+		for _, issue := range issues {
+			issue.Labels = labels[issue.ID]
+			sort.Strings(issue.Labels)
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	record := make([]string, len(columns))
+	for _, issue := range issues {
+		for i, name := range columns {
+			record[i] = csvColumns[name].get(issue)
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write issue %s: %w", issue.ID, err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// ImportCSV reads issues from CSV with a header row naming the columns, as
+// written by ExportCSV. A row whose id names an existing issue updates that
+// issue's fields in the file's columns (labels are replaced); any other row
+// creates an issue, with a generated ID when id is empty or absent, and
+// requires a title. Export-only columns (created_at, updated_at, closed_at)
+// are ignored.
+//
+// Each row is imported on its own: a row that fails validation or cannot be
+// written does not stop the others, and the failed rows are returned, with
+// their line numbers, in a *CSVImportError. A malformed header or broken
+// quoting aborts the import.
+func (s *MariaDBStore) ImportCSV(ctx context.Context, r io.Reader) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return errors.New("CSV has no header row")
+	} else if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := csvColumns[name]; !ok {
+			return fmt.Errorf("unknown CSV column %q", header[i])
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate CSV column %q", header[i])
+		}
+		seen[name] = true
+		header[i] = name
+	}
+	if !seen["id"] && !seen["title"] {
+		return errors.New("CSV must have an id or title column")
+	}
+
+	customStatuses, err := s.GetCustomStatuses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get custom statuses: %w", err)
+	}
+	customTypes, err := s.GetCustomTypes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get custom types: %w", err)
+	}
+
+	var rowErrs []*CSVRowError
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := cr.FieldPos(0)
+		if errors.Is(err, csv.ErrFieldCount) {
+			rowErrs = append(rowErrs, &CSVRowError{Line: line, Err: fmt.Errorf("has %d fields, want %d", len(record), len(header))})
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		if err := s.importCSVRow(ctx, header, record, customStatuses, customTypes); err != nil {
+			if errors.Is(err, ErrStoreClosed) || errors.Is(err, ErrReadOnly) || ctx.Err() != nil {
+				return err
+			}
+			rowErrs = append(rowErrs, &CSVRowError{Line: line, Err: err})
+		}
+	}
+	if len(rowErrs) > 0 {
+		return &CSVImportError{Rows: rowErrs}
+	}
+	return nil
+}
+
+// importCSVRow creates or updates the issue described by one CSV record.
+func (s *MariaDBStore) importCSVRow(ctx context.Context, header, record []string, customStatuses, customTypes []string) error {
+	row := &types.Issue{}
+	for i, name := range header {
+		col := csvColumns[name]
+		if col.set == nil {
+			continue
+		}
+		if err := col.set(row, record[i]); err != nil {
+			return fmt.Errorf("column %s: %w", name, err)
+		}
+	}
+
+	var existing *types.Issue
+	if row.ID != "" {
+		var err error
+		if existing, err = s.GetIssue(ctx, row.ID); err != nil {
+			return err
+		}
+	}
+	if existing == nil {
+		return s.createCSVIssue(ctx, header, row)
+	}
+
+	updates := make(map[string]interface{})
+	for _, name := range header {
+		if update := csvColumns[name].update; update != nil {
+			updates[name] = update(row)
+		}
+	}
+	if title, ok := updates["title"]; ok && title == "" {
+		return errors.New("title is required")
+	}
+	if status, ok := updates["status"]; ok && !types.Status(status.(string)).IsValidWithCustom(customStatuses) {
+		return fmt.Errorf("invalid status: %s", status)
+	}
+	if issueType, ok := updates["issue_type"]; ok && !types.IssueType(issueType.(string)).IsValidWithCustom(customTypes) {
+		return fmt.Errorf("invalid issue type: %s", issueType)
+	}
+	if len(updates) > 0 {
+		if err := s.UpdateIssue(ctx, row.ID, updates, csvImportActor); err != nil {
+			return err
+		}
+	}
+	if slices.Contains(header, "labels") {
+		return s.replaceLabels(ctx, row.ID, existing.Labels, row.Labels)
+	}
+	return nil
+}
+
+// createCSVIssue creates the issue for a CSV row, filling in the defaults of
+// columns the file does not have.
+func (s *MariaDBStore) createCSVIssue(ctx context.Context, header []string, issue *types.Issue) error {
+	if issue.Title == "" {
+		return errors.New("title is required")
+	}
+	if !slices.Contains(header, "priority") {
+		issue.Priority = 2
+	}
+	if issue.Status == "" {
+		issue.Status = types.StatusOpen
+	}
+	if issue.IssueType == "" {
+		issue.IssueType = types.TypeTask
+	}
+	issue.CreatedBy = csvImportActor
+	if err := s.CreateIssue(ctx, issue, csvImportActor); err != nil {
+		return err
+	}
+	return s.replaceLabels(ctx, issue.ID, nil, issue.Labels)
+}
+
+// replaceLabels changes an issue's labels from old to labels.
+func (s *MariaDBStore) replaceLabels(ctx context.Context, id string, old, labels []string) error {
+	want := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		want[label] = true
+	}
+	have := make(map[string]bool, len(old))
+	for _, label := range old {
+		have[label] = true
+		if !want[label] {
+			if err := s.RemoveLabel(ctx, id, label, csvImportActor); err != nil {
+				return err
+			}
+		}
+	}
+	for _, label := range labels {
+		if !have[label] {
+			if err := s.AddLabel(ctx, id, label, csvImportActor); err != nil {
+				return err
+			}
+			have[label] = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package mariadb
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestExportImportCSV(t *testing.T) {
+	source, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	description := "Steps:\n1. Open settings, then \"Profile\"\n2. Save\n\nExpected: no crash, obviously"
+	for _, issue := range []*types.Issue{
+		{ID: "test-a", Title: "Crash, on save", Description: description, Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug, Assignee: "alice"},
+		{ID: "test-b", Title: "Plain", Status: types.StatusInProgress, Priority: 3, IssueType: types.TypeTask},
+	} {
+		if err := source.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", issue.ID, err)
+		}
+	}
+	for _, label := range []string{"ui", "p1 triage"} {
+		if err := source.AddLabel(ctx, "test-a", label, "tester"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+
+	var exported bytes.Buffer
+	if err := source.ExportCSV(ctx, &exported, nil); err != nil {
+		t.Fatalf("ExportCSV failed: %v", err)
+	}
+	if !strings.HasPrefix(exported.String(), "id,title,description,status,priority,issue_type,assignee,labels\n") {
+		t.Errorf("export does not start with the default header:\n%s", exported.String())
+	}
+	if !strings.Contains(exported.String(), `"Crash, on save"`) {
+		t.Errorf("title with a comma is not quoted:\n%s", exported.String())
+	}
+
+	target, cleanupTarget := setupTestStore(t)
+	defer cleanupTarget()
+	if err := target.ImportCSV(ctx, bytes.NewReader(exported.Bytes())); err != nil {
+		t.Fatalf("ImportCSV failed: %v", err)
+	}
+	got, err := target.GetIssue(ctx, "test-a")
+	if err != nil || got == nil {
+		t.Fatalf("GetIssue(test-a) = %v, %v", got, err)
+	}
+	if got.Title != "Crash, on save" || got.Description != description {
+		t.Errorf("round-tripped title, description = %q, %q", got.Title, got.Description)
+	}
+	if got.Priority != 1 || got.IssueType != types.TypeBug || got.Assignee != "alice" {
+		t.Errorf("round-tripped priority, type, assignee = %d, %q, %q", got.Priority, got.IssueType, got.Assignee)
+	}
+	if !reflect.DeepEqual(got.Labels, []string{"p1 triage", "ui"}) {
+		t.Errorf("round-tripped labels = %v", got.Labels)
+	}
+
+	// Exporting the import gives the same file
+	var again bytes.Buffer
+	if err := target.ExportCSV(ctx, &again, nil); err != nil {
+		t.Fatalf("second ExportCSV failed: %v", err)
+	}
+	if again.String() != exported.String() {
+		t.Errorf("re-export differs:\n%s\nwant:\n%s", again.String(), exported.String())
+	}
+}
+
+func TestImportCSVUpdatesAndReportsRowErrors(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if err := store.CreateIssue(ctx, &types.Issue{ID: "test-a", Title: "Old", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	data := "id,title,priority,labels\n" +
+		"test-a,Renamed,P0,urgent\n" +
+		",\"New, from a\nspreadsheet\",3,\n" +
+		",,1,\n" +
+		"test-b,Bad priority,high,\n" +
+		"test-c,Too many,1,,extra\n"
+	err := store.ImportCSV(ctx, strings.NewReader(data))
+	var importErr *CSVImportError
+	if !errors.As(err, &importErr) {
+		t.Fatalf("ImportCSV = %v, want *CSVImportError", err)
+	}
+	var lines []int
+	for _, row := range importErr.Rows {
+		lines = append(lines, row.Line)
+	}
+	// The quoted title spans lines 3-4, so the missing title is on line 5
+	if !reflect.DeepEqual(lines, []int{5, 6, 7}) {
+		t.Errorf("failed lines = %v, want [5 6 7]: %v", lines, err)
+	}
+
+	updated, err := store.GetIssue(ctx, "test-a")
+	if err != nil || updated == nil {
+		t.Fatalf("GetIssue(test-a) = %v, %v", updated, err)
+	}
+	if updated.Title != "Renamed" || updated.Priority != 0 || !reflect.DeepEqual(updated.Labels, []string{"urgent"}) {
+		t.Errorf("updated issue = %q, P%d, %v; want Renamed, P0, [urgent]", updated.Title, updated.Priority, updated.Labels)
+	}
+
+	created, err := store.SearchIssues(ctx, "spreadsheet", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(created) != 1 || created[0].Title != "New, from a\nspreadsheet" || created[0].Priority != 3 || created[0].Status != types.StatusOpen {
+		t.Errorf("created issues = %+v, want one open P3 issue with the multi-line title", created)
+	}
+}
+
+func TestCSVColumnValidation(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if err := store.ExportCSV(ctx, &bytes.Buffer{}, []string{"id", "bogus"}); err == nil {
+		t.Error("ExportCSV with an unknown column should fail")
+	}
+	if err := store.ImportCSV(ctx, strings.NewReader("id,bogus\ntest-a,x\n")); err == nil {
+		t.Error("ImportCSV with an unknown column should fail")
+	}
+	if err := store.ImportCSV(ctx, strings.NewReader("priority,status\n1,open\n")); err == nil {
+		t.Error("ImportCSV without an id or title column should fail")
+	}
+}
@@ -0,0 +1,86 @@
+package mariadb
+
+import (
+	"context"
+	"fmt"
+)
+
+// TableStats is the size of one Beads table, as reported by
+// information_schema.tables.
+type TableStats struct {
+	Name       string // Table name, with the table prefix
+	Rows       int64  // Estimated row count; see DatabaseStats
+	DataBytes  int64  // Size of the rows
+	IndexBytes int64  // Size of the secondary indexes
+}
+
+// DBStats summarizes the size of a Beads database for capacity planning.
+type DBStats struct {
+	Tables     []TableStats // In schema order; tables that do not exist are left out
+	DataBytes  int64        // Sum of Tables' DataBytes
+	IndexBytes int64        // Sum of Tables' IndexBytes
+	TotalBytes int64        // DataBytes plus IndexBytes
+
+	// Exact row counts of the main tables, soft-deleted issues included
+	Issues       int64
+	Dependencies int64
+}
+
+// DatabaseStats returns the size of each Beads table and of the whole
+// database, and the exact numbers of issues and dependencies, e.g. for a
+// `bd stats` command. Tables outside Beads, and views, are not counted.
+//
+// Table sizes and row counts come from information_schema, which for InnoDB
+// is maintained by the server's persistent statistics: row counts are
+// estimates that can be off by tens of percent, and all figures can lag
+// recent writes until statistics are recalculated. Run Analyze first for
+// current figures. Issues and Dependencies are always exact, since they are
+// counted directly.
+func (s *MariaDBStore) DatabaseStats(ctx context.Context) (DBStats, error) {
+	byName := make(map[string]TableStats)
+	err := s.withReadRetry(ctx, func() error {
+		clear(byName)
+		rows, err := s.dbOrTx().QueryContext(ctx, `
+			SELECT table_name, COALESCE(table_rows, 0), COALESCE(data_length, 0), COALESCE(index_length, 0)
+			FROM information_schema.tables
+			WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE'
+		`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var t TableStats
+			if err := rows.Scan(&t.Name, &t.Rows, &t.DataBytes, &t.IndexBytes); err != nil {
+				return err
+			}
+			byName[t.Name] = t
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return DBStats{}, fmt.Errorf("failed to read table statistics: %w", err)
+	}
+
+	var stats DBStats
+	for _, name := range tableNames {
+		t, ok := byName[s.tablePrefix+name]
+		if !ok {
+			continue
+		}
+		stats.Tables = append(stats.Tables, t)
+		stats.DataBytes += t.DataBytes
+		stats.IndexBytes += t.IndexBytes
+	}
+	stats.TotalBytes = stats.DataBytes + stats.IndexBytes
+
+	err = s.withReadRetry(ctx, func() error {
+		return s.dbOrTx().QueryRowContext(ctx, `
+			SELECT (SELECT COUNT(*) FROM issues), (SELECT COUNT(*) FROM dependencies)
+		`).Scan(&stats.Issues, &stats.Dependencies)
+	})
+	if err != nil {
+		return DBStats{}, fmt.Errorf("failed to count issues and dependencies: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,56 @@
+package mariadb
+
+import (
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestDatabaseStats(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	issues := newBatchIssues(10, "Stats")
+	if err := store.CreateIssuesBatch(ctx, issues, "tester"); err != nil {
+		t.Fatalf("CreateIssuesBatch failed: %v", err)
+	}
+	for i := 1; i < len(issues); i++ {
+		dep := &types.Dependency{IssueID: issues[i].ID, DependsOnID: issues[0].ID, Type: types.DepRelated}
+		if err := store.AddDependency(ctx, dep, "tester"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	// Refresh the estimates the table statistics are built from
+	if err := store.Analyze(ctx); err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	stats, err := store.DatabaseStats(ctx)
+	if err != nil {
+		t.Fatalf("DatabaseStats failed: %v", err)
+	}
+	if stats.Issues != 10 || stats.Dependencies != 9 {
+		t.Errorf("counts = %d issues, %d dependencies; want 10, 9", stats.Issues, stats.Dependencies)
+	}
+	if len(stats.Tables) != len(tableNames) {
+		t.Errorf("got %d tables, want %d", len(stats.Tables), len(tableNames))
+	}
+	var sum int64
+	tables := make(map[string]TableStats)
+	for _, table := range stats.Tables {
+		tables[table.Name] = table
+		sum += table.DataBytes + table.IndexBytes
+	}
+	if issuesTable := tables["issues"]; issuesTable.Rows == 0 || issuesTable.DataBytes == 0 || issuesTable.IndexBytes == 0 {
+		t.Errorf("issues table stats = %+v, want nonzero rows and sizes", issuesTable)
+	}
+	if stats.TotalBytes == 0 || stats.TotalBytes != sum || stats.TotalBytes != stats.DataBytes+stats.IndexBytes {
+		t.Errorf("totals = %d data + %d index = %d, table sum %d", stats.DataBytes, stats.IndexBytes, stats.TotalBytes, sum)
+	}
+	if _, ok := tables["ready_issues"]; ok {
+		t.Error("views should not be counted as tables")
+	}
+}
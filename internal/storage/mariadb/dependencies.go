@@ -4,47 +4,370 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/steveyegge/beads/internal/storage"
 	"github.com/steveyegge/beads/internal/types"
 )
 
-// AddDependency adds a dependency between two issues
+// AddDependency adds a dependency between two issues. It is checked for
+// cycles like a batch of one passed to AddDependencies.
 func (s *MariaDBStore) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
-	metadata := dep.Metadata
-	if metadata == "" {
-		metadata = "{}"
+	return s.AddDependencies(ctx, []*types.Dependency{dep}, actor)
+}
+
+// ErrDependencyCycle is returned by AddDependencies when the new
+// dependencies would make an issue depend on itself.
+var ErrDependencyCycle = storage.ErrDependencyCycle
+
+// AddDependencies adds dependencies with multi-row INSERT statements in a
+// single transaction. Existing dependencies are updated, as by AddDependency.
+// The dependencies are checked as the SQLite backend checks them, except that
+// the target need not exist locally, since it may be an issue in another
+// repository (see ListDanglingDependencies): the dependent issue must exist,
+// the type must be valid, and an epic cannot depend on a non-epic child
+// through a parent-child dependency. The graph of every dependency type except
+// relates-to is then checked; if any new dependency closes a loop, nothing is
+// written and the error wraps ErrDependencyCycle. depends_on_id has no foreign
+// key, so this check is the only guard against cycles. Only the paths leaving
+// the new dependencies' targets are read, with a locking read, so concurrent
+// batches cannot each close half of a loop while batches elsewhere in the
+// graph proceed.
+func (s *MariaDBStore) AddDependencies(ctx context.Context, deps []*types.Dependency, actor string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	if len(deps) == 0 {
+		return nil
+	}
+
+	rows := make([][]interface{}, len(deps))
+	for i, dep := range deps {
+		metadata := dep.Metadata
+		if metadata == "" {
+			metadata = "{}"
+		}
+		rows[i] = []interface{}{dep.IssueID, dep.DependsOnID, dep.Type, actor, metadata, dep.ThreadID}
+	}
+
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		if err := checkNewDependencies(ctx, tx, deps); err != nil {
+			return err
+		}
+		if err := execBatchInsert(ctx, tx, s.maxPacket,
+			"INSERT INTO dependencies (issue_id, depends_on_id, type, created_by, metadata, thread_id)",
+			" ON DUPLICATE KEY UPDATE type = VALUES(type), metadata = VALUES(metadata)", rows); err != nil {
+			return fmt.Errorf("failed to add dependencies: %w", err)
+		}
+		return nil
+	})
+}
+
+// checkNewDependencies returns an error if deps may not be added within tx,
+// as described by AddDependencies.
+func checkNewDependencies(ctx context.Context, tx *sql.Tx, deps []*types.Dependency) error {
+	var ordering []*types.Dependency // Dependencies checked for cycles
+	for _, dep := range deps {
+		if !dep.Type.IsValid() {
+			return fmt.Errorf("invalid dependency type: %q (must be non-empty string, max 50 chars)", dep.Type)
+		}
+		if dep.IssueID == dep.DependsOnID {
+			return fmt.Errorf("%w: %s depends on itself", ErrDependencyCycle, dep.IssueID)
+		}
+		// relates-to links go both ways, so they are not cycles
+		if dep.Type != types.DepRelatesTo {
+			ordering = append(ordering, dep)
+		}
+	}
+	if err := checkDependencyIssues(ctx, tx, deps); err != nil {
+		return err
+	}
+	if len(ordering) == 0 {
+		return nil
+	}
+
+	graph, err := lockReachableGraph(ctx, tx, ordering)
+	if err != nil {
+		return err
+	}
+	// The batch replaces the type of dependencies it already has
+	for _, dep := range deps {
+		graph[dep.IssueID] = slices.DeleteFunc(graph[dep.IssueID], func(id string) bool { return id == dep.DependsOnID })
+	}
+	for _, dep := range ordering {
+		graph[dep.IssueID] = append(graph[dep.IssueID], dep.DependsOnID)
+	}
+	for _, dep := range ordering {
+		if path := findPath(graph, dep.DependsOnID, dep.IssueID); path != nil {
+			return fmt.Errorf("%w: %s -> %s", ErrDependencyCycle, dep.IssueID, strings.Join(path, " -> "))
+		}
+	}
+	return nil
+}
+
+// checkDependencyIssues returns an error if the dependent issue of deps does
+// not exist or is soft-deleted, or if an epic depends on a non-epic through a
+// parent-child dependency: the child depends on its parent, not the reverse.
+// A target that is absent locally is allowed as a cross-repo reference.
+func checkDependencyIssues(ctx context.Context, tx *sql.Tx, deps []*types.Dependency) error {
+	var ids []string
+	for _, dep := range deps {
+		ids = append(ids, dep.IssueID, dep.DependsOnID)
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	// nolint:gosec // G201: placeholders contains only ? markers
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT id, issue_type FROM issues WHERE id IN (%s) AND %s", placeholders, notSoftDeleted), args...)
+	if err != nil {
+		return fmt.Errorf("failed to check dependency issues: %w", err)
+	}
+	defer rows.Close()
+	issueTypes := make(map[string]types.IssueType, len(ids))
+	for rows.Next() {
+		var id string
+		var issueType types.IssueType
+		if err := rows.Scan(&id, &issueType); err != nil {
+			return fmt.Errorf("failed to check dependency issues: %w", err)
+		}
+		issueTypes[id] = issueType
 	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to check dependency issues: %w", err)
+	}
+
+	for _, dep := range deps {
+		from, ok := issueTypes[dep.IssueID]
+		if !ok {
+			return fmt.Errorf("issue %s not found", dep.IssueID)
+		}
+		to, ok := issueTypes[dep.DependsOnID]
+		if ok && dep.Type == types.DepParentChild && from == types.TypeEpic && to != types.TypeEpic {
+			return fmt.Errorf("invalid parent-child dependency: parent (%s) cannot depend on child (%s)", dep.IssueID, dep.DependsOnID)
+		}
+	}
+	return nil
+}
+
+// lockReachableGraph loads the dependencies, of every type except
+// relates-to, on the paths leaving the targets of deps, as an adjacency list
+// like loadGraph's. A new dependency can only close a loop through them. They
+// are read with a locking read, which also locks the index gaps where an
+// issue on those paths has no dependencies, so concurrent batches cannot add
+// to the paths until tx ends. The rest of the graph is neither read nor
+// locked.
+//
+// The recursive part of the query may read an older snapshot than the locking
+// part, which sees the latest committed rows, so the targets of dependencies
+// it did not follow are queried again until every path has been followed.
+func lockReachableGraph(ctx context.Context, tx *sql.Tx, deps []*types.Dependency) (map[string][]string, error) {
+	graph := make(map[string][]string)
+	loaded := make(map[string]bool) // Issues whose dependencies are in graph
+	var frontier []string
+	for _, dep := range deps {
+		frontier = append(frontier, dep.DependsOnID)
+	}
+	slices.Sort(frontier)
+	frontier = slices.Compact(frontier)
+
+	for len(frontier) > 0 {
+		reached, err := lockReachable(ctx, tx, frontier)
+		if err != nil {
+			return nil, err
+		}
+		for id, dependsOn := range reached {
+			if !loaded[id] {
+				loaded[id] = true
+				graph[id] = dependsOn
+			}
+		}
+		frontier = nil
+		for id := range reached {
+			for _, next := range graph[id] {
+				if !loaded[next] && !slices.Contains(frontier, next) {
+					frontier = append(frontier, next)
+				}
+			}
+		}
+	}
+	return graph, nil
+}
 
-	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO dependencies (issue_id, depends_on_id, type, created_at, created_by, metadata, thread_id)
-		VALUES (?, ?, ?, NOW(), ?, ?, ?)
-		ON DUPLICATE KEY UPDATE type = VALUES(type), metadata = VALUES(metadata)
-	`, dep.IssueID, dep.DependsOnID, dep.Type, actor, metadata, dep.ThreadID)
+// lockReachable returns the issues reachable from ids through dependencies
+// other than relates-to, ids included, each mapped to the issues it depends
+// on, locking those dependencies as lockReachableGraph describes.
+func lockReachable(ctx context.Context, tx *sql.Tx, ids []string) (map[string][]string, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	seeds := strings.Repeat(" UNION SELECT ?", len(ids))
+	args := make([]interface{}, 0, 2*len(ids)+3)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	args = append(args, types.DepRelatesTo, types.DepRelatesTo)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	args = append(args, types.DepRelatesTo)
+
+	// UNION drops repeated dependencies, so the recursion ends on cycles
+	// nolint:gosec // G201: placeholders and seeds contain only ? markers
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		WITH RECURSIVE reachable (issue_id, depends_on_id) AS (
+			SELECT issue_id, depends_on_id FROM dependencies
+			WHERE issue_id IN (%s) AND type != ?
+			UNION
+			SELECT d.issue_id, d.depends_on_id FROM reachable r
+			JOIN dependencies d ON d.issue_id = r.depends_on_id
+			WHERE d.type != ?
+		)
+		SELECT n.id, d.depends_on_id
+		FROM (SELECT depends_on_id AS id FROM reachable%s) n
+		LEFT JOIN dependencies d ON d.issue_id = n.id AND d.type != ?
+		FOR UPDATE
+	`, placeholders, seeds), args...)
 	if err != nil {
-		return fmt.Errorf("failed to add dependency: %w", err)
+		return nil, fmt.Errorf("failed to load dependency graph: %w", err)
+	}
+	defer rows.Close()
+
+	reached := make(map[string][]string)
+	for rows.Next() {
+		var id string
+		var dependsOn sql.NullString
+		if err := rows.Scan(&id, &dependsOn); err != nil {
+			return nil, fmt.Errorf("failed to scan dependency: %w", err)
+		}
+		if dependsOn.Valid {
+			reached[id] = append(reached[id], dependsOn.String)
+		} else if _, ok := reached[id]; !ok {
+			reached[id] = nil
+		}
+	}
+	return reached, rows.Err()
+}
+
+// blockingGraph loads the blocks dependencies as an adjacency list from each
+// issue to the issues it depends on.
+func blockingGraph(ctx context.Context, db querier) (map[string][]string, error) {
+	return loadGraph(ctx, db, "SELECT issue_id, depends_on_id FROM dependencies WHERE type = ?", types.DepBlocks)
+}
+
+// loadGraph runs query, which selects issue_id and depends_on_id pairs, and
+// returns them as an adjacency list from each issue to the issues it depends
+// on.
+func loadGraph(ctx context.Context, db querier, query string, args ...interface{}) (map[string][]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dependency graph: %w", err)
+	}
+	defer rows.Close()
+
+	graph := make(map[string][]string)
+	for rows.Next() {
+		var issueID, dependsOnID string
+		if err := rows.Scan(&issueID, &dependsOnID); err != nil {
+			return nil, fmt.Errorf("failed to scan dependency: %w", err)
+		}
+		graph[issueID] = append(graph[issueID], dependsOnID)
+	}
+	return graph, rows.Err()
+}
+
+// findPath returns a path of issue IDs from one issue to another in graph,
+// starting with from and ending with to, or nil if to is unreachable.
+func findPath(graph map[string][]string, from, to string) []string {
+	parent := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if node == to {
+			var path []string
+			for ; node != ""; node = parent[node] {
+				path = append(path, node)
+			}
+			slices.Reverse(path)
+			return path
+		}
+		for _, next := range graph[node] {
+			if _, seen := parent[next]; !seen {
+				parent[next] = node
+				queue = append(queue, next)
+			}
+		}
 	}
 	return nil
 }
 
 // RemoveDependency removes a dependency between two issues
 func (s *MariaDBStore) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
-	_, err := s.db.ExecContext(ctx, `
-		DELETE FROM dependencies WHERE issue_id = ? AND depends_on_id = ?
-	`, issueID, dependsOnID)
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	err := s.withReplayableRetry(ctx, func() error {
+		_, err := s.dbOrTx().ExecContext(ctx, `
+			DELETE FROM dependencies WHERE issue_id = ? AND depends_on_id = ?
+		`, issueID, dependsOnID)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to remove dependency: %w", err)
 	}
 	return nil
 }
 
+// ClearDependencies removes every dependency of issueID, of any type, in one
+// statement, and returns how many it removed. Dependencies on issueID are
+// kept; see ClearDependents.
+func (s *MariaDBStore) ClearDependencies(ctx context.Context, issueID string) (int, error) {
+	return s.clearDependencies(ctx, "issue_id", issueID)
+}
+
+// ClearDependents removes every dependency on issueID, of any type, in one
+// statement, and returns how many it removed. issueID's own dependencies are
+// kept; see ClearDependencies.
+func (s *MariaDBStore) ClearDependents(ctx context.Context, issueID string) (int, error) {
+	return s.clearDependencies(ctx, "depends_on_id", issueID)
+}
+
+// clearDependencies deletes the dependencies whose column (issue_id or
+// depends_on_id) is issueID.
+func (s *MariaDBStore) clearDependencies(ctx context.Context, column, issueID string) (int, error) {
+	if err := s.checkWritable(); err != nil {
+		return 0, err
+	}
+	var removed int64
+	err := s.withReplayableRetry(ctx, func() error {
+		// nolint:gosec // G201: column is one of two constant column names
+		result, err := s.dbOrTx().ExecContext(ctx, fmt.Sprintf("DELETE FROM dependencies WHERE %s = ?", column), issueID)
+		if err != nil {
+			return err
+		}
+		removed, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear dependencies: %w", err)
+	}
+	return int(removed), nil
+}
+
 // GetDependencies retrieves issues that this issue depends on
 func (s *MariaDBStore) GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
 		SELECT i.id FROM issues i
 		JOIN dependencies d ON i.id = d.depends_on_id
-		WHERE d.issue_id = ?
+		WHERE d.issue_id = ? AND `+notSoftDeletedAs("i")+`
 		ORDER BY i.priority ASC, i.created_at DESC
 	`, issueID)
 	if err != nil {
@@ -52,15 +375,18 @@ func (s *MariaDBStore) GetDependencies(ctx context.Context, issueID string) ([]*
 	}
 	defer rows.Close()
 
-	return s.scanIssueIDs(ctx, rows)
+	return s.scanIssueIDs(ctx, rows, false)
 }
 
 // GetDependents retrieves issues that depend on this issue
 func (s *MariaDBStore) GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
 		SELECT i.id FROM issues i
 		JOIN dependencies d ON i.id = d.issue_id
-		WHERE d.depends_on_id = ?
+		WHERE d.depends_on_id = ? AND `+notSoftDeletedAs("i")+`
 		ORDER BY i.priority ASC, i.created_at DESC
 	`, issueID)
 	if err != nil {
@@ -68,12 +394,15 @@ func (s *MariaDBStore) GetDependents(ctx context.Context, issueID string) ([]*ty
 	}
 	defer rows.Close()
 
-	return s.scanIssueIDs(ctx, rows)
+	return s.scanIssueIDs(ctx, rows, false)
 }
 
 // GetDependenciesWithMetadata returns dependencies with metadata
 func (s *MariaDBStore) GetDependenciesWithMetadata(ctx context.Context, issueID string) ([]*types.IssueWithDependencyMetadata, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
 		SELECT d.depends_on_id, d.type, d.created_at, d.created_by, d.metadata, d.thread_id
 		FROM dependencies d
 		WHERE d.issue_id = ?
@@ -139,7 +468,10 @@ func (s *MariaDBStore) GetDependenciesWithMetadata(ctx context.Context, issueID
 
 // GetDependentsWithMetadata returns dependents with metadata
 func (s *MariaDBStore) GetDependentsWithMetadata(ctx context.Context, issueID string) ([]*types.IssueWithDependencyMetadata, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
 		SELECT d.issue_id, d.type, d.created_at, d.created_by, d.metadata, d.thread_id
 		FROM dependencies d
 		WHERE d.depends_on_id = ?
@@ -205,7 +537,10 @@ func (s *MariaDBStore) GetDependentsWithMetadata(ctx context.Context, issueID st
 
 // GetDependencyRecords returns raw dependency records for an issue
 func (s *MariaDBStore) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
 		SELECT issue_id, depends_on_id, type, created_at, created_by, metadata, thread_id
 		FROM dependencies
 		WHERE issue_id = ?
@@ -220,7 +555,10 @@ func (s *MariaDBStore) GetDependencyRecords(ctx context.Context, issueID string)
 
 // GetAllDependencyRecords returns all dependency records
 func (s *MariaDBStore) GetAllDependencyRecords(ctx context.Context) (map[string][]*types.Dependency, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
 		SELECT issue_id, depends_on_id, type, created_at, created_by, metadata, thread_id
 		FROM dependencies
 		ORDER BY issue_id
@@ -241,8 +579,56 @@ func (s *MariaDBStore) GetAllDependencyRecords(ctx context.Context) (map[string]
 	return result, rows.Err()
 }
 
+// ListDanglingDependencies returns the dependencies whose target issue is not
+// in this database, ordered by issue and target. Since depends_on_id has no
+// foreign key, these are references to other repositories (such as
+// external:<rig>:<id>) or to issues that were deleted outright, for tooling to
+// flag or resolve. Soft-deleted targets still exist and are not included.
+func (s *MariaDBStore) ListDanglingDependencies(ctx context.Context) ([]*types.Dependency, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
+		SELECT d.issue_id, d.depends_on_id, d.type, d.created_at, d.created_by, d.metadata, d.thread_id
+		FROM dependencies d
+		LEFT JOIN issues i ON i.id = d.depends_on_id
+		WHERE i.id IS NULL
+		ORDER BY d.issue_id, d.depends_on_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dangling dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDependencyRows(rows)
+}
+
+// GetExternalDependencies returns the dependencies of issue id whose target
+// is not in this database, as ListDanglingDependencies does for all issues.
+func (s *MariaDBStore) GetExternalDependencies(ctx context.Context, id string) ([]*types.Dependency, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
+		SELECT d.issue_id, d.depends_on_id, d.type, d.created_at, d.created_by, d.metadata, d.thread_id
+		FROM dependencies d
+		LEFT JOIN issues i ON i.id = d.depends_on_id
+		WHERE d.issue_id = ? AND i.id IS NULL
+		ORDER BY d.depends_on_id
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get external dependencies of %s: %w", id, err)
+	}
+	defer rows.Close()
+
+	return scanDependencyRows(rows)
+}
+
 // GetDependencyRecordsForIssues returns dependency records for specific issues
 func (s *MariaDBStore) GetDependencyRecordsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Dependency, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 	if len(issueIDs) == 0 {
 		return make(map[string][]*types.Dependency), nil
 	}
@@ -263,7 +649,7 @@ func (s *MariaDBStore) GetDependencyRecordsForIssues(ctx context.Context, issueI
 		ORDER BY issue_id
 	`, inClause)
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.queryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get dependency records for issues: %w", err)
 	}
@@ -282,6 +668,9 @@ func (s *MariaDBStore) GetDependencyRecordsForIssues(ctx context.Context, issueI
 
 // GetDependencyCounts returns dependency counts for multiple issues
 func (s *MariaDBStore) GetDependencyCounts(ctx context.Context, issueIDs []string) (map[string]*types.DependencyCounts, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 	if len(issueIDs) == 0 {
 		return make(map[string]*types.DependencyCounts), nil
 	}
@@ -303,7 +692,7 @@ func (s *MariaDBStore) GetDependencyCounts(ctx context.Context, issueIDs []strin
 		GROUP BY issue_id
 	`, inClause)
 
-	depRows, err := s.db.QueryContext(ctx, depQuery, args...)
+	depRows, err := s.queryContext(ctx, depQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get dependency counts: %w", err)
 	}
@@ -334,7 +723,7 @@ func (s *MariaDBStore) GetDependencyCounts(ctx context.Context, issueIDs []strin
 		GROUP BY depends_on_id
 	`, inClause)
 
-	blockingRows, err := s.db.QueryContext(ctx, blockingQuery, args...)
+	blockingRows, err := s.queryContext(ctx, blockingQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get blocking counts: %w", err)
 	}
@@ -356,6 +745,9 @@ func (s *MariaDBStore) GetDependencyCounts(ctx context.Context, issueIDs []strin
 
 // GetDependencyTree returns a dependency tree for visualization
 func (s *MariaDBStore) GetDependencyTree(ctx context.Context, issueID string, maxDepth int, showAllPaths bool, reverse bool) ([]*types.TreeNode, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 	// Simple implementation - can be optimized with CTE
 	visited := make(map[string]bool)
 	return s.buildDependencyTree(ctx, issueID, 0, maxDepth, reverse, visited)
@@ -380,7 +772,7 @@ func (s *MariaDBStore) buildDependencyTree(ctx context.Context, issueID string,
 		query = "SELECT depends_on_id FROM dependencies WHERE issue_id = ?"
 	}
 
-	rows, err := s.db.QueryContext(ctx, query, issueID)
+	rows, err := s.queryContext(ctx, query, issueID)
 	if err != nil {
 		return nil, err
 	}
@@ -486,15 +878,111 @@ func (s *MariaDBStore) DetectCycles(ctx context.Context) ([][]*types.Issue, erro
 	return cycles, nil
 }
 
+// FindDependencyCycles returns the cycles among blocks dependencies, each as
+// the issue IDs in dependency order (each issue depends on the next, and the
+// last on the first), starting from its smallest ID. Cycles are sorted by
+// their first ID. AddDependency rejects new cycles, so these come from data
+// written before that check, or around it, and keep their issues out of
+// ready_issues for good.
+//
+// Every issue on a cycle is reported, but cycles sharing issues are not all
+// enumerated: each dependency that closes a cycle during the search reports
+// one, so two cycles through the same issues may be reported as one. Breaking
+// the reported cycles and searching again finds the rest.
+func (s *MariaDBStore) FindDependencyCycles(ctx context.Context) ([][]string, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	graph, err := blockingGraph(ctx, s.dbOrTx())
+	if err != nil {
+		return nil, err
+	}
+	return findCycles(graph), nil
+}
+
+// findCycles returns a cycle for each back edge of a depth-first search of
+// graph, as described by FindDependencyCycles, sorting graph's adjacency
+// lists on the way. The search keeps its own stack, so deep dependency chains
+// cannot overflow the goroutine's.
+func findCycles(graph map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		onPath    // On the current search path
+		done      // Searched, with everything reachable from it
+	)
+	color := make(map[string]int, len(graph))
+	position := make(map[string]int) // Index of an onPath issue in path
+
+	// Visit issues and dependencies in order, so the result is stable
+	starts := make([]string, 0, len(graph))
+	for id, deps := range graph {
+		starts = append(starts, id)
+		slices.Sort(deps)
+	}
+	slices.Sort(starts)
+
+	type frame struct {
+		id   string
+		next int // Index in graph[id] of the next dependency to follow
+	}
+	var cycles [][]string
+	for _, start := range starts {
+		if color[start] != unvisited {
+			continue
+		}
+		color[start], position[start] = onPath, 0
+		path := []string{start}
+		stack := []frame{{id: start}}
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			deps := graph[top.id]
+			if top.next == len(deps) {
+				color[top.id] = done
+				delete(position, top.id)
+				stack = stack[:len(stack)-1]
+				path = path[:len(path)-1]
+				continue
+			}
+			dep := deps[top.next]
+			top.next++
+			switch color[dep] {
+			case unvisited:
+				color[dep], position[dep] = onPath, len(path)
+				path = append(path, dep)
+				stack = append(stack, frame{id: dep})
+			case onPath:
+				cycles = append(cycles, rotateToMin(slices.Clone(path[position[dep]:])))
+			}
+		}
+	}
+	slices.SortStableFunc(cycles, func(a, b []string) int { return strings.Compare(a[0], b[0]) })
+	return cycles
+}
+
+// rotateToMin returns cycle rotated to start at its smallest ID.
+func rotateToMin(cycle []string) []string {
+	first := 0
+	for i, id := range cycle {
+		if id < cycle[first] {
+			first = i
+		}
+	}
+	return append(cycle[first:], cycle[:first]...)
+}
+
 // IsBlocked checks if an issue has open blockers
 func (s *MariaDBStore) IsBlocked(ctx context.Context, issueID string) (bool, []string, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	if s.IsClosed() {
+		return false, nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
 		SELECT d.depends_on_id
 		FROM dependencies d
 		JOIN issues i ON d.depends_on_id = i.id
 		WHERE d.issue_id = ?
 		  AND d.type = 'blocks'
 		  AND i.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+		  AND i.deleted_at IS NULL
 	`, issueID)
 	if err != nil {
 		return false, nil, fmt.Errorf("failed to check blockers: %w", err)
@@ -513,10 +1001,71 @@ func (s *MariaDBStore) IsBlocked(ctx context.Context, issueID string) (bool, []s
 	return len(blockers) > 0, blockers, rows.Err()
 }
 
+// maxBlockerDepth bounds the dependency chains GetTransitiveBlockers follows,
+// matching the depth limit of the ready_issues view.
+const maxBlockerDepth = 50
+
+// GetTransitiveBlockers returns the IDs of every open issue blocking id,
+// directly or through a chain of blocks dependencies on other open issues.
+// Closed and soft-deleted issues, and whatever blocks them, are not included.
+// Blockers are ordered by distance, direct blockers first, then by ID.
+// Chains are followed up to maxBlockerDepth levels and cycles are cut.
+func (s *MariaDBStore) GetTransitiveBlockers(ctx context.Context, id string) ([]string, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	// path lists the issues on the chain as ",a,b,c," so a revisit is detected
+	rows, err := s.readQueryContext(ctx, `
+		WITH RECURSIVE blockers (id, depth, path) AS (
+			SELECT d.depends_on_id, 1, CAST(CONCAT(',', d.issue_id, ',', d.depends_on_id, ',') AS CHAR(8192))
+			FROM dependencies d
+			JOIN issues blocker ON blocker.id = d.depends_on_id
+			WHERE d.issue_id = ?
+			  AND d.type = 'blocks'
+			  AND d.depends_on_id != d.issue_id
+			  AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+			  AND blocker.deleted_at IS NULL
+			UNION ALL
+			SELECT d.depends_on_id, b.depth + 1, CONCAT(b.path, d.depends_on_id, ',')
+			FROM blockers b
+			JOIN dependencies d ON d.issue_id = b.id
+			JOIN issues blocker ON blocker.id = d.depends_on_id
+			WHERE d.type = 'blocks'
+			  AND b.depth < ?
+			  AND LOCATE(CONCAT(',', d.depends_on_id, ','), b.path) = 0
+			  AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+			  AND blocker.deleted_at IS NULL
+		)
+		SELECT id, MIN(depth) AS distance
+		FROM blockers
+		WHERE id != ?
+		GROUP BY id
+		ORDER BY distance ASC, id ASC
+	`, id, maxBlockerDepth, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transitive blockers: %w", err)
+	}
+	defer rows.Close()
+
+	var blockers []string
+	for rows.Next() {
+		var blocker string
+		var distance int
+		if err := rows.Scan(&blocker, &distance); err != nil {
+			return nil, fmt.Errorf("failed to scan blocker: %w", err)
+		}
+		blockers = append(blockers, blocker)
+	}
+	return blockers, rows.Err()
+}
+
 // GetNewlyUnblockedByClose finds issues that become unblocked when an issue is closed
 func (s *MariaDBStore) GetNewlyUnblockedByClose(ctx context.Context, closedIssueID string) ([]*types.Issue, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 	// Find issues that were blocked only by the closed issue
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.queryContext(ctx, `
 		SELECT DISTINCT d.issue_id
 		FROM dependencies d
 		JOIN issues i ON d.issue_id = i.id
@@ -530,6 +1079,7 @@ func (s *MariaDBStore) GetNewlyUnblockedByClose(ctx context.Context, closedIssue
 			  AND d2.type = 'blocks'
 			  AND d2.depends_on_id != ?
 			  AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+			  AND blocker.deleted_at IS NULL
 		  )
 	`, closedIssueID, closedIssueID)
 	if err != nil {
@@ -537,12 +1087,14 @@ func (s *MariaDBStore) GetNewlyUnblockedByClose(ctx context.Context, closedIssue
 	}
 	defer rows.Close()
 
-	return s.scanIssueIDs(ctx, rows)
+	return s.scanIssueIDs(ctx, rows, false)
 }
 
 // Helper functions
 
-func (s *MariaDBStore) scanIssueIDs(ctx context.Context, rows *sql.Rows) ([]*types.Issue, error) {
+// scanIssueIDs loads the issues whose IDs rows selects, keeping soft-deleted
+// issues if includeDeleted is set.
+func (s *MariaDBStore) scanIssueIDs(ctx context.Context, rows *sql.Rows, includeDeleted bool) ([]*types.Issue, error) {
 	// First, collect all IDs
 	var ids []string
 	for rows.Next() {
@@ -568,11 +1120,21 @@ func (s *MariaDBStore) scanIssueIDs(ctx context.Context, rows *sql.Rows) ([]*typ
 	}
 
 	// Fetch all issues in a single batch query
-	return s.GetIssuesByIDs(ctx, ids)
+	return s.getIssuesByIDs(ctx, ids, includeDeleted)
 }
 
-// GetIssuesByIDs retrieves multiple issues by ID in a single query to avoid N+1 performance issues
+// GetIssuesByIDs retrieves multiple issues by ID in a single query to avoid N+1 performance issues.
+// Soft-deleted issues are left out.
 func (s *MariaDBStore) GetIssuesByIDs(ctx context.Context, ids []string) ([]*types.Issue, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	return s.getIssuesByIDs(ctx, ids, false)
+}
+
+// getIssuesByIDs is GetIssuesByIDs, keeping soft-deleted issues if
+// includeDeleted is set.
+func (s *MariaDBStore) getIssuesByIDs(ctx context.Context, ids []string, includeDeleted bool) ([]*types.Issue, error) {
 	if len(ids) == 0 {
 		return nil, nil
 	}
@@ -601,8 +1163,11 @@ func (s *MariaDBStore) GetIssuesByIDs(ctx context.Context, ids []string) ([]*typ
 		FROM issues
 		WHERE id IN (%s)
 	`, strings.Join(placeholders, ","))
+	if !includeDeleted {
+		query += " AND " + notSoftDeleted
+	}
 
-	queryRows, err := s.db.QueryContext(ctx, query, args...)
+	queryRows, err := s.queryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get issues by IDs: %w", err)
 	}
@@ -0,0 +1,416 @@
+package mariadb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestAddDependencies(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for _, id := range []string{"test-a", "test-b", "test-c", "test-d"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	blocks := func(from, to string) *types.Dependency {
+		return &types.Dependency{IssueID: from, DependsOnID: to, Type: types.DepBlocks}
+	}
+
+	if err := store.AddDependencies(ctx, []*types.Dependency{blocks("test-a", "test-b"), blocks("test-b", "test-c")}, "tester"); err != nil {
+		t.Fatalf("AddDependencies failed: %v", err)
+	}
+	records, err := store.GetAllDependencyRecords(ctx)
+	if err != nil {
+		t.Fatalf("GetAllDependencyRecords failed: %v", err)
+	}
+	if len(records["test-a"]) != 1 || len(records["test-b"]) != 1 {
+		t.Fatalf("dependency records = %v, want a->b and b->c", records)
+	}
+
+	// C -> A closes the loop A -> B -> C -> A; the whole batch is rejected
+	err = store.AddDependencies(ctx, []*types.Dependency{blocks("test-d", "test-a"), blocks("test-c", "test-a")}, "tester")
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("AddDependencies error = %v, want ErrDependencyCycle", err)
+	}
+	for _, id := range []string{"test-c", "test-d"} {
+		if deps, err := store.GetDependencyRecords(ctx, id); err != nil || len(deps) != 0 {
+			t.Errorf("GetDependencyRecords(%s) = %v, %v; want none after rejected batch", id, deps, err)
+		}
+	}
+
+	if err := store.AddDependencies(ctx, []*types.Dependency{blocks("test-d", "test-d")}, "tester"); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("self-dependency error = %v, want ErrDependencyCycle", err)
+	}
+
+	// Other types close loops too, except relates-to links, which go both ways
+	related := &types.Dependency{IssueID: "test-c", DependsOnID: "test-a", Type: types.DepRelated}
+	if err := store.AddDependencies(ctx, []*types.Dependency{related}, "tester"); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("AddDependencies(related) error = %v, want ErrDependencyCycle", err)
+	}
+	relatesTo := &types.Dependency{IssueID: "test-c", DependsOnID: "test-a", Type: types.DepRelatesTo}
+	if err := store.AddDependencies(ctx, []*types.Dependency{relatesTo}, "tester"); err != nil {
+		t.Errorf("AddDependencies(relates-to) failed: %v", err)
+	}
+}
+
+func TestAddDependenciesOverLegacyCycle(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for _, id := range []string{"test-w", "test-x", "test-y", "test-z"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	// x -> y -> x, written as legacy data would be
+	for _, dep := range [][2]string{{"test-x", "test-y"}, {"test-y", "test-x"}} {
+		if _, err := store.UnderlyingDB().ExecContext(ctx,
+			"INSERT INTO dependencies (issue_id, depends_on_id, type, created_by) VALUES (?, ?, ?, ?)",
+			dep[0], dep[1], types.DepBlocks, "legacy"); err != nil {
+			t.Fatalf("failed to insert %s -> %s: %v", dep[0], dep[1], err)
+		}
+	}
+	blocks := func(from, to string) *types.Dependency {
+		return &types.Dependency{IssueID: from, DependsOnID: to, Type: types.DepBlocks}
+	}
+
+	// Following the paths from test-x ends despite the loop
+	if err := store.AddDependencies(ctx, []*types.Dependency{blocks("test-w", "test-x"), blocks("test-z", "test-w")}, "tester"); err != nil {
+		t.Fatalf("AddDependencies(w -> x, z -> w) failed: %v", err)
+	}
+	err := store.AddDependency(ctx, blocks("test-y", "test-z"), "tester")
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("AddDependency(y -> z) error = %v, want ErrDependencyCycle", err)
+	}
+}
+
+func TestAddDependenciesConcurrentCycle(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for _, id := range []string{"test-a", "test-b"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+
+	// Each half of the loop is fine on its own; at most one may be written
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, dep := range [][2]string{{"test-a", "test-b"}, {"test-b", "test-a"}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = store.AddDependency(ctx, &types.Dependency{IssueID: dep[0], DependsOnID: dep[1], Type: types.DepBlocks}, "tester")
+		}()
+	}
+	wg.Wait()
+
+	added := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			added++
+		case !errors.Is(err, ErrDependencyCycle):
+			t.Errorf("AddDependency error = %v, want ErrDependencyCycle", err)
+		}
+	}
+	if added != 1 {
+		t.Errorf("%d of the concurrent dependencies were added, want 1", added)
+	}
+	if cycles, err := store.FindDependencyCycles(ctx); err != nil || cycles != nil {
+		t.Errorf("FindDependencyCycles = %v, %v; want none", cycles, err)
+	}
+}
+
+func TestClearDependenciesAndDependents(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for _, id := range []string{"test-hub", "test-up1", "test-up2", "test-down1", "test-down2", "test-other"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	// test-hub depends on two issues and an external ref, and two depend on it
+	deps := []*types.Dependency{
+		{IssueID: "test-hub", DependsOnID: "test-up1", Type: types.DepBlocks},
+		{IssueID: "test-hub", DependsOnID: "test-up2", Type: types.DepRelated},
+		{IssueID: "test-hub", DependsOnID: "external:other:test-x", Type: types.DepBlocks},
+		{IssueID: "test-down1", DependsOnID: "test-hub", Type: types.DepBlocks},
+		{IssueID: "test-down2", DependsOnID: "test-hub", Type: types.DepParentChild},
+		{IssueID: "test-other", DependsOnID: "test-up1", Type: types.DepBlocks},
+	}
+	if err := store.AddDependencies(ctx, deps, "tester"); err != nil {
+		t.Fatalf("AddDependencies failed: %v", err)
+	}
+	count := func(issueID string, dependents bool) int {
+		t.Helper()
+		var n int
+		column := "issue_id"
+		if dependents {
+			column = "depends_on_id"
+		}
+		if err := store.UnderlyingDB().QueryRowContext(ctx, "SELECT COUNT(*) FROM dependencies WHERE "+column+" = ?", issueID).Scan(&n); err != nil {
+			t.Fatalf("counting dependencies failed: %v", err)
+		}
+		return n
+	}
+
+	removed, err := store.ClearDependencies(ctx, "test-hub")
+	if err != nil || removed != 3 {
+		t.Fatalf("ClearDependencies = %d, %v; want 3", removed, err)
+	}
+	if n := count("test-hub", false); n != 0 {
+		t.Errorf("test-hub still has %d dependencies", n)
+	}
+	if n := count("test-hub", true); n != 2 {
+		t.Errorf("test-hub has %d dependents after ClearDependencies, want 2", n)
+	}
+	if n := count("test-other", false); n != 1 {
+		t.Errorf("test-other has %d dependencies, want 1", n)
+	}
+
+	removed, err = store.ClearDependents(ctx, "test-hub")
+	if err != nil || removed != 2 {
+		t.Fatalf("ClearDependents = %d, %v; want 2", removed, err)
+	}
+	if n := count("test-hub", true); n != 0 {
+		t.Errorf("test-hub still has %d dependents", n)
+	}
+
+	// Nothing left to clear
+	if removed, err := store.ClearDependents(ctx, "test-hub"); err != nil || removed != 0 {
+		t.Errorf("second ClearDependents = %d, %v; want 0", removed, err)
+	}
+	if removed, err := store.ClearDependencies(ctx, "test-missing"); err != nil || removed != 0 {
+		t.Errorf("ClearDependencies(missing) = %d, %v; want 0", removed, err)
+	}
+}
+
+func TestFindPath(t *testing.T) {
+	graph := map[string][]string{"a": {"b"}, "b": {"c", "d"}, "d": {"e"}}
+	if got := findPath(graph, "a", "e"); !reflect.DeepEqual(got, []string{"a", "b", "d", "e"}) {
+		t.Errorf("findPath(a, e) = %v", got)
+	}
+	if got := findPath(graph, "e", "a"); got != nil {
+		t.Errorf("findPath(e, a) = %v, want nil", got)
+	}
+}
+
+func TestFindCycles(t *testing.T) {
+	graph := map[string][]string{
+		"a": {"b"}, "b": {"c"}, "c": {"a"}, // a -> b -> c -> a
+		"e": {"d"}, "d": {"e", "x"}, // d -> e -> d, and an edge out
+		"f": {"f"},           // Self-dependency
+		"g": {"a", "d", "h"}, // Leads into cycles without being on one
+	}
+	want := [][]string{{"a", "b", "c"}, {"d", "e"}, {"f"}}
+	if got := findCycles(graph); !reflect.DeepEqual(got, want) {
+		t.Errorf("findCycles = %v, want %v", got, want)
+	}
+	if got := findCycles(map[string][]string{"a": {"b"}, "b": {"c"}}); got != nil {
+		t.Errorf("findCycles(acyclic) = %v, want nil", got)
+	}
+
+	// A chain far deeper than recursion would handle comfortably, closed at the end
+	const depth = 100_000
+	graph = make(map[string][]string, depth)
+	for i := 0; i < depth; i++ {
+		graph[fmt.Sprintf("n%06d", i)] = []string{fmt.Sprintf("n%06d", i+1)}
+	}
+	graph[fmt.Sprintf("n%06d", depth)] = []string{fmt.Sprintf("n%06d", depth-2)}
+	want = [][]string{{fmt.Sprintf("n%06d", depth-2), fmt.Sprintf("n%06d", depth-1), fmt.Sprintf("n%06d", depth)}}
+	if got := findCycles(graph); !reflect.DeepEqual(got, want) {
+		t.Errorf("findCycles(deep chain) = %v, want %v", got, want)
+	}
+}
+
+func TestGetTransitiveBlockers(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	create := func(id string, status types.Status) {
+		t.Helper()
+		issue := &types.Issue{ID: id, Title: id, Status: status, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	block := func(from, to string) {
+		t.Helper()
+		if err := store.AddDependency(ctx, &types.Dependency{IssueID: from, DependsOnID: to, Type: types.DepBlocks}, "tester"); err != nil {
+			t.Fatalf("AddDependency(%s, %s) failed: %v", from, to, err)
+		}
+	}
+
+	// root <- mid1 <- top, root <- mid2 <- top, closed <- top, closed <- hidden
+	for _, id := range []string{"test-top", "test-mid1", "test-mid2", "test-root", "test-hidden"} {
+		create(id, types.StatusOpen)
+	}
+	create("test-closed", types.StatusClosed)
+	block("test-top", "test-mid1")
+	block("test-top", "test-mid2")
+	block("test-mid1", "test-root")
+	block("test-mid2", "test-root")
+	block("test-top", "test-closed")
+	block("test-closed", "test-hidden")
+
+	got, err := store.GetTransitiveBlockers(ctx, "test-top")
+	if err != nil {
+		t.Fatalf("GetTransitiveBlockers failed: %v", err)
+	}
+	if want := []string{"test-mid1", "test-mid2", "test-root"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTransitiveBlockers(top) = %v, want %v", got, want)
+	}
+	if got, err := store.GetTransitiveBlockers(ctx, "test-root"); err != nil || len(got) != 0 {
+		t.Errorf("GetTransitiveBlockers(root) = %v, %v; want none", got, err)
+	}
+
+	// A cycle still terminates. AddDependency rejects cycles, so the last
+	// edge is written as legacy data would be.
+	for _, id := range []string{"test-x", "test-y", "test-z"} {
+		create(id, types.StatusOpen)
+	}
+	block("test-x", "test-y")
+	block("test-y", "test-z")
+	if _, err := store.UnderlyingDB().ExecContext(ctx,
+		"INSERT INTO dependencies (issue_id, depends_on_id, type, created_by) VALUES (?, ?, ?, ?)",
+		"test-z", "test-x", types.DepBlocks, "legacy"); err != nil {
+		t.Fatalf("failed to insert test-z -> test-x: %v", err)
+	}
+	got, err = store.GetTransitiveBlockers(ctx, "test-x")
+	if err != nil {
+		t.Fatalf("GetTransitiveBlockers(cycle) failed: %v", err)
+	}
+	if want := []string{"test-y", "test-z"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTransitiveBlockers(x) = %v, want %v", got, want)
+	}
+}
+
+func TestListDanglingDependencies(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for _, id := range []string{"test-a", "test-b", "test-gone"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	for _, dep := range [][2]string{
+		{"test-a", "test-b"},
+		{"test-a", "external:other-rig:other-1"},
+		{"test-b", "other-7"},
+		{"test-b", "test-gone"},
+	} {
+		if err := store.AddDependency(ctx, &types.Dependency{IssueID: dep[0], DependsOnID: dep[1], Type: types.DepBlocks}, "tester"); err != nil {
+			t.Fatalf("AddDependency(%s, %s) failed: %v", dep[0], dep[1], err)
+		}
+	}
+	// A deleted row leaves the reference to it dangling
+	if _, err := store.UnderlyingDB().ExecContext(ctx, "DELETE FROM issues WHERE id = ?", "test-gone"); err != nil {
+		t.Fatalf("failed to delete test-gone: %v", err)
+	}
+
+	dangling, err := store.ListDanglingDependencies(ctx)
+	if err != nil {
+		t.Fatalf("ListDanglingDependencies failed: %v", err)
+	}
+	var got []string
+	for _, dep := range dangling {
+		got = append(got, dep.IssueID+" -> "+dep.DependsOnID)
+	}
+	want := []string{"test-a -> external:other-rig:other-1", "test-b -> other-7", "test-b -> test-gone"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListDanglingDependencies = %v, want %v", got, want)
+	}
+
+	external, err := store.GetExternalDependencies(ctx, "test-a")
+	if err != nil {
+		t.Fatalf("GetExternalDependencies failed: %v", err)
+	}
+	if len(external) != 1 || external[0].DependsOnID != "external:other-rig:other-1" || external[0].Type != types.DepBlocks {
+		t.Errorf("GetExternalDependencies(test-a) = %+v, want the external:other-rig:other-1 blocker", external)
+	}
+	if external, err := store.GetExternalDependencies(ctx, "test-gone"); err != nil || len(external) != 0 {
+		t.Errorf("GetExternalDependencies(test-gone) = %+v, %v; want none", external, err)
+	}
+}
+
+func TestFindDependencyCycles(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for _, id := range []string{"test-a", "test-b", "test-c", "test-d", "test-e", "test-f", "test-g"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: "test-g", DependsOnID: "test-a", Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if cycles, err := store.FindDependencyCycles(ctx); err != nil || cycles != nil {
+		t.Fatalf("FindDependencyCycles = %v, %v; want none", cycles, err)
+	}
+
+	// AddDependency rejects cycles, so write them as legacy data would be
+	for _, dep := range [][3]string{
+		{"test-a", "test-b", "blocks"},
+		{"test-b", "test-c", "blocks"},
+		{"test-c", "test-a", "blocks"},
+		{"test-d", "test-e", "blocks"},
+		{"test-e", "test-d", "blocks"},
+		{"test-f", "test-f", "blocks"},
+		{"test-e", "test-f", "related"}, // Only blocks dependencies count
+		{"test-f", "test-e", "related"},
+	} {
+		if _, err := store.UnderlyingDB().ExecContext(ctx,
+			"INSERT INTO dependencies (issue_id, depends_on_id, type, created_by) VALUES (?, ?, ?, ?)",
+			dep[0], dep[1], dep[2], "legacy"); err != nil {
+			t.Fatalf("failed to insert %s -> %s: %v", dep[0], dep[1], err)
+		}
+	}
+
+	cycles, err := store.FindDependencyCycles(ctx)
+	if err != nil {
+		t.Fatalf("FindDependencyCycles failed: %v", err)
+	}
+	want := [][]string{{"test-a", "test-b", "test-c"}, {"test-d", "test-e"}, {"test-f"}}
+	if !reflect.DeepEqual(cycles, want) {
+		t.Errorf("FindDependencyCycles = %v, want %v", cycles, want)
+	}
+}
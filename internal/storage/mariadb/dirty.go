@@ -9,7 +9,10 @@ import (
 
 // GetDirtyIssues returns IDs of issues that have been modified since last export
 func (s *MariaDBStore) GetDirtyIssues(ctx context.Context) ([]string, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
 		SELECT issue_id FROM dirty_issues ORDER BY marked_at ASC
 	`)
 	if err != nil {
@@ -30,8 +33,11 @@ func (s *MariaDBStore) GetDirtyIssues(ctx context.Context) ([]string, error) {
 
 // GetDirtyIssueHash returns the dirty hash for a specific issue
 func (s *MariaDBStore) GetDirtyIssueHash(ctx context.Context, issueID string) (string, error) {
+	if s.IsClosed() {
+		return "", ErrStoreClosed
+	}
 	var hash string
-	err := s.db.QueryRowContext(ctx, `
+	err := s.queryRowContext(ctx, `
 		SELECT i.content_hash FROM issues i
 		JOIN dirty_issues d ON i.id = d.issue_id
 		WHERE d.issue_id = ?
@@ -44,6 +50,9 @@ func (s *MariaDBStore) GetDirtyIssueHash(ctx context.Context, issueID string) (s
 
 // ClearDirtyIssuesByID removes specific issues from the dirty list
 func (s *MariaDBStore) ClearDirtyIssuesByID(ctx context.Context, issueIDs []string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	if len(issueIDs) == 0 {
 		return nil
 	}
@@ -57,7 +66,10 @@ func (s *MariaDBStore) ClearDirtyIssuesByID(ctx context.Context, issueIDs []stri
 
 	// nolint:gosec // G201: placeholders contains only ? markers, actual values passed via args
 	query := fmt.Sprintf("DELETE FROM dirty_issues WHERE issue_id IN (%s)", strings.Join(placeholders, ","))
-	_, err := s.db.ExecContext(ctx, query, args...)
+	err := s.withReplayableRetry(ctx, func() error {
+		_, err := s.dbOrTx().ExecContext(ctx, query, args...)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to clear dirty issues: %w", err)
 	}
@@ -66,8 +78,11 @@ func (s *MariaDBStore) ClearDirtyIssuesByID(ctx context.Context, issueIDs []stri
 
 // GetExportHash returns the last export hash for an issue
 func (s *MariaDBStore) GetExportHash(ctx context.Context, issueID string) (string, error) {
+	if s.IsClosed() {
+		return "", ErrStoreClosed
+	}
 	var hash string
-	err := s.db.QueryRowContext(ctx, `
+	err := s.queryRowContext(ctx, `
 		SELECT content_hash FROM export_hashes WHERE issue_id = ?
 	`, issueID).Scan(&hash)
 	if err != nil {
@@ -78,11 +93,17 @@ func (s *MariaDBStore) GetExportHash(ctx context.Context, issueID string) (strin
 
 // SetExportHash stores the export hash for an issue
 func (s *MariaDBStore) SetExportHash(ctx context.Context, issueID, contentHash string) error {
-	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO export_hashes (issue_id, content_hash, exported_at)
-		VALUES (?, ?, ?)
-		ON DUPLICATE KEY UPDATE content_hash = VALUES(content_hash), exported_at = VALUES(exported_at)
-	`, issueID, contentHash, time.Now().UTC())
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	err := s.withReplayableRetry(ctx, func() error {
+		_, err := s.dbOrTx().ExecContext(ctx, `
+			INSERT INTO export_hashes (issue_id, content_hash, exported_at)
+			VALUES (?, ?, ?)
+			ON DUPLICATE KEY UPDATE content_hash = VALUES(content_hash), exported_at = VALUES(exported_at)
+		`, issueID, contentHash, time.Now().UTC())
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to set export hash: %w", err)
 	}
@@ -91,7 +112,13 @@ func (s *MariaDBStore) SetExportHash(ctx context.Context, issueID, contentHash s
 
 // ClearAllExportHashes removes all export hashes (for full re-export)
 func (s *MariaDBStore) ClearAllExportHashes(ctx context.Context) error {
-	_, err := s.db.ExecContext(ctx, "DELETE FROM export_hashes")
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	err := s.withReplayableRetry(ctx, func() error {
+		_, err := s.dbOrTx().ExecContext(ctx, "DELETE FROM export_hashes")
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to clear export hashes: %w", err)
 	}
@@ -105,6 +132,9 @@ func (s *MariaDBStore) GetJSONLFileHash(ctx context.Context) (string, error) {
 
 // SetJSONLFileHash stores the JSONL file hash
 func (s *MariaDBStore) SetJSONLFileHash(ctx context.Context, fileHash string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	return s.SetMetadata(ctx, "jsonl_file_hash", fileHash)
 }
 
@@ -3,6 +3,7 @@ package mariadb
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,7 +12,10 @@ import (
 
 // AddComment adds a comment event to an issue
 func (s *MariaDBStore) AddComment(ctx context.Context, issueID, actor, comment string) error {
-	_, err := s.db.ExecContext(ctx, `
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	_, err := s.dbOrTx().ExecContext(ctx, `
 		INSERT INTO events (issue_id, event_type, actor, comment)
 		VALUES (?, ?, ?, ?)
 	`, issueID, types.EventCommented, actor, comment)
@@ -23,6 +27,9 @@ func (s *MariaDBStore) AddComment(ctx context.Context, issueID, actor, comment s
 
 // GetEvents retrieves events for an issue
 func (s *MariaDBStore) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 	query := `
 		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at
 		FROM events
@@ -35,7 +42,7 @@ func (s *MariaDBStore) GetEvents(ctx context.Context, issueID string, limit int)
 		query += fmt.Sprintf(" LIMIT %d", limit)
 	}
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.queryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events: %w", err)
 	}
@@ -65,7 +72,10 @@ func (s *MariaDBStore) GetEvents(ctx context.Context, issueID string, limit int)
 
 // GetAllEventsSince returns all events with ID greater than sinceID, ordered by ID ascending.
 func (s *MariaDBStore) GetAllEventsSince(ctx context.Context, sinceID int64) ([]*types.Event, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
 		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at
 		FROM events
 		WHERE id > ?
@@ -100,15 +110,21 @@ func (s *MariaDBStore) GetAllEventsSince(ctx context.Context, sinceID int64) ([]
 
 // AddIssueComment adds a comment to an issue (structured comment)
 func (s *MariaDBStore) AddIssueComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
 	return s.ImportIssueComment(ctx, issueID, author, text, time.Now().UTC())
 }
 
 // ImportIssueComment adds a comment during import, preserving the original timestamp.
 // This prevents comment timestamp drift across JSONL sync cycles.
 func (s *MariaDBStore) ImportIssueComment(ctx context.Context, issueID, author, text string, createdAt time.Time) (*types.Comment, error) {
+	if err := s.checkWritable(); err != nil {
+		return nil, err
+	}
 	// Verify issue exists
 	var exists bool
-	if err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM issues WHERE id = ?)`, issueID).Scan(&exists); err != nil {
+	if err := s.dbOrTx().QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM issues WHERE id = ?)`, issueID).Scan(&exists); err != nil {
 		return nil, fmt.Errorf("failed to check issue existence: %w", err)
 	}
 	if !exists {
@@ -116,7 +132,7 @@ func (s *MariaDBStore) ImportIssueComment(ctx context.Context, issueID, author,
 	}
 
 	createdAt = createdAt.UTC()
-	result, err := s.db.ExecContext(ctx, `
+	result, err := s.dbOrTx().ExecContext(ctx, `
 		INSERT INTO comments (issue_id, author, text, created_at)
 		VALUES (?, ?, ?, ?)
 	`, issueID, author, text, createdAt)
@@ -130,7 +146,7 @@ func (s *MariaDBStore) ImportIssueComment(ctx context.Context, issueID, author,
 	}
 
 	// Mark issue dirty for incremental JSONL export
-	if _, err := s.db.ExecContext(ctx, `
+	if _, err := s.dbOrTx().ExecContext(ctx, `
 		INSERT INTO dirty_issues (issue_id, marked_at)
 		VALUES (?, ?)
 		ON DUPLICATE KEY UPDATE marked_at = VALUES(marked_at)
@@ -147,13 +163,41 @@ func (s *MariaDBStore) ImportIssueComment(ctx context.Context, issueID, author,
 	}, nil
 }
 
+// DeleteComment deletes the structured comment with the given ID and marks
+// its issue dirty for export. It returns an error if there is no such comment.
+func (s *MariaDBStore) DeleteComment(ctx context.Context, commentID int64, actor string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		var issueID string
+		err := tx.QueryRowContext(ctx, `SELECT issue_id FROM comments WHERE id = ? FOR UPDATE`, commentID).Scan(&issueID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("comment %d not found", commentID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get comment: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM comments WHERE id = ?`, commentID); err != nil {
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+		if err := markDirty(ctx, tx, issueID); err != nil {
+			return fmt.Errorf("failed to mark issue dirty: %w", err)
+		}
+		return nil
+	})
+}
+
 // GetIssueComments retrieves all comments for an issue
 func (s *MariaDBStore) GetIssueComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
 		SELECT id, issue_id, author, text, created_at
 		FROM comments
 		WHERE issue_id = ?
-		ORDER BY created_at ASC
+		ORDER BY created_at ASC, id ASC
 	`, issueID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comments: %w", err)
@@ -173,6 +217,9 @@ func (s *MariaDBStore) GetIssueComments(ctx context.Context, issueID string) ([]
 
 // GetCommentsForIssues retrieves comments for multiple issues
 func (s *MariaDBStore) GetCommentsForIssues(ctx context.Context, issueIDs []string) (map[string][]*types.Comment, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 	if len(issueIDs) == 0 {
 		return make(map[string][]*types.Comment), nil
 	}
@@ -189,10 +236,10 @@ func (s *MariaDBStore) GetCommentsForIssues(ctx context.Context, issueIDs []stri
 		SELECT id, issue_id, author, text, created_at
 		FROM comments
 		WHERE issue_id IN (%s)
-		ORDER BY issue_id, created_at ASC
+		ORDER BY issue_id, created_at ASC, id ASC
 	`, joinStrings(placeholders, ","))
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.queryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comments: %w", err)
 	}
@@ -211,6 +258,9 @@ func (s *MariaDBStore) GetCommentsForIssues(ctx context.Context, issueIDs []stri
 
 // GetCommentCounts returns the number of comments for each issue in a single batch query.
 func (s *MariaDBStore) GetCommentCounts(ctx context.Context, issueIDs []string) (map[string]int, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 	if len(issueIDs) == 0 {
 		return make(map[string]int), nil
 	}
@@ -230,7 +280,7 @@ func (s *MariaDBStore) GetCommentCounts(ctx context.Context, issueIDs []string)
 		GROUP BY issue_id
 	`, joinStrings(placeholders, ","))
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.queryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comment counts: %w", err)
 	}
@@ -0,0 +1,81 @@
+package mariadb
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIssueComments(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	issues := newBatchIssues(2, "Discussed issue")
+	if err := store.CreateIssuesBatch(ctx, issues, "tester"); err != nil {
+		t.Fatalf("CreateIssuesBatch failed: %v", err)
+	}
+	id := issues[0].ID
+
+	// Comments in the same second keep the order they were added in
+	base := time.Now().UTC().Truncate(time.Second)
+	var ids []int64
+	for i, text := range []string{"first", "second", "third"} {
+		c, err := store.ImportIssueComment(ctx, id, "alice", text, base.Add(time.Duration(i/2)*time.Second))
+		if err != nil {
+			t.Fatalf("ImportIssueComment(%s) failed: %v", text, err)
+		}
+		ids = append(ids, c.ID)
+	}
+	texts := func() string {
+		t.Helper()
+		comments, err := store.GetIssueComments(ctx, id)
+		if err != nil {
+			t.Fatalf("GetIssueComments failed: %v", err)
+		}
+		var out []string
+		for _, c := range comments {
+			out = append(out, c.Text)
+		}
+		return strings.Join(out, ",")
+	}
+	if got := texts(); got != "first,second,third" {
+		t.Errorf("comments = %s, want first,second,third", got)
+	}
+
+	if err := store.ClearDirtyIssuesByID(ctx, []string{id}); err != nil {
+		t.Fatalf("ClearDirtyIssuesByID failed: %v", err)
+	}
+	if err := store.DeleteComment(ctx, ids[1], "tester"); err != nil {
+		t.Fatalf("DeleteComment failed: %v", err)
+	}
+	if got := texts(); got != "first,third" {
+		t.Errorf("comments after delete = %s, want first,third", got)
+	}
+	if dirty, err := store.GetDirtyIssues(ctx); err != nil || !strings.Contains(strings.Join(dirty, ","), id) {
+		t.Errorf("GetDirtyIssues = %v, %v; want %s marked after DeleteComment", dirty, err, id)
+	}
+	if err := store.DeleteComment(ctx, ids[1], "tester"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("DeleteComment(deleted) = %v, want not found", err)
+	}
+
+	// Deleting the issue deletes its comments
+	if _, err := store.AddIssueComment(ctx, issues[1].ID, "bob", "keep"); err != nil {
+		t.Fatalf("AddIssueComment failed: %v", err)
+	}
+	if err := store.DeleteIssue(ctx, id); err != nil {
+		t.Fatalf("DeleteIssue failed: %v", err)
+	}
+	var remaining int
+	if err := store.UnderlyingDB().QueryRowContext(ctx, "SELECT COUNT(*) FROM comments WHERE issue_id = ?", id).Scan(&remaining); err != nil {
+		t.Fatalf("counting comments failed: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("%d comments left after DeleteIssue, want 0", remaining)
+	}
+	if comments, err := store.GetIssueComments(ctx, issues[1].ID); err != nil || len(comments) != 1 {
+		t.Errorf("other issue's comments = %v, %v; want 1", comments, err)
+	}
+}
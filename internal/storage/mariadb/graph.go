@@ -0,0 +1,152 @@
+package mariadb
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// dotStatusColors are the fill colors of DOT nodes by issue status. Other
+// statuses, including custom ones, are filled white.
+var dotStatusColors = map[types.Status]string{
+	types.StatusOpen:       "white",
+	types.StatusInProgress: "lightyellow",
+	types.StatusBlocked:    "lightcoral",
+	types.StatusDeferred:   "lightblue",
+	types.StatusClosed:     "lightgray",
+}
+
+// dotEdge is a dependency drawn by ExportDependencyGraphDOT.
+type dotEdge struct {
+	from, to string
+	depType  types.DependencyType
+}
+
+// ExportDependencyGraphDOT writes the dependency graph to w in Graphviz DOT
+// format, for rendering with dot -Tpng. With roots, the graph holds the
+// roots and the issues reachable from them by following dependencies;
+// without, it holds every issue on either side of a dependency. Nodes are
+// labeled with the issue's ID, title, and status and filled by status
+// (closed issues are also grayed out), and each dependency is an edge from
+// the issue to the issue it depends on, labeled with its type. Targets that
+// are not in this database, such as external references, are drawn dashed
+// with only their ID.
+func (s *MariaDBStore) ExportDependencyGraphDOT(ctx context.Context, w io.Writer, roots []string) error {
+	if s.IsClosed() {
+		return ErrStoreClosed
+	}
+
+	rows, err := s.queryContext(ctx, `
+		SELECT issue_id, depends_on_id, type
+		FROM dependencies
+		ORDER BY issue_id, depends_on_id, type
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to load dependency graph: %w", err)
+	}
+	outgoing := make(map[string][]dotEdge)
+	var all []dotEdge
+	for rows.Next() {
+		var e dotEdge
+		if err := rows.Scan(&e.from, &e.to, &e.depType); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan dependency: %w", err)
+		}
+		outgoing[e.from] = append(outgoing[e.from], e)
+		all = append(all, e)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to load dependency graph: %w", err)
+	}
+
+	// Collect the nodes, and the edges between them, in a stable order
+	inGraph := make(map[string]bool)
+	var nodes []string
+	var edges []dotEdge
+	add := func(id string) {
+		if !inGraph[id] {
+			inGraph[id] = true
+			nodes = append(nodes, id)
+		}
+	}
+	if len(roots) == 0 {
+		for _, e := range all {
+			add(e.from)
+			add(e.to)
+		}
+		edges = all
+	} else {
+		queue := make([]string, 0, len(roots))
+		for _, id := range roots {
+			if !inGraph[id] {
+				add(id)
+				queue = append(queue, id)
+			}
+		}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, e := range outgoing[id] {
+				edges = append(edges, e)
+				if !inGraph[e.to] {
+					add(e.to)
+					queue = append(queue, e.to)
+				}
+			}
+		}
+	}
+	sort.Strings(nodes)
+
+	issues, err := s.GetIssuesByIDs(ctx, nodes)
+	if err != nil {
+		return fmt.Errorf("failed to load graph issues: %w", err)
+	}
+	byID := make(map[string]*types.Issue, len(issues))
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph dependencies {")
+	fmt.Fprintln(bw, "  rankdir=TB;")
+	fmt.Fprintln(bw, "  node [shape=box, style=\"rounded,filled\", fillcolor=white];")
+	for _, id := range nodes {
+		issue := byID[id]
+		if issue == nil {
+			fmt.Fprintf(bw, "  %s [label=%s, style=\"rounded,dashed\"];\n", dotQuote(id), dotQuote(id))
+			continue
+		}
+		fill, font := dotStatusColors[issue.Status], "black"
+		if fill == "" {
+			fill = "white"
+		}
+		if issue.Status == types.StatusClosed {
+			font = "dimgray"
+		}
+		label := fmt.Sprintf("%s\n%s\n(%s)", issue.ID, issue.Title, issue.Status)
+		fmt.Fprintf(bw, "  %s [label=%s, fillcolor=%s, fontcolor=%s];\n", dotQuote(id), dotQuote(label), fill, font)
+	}
+	for _, e := range edges {
+		fmt.Fprintf(bw, "  %s -> %s [label=%s];\n", dotQuote(e.from), dotQuote(e.to), dotQuote(string(e.depType)))
+	}
+	fmt.Fprintln(bw, "}")
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write DOT graph: %w", err)
+	}
+	return nil
+}
+
+// dotQuoter escapes the characters that end or break a DOT quoted string.
+var dotQuoter = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", "", "\n", `\n`)
+
+// dotQuote returns s as a DOT quoted string. Unlike %q, it leaves non-ASCII
+// characters as they are, since DOT reads UTF-8 but not Go escapes.
+func dotQuote(s string) string {
+	return `"` + dotQuoter.Replace(s) + `"`
+}
@@ -0,0 +1,100 @@
+package mariadb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestDotQuote(t *testing.T) {
+	tests := map[string]string{
+		"plain":        `"plain"`,
+		`say "hi"`:     `"say \"hi\""`,
+		`back\slash`:   `"back\\slash"`,
+		"two\r\nlines": `"two\nlines"`,
+		"naïve café ✓": `"naïve café ✓"`,
+	}
+	for in, want := range tests {
+		if got := dotQuote(in); got != want {
+			t.Errorf("dotQuote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestExportDependencyGraphDOT(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for _, issue := range []*types.Issue{
+		{ID: "test-a", Title: "Ship it", Status: types.StatusOpen},
+		{ID: "test-b", Title: `Fix "parser"`, Status: types.StatusInProgress},
+		{ID: "test-c", Title: "Done already", Status: types.StatusClosed},
+		{ID: "test-x", Title: "Unrelated", Status: types.StatusBlocked},
+		{ID: "test-y", Title: "Also unrelated", Status: types.StatusOpen},
+	} {
+		issue.Priority, issue.IssueType = 2, types.TypeTask
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", issue.ID, err)
+		}
+	}
+	for _, dep := range []*types.Dependency{
+		{IssueID: "test-a", DependsOnID: "test-b", Type: types.DepBlocks},
+		{IssueID: "test-b", DependsOnID: "test-c", Type: types.DepBlocks},
+		{IssueID: "test-b", DependsOnID: "external:other:test-z", Type: types.DepRelated},
+		{IssueID: "test-x", DependsOnID: "test-y", Type: types.DepBlocks},
+	} {
+		if err := store.AddDependency(ctx, dep, "tester"); err != nil {
+			t.Fatalf("AddDependency(%s -> %s) failed: %v", dep.IssueID, dep.DependsOnID, err)
+		}
+	}
+
+	export := func(roots []string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := store.ExportDependencyGraphDOT(ctx, &buf, roots); err != nil {
+			t.Fatalf("ExportDependencyGraphDOT(%v) failed: %v", roots, err)
+		}
+		return buf.String()
+	}
+
+	dot := export([]string{"test-a"})
+	if !strings.HasPrefix(dot, "digraph dependencies {\n") || !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("output is not a digraph:\n%s", dot)
+	}
+	for _, want := range []string{
+		`"test-a" [label="test-a\nShip it\n(open)", fillcolor=white, fontcolor=black];`,
+		`"test-b" [label="test-b\nFix \"parser\"\n(in_progress)", fillcolor=lightyellow, fontcolor=black];`,
+		`"test-c" [label="test-c\nDone already\n(closed)", fillcolor=lightgray, fontcolor=dimgray];`,
+		`"external:other:test-z" [label="external:other:test-z", style="rounded,dashed"];`,
+		`"test-a" -> "test-b" [label="blocks"];`,
+		`"test-b" -> "test-c" [label="blocks"];`,
+		`"test-b" -> "external:other:test-z" [label="related"];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("graph from test-a lacks %s:\n%s", want, dot)
+		}
+	}
+	if strings.Contains(dot, "test-x") || strings.Contains(dot, "test-y") {
+		t.Errorf("graph from test-a includes unreachable issues:\n%s", dot)
+	}
+
+	// Without roots, the whole graph
+	dot = export(nil)
+	for _, want := range []string{
+		`"test-x" [label="test-x\nUnrelated\n(blocked)", fillcolor=lightcoral, fontcolor=black];`,
+		`"test-x" -> "test-y" [label="blocks"];`,
+		`"test-a" -> "test-b" [label="blocks"];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("whole graph lacks %s:\n%s", want, dot)
+		}
+	}
+	if n := strings.Count(dot, " -> "); n != 4 {
+		t.Errorf("whole graph has %d edges, want 4:\n%s", n, dot)
+	}
+}
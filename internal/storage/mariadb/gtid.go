@@ -0,0 +1,140 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// gtidWaitTimeout bounds how long a replica read waits for the replica to
+// apply the store's writes before it is served by the primary instead.
+const gtidWaitTimeout = time.Second
+
+// gtidTracker holds the primary's GTID position as of the store's latest
+// write, for Config.ReadYourWrites.
+type gtidTracker struct {
+	mu  sync.Mutex
+	pos string
+}
+
+// load returns the tracked position; empty until the first write.
+func (g *gtidTracker) load() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.pos
+}
+
+// merge advances the tracked position to include pos. Positions captured by
+// concurrent writes may arrive out of order, so each replication domain
+// keeps its highest sequence number.
+func (g *gtidTracker) merge(pos string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pos = mergeGTIDPos(g.pos, pos)
+}
+
+// mergeGTIDPos combines two MariaDB GTID positions (comma-separated
+// domain-server-sequence triples), keeping the highest sequence number seen
+// in each domain. Malformed entries are ignored.
+func mergeGTIDPos(a, b string) string {
+	type gtid struct {
+		server string
+		seq    uint64
+	}
+	latest := make(map[uint64]gtid)
+	for _, pos := range []string{a, b} {
+		for _, entry := range strings.Split(pos, ",") {
+			parts := strings.Split(strings.TrimSpace(entry), "-")
+			if len(parts) != 3 {
+				continue
+			}
+			domain, err := strconv.ParseUint(parts[0], 10, 32)
+			if err != nil {
+				continue
+			}
+			seq, err := strconv.ParseUint(parts[2], 10, 64)
+			if err != nil {
+				continue
+			}
+			if cur, ok := latest[domain]; !ok || seq > cur.seq {
+				latest[domain] = gtid{server: parts[1], seq: seq}
+			}
+		}
+	}
+
+	domains := make([]uint64, 0, len(latest))
+	for domain := range latest {
+		domains = append(domains, domain)
+	}
+	slices.Sort(domains)
+	entries := make([]string, len(domains))
+	for i, domain := range domains {
+		g := latest[domain]
+		entries[i] = fmt.Sprintf("%d-%s-%d", domain, g.server, g.seq)
+	}
+	return strings.Join(entries, ",")
+}
+
+// gtidPos returns the position replica reads must wait for; empty when
+// ReadYourWrites is off or nothing has been written yet.
+func (s *MariaDBStore) gtidPos() string {
+	if s.gtid == nil {
+		return ""
+	}
+	return s.gtid.load()
+}
+
+// recordWrite captures the primary's GTID position after a successful write,
+// so later replica reads wait for it. If it cannot be read, reads go to the
+// primary for replicaRetryInterval instead.
+func (s *MariaDBStore) recordWrite(ctx context.Context) {
+	if s.gtid == nil || s.tx != nil {
+		return
+	}
+	var pos string
+	if err := s.primaryDB().QueryRowContext(ctx, "SELECT @@gtid_binlog_pos").Scan(&pos); err != nil {
+		s.log().Warn("failed to read MariaDB GTID position; reading from the primary",
+			"database", s.dbName, "error", err)
+		s.replicaDownUntil.Store(time.Now().Add(replicaRetryInterval).UnixNano())
+		return
+	}
+	s.gtid.merge(pos)
+}
+
+// queryReplicaAtGTID runs a read-only query on a replica connection once it
+// has applied pos. If the replica is still behind after gtidWaitTimeout, the
+// query is served by the primary.
+func (s *MariaDBStore) queryReplicaAtGTID(ctx context.Context, primary, replica *sql.DB, pos string, query string, args ...interface{}) (*sql.Rows, error) {
+	// MASTER_GTID_WAIT applies to one replica, so the query must use the
+	// same connection
+	conn, err := replica.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result sql.NullInt64
+	err = conn.QueryRowContext(ctx, "SELECT MASTER_GTID_WAIT(?, ?)", pos, gtidWaitTimeout.Seconds()).Scan(&result)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if !result.Valid || result.Int64 != 0 {
+		_ = conn.Close()
+		s.log().Debug("MariaDB replica behind the last write; reading from the primary",
+			"database", s.dbName, "gtid", pos)
+		return primary.QueryContext(ctx, query, args...)
+	}
+
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	// Close blocks until rows is closed, then returns the connection to the pool
+	go func() { _ = conn.Close() }()
+	return rows, nil
+}
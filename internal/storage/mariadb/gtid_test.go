@@ -0,0 +1,86 @@
+package mariadb
+
+import (
+	"os"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestMergeGTIDPos(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "", ""},
+		{"", "0-1-100", "0-1-100"},
+		{"0-1-100", "0-1-99", "0-1-100"},
+		{"0-1-100", "0-2-101", "0-2-101"},
+		{"1-1-5,0-1-100", "0-1-101", "0-1-101,1-1-5"},
+		{"0-1-100", "bogus, 2-1-x", "0-1-100"},
+	}
+	for _, tt := range tests {
+		if got := mergeGTIDPos(tt.a, tt.b); got != tt.want {
+			t.Errorf("mergeGTIDPos(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestReadYourWritesWithoutGTID(t *testing.T) {
+	// The "replica" is the primary itself, which may not report GTIDs
+	store, cleanup := setupTestStoreWithConfig(t, &Config{ReplicaHosts: []string{"127.0.0.1"}, ReadYourWrites: true})
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	issue := &types.Issue{Title: "Read your writes", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if store.gtidPos() == "" && store.ReadDB() != store.UnderlyingDB() {
+		t.Error("reads should go to the primary when the GTID position is unknown")
+	}
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != issue.ID {
+		t.Errorf("GetReadyWork = %v, want [%s]", ready, issue.ID)
+	}
+}
+
+// TestReadYourWritesReplica needs a GTID replica of the local server,
+// given as BEADS_MARIADB_REPLICA_HOST (host or host:port).
+func TestReadYourWritesReplica(t *testing.T) {
+	host := os.Getenv("BEADS_MARIADB_REPLICA_HOST")
+	if host == "" {
+		t.Skip("BEADS_MARIADB_REPLICA_HOST not set")
+	}
+	store, cleanup := setupTestStoreWithConfig(t, &Config{ReplicaHosts: []string{host}, ReadYourWrites: true})
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for i := 0; i < 20; i++ {
+		issue := &types.Issue{Title: "Replicated", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if store.gtidPos() == "" {
+			t.Fatal("no GTID position recorded after a write")
+		}
+		if store.ReadDB() == store.UnderlyingDB() {
+			t.Fatal("ReadDB should be the replica pool")
+		}
+		ready, err := store.GetReadyWork(ctx, types.WorkFilter{})
+		if err != nil {
+			t.Fatalf("GetReadyWork failed: %v", err)
+		}
+		if len(ready) != i+1 {
+			t.Fatalf("GetReadyWork after write %d returned %d issues", i+1, len(ready))
+		}
+	}
+}
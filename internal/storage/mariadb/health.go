@@ -0,0 +1,99 @@
+package mariadb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// HealthCheck verifies the server is reachable by running SELECT 1.
+// Transient connection errors are retried until ctx expires.
+// Returns ErrStoreClosed if the store has been closed.
+func (s *MariaDBStore) HealthCheck(ctx context.Context) error {
+	err := s.withRetry(ctx, func() error {
+		var one int
+		return s.primaryDB().QueryRowContext(ctx, "SELECT 1").Scan(&one)
+	})
+	if err != nil {
+		return fmt.Errorf("MariaDB health check failed for database %s: %w", s.dbName, err)
+	}
+	return nil
+}
+
+// Reconnect replaces the connection pool with a fresh one opened from the
+// store's connection string, with credentials from Config.CredentialProvider
+// if one is set. Use it when HealthCheck keeps failing after a
+// prolonged outage has left every pooled connection dead. The new pool is
+// pinged before it is swapped in, so on error the old pool stays in place.
+// Concurrent callers are serialized. Statements and transactions already
+// running on the old pool finish there; it is closed in the background once
+// they have. Returns ErrStoreClosed if the store has been closed.
+func (s *MariaDBStore) Reconnect(ctx context.Context) error {
+	if s.IsClosed() {
+		return ErrStoreClosed
+	}
+	if s.tx != nil {
+		return fmt.Errorf("cannot reconnect inside a WithTx transaction")
+	}
+
+	s.reconnectMu.Lock()
+	defer s.reconnectMu.Unlock()
+
+	connStr, err := s.refreshedConnStr(ctx)
+	if err != nil {
+		return err
+	}
+	db, err := openDB(connStr, s.conn)
+	if err != nil {
+		return fmt.Errorf("failed to reopen MariaDB connection (%s): %w", redactDSN(connStr), err)
+	}
+	s.pool.apply(db)
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		s.log().Error("MariaDB reconnect failed", "database", s.dbName, "error", err)
+		return fmt.Errorf("failed to reconnect to MariaDB database %s: %w", s.dbName, err)
+	}
+
+	s.mu.Lock()
+	if s.closed.Load() {
+		// Close ran while we were connecting
+		s.mu.Unlock()
+		_ = db.Close()
+		return ErrStoreClosed
+	}
+	old := s.primary
+	s.primary = &trackedPool{db: db}
+	s.connStr = connStr
+	s.mu.Unlock()
+
+	if old != nil {
+		go func() {
+			old.busy.Wait()
+			_ = old.db.Close()
+		}()
+	}
+	s.log().Info("MariaDB connection pool reopened", "database", s.dbName)
+	return nil
+}
+
+// refreshedConnStr returns the connection string for Reconnect: connStr with
+// the user and password replaced by the credential provider's, if any.
+func (s *MariaDBStore) refreshedConnStr(ctx context.Context) (string, error) {
+	if s.credentialProvider == nil {
+		return s.connStr, nil
+	}
+	user, password, err := s.credentialProvider(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh MariaDB credentials: %w", err)
+	}
+	mc, err := mysql.ParseDSN(s.connStr)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse MariaDB connection string (%s): %w", redactDSN(s.connStr), err)
+	}
+	if user != "" {
+		mc.User = user
+	}
+	mc.Passwd = password
+	return mc.FormatDSN(), nil
+}
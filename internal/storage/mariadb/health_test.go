@@ -0,0 +1,292 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestHealthCheck(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if err := store.HealthCheck(ctx); err != nil {
+		t.Errorf("HealthCheck on healthy store failed: %v", err)
+	}
+}
+
+func TestHealthCheckClosed(t *testing.T) {
+	store := &MariaDBStore{storeState: newStoreState(nil, "")}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if err := store.HealthCheck(ctx); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("HealthCheck on closed store = %v, want ErrStoreClosed", err)
+	}
+}
+
+func TestHealthCheckUnreachable(t *testing.T) {
+	// Nothing listens on port 1, so the dial is refused (not retried)
+	db, err := sql.Open("mysql", "root@tcp(127.0.0.1:1)/beads")
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	store := &MariaDBStore{storeState: newStoreState(db, ""), dbName: "beads"}
+	defer store.Close()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	err = store.HealthCheck(ctx)
+	if err == nil {
+		t.Fatal("expected error for unreachable server")
+	}
+	if errors.Is(err, ErrStoreClosed) {
+		t.Errorf("unreachable server should not report ErrStoreClosed: %v", err)
+	}
+}
+
+func TestReconnectRestoresDeadPool(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	// Simulate a pool whose connections are all gone
+	if err := store.UnderlyingDB().Close(); err != nil {
+		t.Fatalf("failed to close pool: %v", err)
+	}
+	if err := store.HealthCheck(ctx); err == nil {
+		t.Fatal("expected HealthCheck to fail on a dead pool")
+	}
+
+	if err := store.Reconnect(ctx); err != nil {
+		t.Fatalf("Reconnect failed: %v", err)
+	}
+	if err := store.HealthCheck(ctx); err != nil {
+		t.Errorf("HealthCheck after Reconnect failed: %v", err)
+	}
+	if prefix, err := store.GetConfig(ctx, "issue_prefix"); err != nil || prefix != "test" {
+		t.Errorf("GetConfig after Reconnect = %q, %v; want test", prefix, err)
+	}
+	if got := store.Stats().MaxOpenConnections; got != DefaultMaxOpenConns {
+		t.Errorf("MaxOpenConnections after Reconnect = %d, want %d", got, DefaultMaxOpenConns)
+	}
+}
+
+func TestReconnectWaitsForOldPool(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	// A transaction keeps using the old pool across the reconnect
+	old := store.UnderlyingDB()
+	inTx, release := make(chan struct{}), make(chan struct{})
+	txDone := make(chan error, 1)
+	go func() {
+		txDone <- store.WithTx(ctx, func(tx *MariaDBStore) error {
+			close(inTx)
+			<-release
+			return tx.SetConfig(ctx, "reconnect.marker", "kept")
+		})
+	}()
+	<-inTx
+
+	// Reads race with the swap; run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, err := store.GetConfig(ctx, "issue_prefix"); err != nil {
+					t.Errorf("GetConfig during Reconnect failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	if err := store.Reconnect(ctx); err != nil {
+		t.Fatalf("Reconnect failed: %v", err)
+	}
+	wg.Wait()
+	if store.UnderlyingDB() == old {
+		t.Fatal("Reconnect kept the old pool")
+	}
+	if err := old.PingContext(ctx); err != nil {
+		t.Errorf("old pool closed while a transaction still used it: %v", err)
+	}
+
+	close(release)
+	if err := <-txDone; err != nil {
+		t.Fatalf("transaction on the old pool failed: %v", err)
+	}
+	if got, err := store.GetConfig(ctx, "reconnect.marker"); err != nil || got != "kept" {
+		t.Errorf("GetConfig = %q, %v; want the transaction's write", got, err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for old.PingContext(ctx) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("old pool still open after its last transaction finished")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReconnectConcurrent(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- store.Reconnect(ctx)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Reconnect failed: %v", err)
+		}
+	}
+	if err := store.HealthCheck(ctx); err != nil {
+		t.Errorf("HealthCheck after concurrent Reconnect failed: %v", err)
+	}
+}
+
+func TestReconnectFailureKeepsPool(t *testing.T) {
+	// Nothing listens on port 1, so the new pool can't be pinged
+	db, err := sql.Open("mysql", "root@tcp(127.0.0.1:1)/beads")
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	store := &MariaDBStore{storeState: newStoreState(db, "root@tcp(127.0.0.1:1)/beads"), dbName: "beads"}
+	defer store.Close()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if err := store.Reconnect(ctx); err == nil {
+		t.Fatal("expected Reconnect to fail for unreachable server")
+	}
+	if store.UnderlyingDB() != db {
+		t.Error("failed Reconnect should keep the existing pool")
+	}
+}
+
+// rotatingCredentials returns a provider whose password changes on every call.
+func rotatingCredentials(calls *int) func(context.Context) (string, string, error) {
+	return func(context.Context) (string, string, error) {
+		*calls++
+		return "iam_user", fmt.Sprintf("token-%d", *calls), nil
+	}
+}
+
+func TestRefreshedConnStrRotatesCredentials(t *testing.T) {
+	connStr, err := buildDSN(&Config{Host: "db.example.com", Port: 3306, User: "root", Password: "static", TLSMode: TLSModeRequired}, "beads")
+	if err != nil {
+		t.Fatalf("buildDSN failed: %v", err)
+	}
+	calls := 0
+	store := &MariaDBStore{storeState: newStoreState(nil, connStr), dbName: "beads", credentialProvider: rotatingCredentials(&calls)}
+
+	for want := 1; want <= 2; want++ {
+		dsn, err := store.refreshedConnStr(context.Background())
+		if err != nil {
+			t.Fatalf("refreshedConnStr failed: %v", err)
+		}
+		mc, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			t.Fatalf("ParseDSN(%q) failed: %v", dsn, err)
+		}
+		if mc.User != "iam_user" || mc.Passwd != fmt.Sprintf("token-%d", want) {
+			t.Errorf("credentials = %q/%q, want iam_user/token-%d", mc.User, mc.Passwd, want)
+		}
+		// Everything else is kept
+		if mc.Addr != "db.example.com:3306" || mc.DBName != "beads" || mc.TLSConfig != TLSModeRequired || !mc.ParseTime {
+			t.Errorf("refreshed DSN lost settings: %q", redactDSN(dsn))
+		}
+	}
+}
+
+func TestRefreshedConnStrStaticPassword(t *testing.T) {
+	// Without a provider the static connection string is reused
+	store := &MariaDBStore{storeState: newStoreState(nil, "root:static@tcp(127.0.0.1:3306)/beads?parseTime=true")}
+	dsn, err := store.refreshedConnStr(context.Background())
+	if err != nil || dsn != store.connStr {
+		t.Errorf("refreshedConnStr = %q, %v; want the static connection string", dsn, err)
+	}
+}
+
+func TestReconnectProviderError(t *testing.T) {
+	errExpired := errors.New("token service unavailable")
+	store := &MariaDBStore{
+		storeState: newStoreState(nil, "root@tcp(127.0.0.1:1)/beads"),
+		dbName:     "beads",
+		credentialProvider: func(context.Context) (string, string, error) {
+			return "", "", errExpired
+		},
+	}
+	if err := store.Reconnect(context.Background()); !errors.Is(err, errExpired) {
+		t.Errorf("Reconnect = %v, want the provider error", err)
+	}
+}
+
+func TestNewAndReconnectUseCredentialProvider(t *testing.T) {
+	// Only runs when a server is available
+	existing, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	calls := 0
+	store, err := New(ctx, &Config{
+		Database: existing.Path(),
+		CredentialProvider: func(context.Context) (string, string, error) {
+			calls++
+			return "root", os.Getenv("BEADS_MARIADB_PASSWORD"), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer store.Close()
+	if calls != 1 {
+		t.Fatalf("provider called %d times by New, want 1", calls)
+	}
+
+	if err := store.Reconnect(ctx); err != nil {
+		t.Fatalf("Reconnect failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("provider called %d times after Reconnect, want 2", calls)
+	}
+	if err := store.HealthCheck(ctx); err != nil {
+		t.Errorf("HealthCheck after Reconnect failed: %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package mariadb
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// errDuplicateEntry is the MariaDB error number for a duplicate key (ER_DUP_ENTRY).
+const errDuplicateEntry = 1062
+
+// maxIDAttempts bounds the candidates generateIssueID asks a generator for,
+// and how often an issue whose generated ID was taken by a concurrent create
+// gets a new one.
+const maxIDAttempts = 100
+
+// IDGenerator generates the IDs of issues created without one (see
+// Config.IDGenerator). The store checks each candidate against the existing
+// issues and asks for another while it is taken, and again if a concurrent
+// create inserts the same ID first, so candidates need not be unique, but
+// each attempt should make a different one likely.
+type IDGenerator interface {
+	NextID(ctx context.Context, req IDRequest) (string, error)
+}
+
+// IDRequest describes the issue an IDGenerator is asked to name.
+type IDRequest struct {
+	Tx      *sql.Tx      // The creating transaction, for generators that keep state in the database
+	Prefix  string       // The issue prefix, including any IDPrefix or PrefixOverride
+	Issue   *types.Issue // The issue being created
+	Actor   string       // Who creates the issue
+	Attempt int          // Number of candidates already found taken, from 0
+}
+
+// HashIDs is the default IDGenerator: prefix-hash, where hash is the base36
+// hash of the issue's title, description, creator, and creation time. The
+// hash grows from 4 to 8 characters as the prefix gets more issues (see
+// GetAdaptiveIDLengthTx), and lengthens further on collisions.
+type HashIDs struct{}
+
+// hashNonces is the number of nonces HashIDs tries at each length.
+const hashNonces = 10
+
+// maxHashIDLength is the longest hash HashIDs generates.
+const maxHashIDLength = 8
+
+func (HashIDs) NextID(ctx context.Context, req IDRequest) (string, error) {
+	baseLength, err := GetAdaptiveIDLengthTx(ctx, req.Tx, req.Prefix)
+	if err != nil {
+		// Fallback to 6 on error
+		baseLength = 6
+	}
+	baseLength = min(baseLength, maxHashIDLength)
+
+	length := baseLength + req.Attempt/hashNonces
+	if length > maxHashIDLength {
+		return "", fmt.Errorf("failed to generate unique ID after trying lengths %d-%d with %d nonces each", baseLength, maxHashIDLength, hashNonces)
+	}
+	issue := req.Issue
+	return generateHashID(req.Prefix, issue.Title, issue.Description, req.Actor, issue.CreatedAt, length, req.Attempt%hashNonces), nil
+}
+
+// SequentialIDs numbers issues prefix-1, prefix-2, ... with a counter per
+// prefix, kept in the metadata table under "id_sequence.<prefix>". The
+// counter row stays locked until the creating transaction ends, so
+// concurrent creates with the same prefix take turns, and a rolled-back
+// create gives its number back. Numbers already taken, for example by
+// imported issues, are skipped.
+type SequentialIDs struct{}
+
+func (SequentialIDs) NextID(ctx context.Context, req IDRequest) (string, error) {
+	key := "id_sequence." + req.Prefix
+	_, err := req.Tx.ExecContext(ctx, "INSERT INTO metadata (`key`, value) VALUES (?, '1') ON DUPLICATE KEY UPDATE value = CAST(value AS UNSIGNED) + 1", key)
+	if err != nil {
+		return "", fmt.Errorf("failed to advance ID sequence: %w", err)
+	}
+	var n uint64
+	if err := req.Tx.QueryRowContext(ctx, "SELECT CAST(value AS UNSIGNED) FROM metadata WHERE `key` = ?", key).Scan(&n); err != nil {
+		return "", fmt.Errorf("failed to read ID sequence: %w", err)
+	}
+	return req.Prefix + "-" + strconv.FormatUint(n, 10), nil
+}
+
+// ULIDs generates prefix-ulid, where ulid is a ULID in lowercase: 48 bits of
+// millisecond timestamp and 80 random bits, in 26 Crockford base32
+// characters. IDs from one machine sort by creation time, and need no
+// coordination between writers.
+type ULIDs struct{}
+
+func (ULIDs) NextID(_ context.Context, req IDRequest) (string, error) {
+	id, err := newULID(time.Now())
+	if err != nil {
+		return "", err
+	}
+	return req.Prefix + "-" + id, nil
+}
+
+// crockfordAlphabet is Crockford's base32 alphabet, in lowercase.
+const crockfordAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// newULID returns the ULID for t with fresh random bits.
+func newULID(t time.Time) (string, error) {
+	var b [16]byte
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", fmt.Errorf("failed to read random bits: %w", err)
+	}
+
+	// 128 bits in 26 characters of 5 bits: the first carries only 3
+	var out [26]byte
+	var acc uint32
+	bits := 2 // Pad the front, so the last character ends on the last bit
+	pos := 0
+	for _, c := range b {
+		acc = acc<<8 | uint32(c)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[pos] = crockfordAlphabet[(acc>>bits)&31]
+			pos++
+		}
+	}
+	return string(out[:]), nil
+}
+
+// idGenerator returns Config.IDGenerator, or HashIDs if none was set.
+func (s *MariaDBStore) idGenerator() IDGenerator {
+	if s.idGen == nil {
+		return HashIDs{}
+	}
+	return s.idGen
+}
+
+// generateIssueID asks gen for IDs for issue until one is free. IDs in
+// reserved are treated as taken; batch creation uses it for IDs not yet
+// inserted.
+func generateIssueID(ctx context.Context, tx *sql.Tx, gen IDGenerator, prefix string, issue *types.Issue, actor string, reserved map[string]bool) (string, error) {
+	for attempt := 0; attempt < maxIDAttempts; attempt++ {
+		candidate, err := gen.NextID(ctx, IDRequest{Tx: tx, Prefix: prefix, Issue: issue, Actor: actor, Attempt: attempt})
+		if err != nil {
+			return "", err
+		}
+		if reserved[candidate] {
+			continue
+		}
+
+		// Check if this ID already exists
+		var count int
+		err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM issues WHERE id = ?`, candidate).Scan(&count)
+		if err != nil {
+			return "", fmt.Errorf("failed to check for ID collision: %w", err)
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate unique ID after %d attempts", maxIDAttempts)
+}
+
+// insertWithGeneratedID gives issue an ID from gen and inserts it with insert.
+// If a concurrent create inserted the same ID first, the failed insert is
+// undone by the server and issue gets another ID; the transaction's snapshot
+// may not show the other issue, so the IDs that failed are remembered. On
+// error, issue.ID is cleared again.
+func insertWithGeneratedID(ctx context.Context, tx *sql.Tx, gen IDGenerator, prefix string, issue *types.Issue, actor string, insert func() error) error {
+	taken := make(map[string]bool)
+	for retries := 0; ; retries++ {
+		id, err := generateIssueID(ctx, tx, gen, prefix, issue, actor, taken)
+		if err != nil {
+			return fmt.Errorf("failed to generate issue ID: %w", err)
+		}
+		issue.ID = id
+		err = insert()
+		if err == nil {
+			return nil
+		}
+		issue.ID = ""
+		if !isDuplicateKeyError(err) || retries == maxIDAttempts {
+			return err
+		}
+		taken[id] = true
+	}
+}
+
+// isDuplicateKeyError reports whether err is a duplicate key error.
+func isDuplicateKeyError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry
+}
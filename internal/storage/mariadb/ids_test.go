@@ -0,0 +1,147 @@
+package mariadb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestNewULID(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-hjkmnp-tv-z]{26}$`)
+	at := time.UnixMilli(1_700_000_000_000)
+	a, err := newULID(at)
+	if err != nil {
+		t.Fatalf("newULID failed: %v", err)
+	}
+	b, err := newULID(at.Add(time.Millisecond))
+	if err != nil {
+		t.Fatalf("newULID failed: %v", err)
+	}
+	if !pattern.MatchString(a) || !pattern.MatchString(b) {
+		t.Fatalf("newULID = %q, %q; want 26 lowercase Crockford base32 characters", a, b)
+	}
+	// The first 10 characters encode the timestamp
+	if a[:10] != "01hf7yat00" {
+		t.Errorf("newULID timestamp = %q, want 01hf7yat00", a[:10])
+	}
+	if a >= b {
+		t.Errorf("newULID(%v) = %q sorts after newULID one millisecond later = %q", at, a, b)
+	}
+	if c, _ := newULID(at); c == a {
+		t.Errorf("newULID returned %q twice for the same time", a)
+	}
+}
+
+// attemptIDs generates prefix-aN for attempt N, so concurrent creates all
+// start with the same candidate.
+type attemptIDs struct{}
+
+func (attemptIDs) NextID(_ context.Context, req IDRequest) (string, error) {
+	return req.Prefix + "-a" + strconv.Itoa(req.Attempt), nil
+}
+
+func TestIDGeneratorsUniqueUnderConcurrency(t *testing.T) {
+	const workers, perWorker = 4, 10
+	tests := []struct {
+		name    string
+		gen     IDGenerator
+		pattern string
+	}{
+		{"default", nil, `^test-[0-9a-z]{4,8}$`},
+		{"hash", HashIDs{}, `^test-[0-9a-z]{4,8}$`},
+		{"sequential", SequentialIDs{}, `^test-[1-9][0-9]*$`},
+		{"ulid", ULIDs{}, `^test-[0-9a-hjkmnp-tv-z]{26}$`},
+		{"duplicate candidates", attemptIDs{}, `^test-a[0-9]+$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, cleanup := setupTestStoreWithConfig(t, &Config{IDGenerator: tt.gen})
+			defer cleanup()
+
+			ctx, cancel := testContext(t)
+			defer cancel()
+
+			var mu sync.Mutex
+			seen := make(map[string]bool)
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < perWorker; i++ {
+						// Same title and time for all, so hash IDs collide too
+						issue := &types.Issue{Title: "Concurrent", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask,
+							CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+						if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+							t.Errorf("CreateIssue failed: %v", err)
+							return
+						}
+						mu.Lock()
+						if seen[issue.ID] {
+							t.Errorf("ID %s generated twice", issue.ID)
+						}
+						seen[issue.ID] = true
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+
+			if len(seen) != workers*perWorker {
+				t.Fatalf("created %d issues, want %d", len(seen), workers*perWorker)
+			}
+			re := regexp.MustCompile(tt.pattern)
+			for id := range seen {
+				if !re.MatchString(id) {
+					t.Errorf("generated ID %q does not match %s", id, tt.pattern)
+				}
+			}
+			if tt.name == "sequential" {
+				for n := 1; n <= workers*perWorker; n++ {
+					if id := fmt.Sprintf("test-%d", n); !seen[id] {
+						t.Errorf("sequential IDs skipped %s", id)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSequentialIDsSkipTakenNumbers(t *testing.T) {
+	store, cleanup := setupTestStoreWithConfig(t, &Config{IDGenerator: SequentialIDs{}})
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	var got []string
+	for _, id := range []string{"", "test-2", "", ""} {
+		issue := &types.Issue{ID: id, Title: "Numbered", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		got = append(got, issue.ID)
+	}
+	want := []string{"test-1", "test-2", "test-3", "test-4"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("created %v, want %v", got, want)
+	}
+
+	// Batches and child prefixes draw from the generator too
+	batch := []*types.Issue{
+		{Title: "Batch 1", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{Title: "Batch 2", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, IDPrefix: "ops"},
+	}
+	if err := store.CreateIssuesBatch(ctx, batch, "tester"); err != nil {
+		t.Fatalf("CreateIssuesBatch failed: %v", err)
+	}
+	if batch[0].ID != "test-5" || batch[1].ID != "test-ops-1" {
+		t.Errorf("batch IDs = %s, %s; want test-5, test-ops-1", batch[0].ID, batch[1].ID)
+	}
+}
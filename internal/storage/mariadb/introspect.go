@@ -0,0 +1,280 @@
+package mariadb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/steveyegge/beads/internal/storage/sqlutil"
+)
+
+// viewNames lists every view created by initSchema.
+var viewNames = []string{"ready_issues", "blocked_issues"}
+
+// ExportSchema returns the DDL of every Beads table and view in the database,
+// as reported by SHOW CREATE TABLE and SHOW CREATE VIEW, concatenated into a
+// script that recreates them. Tables and views that do not exist are listed
+// as comments. Use it to inspect schema drift or attach to bug reports; the
+// output contains no row data.
+func (s *MariaDBStore) ExportSchema(ctx context.Context) (string, error) {
+	if s.IsClosed() {
+		return "", ErrStoreClosed
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "-- Beads schema for MariaDB database %s\n", s.dbName)
+
+	for _, table := range tableNames {
+		var name, ddl string
+		err := s.withReadRetry(ctx, func() error {
+			return s.dbOrTx().QueryRowContext(ctx, "SHOW CREATE TABLE "+quoteIdentifier(table)).Scan(&name, &ddl)
+		})
+		if isNoSuchTable(err) {
+			fmt.Fprintf(&out, "\n-- table %s does not exist\n", s.tablePrefix+table)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to export table %s: %w", table, err)
+		}
+		fmt.Fprintf(&out, "\n%s;\n", ddl)
+	}
+
+	for _, view := range viewNames {
+		var name, ddl, charsetClient, collationConn string
+		err := s.withReadRetry(ctx, func() error {
+			return s.dbOrTx().QueryRowContext(ctx, "SHOW CREATE VIEW "+quoteIdentifier(view)).Scan(&name, &ddl, &charsetClient, &collationConn)
+		})
+		if isNoSuchTable(err) {
+			fmt.Fprintf(&out, "\n-- view %s does not exist\n", s.tablePrefix+view)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to export view %s: %w", view, err)
+		}
+		fmt.Fprintf(&out, "\n%s;\n", ddl)
+	}
+
+	return out.String(), nil
+}
+
+// isNoSuchTable reports whether err is MariaDB's missing table error.
+func isNoSuchTable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == errNoSuchTable
+}
+
+// Kinds of schema object reported in a SchemaDiff.
+const (
+	SchemaObjectTable  = "table"
+	SchemaObjectView   = "view"
+	SchemaObjectColumn = "column"
+	SchemaObjectIndex  = "index"
+)
+
+// SchemaDiff is one difference between the database schema and the schema
+// Beads expects.
+type SchemaDiff struct {
+	Object     string // SchemaObjectTable, SchemaObjectView, SchemaObjectColumn, or SchemaObjectIndex
+	Table      string // Table or view name
+	Name       string // Column or index name; empty for tables and views
+	Unexpected bool   // True if the object exists but Beads does not define it; false if it is missing
+}
+
+func (d SchemaDiff) String() string {
+	state := "missing"
+	if d.Unexpected {
+		state = "unexpected"
+	}
+	if d.Name == "" {
+		return fmt.Sprintf("%s %s %s", state, d.Object, d.Table)
+	}
+	return fmt.Sprintf("%s %s %s.%s", state, d.Object, d.Table, d.Name)
+}
+
+// expectedTable is the columns and indexes Beads defines for a table.
+type expectedTable struct {
+	columns     []string
+	indexes     []string
+	foreignKeys []string // Constraint names, which the server may also give to an index
+}
+
+// expectedSchema is parsed from the schema DDL once, on first use.
+var expectedSchema = sync.OnceValue(func() map[string]*expectedTable {
+	return parseExpectedSchema(schema + ";" + schemaMigrationsTable)
+})
+
+// parseExpectedSchema extracts table, column, and index names from the
+// CREATE TABLE statements in ddl, which must list one column or index per
+// line. Foreign key constraints are kept apart from indexes, since whether the
+// server adds an index for one depends on the other indexes.
+func parseExpectedSchema(ddl string) map[string]*expectedTable {
+	tables := make(map[string]*expectedTable)
+	for _, stmt := range sqlutil.SplitStatements(ddl) {
+		var lines []string
+		for _, line := range strings.Split(stmt, "\n") {
+			line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+			if line != "" && !strings.HasPrefix(line, "--") {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) == 0 || !strings.HasPrefix(strings.ToUpper(lines[0]), "CREATE TABLE") {
+			continue
+		}
+		header := strings.Fields(strings.TrimSuffix(lines[0], "("))
+		table := &expectedTable{}
+		tables[header[len(header)-1]] = table
+
+		for _, line := range lines[1:] {
+			words := strings.Fields(line)
+			switch strings.ToUpper(words[0]) {
+			case ")":
+			case "INDEX", "KEY":
+				table.indexes = append(table.indexes, words[1])
+			case "UNIQUE":
+				table.indexes = append(table.indexes, words[2])
+			case "PRIMARY":
+				table.indexes = append(table.indexes, "PRIMARY")
+			case "CONSTRAINT":
+				table.foreignKeys = append(table.foreignKeys, words[1])
+			case "FOREIGN":
+			default:
+				table.columns = append(table.columns, strings.Trim(words[0], "`"))
+				if strings.Contains(strings.ToUpper(line), "PRIMARY KEY") {
+					table.indexes = append(table.indexes, "PRIMARY")
+				}
+			}
+		}
+	}
+	return tables
+}
+
+// ValidateSchema compares the tables, columns, indexes, and views in the
+// database with the schema Beads expects and returns every missing or
+// unexpected object, without modifying anything. Names in the result include
+// the table prefix. Column types and index
+// definitions are not compared. Unexpected tables are not reported, since
+// other applications may share the database. A nil result means the schema
+// matches.
+func (s *MariaDBStore) ValidateSchema(ctx context.Context) ([]SchemaDiff, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+
+	columns, err := s.schemaNames(ctx, `
+		SELECT table_name, column_name
+		FROM information_schema.columns
+		WHERE table_schema = DATABASE()
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+	indexes, err := s.schemaNames(ctx, `
+		SELECT DISTINCT table_name, index_name
+		FROM information_schema.statistics
+		WHERE table_schema = DATABASE()
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %w", err)
+	}
+	views, err := s.schemaNames(ctx, `
+		SELECT table_name, ''
+		FROM information_schema.tables
+		WHERE table_schema = DATABASE() AND table_type = 'VIEW'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read views: %w", err)
+	}
+
+	var diffs []SchemaDiff
+	expected := expectedSchema()
+	for _, name := range tableNames {
+		want := expected[name]
+		table := s.tablePrefix + name
+		if _, ok := columns[table]; !ok {
+			diffs = append(diffs, SchemaDiff{Object: SchemaObjectTable, Table: table})
+			continue
+		}
+		diffs = append(diffs, diffNames(SchemaObjectColumn, table, want.columns, columns[table])...)
+		diffs = append(diffs, diffNames(SchemaObjectIndex, table, s.prefixIndexNames(want.indexes), indexes[table], s.prefixIndexNames(want.foreignKeys)...)...)
+	}
+	for _, name := range viewNames {
+		if view := s.tablePrefix + name; !views[view][""] {
+			diffs = append(diffs, SchemaDiff{Object: SchemaObjectView, Table: view})
+		}
+	}
+	return diffs, nil
+}
+
+// prefixIndexNames applies the table prefix to index and constraint names.
+// The primary key is always named PRIMARY.
+func (s *MariaDBStore) prefixIndexNames(names []string) []string {
+	if s.tablePrefix == "" {
+		return names
+	}
+	prefixed := make([]string, len(names))
+	for i, name := range names {
+		if name != "PRIMARY" {
+			name = s.tablePrefix + name
+		}
+		prefixed[i] = name
+	}
+	return prefixed
+}
+
+// schemaNames runs query, which selects (table, name) pairs, and groups the
+// names by table.
+func (s *MariaDBStore) schemaNames(ctx context.Context, query string) (map[string]map[string]bool, error) {
+	names := make(map[string]map[string]bool)
+	err := s.withReadRetry(ctx, func() error {
+		clear(names)
+		rows, err := s.dbOrTx().QueryContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var table, name string
+			if err := rows.Scan(&table, &name); err != nil {
+				return err
+			}
+			if names[table] == nil {
+				names[table] = make(map[string]bool)
+			}
+			names[table][name] = true
+		}
+		return rows.Err()
+	})
+	return names, err
+}
+
+// diffNames reports the names in want missing from got as missing, and the
+// names in got that are neither in want nor in ignore as unexpected.
+func diffNames(object, table string, want []string, got map[string]bool, ignore ...string) []SchemaDiff {
+	var diffs []SchemaDiff
+	known := make(map[string]bool, len(want)+len(ignore))
+	for _, name := range want {
+		known[name] = true
+		if !got[name] {
+			diffs = append(diffs, SchemaDiff{Object: object, Table: table, Name: name})
+		}
+	}
+	for _, name := range ignore {
+		known[name] = true
+	}
+	unexpected := make([]string, 0, len(got))
+	for name := range got {
+		if !known[name] {
+			unexpected = append(unexpected, name)
+		}
+	}
+	sort.Strings(unexpected)
+	for _, name := range unexpected {
+		diffs = append(diffs, SchemaDiff{Object: object, Table: table, Name: name, Unexpected: true})
+	}
+	return diffs
+}
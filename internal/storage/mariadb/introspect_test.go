@@ -0,0 +1,119 @@
+package mariadb
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExportSchema(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	ddl, err := store.ExportSchema(ctx)
+	if err != nil {
+		t.Fatalf("ExportSchema failed: %v", err)
+	}
+	for _, want := range []string{"CREATE TABLE `issues`", "CREATE TABLE `dependencies`", "ready_issues", "blocked_issues"} {
+		if !strings.Contains(ddl, want) {
+			t.Errorf("ExportSchema output missing %q:\n%s", want, ddl)
+		}
+	}
+	if strings.Contains(ddl, "does not exist") {
+		t.Errorf("ExportSchema reported missing objects after init:\n%s", ddl)
+	}
+
+	// A dropped table is reported rather than failing the export
+	if _, err := store.UnderlyingDB().ExecContext(ctx, "DROP TABLE routes"); err != nil {
+		t.Fatalf("failed to drop routes: %v", err)
+	}
+	ddl, err = store.ExportSchema(ctx)
+	if err != nil {
+		t.Fatalf("ExportSchema failed: %v", err)
+	}
+	if !strings.Contains(ddl, "-- table routes does not exist") {
+		t.Errorf("ExportSchema output should note the missing routes table:\n%s", ddl)
+	}
+}
+
+func TestParseExpectedSchema(t *testing.T) {
+	issues := expectedSchema()["issues"]
+	if issues == nil {
+		t.Fatal("expected schema has no issues table")
+	}
+	for _, want := range []string{"id", "wisp_type", "spec_id", "issue_type", "defer_until"} {
+		if !slices.Contains(issues.columns, want) {
+			t.Errorf("issues columns %v missing %s", issues.columns, want)
+		}
+	}
+	for _, want := range []string{"PRIMARY", "idx_issues_issue_type", "idx_issues_spec_id"} {
+		if !slices.Contains(issues.indexes, want) {
+			t.Errorf("issues indexes %v missing %s", issues.indexes, want)
+		}
+	}
+
+	config := expectedSchema()["config"]
+	if config == nil || !slices.Equal(config.columns, []string{"key", "value"}) {
+		t.Errorf("config table = %+v, want columns key and value", config)
+	}
+	deps := expectedSchema()["dependencies"]
+	if deps == nil || !slices.Contains(deps.foreignKeys, "fk_dep_issue") || slices.Contains(deps.columns, "CONSTRAINT") {
+		t.Errorf("dependencies table = %+v, want fk_dep_issue as a foreign key only", deps)
+	}
+	for _, table := range tableNames {
+		if expectedSchema()[table] == nil {
+			t.Errorf("expected schema missing table %s", table)
+		}
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	diffs, err := store.ValidateSchema(ctx)
+	if err != nil {
+		t.Fatalf("ValidateSchema failed: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Fatalf("ValidateSchema after init = %v, want no differences", diffs)
+	}
+
+	db := store.UnderlyingDB()
+	for _, stmt := range []string{
+		"DROP INDEX idx_issues_spec_id ON issues",
+		"ALTER TABLE issues DROP COLUMN spec_id",
+		"ALTER TABLE issues DROP COLUMN wisp_type",
+		"ALTER TABLE issues ADD COLUMN legacy_flag INT",
+		"DROP TABLE routes",
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s failed: %v", stmt, err)
+		}
+	}
+
+	diffs, err = store.ValidateSchema(ctx)
+	if err != nil {
+		t.Fatalf("ValidateSchema failed: %v", err)
+	}
+	var got []string
+	for _, d := range diffs {
+		got = append(got, d.String())
+	}
+	want := []string{
+		"missing column issues.spec_id",
+		"missing column issues.wisp_type",
+		"unexpected column issues.legacy_flag",
+		"missing index issues.idx_issues_spec_id",
+		"missing table routes",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ValidateSchema = %v, want %v", got, want)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +16,9 @@ import (
 
 // CreateIssue creates a new issue
 func (s *MariaDBStore) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	// Fetch custom statuses and types for validation
 	customStatuses, err := s.GetCustomStatuses(ctx)
 	if err != nil {
@@ -25,36 +29,13 @@ func (s *MariaDBStore) CreateIssue(ctx context.Context, issue *types.Issue, acto
 		return fmt.Errorf("failed to get custom types: %w", err)
 	}
 
-	// Set timestamps
-	now := time.Now().UTC()
-	if issue.CreatedAt.IsZero() {
-		issue.CreatedAt = now
-	}
-	if issue.UpdatedAt.IsZero() {
-		issue.UpdatedAt = now
-	}
-
-	// Defensive fix for closed_at invariant
-	if issue.Status == types.StatusClosed && issue.ClosedAt == nil {
-		maxTime := issue.CreatedAt
-		if issue.UpdatedAt.After(maxTime) {
-			maxTime = issue.UpdatedAt
-		}
-		closedAt := maxTime.Add(time.Second)
-		issue.ClosedAt = &closedAt
-	}
-
-	// Defensive fix for deleted_at invariant
-	if issue.Status == types.StatusTombstone && issue.DeletedAt == nil {
-		maxTime := issue.CreatedAt
-		if issue.UpdatedAt.After(maxTime) {
-			maxTime = issue.UpdatedAt
-		}
-		deletedAt := maxTime.Add(time.Second)
-		issue.DeletedAt = &deletedAt
-	}
+	// Set timestamps and closed_at/deleted_at invariants
+	setCreateDefaults(issue)
 
 	// Validate issue
+	if err := checkPriority(issue.Priority); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
 	if err := issue.ValidateWithCustom(customStatuses, customTypes); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
@@ -64,59 +45,65 @@ func (s *MariaDBStore) CreateIssue(ctx context.Context, issue *types.Issue, acto
 		issue.ContentHash = issue.ComputeContentHash()
 	}
 
-	// Start transaction
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+	// Fail early, with a clear error, if the issue cannot fit in a packet
+	if err := checkRowSizes([][]interface{}{issueInsertArgs(issue)}, 0, s.maxPacket); err != nil {
+		return err
 	}
-	defer func() { _ = tx.Rollback() }()
 
-	// Get prefix from config
-	var configPrefix string
-	err = tx.QueryRowContext(ctx, "SELECT value FROM config WHERE `key` = ?", "issue_prefix").Scan(&configPrefix)
-	if err == sql.ErrNoRows || configPrefix == "" {
-		return fmt.Errorf("database not initialized: issue_prefix config is missing (run 'bd init --prefix <prefix>' first)")
-	} else if err != nil {
-		return fmt.Errorf("failed to get config: %w", err)
-	}
+	// Run in a transaction that is replayed on deadlock. A replay reuses the
+	// ID generated by the failed attempt, which was rolled back with it (a
+	// failed insert clears it, so the replay generates a new one).
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		// Get prefix from config
+		var configPrefix string
+		err := tx.QueryRowContext(ctx, "SELECT value FROM config WHERE `key` = ?", "issue_prefix").Scan(&configPrefix)
+		if err == sql.ErrNoRows || configPrefix == "" {
+			return fmt.Errorf("database not initialized: issue_prefix config is missing (run 'bd init --prefix <prefix>' first)")
+		} else if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
 
-	// Determine prefix for ID generation
-	prefix := configPrefix
-	if issue.PrefixOverride != "" {
-		prefix = issue.PrefixOverride
-	} else if issue.IDPrefix != "" {
-		prefix = configPrefix + "-" + issue.IDPrefix
-	}
+		// Determine prefix for ID generation
+		prefix := configPrefix
+		if issue.PrefixOverride != "" {
+			prefix = issue.PrefixOverride
+		} else if issue.IDPrefix != "" {
+			prefix = configPrefix + "-" + issue.IDPrefix
+		}
 
-	// Generate or validate ID
-	if issue.ID == "" {
-		generatedID, err := generateIssueID(ctx, tx, prefix, issue, actor)
+		// Insert issue, generating its ID if it has none
+		insert := func() error {
+			_, err := insertIssue(ctx, tx, issue, ConflictError)
+			return err
+		}
+		if issue.ID == "" {
+			err = insertWithGeneratedID(ctx, tx, s.idGenerator(), prefix, issue, actor, insert)
+		} else {
+			err = insert()
+		}
 		if err != nil {
-			return fmt.Errorf("failed to generate issue ID: %w", err)
+			return fmt.Errorf("failed to insert issue: %w", err)
 		}
-		issue.ID = generatedID
-	}
 
-	// Insert issue
-	if err := insertIssue(ctx, tx, issue); err != nil {
-		return fmt.Errorf("failed to insert issue: %w", err)
-	}
-
-	// Record creation event
-	if err := recordEvent(ctx, tx, issue.ID, types.EventCreated, actor, "", ""); err != nil {
-		return fmt.Errorf("failed to record creation event: %w", err)
-	}
+		// Record creation event
+		if err := recordEvent(ctx, tx, issue.ID, types.EventCreated, actor, "", ""); err != nil {
+			return fmt.Errorf("failed to record creation event: %w", err)
+		}
 
-	// Mark issue as dirty
-	if err := markDirty(ctx, tx, issue.ID); err != nil {
-		return fmt.Errorf("failed to mark issue dirty: %w", err)
-	}
+		// Mark issue as dirty
+		if err := markDirty(ctx, tx, issue.ID); err != nil {
+			return fmt.Errorf("failed to mark issue dirty: %w", err)
+		}
 
-	return tx.Commit()
+		return nil
+	})
 }
 
 // CreateIssues creates multiple issues in a single transaction
 func (s *MariaDBStore) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	return s.CreateIssuesWithFullOptions(ctx, issues, actor, storage.BatchCreateOptions{
 		OrphanHandling:       storage.OrphanAllow,
 		SkipPrefixValidation: false,
@@ -127,6 +114,33 @@ func (s *MariaDBStore) CreateIssues(ctx context.Context, issues []*types.Issue,
 // This is the backend-agnostic batch creation method that supports orphan handling
 // and prefix validation options.
 func (s *MariaDBStore) CreateIssuesWithFullOptions(ctx context.Context, issues []*types.Issue, actor string, opts storage.BatchCreateOptions) error {
+	return s.CreateIssuesOnConflict(ctx, issues, actor, opts, ConflictError)
+}
+
+// OnConflict is an alias for storage.OnConflict, the conflict modes shared
+// with storage.ImportJSON.
+type OnConflict = storage.OnConflict
+
+// Conflict modes - aliases for the storage package constants. ConflictSkip
+// uses a no-op ON DUPLICATE KEY UPDATE rather than INSERT IGNORE, which would
+// also turn errors such as oversized values into warnings. ConflictReplace
+// records an update event and only bumps the version if a field changes; it
+// keeps a soft-deleted issue's deleted_at, deleted_by and delete_reason.
+const (
+	ConflictError           = storage.ConflictError
+	ConflictSkip            = storage.ConflictSkip
+	ConflictReplace         = storage.ConflictReplace
+	ConflictReplaceUndelete = storage.ConflictReplaceUndelete
+)
+
+// CreateIssuesOnConflict is CreateIssuesWithFullOptions with control over
+// issues whose ID already exists, so imports can be re-run idempotently.
+// Only newly inserted issues get a creation event; replaced issues get an
+// update event and skipped or unchanged ones are left alone.
+func (s *MariaDBStore) CreateIssuesOnConflict(ctx context.Context, issues []*types.Issue, actor string, opts storage.BatchCreateOptions, onConflict OnConflict) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	if len(issues) == 0 {
 		return nil
 	}
@@ -141,100 +155,83 @@ func (s *MariaDBStore) CreateIssuesWithFullOptions(ctx context.Context, issues [
 		return fmt.Errorf("failed to get custom types: %w", err)
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer func() { _ = tx.Rollback() }()
-
-	// Get prefix from config for validation
-	var configPrefix string
-	err = tx.QueryRowContext(ctx, "SELECT value FROM config WHERE `key` = ?", "issue_prefix").Scan(&configPrefix)
-	if err == sql.ErrNoRows || configPrefix == "" {
-		return fmt.Errorf("database not initialized: issue_prefix config is missing (run 'bd init --prefix <prefix>' first)")
-	} else if err != nil {
-		return fmt.Errorf("failed to get config: %w", err)
-	}
-
-	for _, issue := range issues {
-		now := time.Now().UTC()
-		if issue.CreatedAt.IsZero() {
-			issue.CreatedAt = now
-		}
-		if issue.UpdatedAt.IsZero() {
-			issue.UpdatedAt = now
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		// Get prefix from config for validation
+		var configPrefix string
+		err := tx.QueryRowContext(ctx, "SELECT value FROM config WHERE `key` = ?", "issue_prefix").Scan(&configPrefix)
+		if err == sql.ErrNoRows || configPrefix == "" {
+			return fmt.Errorf("database not initialized: issue_prefix config is missing (run 'bd init --prefix <prefix>' first)")
+		} else if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
 		}
 
-		// Defensive fix for closed_at invariant
-		if issue.Status == types.StatusClosed && issue.ClosedAt == nil {
-			maxTime := issue.CreatedAt
-			if issue.UpdatedAt.After(maxTime) {
-				maxTime = issue.UpdatedAt
-			}
-			closedAt := maxTime.Add(time.Second)
-			issue.ClosedAt = &closedAt
-		}
+		for _, issue := range issues {
+			setCreateDefaults(issue)
 
-		// Defensive fix for deleted_at invariant
-		if issue.Status == types.StatusTombstone && issue.DeletedAt == nil {
-			maxTime := issue.CreatedAt
-			if issue.UpdatedAt.After(maxTime) {
-				maxTime = issue.UpdatedAt
+			// Validate issue
+			if err := checkPriority(issue.Priority); err != nil {
+				return fmt.Errorf("validation failed for issue %s: %w", issue.ID, err)
+			}
+			if err := issue.ValidateWithCustom(customStatuses, customTypes); err != nil {
+				return fmt.Errorf("validation failed for issue %s: %w", issue.ID, err)
 			}
-			deletedAt := maxTime.Add(time.Second)
-			issue.DeletedAt = &deletedAt
-		}
-
-		// Validate issue
-		if err := issue.ValidateWithCustom(customStatuses, customTypes); err != nil {
-			return fmt.Errorf("validation failed for issue %s: %w", issue.ID, err)
-		}
-
-		if issue.ContentHash == "" {
-			issue.ContentHash = issue.ComputeContentHash()
-		}
 
-		// Validate prefix if not skipped (for imports with different prefixes)
-		if !opts.SkipPrefixValidation && issue.ID != "" {
-			if err := validateIssueIDPrefix(issue.ID, configPrefix); err != nil {
-				return fmt.Errorf("prefix validation failed for %s: %w", issue.ID, err)
+			if issue.ContentHash == "" {
+				issue.ContentHash = issue.ComputeContentHash()
 			}
-		}
 
-		// Handle orphan checking for hierarchical IDs
-		if issue.ID != "" {
-			if parentID, _, ok := parseHierarchicalID(issue.ID); ok {
-				var parentCount int
-				err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM issues WHERE id = ?`, parentID).Scan(&parentCount)
-				if err != nil {
-					return fmt.Errorf("failed to check parent existence: %w", err)
+			// Validate prefix if not skipped (for imports with different prefixes)
+			if !opts.SkipPrefixValidation && issue.ID != "" {
+				if err := validateIssueIDPrefix(issue.ID, configPrefix); err != nil {
+					return fmt.Errorf("prefix validation failed for %s: %w", issue.ID, err)
 				}
-				if parentCount == 0 {
-					switch opts.OrphanHandling {
-					case storage.OrphanStrict:
-						return fmt.Errorf("parent issue %s does not exist (strict mode)", parentID)
-					case storage.OrphanSkip:
-						// Skip this issue
-						continue
-					case storage.OrphanResurrect, storage.OrphanAllow:
-						// Allow orphan - continue with insert
+			}
+
+			// Handle orphan checking for hierarchical IDs
+			if issue.ID != "" {
+				if parentID, _, ok := parseHierarchicalID(issue.ID); ok {
+					var parentCount int
+					err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM issues WHERE id = ?`, parentID).Scan(&parentCount)
+					if err != nil {
+						return fmt.Errorf("failed to check parent existence: %w", err)
+					}
+					if parentCount == 0 {
+						switch opts.OrphanHandling {
+						case storage.OrphanStrict:
+							return fmt.Errorf("parent issue %s does not exist (strict mode)", parentID)
+						case storage.OrphanSkip:
+							// Skip this issue
+							continue
+						case storage.OrphanResurrect, storage.OrphanAllow:
+							// Allow orphan - continue with insert
+						}
 					}
 				}
 			}
-		}
 
-		if err := insertIssue(ctx, tx, issue); err != nil {
-			return fmt.Errorf("failed to insert issue %s: %w", issue.ID, err)
-		}
-		if err := recordEvent(ctx, tx, issue.ID, types.EventCreated, actor, "", ""); err != nil {
-			return fmt.Errorf("failed to record event for %s: %w", issue.ID, err)
-		}
-		if err := markDirty(ctx, tx, issue.ID); err != nil {
-			return fmt.Errorf("failed to mark dirty %s: %w", issue.ID, err)
+			affected, err := insertIssue(ctx, tx, issue, onConflict)
+			if err != nil {
+				return fmt.Errorf("failed to insert issue %s: %w", issue.ID, err)
+			}
+			eventType := types.EventCreated
+			switch affected {
+			case 0:
+				// Skipped, or replaced with identical values
+				continue
+			case 2:
+				// MariaDB counts a row updated by ON DUPLICATE KEY UPDATE twice
+				eventType = types.EventUpdated
+			}
+			if err := recordEvent(ctx, tx, issue.ID, eventType, actor, "", ""); err != nil {
+				return fmt.Errorf("failed to record event for %s: %w", issue.ID, err)
+			}
+			if err := markDirty(ctx, tx, issue.ID); err != nil {
+				return fmt.Errorf("failed to mark dirty %s: %w", issue.ID, err)
+			}
 		}
-	}
 
-	return tx.Commit()
+		return nil
+	})
 }
 
 // validateIssueIDPrefix validates that the issue ID has the correct prefix
@@ -266,16 +263,23 @@ func parseHierarchicalID(id string) (parentID string, childNum int, ok bool) {
 	return parentID, num, true
 }
 
-// GetIssue retrieves an issue by ID
+// GetIssue retrieves an issue by ID. Soft-deleted issues are reported as not
+// found; use SearchIssues with IDs and IncludeDeleted to read them.
 func (s *MariaDBStore) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 
-	issue, err := scanIssue(ctx, s.db, id)
+	var issue *types.Issue
+	err := s.withReadRetry(ctx, func() error {
+		var err error
+		issue, err = scanIssue(ctx, s.dbOrTx(), id)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	if issue == nil {
+	if issue == nil || isSoftDeleted(issue) {
 		return nil, nil
 	}
 
@@ -291,11 +295,12 @@ func (s *MariaDBStore) GetIssue(ctx context.Context, id string) (*types.Issue, e
 
 // GetIssueByExternalRef retrieves an issue by external reference
 func (s *MariaDBStore) GetIssueByExternalRef(ctx context.Context, externalRef string) (*types.Issue, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 
 	var id string
-	err := s.db.QueryRowContext(ctx, "SELECT id FROM issues WHERE external_ref = ?", externalRef).Scan(&id)
+	err := s.queryRowContext(ctx, "SELECT id FROM issues WHERE external_ref = ?", externalRef).Scan(&id)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -306,8 +311,96 @@ func (s *MariaDBStore) GetIssueByExternalRef(ctx context.Context, externalRef st
 	return s.GetIssue(ctx, id)
 }
 
+// ErrVersionConflict is returned by UpdateIssueAtVersion when the issue was
+// changed since the caller read its version.
+var ErrVersionConflict = storage.ErrVersionConflict
+
+// ErrInvalidPriority is returned when an issue is created or updated with a
+// priority outside MinPriority to MaxPriority.
+var ErrInvalidPriority = storage.ErrInvalidPriority
+
+// Priorities rank from MinPriority (P0, critical) to MaxPriority (P4,
+// backlog), the range types.ValidatePriority accepts; ready work is ordered
+// by this numeric rank.
+const (
+	MinPriority = 0
+	MaxPriority = 4
+)
+
+// checkPriority returns the error of types.ValidatePriority for p, wrapped
+// with ErrInvalidPriority.
+func checkPriority(p int) error {
+	if err := types.ValidatePriority(p); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPriority, err)
+	}
+	return nil
+}
+
+// priorityValue converts the value of a priority update to its rank. It
+// accepts integers, whole floats (as decoded from JSON), and strings such as
+// "2" or "P2".
+func priorityValue(v interface{}) (int, error) {
+	var p int
+	switch v := v.(type) {
+	case int:
+		p = v
+	case int32:
+		p = int(v)
+	case int64:
+		p = int(v)
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("%w %v: must be a whole number", ErrInvalidPriority, v)
+		}
+		p = int(v)
+	case string:
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(v), "P"), "p"))
+		if err != nil {
+			return 0, fmt.Errorf("%w %q: want 0-4 or P0-P4", ErrInvalidPriority, v)
+		}
+		p = n
+	default:
+		return 0, fmt.Errorf("%w: unsupported type %T", ErrInvalidPriority, v)
+	}
+	return p, checkPriority(p)
+}
+
 // UpdateIssue updates fields on an issue
 func (s *MariaDBStore) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	return s.updateIssue(ctx, id, updates, actor, nil)
+}
+
+// UpdateIssueAtVersion updates fields on an issue only if its version, as
+// returned by GetIssueVersion, is still expectedVersion. Otherwise it returns
+// an error wrapping ErrVersionConflict and changes nothing; callers should
+// re-read the issue and retry. Every update increments the version.
+func (s *MariaDBStore) UpdateIssueAtVersion(ctx context.Context, id string, expectedVersion int64, updates map[string]interface{}, actor string) error {
+	return s.updateIssue(ctx, id, updates, actor, &expectedVersion)
+}
+
+// GetIssueVersion returns the current version of an issue, for use with
+// UpdateIssueAtVersion.
+func (s *MariaDBStore) GetIssueVersion(ctx context.Context, id string) (int64, error) {
+	if s.IsClosed() {
+		return 0, ErrStoreClosed
+	}
+	var version int64
+	err := s.queryRowContext(ctx, "SELECT version FROM issues WHERE id = ?", id).Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("issue %s not found", id)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get issue version: %w", err)
+	}
+	return version, nil
+}
+
+// updateIssue implements UpdateIssue. With a non-nil expectedVersion, the
+// update only applies to that version of the issue.
+func (s *MariaDBStore) updateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string, expectedVersion *int64) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	oldIssue, err := s.GetIssue(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to get issue for update: %w", err)
@@ -317,7 +410,7 @@ func (s *MariaDBStore) UpdateIssue(ctx context.Context, id string, updates map[s
 	}
 
 	// Build update query
-	setClauses := []string{"updated_at = ?"}
+	setClauses := []string{"updated_at = ?", "version = version + 1"}
 	args := []interface{}{time.Now().UTC()}
 
 	for key, value := range updates {
@@ -335,6 +428,12 @@ func (s *MariaDBStore) UpdateIssue(ctx context.Context, id string, updates map[s
 		if key == "waiters" {
 			waitersJSON, _ := json.Marshal(value)
 			args = append(args, string(waitersJSON))
+		} else if key == "priority" {
+			priority, err := priorityValue(value)
+			if err != nil {
+				return err
+			}
+			args = append(args, priority)
 		} else if key == "metadata" {
 			// GH#1417: Normalize metadata to string, accepting string/[]byte/json.RawMessage
 			metadataStr, err := storage.NormalizeMetadataValue(value)
@@ -351,39 +450,65 @@ func (s *MariaDBStore) UpdateIssue(ctx context.Context, id string, updates map[s
 	setClauses, args = manageClosedAt(oldIssue, updates, setClauses, args)
 
 	args = append(args, id)
+	whereSQL := "id = ?"
+	if expectedVersion != nil {
+		whereSQL += " AND version = ?"
+		args = append(args, *expectedVersion)
+	}
+	auditCols := auditColumns(updates)
+
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		// nolint:gosec // G201: setClauses contains only column names (e.g. "status = ?"), actual values passed via args
+		query := fmt.Sprintf("UPDATE issues SET %s WHERE %s", strings.Join(setClauses, ", "), whereSQL)
+		if err := updateAudited(ctx, tx, id, actor, auditCols, func() error {
+			result, err := tx.ExecContext(ctx, query, args...)
+			if err != nil {
+				return fmt.Errorf("failed to update issue: %w", err)
+			}
+			if expectedVersion == nil {
+				return nil
+			}
+			// version always changes, so a matched row is always affected
+			rows, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get rows affected: %w", err)
+			}
+			if rows == 0 {
+				var current int64
+				if err := tx.QueryRowContext(ctx, "SELECT version FROM issues WHERE id = ?", id).Scan(&current); err != nil {
+					return fmt.Errorf("failed to get issue version: %w", err)
+				}
+				return fmt.Errorf("%w: issue %s is at version %d, not %d", ErrVersionConflict, id, current, *expectedVersion)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
 
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer func() { _ = tx.Rollback() }()
-
-	// nolint:gosec // G201: setClauses contains only column names (e.g. "status = ?"), actual values passed via args
-	query := fmt.Sprintf("UPDATE issues SET %s WHERE id = ?", strings.Join(setClauses, ", "))
-	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
-		return fmt.Errorf("failed to update issue: %w", err)
-	}
-
-	// Record event
-	oldData, _ := json.Marshal(oldIssue)
-	newData, _ := json.Marshal(updates)
-	eventType := determineEventType(oldIssue, updates)
+		// Record event
+		oldData, _ := json.Marshal(oldIssue)
+		newData, _ := json.Marshal(updates)
+		eventType := determineEventType(oldIssue, updates)
 
-	if err := recordEvent(ctx, tx, id, eventType, actor, string(oldData), string(newData)); err != nil {
-		return fmt.Errorf("failed to record event: %w", err)
-	}
+		if err := recordEvent(ctx, tx, id, eventType, actor, string(oldData), string(newData)); err != nil {
+			return fmt.Errorf("failed to record event: %w", err)
+		}
 
-	if err := markDirty(ctx, tx, id); err != nil {
-		return fmt.Errorf("failed to mark dirty: %w", err)
-	}
+		if err := markDirty(ctx, tx, id); err != nil {
+			return fmt.Errorf("failed to mark dirty: %w", err)
+		}
 
-	return tx.Commit()
+		return nil
+	})
 }
 
 // ClaimIssue atomically claims an issue using compare-and-swap semantics.
 // It sets the assignee to actor and status to "in_progress" only if the issue
 // currently has no assignee. Returns storage.ErrAlreadyClaimed if already claimed.
 func (s *MariaDBStore) ClaimIssue(ctx context.Context, id string, actor string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	oldIssue, err := s.GetIssue(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to get issue for claim: %w", err)
@@ -394,164 +519,361 @@ func (s *MariaDBStore) ClaimIssue(ctx context.Context, id string, actor string)
 
 	now := time.Now().UTC()
 
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer func() { _ = tx.Rollback() }()
-
-	// Use conditional UPDATE with WHERE clause to ensure atomicity.
-	// The UPDATE only succeeds if assignee is currently empty.
-	result, err := tx.ExecContext(ctx, `
-		UPDATE issues
-		SET assignee = ?, status = 'in_progress', updated_at = ?
-		WHERE id = ? AND (assignee = '' OR assignee IS NULL)
-	`, actor, now, id)
-	if err != nil {
-		return fmt.Errorf("failed to claim issue: %w", err)
-	}
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		err := updateAudited(ctx, tx, id, actor, claimAuditColumns, func() error {
+			// Use conditional UPDATE with WHERE clause to ensure atomicity.
+			// The UPDATE only succeeds if assignee is currently empty.
+			result, err := tx.ExecContext(ctx, `
+				UPDATE issues
+				SET assignee = ?, status = 'in_progress', updated_at = ?, version = version + 1
+				WHERE id = ? AND (assignee = '' OR assignee IS NULL)
+			`, actor, now, id)
+			if err != nil {
+				return fmt.Errorf("failed to claim issue: %w", err)
+			}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get rows affected: %w", err)
+			}
 
-	if rowsAffected == 0 {
-		// The UPDATE didn't affect any rows, which means the assignee was not empty.
-		// Query to find out who has it claimed.
-		var currentAssignee string
-		err := s.db.QueryRowContext(ctx, `SELECT assignee FROM issues WHERE id = ?`, id).Scan(&currentAssignee)
+			if rowsAffected == 0 {
+				// The UPDATE didn't affect any rows, which means the assignee was not empty.
+				// Query to find out who has it claimed.
+				var currentAssignee string
+				err := tx.QueryRowContext(ctx, `SELECT assignee FROM issues WHERE id = ?`, id).Scan(&currentAssignee)
+				if err != nil {
+					return fmt.Errorf("failed to get current assignee: %w", err)
+				}
+				return fmt.Errorf("%w by %s", storage.ErrAlreadyClaimed, currentAssignee)
+			}
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("failed to get current assignee: %w", err)
+			return err
 		}
-		return fmt.Errorf("%w by %s", storage.ErrAlreadyClaimed, currentAssignee)
-	}
 
-	// Record the claim event
-	oldData, _ := json.Marshal(oldIssue)
-	newUpdates := map[string]interface{}{
-		"assignee": actor,
-		"status":   "in_progress",
-	}
-	newData, _ := json.Marshal(newUpdates)
+		// Record the claim event
+		oldData, _ := json.Marshal(oldIssue)
+		newUpdates := map[string]interface{}{
+			"assignee": actor,
+			"status":   "in_progress",
+		}
+		newData, _ := json.Marshal(newUpdates)
 
-	if err := recordEvent(ctx, tx, id, "claimed", actor, string(oldData), string(newData)); err != nil {
-		return fmt.Errorf("failed to record claim event: %w", err)
-	}
+		if err := recordEvent(ctx, tx, id, "claimed", actor, string(oldData), string(newData)); err != nil {
+			return fmt.Errorf("failed to record claim event: %w", err)
+		}
 
-	if err := markDirty(ctx, tx, id); err != nil {
-		return fmt.Errorf("failed to mark dirty: %w", err)
-	}
+		if err := markDirty(ctx, tx, id); err != nil {
+			return fmt.Errorf("failed to mark dirty: %w", err)
+		}
 
-	return tx.Commit()
+		return nil
+	})
 }
 
 // CloseIssue closes an issue with a reason
 func (s *MariaDBStore) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
 	now := time.Now().UTC()
 
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		err := updateAudited(ctx, tx, id, actor, closeAuditColumns, func() error {
+			result, err := tx.ExecContext(ctx, `
+				UPDATE issues SET status = ?, closed_at = ?, updated_at = ?, close_reason = ?, closed_by_session = ?, version = version + 1
+				WHERE id = ?
+			`, types.StatusClosed, now, now, reason, session, id)
+			if err != nil {
+				return fmt.Errorf("failed to close issue: %w", err)
+			}
+
+			rows, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get rows affected: %w", err)
+			}
+			if rows == 0 {
+				return fmt.Errorf("issue not found: %s", id)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := recordEvent(ctx, tx, id, types.EventClosed, actor, "", reason); err != nil {
+			return fmt.Errorf("failed to record event: %w", err)
+		}
+
+		if err := markDirty(ctx, tx, id); err != nil {
+			return fmt.Errorf("failed to mark dirty: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteIssue permanently removes an issue, along with its events, comments,
+// labels, and the dependencies on either side of it.
+func (s *MariaDBStore) DeleteIssue(ctx context.Context, id string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
 	}
-	defer func() { _ = tx.Rollback() }()
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		_, err := deleteIssue(ctx, tx, id)
+		return err
+	})
+}
 
-	result, err := tx.ExecContext(ctx, `
-		UPDATE issues SET status = ?, closed_at = ?, updated_at = ?, close_reason = ?, closed_by_session = ?
-		WHERE id = ?
-	`, types.StatusClosed, now, now, reason, session, id)
+// deleteIssue permanently removes id within tx, returning the number of
+// dependency rows removed with it. There is no foreign key on depends_on_id,
+// so those rows would otherwise be left dangling.
+func deleteIssue(ctx context.Context, tx *sql.Tx, id string) (int, error) {
+	var deps int64
+	tables := []string{"dependencies", "events", "comments", "issue_labels", "dirty_issues"}
+	for _, table := range tables {
+		if table == "dependencies" {
+			result, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE issue_id = ? OR depends_on_id = ?", table), id, id)
+			if err != nil {
+				return 0, fmt.Errorf("failed to delete from %s: %w", table, err)
+			}
+			if deps, err = result.RowsAffected(); err != nil {
+				return 0, fmt.Errorf("failed to get rows affected: %w", err)
+			}
+		} else if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE issue_id = ?", table), id); err != nil {
+			return 0, fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
+
+	if err := recordHardDelete(ctx, tx, id); err != nil {
+		return 0, err
+	}
+	result, err := tx.ExecContext(ctx, "DELETE FROM issues WHERE id = ?", id)
 	if err != nil {
-		return fmt.Errorf("failed to close issue: %w", err)
+		return 0, fmt.Errorf("failed to delete issue: %w", err)
 	}
 
 	rows, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("issue not found: %s", id)
+		return 0, fmt.Errorf("issue not found: %s", id)
 	}
 
-	if err := recordEvent(ctx, tx, id, types.EventClosed, actor, "", reason); err != nil {
-		return fmt.Errorf("failed to record event: %w", err)
-	}
-
-	if err := markDirty(ctx, tx, id); err != nil {
-		return fmt.Errorf("failed to mark dirty: %w", err)
-	}
-
-	return tx.Commit()
+	return int(deps), nil
 }
 
-// DeleteIssue permanently removes an issue
-func (s *MariaDBStore) DeleteIssue(ctx context.Context, id string) error {
-	tx, err := s.db.BeginTx(ctx, nil)
+// recordHardDelete writes a deleted_issues row for id, so Watch reports its
+// removal. Call it before the issues row is deleted; if there is none, it
+// writes nothing.
+func recordHardDelete(ctx context.Context, tx *sql.Tx, id string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO deleted_issues (issue_id, version, deleted_at)
+		SELECT id, version + 1, ? FROM issues WHERE id = ?
+	`, time.Now().UTC(), id)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return fmt.Errorf("failed to record deletion of %s: %w", id, err)
 	}
-	defer func() { _ = tx.Rollback() }()
+	return nil
+}
 
-	// Delete related data (foreign keys will cascade, but be explicit)
-	tables := []string{"dependencies", "events", "comments", "labels", "dirty_issues"}
-	for _, table := range tables {
-		if table == "dependencies" {
-			_, err = tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE issue_id = ? OR depends_on_id = ?", table), id, id)
-		} else {
-			_, err = tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE issue_id = ?", table), id)
+// BulkDeleteIssues deletes the issues in ids in a single transaction and
+// returns the number of dependency rows removed. If any issue cannot be
+// deleted, none are.
+//
+// With soft set, the issues are soft-deleted as SoftDeleteIssue does
+// instead. Their rows stay, so nothing dangles and no dependencies are
+// removed: they are hidden along with the issues and come back with
+// RestoreIssue. Otherwise each issue is removed as DeleteIssue does,
+// dependencies on either side of it included.
+func (s *MariaDBStore) BulkDeleteIssues(ctx context.Context, ids []string, soft bool) (int, error) {
+	if err := s.checkWritable(); err != nil {
+		return 0, err
+	}
+
+	// A repeated ID would otherwise fail as not found the second time
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
 		}
-		if err != nil {
-			return fmt.Errorf("failed to delete from %s: %w", table, err)
+	}
+	now := time.Now().UTC()
+
+	var removed int
+	err := s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		removed = 0
+		for _, id := range unique {
+			if soft {
+				if err := softDeleteIssue(ctx, tx, id, now); err != nil {
+					return err
+				}
+				continue
+			}
+			n, err := deleteIssue(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			removed += n
 		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to bulk delete issues: %w", err)
 	}
+	return removed, nil
+}
 
-	result, err := tx.ExecContext(ctx, "DELETE FROM issues WHERE id = ?", id)
+// SoftDeleteIssue archives an issue by setting its deleted_at. Unlike
+// DeleteIssue, the row and its dependencies, events, comments, and labels are
+// kept: the issue just disappears from GetIssue, list queries, and the ready
+// and blocked views, and no longer blocks other issues. RestoreIssue undoes it.
+func (s *MariaDBStore) SoftDeleteIssue(ctx context.Context, id string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		return softDeleteIssue(ctx, tx, id, now)
+	})
+}
+
+// softDeleteIssue sets the deleted_at of id to now within tx.
+func softDeleteIssue(ctx context.Context, tx *sql.Tx, id string, now time.Time) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE issues SET deleted_at = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND deleted_at IS NULL AND status != ?
+	`, now, now, id, types.StatusTombstone)
 	if err != nil {
-		return fmt.Errorf("failed to delete issue: %w", err)
+		return fmt.Errorf("failed to soft delete issue: %w", err)
+	}
+	if err := requireSoftDeleteChange(ctx, tx, result, id, true); err != nil {
+		return err
+	}
+	if err := markDirty(ctx, tx, id); err != nil {
+		return fmt.Errorf("failed to mark dirty: %w", err)
+	}
+	return nil
+}
+
+// RestoreIssue undoes SoftDeleteIssue, clearing the issue's deleted_at.
+func (s *MariaDBStore) RestoreIssue(ctx context.Context, id string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
 	}
+	now := time.Now().UTC()
 
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE issues SET deleted_at = NULL, updated_at = ?, version = version + 1
+			WHERE id = ? AND deleted_at IS NOT NULL AND status != ?
+		`, now, id, types.StatusTombstone)
+		if err != nil {
+			return fmt.Errorf("failed to restore issue: %w", err)
+		}
+		if err := requireSoftDeleteChange(ctx, tx, result, id, false); err != nil {
+			return err
+		}
+		if err := markDirty(ctx, tx, id); err != nil {
+			return fmt.Errorf("failed to mark dirty: %w", err)
+		}
+		return nil
+	})
+}
+
+// requireSoftDeleteChange explains why a soft delete (deleting) or restore
+// of id changed no rows.
+func requireSoftDeleteChange(ctx context.Context, tx *sql.Tx, result sql.Result, id string, deleting bool) error {
 	rows, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
-	if rows == 0 {
+	if rows > 0 {
+		return nil
+	}
+	issue, err := scanIssue(ctx, txQuerier{tx}, id)
+	if err != nil {
+		return err
+	}
+	switch {
+	case issue == nil:
 		return fmt.Errorf("issue not found: %s", id)
+	case issue.Status == types.StatusTombstone:
+		return fmt.Errorf("issue %s is a tombstone", id)
+	case deleting:
+		return fmt.Errorf("issue %s is already soft-deleted", id)
+	default:
+		return fmt.Errorf("issue %s is not soft-deleted", id)
 	}
+}
 
-	return tx.Commit()
+// isSoftDeleted reports whether issue was archived by SoftDeleteIssue.
+// Tombstones also have a DeletedAt but are a separate status.
+func isSoftDeleted(issue *types.Issue) bool {
+	return issue.DeletedAt != nil && issue.Status != types.StatusTombstone
 }
 
 // =============================================================================
 // Helper functions
 // =============================================================================
 
-func insertIssue(ctx context.Context, tx *sql.Tx, issue *types.Issue) error {
-	_, err := tx.ExecContext(ctx, `
-		INSERT INTO issues (
-			id, content_hash, title, description, design, acceptance_criteria, notes,
-			status, priority, issue_type, assignee, estimated_minutes,
-			created_at, created_by, owner, updated_at, closed_at, external_ref, spec_id,
-			compaction_level, compacted_at, compacted_at_commit, original_size,
-			deleted_at, deleted_by, delete_reason, original_type,
-			sender, ephemeral, wisp_type, pinned, is_template, crystallizes,
-			mol_type, work_type, quality_score, source_system, source_repo, close_reason,
-			event_kind, actor, target, payload,
-			await_type, await_id, timeout_ns, waiters,
-			hook_bead, role_bead, agent_state, last_activity, role_type, rig,
-			due_at, defer_until, metadata
-		) VALUES (
-			?, ?, ?, ?, ?, ?, ?,
-			?, ?, ?, ?, ?,
-			?, ?, ?, ?, ?, ?, ?,
-			?, ?, ?, ?,
-			?, ?, ?, ?,
-			?, ?, ?, ?, ?, ?,
-			?, ?, ?, ?, ?, ?,
-			?, ?, ?, ?,
-			?, ?, ?, ?,
-			?, ?, ?, ?, ?, ?,
-			?, ?, ?
-		)
-	`,
+// setCreateDefaults fills in missing timestamps on a new issue and applies
+// the closed_at and deleted_at invariants for closed and tombstoned issues.
+func setCreateDefaults(issue *types.Issue) {
+	now := time.Now().UTC()
+	if issue.CreatedAt.IsZero() {
+		issue.CreatedAt = now
+	}
+	if issue.UpdatedAt.IsZero() {
+		issue.UpdatedAt = now
+	}
+
+	// Defensive fix for closed_at invariant
+	if issue.Status == types.StatusClosed && issue.ClosedAt == nil {
+		maxTime := issue.CreatedAt
+		if issue.UpdatedAt.After(maxTime) {
+			maxTime = issue.UpdatedAt
+		}
+		closedAt := maxTime.Add(time.Second)
+		issue.ClosedAt = &closedAt
+	}
+
+	// Defensive fix for deleted_at invariant
+	if issue.Status == types.StatusTombstone && issue.DeletedAt == nil {
+		maxTime := issue.CreatedAt
+		if issue.UpdatedAt.After(maxTime) {
+			maxTime = issue.UpdatedAt
+		}
+		deletedAt := maxTime.Add(time.Second)
+		issue.DeletedAt = &deletedAt
+	}
+}
+
+// issueInsertColumns lists the issues columns written on creation, in the
+// order of the values returned by issueInsertArgs.
+var issueInsertColumns = []string{
+	"id", "content_hash", "title", "description", "design", "acceptance_criteria", "notes",
+	"status", "priority", "issue_type", "assignee", "estimated_minutes",
+	"created_at", "created_by", "owner", "updated_at", "closed_at", "external_ref", "spec_id",
+	"compaction_level", "compacted_at", "compacted_at_commit", "original_size",
+	"deleted_at", "deleted_by", "delete_reason", "original_type",
+	"sender", "ephemeral", "wisp_type", "pinned", "is_template", "crystallizes",
+	"mol_type", "work_type", "quality_score", "source_system", "source_repo", "close_reason",
+	"event_kind", "actor", "target", "payload",
+	"await_type", "await_id", "timeout_ns", "waiters",
+	"hook_bead", "role_bead", "agent_state", "last_activity", "role_type", "rig",
+	"due_at", "defer_until", "metadata",
+}
+
+// issueInsertArgs returns the values for issueInsertColumns.
+func issueInsertArgs(issue *types.Issue) []interface{} {
+	return []interface{}{
 		issue.ID, issue.ContentHash, issue.Title, issue.Description, issue.Design, issue.AcceptanceCriteria, issue.Notes,
 		issue.Status, issue.Priority, issue.IssueType, nullString(issue.Assignee), nullInt(issue.EstimatedMinutes),
 		issue.CreatedAt, issue.CreatedBy, issue.Owner, issue.UpdatedAt, issue.ClosedAt, nullStringPtr(issue.ExternalRef), issue.SpecID,
@@ -563,11 +885,72 @@ func insertIssue(ctx context.Context, tx *sql.Tx, issue *types.Issue) error {
 		issue.AwaitType, issue.AwaitID, issue.Timeout.Nanoseconds(), formatJSONStringArray(issue.Waiters),
 		issue.HookBead, issue.RoleBead, issue.AgentState, issue.LastActivity, issue.RoleType, issue.Rig,
 		issue.DueAt, issue.DeferUntil, jsonMetadata(issue.Metadata),
-	)
-	return err
+	}
 }
 
-func scanIssue(ctx context.Context, db *sql.DB, id string) (*types.Issue, error) {
+// insertIssue inserts issue, resolving an existing issue with the same ID as
+// onConflict says. It returns the rows affected: 1 for an insert, 2 for a
+// replaced row, and 0 for a skipped or unchanged one.
+func insertIssue(ctx context.Context, tx *sql.Tx, issue *types.Issue, onConflict OnConflict) (int64, error) {
+	query := "INSERT INTO issues (" + strings.Join(issueInsertColumns, ", ") + ") VALUES " + rowPlaceholders(len(issueInsertColumns))
+	switch onConflict {
+	case ConflictError:
+	case ConflictSkip:
+		query += " ON DUPLICATE KEY UPDATE id = id"
+	case ConflictReplace:
+		query += " ON DUPLICATE KEY UPDATE " + issueReplaceClause(false)
+	case ConflictReplaceUndelete:
+		query += " ON DUPLICATE KEY UPDATE " + issueReplaceClause(true)
+	default:
+		return 0, fmt.Errorf("unknown OnConflict mode %d", onConflict)
+	}
+	result, err := tx.ExecContext(ctx, query, issueInsertArgs(issue)...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// issueReplaceClause assigns every inserted column except the issue's
+// identity and creation fields, for ConflictReplace. Unless undelete is set,
+// an existing deletion is kept. The assignments run left to right, so the
+// version is compared and bumped first, while the row still has its old
+// values, and deleted_at is assigned after the fields that test it.
+func issueReplaceClause(undelete bool) string {
+	var cols, values []string
+	var deletedAt string
+	for _, col := range issueInsertColumns {
+		value := "VALUES(" + col + ")"
+		switch col {
+		case "id", "created_at", "created_by":
+			continue
+		case "deleted_at":
+			if !undelete {
+				value = "COALESCE(deleted_at, VALUES(deleted_at))"
+			}
+			deletedAt = value
+			continue
+		case "deleted_by", "delete_reason":
+			if !undelete {
+				value = "IF(deleted_at IS NULL, VALUES(" + col + "), " + col + ")"
+			}
+		}
+		cols = append(cols, col)
+		values = append(values, value)
+	}
+	cols = append(cols, "deleted_at")
+	values = append(values, deletedAt)
+
+	unchanged := make([]string, len(cols))
+	sets := make([]string, len(cols))
+	for i, col := range cols {
+		unchanged[i] = col + " <=> " + values[i]
+		sets[i] = col + " = " + values[i]
+	}
+	return "version = IF(" + strings.Join(unchanged, " AND ") + ", version, version + 1), " + strings.Join(sets, ", ")
+}
+
+func scanIssue(ctx context.Context, db querier, id string) (*types.Issue, error) {
 	var issue types.Issue
 	var createdAtStr, updatedAtStr sql.NullString // TEXT columns - must parse manually
 	var closedAt, compactedAt, deletedAt, lastActivity, dueAt, deferUntil sql.NullTime
@@ -780,43 +1163,6 @@ func markDirty(ctx context.Context, tx *sql.Tx, issueID string) error {
 	return err
 }
 
-// generateIssueID generates a unique hash-based ID for an issue
-// Uses adaptive length based on database size and tries multiple nonces on collision
-func generateIssueID(ctx context.Context, tx *sql.Tx, prefix string, issue *types.Issue, actor string) (string, error) {
-	// Get adaptive base length based on current database size
-	baseLength, err := GetAdaptiveIDLengthTx(ctx, tx, prefix)
-	if err != nil {
-		// Fallback to 6 on error
-		baseLength = 6
-	}
-
-	// Try baseLength, baseLength+1, baseLength+2, up to max of 8
-	maxLength := 8
-	if baseLength > maxLength {
-		baseLength = maxLength
-	}
-
-	for length := baseLength; length <= maxLength; length++ {
-		// Try up to 10 nonces at each length
-		for nonce := 0; nonce < 10; nonce++ {
-			candidate := generateHashID(prefix, issue.Title, issue.Description, actor, issue.CreatedAt, length, nonce)
-
-			// Check if this ID already exists
-			var count int
-			err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM issues WHERE id = ?`, candidate).Scan(&count)
-			if err != nil {
-				return "", fmt.Errorf("failed to check for ID collision: %w", err)
-			}
-
-			if count == 0 {
-				return candidate, nil
-			}
-		}
-	}
-
-	return "", fmt.Errorf("failed to generate unique ID after trying lengths %d-%d with 10 nonces each", baseLength, maxLength)
-}
-
 // generateHashID creates a hash-based ID for a top-level issue.
 // Uses base36 encoding (0-9, a-z) for better information density than hex.
 func generateHashID(prefix, title, description, creator string, timestamp time.Time, length, nonce int) string {
@@ -947,5 +1293,3 @@ func formatJSONStringArray(arr []string) string {
 	}
 	return string(data)
 }
-
-
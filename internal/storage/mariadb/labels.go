@@ -2,6 +2,7 @@ package mariadb
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 
@@ -10,9 +11,12 @@ import (
 
 // AddLabel adds a label to an issue
 func (s *MariaDBStore) AddLabel(ctx context.Context, issueID, label, actor string) error {
-	_, err := s.db.ExecContext(ctx, `
-		INSERT IGNORE INTO labels (issue_id, label) VALUES (?, ?)
-	`, issueID, label)
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	err := s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		return attachLabels(ctx, tx, issueID, []string{label})
+	})
 	if err != nil {
 		return fmt.Errorf("failed to add label: %w", err)
 	}
@@ -21,19 +25,118 @@ func (s *MariaDBStore) AddLabel(ctx context.Context, issueID, label, actor strin
 
 // RemoveLabel removes a label from an issue
 func (s *MariaDBStore) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
-	_, err := s.db.ExecContext(ctx, `
-		DELETE FROM labels WHERE issue_id = ? AND label = ?
-	`, issueID, label)
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	err := s.withReplayableRetry(ctx, func() error {
+		return detachLabels(ctx, s.dbOrTx(), issueID, []string{label})
+	})
 	if err != nil {
 		return fmt.Errorf("failed to remove label: %w", err)
 	}
 	return nil
 }
 
+// AddLabels adds several labels to an issue in one transaction. Labels the
+// issue already has, and repeats within labels, are ignored.
+func (s *MariaDBStore) AddLabels(ctx context.Context, issueID string, labels []string, actor string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	labels = uniqueLabels(labels)
+	if len(labels) == 0 {
+		return nil
+	}
+
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		if err := attachLabels(ctx, tx, issueID, labels); err != nil {
+			return fmt.Errorf("failed to add labels: %w", err)
+		}
+		return nil
+	})
+}
+
+// RemoveLabels removes several labels from an issue in one statement.
+// Labels the issue does not have are ignored.
+func (s *MariaDBStore) RemoveLabels(ctx context.Context, issueID string, labels []string, actor string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	labels = uniqueLabels(labels)
+	if len(labels) == 0 {
+		return nil
+	}
+
+	err := s.withReplayableRetry(ctx, func() error {
+		return detachLabels(ctx, s.dbOrTx(), issueID, labels)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove labels: %w", err)
+	}
+	return nil
+}
+
+// attachLabels gives issueID the labels within tx, adding the ones not yet
+// in the labels table. Labels the issue already has are ignored.
+func attachLabels(ctx context.Context, tx *sql.Tx, issueID string, labels []string) error {
+	placeholders := strings.TrimSuffix(strings.Repeat("(?),", len(labels)), ",")
+	args := make([]interface{}, 0, len(labels)+1)
+	for _, label := range labels {
+		args = append(args, label)
+	}
+	// nolint:gosec // G201: placeholders contains only (?) markers, actual values passed via args
+	if _, err := tx.ExecContext(ctx, "INSERT IGNORE INTO labels (name) VALUES "+placeholders, args...); err != nil {
+		return err
+	}
+
+	args = append([]interface{}{issueID}, args...)
+	// nolint:gosec // G201: placeholders contains only ? markers, actual values passed via args
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT IGNORE INTO issue_labels (issue_id, label_id)
+		SELECT ?, id FROM labels WHERE name IN (%s)
+	`, strings.TrimSuffix(strings.Repeat("?,", len(labels)), ",")), args...)
+	return err
+}
+
+// detachLabels removes labels from issueID. The labels stay in the labels
+// table for other issues.
+func detachLabels(ctx context.Context, db querier, issueID string, labels []string) error {
+	args := make([]interface{}, 0, len(labels)+1)
+	args = append(args, issueID)
+	for _, label := range labels {
+		args = append(args, label)
+	}
+	// nolint:gosec // G201: placeholders contains only ? markers, actual values passed via args
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`
+		DELETE FROM issue_labels
+		WHERE issue_id = ? AND label_id IN (SELECT id FROM labels WHERE name IN (%s))
+	`, strings.TrimSuffix(strings.Repeat("?,", len(labels)), ",")), args...)
+	return err
+}
+
+// uniqueLabels returns labels without repeats, in their original order.
+func uniqueLabels(labels []string) []string {
+	seen := make(map[string]bool, len(labels))
+	unique := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if !seen[label] {
+			seen[label] = true
+			unique = append(unique, label)
+		}
+	}
+	return unique
+}
+
 // GetLabels retrieves all labels for an issue
 func (s *MariaDBStore) GetLabels(ctx context.Context, issueID string) ([]string, error) {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT label FROM labels WHERE issue_id = ? ORDER BY label
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
+		SELECT l.name FROM issue_labels il
+		JOIN labels l ON l.id = il.label_id
+		WHERE il.issue_id = ?
+		ORDER BY l.name
 	`, issueID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get labels: %w", err)
@@ -53,6 +156,9 @@ func (s *MariaDBStore) GetLabels(ctx context.Context, issueID string) ([]string,
 
 // GetLabelsForIssues retrieves labels for multiple issues
 func (s *MariaDBStore) GetLabelsForIssues(ctx context.Context, issueIDs []string) (map[string][]string, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 	if len(issueIDs) == 0 {
 		return make(map[string][]string), nil
 	}
@@ -66,12 +172,13 @@ func (s *MariaDBStore) GetLabelsForIssues(ctx context.Context, issueIDs []string
 
 	// nolint:gosec // G201: placeholders contains only ? markers, actual values passed via args
 	query := fmt.Sprintf(`
-		SELECT issue_id, label FROM labels
-		WHERE issue_id IN (%s)
-		ORDER BY issue_id, label
+		SELECT il.issue_id, l.name FROM issue_labels il
+		JOIN labels l ON l.id = il.label_id
+		WHERE il.issue_id IN (%s)
+		ORDER BY il.issue_id, l.name
 	`, strings.Join(placeholders, ","))
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.queryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get labels for issues: %w", err)
 	}
@@ -90,10 +197,14 @@ func (s *MariaDBStore) GetLabelsForIssues(ctx context.Context, issueIDs []string
 
 // GetIssuesByLabel retrieves all issues with a specific label
 func (s *MariaDBStore) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.queryContext(ctx, `
 		SELECT i.id FROM issues i
-		JOIN labels l ON i.id = l.issue_id
-		WHERE l.label = ?
+		JOIN issue_labels il ON i.id = il.issue_id
+		JOIN labels l ON l.id = il.label_id
+		WHERE l.name = ? AND `+notSoftDeletedAs("i")+`
 		ORDER BY i.priority ASC, i.created_at DESC
 	`, label)
 	if err != nil {
@@ -118,4 +229,13 @@ func (s *MariaDBStore) GetIssuesByLabel(ctx context.Context, label string) ([]*t
 	return issues, rows.Err()
 }
 
-
+// ListIssuesByLabel returns the issues with the given label, in the order of
+// SearchIssues. Unlike GetIssuesByLabel, which loads the issues one by one,
+// it reads them in a single query, finding the label by name and its issues
+// through the issue_labels label index.
+func (s *MariaDBStore) ListIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	return s.SearchIssues(ctx, "", types.IssueFilter{Labels: []string{label}})
+}
@@ -0,0 +1,72 @@
+package mariadb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAddRemoveLabels(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	issues := newBatchIssues(3, "Labeled issue")
+	if err := store.CreateIssuesBatch(ctx, issues, "tester"); err != nil {
+		t.Fatalf("CreateIssuesBatch failed: %v", err)
+	}
+	a, b := issues[0].ID, issues[1].ID
+
+	if err := store.AddLabels(ctx, a, []string{"backend", "urgent", "backend"}, "tester"); err != nil {
+		t.Fatalf("AddLabels failed: %v", err)
+	}
+	// Labels already present are ignored
+	if err := store.AddLabels(ctx, a, []string{"urgent", "ui"}, "tester"); err != nil {
+		t.Fatalf("AddLabels (again) failed: %v", err)
+	}
+	if err := store.AddLabels(ctx, b, []string{"urgent"}, "tester"); err != nil {
+		t.Fatalf("AddLabels failed: %v", err)
+	}
+
+	if got, err := store.GetLabels(ctx, a); err != nil || !reflect.DeepEqual(got, []string{"backend", "ui", "urgent"}) {
+		t.Errorf("GetLabels(a) = %v, %v; want [backend ui urgent]", got, err)
+	}
+	labelIDs := func(label string) []string {
+		t.Helper()
+		found, err := store.ListIssuesByLabel(ctx, label)
+		if err != nil {
+			t.Fatalf("ListIssuesByLabel(%s) failed: %v", label, err)
+		}
+		ids := make([]string, len(found))
+		for i, issue := range found {
+			ids[i] = issue.ID
+		}
+		return ids
+	}
+	if got := labelIDs("urgent"); len(got) != 2 {
+		t.Errorf("issues labeled urgent = %v, want %s and %s", got, a, b)
+	}
+
+	if err := store.RemoveLabels(ctx, a, []string{"urgent", "backend", "missing"}, "tester"); err != nil {
+		t.Fatalf("RemoveLabels failed: %v", err)
+	}
+	if got, err := store.GetLabels(ctx, a); err != nil || !reflect.DeepEqual(got, []string{"ui"}) {
+		t.Errorf("GetLabels(a) after removal = %v, %v; want [ui]", got, err)
+	}
+	if got := labelIDs("urgent"); !reflect.DeepEqual(got, []string{b}) {
+		t.Errorf("issues labeled urgent after removal = %v, want [%s]", got, b)
+	}
+
+	if got := labelIDs("missing"); len(got) != 0 {
+		t.Errorf("issues labeled missing = %v, want none", got)
+	}
+
+	// Empty lists are no-ops
+	if err := store.AddLabels(ctx, a, nil, "tester"); err != nil {
+		t.Errorf("AddLabels(nil) = %v", err)
+	}
+	if err := store.RemoveLabels(ctx, a, nil, "tester"); err != nil {
+		t.Errorf("RemoveLabels(nil) = %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Optimize runs OPTIMIZE TABLE on each Beads table, reclaiming the space left
+// by deleted and updated rows and refreshing index statistics. InnoDB
+// rebuilds each table online, so reads and writes continue meanwhile, but a
+// rebuild takes I/O and temporary disk space about the size of the table: on
+// busy servers, run it off-peak (a maintenance schedule calling it nightly or
+// weekly is typical). Tables are processed one at a time, and Optimize stops
+// between tables once ctx is done. Cancelling ctx during a table abandons its
+// connection; the server finishes that table's rebuild on its own. A table is
+// never sent OPTIMIZE twice, even when the connection is lost.
+//
+// It cannot run inside WithTx, since OPTIMIZE TABLE commits implicitly.
+func (s *MariaDBStore) Optimize(ctx context.Context) error {
+	return s.maintainTables(ctx, "OPTIMIZE")
+}
+
+// Analyze runs ANALYZE TABLE on each Beads table, refreshing the index
+// statistics the optimizer plans queries with. It is much cheaper than
+// Optimize, which also analyzes, and reclaims no space. Like Optimize, it
+// stops between tables once ctx is done.
+func (s *MariaDBStore) Analyze(ctx context.Context) error {
+	return s.maintainTables(ctx, "ANALYZE")
+}
+
+// maintainTables runs the table maintenance statement op (OPTIMIZE or ANALYZE)
+// on each table in tableNames, on a connection of its own (see
+// openMaintenancePool). Only getting the connection is retried: a statement
+// whose connection is lost may still be running on the server, and sending
+// it again would start the rebuild over.
+func (s *MariaDBStore) maintainTables(ctx context.Context, op string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	if s.tx != nil {
+		return fmt.Errorf("cannot %s tables inside a WithTx transaction", strings.ToLower(op))
+	}
+	done, err := s.track()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	db, err := s.openMaintenancePool()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	var conn *sql.Conn
+	err = s.withRetry(ctx, func() error {
+		var err error
+		conn, err = db.Conn(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect for table maintenance: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	for _, table := range tableNames {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := maintainTable(ctx, conn, op, table); err != nil {
+			return fmt.Errorf("failed to %s table %s: %w", strings.ToLower(op), s.tablePrefix+table, err)
+		}
+	}
+	s.log().Info("MariaDB table maintenance finished", "database", s.dbName, "operation", op, "tables", len(tableNames))
+	return nil
+}
+
+// openMaintenancePool opens a pool of one connection with the store's
+// credentials and connection options but no Config.IOTimeout, since
+// OPTIMIZE TABLE on a large table sends nothing until it has finished.
+func (s *MariaDBStore) openMaintenancePool() (*sql.DB, error) {
+	mc, err := mysql.ParseDSN(s.currentConnStr())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse connection string: %w", err)
+	}
+	mc.ReadTimeout = 0
+	mc.WriteTimeout = 0
+	db, err := openDB(mc.FormatDSN(), s.conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open maintenance connection: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// maintainTable runs op on table. The statement reports problems as rows of
+// its result set rather than as an error, so those are read and returned.
+// InnoDB's note that it recreates the table instead of optimizing it is
+// expected and ignored.
+func maintainTable(ctx context.Context, conn *sql.Conn, op, table string) error {
+	rows, err := conn.QueryContext(ctx, op+" TABLE "+table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var msgs []string
+	for rows.Next() {
+		var name, operation, msgType, msgText string
+		if err := rows.Scan(&name, &operation, &msgType, &msgText); err != nil {
+			return fmt.Errorf("failed to scan %s result: %w", op, err)
+		}
+		if strings.EqualFold(msgType, "error") {
+			msgs = append(msgs, msgText)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(msgs) > 0 {
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package mariadb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestOptimizeAndAnalyze(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	// Leave some dead rows behind
+	for i := 0; i < 20; i++ {
+		issue := &types.Issue{ID: fmt.Sprintf("test-%d", i), Title: "Churn", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if i%2 == 0 {
+			if err := store.DeleteIssue(ctx, issue.ID); err != nil {
+				t.Fatalf("DeleteIssue failed: %v", err)
+			}
+		}
+	}
+
+	if err := store.Optimize(ctx); err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+	if err := store.Analyze(ctx); err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	// The store keeps working on the rebuilt tables
+	issue, err := store.GetIssue(ctx, "test-1")
+	if err != nil || issue == nil {
+		t.Errorf("GetIssue after Optimize = %v, %v", issue, err)
+	}
+
+	err = store.WithTx(ctx, func(tx *MariaDBStore) error {
+		return tx.Optimize(ctx)
+	})
+	if err == nil {
+		t.Error("Optimize inside WithTx should fail")
+	}
+
+	cancelled, cancelNow := context.WithCancel(ctx)
+	cancelNow()
+	if err := store.Optimize(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Optimize with a cancelled context = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,82 @@
+package mariadb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// testTimeout is the maximum time for any single test operation.
+const testTimeout = 30 * time.Second
+
+// testContext returns a context with timeout for test operations
+func testContext(t testing.TB) (context.Context, context.CancelFunc) {
+	t.Helper()
+	return context.WithTimeout(context.Background(), testTimeout)
+}
+
+// testDatabaseName returns a unique throwaway database name
+func testDatabaseName(t testing.TB) string {
+	t.Helper()
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("failed to generate database name: %v", err)
+	}
+	return "beads_test_" + hex.EncodeToString(b)
+}
+
+// setupTestStore creates a store backed by a throwaway database on a local
+// MariaDB server (127.0.0.1:3306, password from BEADS_MARIADB_PASSWORD).
+// The test is skipped if no server is reachable.
+func setupTestStore(t testing.TB) (*MariaDBStore, func()) {
+	t.Helper()
+	return setupTestStoreWithConfig(t, &Config{})
+}
+
+// setupTestStoreWithConfig is like setupTestStore but starts from cfg.
+// cfg.Database is overwritten with a unique name.
+func setupTestStoreWithConfig(t testing.TB, cfg *Config) (*MariaDBStore, func()) {
+	t.Helper()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	cfg.Database = testDatabaseName(t)
+	store, err := New(ctx, cfg)
+	if err != nil {
+		// Requires a running MariaDB server - skip if unavailable
+		if serverUnreachable(err) {
+			t.Skipf("MariaDB server unavailable: %v", err)
+		}
+		t.Fatalf("failed to create MariaDB store: %v", err)
+	}
+
+	if !cfg.ReadOnly {
+		if err := store.SetConfig(ctx, "issue_prefix", "test"); err != nil {
+			store.Close()
+			t.Fatalf("failed to set prefix: %v", err)
+		}
+	}
+
+	cleanup := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+		defer cancel()
+		if db := store.UnderlyingDB(); db != nil {
+			_, _ = db.ExecContext(ctx, "DROP DATABASE IF EXISTS "+quoteIdentifier(cfg.Database))
+		}
+		store.Close()
+	}
+
+	return store, cleanup
+}
+
+// serverUnreachable reports whether err comes from failing to dial the
+// server, as when nothing listens on its port or socket.
+func serverUnreachable(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
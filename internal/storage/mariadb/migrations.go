@@ -1,37 +1,228 @@
 package mariadb
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/go-sql-driver/mysql"
 )
 
 // Migration represents a single schema migration for MariaDB.
 type Migration struct {
 	Name string
-	Func func(*sql.DB) error
+	Func func(context.Context, *sql.DB) error
+	Down func(context.Context, *sql.DB) error // Reverts Func; nil means the migration is irreversible
+
+	// Plan returns the DDL statements Func would execute against db, without
+	// executing them; nil means the migration does not support dry runs.
+	Plan func(context.Context, *sql.DB) ([]string, error)
 }
 
+// ErrIrreversibleMigration is returned by RollbackMigration for migrations without a Down.
+var ErrIrreversibleMigration = errors.New("irreversible migration")
+
 // migrationsList is the ordered list of all MariaDB schema migrations.
 // Each migration must be idempotent - safe to run multiple times.
 // New migrations should be appended to the end of this list.
 var migrationsList = []Migration{
-	{"wisp_type_column", migrateWispTypeColumn},
-	{"spec_id_column", migrateSpecIDColumn},
+	{Name: "wisp_type_column", Func: migrateWispTypeColumn, Down: rollbackWispTypeColumn, Plan: planWispTypeColumn},
+	{Name: "spec_id_column", Func: migrateSpecIDColumn, Down: rollbackSpecIDColumn, Plan: planSpecIDColumn},
+	{Name: "issue_type_index", Func: migrateIssueTypeIndex, Down: rollbackIssueTypeIndex, Plan: planIssueTypeIndex},
+	// deleted_at also records tombstone deletion times, so it is never dropped
+	{Name: "deleted_at_column", Func: migrateDeletedAtColumn, Plan: planDeletedAtColumn},
+	{Name: "issue_audit_table", Func: migrateIssueAuditTable, Down: rollbackIssueAuditTable, Plan: planIssueAuditTable},
+	{Name: "version_column", Func: migrateVersionColumn, Down: rollbackVersionColumn, Plan: planVersionColumn},
+	{Name: "updated_at_index", Func: migrateUpdatedAtIndex, Down: rollbackUpdatedAtIndex, Plan: planUpdatedAtIndex},
+	// The original out-of-range priorities are not kept, so there is no Down
+	{Name: "priority_range", Func: migratePriorityRange, Plan: planPriorityRange},
+	{Name: "deleted_issues_table", Func: migrateDeletedIssuesTable, Down: rollbackDeletedIssuesTable, Plan: planDeletedIssuesTable},
+	{Name: "labels_tables", Func: migrateLabelsTables, Down: rollbackLabelsTables, Plan: planLabelsTables},
 }
 
+// schemaMigrationsTable records which migrations have been applied, so
+// RunMigrations can skip them without re-running their precondition checks.
+const schemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    name VARCHAR(255) PRIMARY KEY,
+    applied_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+)`
+
+// errNoSuchTable is the MariaDB error number for a missing table (ER_NO_SUCH_TABLE).
+const errNoSuchTable = 1146
+
 // RunMigrations executes all registered MariaDB migrations in order.
-// Each migration is idempotent and checks whether its changes have
-// already been applied before making modifications.
-func RunMigrations(db *sql.DB) error {
+// Migrations recorded in schema_migrations are skipped. The rest still check
+// whether their changes have already been applied before making modifications,
+// so databases migrated before schema_migrations existed are handled safely.
+// Cancelling ctx aborts the running statement and skips the remaining migrations.
+func RunMigrations(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, schemaMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := AppliedMigrations(ctx, db)
+	if err != nil {
+		return err
+	}
+	done := make(map[string]bool, len(applied))
+	for _, name := range applied {
+		done[name] = true
+	}
+
 	for _, m := range migrationsList {
-		if err := m.Func(db); err != nil {
+		if done[m.Name] {
+			continue
+		}
+		if err := m.Func(ctx, db); err != nil {
 			return fmt.Errorf("mariadb migration %q failed: %w", m.Name, err)
 		}
+		if _, err := db.ExecContext(ctx, "INSERT IGNORE INTO schema_migrations (name) VALUES (?)", m.Name); err != nil {
+			return fmt.Errorf("failed to record migration %q: %w", m.Name, err)
+		}
+	}
+	return nil
+}
+
+// Migrate applies pending migrations to the store's database without
+// re-running schema creation, e.g. for a `bd migrate` command.
+// It refuses to run on a read-only store.
+func (s *MariaDBStore) Migrate(ctx context.Context) error {
+	if s.IsClosed() {
+		return ErrStoreClosed
+	}
+	if s.readOnly {
+		return fmt.Errorf("cannot run migrations on database %s: %w", s.dbName, ErrReadOnly)
+	}
+	db, done, err := s.acquire()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if err := RunMigrations(ctx, db); err != nil {
+		return fmt.Errorf("failed to run mariadb migrations: %w", err)
+	}
+	return nil
+}
+
+// MigrationStatus splits the registered migrations, in migrationsList order,
+// into those recorded as applied to the store's database and those still
+// pending, e.g. for a `bd status` command to warn about pending migrations
+// before they cause errors. If schema_migrations does not exist yet, every
+// migration is pending. Recorded names this binary does not know are ignored.
+func (s *MariaDBStore) MigrationStatus(ctx context.Context) (applied, pending []string, err error) {
+	db, release, err := s.acquire()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	recorded, err := AppliedMigrations(ctx, db)
+	if err != nil {
+		return nil, nil, err
+	}
+	done := make(map[string]bool, len(recorded))
+	for _, name := range recorded {
+		done[name] = true
+	}
+	for _, m := range migrationsList {
+		if done[m.Name] {
+			applied = append(applied, m.Name)
+		} else {
+			pending = append(pending, m.Name)
+		}
+	}
+	return applied, pending, nil
+}
+
+// RunMigrationsDryRun returns the DDL statements RunMigrations would execute,
+// in order, without executing anything. Migrations recorded in
+// schema_migrations are skipped, as RunMigrations would skip them.
+func RunMigrationsDryRun(ctx context.Context, db *sql.DB) ([]string, error) {
+	applied, err := AppliedMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[string]bool, len(applied))
+	for _, name := range applied {
+		done[name] = true
+	}
+
+	var stmts []string
+	for _, m := range migrationsList {
+		if done[m.Name] {
+			continue
+		}
+		if m.Plan == nil {
+			return nil, fmt.Errorf("mariadb migration %q does not support dry run", m.Name)
+		}
+		planned, err := m.Plan(ctx, db)
+		if err != nil {
+			return nil, fmt.Errorf("mariadb migration %q dry run failed: %w", m.Name, err)
+		}
+		stmts = append(stmts, planned...)
+	}
+	return stmts, nil
+}
+
+// RollbackMigration runs the Down of the named migration and removes it from
+// schema_migrations, so the next RunMigrations applies it again.
+// Intended for staging; views over the affected tables are rebuilt on the next New.
+func RollbackMigration(ctx context.Context, db *sql.DB, name string) error {
+	var m *Migration
+	for i := range migrationsList {
+		if migrationsList[i].Name == name {
+			m = &migrationsList[i]
+			break
+		}
+	}
+	if m == nil {
+		return fmt.Errorf("unknown mariadb migration %q", name)
+	}
+	if m.Down == nil {
+		return fmt.Errorf("%w: mariadb migration %q has no Down", ErrIrreversibleMigration, name)
+	}
+
+	if _, err := db.ExecContext(ctx, schemaMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	if err := m.Down(ctx, db); err != nil {
+		return fmt.Errorf("mariadb migration %q rollback failed: %w", name, err)
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM schema_migrations WHERE name = ?", name); err != nil {
+		return fmt.Errorf("failed to unrecord migration %q: %w", name, err)
 	}
 	return nil
 }
 
+// AppliedMigrations returns the names of migrations recorded in
+// schema_migrations, in the order they were applied. It returns an empty
+// list if the table does not exist yet.
+func AppliedMigrations(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM schema_migrations ORDER BY applied_at, name")
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == errNoSuchTable {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan migration name: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
 // ListMigrations returns the names of all registered migrations.
 func ListMigrations() []string {
 	names := make([]string, len(migrationsList))
@@ -42,59 +233,418 @@ func ListMigrations() []string {
 }
 
 // migrateWispTypeColumn adds the wisp_type column if it doesn't exist
-func migrateWispTypeColumn(db *sql.DB) error {
-	// Check if column exists
+func migrateWispTypeColumn(ctx context.Context, db *sql.DB) error {
+	return applyPlan(ctx, db, planWispTypeColumn)
+}
+
+// planWispTypeColumn returns the DDL that adds the wisp_type column, if missing
+func planWispTypeColumn(ctx context.Context, db *sql.DB) ([]string, error) {
+	exists, err := columnExists(ctx, db, "issues", "wisp_type")
+	if err != nil {
+		return nil, fmt.Errorf("checking wisp_type column: %w", err)
+	}
+	if exists {
+		return nil, nil
+	}
+	return []string{"ALTER TABLE issues ADD COLUMN wisp_type VARCHAR(32) DEFAULT ''"}, nil
+}
+
+// migrateSpecIDColumn adds the spec_id column and its index if they don't exist
+func migrateSpecIDColumn(ctx context.Context, db *sql.DB) error {
+	return applyPlan(ctx, db, planSpecIDColumn)
+}
+
+// planSpecIDColumn returns the DDL that adds the spec_id column and index, if missing
+func planSpecIDColumn(ctx context.Context, db *sql.DB) ([]string, error) {
+	var stmts []string
+
+	exists, err := columnExists(ctx, db, "issues", "spec_id")
+	if err != nil {
+		return nil, fmt.Errorf("checking spec_id column: %w", err)
+	}
+	if !exists {
+		stmts = append(stmts, "ALTER TABLE issues ADD COLUMN spec_id VARCHAR(1024)")
+	}
+
+	exists, err = indexExists(ctx, db, "issues", "idx_issues_spec_id")
+	if err != nil {
+		return nil, fmt.Errorf("checking spec_id index: %w", err)
+	}
+	if !exists {
+		stmts = append(stmts, "CREATE INDEX idx_issues_spec_id ON issues(spec_id)")
+	}
+	return stmts, nil
+}
+
+// migrateIssueTypeIndex adds the issue_type index if it doesn't exist.
+// CREATE TABLE IF NOT EXISTS won't add it to tables created before it was in the schema.
+func migrateIssueTypeIndex(ctx context.Context, db *sql.DB) error {
+	return applyPlan(ctx, db, planIssueTypeIndex)
+}
+
+// planIssueTypeIndex returns the DDL that adds the issue_type index, if missing
+func planIssueTypeIndex(ctx context.Context, db *sql.DB) ([]string, error) {
+	exists, err := indexExists(ctx, db, "issues", "idx_issues_issue_type")
+	if err != nil {
+		return nil, fmt.Errorf("checking issue_type index: %w", err)
+	}
+	if exists {
+		return nil, nil
+	}
+	return []string{"CREATE INDEX idx_issues_issue_type ON issues(issue_type)"}, nil
+}
+
+// migrateDeletedAtColumn adds the deleted_at column used by soft deletes if it doesn't exist
+func migrateDeletedAtColumn(ctx context.Context, db *sql.DB) error {
+	return applyPlan(ctx, db, planDeletedAtColumn)
+}
+
+// planDeletedAtColumn returns the DDL that adds the deleted_at column, if missing
+func planDeletedAtColumn(ctx context.Context, db *sql.DB) ([]string, error) {
+	exists, err := columnExists(ctx, db, "issues", "deleted_at")
+	if err != nil {
+		return nil, fmt.Errorf("checking deleted_at column: %w", err)
+	}
+	if exists {
+		return nil, nil
+	}
+	return []string{"ALTER TABLE issues ADD COLUMN deleted_at DATETIME"}, nil
+}
+
+// migrateIssueAuditTable creates the issue_audit table if it doesn't exist
+func migrateIssueAuditTable(ctx context.Context, db *sql.DB) error {
+	return applyPlan(ctx, db, planIssueAuditTable)
+}
+
+// planIssueAuditTable returns the DDL that creates the issue_audit table, if missing
+func planIssueAuditTable(ctx context.Context, db *sql.DB) ([]string, error) {
+	exists, err := tableExists(ctx, db, "issue_audit")
+	if err != nil {
+		return nil, fmt.Errorf("checking issue_audit table: %w", err)
+	}
+	if exists {
+		return nil, nil
+	}
+	return []string{strings.TrimSpace(issueAuditTable)}, nil
+}
+
+// migrateVersionColumn adds the version column used by UpdateIssueAtVersion if it doesn't exist
+func migrateVersionColumn(ctx context.Context, db *sql.DB) error {
+	return applyPlan(ctx, db, planVersionColumn)
+}
+
+// planVersionColumn returns the DDL that adds the version column, if missing
+func planVersionColumn(ctx context.Context, db *sql.DB) ([]string, error) {
+	exists, err := columnExists(ctx, db, "issues", "version")
+	if err != nil {
+		return nil, fmt.Errorf("checking version column: %w", err)
+	}
+	if exists {
+		return nil, nil
+	}
+	return []string{"ALTER TABLE issues ADD COLUMN version BIGINT NOT NULL DEFAULT 1"}, nil
+}
+
+// migratePriorityRange clamps priorities outside MinPriority..MaxPriority,
+// written before they were validated, to the nearest valid rank
+func migratePriorityRange(ctx context.Context, db *sql.DB) error {
+	return applyPlan(ctx, db, planPriorityRange)
+}
+
+// planPriorityRange returns the UPDATE that clamps out-of-range priorities,
+// if any issue has one
+func planPriorityRange(ctx context.Context, db *sql.DB) ([]string, error) {
+	where := fmt.Sprintf("priority < %d OR priority > %d", MinPriority, MaxPriority)
 	var count int
-	err := db.QueryRow(`
-		SELECT COUNT(*) 
-		FROM information_schema.columns 
-		WHERE table_schema = DATABASE() 
-		AND table_name = 'issues' 
-		AND column_name = 'wisp_type'
-	`).Scan(&count)
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM issues WHERE "+where).Scan(&count); err != nil {
+		return nil, fmt.Errorf("checking priorities: %w", err)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	return []string{fmt.Sprintf("UPDATE issues SET priority = LEAST(GREATEST(priority, %d), %d) WHERE %s", MinPriority, MaxPriority, where)}, nil
+}
+
+// migrateDeletedIssuesTable creates the deleted_issues table if it doesn't exist
+func migrateDeletedIssuesTable(ctx context.Context, db *sql.DB) error {
+	return applyPlan(ctx, db, planDeletedIssuesTable)
+}
+
+// planDeletedIssuesTable returns the DDL that creates the deleted_issues table, if missing
+func planDeletedIssuesTable(ctx context.Context, db *sql.DB) ([]string, error) {
+	exists, err := tableExists(ctx, db, "deleted_issues")
 	if err != nil {
-		return fmt.Errorf("checking wisp_type column: %w", err)
+		return nil, fmt.Errorf("checking deleted_issues table: %w", err)
+	}
+	if exists {
+		return nil, nil
+	}
+	return []string{strings.TrimSpace(deletedIssuesTable)}, nil
+}
+
+// migrateLabelsTables moves the labels of a per-issue labels table into the
+// normalized labels and issue_labels tables
+func migrateLabelsTables(ctx context.Context, db *sql.DB) error {
+	return applyPlan(ctx, db, planLabelsTables)
+}
+
+// planLabelsTables returns the statements that convert a labels table of the
+// old (issue_id, label) form: each distinct label gets a row in a new labels
+// table, each issue's label a row in issue_labels, and the new table then
+// replaces the old one. Nothing is returned once labels has the new form,
+// except dropping an old table left behind by an interrupted run.
+func planLabelsTables(ctx context.Context, db *sql.DB) ([]string, error) {
+	// The working tables are not Beads tables, so the rewriter leaves their
+	// names alone and they carry the prefix explicitly
+	prefix := tablePrefixOf(db)
+	staging, old := prefix+"labels_new", prefix+"labels_old"
+
+	legacy, err := columnExists(ctx, db, "labels", "issue_id")
+	if err != nil {
+		return nil, fmt.Errorf("checking labels table: %w", err)
+	}
+	if !legacy {
+		leftover, err := tableExists(ctx, db, "labels_old")
+		if err != nil {
+			return nil, fmt.Errorf("checking labels_old table: %w", err)
+		}
+		if leftover {
+			return []string{"DROP TABLE " + old}, nil
+		}
+		return nil, nil
 	}
-	if count > 0 {
-		return nil // Column already exists
+	return []string{
+		"DROP TABLE IF EXISTS " + staging,
+		"DELETE FROM issue_labels",
+		"CREATE TABLE " + staging + " (id BIGINT AUTO_INCREMENT PRIMARY KEY, name VARCHAR(255) NOT NULL, UNIQUE INDEX idx_labels_name (name))",
+		"INSERT IGNORE INTO " + staging + " (name) SELECT DISTINCT label FROM labels ORDER BY label",
+		"INSERT INTO issue_labels (issue_id, label_id) SELECT l.issue_id, (SELECT n.id FROM " + staging + " n WHERE n.name = l.label) FROM labels l",
+		"RENAME TABLE labels TO " + old + ", " + staging + " TO " + prefix + "labels",
+		"DROP TABLE " + old,
+	}, nil
+}
+
+// applyPlan executes the statements returned by plan. Errors reporting that a
+// column or index already exists are ignored, since a concurrent process may
+// have applied the same migration between the check and the DDL.
+func applyPlan(ctx context.Context, db *sql.DB, plan func(context.Context, *sql.DB) ([]string, error)) error {
+	stmts, err := plan(ctx, db)
+	if err != nil {
+		return err
 	}
+	for _, stmt := range stmts {
+		if _, err := db.ExecContext(ctx, stmt); err != nil && !isAlreadyExistsError(err) {
+			return fmt.Errorf("executing %q: %w", stmt, err)
+		}
+	}
+	return nil
+}
 
-	_, err = db.Exec("ALTER TABLE issues ADD COLUMN wisp_type VARCHAR(32) DEFAULT ''")
-	if err != nil && !strings.Contains(strings.ToLower(err.Error()), "duplicate column") {
-		return fmt.Errorf("adding wisp_type column: %w", err)
+// isAlreadyExistsError returns true if err reports a duplicate column or index
+func isAlreadyExistsError(err error) bool {
+	errLower := strings.ToLower(err.Error())
+	return strings.Contains(errLower, "duplicate") || strings.Contains(errLower, "already exists")
+}
+
+// rollbackWispTypeColumn drops the wisp_type column if it exists
+func rollbackWispTypeColumn(ctx context.Context, db *sql.DB) error {
+	exists, err := columnExists(ctx, db, "issues", "wisp_type")
+	if err != nil {
+		return fmt.Errorf("checking wisp_type column: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+	if _, err := db.ExecContext(ctx, "ALTER TABLE issues DROP COLUMN wisp_type"); err != nil {
+		return fmt.Errorf("dropping wisp_type column: %w", err)
 	}
 	return nil
 }
 
-// migrateSpecIDColumn adds the spec_id column if it doesn't exist
-func migrateSpecIDColumn(db *sql.DB) error {
-	// Check if column exists
-	var count int
-	err := db.QueryRow(`
-		SELECT COUNT(*) 
-		FROM information_schema.columns 
-		WHERE table_schema = DATABASE() 
-		AND table_name = 'issues' 
-		AND column_name = 'spec_id'
-	`).Scan(&count)
+// rollbackSpecIDColumn drops the spec_id index and column if they exist
+func rollbackSpecIDColumn(ctx context.Context, db *sql.DB) error {
+	exists, err := indexExists(ctx, db, "issues", "idx_issues_spec_id")
+	if err != nil {
+		return fmt.Errorf("checking spec_id index: %w", err)
+	}
+	if exists {
+		if _, err := db.ExecContext(ctx, "DROP INDEX idx_issues_spec_id ON issues"); err != nil {
+			return fmt.Errorf("dropping spec_id index: %w", err)
+		}
+	}
+
+	exists, err = columnExists(ctx, db, "issues", "spec_id")
 	if err != nil {
 		return fmt.Errorf("checking spec_id column: %w", err)
 	}
-	if count > 0 {
-		return nil // Column already exists
+	if !exists {
+		return nil
+	}
+	if _, err := db.ExecContext(ctx, "ALTER TABLE issues DROP COLUMN spec_id"); err != nil {
+		return fmt.Errorf("dropping spec_id column: %w", err)
 	}
+	return nil
+}
 
-	_, err = db.Exec("ALTER TABLE issues ADD COLUMN spec_id VARCHAR(1024)")
-	if err != nil && !strings.Contains(strings.ToLower(err.Error()), "duplicate column") {
-		return fmt.Errorf("adding spec_id column: %w", err)
+// rollbackIssueTypeIndex drops the issue_type index if it exists
+func rollbackIssueTypeIndex(ctx context.Context, db *sql.DB) error {
+	exists, err := indexExists(ctx, db, "issues", "idx_issues_issue_type")
+	if err != nil {
+		return fmt.Errorf("checking issue_type index: %w", err)
 	}
-	
-	// Add index for spec_id
-	_, err = db.Exec("CREATE INDEX idx_issues_spec_id ON issues(spec_id)")
-	if err != nil && !strings.Contains(strings.ToLower(err.Error()), "duplicate") && 
-		!strings.Contains(strings.ToLower(err.Error()), "already exists") {
-		return fmt.Errorf("creating spec_id index: %w", err)
+	if !exists {
+		return nil
+	}
+	if _, err := db.ExecContext(ctx, "DROP INDEX idx_issues_issue_type ON issues"); err != nil {
+		return fmt.Errorf("dropping issue_type index: %w", err)
 	}
 	return nil
 }
 
+// rollbackIssueAuditTable drops the issue_audit table, discarding its history
+func rollbackIssueAuditTable(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, "DROP TABLE IF EXISTS issue_audit"); err != nil {
+		return fmt.Errorf("dropping issue_audit table: %w", err)
+	}
+	return nil
+}
+
+// rollbackDeletedIssuesTable drops the deleted_issues table; Watch then no
+// longer reports hard deletes made before it was dropped
+func rollbackDeletedIssuesTable(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, "DROP TABLE IF EXISTS deleted_issues"); err != nil {
+		return fmt.Errorf("dropping deleted_issues table: %w", err)
+	}
+	return nil
+}
+
+// rollbackLabelsTables converts the labels back to a per-issue labels table
+// of the (issue_id, label) form, leaving issue_labels empty
+func rollbackLabelsTables(ctx context.Context, db *sql.DB) error {
+	legacy, err := columnExists(ctx, db, "labels", "issue_id")
+	if err != nil {
+		return fmt.Errorf("checking labels table: %w", err)
+	}
+	if legacy {
+		return nil
+	}
+
+	// The old index and foreign key are no longer in the schema, so the
+	// rewriter does not prefix them either
+	prefix := tablePrefixOf(db)
+	staging, old := prefix+"labels_legacy", prefix+"labels_old"
+	stmts := []string{
+		"DROP TABLE IF EXISTS " + staging,
+		"CREATE TABLE " + staging + " (issue_id VARCHAR(255) NOT NULL, label VARCHAR(255) NOT NULL, PRIMARY KEY (issue_id, label), " +
+			"INDEX " + prefix + "idx_labels_label (label), " +
+			"CONSTRAINT " + prefix + "fk_labels_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE)",
+		"INSERT IGNORE INTO " + staging + " (issue_id, label) SELECT il.issue_id, l.name FROM issue_labels il JOIN labels l ON l.id = il.label_id",
+		"DELETE FROM issue_labels",
+		"RENAME TABLE labels TO " + old + ", " + staging + " TO " + prefix + "labels",
+		"DROP TABLE " + old,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("executing %q: %w", stmt, err)
+		}
+	}
+	return nil
+}
+
+// migrateUpdatedAtIndex adds the (updated_at, id) index used by
+// ListIssuesModifiedSince and Watch if it doesn't exist
+func migrateUpdatedAtIndex(ctx context.Context, db *sql.DB) error {
+	return applyPlan(ctx, db, planUpdatedAtIndex)
+}
+
+// planUpdatedAtIndex returns the DDL that adds the updated_at index, if missing
+func planUpdatedAtIndex(ctx context.Context, db *sql.DB) ([]string, error) {
+	exists, err := indexExists(ctx, db, "issues", "idx_issues_updated_at")
+	if err != nil {
+		return nil, fmt.Errorf("checking updated_at index: %w", err)
+	}
+	if exists {
+		return nil, nil
+	}
+	return []string{"CREATE INDEX idx_issues_updated_at ON issues(updated_at, id)"}, nil
+}
+
+// rollbackUpdatedAtIndex drops the updated_at index if it exists
+func rollbackUpdatedAtIndex(ctx context.Context, db *sql.DB) error {
+	exists, err := indexExists(ctx, db, "issues", "idx_issues_updated_at")
+	if err != nil {
+		return fmt.Errorf("checking updated_at index: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+	if _, err := db.ExecContext(ctx, "DROP INDEX idx_issues_updated_at ON issues"); err != nil {
+		return fmt.Errorf("dropping updated_at index: %w", err)
+	}
+	return nil
+}
+
+// rollbackVersionColumn drops the version column if it exists
+func rollbackVersionColumn(ctx context.Context, db *sql.DB) error {
+	exists, err := columnExists(ctx, db, "issues", "version")
+	if err != nil {
+		return fmt.Errorf("checking version column: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+	if _, err := db.ExecContext(ctx, "ALTER TABLE issues DROP COLUMN version"); err != nil {
+		return fmt.Errorf("dropping version column: %w", err)
+	}
+	return nil
+}
+
+// tableExists reports whether table exists in the current database.
+// table is unprefixed; the pool's table prefix is applied.
+func tableExists(ctx context.Context, db *sql.DB, table string) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM information_schema.tables
+		WHERE table_schema = DATABASE()
+		AND table_name = ?
+	`, tablePrefixOf(db)+table).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// columnExists reports whether table has column in the current database.
+// table is unprefixed; the pool's table prefix is applied.
+func columnExists(ctx context.Context, db *sql.DB, table, column string) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM information_schema.columns
+		WHERE table_schema = DATABASE()
+		AND table_name = ?
+		AND column_name = ?
+	`, tablePrefixOf(db)+table, column).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// indexExists reports whether table has an index named index in the current database.
+// table and index are unprefixed; the pool's table prefix is applied.
+func indexExists(ctx context.Context, db *sql.DB, table, index string) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM information_schema.statistics
+		WHERE table_schema = DATABASE()
+		AND table_name = ?
+		AND index_name = ?
+	`, tablePrefixOf(db)+table, tablePrefixOf(db)+index).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,484 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunMigrationsRecordsApplied(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	db := store.UnderlyingDB()
+
+	// New already ran migrations, so every migration should be recorded
+	applied, err := AppliedMigrations(ctx, db)
+	if err != nil {
+		t.Fatalf("AppliedMigrations failed: %v", err)
+	}
+	if len(applied) != len(migrationsList) {
+		t.Fatalf("AppliedMigrations = %v, want all of %v", applied, ListMigrations())
+	}
+	for i, name := range ListMigrations() {
+		if applied[i] != name {
+			t.Errorf("AppliedMigrations[%d] = %q, want %q", i, applied[i], name)
+		}
+	}
+}
+
+func TestRunMigrationsSkipsApplied(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	// Swap in instrumented migrations; recorded ones must not run again,
+	// so a second RunMigrations issues no ALTER TABLE statements.
+	original := migrationsList
+	defer func() { migrationsList = original }()
+
+	calls := 0
+	migrationsList = make([]Migration, len(original))
+	for i, m := range original {
+		m := m
+		migrationsList[i] = Migration{Name: m.Name, Func: func(ctx context.Context, db *sql.DB) error {
+			calls++
+			return m.Func(ctx, db)
+		}}
+	}
+
+	if err := RunMigrations(ctx, store.UnderlyingDB()); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no migrations to run, %d ran", calls)
+	}
+}
+
+func TestRunMigrationsCancelled(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	original := migrationsList
+	defer func() { migrationsList = original }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first pending migration is cancelled mid-run; the second must not start
+	secondRan := false
+	migrationsList = append(append([]Migration{}, original...),
+		Migration{Name: "test_cancelled", Func: func(ctx context.Context, db *sql.DB) error {
+			cancel()
+			_, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS test_cancelled (id INT PRIMARY KEY)")
+			return err
+		}},
+		Migration{Name: "test_after_cancel", Func: func(context.Context, *sql.DB) error {
+			secondRan = true
+			return nil
+		}},
+	)
+
+	err := RunMigrations(ctx, store.UnderlyingDB())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("RunMigrations = %v, want context.Canceled", err)
+	}
+	if secondRan {
+		t.Error("migration after cancellation should not run")
+	}
+
+	// Nothing was recorded for the cancelled migrations
+	applied, err := AppliedMigrations(context.Background(), store.UnderlyingDB())
+	if err != nil {
+		t.Fatalf("AppliedMigrations failed: %v", err)
+	}
+	for _, name := range applied {
+		if name == "test_cancelled" || name == "test_after_cancel" {
+			t.Errorf("%s recorded despite cancellation", name)
+		}
+	}
+
+	// An already-cancelled context fails before any migration runs
+	if err := RunMigrations(ctx, store.UnderlyingDB()); !errors.Is(err, context.Canceled) {
+		t.Errorf("RunMigrations with cancelled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestMigrateAppliesPending(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	// Register a fake migration after the store was created, so it is pending
+	original := migrationsList
+	defer func() { migrationsList = original }()
+
+	calls := 0
+	migrationsList = append(append([]Migration{}, original...), Migration{
+		Name: "test_pending",
+		Func: func(ctx context.Context, db *sql.DB) error {
+			calls++
+			_, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS test_pending (id INT PRIMARY KEY)")
+			return err
+		},
+	})
+
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("pending migration ran %d times, want 1", calls)
+	}
+	applied, err := AppliedMigrations(ctx, store.UnderlyingDB())
+	if err != nil {
+		t.Fatalf("AppliedMigrations failed: %v", err)
+	}
+	if len(applied) == 0 || applied[len(applied)-1] != "test_pending" {
+		t.Errorf("AppliedMigrations = %v, want test_pending recorded last", applied)
+	}
+
+	// Once recorded, it does not run again
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("second Migrate failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("pending migration ran %d times after second Migrate, want 1", calls)
+	}
+}
+
+func TestMigrateRefusesReadOnly(t *testing.T) {
+	// Rejected before touching the database, so no server is needed
+	store := &MariaDBStore{storeState: newStoreState(nil, ""), dbName: "beads", readOnly: true}
+	err := store.Migrate(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("Migrate on read-only store = %v, want read-only error", err)
+	}
+}
+
+func TestRollbackMigration(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	db := store.UnderlyingDB()
+
+	tests := []struct {
+		name   string
+		column string
+	}{
+		{"wisp_type_column", "wisp_type"},
+		{"spec_id_column", "spec_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RollbackMigration(ctx, db, tt.name); err != nil {
+				t.Fatalf("RollbackMigration failed: %v", err)
+			}
+			if exists, err := columnExists(ctx, db, "issues", tt.column); err != nil || exists {
+				t.Errorf("after rollback columnExists(%s) = %v, %v; want false", tt.column, exists, err)
+			}
+			applied, err := AppliedMigrations(ctx, db)
+			if err != nil {
+				t.Fatalf("AppliedMigrations failed: %v", err)
+			}
+			for _, name := range applied {
+				if name == tt.name {
+					t.Errorf("%s still recorded after rollback", tt.name)
+				}
+			}
+
+			// Re-applying restores the column and the record
+			if err := RunMigrations(ctx, db); err != nil {
+				t.Fatalf("RunMigrations failed: %v", err)
+			}
+			if exists, err := columnExists(ctx, db, "issues", tt.column); err != nil || !exists {
+				t.Errorf("after re-apply columnExists(%s) = %v, %v; want true", tt.column, exists, err)
+			}
+		})
+	}
+}
+
+func TestIssueTypeIndexMigration(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	db := store.UnderlyingDB()
+
+	// Simulate a database created before the index existed
+	if err := RollbackMigration(ctx, db, "issue_type_index"); err != nil {
+		t.Fatalf("RollbackMigration failed: %v", err)
+	}
+	if exists, err := indexExists(ctx, db, "issues", "idx_issues_issue_type"); err != nil || exists {
+		t.Fatalf("indexExists after rollback = %v, %v; want false", exists, err)
+	}
+
+	if err := initSchemaOnDB(ctx, db, DefaultCharset, DefaultCollation); err != nil {
+		t.Fatalf("initSchemaOnDB failed: %v", err)
+	}
+	if exists, err := indexExists(ctx, db, "issues", "idx_issues_issue_type"); err != nil || !exists {
+		t.Errorf("indexExists after schema init = %v, %v; want true", exists, err)
+	}
+}
+
+func TestSecondSchemaInitIsNoOp(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	db := store.UnderlyingDB()
+
+	// Every migration is recorded by the first init, so none may run again
+	original := migrationsList
+	defer func() { migrationsList = original }()
+
+	calls := 0
+	migrationsList = make([]Migration, len(original))
+	for i, m := range original {
+		m := m
+		migrationsList[i] = Migration{Name: m.Name, Func: func(ctx context.Context, db *sql.DB) error {
+			calls++
+			return m.Func(ctx, db)
+		}}
+	}
+
+	if err := initSchemaOnDB(ctx, db, DefaultCharset, DefaultCollation); err != nil {
+		t.Fatalf("second initSchemaOnDB failed: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no migrations to run, %d ran", calls)
+	}
+	stmts, err := RunMigrationsDryRun(ctx, db)
+	if err != nil {
+		t.Fatalf("RunMigrationsDryRun failed: %v", err)
+	}
+	if len(stmts) != 0 {
+		t.Errorf("expected empty plan after second init, got %v", stmts)
+	}
+}
+
+func TestRollbackMigrationErrors(t *testing.T) {
+	original := migrationsList
+	defer func() { migrationsList = original }()
+	migrationsList = []Migration{{Name: "no_down", Func: func(context.Context, *sql.DB) error { return nil }}}
+
+	// Neither case touches the database
+	if err := RollbackMigration(context.Background(), nil, "no_down"); !errors.Is(err, ErrIrreversibleMigration) {
+		t.Errorf("RollbackMigration(no_down) = %v, want ErrIrreversibleMigration", err)
+	}
+	if err := RollbackMigration(context.Background(), nil, "missing"); err == nil {
+		t.Error("expected error for unknown migration")
+	}
+}
+
+func TestRunMigrationsDryRun(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	db := store.UnderlyingDB()
+
+	stmts, err := RunMigrationsDryRun(ctx, db)
+	if err != nil {
+		t.Fatalf("RunMigrationsDryRun failed: %v", err)
+	}
+	if len(stmts) != 0 {
+		t.Errorf("expected empty plan for migrated database, got %v", stmts)
+	}
+
+	// Simulate a database created before spec_id existed
+	if err := RollbackMigration(ctx, db, "spec_id_column"); err != nil {
+		t.Fatalf("RollbackMigration failed: %v", err)
+	}
+
+	stmts, err = RunMigrationsDryRun(ctx, db)
+	if err != nil {
+		t.Fatalf("RunMigrationsDryRun failed: %v", err)
+	}
+	want := []string{
+		"ALTER TABLE issues ADD COLUMN spec_id VARCHAR(1024)",
+		"CREATE INDEX idx_issues_spec_id ON issues(spec_id)",
+	}
+	if len(stmts) != len(want) {
+		t.Fatalf("RunMigrationsDryRun = %v, want %v", stmts, want)
+	}
+	for i := range want {
+		if stmts[i] != want[i] {
+			t.Errorf("stmt[%d] = %q, want %q", i, stmts[i], want[i])
+		}
+	}
+
+	// Dry run must not change the schema
+	if exists, err := columnExists(ctx, db, "issues", "spec_id"); err != nil || exists {
+		t.Errorf("columnExists(spec_id) = %v, %v after dry run; want false", exists, err)
+	}
+
+	if err := RunMigrations(ctx, db); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+}
+
+func TestPriorityRangeMigration(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	db := store.UnderlyingDB()
+	issues := newBatchIssues(3, "Priority")
+	if err := store.CreateIssuesBatch(ctx, issues, "tester"); err != nil {
+		t.Fatalf("CreateIssuesBatch failed: %v", err)
+	}
+
+	// Simulate priorities written before they were validated
+	for i, p := range []int{-2, 9, 3} {
+		if _, err := db.ExecContext(ctx, "UPDATE issues SET priority = ? WHERE id = ?", p, issues[i].ID); err != nil {
+			t.Fatalf("setting priority failed: %v", err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM schema_migrations WHERE name = 'priority_range'"); err != nil {
+		t.Fatalf("forgetting migration failed: %v", err)
+	}
+	if err := RunMigrations(ctx, db); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+
+	for i, want := range []int{0, 4, 3} {
+		got, err := store.GetIssue(ctx, issues[i].ID)
+		if err != nil || got == nil {
+			t.Fatalf("GetIssue(%s) = %v, %v", issues[i].ID, got, err)
+		}
+		if got.Priority != want {
+			t.Errorf("%s priority = %d after migration, want %d", issues[i].ID, got.Priority, want)
+		}
+	}
+}
+
+func TestLabelsTablesMigration(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	db := store.UnderlyingDB()
+	issues := newBatchIssues(3, "Labels")
+	if err := store.CreateIssuesBatch(ctx, issues, "tester"); err != nil {
+		t.Fatalf("CreateIssuesBatch failed: %v", err)
+	}
+
+	// Simulate a database whose labels were written one row per issue
+	if err := RollbackMigration(ctx, db, "labels_tables"); err != nil {
+		t.Fatalf("RollbackMigration failed: %v", err)
+	}
+	if exists, err := columnExists(ctx, db, "labels", "issue_id"); err != nil || !exists {
+		t.Fatalf("after rollback columnExists(labels, issue_id) = %v, %v; want true", exists, err)
+	}
+	for _, row := range [][2]string{
+		{issues[0].ID, "bug"}, {issues[0].ID, "urgent"}, {issues[1].ID, "bug"},
+	} {
+		if _, err := db.ExecContext(ctx, "INSERT INTO labels (issue_id, label) VALUES (?, ?)", row[0], row[1]); err != nil {
+			t.Fatalf("inserting legacy label failed: %v", err)
+		}
+	}
+
+	if err := RunMigrations(ctx, db); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+	if exists, err := columnExists(ctx, db, "labels", "issue_id"); err != nil || exists {
+		t.Errorf("after migration columnExists(labels, issue_id) = %v, %v; want false", exists, err)
+	}
+	var distinct int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM labels").Scan(&distinct); err != nil {
+		t.Fatalf("counting labels failed: %v", err)
+	}
+	if distinct != 2 {
+		t.Errorf("labels has %d rows after migration, want 2", distinct)
+	}
+
+	labels, err := store.GetLabels(ctx, issues[0].ID)
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	if want := []string{"bug", "urgent"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("GetLabels(%s) = %v, want %v", issues[0].ID, labels, want)
+	}
+	tagged, err := store.ListIssuesByLabel(ctx, "bug")
+	if err != nil {
+		t.Fatalf("ListIssuesByLabel failed: %v", err)
+	}
+	if len(tagged) != 2 {
+		t.Errorf("ListIssuesByLabel(bug) returned %d issues, want 2", len(tagged))
+	}
+
+	// New labels reuse the migrated rows
+	if err := store.AddLabel(ctx, issues[2].ID, "bug", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM labels").Scan(&distinct); err != nil {
+		t.Fatalf("counting labels failed: %v", err)
+	}
+	if distinct != 2 {
+		t.Errorf("labels has %d rows after AddLabel, want 2", distinct)
+	}
+}
+
+func TestMigrationStatus(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	db := store.UnderlyingDB()
+	applied, pending, err := store.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus failed: %v", err)
+	}
+	if !reflect.DeepEqual(applied, ListMigrations()) || len(pending) != 0 {
+		t.Errorf("MigrationStatus = %v, %v; want all applied", applied, pending)
+	}
+
+	if _, err := db.ExecContext(ctx, "DELETE FROM schema_migrations WHERE name = 'spec_id_column'"); err != nil {
+		t.Fatalf("forgetting migration failed: %v", err)
+	}
+	applied, pending, err = store.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus failed: %v", err)
+	}
+	if !reflect.DeepEqual(pending, []string{"spec_id_column"}) || len(applied) != len(migrationsList)-1 {
+		t.Errorf("MigrationStatus = %v, %v; want only spec_id_column pending", applied, pending)
+	}
+
+	// Without the tracking table, everything is pending
+	if _, err := db.ExecContext(ctx, "DROP TABLE schema_migrations"); err != nil {
+		t.Fatalf("dropping schema_migrations failed: %v", err)
+	}
+	applied, pending, err = store.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus without schema_migrations failed: %v", err)
+	}
+	if len(applied) != 0 || !reflect.DeepEqual(pending, ListMigrations()) {
+		t.Errorf("MigrationStatus = %v, %v; want all pending", applied, pending)
+	}
+}
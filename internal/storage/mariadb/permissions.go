@@ -0,0 +1,165 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// PermissionIssue describes a privilege the configured user is missing.
+type PermissionIssue struct {
+	Privilege string // Privilege name as reported by MariaDB (e.g. "INSERT")
+	Table     string // Table lacking the privilege, or empty for database-wide privileges
+}
+
+func (p PermissionIssue) String() string {
+	if p.Table == "" {
+		return fmt.Sprintf("missing %s privilege on database", p.Privilege)
+	}
+	return fmt.Sprintf("missing %s privilege on table %s", p.Privilege, p.Table)
+}
+
+// readPrivileges are required by every store, including read-only ones.
+var readPrivileges = []string{"SELECT"}
+
+// writePrivileges are required for normal operation: DML on all tables plus
+// the DDL used by schema init and migrations (CREATE OR REPLACE VIEW needs DROP).
+var writePrivileges = []string{
+	"SELECT", "INSERT", "UPDATE", "DELETE",
+	"CREATE", "ALTER", "INDEX", "DROP", "CREATE VIEW",
+}
+
+// tableLevelPrivileges can be satisfied by per-table grants.
+// Everything else must be granted globally or on the database.
+var tableLevelPrivileges = map[string]bool{
+	"SELECT": true, "INSERT": true, "UPDATE": true, "DELETE": true,
+	"ALTER": true, "INDEX": true, "DROP": true,
+}
+
+// privilegeGrants holds the privileges granted to a user, by scope.
+type privilegeGrants struct {
+	global map[string]bool            // USER_PRIVILEGES
+	schema map[string]bool            // SCHEMA_PRIVILEGES for the store's database
+	table  map[string]map[string]bool // TABLE_PRIVILEGES, keyed by table name
+}
+
+// CheckPermissions verifies the connected user holds every privilege beads needs
+// on the store's database, returning one PermissionIssue per missing privilege.
+// Read-only stores only require SELECT.
+//
+// Grants are read from information_schema. Privileges inherited through roles
+// are not expanded, so a user relying solely on roles may see false positives.
+func (s *MariaDBStore) CheckPermissions(ctx context.Context) ([]PermissionIssue, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	var currentUser string
+	if err := s.queryRowContext(ctx, "SELECT CURRENT_USER()").Scan(&currentUser); err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+	grantee := formatGrantee(currentUser)
+
+	grants := privilegeGrants{
+		global: make(map[string]bool),
+		schema: make(map[string]bool),
+		table:  make(map[string]map[string]bool),
+	}
+
+	rows, err := s.queryContext(ctx, `
+		SELECT PRIVILEGE_TYPE FROM information_schema.USER_PRIVILEGES WHERE GRANTEE = ?
+	`, grantee)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user privileges: %w", err)
+	}
+	if err := scanPrivileges(rows, grants.global); err != nil {
+		return nil, fmt.Errorf("failed to read user privileges: %w", err)
+	}
+
+	rows, err = s.queryContext(ctx, `
+		SELECT PRIVILEGE_TYPE FROM information_schema.SCHEMA_PRIVILEGES
+		WHERE GRANTEE = ? AND TABLE_SCHEMA = ?
+	`, grantee, s.dbName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema privileges: %w", err)
+	}
+	if err := scanPrivileges(rows, grants.schema); err != nil {
+		return nil, fmt.Errorf("failed to read schema privileges: %w", err)
+	}
+
+	rows, err = s.queryContext(ctx, `
+		SELECT TABLE_NAME, PRIVILEGE_TYPE FROM information_schema.TABLE_PRIVILEGES
+		WHERE GRANTEE = ? AND TABLE_SCHEMA = ?
+	`, grantee, s.dbName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table privileges: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table, priv string
+		if err := rows.Scan(&table, &priv); err != nil {
+			return nil, fmt.Errorf("failed to scan table privilege: %w", err)
+		}
+		if grants.table[table] == nil {
+			grants.table[table] = make(map[string]bool)
+		}
+		grants.table[table][strings.ToUpper(priv)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read table privileges: %w", err)
+	}
+
+	required := writePrivileges
+	if s.readOnly {
+		required = readPrivileges
+	}
+	tables := make([]string, len(tableNames))
+	for i, table := range tableNames {
+		tables[i] = s.tablePrefix + table
+	}
+	return missingPrivileges(required, tables, grants), nil
+}
+
+// missingPrivileges reports each required privilege that is not granted
+// globally, on the database, or (where allowed) on every listed table.
+func missingPrivileges(required, tables []string, grants privilegeGrants) []PermissionIssue {
+	var issues []PermissionIssue
+	for _, priv := range required {
+		if grants.global[priv] || grants.schema[priv] {
+			continue
+		}
+		if !tableLevelPrivileges[priv] {
+			issues = append(issues, PermissionIssue{Privilege: priv})
+			continue
+		}
+		for _, table := range tables {
+			if !grants.table[table][priv] {
+				issues = append(issues, PermissionIssue{Privilege: priv, Table: table})
+			}
+		}
+	}
+	return issues
+}
+
+// formatGrantee converts CURRENT_USER() output (user@host) to the quoted
+// 'user'@'host' form used by information_schema privilege tables.
+func formatGrantee(currentUser string) string {
+	user, host := currentUser, ""
+	if i := strings.LastIndex(currentUser, "@"); i >= 0 {
+		user, host = currentUser[:i], currentUser[i+1:]
+	}
+	return fmt.Sprintf("'%s'@'%s'", user, host)
+}
+
+// scanPrivileges reads single-column PRIVILEGE_TYPE rows into a set and closes rows.
+func scanPrivileges(rows *sql.Rows, into map[string]bool) error {
+	defer rows.Close()
+	for rows.Next() {
+		var priv string
+		if err := rows.Scan(&priv); err != nil {
+			return err
+		}
+		into[strings.ToUpper(priv)] = true
+	}
+	return rows.Err()
+}
@@ -0,0 +1,82 @@
+package mariadb
+
+import (
+	"testing"
+)
+
+func TestFormatGrantee(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"root@localhost", "'root'@'localhost'"},
+		{"beads@%", "'beads'@'%'"},
+		{"odd@name@10.0.0.1", "'odd@name'@'10.0.0.1'"},
+		{"nohost", "'nohost'@''"},
+	}
+	for _, tt := range tests {
+		if got := formatGrantee(tt.in); got != tt.want {
+			t.Errorf("formatGrantee(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMissingPrivileges(t *testing.T) {
+	tables := []string{"issues", "dependencies"}
+
+	t.Run("global grant satisfies everything", func(t *testing.T) {
+		grants := privilegeGrants{global: map[string]bool{}}
+		for _, p := range writePrivileges {
+			grants.global[p] = true
+		}
+		if got := missingPrivileges(writePrivileges, tables, grants); len(got) != 0 {
+			t.Errorf("expected no issues, got %v", got)
+		}
+	})
+
+	t.Run("schema grant satisfies read-only", func(t *testing.T) {
+		grants := privilegeGrants{schema: map[string]bool{"SELECT": true}}
+		if got := missingPrivileges(readPrivileges, tables, grants); len(got) != 0 {
+			t.Errorf("expected no issues, got %v", got)
+		}
+	})
+
+	t.Run("partial table grants are reported per table", func(t *testing.T) {
+		grants := privilegeGrants{
+			schema: map[string]bool{"SELECT": true},
+			table: map[string]map[string]bool{
+				"issues": {"INSERT": true},
+			},
+		}
+		got := missingPrivileges([]string{"SELECT", "INSERT"}, tables, grants)
+		want := []PermissionIssue{{Privilege: "INSERT", Table: "dependencies"}}
+		if len(got) != len(want) || got[0] != want[0] {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("database-only privileges ignore table grants", func(t *testing.T) {
+		grants := privilegeGrants{
+			table: map[string]map[string]bool{
+				"issues":       {"CREATE VIEW": true},
+				"dependencies": {"CREATE VIEW": true},
+			},
+		}
+		got := missingPrivileges([]string{"CREATE VIEW"}, tables, grants)
+		if len(got) != 1 || got[0].Table != "" {
+			t.Errorf("expected one database-wide issue, got %v", got)
+		}
+	})
+}
+
+func TestCheckPermissions(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if _, err := store.CheckPermissions(ctx); err != nil {
+		t.Fatalf("CheckPermissions failed: %v", err)
+	}
+}
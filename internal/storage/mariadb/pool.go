@@ -0,0 +1,84 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PoolStats summarizes connection pool pressure for monitoring.
+type PoolStats struct {
+	MaxOpen      int           // Configured maximum open connections (0 = unlimited)
+	Open         int           // Established connections, in use plus idle
+	InUse        int           // Connections currently in use
+	Idle         int           // Idle connections
+	WaitCount    int64         // Total number of times a caller waited for a connection
+	WaitDuration time.Duration // Total time callers spent waiting for a connection
+}
+
+// Stats returns the connection pool statistics of the underlying *sql.DB.
+// It returns the zero value if the store is closed.
+func (s *MariaDBStore) Stats() sql.DBStats {
+	db := s.UnderlyingDB()
+	if db == nil {
+		return sql.DBStats{}
+	}
+	return db.Stats()
+}
+
+// PoolStats returns a summary of Stats suitable for alerting on pool pressure.
+// It returns the zero value if the store is closed.
+func (s *MariaDBStore) PoolStats() PoolStats {
+	st := s.Stats()
+	return PoolStats{
+		MaxOpen:      st.MaxOpenConnections,
+		Open:         st.OpenConnections,
+		InUse:        st.InUse,
+		Idle:         st.Idle,
+		WaitCount:    st.WaitCount,
+		WaitDuration: st.WaitDuration,
+	}
+}
+
+// Warmup opens up to n connections (at most MaxOpenConns) and pings each, so
+// the first queries after startup don't pay for dialing. The connections are
+// returned to the pool idle; those beyond MaxIdleConns are closed again, so
+// warming more than MaxIdleConns has no lasting effect.
+// Returns ErrStoreClosed if the store has been closed.
+func (s *MariaDBStore) Warmup(ctx context.Context, n int) error {
+	if s.IsClosed() {
+		return ErrStoreClosed
+	}
+	if n < 0 {
+		return fmt.Errorf("invalid warmup connection count %d: must not be negative", n)
+	}
+	db, done, err := s.acquire()
+	if err != nil {
+		return err
+	}
+	defer done()
+	if s.pool.maxOpenConns > 0 {
+		n = min(n, s.pool.maxOpenConns)
+	}
+
+	// Hold every connection until all are open, or the pool would hand the
+	// same idle connection out again
+	conns := make([]*sql.Conn, 0, n)
+	defer func() {
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+	}()
+	for range n {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to open MariaDB connection %d of %d for warmup: %w", len(conns)+1, n, err)
+		}
+		conns = append(conns, conn)
+		if err := conn.PingContext(ctx); err != nil {
+			return fmt.Errorf("failed to ping MariaDB connection %d of %d for warmup: %w", len(conns), n, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package mariadb
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if _, err := store.GetConfig(ctx, "issue_prefix"); err != nil {
+		t.Fatalf("GetConfig failed: %v", err)
+	}
+
+	if got := store.Stats().OpenConnections; got < 1 {
+		t.Errorf("Stats().OpenConnections = %d, want >= 1", got)
+	}
+	ps := store.PoolStats()
+	if ps.MaxOpen != DefaultMaxOpenConns {
+		t.Errorf("PoolStats().MaxOpen = %d, want %d", ps.MaxOpen, DefaultMaxOpenConns)
+	}
+	if ps.Open != ps.InUse+ps.Idle {
+		t.Errorf("PoolStats() Open (%d) != InUse (%d) + Idle (%d)", ps.Open, ps.InUse, ps.Idle)
+	}
+}
+
+func TestStatsClosedStore(t *testing.T) {
+	store := &MariaDBStore{storeState: newStoreState(nil, "")}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if got := store.Stats(); got.OpenConnections != 0 || got.MaxOpenConnections != 0 {
+		t.Errorf("expected zero stats on closed store, got %+v", got)
+	}
+	if got := store.PoolStats(); got != (PoolStats{}) {
+		t.Errorf("expected zero PoolStats on closed store, got %+v", got)
+	}
+}
+
+func TestWarmup(t *testing.T) {
+	store, cleanup := setupTestStoreWithConfig(t, &Config{MaxOpenConns: 8, MaxIdleConns: 8})
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if err := store.Warmup(ctx, 5); err != nil {
+		t.Fatalf("Warmup failed: %v", err)
+	}
+	if got := store.Stats().OpenConnections; got < 5 {
+		t.Errorf("Stats().OpenConnections = %d after Warmup(5), want >= 5", got)
+	}
+
+	// Bounded by MaxOpenConns rather than blocking on the pool limit
+	if err := store.Warmup(ctx, 20); err != nil {
+		t.Fatalf("Warmup above MaxOpenConns failed: %v", err)
+	}
+	if got := store.Stats().OpenConnections; got > 8 {
+		t.Errorf("Stats().OpenConnections = %d, want <= MaxOpenConns (8)", got)
+	}
+}
+
+func TestWarmupClosedStore(t *testing.T) {
+	store := &MariaDBStore{storeState: newStoreState(nil, "")}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := store.Warmup(context.Background(), 1); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("Warmup on closed store = %v, want ErrStoreClosed", err)
+	}
+}
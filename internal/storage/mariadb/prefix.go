@@ -0,0 +1,351 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// maxTablePrefixLen keeps prefixed names within MariaDB's 64-character
+// identifier limit.
+const maxTablePrefixLen = 32
+
+// tablePrefixPattern matches valid Config.TablePrefix values.
+var tablePrefixPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// validateTablePrefix checks that prefix can be pasted in front of identifiers.
+func validateTablePrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+	if !tablePrefixPattern.MatchString(prefix) {
+		return fmt.Errorf("invalid MariaDB table prefix %q: only letters, digits, and underscores are allowed", prefix)
+	}
+	if len(prefix) > maxTablePrefixLen {
+		return fmt.Errorf("invalid MariaDB table prefix %q: longer than %d characters", prefix, maxTablePrefixLen)
+	}
+	return nil
+}
+
+// connOptions are the Config settings applied by a pool's connections rather
+// than by the store.
+type connOptions struct {
+	prefix       string        // Config.TablePrefix
+	queryTimeout time.Duration // Config.QueryTimeout
+	slowQuery    slowQueryLog  // Config.SlowQueryThreshold
+}
+
+func connOptionsFromConfig(cfg *Config) connOptions {
+	return connOptions{
+		prefix:       cfg.TablePrefix,
+		queryTimeout: cfg.QueryTimeout,
+		slowQuery: slowQueryLog{
+			threshold: cfg.SlowQueryThreshold,
+			logger:    cfg.Logger,
+			database:  cfg.Database,
+		},
+	}
+}
+
+// openDB opens a connection pool for connStr. With a table prefix, the pool's
+// connections rewrite the Beads table, view, and index names in every query
+// (see prefixRewriter), so the store's SQL stays unprefixed. With a query
+// timeout, every statement is cancelled after it (see timeoutConn). With a
+// slow query threshold, slower statements are logged with their plan (see
+// slowQueryConn).
+func openDB(connStr string, opts connOptions) (*sql.DB, error) {
+	if opts.prefix == "" && opts.queryTimeout == 0 && !opts.slowQuery.enabled() {
+		return sqlOpen("mysql", connStr)
+	}
+	mc, err := mysql.ParseDSN(connStr)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(mc)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(wrapConnector(connector, opts)), nil
+}
+
+// wrapConnector applies the connection options of openDB to a MySQL
+// connector. The slow query log wraps the others, so EXPLAIN statements are
+// prefixed and bounded like the statements they explain.
+func wrapConnector(connector driver.Connector, opts connOptions) driver.Connector {
+	if opts.prefix != "" {
+		connector = newPrefixConnector(connector, opts.prefix)
+	}
+	if opts.queryTimeout > 0 {
+		connector = &timeoutConnector{connector: connector, timeout: opts.queryTimeout}
+	}
+	if opts.slowQuery.enabled() {
+		connector = &slowQueryConnector{connector: connector, log: opts.slowQuery}
+	}
+	return connector
+}
+
+// tablePrefixOf returns the table prefix of a pool opened by openDB, so
+// helpers that look up Beads tables in information_schema by name can find
+// them.
+func tablePrefixOf(db *sql.DB) string {
+	if d, ok := db.Driver().(*prefixDriver); ok {
+		return d.rewriter.prefix
+	}
+	return ""
+}
+
+// prefixRewriter prefixes Beads table, view, index, and constraint names in
+// SQL text. Index and constraint names are distinctive and are prefixed
+// wherever they appear. Table and view names can also be column names (e.g.
+// metadata), so they are only prefixed after a keyword that introduces a
+// table (FROM, JOIN, INTO, UPDATE, TABLE, ...) or before a "." qualifier.
+// String literals and comments are left alone.
+type prefixRewriter struct {
+	prefix string
+	tables map[string]bool
+	names  map[string]bool
+}
+
+func newPrefixRewriter(prefix string) *prefixRewriter {
+	r := &prefixRewriter{prefix: prefix, tables: make(map[string]bool), names: make(map[string]bool)}
+	for _, table := range tableNames {
+		r.tables[table] = true
+	}
+	for _, view := range viewNames {
+		r.tables[view] = true
+	}
+	for _, table := range expectedSchema() {
+		for _, index := range table.indexes {
+			if index != "PRIMARY" {
+				r.names[index] = true
+			}
+		}
+		for _, fk := range table.foreignKeys {
+			r.names[fk] = true
+		}
+	}
+	return r
+}
+
+// tableKeywords are the keywords after which an identifier names a table.
+var tableKeywords = map[string]bool{
+	"FROM": true, "JOIN": true, "INTO": true, "UPDATE": true, "TABLE": true,
+	"TABLES": true, "EXISTS": true, "REFERENCES": true, "VIEW": true, "ON": true,
+}
+
+// rewrite returns query with Beads object names prefixed.
+func (r *prefixRewriter) rewrite(query string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 64)
+	// prev and prevPrev are the last two bare words, upper-cased; lastByte is
+	// the last non-space byte copied.
+	var prev, prevPrev string
+	var lastByte byte
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			n := quotedLength(query[i:], c)
+			b.WriteString(query[i : i+n])
+			i += n
+			lastByte = c
+			continue
+		case c == '#' || (c == '-' && strings.HasPrefix(query[i:], "-- ")):
+			n := strings.IndexByte(query[i:], '\n')
+			if n < 0 {
+				n = len(query) - i
+			}
+			b.WriteString(query[i : i+n])
+			i += n
+			continue
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			n := strings.Index(query[i+2:], "*/")
+			if n < 0 {
+				n = len(query) - i
+			} else {
+				n += 4
+			}
+			b.WriteString(query[i : i+n])
+			i += n
+			continue
+		case c == '`' || isIdentByte(c):
+			start, quoted := i, c == '`'
+			var name string
+			if quoted {
+				n := quotedLength(query[i:], '`')
+				name = strings.Trim(query[i:i+n], "`")
+				i += n
+			} else {
+				for i < len(query) && isIdentByte(query[i]) {
+					i++
+				}
+				name = query[start:i]
+			}
+			qualified := lastByte == '.'
+			if !qualified && r.isObjectName(name, prev, prevPrev, nextNonSpace(query[i:])) {
+				if quoted {
+					b.WriteString("`" + r.prefix + name + "`")
+				} else {
+					b.WriteString(r.prefix + name)
+				}
+			} else {
+				b.WriteString(query[start:i])
+			}
+			if !quoted {
+				prevPrev, prev = prev, strings.ToUpper(name)
+			} else {
+				prevPrev, prev = prev, ""
+			}
+			lastByte = query[i-1]
+			continue
+		}
+		b.WriteByte(c)
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			lastByte = c
+			prevPrev, prev = prev, ""
+		}
+		i++
+	}
+	return b.String()
+}
+
+// isObjectName reports whether the identifier name, preceded by the words
+// prev and prevPrev and followed by the byte next, refers to a Beads object.
+func (r *prefixRewriter) isObjectName(name, prev, prevPrev string, next byte) bool {
+	if r.names[name] {
+		return true
+	}
+	if !r.tables[name] {
+		return false
+	}
+	if next == '.' {
+		return true
+	}
+	// ON DUPLICATE KEY UPDATE is followed by a column
+	return tableKeywords[prev] && !(prev == "UPDATE" && prevPrev == "KEY")
+}
+
+// quotedLength returns the length of the quoted token at the start of s,
+// including its quotes. A doubled quote or, outside identifiers, a
+// backslash escapes the next character.
+func quotedLength(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote != '`':
+			i++
+		case s[i] == quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// nextNonSpace returns the first byte of s that is not whitespace, or 0.
+func nextNonSpace(s string) byte {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case ' ', '\t', '\n', '\r':
+		default:
+			return s[i]
+		}
+	}
+	return 0
+}
+
+// prefixConnector wraps the MySQL connector, rewriting every query with a
+// prefixRewriter before it reaches the server.
+type prefixConnector struct {
+	connector driver.Connector
+	rewriter  *prefixRewriter
+}
+
+func newPrefixConnector(connector driver.Connector, prefix string) *prefixConnector {
+	return &prefixConnector{connector: connector, rewriter: newPrefixRewriter(prefix)}
+}
+
+func (c *prefixConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mc, ok := conn.(mysqlConn)
+	if !ok {
+		_ = conn.Close()
+		return nil, fmt.Errorf("unexpected MySQL driver connection type %T", conn)
+	}
+	return &prefixConn{mysqlConn: mc, rewriter: c.rewriter}, nil
+}
+
+func (c *prefixConnector) Driver() driver.Driver {
+	return &prefixDriver{Driver: c.connector.Driver(), rewriter: c.rewriter}
+}
+
+// prefixDriver is returned by prefixConnector.Driver so tablePrefixOf can
+// recover the prefix from a *sql.DB.
+type prefixDriver struct {
+	driver.Driver
+	rewriter *prefixRewriter
+}
+
+func (d *prefixDriver) Open(name string) (driver.Conn, error) {
+	mc, err := mysql.ParseDSN(name)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(mc)
+	if err != nil {
+		return nil, err
+	}
+	return (&prefixConnector{connector: connector, rewriter: d.rewriter}).Connect(context.Background())
+}
+
+// mysqlConn is the set of driver interfaces implemented by go-sql-driver/mysql
+// connections, which prefixConn must keep exposing to database/sql.
+type mysqlConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+	driver.NamedValueChecker
+}
+
+// prefixConn is a MySQL connection that rewrites every query. All other
+// driver interfaces are promoted from the embedded connection.
+type prefixConn struct {
+	mysqlConn
+	rewriter *prefixRewriter
+}
+
+func (c *prefixConn) Prepare(query string) (driver.Stmt, error) {
+	return c.mysqlConn.Prepare(c.rewriter.rewrite(query))
+}
+
+func (c *prefixConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.mysqlConn.PrepareContext(ctx, c.rewriter.rewrite(query))
+}
+
+func (c *prefixConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.mysqlConn.ExecContext(ctx, c.rewriter.rewrite(query), args)
+}
+
+func (c *prefixConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.mysqlConn.QueryContext(ctx, c.rewriter.rewrite(query), args)
+}
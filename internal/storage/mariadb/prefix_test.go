@@ -0,0 +1,132 @@
+package mariadb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestPrefixRewriter(t *testing.T) {
+	r := newPrefixRewriter("t1_")
+	tests := []struct {
+		in, want string
+	}{
+		{"SELECT * FROM issues WHERE id = ?", "SELECT * FROM t1_issues WHERE id = ?"},
+		{"SELECT i.id FROM issues i JOIN dependencies d ON d.issue_id = i.id", "SELECT i.id FROM t1_issues i JOIN t1_dependencies d ON d.issue_id = i.id"},
+		{"INSERT INTO config (`key`, value) VALUES (?, ?)", "INSERT INTO t1_config (`key`, value) VALUES (?, ?)"},
+		{"SHOW CREATE TABLE `issues`", "SHOW CREATE TABLE `t1_issues`"},
+		{"UPDATE issues SET metadata = ? WHERE issues.id = ?", "UPDATE t1_issues SET metadata = ? WHERE t1_issues.id = ?"},
+		{"INSERT INTO issues (id, metadata) VALUES (?, ?) ON DUPLICATE KEY UPDATE metadata = VALUES(metadata)",
+			"INSERT INTO t1_issues (id, metadata) VALUES (?, ?) ON DUPLICATE KEY UPDATE metadata = VALUES(metadata)"},
+		{"SELECT metadata FROM metadata", "SELECT metadata FROM t1_metadata"},
+		{"CREATE INDEX idx_issues_spec_id ON issues(spec_id)", "CREATE INDEX t1_idx_issues_spec_id ON t1_issues(spec_id)"},
+		{"CONSTRAINT fk_issue_labels_issue FOREIGN KEY (issue_id) REFERENCES issues(id)", "CONSTRAINT t1_fk_issue_labels_issue FOREIGN KEY (issue_id) REFERENCES t1_issues(id)"},
+		{"CREATE TABLE IF NOT EXISTS events (\n    -- events are kept\n    id BIGINT", "CREATE TABLE IF NOT EXISTS t1_events (\n    -- events are kept\n    id BIGINT"},
+		{"SELECT 'FROM issues', \"JOIN labels\" FROM labels /* FROM events */", "SELECT 'FROM issues', \"JOIN labels\" FROM t1_labels /* FROM events */"},
+		{"SELECT 'it''s FROM issues' FROM routes", "SELECT 'it''s FROM issues' FROM t1_routes"},
+		{"SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'issues'", "SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'issues'"},
+		{"CREATE OR REPLACE VIEW ready_issues AS SELECT i.* FROM issues i", "CREATE OR REPLACE VIEW t1_ready_issues AS SELECT i.* FROM t1_issues i"},
+		{"WHERE EXISTS (SELECT 1 FROM issues blocker)", "WHERE EXISTS (SELECT 1 FROM t1_issues blocker)"},
+	}
+	for _, tt := range tests {
+		if got := r.rewrite(tt.in); got != tt.want {
+			t.Errorf("rewrite(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRewriterCoversSchema(t *testing.T) {
+	r := newPrefixRewriter("t1_")
+	for _, stmt := range []string{schema, readyIssuesView, blockedIssuesView, schemaMigrationsTable} {
+		got := r.rewrite(stmt)
+		for _, name := range append(append([]string{}, tableNames...), viewNames...) {
+			for _, ctx := range []string{"EXISTS " + name + " ", "REFERENCES " + name + "(", "FROM " + name + " ", "VIEW " + name + " "} {
+				if strings.Contains(got, ctx) {
+					t.Errorf("rewritten schema still contains %q", ctx)
+				}
+			}
+		}
+	}
+}
+
+func TestValidateTablePrefix(t *testing.T) {
+	for _, prefix := range []string{"", "a_", "tenant42_"} {
+		if err := validateTablePrefix(prefix); err != nil {
+			t.Errorf("validateTablePrefix(%q) = %v, want nil", prefix, err)
+		}
+	}
+	for _, prefix := range []string{"a-b", "a`b", "x y", strings.Repeat("a", maxTablePrefixLen+1)} {
+		if err := validateTablePrefix(prefix); err == nil {
+			t.Errorf("validateTablePrefix(%q) succeeded, want error", prefix)
+		}
+	}
+}
+
+func TestTablePrefixIsolation(t *testing.T) {
+	storeA, cleanup := setupTestStoreWithConfig(t, &Config{TablePrefix: "a_"})
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	// A second tenant in the same database
+	storeB, err := New(ctx, &Config{Database: storeA.Path(), TablePrefix: "b_"})
+	if err != nil {
+		t.Fatalf("New with second prefix failed: %v", err)
+	}
+	defer storeB.Close()
+	if err := storeB.SetConfig(ctx, "issue_prefix", "other"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	issueA := &types.Issue{Title: "Tenant A", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := storeA.CreateIssue(ctx, issueA, "tester"); err != nil {
+		t.Fatalf("CreateIssue in A failed: %v", err)
+	}
+	issueB := &types.Issue{Title: "Tenant B", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := storeB.CreateIssue(ctx, issueB, "tester"); err != nil {
+		t.Fatalf("CreateIssue in B failed: %v", err)
+	}
+	if !strings.HasPrefix(issueA.ID, "test-") || !strings.HasPrefix(issueB.ID, "other-") {
+		t.Errorf("issue IDs %s, %s should use each tenant's issue_prefix", issueA.ID, issueB.ID)
+	}
+	if err := storeA.AddLabel(ctx, issueA.ID, "tenant-a", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	if got, err := storeA.GetIssue(ctx, issueB.ID); err != nil || got != nil {
+		t.Errorf("store A GetIssue(%s) = %v, %v; want nothing", issueB.ID, got, err)
+	}
+	ready, err := storeB.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != issueB.ID {
+		t.Errorf("store B ready work = %v, want only %s", ready, issueB.ID)
+	}
+	if labels, err := storeB.GetLabels(ctx, issueA.ID); err != nil || len(labels) != 0 {
+		t.Errorf("store B GetLabels(%s) = %v, %v; want none", issueA.ID, labels, err)
+	}
+
+	// Both tenants' tables live side by side, and neither sees unprefixed ones
+	var count int
+	if err := storeA.UnderlyingDB().QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM information_schema.tables
+		WHERE table_schema = DATABASE() AND table_name IN ('a_issues', 'b_issues', 'a_ready_issues', 'b_ready_issues')
+	`).Scan(&count); err != nil {
+		t.Fatalf("failed to query tables: %v", err)
+	}
+	if count != 4 {
+		t.Errorf("found %d prefixed issues tables and views, want 4", count)
+	}
+	for name, store := range map[string]*MariaDBStore{"a_": storeA, "b_": storeB} {
+		diffs, err := store.ValidateSchema(ctx)
+		if err != nil {
+			t.Fatalf("ValidateSchema(%s) failed: %v", name, err)
+		}
+		if len(diffs) != 0 {
+			t.Errorf("ValidateSchema(%s) = %v, want no differences", name, diffs)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package mariadb
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -12,9 +14,59 @@ import (
 
 // SearchIssues finds issues matching query and filters
 func (s *MariaDBStore) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+
+	whereSQL, args := issueFilterWhere(query, filter)
+
+	limitSQL := ""
+	if filter.Limit > 0 {
+		limitSQL = fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	// nolint:gosec // G201: whereSQL contains column comparisons with ?, limitSQL is a safe integer
+	querySQL := fmt.Sprintf(`
+		SELECT id FROM issues
+		%s
+		ORDER BY priority ASC, created_at DESC
+		%s
+	`, whereSQL, limitSQL)
 
+	rows, err := s.readQueryContext(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+	defer rows.Close()
+
+	return s.scanIssueIDs(ctx, rows, filter.IncludeDeleted)
+}
+
+// ListIssuesByPriority returns the issues with the given priority, newest
+// first. It returns an error wrapping ErrInvalidPriority if priority is
+// outside MinPriority to MaxPriority.
+func (s *MariaDBStore) ListIssuesByPriority(ctx context.Context, priority int) ([]*types.Issue, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	if err := checkPriority(priority); err != nil {
+		return nil, err
+	}
+	return s.SearchIssues(ctx, "", types.IssueFilter{Priority: &priority})
+}
+
+// notSoftDeleted excludes soft-deleted issues (see SoftDeleteIssue).
+// Tombstones also carry a deleted_at and are filtered by status instead.
+const notSoftDeleted = "(deleted_at IS NULL OR status = 'tombstone')"
+
+// notSoftDeletedAs is notSoftDeleted for the issues aliased as alias.
+func notSoftDeletedAs(alias string) string {
+	return "(" + alias + ".deleted_at IS NULL OR " + alias + ".status = 'tombstone')"
+}
+
+// issueFilterWhere returns the WHERE clause (empty if nothing is filtered)
+// and its arguments selecting the issues that match query and filter.
+func issueFilterWhere(query string, filter types.IssueFilter) (string, []interface{}) {
 	whereClauses := []string{}
 	args := []interface{}{}
 
@@ -49,6 +101,9 @@ func (s *MariaDBStore) SearchIssues(ctx context.Context, query string, filter ty
 		whereClauses = append(whereClauses, "status != ?")
 		args = append(args, types.StatusTombstone)
 	}
+	if !filter.IncludeDeleted {
+		whereClauses = append(whereClauses, notSoftDeleted)
+	}
 
 	if len(filter.ExcludeStatus) > 0 {
 		placeholders := make([]string, len(filter.ExcludeStatus))
@@ -123,13 +178,13 @@ func (s *MariaDBStore) SearchIssues(ctx context.Context, query string, filter ty
 		whereClauses = append(whereClauses, "(assignee IS NULL OR assignee = '')")
 	}
 	if filter.NoLabels {
-		whereClauses = append(whereClauses, "id NOT IN (SELECT DISTINCT issue_id FROM labels)")
+		whereClauses = append(whereClauses, "id NOT IN (SELECT DISTINCT issue_id FROM issue_labels)")
 	}
 
 	// Label filtering (AND)
 	if len(filter.Labels) > 0 {
 		for _, label := range filter.Labels {
-			whereClauses = append(whereClauses, "id IN (SELECT issue_id FROM labels WHERE label = ?)")
+			whereClauses = append(whereClauses, "id IN (SELECT il.issue_id FROM issue_labels il JOIN labels l ON l.id = il.label_id WHERE l.name = ?)")
 			args = append(args, label)
 		}
 	}
@@ -141,7 +196,7 @@ func (s *MariaDBStore) SearchIssues(ctx context.Context, query string, filter ty
 			placeholders[i] = "?"
 			args = append(args, label)
 		}
-		whereClauses = append(whereClauses, fmt.Sprintf("id IN (SELECT issue_id FROM labels WHERE label IN (%s))", strings.Join(placeholders, ", ")))
+		whereClauses = append(whereClauses, fmt.Sprintf("id IN (SELECT il.issue_id FROM issue_labels il JOIN labels l ON l.id = il.label_id WHERE l.name IN (%s))", strings.Join(placeholders, ", ")))
 	}
 
 	// ID filtering
@@ -221,35 +276,16 @@ func (s *MariaDBStore) SearchIssues(ctx context.Context, query string, filter ty
 	if len(whereClauses) > 0 {
 		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
 	}
-
-	limitSQL := ""
-	if filter.Limit > 0 {
-		limitSQL = fmt.Sprintf(" LIMIT %d", filter.Limit)
-	}
-
-	// nolint:gosec // G201: whereSQL contains column comparisons with ?, limitSQL is a safe integer
-	querySQL := fmt.Sprintf(`
-		SELECT id FROM issues
-		%s
-		ORDER BY priority ASC, created_at DESC
-		%s
-	`, whereSQL, limitSQL)
-
-	rows, err := s.db.QueryContext(ctx, querySQL, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search issues: %w", err)
-	}
-	defer rows.Close()
-
-	return s.scanIssueIDs(ctx, rows)
+	return whereSQL, args
 }
 
 // GetReadyWork returns issues that are ready to work on (not blocked)
 func (s *MariaDBStore) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 
-	whereClauses := []string{"status = 'open'", "(ephemeral = 0 OR ephemeral IS NULL)"}
+	whereClauses := []string{"status = 'open'", "(ephemeral = 0 OR ephemeral IS NULL)", "deleted_at IS NULL"}
 	args := []interface{}{}
 
 	if filter.Priority != nil {
@@ -267,7 +303,7 @@ func (s *MariaDBStore) GetReadyWork(ctx context.Context, filter types.WorkFilter
 	}
 	if len(filter.Labels) > 0 {
 		for _, label := range filter.Labels {
-			whereClauses = append(whereClauses, "id IN (SELECT issue_id FROM labels WHERE label = ?)")
+			whereClauses = append(whereClauses, "id IN (SELECT il.issue_id FROM issue_labels il JOIN labels l ON l.id = il.label_id WHERE l.name = ?)")
 			args = append(args, label)
 		}
 	}
@@ -282,6 +318,7 @@ func (s *MariaDBStore) GetReadyWork(ctx context.Context, filter types.WorkFilter
 			    SELECT 1 FROM issues blocker
 			    WHERE blocker.id = d.depends_on_id
 			      AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+			      AND blocker.deleted_at IS NULL
 			  )
 		)
 	`)
@@ -301,22 +338,23 @@ func (s *MariaDBStore) GetReadyWork(ctx context.Context, filter types.WorkFilter
 		%s
 	`, whereSQL, limitSQL)
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.readQueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ready work: %w", err)
 	}
 	defer rows.Close()
 
-	return s.scanIssueIDs(ctx, rows)
+	return s.scanIssueIDs(ctx, rows, false)
 }
 
 // GetBlockedIssues returns issues that are blocked by other issues
 func (s *MariaDBStore) GetBlockedIssues(ctx context.Context, filter types.WorkFilter) ([]*types.BlockedIssue, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 
 	// Use correlated subquery to avoid three-table merge join (Dolt mergeJoinIter panic)
-	rows, err := s.db.QueryContext(ctx, `
+	rows, err := s.readQueryContext(ctx, `
 		SELECT i.id,
 		  (SELECT COUNT(*)
 		   FROM dependencies d
@@ -326,10 +364,12 @@ func (s *MariaDBStore) GetBlockedIssues(ctx context.Context, filter types.WorkFi
 		       SELECT 1 FROM issues blocker
 		       WHERE blocker.id = d.depends_on_id
 		         AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+		         AND blocker.deleted_at IS NULL
 		     )
 		  ) as blocked_by_count
 		FROM issues i
 		WHERE i.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+		  AND i.deleted_at IS NULL
 		  AND EXISTS (
 		    SELECT 1 FROM dependencies d
 		    WHERE d.issue_id = i.id
@@ -338,6 +378,7 @@ func (s *MariaDBStore) GetBlockedIssues(ctx context.Context, filter types.WorkFi
 		        SELECT 1 FROM issues blocker
 		        WHERE blocker.id = d.depends_on_id
 		          AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+		          AND blocker.deleted_at IS NULL
 		      )
 		  )
 		ORDER BY i.priority ASC, i.created_at DESC
@@ -362,7 +403,7 @@ func (s *MariaDBStore) GetBlockedIssues(ctx context.Context, filter types.WorkFi
 
 		// Get blocker IDs
 		var blockerIDs []string
-		blockerRows, err := s.db.QueryContext(ctx, `
+		blockerRows, err := s.readQueryContext(ctx, `
 			SELECT d.depends_on_id
 			FROM dependencies d
 			WHERE d.issue_id = ?
@@ -371,6 +412,7 @@ func (s *MariaDBStore) GetBlockedIssues(ctx context.Context, filter types.WorkFi
 			    SELECT 1 FROM issues blocker
 			    WHERE blocker.id = d.depends_on_id
 			      AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+			      AND blocker.deleted_at IS NULL
 			  )
 		`, id)
 		if err != nil {
@@ -398,16 +440,20 @@ func (s *MariaDBStore) GetBlockedIssues(ctx context.Context, filter types.WorkFi
 
 // GetEpicsEligibleForClosure returns epics whose children are all closed
 func (s *MariaDBStore) GetEpicsEligibleForClosure(ctx context.Context) ([]*types.EpicStatus, error) {
-	rows, err := s.db.QueryContext(ctx, `
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	rows, err := s.readQueryContext(ctx, `
 		SELECT e.id,
 		       (SELECT COUNT(*) FROM dependencies d JOIN issues c ON d.issue_id = c.id
-		        WHERE d.depends_on_id = e.id AND d.type = 'parent-child') as total_children,
+		        WHERE d.depends_on_id = e.id AND d.type = 'parent-child' AND `+notSoftDeletedAs("c")+`) as total_children,
 		       (SELECT COUNT(*) FROM dependencies d JOIN issues c ON d.issue_id = c.id
-		        WHERE d.depends_on_id = e.id AND d.type = 'parent-child' AND c.status = 'closed') as closed_children
+		        WHERE d.depends_on_id = e.id AND d.type = 'parent-child' AND c.status = 'closed' AND `+notSoftDeletedAs("c")+`) as closed_children
 		FROM issues e
 		WHERE e.issue_type = 'epic'
 		  AND e.status != 'closed'
 		  AND e.status != 'tombstone'
+		  AND e.deleted_at IS NULL
 		HAVING total_children > 0 AND total_children = closed_children
 	`)
 	if err != nil {
@@ -441,6 +487,9 @@ func (s *MariaDBStore) GetEpicsEligibleForClosure(ctx context.Context) ([]*types
 
 // GetStaleIssues returns issues that haven't been updated recently
 func (s *MariaDBStore) GetStaleIssues(ctx context.Context, filter types.StaleFilter) ([]*types.Issue, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 	cutoff := time.Now().UTC().AddDate(0, 0, -filter.Days)
 
 	statusClause := "status IN ('open', 'in_progress')"
@@ -454,6 +503,7 @@ func (s *MariaDBStore) GetStaleIssues(ctx context.Context, filter types.StaleFil
 		WHERE updated_at < ?
 		  AND %s
 		  AND (ephemeral = 0 OR ephemeral IS NULL)
+		  AND deleted_at IS NULL
 		ORDER BY updated_at ASC
 	`, statusClause)
 	args := []interface{}{cutoff}
@@ -465,22 +515,25 @@ func (s *MariaDBStore) GetStaleIssues(ctx context.Context, filter types.StaleFil
 		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
 	}
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.readQueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stale issues: %w", err)
 	}
 	defer rows.Close()
 
-	return s.scanIssueIDs(ctx, rows)
+	return s.scanIssueIDs(ctx, rows, false)
 }
 
 // GetStatistics returns summary statistics
 func (s *MariaDBStore) GetStatistics(ctx context.Context) (*types.Statistics, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 	stats := &types.Statistics{}
 
 	// Get counts (mirror SQLite semantics: exclude tombstones from TotalIssues, report separately).
 	// Important: COALESCE to avoid NULL scans when the table is empty.
-	err := s.db.QueryRowContext(ctx, `
+	err := s.queryRowContext(ctx, `
 		SELECT
 			COALESCE(SUM(CASE WHEN status != 'tombstone' THEN 1 ELSE 0 END), 0) as total,
 			COALESCE(SUM(CASE WHEN status = 'open' THEN 1 ELSE 0 END), 0) as open_count,
@@ -490,6 +543,7 @@ func (s *MariaDBStore) GetStatistics(ctx context.Context) (*types.Statistics, er
 			COALESCE(SUM(CASE WHEN status = 'tombstone' THEN 1 ELSE 0 END), 0) as tombstone,
 			COALESCE(SUM(CASE WHEN pinned = 1 THEN 1 ELSE 0 END), 0) as pinned
 		FROM issues
+		WHERE `+notSoftDeleted+`
 	`).Scan(
 		&stats.TotalIssues,
 		&stats.OpenIssues,
@@ -508,17 +562,19 @@ func (s *MariaDBStore) GetStatistics(ctx context.Context) (*types.Statistics, er
 	// Step 1: Get IDs of open blockers
 	// Step 2: Count distinct blocked issues from those blockers
 	var blockedCount int
-	blockerRows, err := s.db.QueryContext(ctx, `
+	blockerRows, err := s.readQueryContext(ctx, `
 		SELECT DISTINCT d.issue_id
 		FROM dependencies d
 		WHERE d.type = 'blocks'
 		  AND d.depends_on_id IN (
 		    SELECT id FROM issues
 		    WHERE status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+		      AND deleted_at IS NULL
 		  )
 		  AND d.issue_id IN (
 		    SELECT id FROM issues
 		    WHERE status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+		      AND deleted_at IS NULL
 		  )
 	`)
 	if err == nil {
@@ -543,20 +599,94 @@ func (s *MariaDBStore) GetStatistics(ctx context.Context) (*types.Statistics, er
 	return stats, nil
 }
 
+// countGroupColumns are the issues columns CountIssues may group by.
+// Column names are interpolated into SQL, so only these are accepted.
+var countGroupColumns = map[string]bool{
+	"status":     true,
+	"issue_type": true,
+	"priority":   true,
+	"wisp_type":  true,
+	"assignee":   true,
+}
+
+// CountKeySeparator joins the column values of a CountIssues group key.
+const CountKeySeparator = "|"
+
+// CountIssues counts the issues matching filter, grouped by the groupBy
+// columns (status, issue_type, priority, wisp_type or assignee). Each key holds
+// the group's values in groupBy order joined by CountKeySeparator, e.g.
+// "open|bug"; NULL values are empty. With no groupBy, the total is under "".
+// filter.Limit is ignored.
+func (s *MariaDBStore) CountIssues(ctx context.Context, groupBy []string, filter types.IssueFilter) (map[string]int, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	seen := make(map[string]bool, len(groupBy))
+	for _, col := range groupBy {
+		if !countGroupColumns[col] {
+			return nil, fmt.Errorf("cannot group issues by %q", col)
+		}
+		if seen[col] {
+			return nil, fmt.Errorf("duplicate group-by column %q", col)
+		}
+		seen[col] = true
+	}
+
+	whereSQL, args := issueFilterWhere("", filter)
+	cols := strings.Join(groupBy, ", ")
+	query := "SELECT COUNT(*) FROM issues " + whereSQL
+	if len(groupBy) > 0 {
+		query = "SELECT " + cols + ", COUNT(*) FROM issues " + whereSQL + " GROUP BY " + cols
+	}
+
+	rows, err := s.readQueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count issues: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	values := make([]sql.NullString, len(groupBy))
+	dest := make([]interface{}, len(groupBy)+1)
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		var count int
+		dest[len(groupBy)] = &count
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan issue count: %w", err)
+		}
+		key := make([]string, len(groupBy))
+		for i, v := range values {
+			key[i] = v.String
+		}
+		// NULL and '' fall into the same group
+		counts[strings.Join(key, CountKeySeparator)] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count issues: %w", err)
+	}
+	return counts, nil
+}
+
 // GetMoleculeProgress returns progress stats for a molecule
 func (s *MariaDBStore) GetMoleculeProgress(ctx context.Context, moleculeID string) (*types.MoleculeProgressStats, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
 	stats := &types.MoleculeProgressStats{
 		MoleculeID: moleculeID,
 	}
 
 	// Get molecule title
 	var title sql.NullString
-	err := s.db.QueryRowContext(ctx, "SELECT title FROM issues WHERE id = ?", moleculeID).Scan(&title)
+	err := s.queryRowContext(ctx, "SELECT title FROM issues WHERE id = ?", moleculeID).Scan(&title)
 	if err == nil && title.Valid {
 		stats.MoleculeTitle = title.String
 	}
 
-	err = s.db.QueryRowContext(ctx, `
+	err = s.queryRowContext(ctx, `
 		SELECT
 			COUNT(*) as total,
 			SUM(CASE WHEN status = 'closed' THEN 1 ELSE 0 END) as completed,
@@ -573,7 +703,7 @@ func (s *MariaDBStore) GetMoleculeProgress(ctx context.Context, moleculeID strin
 
 	// Get first in_progress step ID
 	var stepID sql.NullString
-	_ = s.db.QueryRowContext(ctx, `
+	_ = s.queryRowContext(ctx, `
 		SELECT i.id FROM issues i
 		JOIN dependencies d ON i.id = d.issue_id
 		WHERE d.depends_on_id = ?
@@ -591,36 +721,482 @@ func (s *MariaDBStore) GetMoleculeProgress(ctx context.Context, moleculeID strin
 
 // GetNextChildID returns the next available child ID for a parent
 func (s *MariaDBStore) GetNextChildID(ctx context.Context, parentID string) (string, error) {
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
+	if err := s.checkWritable(); err != nil {
 		return "", err
 	}
-	defer tx.Rollback()
+	var nextChild int
+	err := s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		// Get or create counter
+		var lastChild int
+		err := tx.QueryRowContext(ctx, "SELECT last_child FROM child_counters WHERE parent_id = ?", parentID).Scan(&lastChild)
+		if err == sql.ErrNoRows {
+			lastChild = 0
+		} else if err != nil {
+			return err
+		}
+
+		nextChild = lastChild + 1
 
-	// Get or create counter
-	var lastChild int
-	err = tx.QueryRowContext(ctx, "SELECT last_child FROM child_counters WHERE parent_id = ?", parentID).Scan(&lastChild)
-	if err == sql.ErrNoRows {
-		lastChild = 0
-	} else if err != nil {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO child_counters (parent_id, last_child) VALUES (?, ?)
+			ON DUPLICATE KEY UPDATE last_child = ?
+		`, parentID, nextChild, nextChild)
+		return err
+	})
+	if err != nil {
 		return "", err
 	}
 
-	nextChild := lastChild + 1
+	return fmt.Sprintf("%s.%d", parentID, nextChild), nil
+}
+
 
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO child_counters (parent_id, last_child) VALUES (?, ?)
-		ON DUPLICATE KEY UPDATE last_child = ?
-	`, parentID, nextChild, nextChild)
+
+// ReadyFilter narrows ListReadyIssues and ListBlockedIssues. Zero values
+// match everything.
+type ReadyFilter struct {
+	MinPriority *int    // Lowest priority number to include (inclusive)
+	MaxPriority *int    // Highest priority number to include (inclusive)
+	IssueType   string  // Only issues of this type
+	Assignee    *string // Only issues with this assignee
+	Limit       int     // Maximum number of issues; 0 means no limit
+	Offset      int     // Number of issues to skip
+}
+
+// ListReadyIssues returns issues from the ready_issues view that match
+// filter, ordered by priority then ID. Filtering, ordering and paging run in
+// SQL, so only the requested page is read.
+func (s *MariaDBStore) ListReadyIssues(ctx context.Context, filter ReadyFilter) ([]*types.Issue, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	query, args, err := buildViewQuery("ready_issues", "id", filter)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if err := tx.Commit(); err != nil {
-		return "", err
+	rows, err := s.readQueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ready issues: %w", err)
 	}
+	defer rows.Close()
 
-	return fmt.Sprintf("%s.%d", parentID, nextChild), nil
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan issue id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list ready issues: %w", err)
+	}
+	_ = rows.Close() // Close before the nested query (see scanIssueIDs)
+
+	return s.issuesInOrder(ctx, ids)
+}
+
+// OrderKey is a sort key for ListReadyIssuesOrdered. Each key has a natural
+// direction; prefixing it with "-" reverses it.
+type OrderKey string
+
+const (
+	OrderPriority  OrderKey = "priority"   // Most urgent (lowest priority number) first
+	OrderCreatedAt OrderKey = "created_at" // Oldest first
+	OrderFanOut    OrderKey = "fan_out"    // Blocking the most other issues first
+)
+
+// readyOrderColumns maps each allowed OrderKey to its SQL expression and
+// natural direction. Fan-out counts the blocks dependencies on the issue.
+var readyOrderColumns = map[OrderKey]struct {
+	expr string
+	desc bool
+}{
+	OrderPriority:  {"priority", false},
+	OrderCreatedAt: {"created_at", false},
+	OrderFanOut:    {"(SELECT COUNT(*) FROM dependencies d WHERE d.depends_on_id = ready_issues.id AND d.type = 'blocks')", true},
+}
+
+// ListReadyIssuesOrdered returns the issues from the ready_issues view,
+// sorted by the keys of order in turn, then by ID. With no keys, it orders
+// like ListReadyIssues. Unknown and repeated keys are rejected.
+func (s *MariaDBStore) ListReadyIssuesOrdered(ctx context.Context, order []OrderKey) ([]*types.Issue, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	orderBy, err := buildReadyOrderBy(order)
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec // G202: orderBy is built from readyOrderColumns only
+	rows, err := s.readQueryContext(ctx, "SELECT id FROM ready_issues ORDER BY "+orderBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ready issues: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan issue id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list ready issues: %w", err)
+	}
+	_ = rows.Close() // Close before the nested query (see scanIssueIDs)
+
+	return s.issuesInOrder(ctx, ids)
+}
+
+// buildReadyOrderBy returns the ORDER BY list for order, ending with the ID
+// so that the order is total.
+func buildReadyOrderBy(order []OrderKey) (string, error) {
+	if len(order) == 0 {
+		order = []OrderKey{OrderPriority}
+	}
+	seen := make(map[OrderKey]bool, len(order))
+	terms := make([]string, 0, len(order)+1)
+	for _, key := range order {
+		name, reverse := OrderKey(strings.TrimPrefix(string(key), "-")), strings.HasPrefix(string(key), "-")
+		col, ok := readyOrderColumns[name]
+		if !ok {
+			return "", fmt.Errorf("invalid order key %q", key)
+		}
+		if seen[name] {
+			return "", fmt.Errorf("duplicate order key %q", name)
+		}
+		seen[name] = true
+		dir := " ASC"
+		if col.desc != reverse {
+			dir = " DESC"
+		}
+		terms = append(terms, col.expr+dir)
+	}
+	return strings.Join(append(terms, "id ASC"), ", "), nil
 }
 
+// ListBlockedIssues returns issues from the blocked_issues view that match
+// filter, ordered by priority then ID, with the IDs of their open blockers.
+// Filtering, ordering and paging run in SQL, so only the requested page is read.
+func (s *MariaDBStore) ListBlockedIssues(ctx context.Context, filter ReadyFilter) ([]*types.BlockedIssue, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	query, args, err := buildViewQuery("blocked_issues", "id, blocked_by_count", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.readQueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blocked issues: %w", err)
+	}
+	defer rows.Close()
 
+	var ids []string
+	counts := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan blocked issue: %w", err)
+		}
+		ids = append(ids, id)
+		counts[id] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list blocked issues: %w", err)
+	}
+	_ = rows.Close() // Close before the nested queries (see scanIssueIDs)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	issues, err := s.issuesInOrder(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args = make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	// nolint:gosec // G201: placeholders contains only ? markers
+	blockerRows, err := s.readQueryContext(ctx, fmt.Sprintf(`
+		SELECT d.issue_id, d.depends_on_id
+		FROM dependencies d
+		WHERE d.issue_id IN (%s)
+		  AND d.type = 'blocks'
+		  AND EXISTS (
+		    SELECT 1 FROM issues blocker
+		    WHERE blocker.id = d.depends_on_id
+		      AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+		      AND blocker.deleted_at IS NULL
+		  )
+		ORDER BY d.issue_id, d.depends_on_id
+	`, placeholders), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blockers: %w", err)
+	}
+	defer blockerRows.Close()
+	blockers := make(map[string][]string)
+	for blockerRows.Next() {
+		var issueID, blockerID string
+		if err := blockerRows.Scan(&issueID, &blockerID); err != nil {
+			return nil, fmt.Errorf("failed to scan blocker: %w", err)
+		}
+		blockers[issueID] = append(blockers[issueID], blockerID)
+	}
+	if err := blockerRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get blockers: %w", err)
+	}
+
+	results := make([]*types.BlockedIssue, len(issues))
+	for i, issue := range issues {
+		results[i] = &types.BlockedIssue{
+			Issue:          *issue,
+			BlockedByCount: counts[issue.ID],
+			BlockedBy:      blockers[issue.ID],
+		}
+	}
+	return results, nil
+}
+
+// buildViewQuery returns the query selecting columns from view for filter,
+// ordered by priority then ID.
+func buildViewQuery(view, columns string, filter ReadyFilter) (string, []interface{}, error) {
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return "", nil, fmt.Errorf("invalid filter: Limit and Offset must not be negative")
+	}
+
+	var whereClauses []string
+	var args []interface{}
+	if filter.MinPriority != nil {
+		whereClauses = append(whereClauses, "priority >= ?")
+		args = append(args, *filter.MinPriority)
+	}
+	if filter.MaxPriority != nil {
+		whereClauses = append(whereClauses, "priority <= ?")
+		args = append(args, *filter.MaxPriority)
+	}
+	if filter.IssueType != "" {
+		whereClauses = append(whereClauses, "issue_type = ?")
+		args = append(args, filter.IssueType)
+	}
+	if filter.Assignee != nil {
+		whereClauses = append(whereClauses, "assignee = ?")
+		args = append(args, *filter.Assignee)
+	}
+
+	query := "SELECT " + columns + " FROM " + view
+	if len(whereClauses) > 0 {
+		query += " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+	query += " ORDER BY priority ASC, id ASC"
+	switch {
+	case filter.Limit > 0:
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, filter.Limit, filter.Offset)
+	case filter.Offset > 0:
+		// MariaDB has no OFFSET without LIMIT; use the largest row count
+		query += " LIMIT 18446744073709551615 OFFSET ?"
+		args = append(args, filter.Offset)
+	}
+	return query, args, nil
+}
+
+// issuesInOrder fetches the issues with ids, in the order of ids.
+// Issues deleted since ids were read are omitted.
+func (s *MariaDBStore) issuesInOrder(ctx context.Context, ids []string) ([]*types.Issue, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	// ids were selected with the caller's filter, deleted issues included
+	// only if it asked for them
+	issues, err := s.getIssuesByIDs(ctx, ids, true)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*types.Issue, len(issues))
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+	ordered := make([]*types.Issue, 0, len(ids))
+	for _, id := range ids {
+		if issue := byID[id]; issue != nil {
+			ordered = append(ordered, issue)
+		}
+	}
+	return ordered, nil
+}
+
+// Cursor is an opaque position in the ListIssues order. The zero Cursor
+// starts at the first issue.
+type Cursor string
+
+// cursorKey is the ListIssues sort key of the last issue on a page.
+type cursorKey struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+}
+
+func encodeCursor(key cursorKey) Cursor {
+	data, _ := json.Marshal(key)
+	return Cursor(base64.RawURLEncoding.EncodeToString(data))
+}
+
+func decodeCursor(c Cursor) (cursorKey, error) {
+	var key cursorKey
+	data, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil {
+		return key, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if err := json.Unmarshal(data, &key); err != nil || key.ID == "" {
+		return key, fmt.Errorf("invalid cursor %q", c)
+	}
+	return key, nil
+}
+
+// ListIssues returns up to limit issues after the position after, ordered by
+// (created_at, id), and the cursor for the next page. The next cursor is
+// empty once the last issue has been returned. Unlike LIMIT/OFFSET paging,
+// issues created or deleted between calls do not shift later pages: each
+// issue existing throughout is returned exactly once, and new issues appear
+// at the end. Soft-deleted issues are skipped.
+func (s *MariaDBStore) ListIssues(ctx context.Context, after Cursor, limit int) ([]*types.Issue, Cursor, error) {
+	if s.IsClosed() {
+		return nil, "", ErrStoreClosed
+	}
+	if limit <= 0 {
+		return nil, "", fmt.Errorf("invalid limit %d: must be positive", limit)
+	}
+
+	query := "SELECT id, created_at FROM issues WHERE " + notSoftDeleted
+	var args []interface{}
+	if after != "" {
+		key, err := decodeCursor(after)
+		if err != nil {
+			return nil, "", err
+		}
+		query += " AND (created_at > ? OR (created_at = ? AND id > ?))"
+		args = append(args, key.CreatedAt, key.CreatedAt, key.ID)
+	}
+	// Fetch one extra row to learn whether another page follows
+	query += " ORDER BY created_at ASC, id ASC LIMIT ?"
+	args = append(args, limit+1)
+
+	rows, err := s.readQueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list issues: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	var last cursorKey
+	more := false
+	for rows.Next() {
+		if len(ids) == limit {
+			more = true
+			break
+		}
+		if err := rows.Scan(&last.ID, &last.CreatedAt); err != nil {
+			return nil, "", fmt.Errorf("failed to scan issue key: %w", err)
+		}
+		ids = append(ids, last.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to list issues: %w", err)
+	}
+	_ = rows.Close() // Close before the nested query (see scanIssueIDs)
+
+	issues, err := s.issuesInOrder(ctx, ids)
+	if err != nil {
+		return nil, "", err
+	}
+	var next Cursor
+	if more {
+		next = encodeCursor(last)
+	}
+	return issues, next, nil
+}
+
+// ListIssuesModifiedSince returns issues updated after since, ordered by
+// (updated_at, id), for incremental sync to external systems. It returns up
+// to limit issues, plus any more updated in the same second as the last one,
+// so a page never ends partway through a timestamp; and a high-water mark to
+// pass as since in the next call. The mark equals since when nothing changed.
+// Soft-deleted issues are included, with DeletedAt set, so deletions sync too.
+//
+// updated_at is set before a write commits, so a slow transaction can become
+// visible with an updated_at at or before a mark already returned. Callers
+// that can't miss such writes should pass a mark a few seconds earlier and
+// skip issues they've already seen at the same version.
+func (s *MariaDBStore) ListIssuesModifiedSince(ctx context.Context, since time.Time, limit int) ([]*types.Issue, time.Time, error) {
+	if s.IsClosed() {
+		return nil, since, ErrStoreClosed
+	}
+	if limit <= 0 {
+		return nil, since, fmt.Errorf("invalid limit %d: must be positive", limit)
+	}
+
+	ids, last, err := s.modifiedIssueIDs(ctx, `
+		SELECT id, updated_at FROM issues
+		WHERE updated_at > ?
+		ORDER BY updated_at ASC, id ASC LIMIT ?
+	`, since, limit)
+	if err != nil {
+		return nil, since, err
+	}
+	if len(ids) == 0 {
+		return nil, since, nil
+	}
+	mark := last
+	if len(ids) == limit {
+		// Complete the last timestamp, so the next call can start after it
+		tied, _, err := s.modifiedIssueIDs(ctx, `
+			SELECT id, updated_at FROM issues
+			WHERE updated_at = ? AND id > ?
+			ORDER BY id ASC
+		`, last, ids[len(ids)-1])
+		if err != nil {
+			return nil, since, err
+		}
+		ids = append(ids, tied...)
+	}
+
+	issues, err := s.issuesInOrder(ctx, ids)
+	if err != nil {
+		return nil, since, err
+	}
+	return issues, mark, nil
+}
+
+// modifiedIssueIDs runs a query selecting (id, updated_at) and returns the ids
+// and the last updated_at.
+func (s *MariaDBStore) modifiedIssueIDs(ctx context.Context, query string, args ...interface{}) ([]string, time.Time, error) {
+	rows, err := s.readQueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to list modified issues: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	var last time.Time
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id, &last); err != nil {
+			return nil, time.Time{}, fmt.Errorf("failed to scan issue key: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to list modified issues: %w", err)
+	}
+	return ids, last, nil
+}
@@ -0,0 +1,440 @@
+package mariadb
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestBuildViewQuery(t *testing.T) {
+	one, three := 1, 3
+	alice := "alice"
+	tests := []struct {
+		name     string
+		filter   ReadyFilter
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:    "no filter",
+			wantSQL: "SELECT id FROM ready_issues ORDER BY priority ASC, id ASC",
+		},
+		{
+			name:     "all filters",
+			filter:   ReadyFilter{MinPriority: &one, MaxPriority: &three, IssueType: "bug", Assignee: &alice, Limit: 10, Offset: 20},
+			wantSQL:  "SELECT id FROM ready_issues WHERE priority >= ? AND priority <= ? AND issue_type = ? AND assignee = ? ORDER BY priority ASC, id ASC LIMIT ? OFFSET ?",
+			wantArgs: []interface{}{1, 3, "bug", "alice", 10, 20},
+		},
+		{
+			name:     "offset without limit",
+			filter:   ReadyFilter{Offset: 5},
+			wantSQL:  "SELECT id FROM ready_issues ORDER BY priority ASC, id ASC LIMIT 18446744073709551615 OFFSET ?",
+			wantArgs: []interface{}{5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := buildViewQuery("ready_issues", "id", tt.filter)
+			if err != nil {
+				t.Fatalf("buildViewQuery failed: %v", err)
+			}
+			if query != tt.wantSQL {
+				t.Errorf("query = %q\nwant    %q", query, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	if _, _, err := buildViewQuery("ready_issues", "id", ReadyFilter{Limit: -1}); err == nil {
+		t.Error("expected error for negative limit")
+	}
+}
+
+func TestListReadyIssues(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	alice := "alice"
+	create := func(id string, priority int, issueType types.IssueType, assignee string) {
+		t.Helper()
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: priority, IssueType: issueType, Assignee: assignee}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	create("test-c", 2, types.TypeTask, "alice")
+	create("test-a", 2, types.TypeBug, "")
+	create("test-b", 1, types.TypeTask, "alice")
+	create("test-d", 3, types.TypeBug, "alice")
+	create("test-blocker", 0, types.TypeTask, "")
+	create("test-blocked", 1, types.TypeTask, "alice")
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: "test-blocked", DependsOnID: "test-blocker", Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	one, two := 1, 2
+	tests := []struct {
+		name   string
+		filter ReadyFilter
+		want   []string
+	}{
+		{"all, by priority then id", ReadyFilter{}, []string{"test-blocker", "test-b", "test-a", "test-c", "test-d"}},
+		{"priority range", ReadyFilter{MinPriority: &one, MaxPriority: &two}, []string{"test-b", "test-a", "test-c"}},
+		{"issue type", ReadyFilter{IssueType: "bug"}, []string{"test-a", "test-d"}},
+		{"assignee", ReadyFilter{Assignee: &alice}, []string{"test-b", "test-c", "test-d"}},
+		{"page", ReadyFilter{Limit: 2, Offset: 1}, []string{"test-b", "test-a"}},
+		{"offset only", ReadyFilter{Offset: 3}, []string{"test-c", "test-d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := store.ListReadyIssues(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("ListReadyIssues failed: %v", err)
+			}
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListReadyIssues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	blocked, err := store.ListBlockedIssues(ctx, ReadyFilter{Assignee: &alice})
+	if err != nil {
+		t.Fatalf("ListBlockedIssues failed: %v", err)
+	}
+	if len(blocked) != 1 || blocked[0].ID != "test-blocked" || blocked[0].BlockedByCount != 1 ||
+		!reflect.DeepEqual(blocked[0].BlockedBy, []string{"test-blocker"}) {
+		t.Errorf("ListBlockedIssues = %+v, want test-blocked blocked by test-blocker", blocked)
+	}
+	if blocked, err := store.ListBlockedIssues(ctx, ReadyFilter{IssueType: "bug"}); err != nil || len(blocked) != 0 {
+		t.Errorf("ListBlockedIssues(bug) = %v, %v; want none", blocked, err)
+	}
+}
+
+func TestBuildReadyOrderBy(t *testing.T) {
+	got, err := buildReadyOrderBy([]OrderKey{"-priority", OrderFanOut, OrderCreatedAt})
+	if err != nil {
+		t.Fatalf("buildReadyOrderBy failed: %v", err)
+	}
+	want := "priority DESC, (SELECT COUNT(*) FROM dependencies d WHERE d.depends_on_id = ready_issues.id AND d.type = 'blocks') DESC, created_at ASC, id ASC"
+	if got != want {
+		t.Errorf("buildReadyOrderBy = %q\nwant              %q", got, want)
+	}
+	if got, err := buildReadyOrderBy(nil); err != nil || got != "priority ASC, id ASC" {
+		t.Errorf("buildReadyOrderBy(nil) = %q, %v; want priority then id", got, err)
+	}
+	for _, order := range [][]OrderKey{
+		{"title"},
+		{"priority; DROP TABLE issues"},
+		{"--priority"},
+		{OrderPriority, "-priority"},
+	} {
+		if _, err := buildReadyOrderBy(order); err == nil {
+			t.Errorf("buildReadyOrderBy(%q) succeeded, want error", order)
+		}
+	}
+}
+
+func TestListReadyIssuesOrdered(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	create := func(id string, priority int, age int) {
+		t.Helper()
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: priority, IssueType: types.TypeTask,
+			CreatedAt: base.Add(time.Duration(age) * time.Hour)}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	block := func(id, blocker string) {
+		t.Helper()
+		if err := store.AddDependency(ctx, &types.Dependency{IssueID: id, DependsOnID: blocker, Type: types.DepBlocks}, "tester"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	// Ready: test-a (P2, oldest, blocks none), test-b (P1, blocks one),
+	// test-c (P1, blocks two), test-d (P3, newest, blocks none)
+	create("test-a", 2, 0)
+	create("test-b", 1, 2)
+	create("test-c", 1, 1)
+	create("test-d", 3, 3)
+	create("test-x", 0, 4)
+	create("test-y", 0, 4)
+	create("test-z", 0, 4)
+	block("test-x", "test-c")
+	block("test-y", "test-c")
+	block("test-z", "test-b")
+	// Related issues do not count as fan-out
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: "test-a", DependsOnID: "test-d", Type: types.DepRelated}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	tests := []struct {
+		order []OrderKey
+		want  []string
+	}{
+		{nil, []string{"test-b", "test-c", "test-a", "test-d"}},
+		{[]OrderKey{OrderPriority}, []string{"test-b", "test-c", "test-a", "test-d"}},
+		{[]OrderKey{OrderPriority, OrderCreatedAt}, []string{"test-c", "test-b", "test-a", "test-d"}},
+		{[]OrderKey{OrderCreatedAt}, []string{"test-a", "test-c", "test-b", "test-d"}},
+		{[]OrderKey{"-created_at"}, []string{"test-d", "test-b", "test-c", "test-a"}},
+		{[]OrderKey{OrderFanOut, "-created_at"}, []string{"test-c", "test-b", "test-d", "test-a"}},
+		{[]OrderKey{"-fan_out", OrderPriority}, []string{"test-a", "test-d", "test-b", "test-c"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.order), func(t *testing.T) {
+			issues, err := store.ListReadyIssuesOrdered(ctx, tt.order)
+			if err != nil {
+				t.Fatalf("ListReadyIssuesOrdered failed: %v", err)
+			}
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListReadyIssuesOrdered(%v) = %v, want %v", tt.order, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := store.ListReadyIssuesOrdered(ctx, []OrderKey{"updated_at"}); err == nil {
+		t.Error("expected error for unknown order key")
+	}
+}
+
+func TestListIssuesCursorPaging(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	create := func(id string, createdAt time.Time) {
+		t.Helper()
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, CreatedAt: createdAt}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	want := map[string]bool{}
+	for i := 0; i < 20; i++ {
+		// Pairs of issues share a timestamp, so id breaks the tie
+		id := fmt.Sprintf("test-%02d", i)
+		create(id, base.Add(time.Duration(i/2)*time.Minute))
+		want[id] = true
+	}
+
+	seen := map[string]int{}
+	var cursor Cursor
+	pages := 0
+	for {
+		issues, next, err := store.ListIssues(ctx, cursor, 6)
+		if err != nil {
+			t.Fatalf("ListIssues failed: %v", err)
+		}
+		pages++
+		for i, issue := range issues {
+			seen[issue.ID]++
+			if i > 0 && issues[i-1].CreatedAt.Equal(issue.CreatedAt) && issues[i-1].ID > issue.ID {
+				t.Errorf("page %d not ordered by (created_at, id): %s before %s", pages, issues[i-1].ID, issue.ID)
+			}
+		}
+		if pages == 2 {
+			// Mid-iteration: one issue sorts before the cursor, one after the end
+			create("test-early", base.Add(-time.Hour))
+			create("test-late", base.Add(time.Hour))
+			want["test-late"] = true
+		}
+		if next == "" {
+			break
+		}
+		if pages > 10 {
+			t.Fatal("ListIssues did not terminate")
+		}
+		cursor = next
+	}
+
+	for id := range want {
+		if seen[id] != 1 {
+			t.Errorf("issue %s returned %d times, want once", id, seen[id])
+		}
+	}
+	if seen["test-early"] != 0 {
+		t.Error("issue inserted before the cursor should not appear in later pages")
+	}
+	if pages != 4 {
+		t.Errorf("paged %d times, want 4 (21 issues, 6 per page)", pages)
+	}
+}
+
+func TestListIssuesInvalidArguments(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if _, _, err := store.ListIssues(ctx, "", 0); err == nil {
+		t.Error("expected error for zero limit")
+	}
+	if _, _, err := store.ListIssues(ctx, "not a cursor!", 10); err == nil {
+		t.Error("expected error for malformed cursor")
+	}
+}
+
+func TestListIssuesModifiedSince(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	touch := func(id string, updatedAt time.Time) {
+		t.Helper()
+		if _, err := store.UnderlyingDB().ExecContext(ctx, "UPDATE issues SET updated_at = ? WHERE id = ?", updatedAt, id); err != nil {
+			t.Fatalf("failed to set updated_at of %s: %v", id, err)
+		}
+	}
+	for _, id := range []string{"test-a", "test-b", "test-c", "test-d", "test-e"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	touch("test-a", base.Add(-time.Hour))
+	touch("test-b", base)
+	touch("test-e", base.Add(time.Minute))
+	touch("test-c", base.Add(2*time.Minute))
+	touch("test-d", base.Add(2*time.Minute))
+
+	ids := func(issues []*types.Issue) string {
+		var got []string
+		for _, issue := range issues {
+			got = append(got, issue.ID)
+		}
+		return strings.Join(got, ",")
+	}
+
+	// Only issues touched after since; the page is extended to finish test-c's timestamp
+	issues, mark, err := store.ListIssuesModifiedSince(ctx, base, 2)
+	if err != nil {
+		t.Fatalf("ListIssuesModifiedSince failed: %v", err)
+	}
+	if got := ids(issues); got != "test-e,test-c,test-d" {
+		t.Errorf("first page = %s, want test-e,test-c,test-d", got)
+	}
+	if !mark.Equal(base.Add(2 * time.Minute)) {
+		t.Errorf("mark = %v, want %v", mark, base.Add(2*time.Minute))
+	}
+
+	issues, next, err := store.ListIssuesModifiedSince(ctx, mark, 2)
+	if err != nil {
+		t.Fatalf("ListIssuesModifiedSince failed: %v", err)
+	}
+	if len(issues) != 0 || !next.Equal(mark) {
+		t.Errorf("after the mark got %s, mark %v; want nothing and the same mark", ids(issues), next)
+	}
+
+	touch("test-a", base.Add(time.Hour))
+	issues, _, err = store.ListIssuesModifiedSince(ctx, mark, 2)
+	if err != nil {
+		t.Fatalf("ListIssuesModifiedSince failed: %v", err)
+	}
+	if got := ids(issues); got != "test-a" {
+		t.Errorf("after touching test-a got %s, want test-a", got)
+	}
+
+	if _, _, err := store.ListIssuesModifiedSince(ctx, base, 0); err == nil {
+		t.Error("expected error for zero limit")
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	key := cursorKey{CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), ID: "bd-abc"}
+	got, err := decodeCursor(encodeCursor(key))
+	if err != nil {
+		t.Fatalf("decodeCursor failed: %v", err)
+	}
+	if !got.CreatedAt.Equal(key.CreatedAt) || got.ID != key.ID {
+		t.Errorf("decodeCursor(encodeCursor(%v)) = %v", key, got)
+	}
+	if _, err := decodeCursor(Cursor(base64.RawURLEncoding.EncodeToString([]byte("{}")))); err == nil {
+		t.Error("expected error for a cursor without an ID")
+	}
+}
+
+func TestCountIssues(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	create := func(id string, status types.Status, issueType types.IssueType) {
+		t.Helper()
+		issue := &types.Issue{ID: id, Title: id, Status: status, Priority: 2, IssueType: issueType}
+		if status == types.StatusClosed {
+			now := time.Now()
+			issue.ClosedAt = &now
+		}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	create("test-1", types.StatusOpen, types.TypeBug)
+	create("test-2", types.StatusOpen, types.TypeBug)
+	create("test-3", types.StatusClosed, types.TypeBug)
+	create("test-4", types.StatusOpen, types.TypeTask)
+	create("test-5", types.StatusInProgress, types.TypeFeature)
+
+	open := types.StatusOpen
+	tests := []struct {
+		name    string
+		groupBy []string
+		filter  types.IssueFilter
+		want    map[string]int
+	}{
+		{"no grouping", nil, types.IssueFilter{}, map[string]int{"": 5}},
+		{"issue type", []string{"issue_type"}, types.IssueFilter{}, map[string]int{"bug": 3, "task": 1, "feature": 1}},
+		{"status and issue type", []string{"status", "issue_type"}, types.IssueFilter{},
+			map[string]int{"open|bug": 2, "closed|bug": 1, "open|task": 1, "in_progress|feature": 1}},
+		{"filtered", []string{"issue_type"}, types.IssueFilter{Status: &open}, map[string]int{"bug": 2, "task": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.CountIssues(ctx, tt.groupBy, tt.filter)
+			if err != nil {
+				t.Fatalf("CountIssues failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CountIssues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, groupBy := range [][]string{{"title"}, {"status; DROP TABLE issues"}, {"status", "status"}} {
+		if _, err := store.CountIssues(ctx, groupBy, types.IssueFilter{}); err == nil {
+			t.Errorf("CountIssues(%q) succeeded, want error", groupBy)
+		}
+	}
+}
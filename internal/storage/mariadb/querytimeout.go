@@ -0,0 +1,160 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// queryTimeoutGrace is how much longer than Config.QueryTimeout the server's
+// max_statement_time (max_execution_time on MySQL) allows a statement. The
+// client gives up first, so the caller sees context.DeadlineExceeded; the
+// server limit then stops the statement the abandoned connection left
+// running.
+const queryTimeoutGrace = time.Second
+
+// maxStatementTime returns the max_statement_time session value, in seconds,
+// for a query timeout.
+func maxStatementTime(timeout time.Duration) string {
+	return strconv.FormatFloat((timeout + queryTimeoutGrace).Seconds(), 'f', -1, 64)
+}
+
+// maxExecutionTime returns the MySQL max_execution_time session value, in
+// milliseconds, for a query timeout.
+func maxExecutionTime(timeout time.Duration) string {
+	return strconv.FormatInt((timeout + queryTimeoutGrace).Milliseconds(), 10)
+}
+
+// timeoutConnector wraps a connector so that every statement on its
+// connections runs under a context bounded by timeout.
+type timeoutConnector struct {
+	connector driver.Connector
+	timeout   time.Duration
+}
+
+func (c *timeoutConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mc, ok := conn.(mysqlConn)
+	if !ok {
+		_ = conn.Close()
+		return nil, fmt.Errorf("unexpected MySQL driver connection type %T", conn)
+	}
+	return &timeoutConn{mysqlConn: mc, timeout: c.timeout}, nil
+}
+
+// Driver returns the wrapped connector's driver, so tablePrefixOf still
+// recognizes a prefixed pool.
+func (c *timeoutConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// timeoutConn is a MySQL connection whose statements are cancelled after
+// timeout. Cancelling makes the driver abandon the connection, returning the
+// context's error. Statements with arguments are prepared first, so prepared
+// statements are bounded too.
+type timeoutConn struct {
+	mysqlConn
+	timeout time.Duration
+}
+
+func (c *timeoutConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.mysqlConn.ExecContext(ctx, query, args)
+}
+
+func (c *timeoutConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	rows, err := c.mysqlConn.QueryContext(ctx, query, args)
+	return wrapTimeoutRows(rows, err, cancel)
+}
+
+func (c *timeoutConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *timeoutConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.mysqlConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	ms, ok := stmt.(mysqlStmt)
+	if !ok {
+		_ = stmt.Close()
+		return nil, fmt.Errorf("unexpected MySQL driver statement type %T", stmt)
+	}
+	return &timeoutStmt{mysqlStmt: ms, timeout: c.timeout}, nil
+}
+
+// mysqlStmt is the set of driver interfaces implemented by go-sql-driver/mysql
+// prepared statements.
+type mysqlStmt interface {
+	driver.Stmt
+	driver.StmtExecContext
+	driver.StmtQueryContext
+	driver.NamedValueChecker
+	driver.ColumnConverter
+}
+
+// timeoutStmt is a prepared statement whose executions are cancelled after
+// timeout.
+type timeoutStmt struct {
+	mysqlStmt
+	timeout time.Duration
+}
+
+func (s *timeoutStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.mysqlStmt.ExecContext(ctx, args)
+}
+
+func (s *timeoutStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	rows, err := s.mysqlStmt.QueryContext(ctx, args)
+	return wrapTimeoutRows(rows, err, cancel)
+}
+
+// mysqlRows is the set of driver interfaces implemented by go-sql-driver/mysql
+// result sets, which timeoutRows must keep exposing to database/sql.
+type mysqlRows interface {
+	driver.Rows
+	driver.RowsNextResultSet
+	driver.RowsColumnTypeDatabaseTypeName
+	driver.RowsColumnTypeNullable
+	driver.RowsColumnTypePrecisionScale
+	driver.RowsColumnTypeScanType
+}
+
+// timeoutRows keeps a query's timeout running while its rows are read, and
+// releases it when they are closed.
+type timeoutRows struct {
+	mysqlRows
+	cancel context.CancelFunc
+}
+
+// wrapTimeoutRows returns the result of a query run under a timeout, with
+// cancel deferred to closing the rows.
+func wrapTimeoutRows(rows driver.Rows, err error, cancel context.CancelFunc) (driver.Rows, error) {
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	mr, ok := rows.(mysqlRows)
+	if !ok {
+		_ = rows.Close()
+		cancel()
+		return nil, fmt.Errorf("unexpected MySQL driver rows type %T", rows)
+	}
+	return &timeoutRows{mysqlRows: mr, cancel: cancel}, nil
+}
+
+func (r *timeoutRows) Close() error {
+	defer r.cancel()
+	return r.mysqlRows.Close()
+}
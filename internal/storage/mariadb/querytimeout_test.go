@@ -0,0 +1,37 @@
+package mariadb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueryTimeout(t *testing.T) {
+	store, cleanup := setupTestStoreWithConfig(t, &Config{QueryTimeout: time.Second})
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	start := time.Now()
+	var slept int
+	err := store.UnderlyingDB().QueryRowContext(ctx, "SELECT SLEEP(5)").Scan(&slept)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SELECT SLEEP(5) = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("SELECT SLEEP(5) aborted after %v, want about QueryTimeout (1s)", elapsed)
+	}
+
+	// Statements with arguments are prepared and bounded the same way
+	err = store.UnderlyingDB().QueryRowContext(ctx, "SELECT SLEEP(?)", 5).Scan(&slept)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SELECT SLEEP(?) = %v, want context.DeadlineExceeded", err)
+	}
+
+	// The pool recovers from the discarded connections
+	if _, err := store.GetConfig(ctx, "issue_prefix"); err != nil {
+		t.Errorf("GetConfig after timeouts failed: %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package mariadb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// StoreRegistry shares stores between the users of each database on one
+// server, for processes that manage several Beads repos: instead of every
+// user opening its own store, and pool, they Acquire the database's store
+// from the registry and Release it when done. The store is opened on the
+// first Acquire and closed when the last user releases it. Users must not
+// Close stores they acquired.
+//
+// A StoreRegistry is safe for concurrent use.
+type StoreRegistry struct {
+	base Config // Connection settings shared by every database's store
+
+	mu      sync.Mutex
+	entries map[string]*registryEntry
+	closed  bool
+
+	open func(ctx context.Context, cfg *Config) (*MariaDBStore, error) // New; replaced by tests
+}
+
+// registryEntry is a database's store and its number of users. ready is
+// closed once the store has been opened, or failed to open with err.
+type registryEntry struct {
+	ready chan struct{}
+	store *MariaDBStore
+	err   error
+	refs  int
+}
+
+// ErrRegistryClosed is returned by StoreRegistry.Acquire after Close.
+var ErrRegistryClosed = errors.New("mariadb store registry is closed")
+
+// NewStoreRegistry returns a registry that opens stores with base, with
+// Database set to the database being acquired.
+func NewStoreRegistry(base *Config) *StoreRegistry {
+	return &StoreRegistry{base: *base, entries: make(map[string]*registryEntry), open: New}
+}
+
+// Acquire returns the store for database, opening it if no one holds it,
+// and counts the caller as one of its users. Each successful Acquire must be
+// paired with a Release. Concurrent first Acquires of a database open it
+// once; if that fails, they all get the error, and the next Acquire tries
+// again.
+//
+// The store is opened with a context that no caller can cancel, since it
+// is shared; ctx only bounds how long this caller waits for it. A caller
+// that gives up gets ctx's error and is no longer counted as a user.
+func (r *StoreRegistry) Acquire(ctx context.Context, database string) (*MariaDBStore, error) {
+	if err := validateDatabaseName(database); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil, ErrRegistryClosed
+	}
+	entry, ok := r.entries[database]
+	if ok {
+		entry.refs++
+	} else {
+		entry = &registryEntry{ready: make(chan struct{}), refs: 1}
+		r.entries[database] = entry
+		go r.openEntry(context.WithoutCancel(ctx), database, entry)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		r.mu.Lock()
+		last := r.drop(database, entry)
+		r.mu.Unlock()
+		if last {
+			// The last user left before the open finished
+			go func() { _ = r.closeEntry(database, entry) }()
+		}
+		return nil, ctx.Err()
+	}
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	return entry.store, nil
+}
+
+// openEntry opens the store for entry and wakes its waiters.
+func (r *StoreRegistry) openEntry(ctx context.Context, database string, entry *registryEntry) {
+	cfg := r.base
+	cfg.Database = database
+	entry.store, entry.err = r.open(ctx, &cfg)
+	if entry.err != nil {
+		// Waiters see the error; later Acquires start over
+		r.mu.Lock()
+		if r.entries[database] == entry {
+			delete(r.entries, database)
+		}
+		r.mu.Unlock()
+	}
+	close(entry.ready)
+}
+
+// drop ends one user's use of entry and reports whether the caller must
+// close it, because that was the last user and the registry still held it.
+// r.mu must be held.
+func (r *StoreRegistry) drop(database string, entry *registryEntry) bool {
+	entry.refs--
+	if entry.refs > 0 || r.entries[database] != entry {
+		return false
+	}
+	delete(r.entries, database)
+	return true
+}
+
+// closeEntry closes entry's store once it has been opened.
+func (r *StoreRegistry) closeEntry(database string, entry *registryEntry) error {
+	<-entry.ready
+	if entry.err != nil {
+		return nil
+	}
+	if err := entry.store.Close(); err != nil {
+		return fmt.Errorf("failed to close store for database %s: %w", database, err)
+	}
+	return nil
+}
+
+// Release ends one user's use of the store for database and closes the store
+// if that was the last user.
+func (r *StoreRegistry) Release(database string) error {
+	r.mu.Lock()
+	entry, ok := r.entries[database]
+	if !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("database %s is not held in the registry", database)
+	}
+	last := r.drop(database, entry)
+	r.mu.Unlock()
+	if !last {
+		return nil
+	}
+	return r.closeEntry(database, entry)
+}
+
+// Close closes every store in the registry, whether or not it is still held,
+// and makes later Acquires fail with ErrRegistryClosed.
+func (r *StoreRegistry) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	entries := r.entries
+	r.entries = make(map[string]*registryEntry)
+	r.mu.Unlock()
+
+	var err error
+	for database, entry := range entries {
+		err = errors.Join(err, r.closeEntry(database, entry))
+	}
+	return err
+}
@@ -0,0 +1,240 @@
+package mariadb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubRegistry returns a registry whose stores are opened without a server,
+// and the number of stores it has opened.
+func stubRegistry() (*StoreRegistry, *atomic.Int32) {
+	var opens atomic.Int32
+	r := NewStoreRegistry(&Config{})
+	r.open = func(_ context.Context, cfg *Config) (*MariaDBStore, error) {
+		opens.Add(1)
+		time.Sleep(time.Millisecond) // Let concurrent Acquires pile up
+		return &MariaDBStore{storeState: newStoreState(nil, ""), dbName: cfg.Database}, nil
+	}
+	return r, &opens
+}
+
+func TestStoreRegistryRefCounts(t *testing.T) {
+	r, opens := stubRegistry()
+	ctx := context.Background()
+
+	a, err := r.Acquire(ctx, "beads_a")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	again, err := r.Acquire(ctx, "beads_a")
+	if err != nil {
+		t.Fatalf("second Acquire failed: %v", err)
+	}
+	b, err := r.Acquire(ctx, "beads_b")
+	if err != nil {
+		t.Fatalf("Acquire(beads_b) failed: %v", err)
+	}
+	if again != a || b == a || opens.Load() != 2 {
+		t.Fatalf("got stores %p, %p, %p from %d opens; want beads_a shared and 2 opens", a, again, b, opens.Load())
+	}
+	if a.dbName != "beads_a" || b.dbName != "beads_b" {
+		t.Errorf("stores opened for %s and %s", a.dbName, b.dbName)
+	}
+
+	if err := r.Release("beads_a"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if a.IsClosed() {
+		t.Fatal("store closed while still held")
+	}
+	if err := r.Release("beads_a"); err != nil {
+		t.Fatalf("last Release failed: %v", err)
+	}
+	if !a.IsClosed() {
+		t.Error("store still open after last Release")
+	}
+	if err := r.Release("beads_a"); err == nil {
+		t.Error("Release of a database not held succeeded")
+	}
+
+	// Acquiring after the last release opens a fresh store
+	fresh, err := r.Acquire(ctx, "beads_a")
+	if err != nil || fresh == a || opens.Load() != 3 {
+		t.Errorf("Acquire after release = %p, %v with %d opens; want a new store", fresh, err, opens.Load())
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if !b.IsClosed() || !fresh.IsClosed() {
+		t.Error("Close left stores open")
+	}
+	if _, err := r.Acquire(ctx, "beads_a"); !errors.Is(err, ErrRegistryClosed) {
+		t.Errorf("Acquire after Close = %v, want ErrRegistryClosed", err)
+	}
+	if _, err := NewStoreRegistry(&Config{}).Acquire(ctx, "bad-name"); err == nil {
+		t.Error("Acquire with an invalid database name succeeded")
+	}
+}
+
+func TestStoreRegistryConcurrentAcquire(t *testing.T) {
+	r, opens := stubRegistry()
+	ctx := context.Background()
+
+	const users = 20
+	stores := make([]*MariaDBStore, users)
+	var wg sync.WaitGroup
+	for i := range stores {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			store, err := r.Acquire(ctx, "beads")
+			if err != nil {
+				t.Errorf("Acquire failed: %v", err)
+			}
+			stores[i] = store
+		}(i)
+	}
+	wg.Wait()
+	if opens.Load() != 1 {
+		t.Fatalf("%d concurrent Acquires opened %d stores, want 1", users, opens.Load())
+	}
+	for _, store := range stores[1:] {
+		if store != stores[0] {
+			t.Fatal("concurrent Acquires returned different stores")
+		}
+	}
+
+	for i := 0; i < users; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.Release("beads"); err != nil {
+				t.Errorf("Release failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if !stores[0].IsClosed() {
+		t.Error("store still open after every user released it")
+	}
+}
+
+func TestStoreRegistryOpenFailure(t *testing.T) {
+	r := NewStoreRegistry(&Config{})
+	fail := errors.New("server down")
+	var calls atomic.Int32
+	r.open = func(context.Context, *Config) (*MariaDBStore, error) {
+		if calls.Add(1) == 1 {
+			return nil, fail
+		}
+		return &MariaDBStore{storeState: newStoreState(nil, "")}, nil
+	}
+
+	if _, err := r.Acquire(context.Background(), "beads"); !errors.Is(err, fail) {
+		t.Fatalf("Acquire = %v, want the open error", err)
+	}
+	// The failure is not cached
+	if _, err := r.Acquire(context.Background(), "beads"); err != nil {
+		t.Fatalf("Acquire after a failed open = %v", err)
+	}
+	if err := r.Release("beads"); err != nil {
+		t.Errorf("Release failed: %v", err)
+	}
+}
+
+func TestStoreRegistrySharesPool(t *testing.T) {
+	// Probe for a server the way every DB test does
+	_, cleanup := setupTestStore(t)
+	cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	database := testDatabaseName(t)
+	r := NewStoreRegistry(&Config{})
+	defer r.Close()
+
+	first, err := r.Acquire(ctx, database)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	second, err := r.Acquire(ctx, database)
+	if err != nil {
+		t.Fatalf("second Acquire failed: %v", err)
+	}
+	if second != first || second.UnderlyingDB() != first.UnderlyingDB() {
+		t.Fatal("second Acquire did not reuse the store's pool")
+	}
+
+	if err := r.Release(database); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := second.UnderlyingDB().ExecContext(ctx, "DROP DATABASE "+quoteIdentifier(database)); err != nil {
+		t.Fatalf("pool unusable while still held: %v", err)
+	}
+	if err := r.Release(database); err != nil {
+		t.Fatalf("last Release failed: %v", err)
+	}
+	if !first.IsClosed() {
+		t.Error("store still open after last Release")
+	}
+}
+
+func TestStoreRegistryAcquireCancel(t *testing.T) {
+	r := NewStoreRegistry(&Config{})
+	release := make(chan struct{})
+	var openErr atomic.Value
+	r.open = func(ctx context.Context, cfg *Config) (*MariaDBStore, error) {
+		<-release
+		if err := ctx.Err(); err != nil {
+			openErr.Store(err)
+		}
+		return &MariaDBStore{storeState: newStoreState(nil, ""), dbName: cfg.Database}, nil
+	}
+
+	// The first caller gives up while the store is opening
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.Acquire(ctx, "beads")
+		done <- err
+	}()
+	waiter, cancelWaiter := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelWaiter()
+	if _, err := r.Acquire(waiter, "beads"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire of an opening store = %v, want the waiter's deadline", err)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled Acquire = %v, want context.Canceled", err)
+	}
+
+	// A later caller still gets the store, opened with a live context
+	later := make(chan *MariaDBStore, 1)
+	go func() {
+		store, err := r.Acquire(context.Background(), "beads")
+		if err != nil {
+			t.Errorf("Acquire failed: %v", err)
+		}
+		later <- store
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	store := <-later
+	if err, _ := openErr.Load().(error); err != nil {
+		t.Errorf("store opened with a cancelled context: %v", err)
+	}
+
+	// Only the later caller holds it
+	if err := r.Release("beads"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if store == nil || !store.IsClosed() {
+		t.Error("store still open after its only remaining user released it")
+	}
+}
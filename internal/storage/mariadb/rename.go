@@ -2,7 +2,11 @@ package mariadb
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/steveyegge/beads/internal/types"
@@ -10,122 +14,305 @@ import (
 
 // UpdateIssueID updates an issue ID and all its references
 func (s *MariaDBStore) UpdateIssueID(ctx context.Context, oldID, newID string, issue *types.Issue, actor string) error {
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+	if err := s.checkWritable(); err != nil {
+		return err
 	}
-	defer func() { _ = tx.Rollback() }()
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		// Update the issue itself
+		result, err := tx.ExecContext(ctx, `
+			UPDATE issues
+			SET id = ?, title = ?, description = ?, design = ?, acceptance_criteria = ?, notes = ?, updated_at = ?
+			WHERE id = ?
+		`, newID, issue.Title, issue.Description, issue.Design, issue.AcceptanceCriteria, issue.Notes, time.Now().UTC(), oldID)
+		if err != nil {
+			return fmt.Errorf("failed to update issue ID: %w", err)
+		}
 
-	// Update the issue itself
-	result, err := tx.ExecContext(ctx, `
-		UPDATE issues
-		SET id = ?, title = ?, description = ?, design = ?, acceptance_criteria = ?, notes = ?, updated_at = ?
-		WHERE id = ?
-	`, newID, issue.Title, issue.Description, issue.Design, issue.AcceptanceCriteria, issue.Notes, time.Now().UTC(), oldID)
-	if err != nil {
-		return fmt.Errorf("failed to update issue ID: %w", err)
-	}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("issue not found: %s", oldID)
+		}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-	if rows == 0 {
-		return fmt.Errorf("issue not found: %s", oldID)
-	}
+		// Update references in dependencies
+		_, err = tx.ExecContext(ctx, `UPDATE dependencies SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+		if err != nil {
+			return fmt.Errorf("failed to update issue_id in dependencies: %w", err)
+		}
 
-	// Update references in dependencies
-	_, err = tx.ExecContext(ctx, `UPDATE dependencies SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
-	if err != nil {
-		return fmt.Errorf("failed to update issue_id in dependencies: %w", err)
-	}
+		_, err = tx.ExecContext(ctx, `UPDATE dependencies SET depends_on_id = ? WHERE depends_on_id = ?`, newID, oldID)
+		if err != nil {
+			return fmt.Errorf("failed to update depends_on_id in dependencies: %w", err)
+		}
 
-	_, err = tx.ExecContext(ctx, `UPDATE dependencies SET depends_on_id = ? WHERE depends_on_id = ?`, newID, oldID)
-	if err != nil {
-		return fmt.Errorf("failed to update depends_on_id in dependencies: %w", err)
-	}
+		// Update references in events
+		_, err = tx.ExecContext(ctx, `UPDATE events SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+		if err != nil {
+			return fmt.Errorf("failed to update events: %w", err)
+		}
 
-	// Update references in events
-	_, err = tx.ExecContext(ctx, `UPDATE events SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
-	if err != nil {
-		return fmt.Errorf("failed to update events: %w", err)
-	}
+		// Update references in issue_labels
+		_, err = tx.ExecContext(ctx, `UPDATE issue_labels SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+		if err != nil {
+			return fmt.Errorf("failed to update labels: %w", err)
+		}
 
-	// Update references in labels
-	_, err = tx.ExecContext(ctx, `UPDATE labels SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
-	if err != nil {
-		return fmt.Errorf("failed to update labels: %w", err)
-	}
+		// Update references in comments
+		_, err = tx.ExecContext(ctx, `UPDATE comments SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
+		if err != nil {
+			return fmt.Errorf("failed to update comments: %w", err)
+		}
 
-	// Update references in comments
-	_, err = tx.ExecContext(ctx, `UPDATE comments SET issue_id = ? WHERE issue_id = ?`, newID, oldID)
-	if err != nil {
-		return fmt.Errorf("failed to update comments: %w", err)
+		// Update dirty_issues
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO dirty_issues (issue_id, marked_at)
+			VALUES (?, ?)
+			ON DUPLICATE KEY UPDATE marked_at = VALUES(marked_at)
+		`, newID, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("failed to mark issue dirty: %w", err)
+		}
+
+		// Delete old dirty entry
+		_, err = tx.ExecContext(ctx, `DELETE FROM dirty_issues WHERE issue_id = ?`, oldID)
+		if err != nil {
+			return fmt.Errorf("failed to delete old dirty entry: %w", err)
+		}
+
+		// Record rename event
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO events (issue_id, event_type, actor, old_value, new_value)
+			VALUES (?, 'renamed', ?, ?, ?)
+		`, newID, actor, oldID, newID)
+		if err != nil {
+			return fmt.Errorf("failed to record rename event: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// RenameDependencyPrefix updates the prefix in all dependency records
+func (s *MariaDBStore) RenameDependencyPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
 	}
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		// Update issue_id column
+		_, err := tx.ExecContext(ctx, `
+			UPDATE dependencies
+			SET issue_id = CONCAT(?, SUBSTRING(issue_id, LENGTH(?) + 1))
+			WHERE issue_id LIKE CONCAT(?, '%')
+		`, newPrefix, oldPrefix, oldPrefix)
+		if err != nil {
+			return fmt.Errorf("failed to update issue_id in dependencies: %w", err)
+		}
 
-	// Update dirty_issues
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO dirty_issues (issue_id, marked_at)
-		VALUES (?, ?)
-		ON DUPLICATE KEY UPDATE marked_at = VALUES(marked_at)
-	`, newID, time.Now().UTC())
-	if err != nil {
-		return fmt.Errorf("failed to mark issue dirty: %w", err)
+		// Update depends_on_id column
+		_, err = tx.ExecContext(ctx, `
+			UPDATE dependencies
+			SET depends_on_id = CONCAT(?, SUBSTRING(depends_on_id, LENGTH(?) + 1))
+			WHERE depends_on_id LIKE CONCAT(?, '%')
+		`, newPrefix, oldPrefix, oldPrefix)
+		if err != nil {
+			return fmt.Errorf("failed to update depends_on_id in dependencies: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// RenameCounterPrefix is a no-op with hash-based IDs
+func (s *MariaDBStore) RenameCounterPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
 	}
+	// Hash-based IDs don't use counters
+	return nil
+}
 
-	// Delete old dirty entry
-	_, err = tx.ExecContext(ctx, `DELETE FROM dirty_issues WHERE issue_id = ?`, oldID)
-	if err != nil {
-		return fmt.Errorf("failed to delete old dirty entry: %w", err)
+// remapActor is recorded as the actor of RemapIssueIDs renames.
+const remapActor = "remap"
+
+// remapTempPrefix starts the temporary IDs RemapIssueIDs moves issues
+// through, so that chains and swaps never need two issues with one ID.
+const remapTempPrefix = "~remap-"
+
+// issueIDColumns are the columns outside issues that hold issue IDs.
+var issueIDColumns = []struct{ table, column string }{
+	{"dependencies", "issue_id"},
+	{"dependencies", "depends_on_id"},
+	{"issue_labels", "issue_id"},
+	{"comments", "issue_id"},
+	{"events", "issue_id"},
+	{"dirty_issues", "issue_id"},
+	{"export_hashes", "issue_id"},
+	{"child_counters", "parent_id"},
+	{"issue_snapshots", "issue_id"},
+	{"compaction_snapshots", "issue_id"},
+	{"interactions", "issue_id"},
+	{"issue_audit", "issue_id"},
+}
+
+// RemapIssueIDs renames issues in one transaction, mapping old IDs to new
+// ones, and rewrites every reference to them: both sides of dependencies,
+// and their labels, comments, events, audit history, and snapshots. The mapping
+// is applied all at once, so chains (a to b and b to c) and swaps work, and
+// a dependency between two renamed issues, or of an issue on itself, follows
+// both of them. Entries that map an ID to itself are ignored.
+//
+// Collisions are checked before anything changes: every old ID must exist,
+// no two may map to the same new ID, and a new ID may only belong to an
+// existing issue if the mapping renames that issue too. Each renamed issue
+// gets a renamed event and is marked dirty.
+func (s *MariaDBStore) RemapIssueIDs(ctx context.Context, mapping map[string]string) error {
+	if err := s.checkWritable(); err != nil {
+		return err
 	}
 
-	// Record rename event
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO events (issue_id, event_type, actor, old_value, new_value)
-		VALUES (?, 'renamed', ?, ?, ?)
-	`, newID, actor, oldID, newID)
-	if err != nil {
-		return fmt.Errorf("failed to record rename event: %w", err)
+	var oldIDs []string
+	targets := make(map[string]string, len(mapping))
+	for oldID, newID := range mapping {
+		if oldID == newID {
+			continue
+		}
+		if newID == "" {
+			return fmt.Errorf("invalid mapping: empty new ID for %s", oldID)
+		}
+		if other, ok := targets[newID]; ok {
+			return fmt.Errorf("invalid mapping: both %s and %s map to %s", other, oldID, newID)
+		}
+		targets[newID] = oldID
+		oldIDs = append(oldIDs, oldID)
 	}
+	if len(oldIDs) == 0 {
+		return nil
+	}
+	sort.Strings(oldIDs)
+
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		for _, oldID := range oldIDs {
+			if exists, err := issueExists(ctx, tx, oldID); err != nil {
+				return err
+			} else if !exists {
+				return fmt.Errorf("issue not found: %s", oldID)
+			}
+			newID := mapping[oldID]
+			if _, renamed := mapping[newID]; renamed && mapping[newID] != newID {
+				continue // Freed by the remap
+			}
+			if exists, err := issueExists(ctx, tx, newID); err != nil {
+				return err
+			} else if exists {
+				return fmt.Errorf("cannot remap %s to %s: issue %s already exists", oldID, newID, newID)
+			}
+		}
+		var temps int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM issues WHERE id LIKE ?", remapTempPrefix+"%").Scan(&temps); err != nil {
+			return fmt.Errorf("failed to check for temporary IDs: %w", err)
+		}
+		if temps > 0 {
+			return fmt.Errorf("cannot remap issues: %d issue IDs start with %q", temps, remapTempPrefix)
+		}
 
-	return tx.Commit()
+		columns, err := issueColumns(ctx, tx)
+		if err != nil {
+			return err
+		}
+		for i, oldID := range oldIDs {
+			if err := moveIssueID(ctx, tx, columns, oldID, remapTempPrefix+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+		now := time.Now().UTC()
+		for i, oldID := range oldIDs {
+			newID := mapping[oldID]
+			if err := moveIssueID(ctx, tx, columns, remapTempPrefix+strconv.Itoa(i), newID); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, "UPDATE issues SET updated_at = ?, version = version + 1 WHERE id = ?", now, newID); err != nil {
+				return fmt.Errorf("failed to update %s: %w", newID, err)
+			}
+			if err := recordEvent(ctx, tx, newID, "renamed", remapActor, oldID, newID); err != nil {
+				return fmt.Errorf("failed to record rename event: %w", err)
+			}
+			if err := markDirty(ctx, tx, newID); err != nil {
+				return fmt.Errorf("failed to mark dirty: %w", err)
+			}
+		}
+		return nil
+	})
 }
 
-// RenameDependencyPrefix updates the prefix in all dependency records
-func (s *MariaDBStore) RenameDependencyPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+// issueExists reports whether an issue with id exists, soft-deleted or not.
+func issueExists(ctx context.Context, tx *sql.Tx, id string) (bool, error) {
+	var count int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM issues WHERE id = ?", id).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check issue %s: %w", id, err)
 	}
-	defer func() { _ = tx.Rollback() }()
+	return count > 0, nil
+}
 
-	// Update issue_id column
-	_, err = tx.ExecContext(ctx, `
-		UPDATE dependencies
-		SET issue_id = CONCAT(?, SUBSTRING(issue_id, LENGTH(?) + 1))
-		WHERE issue_id LIKE CONCAT(?, '%')
-	`, newPrefix, oldPrefix, oldPrefix)
+// issueColumns returns the columns of the issues table, in order.
+func issueColumns(ctx context.Context, tx *sql.Tx) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, "SHOW COLUMNS FROM issues")
 	if err != nil {
-		return fmt.Errorf("failed to update issue_id in dependencies: %w", err)
+		return nil, fmt.Errorf("failed to read issue columns: %w", err)
 	}
-
-	// Update depends_on_id column
-	_, err = tx.ExecContext(ctx, `
-		UPDATE dependencies
-		SET depends_on_id = CONCAT(?, SUBSTRING(depends_on_id, LENGTH(?) + 1))
-		WHERE depends_on_id LIKE CONCAT(?, '%')
-	`, newPrefix, oldPrefix, oldPrefix)
+	defer rows.Close()
+	columnNames, err := rows.Columns()
 	if err != nil {
-		return fmt.Errorf("failed to update depends_on_id in dependencies: %w", err)
+		return nil, fmt.Errorf("failed to read issue columns: %w", err)
 	}
-
-	return tx.Commit()
+	values := make([]sql.RawBytes, len(columnNames))
+	dest := make([]interface{}, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	var columns []string
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan issue column: %w", err)
+		}
+		columns = append(columns, string(values[0])) // Field
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read issue columns: %w", err)
+	}
+	return columns, nil
 }
 
-// RenameCounterPrefix is a no-op with hash-based IDs
-func (s *MariaDBStore) RenameCounterPrefix(ctx context.Context, oldPrefix, newPrefix string) error {
-	// Hash-based IDs don't use counters
+// moveIssueID renames issue from to to within tx. The foreign keys on issue
+// IDs don't cascade updates, so the issue is copied to its new ID, the rows
+// referring to it are moved over, and the old row is deleted.
+func moveIssueID(ctx context.Context, tx *sql.Tx, columns []string, from, to string) error {
+	quoted := make([]string, len(columns))
+	selected := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quoteIdentifier(col)
+		selected[i] = quoted[i]
+		if col == "id" {
+			selected[i] = "?"
+		}
+	}
+	// nolint:gosec // G202: columns are the issues table's own column names, quoted
+	query := "INSERT INTO issues (" + strings.Join(quoted, ", ") + ") SELECT " + strings.Join(selected, ", ") + " FROM issues WHERE id = ?"
+	if _, err := tx.ExecContext(ctx, query, to, from); err != nil {
+		return fmt.Errorf("failed to copy issue %s to %s: %w", from, to, err)
+	}
+	for _, ref := range issueIDColumns {
+		// nolint:gosec // G201: table and column come from issueIDColumns
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", ref.table, ref.column, ref.column), to, from); err != nil {
+			return fmt.Errorf("failed to update %s.%s: %w", ref.table, ref.column, err)
+		}
+	}
+	if err := recordHardDelete(ctx, tx, from); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM issues WHERE id = ?", from); err != nil {
+		return fmt.Errorf("failed to delete issue %s: %w", from, err)
+	}
 	return nil
 }
-
-
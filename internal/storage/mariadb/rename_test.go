@@ -0,0 +1,113 @@
+package mariadb
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestRemapIssueIDs(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for _, id := range []string{"test-a", "test-b", "test-d"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	for _, dep := range [][2]string{{"test-b", "test-a"}, {"test-d", "test-b"}, {"test-d", "ext-1"}} {
+		if err := store.AddDependency(ctx, &types.Dependency{IssueID: dep[0], DependsOnID: dep[1], Type: types.DepBlocks}, "tester"); err != nil {
+			t.Fatalf("AddDependency(%s -> %s) failed: %v", dep[0], dep[1], err)
+		}
+	}
+	// AddDependency rejects self-dependencies, so write one as legacy data would be
+	if _, err := store.UnderlyingDB().ExecContext(ctx,
+		"INSERT INTO dependencies (issue_id, depends_on_id, type, created_by) VALUES (?, ?, ?, ?)",
+		"test-a", "test-a", types.DepBlocks, "legacy"); err != nil {
+		t.Fatalf("failed to insert test-a -> test-a: %v", err)
+	}
+	if err := store.AddLabel(ctx, "test-a", "moved", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	// Collisions fail before anything changes
+	for _, mapping := range []map[string]string{
+		{"test-a": "test-d"},
+		{"test-a": "test-x", "test-b": "test-x"},
+		{"test-missing": "test-y"},
+		{"test-a": ""},
+	} {
+		if err := store.RemapIssueIDs(ctx, mapping); err == nil {
+			t.Errorf("RemapIssueIDs(%v) succeeded, want error", mapping)
+		}
+	}
+	if issue, err := store.GetIssue(ctx, "test-a"); err != nil || issue == nil {
+		t.Fatalf("GetIssue(test-a) = %v, %v; want it unchanged after failed remaps", issue, err)
+	}
+
+	versionA, err := store.GetIssueVersion(ctx, "test-a")
+	if err != nil {
+		t.Fatalf("GetIssueVersion failed: %v", err)
+	}
+
+	// A chain: test-b takes test-a's old ID, and test-c is new
+	mapping := map[string]string{"test-a": "test-b", "test-b": "test-c", "test-d": "test-d"}
+	if err := store.RemapIssueIDs(ctx, mapping); err != nil {
+		t.Fatalf("RemapIssueIDs failed: %v", err)
+	}
+
+	for id, title := range map[string]string{"test-b": "test-a", "test-c": "test-b", "test-d": "test-d"} {
+		issue, err := store.GetIssue(ctx, id)
+		if err != nil || issue == nil || issue.Title != title {
+			t.Errorf("GetIssue(%s) = %v, %v; want the issue titled %s", id, issue, err, title)
+		}
+	}
+	if issue, err := store.GetIssue(ctx, "test-a"); err != nil || issue != nil {
+		t.Errorf("GetIssue(test-a) = %v, %v; want nil", issue, err)
+	}
+	if version, err := store.GetIssueVersion(ctx, "test-b"); err != nil || version != versionA+1 {
+		t.Errorf("version of the issue renamed to test-b = %d, %v; want %d", version, err, versionA+1)
+	}
+
+	deps, err := store.GetAllDependencyRecords(ctx)
+	if err != nil {
+		t.Fatalf("GetAllDependencyRecords failed: %v", err)
+	}
+	var got []string
+	for _, records := range deps {
+		for _, dep := range records {
+			got = append(got, dep.IssueID+" -> "+dep.DependsOnID)
+		}
+	}
+	sort.Strings(got)
+	want := []string{"test-b -> test-b", "test-c -> test-b", "test-d -> ext-1", "test-d -> test-c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dependencies = %v, want %v", got, want)
+	}
+
+	if labels, err := store.GetLabels(ctx, "test-b"); err != nil || !reflect.DeepEqual(labels, []string{"moved"}) {
+		t.Errorf("GetLabels(test-b) = %v, %v; want [moved]", labels, err)
+	}
+	events, err := store.GetEvents(ctx, "test-c", 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	var created, renamed bool
+	for _, e := range events {
+		switch {
+		case e.EventType == types.EventCreated:
+			created = true
+		case e.EventType == "renamed" && e.OldValue != nil && *e.OldValue == "test-b":
+			renamed = true
+		}
+	}
+	if !created || !renamed {
+		t.Errorf("events of test-c = %v, want its creation event and a rename from test-b", events)
+	}
+}
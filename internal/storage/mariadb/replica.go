@@ -0,0 +1,155 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// replicaRetryInterval is how long reads stay on the primary after a replica
+// could not be reached, before the replica pool is tried again.
+const replicaRetryInterval = 30 * time.Second
+
+// openReplicaPool opens one connection pool spanning cfg.ReplicaHosts.
+// New connections dial the replicas round-robin, moving on to the next
+// replica when one cannot be reached.
+//
+// With TLS verification enabled, every replica must present a certificate
+// valid for the first replica's host name. The replicas are assumed to be the
+// same flavor of server as the primary (see buildPoolDSN).
+func openReplicaPool(cfg *Config, mariadb bool) (*sql.DB, error) {
+	addrs := replicaAddrs(cfg)
+
+	mc, err := buildPoolConfig(cfg, cfg.Database, mariadb)
+	if err != nil {
+		return nil, err
+	}
+	mc.Net = "tcp"
+	mc.Addr = addrs[0]
+
+	dialer := &net.Dialer{Timeout: cfg.ConnectTimeout}
+	var next atomic.Uint32
+	mc.DialFunc = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		start := int(next.Add(1))
+		var errs []error
+		for i := range addrs {
+			conn, err := dialer.DialContext(ctx, "tcp", addrs[(start+i)%len(addrs)])
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	connector, err := mysql.NewConnector(mc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure MariaDB replica connection: %w", err)
+	}
+	db := sql.OpenDB(wrapConnector(connector, connOptionsFromConfig(cfg)))
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	return db, nil
+}
+
+// replicaAddrs returns cfg.ReplicaHosts as host:port addresses.
+func replicaAddrs(cfg *Config) []string {
+	addrs := make([]string, len(cfg.ReplicaHosts))
+	for i, host := range cfg.ReplicaHosts {
+		if _, _, err := net.SplitHostPort(host); err == nil {
+			addrs[i] = host
+		} else {
+			addrs[i] = net.JoinHostPort(host, strconv.Itoa(cfg.Port))
+		}
+	}
+	return addrs
+}
+
+// ReadDB returns the pool used for read-only queries: the replica pool when
+// ReplicaHosts are configured and reachable, otherwise the primary
+// (the same pool as UnderlyingDB).
+//
+// Replicas may lag the primary, so reads that must observe a preceding write
+// should use UnderlyingDB.
+func (s *MariaDBStore) ReadDB() *sql.DB {
+	if replica := s.readReplica(); replica != nil {
+		return replica
+	}
+	return s.UnderlyingDB()
+}
+
+// readReplica returns the replica pool if reads should go to it, or nil:
+// without ReplicaHosts, while the replica is skipped after a failure, and
+// once the store is closed.
+func (s *MariaDBStore) readReplica() *sql.DB {
+	if time.Now().UnixNano() < s.replicaDownUntil.Load() {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.replica
+}
+
+// readQueryContext runs a read-only query on ReadDB. If the replica cannot be
+// reached, the query is retried on the primary and the replica is skipped for
+// replicaRetryInterval. Other transient errors are retried under
+// withReadRetry, like queryContext.
+func (s *MariaDBStore) readQueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := s.withReadRetry(ctx, func() error {
+		var err error
+		rows, err = s.readQueryOnce(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// readQueryOnce runs one attempt of readQueryContext.
+func (s *MariaDBStore) readQueryOnce(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if s.tx != nil {
+		// Inside WithTx, reads must see the transaction's own writes
+		return s.tx.QueryContext(ctx, query, args...)
+	}
+	primary, done, err := s.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	replica := s.readReplica()
+	if replica == nil {
+		return primary.QueryContext(ctx, query, args...)
+	}
+	var rows *sql.Rows
+	if pos := s.gtidPos(); pos != "" {
+		rows, err = s.queryReplicaAtGTID(ctx, primary, replica, pos, query, args...)
+	} else {
+		rows, err = replica.QueryContext(ctx, query, args...)
+	}
+	if err == nil || !isReplicaUnavailable(ctx, err) {
+		return rows, err
+	}
+	s.replicaDownUntil.Store(time.Now().Add(replicaRetryInterval).UnixNano())
+	return primary.QueryContext(ctx, query, args...)
+}
+
+// isReplicaUnavailable returns true if err means the replica could not serve
+// the query at all, as opposed to the query itself failing.
+func isReplicaUnavailable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		// The server answered; only a connection being torn down counts
+		return mysqlErr.Number == errServerShutdown || mysqlErr.Number == errConnectionKilled
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package mariadb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestReplicaAddrs(t *testing.T) {
+	cfg := &Config{Port: 3306, ReplicaHosts: []string{"replica1", "replica2:3307", "::1", "[::1]:3308"}}
+	want := []string{"replica1:3306", "replica2:3307", "[::1]:3306", "[::1]:3308"}
+	if got := replicaAddrs(cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("replicaAddrs = %v, want %v", got, want)
+	}
+}
+
+func TestReadDBWithoutReplicas(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	if store.ReadDB() != store.UnderlyingDB() {
+		t.Error("ReadDB should be the primary when no replicas are configured")
+	}
+}
+
+func TestReplicaRouting(t *testing.T) {
+	// The "replica" is the same local server, reached through a second pool
+	store, cleanup := setupTestStoreWithConfig(t, &Config{ReplicaHosts: []string{"127.0.0.1"}})
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	if store.ReadDB() == store.UnderlyingDB() {
+		t.Fatal("ReadDB should be the replica pool")
+	}
+
+	issue := &types.Issue{Title: "Replica read", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != issue.ID {
+		t.Errorf("GetReadyWork = %v, want [%s]", ready, issue.ID)
+	}
+	if store.ReadDB().Stats().OpenConnections < 1 {
+		t.Error("expected GetReadyWork to use the replica pool")
+	}
+}
+
+func TestReplicaFallback(t *testing.T) {
+	// Nothing listens on port 1, so every replica read falls back to the primary
+	store, cleanup := setupTestStoreWithConfig(t, &Config{ReplicaHosts: []string{"127.0.0.1:1"}})
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	issue := &types.Issue{Title: "Fallback read", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 1 {
+		t.Errorf("GetReadyWork returned %d issues, want 1", len(ready))
+	}
+	if store.ReadDB() != store.UnderlyingDB() {
+		t.Error("ReadDB should fall back to the primary after a replica failure")
+	}
+}
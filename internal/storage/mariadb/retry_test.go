@@ -0,0 +1,727 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: false,
+		},
+		{
+			name:     "driver bad connection",
+			err:      errors.New("driver: bad connection"),
+			expected: true,
+		},
+		{
+			name:     "invalid connection",
+			err:      errors.New("invalid connection"),
+			expected: true,
+		},
+		{
+			name:     "broken pipe",
+			err:      errors.New("write: broken pipe"),
+			expected: true,
+		},
+		{
+			name:     "connection reset",
+			err:      errors.New("read: connection reset by peer"),
+			expected: true,
+		},
+		{
+			name:     "connection refused - not retryable",
+			err:      errors.New("dial tcp: connection refused"),
+			expected: false,
+		},
+		{
+			name:     "deadlock",
+			err:      &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"},
+			expected: true,
+		},
+		{
+			name:     "lock wait timeout",
+			err:      &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"},
+			expected: true,
+		},
+		{
+			name:     "wrapped deadlock",
+			err:      fmt.Errorf("failed to update issue: %w", &mysql.MySQLError{Number: 1213}),
+			expected: true,
+		},
+		{
+			name:     "duplicate key - not retryable",
+			err:      &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"},
+			expected: false,
+		},
+		{
+			name:     "deadlock text without MySQLError - not retryable",
+			err:      errors.New("Error 1213: Deadlock found when trying to get lock"),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := isRetryableError(tt.err)
+			if got != tt.expected {
+				t.Errorf("isRetryableError(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestIsRetryableError_TypedVsMessage runs the message-based fallback and the
+// typed classification over the same inputs to document where they differ.
+func TestIsRetryableError_TypedVsMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		message bool // isRetryableErrorMessage
+		typed   bool // isRetryableError
+	}{
+		{
+			name:    "driver.ErrBadConn",
+			err:     driver.ErrBadConn,
+			message: true,
+			typed:   true,
+		},
+		{
+			name:    "wrapped driver.ErrBadConn",
+			err:     fmt.Errorf("failed to get config: %w", driver.ErrBadConn),
+			message: true,
+			typed:   true,
+		},
+		{
+			name:    "mysql.ErrInvalidConn",
+			err:     mysql.ErrInvalidConn,
+			message: true,
+			typed:   true,
+		},
+		{
+			name:    "EPIPE from net.OpError",
+			err:     &net.OpError{Op: "write", Net: "tcp", Err: os.NewSyscallError("write", syscall.EPIPE)},
+			message: true,
+			typed:   true,
+		},
+		{
+			name:    "ECONNRESET from net.OpError",
+			err:     &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)},
+			message: true,
+			typed:   true,
+		},
+		{
+			name:    "ECONNREFUSED",
+			err:     &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			message: false,
+			typed:   false,
+		},
+		{
+			name:    "deadlock",
+			err:     &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"},
+			message: false,
+			typed:   true,
+		},
+		{
+			name:    "connection killed",
+			err:     &mysql.MySQLError{Number: 1927, Message: "Connection was killed"},
+			message: false,
+			typed:   true,
+		},
+		{
+			name:    "server error mentioning a broken pipe",
+			err:     &mysql.MySQLError{Number: 1064, Message: "syntax error near 'broken pipe'"},
+			message: true,
+			typed:   false,
+		},
+		{
+			name:    "untyped bad connection text",
+			err:     errors.New("driver: bad connection"),
+			message: true,
+			typed:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableErrorMessage(tt.err); got != tt.message {
+				t.Errorf("isRetryableErrorMessage(%v) = %v, want %v", tt.err, got, tt.message)
+			}
+			if got := isRetryableError(tt.err); got != tt.typed {
+				t.Errorf("isRetryableError(%v) = %v, want %v", tt.err, got, tt.typed)
+			}
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	deadlock := &mysql.MySQLError{Number: 1213}
+	badConn := errors.New("driver: bad connection")
+
+	if !shouldRetry(deadlock, true) {
+		t.Error("deadlock should be retried for replayable operations")
+	}
+	if shouldRetry(deadlock, false) {
+		t.Error("deadlock must not be retried for non-replayable operations")
+	}
+	if !shouldRetry(badConn, false) {
+		t.Error("bad connection should be retried for any operation")
+	}
+}
+
+func TestWithRetry_Success(t *testing.T) {
+	store := &MariaDBStore{storeState: newStoreState(nil, "")}
+
+	callCount := 0
+	err := store.withRetry(context.Background(), func() error {
+		callCount++
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if callCount != 1 {
+		t.Errorf("expected 1 call on success, got %d", callCount)
+	}
+}
+
+func TestWithRetry_RetryOnBadConnection(t *testing.T) {
+	store := &MariaDBStore{storeState: newStoreState(nil, "")}
+
+	callCount := 0
+	err := store.withRetry(context.Background(), func() error {
+		callCount++
+		if callCount < 3 {
+			return errors.New("driver: bad connection")
+		}
+		return nil // Success on 3rd attempt
+	})
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if callCount != 3 {
+		t.Errorf("expected 3 calls (2 retries + success), got %d", callCount)
+	}
+	if got := store.Retries(); got != 2 {
+		t.Errorf("Retries() = %d, want 2", got)
+	}
+}
+
+func TestWithRetry_DeadlockNotRetried(t *testing.T) {
+	store := &MariaDBStore{storeState: newStoreState(nil, "")}
+
+	callCount := 0
+	err := store.withRetry(context.Background(), func() error {
+		callCount++
+		return &mysql.MySQLError{Number: 1213}
+	})
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+	if callCount != 1 {
+		t.Errorf("expected 1 call for deadlock without replay, got %d", callCount)
+	}
+}
+
+func TestWithReplayableRetry_RetryOnDeadlock(t *testing.T) {
+	store := &MariaDBStore{storeState: newStoreState(nil, "")}
+
+	callCount := 0
+	err := store.withReplayableRetry(context.Background(), func() error {
+		callCount++
+		if callCount < 3 {
+			return &mysql.MySQLError{Number: 1213}
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if callCount != 3 {
+		t.Errorf("expected 3 calls (2 retries + success), got %d", callCount)
+	}
+}
+
+func TestWithRetry_NonRetryableError(t *testing.T) {
+	store := &MariaDBStore{storeState: newStoreState(nil, "")}
+
+	callCount := 0
+	err := store.withReplayableRetry(context.Background(), func() error {
+		callCount++
+		return errors.New("syntax error in SQL")
+	})
+
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+	if callCount != 1 {
+		t.Errorf("expected 1 call for non-retryable error, got %d", callCount)
+	}
+}
+
+func TestWithRetryTx_CommitNotReplayed(t *testing.T) {
+	conn := &lostCommitConnector{}
+	store := &MariaDBStore{
+		storeState: newStoreState(sql.OpenDB(conn), ""),
+		retryCfg:   retrySettings{initialInterval: time.Millisecond},
+	}
+	defer store.Close()
+
+	callCount := 0
+	err := store.withRetryTx(context.Background(), func(*sql.Tx) error {
+		callCount++
+		return nil
+	})
+	// The commit may have been applied, so the transaction is not run again
+	if !errors.Is(err, mysql.ErrInvalidConn) {
+		t.Errorf("withRetryTx error = %v, want mysql.ErrInvalidConn", err)
+	}
+	if callCount != 1 || conn.commits != 1 {
+		t.Errorf("fn ran %d times and committed %d times, want once each", callCount, conn.commits)
+	}
+}
+
+// lostCommitConnector hands out connections whose commits fail as if the
+// connection was lost before the reply arrived.
+type lostCommitConnector struct {
+	commits int
+}
+
+func (c *lostCommitConnector) Connect(context.Context) (driver.Conn, error) {
+	return lostCommitConn{c}, nil
+}
+func (*lostCommitConnector) Driver() driver.Driver { return nil }
+
+type lostCommitConn struct{ c *lostCommitConnector }
+
+func (lostCommitConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (lostCommitConn) Close() error                        { return nil }
+func (c lostCommitConn) Begin() (driver.Tx, error)         { return c, nil }
+func (lostCommitConn) Rollback() error                     { return nil }
+
+func (c lostCommitConn) Commit() error {
+	c.c.commits++
+	return mysql.ErrInvalidConn
+}
+
+func TestIsReadRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"bad connection", driver.ErrBadConn, true},
+		{"deadlock", &mysql.MySQLError{Number: 1213}, true},
+		{"query interrupted", &mysql.MySQLError{Number: 1317}, true},
+		{"server gone", &mysql.MySQLError{Number: 2006}, true},
+		{"server lost", &mysql.MySQLError{Number: 2013}, true},
+		{"unexpected EOF", fmt.Errorf("read packet: %w", io.ErrUnexpectedEOF), true},
+		{"gone away message", errors.New("MySQL server has gone away"), true},
+		{"duplicate key", &mysql.MySQLError{Number: 1062}, false},
+		{"configured", &mysql.MySQLError{Number: 9001}, true},
+		{"syntax", &mysql.MySQLError{Number: 1064}, false},
+	}
+	extra := retrySettingsFromConfig(&Config{ReadRetryErrors: []uint16{9001}}).readErrors
+	for _, tt := range tests {
+		if got := isReadRetryableError(tt.err, extra); got != tt.want {
+			t.Errorf("isReadRetryableError(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	// Reads are retried more broadly than anything else
+	for _, tt := range tests {
+		if tt.err != nil && isRetryableError(tt.err) && !isReadRetryableError(tt.err, nil) {
+			t.Errorf("%s is retried for writes but not reads", tt.name)
+		}
+	}
+}
+
+func TestWithReadRetry_RetriesInterruptedQuery(t *testing.T) {
+	interrupted := &mysql.MySQLError{Number: 1317, Message: "Query execution was interrupted"}
+	store := &MariaDBStore{storeState: newStoreState(nil, ""), retryCfg: retrySettings{initialInterval: time.Millisecond}}
+
+	callCount := 0
+	err := store.withReadRetry(context.Background(), func() error {
+		callCount++
+		if callCount < 3 {
+			return interrupted
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("withReadRetry: unexpected error: %v", err)
+	}
+	if callCount != 3 {
+		t.Errorf("withReadRetry: expected 3 calls (2 retries + success), got %d", callCount)
+	}
+
+	// A write may have been applied before the interruption, so neither
+	// write path retries it
+	for name, retry := range map[string]func(context.Context, func() error) error{
+		"withRetry":           store.withRetry,
+		"withReplayableRetry": store.withReplayableRetry,
+	} {
+		callCount = 0
+		err := retry(context.Background(), func() error {
+			callCount++
+			return interrupted
+		})
+		if !errors.Is(err, interrupted) || callCount != 1 {
+			t.Errorf("%s: got %v after %d calls, want the interruption after 1", name, err, callCount)
+		}
+	}
+}
+
+// interruptingConnector hands out connections whose queries fail with
+// ER_QUERY_INTERRUPTED until fails runs out, then return no rows.
+type interruptingConnector struct {
+	mu    *sync.Mutex
+	fails *int
+}
+
+func (c interruptingConnector) Connect(context.Context) (driver.Conn, error) {
+	return interruptingConn(c), nil
+}
+func (interruptingConnector) Driver() driver.Driver { return nil }
+
+type interruptingConn interruptingConnector
+
+func (interruptingConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (interruptingConn) Close() error                        { return nil }
+func (interruptingConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c interruptingConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if *c.fails > 0 {
+		*c.fails--
+		return nil, &mysql.MySQLError{Number: 1317, Message: "Query execution was interrupted"}
+	}
+	return emptyRows{}, nil
+}
+
+func TestStoreReadsRetryInterruptedQuery(t *testing.T) {
+	fails := 0
+	conn := interruptingConnector{mu: &sync.Mutex{}, fails: &fails}
+	store := &MariaDBStore{
+		storeState: newStoreState(sql.OpenDB(conn), ""),
+		retryCfg:   retrySettings{initialInterval: time.Millisecond},
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	reads := map[string]func() error{
+		"GetLabels":   func() error { _, err := store.GetLabels(ctx, "test-1"); return err },
+		"GetMetadata": func() error { _, err := store.GetMetadata(ctx, "key"); return err },
+		"SearchIssues": func() error {
+			_, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+			return err
+		},
+	}
+	for name, read := range reads {
+		fails = 2
+		if err := read(); err != nil {
+			t.Errorf("%s failed: %v", name, err)
+		}
+		if fails != 0 {
+			t.Errorf("%s: %d interruptions left, want the read retried past both", name, fails)
+		}
+	}
+}
+
+func TestWithRetry_RetryMaxElapsed(t *testing.T) {
+	store := &MariaDBStore{
+		storeState: newStoreState(nil, ""),
+		retryCfg: retrySettings{
+			maxElapsed:      200 * time.Millisecond,
+			initialInterval: 10 * time.Millisecond,
+			maxInterval:     20 * time.Millisecond,
+		},
+	}
+
+	callCount := 0
+	start := time.Now()
+	err := store.withRetry(context.Background(), func() error {
+		callCount++
+		return errors.New("driver: bad connection")
+	})
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected error from persistently failing op")
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("withRetry took %v, want it to give up soon after 200ms", elapsed)
+	}
+	if callCount < 3 {
+		t.Errorf("expected several attempts with a 10ms interval, got %d", callCount)
+	}
+}
+
+func TestWithRetry_CallerDeadlineShorterThanWindow(t *testing.T) {
+	store := &MariaDBStore{storeState: newStoreState(nil, ""), retryCfg: retrySettings{initialInterval: 10 * time.Millisecond, maxInterval: 20 * time.Millisecond}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := store.withRetry(ctx, func() error {
+		return errors.New("driver: bad connection")
+	})
+	elapsed := time.Since(start)
+
+	// The caller's deadline, not the 30s default window, ends retrying
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("withRetry error = %v, want DeadlineExceeded", err)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("withRetry took %v, want it to stop at the 100ms deadline", elapsed)
+	}
+}
+
+func TestWithRetry_StopOnDeadline(t *testing.T) {
+	errAborted := errors.New("invalid connection")
+	// op simulates a query aborted by the deadline, reported as a transient error
+	run := func(stopOnDeadline bool) (calls int, err error) {
+		store := &MariaDBStore{storeState: newStoreState(nil, ""), retryCfg: retrySettings{initialInterval: time.Millisecond, stopOnDeadline: stopOnDeadline}}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err = store.withRetry(ctx, func() error {
+			calls++
+			<-ctx.Done()
+			return errAborted
+		})
+		return calls, err
+	}
+
+	calls, err := run(true)
+	if calls != 1 {
+		t.Errorf("op ran %d times, want 1", calls)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errAborted) {
+		t.Errorf("withRetry error = %v, want DeadlineExceeded wrapping the op error", err)
+	}
+
+	// Without the option the op error is lost behind the context error
+	if _, err := run(false); errors.Is(err, errAborted) {
+		t.Errorf("withRetry error = %v, want only the context error", err)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	store := &MariaDBStore{storeState: newStoreState(nil, ""), retryCfg: retrySettings{maxElapsed: time.Minute}}
+
+	ctx, cancel := store.WithTimeout(context.Background())
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > time.Minute || time.Until(deadline) < 50*time.Second {
+		t.Errorf("deadline = %v (ok %v), want about a minute from now", deadline, ok)
+	}
+
+	// A caller deadline shorter than the retry window wins
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Second)
+	defer cancelParent()
+	ctx, cancel = store.WithTimeout(parent)
+	defer cancel()
+	if deadline, _ := ctx.Deadline(); time.Until(deadline) > time.Second {
+		t.Errorf("deadline = %v, want the parent's one second", deadline)
+	}
+}
+
+func TestRetrySettingsNewBackoff(t *testing.T) {
+	bo := retrySettings{}.newBackoff().(*backoff.ExponentialBackOff)
+	if bo.MaxElapsedTime != DefaultRetryMaxElapsed || bo.InitialInterval != DefaultRetryInitialInterval || bo.MaxInterval != DefaultRetryMaxInterval || bo.RandomizationFactor != DefaultRetryJitter {
+		t.Errorf("zero settings = elapsed %v, initial %v, max %v, jitter %v; want defaults", bo.MaxElapsedTime, bo.InitialInterval, bo.MaxInterval, bo.RandomizationFactor)
+	}
+
+	bo = retrySettingsFromConfig(&Config{
+		RetryMaxElapsed:      2 * time.Minute,
+		RetryInitialInterval: time.Second,
+		RetryMaxInterval:     10 * time.Second,
+		RetryJitter:          1,
+	}).newBackoff().(*backoff.ExponentialBackOff)
+	if bo.MaxElapsedTime != 2*time.Minute || bo.InitialInterval != time.Second || bo.MaxInterval != 10*time.Second || bo.RandomizationFactor != 1 {
+		t.Errorf("configured settings = elapsed %v, initial %v, max %v, jitter %v", bo.MaxElapsedTime, bo.InitialInterval, bo.MaxInterval, bo.RandomizationFactor)
+	}
+}
+
+// TestRetryJitterSpreadsIntervals simulates many clients failing at once and
+// checks their first retries are spread out rather than in lockstep.
+func TestRetryJitterSpreadsIntervals(t *testing.T) {
+	const clients = 1000
+	initial := time.Second
+	firstWaits := func(jitter float64) (lo, hi time.Duration, distinct int) {
+		seen := make(map[time.Duration]bool)
+		lo = time.Duration(1<<63 - 1)
+		for i := 0; i < clients; i++ {
+			wait := retrySettings{initialInterval: initial, jitter: jitter}.newBackoff().NextBackOff()
+			seen[wait] = true
+			lo, hi = min(lo, wait), max(hi, wait)
+		}
+		return lo, hi, len(seen)
+	}
+
+	if lo, hi, distinct := firstWaits(-1); distinct != 1 || lo != initial || hi != initial {
+		t.Errorf("without jitter: %d distinct waits in [%v, %v], want all %v", distinct, lo, hi, initial)
+	}
+
+	// Default jitter: uniform over [0.5s, 1.5s]
+	lo, hi, distinct := firstWaits(0)
+	if distinct < clients*9/10 {
+		t.Errorf("default jitter: only %d distinct waits of %d", distinct, clients)
+	}
+	if lo < initial/2 || hi > initial*3/2 || hi-lo < initial*8/10 {
+		t.Errorf("default jitter: waits in [%v, %v], want spread across [500ms, 1.5s]", lo, hi)
+	}
+
+	// Full jitter: uniform over [0, 2s]
+	lo, hi, _ = firstWaits(1)
+	if lo > initial/4 || hi < initial*7/4 {
+		t.Errorf("full jitter: waits in [%v, %v], want spread across [0, 2s]", lo, hi)
+	}
+}
+
+func TestNewRejectsInvalidRetryJitter(t *testing.T) {
+	// Rejected before connecting, so no server is needed
+	if _, err := New(context.Background(), &Config{RetryJitter: 1.5}); err == nil {
+		t.Error("expected error for RetryJitter above 1")
+	}
+}
+
+func TestNewRejectsNegativeRetryConfig(t *testing.T) {
+	// Rejected before connecting, so no server is needed
+	_, err := New(context.Background(), &Config{RetryMaxElapsed: -time.Second})
+	if err == nil {
+		t.Error("expected error for negative RetryMaxElapsed")
+	}
+}
+
+// captureHandler is a slog.Handler that records every log record.
+type captureHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *captureHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *captureHandler) WithGroup(string) slog.Handler            { return h }
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+// attrs returns the attributes of r keyed by name.
+func attrs(r slog.Record) map[string]slog.Value {
+	m := map[string]slog.Value{}
+	r.Attrs(func(a slog.Attr) bool {
+		m[a.Key] = a.Value
+		return true
+	})
+	return m
+}
+
+func TestWithRetry_LogsRetry(t *testing.T) {
+	h := &captureHandler{}
+	store := &MariaDBStore{storeState: newStoreState(nil, ""), dbName: "beads", logger: slog.New(h), retryCfg: retrySettings{initialInterval: time.Millisecond}}
+
+	callCount := 0
+	err := store.withRetry(context.Background(), func() error {
+		callCount++
+		if callCount == 1 {
+			return errors.New("driver: bad connection")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(h.records) != 1 {
+		t.Fatalf("expected 1 log record for 1 retry, got %d", len(h.records))
+	}
+	r := h.records[0]
+	if r.Level != slog.LevelWarn {
+		t.Errorf("retry logged at %v, want WARN", r.Level)
+	}
+	a := attrs(r)
+	if got := a["attempt"].Int64(); got != 1 {
+		t.Errorf("attempt = %d, want 1", got)
+	}
+	if got := a["error"].String(); !strings.Contains(got, "bad connection") {
+		t.Errorf("error = %q, want the transient error", got)
+	}
+	if _, ok := a["elapsed"]; !ok {
+		t.Error("retry log should include elapsed time")
+	}
+	if got := a["database"].String(); got != "beads" {
+		t.Errorf("database = %q, want beads", got)
+	}
+}
+
+func TestWithRetry_LogsGiveUp(t *testing.T) {
+	h := &captureHandler{}
+	store := &MariaDBStore{
+		storeState: newStoreState(nil, ""),
+		logger:     slog.New(h),
+		retryCfg: retrySettings{
+			maxElapsed:      50 * time.Millisecond,
+			initialInterval: 5 * time.Millisecond,
+		},
+	}
+
+	err := store.withRetry(context.Background(), func() error {
+		return errors.New("driver: bad connection")
+	})
+	if err == nil {
+		t.Fatal("expected error from persistently failing op")
+	}
+	if len(h.records) < 2 {
+		t.Fatalf("expected retry records and a give-up record, got %d", len(h.records))
+	}
+	last := h.records[len(h.records)-1]
+	if last.Level != slog.LevelError || !strings.Contains(last.Message, "giving up") {
+		t.Errorf("last record = %v %q, want ERROR giving up", last.Level, last.Message)
+	}
+}
+
+func TestWithRetry_NoLogForPermanentError(t *testing.T) {
+	h := &captureHandler{}
+	store := &MariaDBStore{storeState: newStoreState(nil, ""), logger: slog.New(h)}
+
+	_ = store.withRetry(context.Background(), func() error {
+		return errors.New("syntax error in SQL")
+	})
+	if len(h.records) != 0 {
+		t.Errorf("expected no log records for a non-retryable error, got %d", len(h.records))
+	}
+}
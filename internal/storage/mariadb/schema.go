@@ -20,6 +20,7 @@ CREATE TABLE IF NOT EXISTS issues (
     created_by VARCHAR(255) DEFAULT '',
     owner VARCHAR(255) DEFAULT '',
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    version BIGINT NOT NULL DEFAULT 1,
     closed_at DATETIME,
     closed_by_session VARCHAR(255) DEFAULT '',
     external_ref VARCHAR(255),
@@ -82,6 +83,7 @@ CREATE TABLE IF NOT EXISTS issues (
     INDEX idx_issues_issue_type (issue_type),
     INDEX idx_issues_assignee (assignee),
     INDEX idx_issues_created_at (created_at),
+    INDEX idx_issues_updated_at (updated_at, id),
     INDEX idx_issues_spec_id (spec_id),
     INDEX idx_issues_external_ref (external_ref)
 );
@@ -105,13 +107,22 @@ CREATE TABLE IF NOT EXISTS dependencies (
     CONSTRAINT fk_dep_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
 );
 
--- Labels table
+-- Labels table (one row per distinct label)
 CREATE TABLE IF NOT EXISTS labels (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    UNIQUE INDEX idx_labels_name (name)
+);
+
+-- Issue labels table (the labels of each issue). label_id has no foreign
+-- key, so the table can be created before the labels_tables migration
+-- converts a labels table of the old, per-issue form.
+CREATE TABLE IF NOT EXISTS issue_labels (
     issue_id VARCHAR(255) NOT NULL,
-    label VARCHAR(255) NOT NULL,
-    PRIMARY KEY (issue_id, label),
-    INDEX idx_labels_label (label),
-    CONSTRAINT fk_labels_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+    label_id BIGINT NOT NULL,
+    PRIMARY KEY (issue_id, label_id),
+    INDEX idx_issue_labels_label (label_id),
+    CONSTRAINT fk_issue_labels_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
 );
 
 -- Comments table
@@ -241,8 +252,48 @@ CREATE TABLE IF NOT EXISTS interactions (
     INDEX idx_interactions_issue_id (issue_id),
     INDEX idx_interactions_parent_id (parent_id)
 );
+
+-- Issue audit table (one row per changed field, written by UpdateIssue)
+` + issueAuditTable + `;
+
+-- Hard-deleted issues, reported by Watch
+` + deletedIssuesTable + `;
 `
 
+// issueAuditTable records issue field changes. It has no foreign key, so
+// history is kept after the issue is deleted.
+const issueAuditTable = `
+CREATE TABLE IF NOT EXISTS issue_audit (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    issue_id VARCHAR(255) NOT NULL,
+    field VARCHAR(64) NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    changed_at DATETIME(6) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    INDEX idx_issue_audit_issue (issue_id, changed_at)
+)`
+
+// deletedIssuesTable records issues removed by DeleteIssue, which leave no
+// row in issues for Watch to poll. version is the one after the issue's last.
+const deletedIssuesTable = `
+CREATE TABLE IF NOT EXISTS deleted_issues (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    issue_id VARCHAR(255) NOT NULL,
+    version BIGINT NOT NULL,
+    deleted_at DATETIME NOT NULL,
+    INDEX idx_deleted_issues_deleted_at (deleted_at, issue_id)
+)`
+
+// tableNames lists every table created by schema, in creation order,
+// followed by schema_migrations (created by RunMigrations).
+var tableNames = []string{
+	"issues", "dependencies", "labels", "issue_labels", "comments", "events", "config", "metadata",
+	"dirty_issues", "export_hashes", "child_counters", "issue_snapshots",
+	"compaction_snapshots", "repo_mtimes", "routes", "interactions",
+	"issue_audit", "deleted_issues", "schema_migrations",
+}
+
 // defaultConfig contains the default configuration values
 const defaultConfig = `
 INSERT IGNORE INTO config (` + "`key`" + `, value) VALUES
@@ -261,6 +312,7 @@ INSERT IGNORE INTO config (` + "`key`" + `, value) VALUES
 // readyIssuesView is a MySQL-compatible view for ready work
 // Note: MariaDB supports recursive CTEs like MySQL.
 // Uses LEFT JOIN instead of NOT EXISTS to avoid potential performance issues.
+// Soft-deleted issues are neither ready nor blocking.
 const readyIssuesView = `
 CREATE OR REPLACE VIEW ready_issues AS
 WITH RECURSIVE
@@ -272,6 +324,7 @@ WITH RECURSIVE
         SELECT 1 FROM issues blocker
         WHERE blocker.id = d.depends_on_id
           AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+          AND blocker.deleted_at IS NULL
       )
   ),
   blocked_transitively AS (
@@ -289,11 +342,13 @@ FROM issues i
 LEFT JOIN blocked_transitively bt ON bt.issue_id = i.id
 WHERE i.status = 'open'
   AND (i.ephemeral = 0 OR i.ephemeral IS NULL)
+  AND i.deleted_at IS NULL
   AND bt.issue_id IS NULL;
 `
 
 // blockedIssuesView is a MySQL-compatible view for blocked issues.
 // Uses subquery instead of three-table join for better performance.
+// Soft-deleted issues are neither blocked nor blocking.
 const blockedIssuesView = `
 CREATE OR REPLACE VIEW blocked_issues AS
 SELECT
@@ -306,10 +361,12 @@ SELECT
          SELECT 1 FROM issues blocker
          WHERE blocker.id = d.depends_on_id
            AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+           AND blocker.deleted_at IS NULL
        )
     ) as blocked_by_count
 FROM issues i
 WHERE i.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+  AND i.deleted_at IS NULL
   AND EXISTS (
     SELECT 1 FROM dependencies d
     WHERE d.issue_id = i.id
@@ -318,6 +375,7 @@ WHERE i.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
         SELECT 1 FROM issues blocker
         WHERE blocker.id = d.depends_on_id
           AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+          AND blocker.deleted_at IS NULL
       )
   );
 `
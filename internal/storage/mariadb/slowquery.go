@@ -0,0 +1,236 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// explainTimeout bounds the EXPLAIN run for a slow statement.
+const explainTimeout = 5 * time.Second
+
+// slowQueryLog is the Config.SlowQueryThreshold setting of a pool.
+type slowQueryLog struct {
+	threshold time.Duration
+	logger    *slog.Logger
+	database  string
+}
+
+func (l slowQueryLog) enabled() bool {
+	return l.threshold > 0 && l.logger != nil
+}
+
+// explainable reports whether MariaDB can EXPLAIN query.
+func explainable(query string) bool {
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return false
+	}
+	switch strings.ToUpper(words[0]) {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "REPLACE":
+		return true
+	}
+	return false
+}
+
+// slowQueryConnector wraps a connector so that statements on its connections
+// slower than the threshold are logged.
+type slowQueryConnector struct {
+	connector driver.Connector
+	log       slowQueryLog
+}
+
+func (c *slowQueryConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mc, ok := conn.(mysqlConn)
+	if !ok {
+		_ = conn.Close()
+		return nil, fmt.Errorf("unexpected MySQL driver connection type %T", conn)
+	}
+	return &slowQueryConn{mysqlConn: mc, log: c.log}, nil
+}
+
+// Driver returns the wrapped connector's driver, so tablePrefixOf still
+// recognizes a prefixed pool.
+func (c *slowQueryConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// slowQueryConn is a MySQL connection that times its statements. Once a slow
+// statement has finished, the connection is free again, so its plan is read
+// with EXPLAIN on the same connection: inside the same transaction, if any.
+// Failed statements are not logged.
+type slowQueryConn struct {
+	mysqlConn
+	log slowQueryLog
+}
+
+func (c *slowQueryConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	result, err := c.mysqlConn.ExecContext(ctx, query, args)
+	if err == nil {
+		c.check(ctx, query, args, time.Since(start))
+	}
+	return result, err
+}
+
+func (c *slowQueryConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := c.mysqlConn.QueryContext(ctx, query, args)
+	return c.wrapRows(ctx, rows, err, query, args, start)
+}
+
+func (c *slowQueryConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *slowQueryConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.mysqlConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	ms, ok := stmt.(mysqlStmt)
+	if !ok {
+		_ = stmt.Close()
+		return nil, fmt.Errorf("unexpected MySQL driver statement type %T", stmt)
+	}
+	return &slowQueryStmt{mysqlStmt: ms, conn: c, query: query}, nil
+}
+
+// wrapRows returns the result of a query, timing it until the rows are
+// closed.
+func (c *slowQueryConn) wrapRows(ctx context.Context, rows driver.Rows, err error, query string, args []driver.NamedValue, start time.Time) (driver.Rows, error) {
+	if err != nil {
+		return nil, err
+	}
+	mr, ok := rows.(mysqlRows)
+	if !ok {
+		_ = rows.Close()
+		return nil, fmt.Errorf("unexpected MySQL driver rows type %T", rows)
+	}
+	return &slowQueryRows{mysqlRows: mr, closed: func() {
+		c.check(ctx, query, args, time.Since(start))
+	}}, nil
+}
+
+// check logs a statement that took elapsed if it is slow.
+func (c *slowQueryConn) check(ctx context.Context, query string, args []driver.NamedValue, elapsed time.Duration) {
+	if elapsed < c.log.threshold {
+		return
+	}
+	attrs := []any{
+		"database", c.log.database,
+		"duration", elapsed,
+		"query", strings.Join(strings.Fields(query), " "),
+	}
+	if explainable(query) {
+		if plan, err := c.explain(ctx, query, args); err != nil {
+			attrs = append(attrs, "explain_error", err)
+		} else {
+			attrs = append(attrs, "plan", plan)
+		}
+	}
+	c.log.logger.Warn("slow MariaDB query", attrs...)
+}
+
+// explain returns the plan of query, one line per EXPLAIN row. It does not
+// stop with the caller's context: abandoning the EXPLAIN would make the driver
+// discard the connection, along with the caller's transaction.
+func (c *slowQueryConn) explain(ctx context.Context, query string, args []driver.NamedValue) (string, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), explainTimeout)
+	defer cancel()
+
+	query = "EXPLAIN " + query
+	rows, err := c.mysqlConn.QueryContext(ctx, query, args)
+	if errors.Is(err, driver.ErrSkip) {
+		// Statements with arguments must be prepared, as database/sql would
+		var stmt driver.Stmt
+		stmt, err = c.mysqlConn.PrepareContext(ctx, query)
+		if err != nil {
+			return "", err
+		}
+		defer stmt.Close()
+		sq, ok := stmt.(driver.StmtQueryContext)
+		if !ok {
+			return "", fmt.Errorf("unexpected MySQL driver statement type %T", stmt)
+		}
+		rows, err = sq.QueryContext(ctx, args)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	return formatPlan(rows)
+}
+
+// formatPlan renders EXPLAIN rows as lines of column=value pairs, leaving out
+// NULL columns.
+func formatPlan(rows driver.Rows) (string, error) {
+	columns := rows.Columns()
+	values := make([]driver.Value, len(columns))
+	var lines []string
+	for {
+		if err := rows.Next(values); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", err
+		}
+		var fields []string
+		for i, v := range values {
+			switch v := v.(type) {
+			case nil:
+			case []byte:
+				fields = append(fields, columns[i]+"="+string(v))
+			default:
+				fields = append(fields, fmt.Sprintf("%s=%v", columns[i], v))
+			}
+		}
+		lines = append(lines, strings.Join(fields, " "))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// slowQueryStmt is a prepared statement whose executions are timed.
+type slowQueryStmt struct {
+	mysqlStmt
+	conn  *slowQueryConn
+	query string
+}
+
+func (s *slowQueryStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	result, err := s.mysqlStmt.ExecContext(ctx, args)
+	if err == nil {
+		s.conn.check(ctx, s.query, args, time.Since(start))
+	}
+	return result, err
+}
+
+func (s *slowQueryStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := s.mysqlStmt.QueryContext(ctx, args)
+	return s.conn.wrapRows(ctx, rows, err, s.query, args, start)
+}
+
+// slowQueryRows checks its query's duration once the rows are closed, when
+// the connection is free for EXPLAIN.
+type slowQueryRows struct {
+	mysqlRows
+	closed func()
+}
+
+func (r *slowQueryRows) Close() error {
+	if err := r.mysqlRows.Close(); err != nil {
+		return err
+	}
+	r.closed()
+	return nil
+}
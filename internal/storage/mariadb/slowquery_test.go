@@ -0,0 +1,85 @@
+package mariadb
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExplainable(t *testing.T) {
+	for query, want := range map[string]bool{
+		"SELECT 1":                         true,
+		"\n\t\tselect id FROM issues":      true,
+		"UPDATE issues SET title = ?":      true,
+		"INSERT INTO labels VALUES (?, ?)": true,
+		"DELETE FROM events":               true,
+		"COMMIT":                           false,
+		"SET time_zone = '+00:00'":         false,
+		"ALTER TABLE issues ADD x INT":     false,
+		"":                                 false,
+	} {
+		if got := explainable(query); got != want {
+			t.Errorf("explainable(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestSlowQueryThresholdLogsPlan(t *testing.T) {
+	h := &captureHandler{}
+	store, cleanup := setupTestStoreWithConfig(t, &Config{SlowQueryThreshold: 500 * time.Millisecond, Logger: slog.New(h)})
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	slowLogs := func() []slog.Record {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		var records []slog.Record
+		for _, r := range h.records {
+			if r.Message == "slow MariaDB query" {
+				records = append(records, r)
+			}
+		}
+		h.records = nil
+		return records
+	}
+	slowLogs()
+
+	// Fast statements are not logged
+	if _, err := store.GetIssue(ctx, "test-missing"); err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if logs := slowLogs(); len(logs) != 0 {
+		t.Errorf("fast query logged as slow: %v", logs)
+	}
+
+	var slept int
+	for _, query := range []string{"SELECT SLEEP(1)", "SELECT SLEEP(?)"} {
+		var args []any
+		if strings.Contains(query, "?") {
+			args = append(args, 1) // prepared, then explained with the same arguments
+		}
+		if err := store.UnderlyingDB().QueryRowContext(ctx, query, args...).Scan(&slept); err != nil {
+			t.Fatalf("%s failed: %v", query, err)
+		}
+		logs := slowLogs()
+		if len(logs) != 1 {
+			t.Fatalf("%s logged %d slow queries, want 1", query, len(logs))
+		}
+		a := attrs(logs[0])
+		if logs[0].Level != slog.LevelWarn || a["query"].String() != query {
+			t.Errorf("logged %v %q, want WARN %q", logs[0].Level, a["query"].String(), query)
+		}
+		if d := a["duration"].Duration(); d < time.Second {
+			t.Errorf("logged duration %v, want at least 1s", d)
+		}
+		if err, ok := a["explain_error"]; ok {
+			t.Fatalf("EXPLAIN %s failed: %v", query, err)
+		}
+		if plan := a["plan"].String(); !strings.Contains(plan, "select_type=") {
+			t.Errorf("logged plan %q is not EXPLAIN output", plan)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Snapshot is an alias for storage.Snapshot, the format of ExportJSON.
+type Snapshot = storage.Snapshot
+
+// ExportJSON writes a Snapshot of the database to w, as storage.ExportJSON
+// does for any backend.
+func (s *MariaDBStore) ExportJSON(ctx context.Context, w io.Writer) error {
+	return storage.ExportJSON(ctx, s, w)
+}
+
+// ImportJSON reads a Snapshot written by ExportJSON and imports it in a single
+// transaction, implementing storage.SnapshotImporter. Config values are upserted; issues whose ID already exists are
+// resolved as onConflict says. A replaced issue's labels and dependencies are
+// replaced with the snapshot's, while a skipped issue keeps its own.
+func (s *MariaDBStore) ImportJSON(ctx context.Context, r io.Reader, onConflict OnConflict) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if snapshot.FormatVersion != storage.SnapshotFormatVersion {
+		return fmt.Errorf("unsupported snapshot format version %d (want %d)", snapshot.FormatVersion, storage.SnapshotFormatVersion)
+	}
+
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		return importRecords(ctx, tx, s.maxPacket, snapshot.Config, snapshot.Issues, snapshot.Dependencies, onConflict)
+	})
+}
+
+// importRecords imports config values, issues, and dependencies within tx.
+// A dependency is only imported if its issue is in issues and was written
+// rather than skipped, so dependencies must be passed with their issue.
+// maxPacket is passed to execBatchInsert.
+func importRecords(ctx context.Context, tx *sql.Tx, maxPacket int, config map[string]string, issues []*types.Issue, deps []*types.Dependency, onConflict OnConflict) error {
+	for key, value := range config {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO config (`key`, value) VALUES (?, ?) ON DUPLICATE KEY UPDATE value = VALUES(value)", key, value); err != nil {
+			return fmt.Errorf("failed to import config %s: %w", key, err)
+		}
+	}
+
+	// written holds the issues whose labels and dependencies come from the import
+	written := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		if issue.ContentHash == "" {
+			issue.ContentHash = issue.ComputeContentHash()
+		}
+		affected, err := insertIssue(ctx, tx, issue, onConflict)
+		if err != nil {
+			return fmt.Errorf("failed to import issue %s: %w", issue.ID, err)
+		}
+		if affected == 0 && onConflict == ConflictSkip {
+			continue
+		}
+		written[issue.ID] = true
+		if onConflict == ConflictReplace || onConflict == ConflictReplaceUndelete {
+			for _, table := range []string{"issue_labels", "dependencies"} {
+				// nolint:gosec // G201: table is a constant table name
+				if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE issue_id = ?", table), issue.ID); err != nil {
+					return fmt.Errorf("failed to clear %s of %s: %w", table, issue.ID, err)
+				}
+			}
+		}
+		if labels := uniqueLabels(issue.Labels); len(labels) > 0 {
+			if err := attachLabels(ctx, tx, issue.ID, labels); err != nil {
+				return fmt.Errorf("failed to import labels of %s: %w", issue.ID, err)
+			}
+		}
+		if err := markDirty(ctx, tx, issue.ID); err != nil {
+			return fmt.Errorf("failed to mark dirty %s: %w", issue.ID, err)
+		}
+	}
+	var depRows [][]interface{}
+	for _, dep := range deps {
+		if !written[dep.IssueID] {
+			continue
+		}
+		metadata := dep.Metadata
+		if metadata == "" {
+			metadata = "{}"
+		}
+		depRows = append(depRows, []interface{}{dep.IssueID, dep.DependsOnID, dep.Type, dep.CreatedAt, dep.CreatedBy, metadata, dep.ThreadID})
+	}
+	if len(depRows) > 0 {
+		if err := execBatchInsert(ctx, tx, maxPacket,
+			"INSERT INTO dependencies (issue_id, depends_on_id, type, created_at, created_by, metadata, thread_id)",
+			" ON DUPLICATE KEY UPDATE type = VALUES(type), metadata = VALUES(metadata)", depRows); err != nil {
+			return fmt.Errorf("failed to import dependencies: %w", err)
+		}
+	}
+	return nil
+}
+
+// jsonlPageSize is the number of issues ExportJSONL reads per query and
+// ImportJSONL writes per transaction. It is a variable so tests can page
+// through small datasets.
+var jsonlPageSize = 1000
+
+// JSONL record kinds. An export starts with a header, then has the config
+// entries, then each issue followed by its dependencies.
+const (
+	jsonlKindHeader     = "header"
+	jsonlKindConfig     = "config"
+	jsonlKindIssue      = "issue"
+	jsonlKindDependency = "dependency"
+)
+
+// jsonlRecord is one line of a JSON Lines export.
+type jsonlRecord struct {
+	Kind          string            `json:"kind"`
+	FormatVersion int               `json:"format_version,omitempty"`
+	Key           string            `json:"key,omitempty"`
+	Value         string            `json:"value,omitempty"`
+	Issue         *types.Issue      `json:"issue,omitempty"`
+	Dependency    *types.Dependency `json:"dependency,omitempty"`
+}
+
+// ExportJSONL writes the same data as ExportJSON as JSON Lines, one record per
+// line. Issues are read in pages of jsonlPageSize using a keyset cursor on the
+// ID, so memory use does not grow with the size of the database. The pages
+// are separate queries: writes made during the export may be partly included.
+func (s *MariaDBStore) ExportJSONL(ctx context.Context, w io.Writer) error {
+	if s.IsClosed() {
+		return ErrStoreClosed
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(&jsonlRecord{Kind: jsonlKindHeader, FormatVersion: storage.SnapshotFormatVersion}); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	config, err := s.GetAllConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to export config: %w", err)
+	}
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := enc.Encode(&jsonlRecord{Kind: jsonlKindConfig, Key: key, Value: config[key]}); err != nil {
+			return fmt.Errorf("failed to write config %s: %w", key, err)
+		}
+	}
+
+	after := ""
+	for {
+		ids, err := s.nextIssueIDs(ctx, after, jsonlPageSize)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		after = ids[len(ids)-1]
+
+		issues, err := s.getIssuesByIDs(ctx, ids, true)
+		if err != nil {
+			return fmt.Errorf("failed to export issues: %w", err)
+		}
+		sort.Slice(issues, func(i, j int) bool { return issues[i].ID < issues[j].ID })
+		labels, err := s.GetLabelsForIssues(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to export labels: %w", err)
+		}
+		deps, err := s.GetDependencyRecordsForIssues(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to export dependencies: %w", err)
+		}
+
+		for _, issue := range issues {
+			issue.Labels = labels[issue.ID]
+			storage.NormalizeIssueTimes(issue)
+			if err := enc.Encode(&jsonlRecord{Kind: jsonlKindIssue, Issue: issue}); err != nil {
+				return fmt.Errorf("failed to write issue %s: %w", issue.ID, err)
+			}
+			issueDeps := deps[issue.ID]
+			sort.Slice(issueDeps, func(i, j int) bool { return issueDeps[i].DependsOnID < issueDeps[j].DependsOnID })
+			for _, dep := range issueDeps {
+				dep.CreatedAt = dep.CreatedAt.UTC()
+				if err := enc.Encode(&jsonlRecord{Kind: jsonlKindDependency, Dependency: dep}); err != nil {
+					return fmt.Errorf("failed to write dependency %s -> %s: %w", dep.IssueID, dep.DependsOnID, err)
+				}
+			}
+		}
+	}
+}
+
+// nextIssueIDs returns up to limit issue IDs greater than after, in order.
+func (s *MariaDBStore) nextIssueIDs(ctx context.Context, after string, limit int) ([]string, error) {
+	rows, err := s.readQueryContext(ctx, "SELECT id FROM issues WHERE id > ? ORDER BY id LIMIT ?", after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to page issues: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan issue id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ImportJSONL reads an export written by ExportJSONL line by line and imports
+// it in transactions of up to jsonlPageSize issues, so memory use does not
+// grow with the size of the export. Conflicts are handled as in ImportJSON.
+// Unlike ImportJSON the import is not atomic: if it fails, the batches
+// before the failure stay committed, and re-running it with ConflictSkip
+// or ConflictReplace resumes it.
+func (s *MariaDBStore) ImportJSONL(ctx context.Context, r io.Reader, onConflict OnConflict) error {
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(r)
+	var header jsonlRecord
+	if err := dec.Decode(&header); err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+	if header.Kind != jsonlKindHeader || header.FormatVersion != storage.SnapshotFormatVersion {
+		return fmt.Errorf("unsupported JSONL export: header %q, format version %d (want %d)", header.Kind, header.FormatVersion, storage.SnapshotFormatVersion)
+	}
+
+	config := make(map[string]string)
+	var issues []*types.Issue
+	var deps []*types.Dependency
+	flush := func() error {
+		if len(config) == 0 && len(issues) == 0 {
+			return nil
+		}
+		err := s.withRetryTx(ctx, func(tx *sql.Tx) error {
+			return importRecords(ctx, tx, s.maxPacket, config, issues, deps, onConflict)
+		})
+		if err != nil {
+			return err
+		}
+		config = make(map[string]string)
+		issues, deps = nil, nil
+		return nil
+	}
+
+	for line := 2; ; line++ {
+		var record jsonlRecord
+		if err := dec.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to read record %d: %w", line, err)
+		}
+		switch record.Kind {
+		case jsonlKindConfig:
+			config[record.Key] = record.Value
+		case jsonlKindIssue:
+			if record.Issue == nil {
+				return fmt.Errorf("record %d: issue record has no issue", line)
+			}
+			// Flush between issues, so a batch holds each issue's dependencies
+			if len(issues) == jsonlPageSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			issues = append(issues, record.Issue)
+		case jsonlKindDependency:
+			if record.Dependency == nil {
+				return fmt.Errorf("record %d: dependency record has no dependency", line)
+			}
+			deps = append(deps, record.Dependency)
+		default:
+			return fmt.Errorf("record %d: unknown record kind %q", line, record.Kind)
+		}
+	}
+	return flush()
+}
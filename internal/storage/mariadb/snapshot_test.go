@@ -0,0 +1,229 @@
+package mariadb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestExportImportJSON(t *testing.T) {
+	source, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	due := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, issue := range []*types.Issue{
+		{ID: "test-a", Title: "First", Description: "desc", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug, DueAt: &due},
+		{ID: "test-b", Title: "Second", Status: types.StatusClosed, Priority: 2, IssueType: types.TypeTask},
+		{ID: "test-c", Title: "Gone", Status: types.StatusOpen, Priority: 3, IssueType: types.TypeTask},
+	} {
+		if err := source.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", issue.ID, err)
+		}
+	}
+	if err := source.AddLabel(ctx, "test-a", "backend", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := source.AddDependency(ctx, &types.Dependency{IssueID: "test-a", DependsOnID: "test-b", Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := source.SoftDeleteIssue(ctx, "test-c"); err != nil {
+		t.Fatalf("SoftDeleteIssue failed: %v", err)
+	}
+	if err := source.SetConfig(ctx, "custom.key", "value"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	var exported bytes.Buffer
+	if err := source.ExportJSON(ctx, &exported); err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+	if !strings.Contains(exported.String(), `"due_at": "2030-01-02T03:04:05Z"`) {
+		t.Errorf("export does not contain RFC 3339 due_at:\n%s", exported.String())
+	}
+
+	target, cleanupTarget := setupTestStore(t)
+	defer cleanupTarget()
+	if err := target.ImportJSON(ctx, bytes.NewReader(exported.Bytes()), ConflictError); err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	var reexported bytes.Buffer
+	if err := target.ExportJSON(ctx, &reexported); err != nil {
+		t.Fatalf("ExportJSON(target) failed: %v", err)
+	}
+
+	want, got := decodeSnapshot(t, exported.Bytes()), decodeSnapshot(t, reexported.Bytes())
+	got.ExportedAt = want.ExportedAt
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("imported snapshot differs:\n got %s\nwant %s", reexported.String(), exported.String())
+	}
+	if issue, err := target.GetIssue(ctx, "test-c"); err != nil || issue != nil {
+		t.Errorf("GetIssue(soft-deleted) = %v, %v; want nil, nil", issue, err)
+	}
+
+	// Re-importing fails by default, but is a no-op when skipping
+	if err := target.ImportJSON(ctx, bytes.NewReader(exported.Bytes()), ConflictError); err == nil {
+		t.Error("ImportJSON into a populated store should fail with ConflictError")
+	}
+	if err := target.ImportJSON(ctx, bytes.NewReader(exported.Bytes()), ConflictSkip); err != nil {
+		t.Errorf("ImportJSON(ConflictSkip) failed: %v", err)
+	}
+
+	if err := target.ImportJSON(ctx, strings.NewReader(`{"format_version": 99}`), ConflictSkip); err == nil {
+		t.Error("ImportJSON should reject an unknown format version")
+	}
+}
+
+func decodeSnapshot(t *testing.T, data []byte) *Snapshot {
+	t.Helper()
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	return &snapshot
+}
+
+func TestExportImportJSONL(t *testing.T) {
+	source, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	for _, id := range []string{"test-a", "test-b", "test-c"} {
+		issue := &types.Issue{ID: id, Title: "Issue " + id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := source.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	if err := source.AddLabel(ctx, "test-b", "frontend", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	for _, dep := range []*types.Dependency{
+		{IssueID: "test-a", DependsOnID: "test-c", Type: types.DepBlocks},
+		{IssueID: "test-a", DependsOnID: "test-b", Type: types.DepRelated},
+	} {
+		if err := source.AddDependency(ctx, dep, "tester"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+
+	var exported bytes.Buffer
+	if err := source.ExportJSONL(ctx, &exported); err != nil {
+		t.Fatalf("ExportJSONL failed: %v", err)
+	}
+	var kinds []string
+	for _, line := range strings.Split(strings.TrimSpace(exported.String()), "\n") {
+		var record jsonlRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line %q is not a record: %v", line, err)
+		}
+		if record.Kind != jsonlKindConfig {
+			kinds = append(kinds, record.Kind)
+		}
+	}
+	want := []string{"header", "issue", "dependency", "dependency", "issue", "issue"}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("record kinds = %v, want %v", kinds, want)
+	}
+
+	target, cleanupTarget := setupTestStore(t)
+	defer cleanupTarget()
+	if err := target.ImportJSONL(ctx, bytes.NewReader(exported.Bytes()), ConflictError); err != nil {
+		t.Fatalf("ImportJSONL failed: %v", err)
+	}
+	var reexported bytes.Buffer
+	if err := target.ExportJSONL(ctx, &reexported); err != nil {
+		t.Fatalf("ExportJSONL(target) failed: %v", err)
+	}
+	if reexported.String() != exported.String() {
+		t.Errorf("imported export differs:\n got %s\nwant %s", reexported.String(), exported.String())
+	}
+
+	if err := target.ImportJSONL(ctx, strings.NewReader(`{"kind":"issue"}`), ConflictSkip); err == nil {
+		t.Error("ImportJSONL should reject input without a header")
+	}
+}
+
+// heapSampler is a writer that discards its input, recording the peak heap
+// size seen every sampleEvery writes.
+type heapSampler struct {
+	writes      int
+	sampleEvery int
+	peak        uint64
+}
+
+func (h *heapSampler) Write(p []byte) (int, error) {
+	h.writes++
+	if h.writes%h.sampleEvery == 0 {
+		runtime.GC()
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if stats.HeapAlloc > h.peak {
+			h.peak = stats.HeapAlloc
+		}
+	}
+	return len(p), nil
+}
+
+func TestExportJSONLMemoryBounded(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping large export in short mode")
+	}
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	defer func(size int) { jsonlPageSize = size }(jsonlPageSize)
+	jsonlPageSize = 50
+
+	// exportPeak streams the whole database and returns the peak heap size
+	exportPeak := func() uint64 {
+		t.Helper()
+		sampler := &heapSampler{sampleEvery: 100}
+		if err := store.ExportJSONL(ctx, sampler); err != nil {
+			t.Fatalf("ExportJSONL failed: %v", err)
+		}
+		return sampler.peak
+	}
+	// addIssues inserts synthetic 32 KiB issues with IDs in [from, to)
+	description := strings.Repeat("x", 32<<10)
+	addIssues := func(from, to int) {
+		t.Helper()
+		for start := from; start < to; start += jsonlPageSize {
+			var rows [][]interface{}
+			for i := start; i < start+jsonlPageSize && i < to; i++ {
+				rows = append(rows, []interface{}{fmt.Sprintf("test-%06d", i), "Synthetic issue", description, "", "", ""})
+			}
+			err := store.withRetryTx(ctx, func(tx *sql.Tx) error {
+				return execBatchInsert(ctx, tx, store.maxPacket, "INSERT INTO issues (id, title, description, design, acceptance_criteria, notes)", "", rows)
+			})
+			if err != nil {
+				t.Fatalf("failed to insert issues: %v", err)
+			}
+		}
+	}
+
+	addIssues(0, 200)
+	small := exportPeak()
+	addIssues(200, 2000)
+	large := exportPeak()
+
+	// Holding all 2000 issues would take over 60 MiB; a page is under 2 MiB
+	if large > small+16<<20 {
+		t.Errorf("peak heap grew from %d MiB at 200 issues to %d MiB at 2000", small>>20, large>>20)
+	}
+}
@@ -12,61 +12,601 @@ package mariadb
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
-	// Import MySQL driver for MariaDB connections
-	_ "github.com/go-sql-driver/mysql"
+	// MySQL driver for MariaDB connections (also used for typed server errors)
+	"github.com/go-sql-driver/mysql"
 
 	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlutil"
 )
 
+// ErrStoreClosed is returned when an operation is attempted on a closed store.
+var ErrStoreClosed = storage.ErrStoreClosed
+
+// ErrReadOnly is returned when a write is attempted on a store opened with
+// Config.ReadOnly.
+var ErrReadOnly = storage.ErrReadOnly
+
+// ErrPacketTooLarge is returned when a row to be written would not fit in a
+// statement under the server's max_allowed_packet.
+var ErrPacketTooLarge = errors.New("row exceeds max_allowed_packet")
+
 // MariaDBStore implements the Storage interface using MariaDB
 type MariaDBStore struct {
-	db       *sql.DB
-	dbName   string       // Database name
-	closed   atomic.Bool  // Tracks whether Close() has been called
-	connStr  string       // Connection string for reconnection
-	mu       sync.RWMutex // Protects concurrent access
-	readOnly bool         // True if opened in read-only mode
+	*storeState // Pools and lifecycle; shared with WithTx views
+
+	tx       *sql.Tx // Set on the transactional view passed to WithTx fn
+	dbName   string  // Database name
+	readOnly bool    // True if opened in read-only mode
+
+	pool     poolSettings  // Applied to the pool opened by Reconnect
+	retryCfg retrySettings // Backoff used by withRetry and withRetryTx
+	logger   *slog.Logger  // Retry and reconnect events; nil discards them
+
+	// credentialProvider refreshes the user and password in connStr on Reconnect
+	credentialProvider func(ctx context.Context) (user, password string, err error)
+
+	charset   string // Table character set used by schema init
+	collation string // Table collation used by schema init; empty means the charset default
+
+	tablePrefix string // Config.TablePrefix; connections rewrite queries to use it
+
+	conn connOptions // Settings applied by the pool's connections; reused by Reconnect
+
+	breaker *circuitBreaker // Config.BreakerThreshold; nil when disabled
+
+	isolation sql.IsolationLevel // Config.IsolationLevel for transactions
+
+	gtid *gtidTracker // Config.ReadYourWrites; nil when disabled or without replicas
+
+	watchInterval time.Duration // Config.WatchInterval
+
+	idGen IDGenerator // Config.IDGenerator; nil means HashIDs
+
+	maxPacket int // Server's max_allowed_packet, read by New; 0 if unknown
+
+	conns *connTracker // Config.TrackConns; nil when disabled
+
+	caps storage.Capabilities // Detected by New; see Capabilities
+}
+
+// storeState is the part of a store that changes after New: its pools, its
+// closed flag, and the bookkeeping around them. WithTx views are copies of
+// the store, so it is held by pointer and the views share it.
+type storeState struct {
+	mu      sync.RWMutex // Guards primary, replica and connStr
+	primary *trackedPool // nil once the store is closed
+	replica *sql.DB      // Read replica pool; nil when no ReplicaHosts are configured
+	connStr string       // Connection string for reconnection
+
+	closed      atomic.Bool // Tracks whether Close() or Drain() has been called
+	reconnectMu sync.Mutex  // Serializes Reconnect calls
+
+	replicaDownUntil atomic.Int64 // Unix nanos until which reads bypass the replica
+
+	retries atomic.Uint64 // Operations retried by withRetry and withRetryTx
+
+	inflight opCount // Operations Drain waits for before closing the pool
+}
+
+// newStoreState returns the state of a store using the pool db, opened from
+// connStr. db may be nil for a store that is already closed.
+func newStoreState(db *sql.DB, connStr string) *storeState {
+	st := &storeState{connStr: connStr}
+	if db != nil {
+		st.primary = &trackedPool{db: db}
+	}
+	return st
+}
+
+// trackedPool is a primary pool and the operations using it, so that a pool
+// replaced by Reconnect is closed only once they have finished.
+type trackedPool struct {
+	db   *sql.DB
+	busy sync.WaitGroup
+}
+
+// acquire returns the primary pool for one statement or transaction and
+// counts it as in flight, for Drain and Reconnect, until done is called.
+// It fails with ErrStoreClosed once the pool is closed. Unlike track, it
+// still succeeds while Drain waits, so running operations can finish.
+func (s *MariaDBStore) acquire() (db *sql.DB, done func(), err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p := s.primary
+	if p == nil {
+		return nil, nil, ErrStoreClosed
+	}
+	p.busy.Add(1)
+	storeDone := s.inflight.add()
+	return p.db, func() {
+		storeDone()
+		p.busy.Done()
+	}, nil
+}
+
+// querier is the query interface of the store's connections, implemented for
+// the pool and for a WithTx transaction, so store methods run unchanged in
+// either.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) row
+}
+
+// row is the result of querier.QueryRowContext: a *sql.Row, or the error that
+// kept the query from running.
+type row interface {
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+// errRow is a row that failed before it was queried.
+type errRow struct{ err error }
+
+func (r errRow) Scan(...interface{}) error { return r.err }
+func (r errRow) Err() error                { return r.err }
+
+// dbOrTx returns the transaction on a WithTx view, and the pool otherwise.
+func (s *MariaDBStore) dbOrTx() querier {
+	if s.tx != nil {
+		return txQuerier{s.tx}
+	}
+	return s.primaryDB()
+}
+
+// queryContext runs a read-only query on dbOrTx, retrying it under
+// withReadRetry. Use it for reads that must see the latest writes;
+// readQueryContext may serve them from a replica. Only the query is retried:
+// an error while reading the returned rows is left to the caller.
+func (s *MariaDBStore) queryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := s.withReadRetry(ctx, func() error {
+		var err error
+		rows, err = s.dbOrTx().QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// queryRowContext is the single-row form of queryContext. The query runs,
+// and is retried, when the returned row is scanned.
+func (s *MariaDBStore) queryRowContext(ctx context.Context, query string, args ...interface{}) readRow {
+	return readRow{s: s, ctx: ctx, query: query, args: args}
+}
+
+// readRow is a single-row read from queryRowContext.
+type readRow struct {
+	s     *MariaDBStore
+	ctx   context.Context
+	query string
+	args  []interface{}
+}
+
+// Scan runs the query and copies its row into dest. Like sql.Row.Scan, it
+// returns sql.ErrNoRows if the query selected nothing.
+func (r readRow) Scan(dest ...interface{}) error {
+	return r.s.withReadRetry(r.ctx, func() error {
+		return r.s.dbOrTx().QueryRowContext(r.ctx, r.query, r.args...).Scan(dest...)
+	})
+}
+
+// primaryDB returns the querier for the primary pool. Each statement acquires
+// the pool (see acquire), so it fails with ErrStoreClosed rather than using a
+// closed pool, and is waited for by Drain and Reconnect. A result set being
+// read is not tracked; database/sql keeps its connection open until it is
+// closed, even if the pool is closed first.
+func (s *MariaDBStore) primaryDB() querier {
+	return poolQuerier{s}
+}
+
+// poolQuerier runs each statement on the store's current primary pool.
+type poolQuerier struct{ s *MariaDBStore }
+
+func (q poolQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	db, done, err := q.s.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return db.ExecContext(ctx, query, args...)
+}
+
+func (q poolQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	db, done, err := q.s.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return db.QueryContext(ctx, query, args...)
+}
+
+func (q poolQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) row {
+	db, done, err := q.s.acquire()
+	if err != nil {
+		return errRow{err}
+	}
+	defer done()
+	return db.QueryRowContext(ctx, query, args...)
+}
+
+// txQuerier adapts a transaction to querier.
+type txQuerier struct{ *sql.Tx }
+
+func (q txQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) row {
+	return q.Tx.QueryRowContext(ctx, query, args...)
 }
 
 // Config holds MariaDB database configuration
 type Config struct {
-	Host     string // Server host (default: 127.0.0.1)
-	Port     int    // Server port (default: 3306)
+	Host   string // Server host (default: 127.0.0.1)
+	Port   int    // Server port (default: 3306)
+	Socket string // Unix socket path; when set, takes precedence over Host and Port
+
+	// ReplicaHosts lists read replicas as host or host:port (default port: Port).
+	// When set, list and report queries are served by the replicas; see ReadDB.
+	ReplicaHosts []string
+
+	// ReadYourWrites makes replica reads observe the store's own writes: after
+	// each write the primary's GTID position is recorded, and replica reads
+	// wait (via MASTER_GTID_WAIT) until the replica has applied it, falling
+	// back to the primary if it lags by more than a second. Requires GTID
+	// replication; has no effect without ReplicaHosts.
+	ReadYourWrites bool
+
 	User     string // MySQL user (default: root)
 	Password string // MySQL password (default: empty, can be set via BEADS_MARIADB_PASSWORD)
 	Database string // Database name (default: beads)
-	ReadOnly bool   // Open in read-only mode (skip schema init)
+	ReadOnly bool   // Open in read-only mode: skip schema init and reject writes with ErrReadOnly
+
+	// CredentialProvider supplies short-lived credentials, such as cloud IAM
+	// tokens. When set, it is called by New and by every Reconnect, and its
+	// results replace User and Password (an empty user keeps User). Call
+	// Reconnect before the token expires to pick up a fresh one.
+	CredentialProvider func(ctx context.Context) (user, password string, err error)
+
+	// TablePrefix is prepended to every table, view, and index name, so several
+	// Beads instances (or other applications) can share one database. Queries
+	// keep using the unprefixed names; connections rewrite them. Letters,
+	// digits, and underscores only, at most 32 characters.
+	TablePrefix string
+
+	// CreateDatabase controls whether New runs CREATE DATABASE IF NOT EXISTS
+	// (nil = true). Disable it when the user lacks the CREATE privilege.
+	CreateDatabase *bool
+
+	// TLS options (see the TLSMode* constants)
+	TLSMode     string // "", "false", "true", "skip-verify", "preferred", or "custom"
+	TLSCAFile   string // PEM CA bundle used to verify the server (custom mode)
+	TLSCertFile string // PEM client certificate (custom mode, requires TLSKeyFile)
+	TLSKeyFile  string // PEM client key (custom mode, requires TLSCertFile)
+
+	// Params are extra DSN parameters, e.g. {"interpolateParams": "true"} or
+	// {"sql_mode": "'STRICT_ALL_TABLES'"}. Values are URL-escaped. Keys the driver
+	// doesn't recognize are set as session variables on connect.
+	// sql_mode defaults to DefaultSQLMode, so oversized values and invalid
+	// dates are rejected whatever the server default; set it here to override.
+	// Parameters controlled by other fields (tls, timeouts, parseTime, charset,
+	// collation) are rejected.
+	Params map[string]string
+
+	// SessionVars are session variables set on every new connection, so the
+	// store doesn't depend on server defaults, e.g. {"time_zone": "+00:00",
+	// "group_concat_max_len": "1048576"}. Unlike Params, values are plain:
+	// numbers are sent as they are and anything else is quoted as a string.
+	// sql_mode, max_statement_time, and tx_read_only (transaction_read_only
+	// on MySQL) may be set here to override the store's defaults. A variable
+	// may not be set in both SessionVars and Params. An unknown variable or
+	// invalid value makes connecting fail.
+	SessionVars map[string]string
+
+	// ProgramName and ProgramVersion identify the application to the server:
+	// they are sent on connect, with the client's hostname, as the
+	// program_name, program_version, and hostname connection attributes, so
+	// DBAs can tell whose connections they see in
+	// performance_schema.session_connect_attrs (default ProgramName:
+	// DefaultProgramName; an empty ProgramVersion is not sent). Neither may
+	// contain a comma. A connectionAttributes param in Params adds attributes,
+	// and replaces these when it names them too.
+	ProgramName    string
+	ProgramVersion string
+
+	// Charset and Collation are used for the connection, CREATE DATABASE and
+	// the schema's tables (default: utf8mb4 and utf8mb4_unicode_ci). utf8mb4 is
+	// needed to store emoji and other 4-byte characters. Setting only Charset
+	// uses the server's default collation for it.
+	Charset   string
+	Collation string
+
+	// ConnectTimeout bounds dialing the server, so an unreachable or
+	// firewalled server fails fast (default: 10s)
+	ConnectTimeout time.Duration
+
+	// IOTimeout bounds each network read and write on a connection, so a
+	// server that stops answering mid-statement is noticed. It must exceed
+	// the longest statement the store runs, lock waits included: a read that
+	// times out fails with a lost connection, which reads then retry.
+	// Optimize and Analyze never use it. Zero (the default) disables it.
+	IOTimeout time.Duration
+
+	// Retry options for transient connection errors and lock conflicts.
+	// RetryMaxElapsed bounds the total time spent retrying one operation
+	// (default: 30s); the interval between attempts grows from
+	// RetryInitialInterval (default: 500ms) up to RetryMaxInterval (default: 60s).
+	//
+	// The caller's context also bounds retrying: an operation gives up when
+	// its ctx is done, so the effective limit is min(ctx deadline,
+	// RetryMaxElapsed). WithTimeout derives a context with that limit.
+	// RetryStopOnDeadline makes an operation whose ctx has expired fail at
+	// once with the error it got, instead of being classified as transient.
+	//
+	// RetryJitter randomizes each interval by up to that fraction either way,
+	// so clients that failed together don't retry in lockstep (default: 0.5;
+	// at most 1, which spreads a wait over [0, 2x]). A negative value
+	// disables jitter.
+	RetryMaxElapsed      time.Duration
+	RetryInitialInterval time.Duration
+	RetryMaxInterval     time.Duration
+	RetryJitter          float64
+	RetryStopOnDeadline  bool
+
+	// ReadRetryErrors adds server error numbers on which standalone reads are
+	// retried, on top of interrupted queries (ER_QUERY_INTERRUPTED) and lost
+	// connections, which only reads retry since a write may have been applied
+	// (for example, error codes a proxy in front of the server returns during
+	// failover). Writes are unaffected.
+	ReadRetryErrors []uint16
+
+	// QueryTimeout bounds each statement, so a pathological query (say, a huge
+	// unindexed scan) can't run forever. A statement still running after
+	// QueryTimeout is cancelled and fails with context.DeadlineExceeded; its
+	// connection is discarded, and the server's max_statement_time (set to
+	// QueryTimeout plus a second unless Params sets it) stops the query on the
+	// server. On MySQL, max_execution_time is set instead, which only stops
+	// SELECT statements. Unlike IOTimeout it is measured from the statement's start,
+	// and unlike WithTimeout it applies to each statement rather than to a
+	// whole operation with its retries. Reading a result set counts towards
+	// its query's timeout. Schema migrations are bounded too, so leave room
+	// for ALTER TABLE on large tables. Zero (the default) disables it.
+	QueryTimeout time.Duration
+
+	// SlowQueryThreshold logs statements that take longer, so missing indexes
+	// show up in the logs. A slow SELECT, INSERT, UPDATE, DELETE, or REPLACE
+	// is re-run under EXPLAIN on the same connection (which plans it without
+	// executing it) and logged to Logger at WARN level with its duration and
+	// plan. Arguments are never logged. Reading a result set counts towards
+	// its query's duration. Zero (the default) or a nil Logger disables it.
+	SlowQueryThreshold time.Duration
+
+	// IsolationLevel is the isolation level of the transactions the store
+	// begins (WithTx, RunInTransaction, and multi-statement writes). Zero
+	// (sql.LevelDefault) keeps the server's, REPEATABLE READ unless changed.
+	//   - sql.LevelReadCommitted takes fewer gap locks, so concurrent writers
+	//     deadlock less, but each statement sees newly committed rows.
+	//   - sql.LevelRepeatableRead reads from one snapshot per transaction.
+	//   - sql.LevelSerializable makes plain SELECTs take shared locks: the
+	//     strongest guarantees, with the most lock waits and deadlocks.
+	// sql.LevelReadUncommitted is accepted too; other levels are rejected.
+	IsolationLevel sql.IsolationLevel
+
+	// Circuit breaker for server outages, so retrying callers don't overload a
+	// recovering server. After BreakerThreshold consecutive connection failures
+	// within BreakerWindow (default: 10s), retried operations fail fast with
+	// ErrCircuitOpen for BreakerCooldown (default: 5s). Then a single probe is
+	// let through, and the breaker closes if it succeeds. Zero BreakerThreshold
+	// (the default) disables the breaker.
+	BreakerThreshold int
+	BreakerWindow    time.Duration
+	BreakerCooldown  time.Duration
+
+	// WatchInterval is how often Watch polls for changed issues
+	// (default: DefaultWatchInterval).
+	WatchInterval time.Duration
+
+	// IDGenerator names issues created without an ID: HashIDs (the default),
+	// SequentialIDs, ULIDs, or a custom IDGenerator. Generated IDs always
+	// start with the issue prefix. Changing generators keeps existing IDs.
+	IDGenerator IDGenerator
+
+	// Logger receives retry attempts, give-ups, reconnects, and slow queries
+	// (default: discard). Entries carry the database name, never the DSN or
+	// password.
+	Logger *slog.Logger
+
+	// Connection pool options
+	MaxOpenConns    int           // Maximum open connections (default: 10)
+	MaxIdleConns    int           // Maximum idle connections (default: 5, capped at MaxOpenConns)
+	ConnMaxLifetime time.Duration // Maximum connection lifetime (default: 5m)
+
+	// TrackConns is a debugging aid for connection leaks: each connection
+	// UnderlyingConn hands out records the caller's stack, and if it has not
+	// been closed after ConnLeakThreshold (default:
+	// DefaultConnLeakThreshold), a warning with that stack is logged to
+	// Logger. Without it (the default), UnderlyingConn does no tracking.
+	TrackConns        bool
+	ConnLeakThreshold time.Duration
+
+	// WarmupConns is the number of connections New opens and pings up front
+	// (see Warmup), so burst traffic right after startup doesn't wait on
+	// dialing (default: 0, connections open lazily)
+	WarmupConns int
 }
 
 // DefaultPort is the default MariaDB port
 const DefaultPort = 3306
 
+// Defaults for Config.Charset and Config.Collation
+const (
+	DefaultCharset   = "utf8mb4"
+	DefaultCollation = "utf8mb4_unicode_ci"
+)
+
+// DefaultSQLMode is the session sql_mode set on every connection unless
+// Config.Params overrides it.
+const DefaultSQLMode = "STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION"
+
+// DefaultConnectTimeout is the default for Config.ConnectTimeout
+const DefaultConnectTimeout = 10 * time.Second
+
+// Default connection pool settings.
+// Server mode supports multi-writer, so these are sized for a typical daemon.
+const (
+	DefaultMaxOpenConns    = 10
+	DefaultMaxIdleConns    = 5
+	DefaultConnMaxLifetime = 5 * time.Minute
+)
+
 // Server retry configuration.
 // go-sql-driver/mysql doesn't have built-in retry. We add retry for transient
 // connection errors (stale pool connections, brief network issues, server restarts).
-const serverRetryMaxElapsed = 30 * time.Second
+// Config.RetryMaxElapsed, RetryInitialInterval, RetryMaxInterval and
+// RetryJitter override these.
+const (
+	DefaultRetryMaxElapsed      = 30 * time.Second
+	DefaultRetryInitialInterval = backoff.DefaultInitialInterval
+	DefaultRetryMaxInterval     = backoff.DefaultMaxInterval
+	DefaultRetryJitter          = backoff.DefaultRandomizationFactor
+)
+
+// retrySettings shape the exponential backoff of withRetry.
+// Zero values select the Default* constants.
+type retrySettings struct {
+	maxElapsed      time.Duration
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	jitter          float64 // Negative disables jitter
+	stopOnDeadline  bool
+	readErrors      map[uint16]bool // Config.ReadRetryErrors
+}
+
+func retrySettingsFromConfig(cfg *Config) retrySettings {
+	r := retrySettings{
+		maxElapsed:      cfg.RetryMaxElapsed,
+		initialInterval: cfg.RetryInitialInterval,
+		maxInterval:     cfg.RetryMaxInterval,
+		jitter:          cfg.RetryJitter,
+		stopOnDeadline:  cfg.RetryStopOnDeadline,
+	}
+	if len(cfg.ReadRetryErrors) > 0 {
+		r.readErrors = make(map[uint16]bool, len(cfg.ReadRetryErrors))
+		for _, n := range cfg.ReadRetryErrors {
+			r.readErrors[n] = true
+		}
+	}
+	return r
+}
+
+// maxElapsedTime returns the retry window for one operation.
+func (r retrySettings) maxElapsedTime() time.Duration {
+	if r.maxElapsed > 0 {
+		return r.maxElapsed
+	}
+	return DefaultRetryMaxElapsed
+}
 
-func newServerRetryBackoff() backoff.BackOff {
+func (r retrySettings) newBackoff() backoff.BackOff {
 	bo := backoff.NewExponentialBackOff()
-	bo.MaxElapsedTime = serverRetryMaxElapsed
+	bo.MaxElapsedTime = r.maxElapsedTime()
+	if r.initialInterval > 0 {
+		bo.InitialInterval = r.initialInterval
+	}
+	if r.maxInterval > 0 {
+		bo.MaxInterval = r.maxInterval
+	}
+	bo.RandomizationFactor = DefaultRetryJitter
+	if r.jitter > 0 {
+		bo.RandomizationFactor = r.jitter
+	} else if r.jitter < 0 {
+		bo.RandomizationFactor = 0
+	}
+	bo.Reset()
 	return bo
 }
 
+// MariaDB server error numbers for lock conflicts. InnoDB rolls back the whole
+// transaction on a deadlock and the failed statement on a lock wait timeout,
+// so the work can be replayed after a short backoff.
+const (
+	errLockWaitTimeout = 1205 // ER_LOCK_WAIT_TIMEOUT
+	errLockDeadlock    = 1213 // ER_LOCK_DEADLOCK
+)
+
+// errBadDatabase is the MariaDB error number for an unknown database (ER_BAD_DB_ERROR).
+const errBadDatabase = 1049
+
+// MariaDB server error numbers for a connection the server is tearing down.
+// The statement did not run, and the pool will dial a fresh connection.
+const (
+	errServerShutdown   = 1053 // ER_SERVER_SHUTDOWN
+	errConnectionKilled = 1927 // ER_CONNECTION_KILLED
+)
+
+// MariaDB error numbers for a query that was cut off. Whether a write cut off
+// this way was applied is unknown, so only reads retry them (see
+// isReadRetryableError). 2006 and 2013 are client error codes, which proxies
+// may relay as server errors.
+const (
+	errQueryInterrupted = 1317 // ER_QUERY_INTERRUPTED
+	errServerGone       = 2006 // CR_SERVER_GONE_ERROR
+	errServerLost       = 2013 // CR_SERVER_LOST
+)
+
+// readRetryableServerErrors are the server error numbers isReadRetryableError
+// accepts beyond those of isRetryableError.
+var readRetryableServerErrors = map[uint16]bool{
+	errQueryInterrupted: true,
+	errServerGone:       true,
+	errServerLost:       true,
+}
+
+// retryableServerErrors are the server error numbers isRetryableError accepts.
+var retryableServerErrors = map[uint16]bool{
+	errLockWaitTimeout:  true,
+	errLockDeadlock:     true,
+	errServerShutdown:   true,
+	errConnectionKilled: true,
+}
+
 // isRetryableError returns true if the error is a transient connection error
-// that should be retried in server mode.
+// or lock conflict that should be retried in server mode.
+//
+// Server errors are classified by number; any other server error is permanent.
+// Client-side errors are matched against the driver's sentinel errors and
+// syscall errors, with message matching as a last resort for untyped errors.
 func isRetryableError(err error) bool {
 	if err == nil {
 		return false
 	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return retryableServerErrors[mysqlErr.Number]
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) {
+		return true
+	}
+	// Network transient errors (brief blips, not persistent failures).
+	// Don't retry ECONNREFUSED - that means server is down.
+	if errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	return isRetryableErrorMessage(err)
+}
+
+// isRetryableErrorMessage matches transient connection errors by message.
+// It covers errors that lost their type along the way (e.g. formatted with %v).
+func isRetryableErrorMessage(err error) bool {
 	errStr := strings.ToLower(err.Error())
 	// MySQL driver transient errors
 	if strings.Contains(errStr, "driver: bad connection") {
@@ -86,19 +626,207 @@ func isRetryableError(err error) bool {
 	return false
 }
 
-// withRetry executes an operation with retry for transient errors.
+// isLockConflictError returns true if err is a deadlock or lock wait timeout.
+func isLockConflictError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == errLockDeadlock || mysqlErr.Number == errLockWaitTimeout
+}
+
+// shouldRetry reports whether a failed operation should be retried.
+// Lock conflicts are only retried for replayable operations: after a deadlock
+// the server has rolled back the enclosing transaction, so re-running a single
+// statement of a caller-managed transaction would silently drop the others.
+func shouldRetry(err error, replayable bool) bool {
+	if !isRetryableError(err) {
+		return false
+	}
+	return replayable || !isLockConflictError(err)
+}
+
+// isReadRetryableError reports whether a failed read-only operation should be
+// retried. Running a SELECT again has no effect beyond its result, so on top
+// of what shouldRetry accepts for a replayable operation it retries queries
+// interrupted by the server, connections lost mid-query (which the driver
+// reports as an unexpected EOF), and the server error numbers in extra.
+func isReadRetryableError(err error, extra map[uint16]bool) bool {
+	if err == nil {
+		return false
+	}
+	if shouldRetry(err, true) {
+		return true
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return readRetryableServerErrors[mysqlErr.Number] || extra[mysqlErr.Number]
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNABORTED) {
+		return true
+	}
+	errStr := strings.ToLower(err.Error())
+	return strings.Contains(errStr, "server has gone away") || strings.Contains(errStr, "lost connection")
+}
+
+// retryPolicy selects the errors retry retries.
+type retryPolicy int
+
+const (
+	retryStatement  retryPolicy = iota // Transient connection errors (withRetry)
+	retryReplayable                    // Also lock conflicts (withReplayableRetry)
+	retryRead                          // Also cut-off queries (withReadRetry)
+)
+
+// withRetry executes an operation with retry for transient connection errors.
+// Lock conflicts are not retried; use withReplayableRetry or withRetryTx for that.
 func (s *MariaDBStore) withRetry(ctx context.Context, op func() error) error {
-	bo := newServerRetryBackoff()
-	return backoff.Retry(func() error {
+	return s.retry(ctx, retryStatement, op)
+}
+
+// withReplayableRetry is like withRetry but also retries deadlocks and lock
+// wait timeouts. op must be safe to run again from the start: an idempotent
+// autocommit statement or a complete transaction (see withRetryTx).
+func (s *MariaDBStore) withReplayableRetry(ctx context.Context, op func() error) error {
+	return s.retry(ctx, retryReplayable, op)
+}
+
+// withReadRetry is like withReplayableRetry, with the broader policy of
+// isReadRetryableError. op must only read: a write cut off mid-statement may
+// have been applied, and would be applied again. Inside WithTx it runs once,
+// like withRetry.
+func (s *MariaDBStore) withReadRetry(ctx context.Context, op func() error) error {
+	return s.retry(ctx, retryRead, op)
+}
+
+// withRetryTx runs fn in its own transaction, replaying the whole transaction
+// on transient errors. fn may run more than once, so it must only write
+// through tx. A failed commit is not replayed (see commitError). On a WithTx
+// view, fn joins the enclosing transaction instead.
+func (s *MariaDBStore) withRetryTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	if s.tx != nil {
+		return fn(s.tx)
+	}
+	return s.withReplayableRetry(ctx, func() error {
+		db, done, err := s.acquire()
+		if err != nil {
+			return err
+		}
+		defer done()
+		tx, err := db.BeginTx(ctx, s.txOptions())
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return &commitError{err: err}
+		}
+		return nil
+	})
+}
+
+// commitError is a failed commit in withRetryTx. It is never retried: a
+// connection lost during the commit may have lost only the reply, with the
+// transaction applied, and replaying it would write its changes twice.
+type commitError struct {
+	err error
+}
+
+func (e *commitError) Error() string { return "failed to commit transaction: " + e.err.Error() }
+func (e *commitError) Unwrap() error { return e.err }
+
+func (s *MariaDBStore) retry(ctx context.Context, policy retryPolicy, op func() error) error {
+	if s.tx != nil {
+		// Statements can't be retried inside a transaction: a failure may
+		// have ended it. WithTx replays the whole transaction instead.
+		return op()
+	}
+	done, err := s.track()
+	if err != nil {
+		return err
+	}
+	defer done()
+	bo := s.retryCfg.newBackoff()
+	start := time.Now()
+	attempt := 0
+	retrying := false
+	err = backoff.RetryNotify(func() error {
+		attempt++
+		if err := s.breaker.allow(); err != nil {
+			retrying = false
+			return backoff.Permanent(err)
+		}
 		err := op()
-		if err != nil && isRetryableError(err) {
+		if s.breaker.record(err) {
+			s.log().Error("MariaDB circuit breaker opened after repeated connection failures",
+				"database", s.dbName, "cooldown", s.breaker.cooldown, "error", err)
+		}
+		if err != nil && s.retryCfg.stopOnDeadline && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			// The failure is most likely the deadline aborting the query
+			retrying = false
+			return backoff.Permanent(fmt.Errorf("%w: %w", ctx.Err(), err))
+		}
+		var commitErr *commitError
+		switch {
+		case err == nil, errors.As(err, &commitErr):
+			retrying = false
+		case policy == retryRead:
+			retrying = isReadRetryableError(err, s.retryCfg.readErrors)
+		default:
+			retrying = shouldRetry(err, policy == retryReplayable)
+		}
+		if retrying {
 			return err // Retryable - backoff will retry
 		}
 		if err != nil {
 			return backoff.Permanent(err) // Non-retryable - stop immediately
 		}
 		return nil
-	}, backoff.WithContext(bo, ctx))
+	}, backoff.WithContext(bo, ctx), func(err error, wait time.Duration) {
+		s.retries.Add(1)
+		s.log().Warn("retrying MariaDB operation after transient error",
+			"database", s.dbName, "attempt", attempt, "elapsed", time.Since(start), "wait", wait, "error", err)
+	})
+	if err != nil && retrying {
+		// Stopped by RetryMaxElapsed or ctx while the error was still transient
+		s.log().Error("giving up on MariaDB operation after transient errors",
+			"database", s.dbName, "attempts", attempt, "elapsed", time.Since(start), "error", err)
+	}
+	if err == nil && policy == retryReplayable {
+		// Writes go through withReplayableRetry or withRetryTx
+		s.recordWrite(ctx)
+	}
+	return err
+}
+
+// WithTimeout returns a child of ctx for one store operation, with a deadline
+// at the end of the retry window (Config.RetryMaxElapsed) unless ctx's own
+// deadline is earlier. The operation's queries and its retries then share
+// one limit, min(ctx deadline, RetryMaxElapsed), rather than a slow query
+// running on after retrying has given up. The caller must call cancel.
+func (s *MariaDBStore) WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, s.retryCfg.maxElapsedTime())
+}
+
+// Retries returns the number of times an operation has been retried after a
+// transient error since the store was opened.
+func (s *MariaDBStore) Retries() uint64 {
+	return s.retries.Load()
+}
+
+// discardLogger is used when Config.Logger is nil.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// log returns the store's logger, or a no-op logger if none was configured.
+func (s *MariaDBStore) log() *slog.Logger {
+	if s.logger != nil {
+		return s.logger
+	}
+	return discardLogger
 }
 
 // New creates a new MariaDB storage backend
@@ -107,6 +835,9 @@ func New(ctx context.Context, cfg *Config) (*MariaDBStore, error) {
 	if cfg.Database == "" {
 		cfg.Database = "beads"
 	}
+	if err := validateDatabaseName(cfg.Database); err != nil {
+		return nil, err
+	}
 	if cfg.Host == "" {
 		cfg.Host = "127.0.0.1"
 	}
@@ -117,127 +848,355 @@ func New(ctx context.Context, cfg *Config) (*MariaDBStore, error) {
 		cfg.User = "root"
 	}
 	// Check environment variable for password (more secure than command-line)
-	if cfg.Password == "" {
+	if cfg.Password == "" && cfg.CredentialProvider == nil {
 		cfg.Password = os.Getenv("BEADS_MARIADB_PASSWORD")
 	}
-
-	// Connect to MariaDB server via MySQL protocol
-	db, connStr, err := openServerConnection(ctx, cfg)
-	if err != nil {
+	if cfg.CredentialProvider != nil {
+		user, password, err := cfg.CredentialProvider(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get MariaDB credentials: %w", err)
+		}
+		if user != "" {
+			cfg.User = user
+		}
+		cfg.Password = password
+	}
+	if cfg.ConnectTimeout < 0 {
+		return nil, fmt.Errorf("invalid MariaDB config: ConnectTimeout must not be negative")
+	}
+	if cfg.ConnectTimeout == 0 {
+		cfg.ConnectTimeout = DefaultConnectTimeout
+	}
+	if cfg.IOTimeout < 0 {
+		return nil, fmt.Errorf("invalid MariaDB config: IOTimeout must not be negative")
+	}
+	if err := applyPoolDefaults(cfg); err != nil {
+		return nil, err
+	}
+	if err := validateIsolationLevel(cfg.IsolationLevel); err != nil {
+		return nil, err
+	}
+	if err := applyCharsetDefaults(cfg); err != nil {
+		return nil, err
+	}
+	if err := validateTablePrefix(cfg.TablePrefix); err != nil {
 		return nil, err
 	}
+	if cfg.RetryMaxElapsed < 0 || cfg.RetryInitialInterval < 0 || cfg.RetryMaxInterval < 0 {
+		return nil, fmt.Errorf("invalid MariaDB retry config: values must not be negative")
+	}
+	if cfg.RetryJitter > 1 {
+		return nil, fmt.Errorf("invalid MariaDB retry config: RetryJitter %v is above 1", cfg.RetryJitter)
+	}
+	if err := validateBreakerConfig(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.QueryTimeout < 0 {
+		return nil, fmt.Errorf("invalid MariaDB config: QueryTimeout must not be negative")
+	}
+	if cfg.SlowQueryThreshold < 0 {
+		return nil, fmt.Errorf("invalid MariaDB config: SlowQueryThreshold must not be negative")
+	}
+	if cfg.WatchInterval < 0 {
+		return nil, fmt.Errorf("invalid MariaDB config: WatchInterval must not be negative")
+	}
+	if cfg.WatchInterval == 0 {
+		cfg.WatchInterval = DefaultWatchInterval
+	}
 
 	// Test connection
 	pingCtx := ctx
 	if pingCtx == nil {
 		pingCtx = context.Background()
 	}
+
+	// Connect to MariaDB server via MySQL protocol
+	db, connStr, mariadb, err := openServerConnection(pingCtx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := db.PingContext(pingCtx); err != nil {
 		_ = db.Close()
+		var mysqlErr *mysql.MySQLError
+		if cfg.CreateDatabase != nil && !*cfg.CreateDatabase && errors.As(err, &mysqlErr) && mysqlErr.Number == errBadDatabase {
+			return nil, fmt.Errorf("MariaDB database %s does not exist and CreateDatabase is disabled; create it or enable CreateDatabase: %w", cfg.Database, err)
+		}
 		return nil, fmt.Errorf("failed to ping MariaDB database: %w", err)
 	}
 
 	store := &MariaDBStore{
-		db:       db,
-		dbName:   cfg.Database,
-		connStr:  connStr,
-		readOnly: cfg.ReadOnly,
+		storeState: newStoreState(db, connStr),
+		dbName:     cfg.Database,
+		readOnly:   cfg.ReadOnly,
+
+		pool:      poolSettingsFromConfig(cfg),
+		retryCfg:  retrySettingsFromConfig(cfg),
+		logger:    cfg.Logger,
+		breaker:   newCircuitBreaker(cfg),
+		conns:     newConnTracker(cfg),
+		isolation: cfg.IsolationLevel,
+
+		watchInterval: cfg.WatchInterval,
+		idGen:         cfg.IDGenerator,
+
+		credentialProvider: cfg.CredentialProvider,
+
+		charset:   cfg.Charset,
+		collation: cfg.Collation,
+
+		tablePrefix: cfg.TablePrefix,
+
+		conn: connOptionsFromConfig(cfg),
 	}
 
+	if len(cfg.ReplicaHosts) > 0 {
+		replica, err := openReplicaPool(cfg, mariadb)
+		if err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+		store.replica = replica
+		if cfg.ReadYourWrites {
+			store.gtid = &gtidTracker{}
+		}
+	}
+	store.probeCapabilities(pingCtx)
+	store.probeMaxPacket(pingCtx)
+
 	// Initialize schema (idempotent)
 	if !cfg.ReadOnly {
 		if err := store.initSchema(ctx); err != nil {
+			_ = store.Close()
 			return nil, fmt.Errorf("failed to initialize schema: %w", err)
 		}
 	}
 
+	if cfg.WarmupConns > 0 {
+		if err := store.Warmup(ctx, cfg.WarmupConns); err != nil {
+			_ = store.Close()
+			return nil, err
+		}
+	}
+
 	return store, nil
 }
 
-// openServerConnection opens a connection to a MariaDB server via MySQL protocol
-func openServerConnection(ctx context.Context, cfg *Config) (*sql.DB, string, error) {
-	// DSN format: user:password@tcp(host:port)/database?parseTime=true
-	// parseTime=true tells the MySQL driver to parse DATETIME/TIMESTAMP to time.Time
-	var connStr string
-	if cfg.Password != "" {
-		connStr = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true",
-			cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Database)
+// applyCharsetDefaults fills in the default charset and collation and
+// validates them, since they are interpolated into DDL.
+func applyCharsetDefaults(cfg *Config) error {
+	if cfg.Charset == "" {
+		cfg.Charset = DefaultCharset
+		if cfg.Collation == "" {
+			cfg.Collation = DefaultCollation
+		}
+	}
+	if !databaseNamePattern.MatchString(cfg.Charset) {
+		return fmt.Errorf("invalid MariaDB charset %q: only letters, digits, and underscores are allowed", cfg.Charset)
+	}
+	if cfg.Collation != "" && !databaseNamePattern.MatchString(cfg.Collation) {
+		return fmt.Errorf("invalid MariaDB collation %q: only letters, digits, and underscores are allowed", cfg.Collation)
+	}
+	return nil
+}
+
+// applyPoolDefaults fills in zero-valued pool settings and validates the result.
+func applyPoolDefaults(cfg *Config) error {
+	if cfg.MaxOpenConns < 0 || cfg.MaxIdleConns < 0 || cfg.ConnMaxLifetime < 0 || cfg.WarmupConns < 0 {
+		return fmt.Errorf("invalid MariaDB pool config: values must not be negative")
+	}
+	if cfg.MaxOpenConns == 0 {
+		cfg.MaxOpenConns = DefaultMaxOpenConns
+	}
+	if cfg.MaxIdleConns == 0 {
+		cfg.MaxIdleConns = min(DefaultMaxIdleConns, cfg.MaxOpenConns)
+	}
+	if cfg.ConnMaxLifetime == 0 {
+		cfg.ConnMaxLifetime = DefaultConnMaxLifetime
+	}
+	if cfg.MaxIdleConns > cfg.MaxOpenConns {
+		return fmt.Errorf("invalid MariaDB pool config: MaxIdleConns (%d) must not exceed MaxOpenConns (%d)",
+			cfg.MaxIdleConns, cfg.MaxOpenConns)
+	}
+	return nil
+}
+
+// openServerConnection opens a connection to a MariaDB server via MySQL
+// protocol. The server is checked first, which tells whether it is MariaDB,
+// and the database created if cfg asks for it.
+func openServerConnection(ctx context.Context, cfg *Config) (db *sql.DB, connStr string, mariadb bool, err error) {
+	// Ensure database exists (may need to create it)
+	if cfg.CreateDatabase == nil || *cfg.CreateDatabase {
+		mariadb, err = createDatabase(ctx, cfg)
 	} else {
-		connStr = fmt.Sprintf("%s@tcp(%s:%d)/%s?parseTime=true",
-			cfg.User, cfg.Host, cfg.Port, cfg.Database)
+		mariadb, err = probeServer(ctx, cfg)
+	}
+	if err != nil {
+		return nil, "", false, err
 	}
 
-	db, err := sql.Open("mysql", connStr)
+	connStr, err = buildPoolDSN(cfg, cfg.Database, mariadb)
+	if err != nil {
+		return nil, "", false, err
+	}
+	db, err = openDB(connStr, connOptionsFromConfig(cfg))
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to open MariaDB server connection: %w", err)
+		return nil, "", false, fmt.Errorf("failed to open MariaDB server connection (%s): %w", redactDSN(connStr), err)
 	}
 
-	// Server mode supports multi-writer, configure reasonable pool size
-	db.SetMaxOpenConns(10)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	poolSettingsFromConfig(cfg).apply(db)
 
-	// Ensure database exists (may need to create it)
-	// First connect without database to create it
-	var initConnStr string
-	if cfg.Password != "" {
-		initConnStr = fmt.Sprintf("%s:%s@tcp(%s:%d)/?parseTime=true",
-			cfg.User, cfg.Password, cfg.Host, cfg.Port)
-	} else {
-		initConnStr = fmt.Sprintf("%s@tcp(%s:%d)/?parseTime=true",
-			cfg.User, cfg.Host, cfg.Port)
+	return db, connStr, mariadb, nil
+}
+
+// probeServer runs checkServerCompatibility over a connection to
+// cfg.Database, without the session variables buildPoolDSN adds, for stores
+// that don't create their database.
+func probeServer(ctx context.Context, cfg *Config) (mariadb bool, err error) {
+	probeConnStr, err := buildDSN(cfg, cfg.Database)
+	if err != nil {
+		return false, err
 	}
-	initDB, err := sql.Open("mysql", initConnStr)
+	probeDB, err := sqlOpen("mysql", probeConnStr)
 	if err != nil {
-		_ = db.Close()
-		return nil, "", fmt.Errorf("failed to open init connection: %w", err)
+		return false, fmt.Errorf("failed to open MariaDB server connection (%s): %w", redactDSN(probeConnStr), err)
+	}
+	defer func() { _ = probeDB.Close() }()
+
+	mariadb, err = checkServerCompatibility(ctx, probeDB, cfg.Charset)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errBadDatabase {
+		return false, fmt.Errorf("MariaDB database %s does not exist and CreateDatabase is disabled; create it or enable CreateDatabase: %w", cfg.Database, err)
+	}
+	if err != nil {
+		if connErr := serverNotRunningError(cfg, err); connErr != nil {
+			return false, connErr
+		}
+		return false, err
+	}
+	return mariadb, nil
+}
+
+// poolSettings are the connection pool limits from Config.
+type poolSettings struct {
+	maxOpenConns    int
+	maxIdleConns    int
+	connMaxLifetime time.Duration
+}
+
+func poolSettingsFromConfig(cfg *Config) poolSettings {
+	return poolSettings{
+		maxOpenConns:    cfg.MaxOpenConns,
+		maxIdleConns:    cfg.MaxIdleConns,
+		connMaxLifetime: cfg.ConnMaxLifetime,
+	}
+}
+
+func (p poolSettings) apply(db *sql.DB) {
+	db.SetMaxOpenConns(p.maxOpenConns)
+	db.SetMaxIdleConns(p.maxIdleConns)
+	db.SetConnMaxLifetime(p.connMaxLifetime)
+}
+
+// sqlOpen opens database handles; tests replace it to observe connections.
+var sqlOpen = sql.Open
+
+// createDatabase runs CREATE DATABASE IF NOT EXISTS for cfg.Database over a
+// separate connection that does not select a database, after checking the
+// server over it. It reports whether the server is MariaDB.
+func createDatabase(ctx context.Context, cfg *Config) (mariadb bool, err error) {
+	// The init connection is not a read-only session (see buildPoolDSN), since
+	// it creates the database
+	initConnStr, err := buildDSN(cfg, "")
+	if err != nil {
+		return false, err
+	}
+	initDB, err := sqlOpen("mysql", initConnStr)
+	if err != nil {
+		return false, fmt.Errorf("failed to open init connection: %w", err)
 	}
 	defer func() { _ = initDB.Close() }()
 
-	_, err = initDB.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", cfg.Database))
+	mariadb, err = checkServerCompatibility(ctx, initDB, cfg.Charset)
+	if err != nil {
+		if connErr := serverNotRunningError(cfg, err); connErr != nil {
+			return false, connErr
+		}
+		return false, err
+	}
+
+	_, err = initDB.ExecContext(ctx, "CREATE DATABASE IF NOT EXISTS "+quoteIdentifier(cfg.Database)+tableOptions(cfg.Charset, cfg.Collation))
 	if err != nil {
 		// MariaDB may return error 1007 even with IF NOT EXISTS - ignore if database already exists
 		errLower := strings.ToLower(err.Error())
 		if !strings.Contains(errLower, "database exists") && !strings.Contains(errLower, "1007") {
-			_ = db.Close()
-			// Check for connection refused - server likely not running
-			if strings.Contains(errLower, "connection refused") || strings.Contains(errLower, "connect: connection refused") {
-				return nil, "", fmt.Errorf("failed to connect to MariaDB server at %s:%d: %w\n\nThe MariaDB server may not be running. Try:\n  sudo systemctl start mariadb    # On systemd systems\n  brew services start mariadb     # On macOS with Homebrew",
-					cfg.Host, cfg.Port, err)
+			if connErr := serverNotRunningError(cfg, err); connErr != nil {
+				return false, connErr
 			}
-			return nil, "", fmt.Errorf("failed to create database: %w", err)
+			return false, fmt.Errorf("failed to create database: %w", err)
 		}
 		// Database already exists - that's fine, continue
 	}
+	return mariadb, nil
+}
 
-	return db, connStr, nil
+// serverNotRunningError returns a hint to start the server if err shows the
+// connection was refused, or nil otherwise.
+func serverNotRunningError(cfg *Config, err error) error {
+	errLower := strings.ToLower(err.Error())
+	// Check for connection refused - server likely not running
+	// (a missing socket file means the same thing)
+	if strings.Contains(errLower, "connection refused") || (cfg.Socket != "" && strings.Contains(errLower, "no such file")) {
+		_, addr := serverAddress(cfg)
+		return fmt.Errorf("failed to connect to MariaDB server at %s: %w\n\nThe MariaDB server may not be running. Try:\n  sudo systemctl start mariadb    # On systemd systems\n  brew services start mariadb     # On macOS with Homebrew",
+			addr, err)
+	}
+	return nil
+}
+
+// tableOptions returns the CHARACTER SET and COLLATE clause for charset and
+// collation, or "" if both are empty.
+func tableOptions(charset, collation string) string {
+	var opts string
+	if charset != "" {
+		opts += " CHARACTER SET " + charset
+	}
+	if collation != "" {
+		opts += " COLLATE " + collation
+	}
+	return opts
 }
 
-// initSchema creates all tables if they don't exist
-func initSchemaOnDB(ctx context.Context, db *sql.DB) error {
+// initSchema creates all tables if they don't exist.
+// Tables are created with the given charset and collation.
+func initSchemaOnDB(ctx context.Context, db *sql.DB, charset, collation string) error {
+	opts := tableOptions(charset, collation)
 	// Execute schema creation - split into individual statements
 	// because MySQL/MariaDB doesn't support multiple statements in one Exec
-	for _, stmt := range splitStatements(schema) {
+	for _, stmt := range sqlutil.SplitStatements(schema) {
 		stmt = strings.TrimSpace(stmt)
 		if stmt == "" {
 			continue
 		}
 		// Skip pure comment-only statements, but execute statements that start with comments
-		if isOnlyComments(stmt) {
+		if sqlutil.IsOnlyComments(stmt) {
 			continue
 		}
+		if strings.Contains(stmt, "CREATE TABLE") {
+			stmt += opts
+		}
 		if _, err := db.ExecContext(ctx, stmt); err != nil {
-			return fmt.Errorf("failed to create schema: %w\nStatement: %s", err, truncateForError(stmt))
+			return fmt.Errorf("failed to create schema: %w\nStatement: %s", err, sqlutil.TruncateForError(stmt))
 		}
 	}
 
 	// Insert default config values
-	for _, stmt := range splitStatements(defaultConfig) {
+	for _, stmt := range sqlutil.SplitStatements(defaultConfig) {
 		stmt = strings.TrimSpace(stmt)
 		if stmt == "" {
 			continue
 		}
-		if isOnlyComments(stmt) {
+		if sqlutil.IsOnlyComments(stmt) {
 			continue
 		}
 		if _, err := db.ExecContext(ctx, stmt); err != nil {
@@ -245,19 +1204,6 @@ func initSchemaOnDB(ctx context.Context, db *sql.DB) error {
 		}
 	}
 
-	// Apply index migrations for existing databases.
-	// CREATE TABLE IF NOT EXISTS won't add new indexes to existing tables.
-	indexMigrations := []string{
-		"CREATE INDEX idx_issues_issue_type ON issues(issue_type)",
-	}
-	for _, migration := range indexMigrations {
-		_, err := db.ExecContext(ctx, migration)
-		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "duplicate") &&
-			!strings.Contains(strings.ToLower(err.Error()), "already exists") {
-			return fmt.Errorf("failed to apply index migration: %w", err)
-		}
-	}
-
 	// Remove FK constraint on depends_on_id to allow external references.
 	// See SQLite migration 025_remove_depends_on_fk.go for design context.
 	// This is idempotent - DROP FOREIGN KEY fails silently if constraint doesn't exist.
@@ -278,7 +1224,7 @@ func initSchemaOnDB(ctx context.Context, db *sql.DB) error {
 	}
 
 	// Run schema migrations for existing databases
-	if err := RunMigrations(db); err != nil {
+	if err := RunMigrations(ctx, db); err != nil {
 		return fmt.Errorf("failed to run mariadb migrations: %w", err)
 	}
 
@@ -286,92 +1232,119 @@ func initSchemaOnDB(ctx context.Context, db *sql.DB) error {
 }
 
 func (s *MariaDBStore) initSchema(ctx context.Context) error {
-	return initSchemaOnDB(ctx, s.db)
+	db, done, err := s.acquire()
+	if err != nil {
+		return err
+	}
+	defer done()
+	return initSchemaOnDB(ctx, db, s.charset, s.collation)
 }
 
-// splitStatements splits a SQL script into individual statements
-func splitStatements(script string) []string {
-	var statements []string
-	var current strings.Builder
-	inString := false
-	stringChar := byte(0)
-
-	for i := 0; i < len(script); i++ {
-		c := script[i]
-
-		if inString {
-			current.WriteByte(c)
-			if c == stringChar && (i == 0 || script[i-1] != '\\') {
-				inString = false
-			}
-			continue
-		}
-
-		if c == '\'' || c == '"' || c == '`' {
-			inString = true
-			stringChar = c
-			current.WriteByte(c)
+// Close closes the database connection
+func (s *MariaDBStore) Close() error {
+	if s.tx != nil {
+		return fmt.Errorf("cannot close a WithTx transaction view; return from fn instead")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed.Store(true)
+	var primary *sql.DB
+	if s.primary != nil {
+		primary = s.primary.db
+	}
+	var err error
+	for _, db := range []*sql.DB{primary, s.replica} {
+		if db == nil {
 			continue
 		}
-
-		if c == ';' {
-			stmt := strings.TrimSpace(current.String())
-			if stmt != "" {
-				statements = append(statements, stmt)
+		if cerr := db.Close(); cerr != nil {
+			if !errors.Is(cerr, context.Canceled) {
+				err = errors.Join(err, cerr)
 			}
-			current.Reset()
-			continue
 		}
-
-		current.WriteByte(c)
 	}
+	s.primary = nil
+	s.replica = nil
+	return err
+}
 
-	// Handle last statement without semicolon
-	stmt := strings.TrimSpace(current.String())
-	if stmt != "" {
-		statements = append(statements, stmt)
+// Drain closes the store gracefully: new operations fail with
+// ErrStoreClosed at once, while operations already running, and the
+// statements they still issue, are given until ctx expires to finish before
+// the pool is closed. The pool is closed either
+// way; if ctx expired first, its error is returned along with any error
+// from closing.
+func (s *MariaDBStore) Drain(ctx context.Context) error {
+	if s.tx != nil {
+		return fmt.Errorf("cannot drain a WithTx transaction view; return from fn instead")
 	}
+	s.mu.Lock()
+	s.closed.Store(true)
+	s.mu.Unlock()
 
-	return statements
+	var err error
+	select {
+	case <-s.inflight.idle():
+	case <-ctx.Done():
+		err = fmt.Errorf("failed to drain in-flight operations: %w", ctx.Err())
+		s.log().Warn("closing MariaDB store with operations still in flight", "database", s.dbName, "error", ctx.Err())
+	}
+	return errors.Join(err, s.Close())
 }
 
-// truncateForError truncates a string for use in error messages
-func truncateForError(s string) string {
-	if len(s) > 100 {
-		return s[:100] + "..."
+// track registers an in-flight operation for Drain, returning ErrStoreClosed
+// once the store is closing. The caller must call done when it finishes.
+// Statements are tracked on their own by acquire; track covers an operation
+// from its start, including retry waits between its statements.
+func (s *MariaDBStore) track() (done func(), err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed.Load() {
+		return nil, ErrStoreClosed
 	}
-	return s
+	return s.inflight.add(), nil
 }
 
-// isOnlyComments returns true if the statement contains only SQL comments
-func isOnlyComments(stmt string) bool {
-	lines := strings.Split(stmt, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "--") {
-			continue
-		}
-		// Found a non-comment, non-empty line
-		return false
-	}
-	return true
+// opCount counts operations in flight. Unlike a sync.WaitGroup, it may be
+// incremented while Drain waits for it to drop to zero, as statements of
+// running operations still are.
+type opCount struct {
+	mu     sync.Mutex
+	n      int
+	idleCh chan struct{} // Closed when n drops to zero
 }
 
-// Close closes the database connection
-func (s *MariaDBStore) Close() error {
-	s.closed.Store(true)
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	var err error
-	if s.db != nil {
-		if cerr := s.db.Close(); cerr != nil {
-			if !errors.Is(cerr, context.Canceled) {
-				err = errors.Join(err, cerr)
+// add counts one more operation, returning the func that ends it.
+func (c *opCount) add() func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.n == 0 {
+		c.idleCh = make(chan struct{})
+	}
+	c.n++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.n--
+			if c.n == 0 {
+				close(c.idleCh)
 			}
-		}
+		})
 	}
-	s.db = nil
-	return err
+}
+
+// idle returns a channel that is closed once no operations are in flight.
+func (c *opCount) idle() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.n == 0 {
+		ch := make(chan struct{})
+		close(ch)
+		return ch
+	}
+	return c.idleCh
 }
 
 // Path returns the database name (for daemon validation compatibility)
@@ -384,15 +1357,77 @@ func (s *MariaDBStore) IsClosed() bool {
 	return s.closed.Load()
 }
 
-// UnderlyingDB returns the underlying *sql.DB connection
+// txOptions returns the options for transactions begun by the store: the
+// configured isolation level, and read-only transactions on a read-only
+// store, so the server rejects any write too.
+func (s *MariaDBStore) txOptions() *sql.TxOptions {
+	if !s.readOnly && s.isolation == sql.LevelDefault {
+		return nil
+	}
+	return &sql.TxOptions{Isolation: s.isolation, ReadOnly: s.readOnly}
+}
+
+// validateIsolationLevel rejects isolation levels MariaDB does not have.
+func validateIsolationLevel(level sql.IsolationLevel) error {
+	switch level {
+	case sql.LevelDefault, sql.LevelReadUncommitted, sql.LevelReadCommitted, sql.LevelRepeatableRead, sql.LevelSerializable:
+		return nil
+	}
+	return fmt.Errorf("invalid MariaDB config: unsupported IsolationLevel %s", level)
+}
+
+// checkWritable returns ErrStoreClosed if the store is closed and ErrReadOnly
+// if it is read-only. Write methods call it before touching the database.
+func (s *MariaDBStore) checkWritable() error {
+	if s.IsClosed() {
+		return ErrStoreClosed
+	}
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// RedactedConnStr returns the connection string with the password masked,
+// safe to include in logs and error messages.
+func (s *MariaDBStore) RedactedConnStr() string {
+	return redactDSN(s.currentConnStr())
+}
+
+// currentConnStr returns the connection string of the current pool.
+func (s *MariaDBStore) currentConnStr() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.connStr
+}
+
+// UnderlyingDB returns the underlying *sql.DB connection, or nil once the
+// store is closed. Reconnect replaces it, so callers should not keep it.
 func (s *MariaDBStore) UnderlyingDB() *sql.DB {
-	return s.db
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.primary == nil {
+		return nil
+	}
+	return s.primary.db
 }
 
 // UnderlyingConn returns a connection from the pool
 func (s *MariaDBStore) UnderlyingConn(ctx context.Context) (*sql.Conn, error) {
-	return s.db.Conn(ctx)
+	db, done, err := s.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	conn, err := db.Conn(ctx)
+	if err == nil && s.conns != nil {
+		s.conns.track(conn)
+	}
+	return conn, err
 }
 
-// Ensure MariaDBStore implements storage.Storage
-var _ storage.Storage = (*MariaDBStore)(nil)
+// Ensure MariaDBStore implements storage.Storage and storage.SnapshotImporter
+var (
+	_ storage.Storage          = (*MariaDBStore)(nil)
+	_ storage.SnapshotImporter = (*MariaDBStore)(nil)
+)
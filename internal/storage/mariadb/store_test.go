@@ -0,0 +1,425 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestNewWithoutCreateDatabase(t *testing.T) {
+	existing, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	// Record every DSN opened by New
+	var mu sync.Mutex
+	var opened []string
+	original := sqlOpen
+	defer func() { sqlOpen = original }()
+	sqlOpen = func(driverName, dsn string) (*sql.DB, error) {
+		mu.Lock()
+		opened = append(opened, dsn)
+		mu.Unlock()
+		return original(driverName, dsn)
+	}
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	createDatabase := false
+	store, err := New(ctx, &Config{Database: existing.Path(), CreateDatabase: &createDatabase})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer store.Close()
+
+	if len(opened) == 0 {
+		t.Fatal("expected New to open a connection")
+	}
+	for _, dsn := range opened {
+		parsed, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			t.Fatalf("ParseDSN(%q) failed: %v", dsn, err)
+		}
+		if parsed.DBName != existing.Path() {
+			t.Errorf("unexpected init connection without the target database: %q", dsn)
+		}
+	}
+}
+
+func TestNewWithoutCreateDatabaseMissing(t *testing.T) {
+	// Only runs when a server is available
+	_, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	createDatabase := false
+	_, err := New(ctx, &Config{Database: testDatabaseName(t), CreateDatabase: &createDatabase})
+	if err == nil {
+		t.Fatal("expected error for missing database")
+	}
+	if !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("expected a missing-database error, got: %v", err)
+	}
+}
+
+// TestMethodsAfterClose calls every exported method that returns an error on a
+// closed store and checks it returns ErrStoreClosed instead of panicking.
+func TestMethodsAfterClose(t *testing.T) {
+	store := &MariaDBStore{storeState: newStoreState(nil, ""), dbName: "beads"}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	contextType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	skip := map[string]bool{"Close": true, "Drain": true} // Close and Drain are idempotent and return nil
+
+	v := reflect.ValueOf(store)
+	for i := 0; i < v.NumMethod(); i++ {
+		method := v.Type().Method(i)
+		mt := method.Type
+		if skip[method.Name] || mt.NumOut() == 0 || mt.Out(mt.NumOut()-1) != errorType {
+			continue
+		}
+		t.Run(method.Name, func(t *testing.T) {
+			// Zero arguments, except a live context (the receiver is argument 0)
+			args := make([]reflect.Value, mt.NumIn()-1)
+			for j := range args {
+				in := mt.In(j + 1)
+				if in == contextType {
+					args[j] = reflect.ValueOf(context.Background())
+				} else {
+					args[j] = reflect.Zero(in)
+				}
+			}
+			if mt.IsVariadic() {
+				args = args[:len(args)-1]
+			}
+			out := v.Method(i).Call(args)
+			err, _ := out[len(out)-1].Interface().(error)
+			if !errors.Is(err, ErrStoreClosed) {
+				t.Errorf("%s after Close = %v, want ErrStoreClosed", method.Name, err)
+			}
+		})
+	}
+
+	// Accessors without an error return stay safe to call
+	if got := store.Stats(); got != (sql.DBStats{}) {
+		t.Errorf("Stats after Close = %+v, want zero value", got)
+	}
+	if !store.IsClosed() {
+		t.Error("IsClosed should be true after Close")
+	}
+}
+
+func TestDrainWaitsForInFlightOperations(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	opDone := make(chan error, 1)
+	go func() {
+		opDone <- store.withRetry(ctx, func() error {
+			close(started)
+			<-release
+			_, err := store.primaryDB().ExecContext(ctx, "SELECT 1")
+			return err
+		})
+	}()
+	<-started
+
+	drained := make(chan error, 1)
+	go func() { drained <- store.Drain(ctx) }()
+
+	select {
+	case err := <-drained:
+		t.Fatalf("Drain returned %v while an operation was in flight", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if _, err := store.GetIssue(ctx, "test-1"); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("GetIssue while draining = %v, want ErrStoreClosed", err)
+	}
+	if err := store.withRetry(ctx, func() error { return nil }); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("withRetry while draining = %v, want ErrStoreClosed", err)
+	}
+
+	close(release)
+	if err := <-opDone; err != nil {
+		t.Errorf("in-flight operation failed: %v", err)
+	}
+	if err := <-drained; err != nil {
+		t.Errorf("Drain failed: %v", err)
+	}
+	if store.Stats() != (sql.DBStats{}) {
+		t.Error("Drain should close the pool")
+	}
+}
+
+// blockingConnector hands out connections whose statements signal started
+// and then wait for release, to hold a statement in flight without a server.
+type blockingConnector struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c blockingConnector) Connect(context.Context) (driver.Conn, error) { return blockingConn(c), nil }
+func (blockingConnector) Driver() driver.Driver                          { return nil }
+
+type blockingConn blockingConnector
+
+func (blockingConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (blockingConn) Close() error                        { return nil }
+func (blockingConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c blockingConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	c.started <- struct{}{}
+	<-c.release
+	return emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return []string{"label"} }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+func TestDrainWaitsForPlainRead(t *testing.T) {
+	conn := blockingConnector{started: make(chan struct{}), release: make(chan struct{})}
+	store := &MariaDBStore{storeState: newStoreState(sql.OpenDB(conn), ""), dbName: "beads"}
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	readDone := make(chan error, 1)
+	go func() {
+		_, err := store.GetLabels(ctx, "test-1")
+		readDone <- err
+	}()
+	<-conn.started
+
+	drained := make(chan error, 1)
+	go func() { drained <- store.Drain(ctx) }()
+	select {
+	case err := <-drained:
+		t.Fatalf("Drain returned %v while a read was in flight", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(conn.release)
+	if err := <-readDone; err != nil {
+		t.Errorf("in-flight read failed: %v", err)
+	}
+	if err := <-drained; err != nil {
+		t.Errorf("Drain failed: %v", err)
+	}
+	if _, err := store.GetLabels(ctx, "test-1"); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("GetLabels after Drain = %v, want ErrStoreClosed", err)
+	}
+}
+
+func TestDrainContextExpires(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	go func() {
+		_ = store.withRetry(context.Background(), func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := store.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Drain = %v, want context.DeadlineExceeded", err)
+	}
+	if !store.IsClosed() {
+		t.Error("IsClosed should be true after Drain")
+	}
+}
+
+// writeMethods are the store methods that modify the database.
+var writeMethods = []string{
+	"AddComment", "AddDependencies", "AddDependency", "AddIssueComment", "AddLabel", "AddLabels", "Analyze", "BulkDeleteIssues", "BulkUpdateStatus",
+	"ClaimIssue", "ClaimNextReadyIssue", "ClearAllExportHashes", "ClearDependencies", "ClearDependents", "ClearDirtyIssuesByID", "CloneTo", "CloseIssue",
+	"CreateIssue", "CreateIssues", "CreateIssuesBatch", "CreateIssuesOnConflict", "CreateIssuesWithFullOptions",
+	"DeleteComment", "DeleteConfig", "DeleteIssue", "GetNextChildID", "ImportCSV", "ImportIssueComment", "ImportJSON", "ImportJSONL",
+	"Optimize",
+	"ReassignIssues", "RemapIssueIDs", "RemoveDependency", "RemoveLabel", "RemoveLabels", "RenameCounterPrefix", "RenameDependencyPrefix", "RestoreIssue",
+	"SetCheckpoint", "SetConfig", "SetExportHash", "SetJSONLFileHash", "SetMetadata", "SoftDeleteIssue",
+	"UpdateIssue", "UpdateIssueAtVersion", "UpdateIssueID",
+}
+
+func TestWriteMethodsRejectedWhenReadOnly(t *testing.T) {
+	// No database: every write must fail before touching it
+	store := &MariaDBStore{storeState: newStoreState(nil, ""), dbName: "beads", readOnly: true}
+	contextType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	v := reflect.ValueOf(store)
+	for _, name := range writeMethods {
+		t.Run(name, func(t *testing.T) {
+			m := v.MethodByName(name)
+			mt := m.Type()
+			args := make([]reflect.Value, mt.NumIn())
+			for j := range args {
+				if in := mt.In(j); in == contextType {
+					args[j] = reflect.ValueOf(context.Background())
+				} else {
+					args[j] = reflect.Zero(in)
+				}
+			}
+			if mt.IsVariadic() {
+				args = args[:len(args)-1]
+			}
+			out := m.Call(args)
+			err, _ := out[len(out)-1].Interface().(error)
+			if !errors.Is(err, ErrReadOnly) {
+				t.Errorf("%s on read-only store = %v, want ErrReadOnly", name, err)
+			}
+		})
+	}
+}
+
+func TestReadOnlyStore(t *testing.T) {
+	writable, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	issue := &types.Issue{ID: "test-ro", Title: "Read me", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := writable.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	store, err := New(ctx, &Config{Database: writable.dbName, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("New(ReadOnly) failed: %v", err)
+	}
+	defer store.Close()
+
+	// Reads work
+	if got, err := store.GetIssue(ctx, "test-ro"); err != nil || got == nil || got.Title != "Read me" {
+		t.Errorf("GetIssue = %v, %v; want the issue", got, err)
+	}
+	if found, err := store.SearchIssues(ctx, "", types.IssueFilter{}); err != nil || len(found) != 1 {
+		t.Errorf("SearchIssues = %d issues, %v; want 1", len(found), err)
+	}
+	if prefix, err := store.GetConfig(ctx, "issue_prefix"); err != nil || prefix != "test" {
+		t.Errorf("GetConfig = %q, %v; want test", prefix, err)
+	}
+
+	// Writes are rejected, including inside transactions
+	if err := store.UpdateIssue(ctx, "test-ro", map[string]interface{}{"title": "Changed"}, "tester"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("UpdateIssue = %v, want ErrReadOnly", err)
+	}
+	if err := store.DeleteIssue(ctx, "test-ro"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("DeleteIssue = %v, want ErrReadOnly", err)
+	}
+	err = store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		if _, err := tx.GetIssue(ctx, "test-ro"); err != nil {
+			return err
+		}
+		return tx.AddLabel(ctx, "test-ro", "x", "tester")
+	})
+	if !errors.Is(err, ErrReadOnly) {
+		t.Errorf("RunInTransaction write = %v, want ErrReadOnly", err)
+	}
+	if got, _ := writable.GetIssue(ctx, "test-ro"); got == nil || got.Title != "Read me" {
+		t.Errorf("issue after rejected writes = %+v, want it unchanged", got)
+	}
+}
+
+func TestStrictSQLModeRejectsOversizedValue(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	var mode string
+	if err := store.UnderlyingDB().QueryRowContext(ctx, "SELECT @@SESSION.sql_mode").Scan(&mode); err != nil {
+		t.Fatalf("failed to read sql_mode: %v", err)
+	}
+	if !strings.Contains(mode, "STRICT_TRANS_TABLES") {
+		t.Errorf("session sql_mode = %q, want STRICT_TRANS_TABLES", mode)
+	}
+
+	issue := &types.Issue{Title: "Long spec", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, SpecID: strings.Repeat("s", 1025)}
+	if err := store.CreateIssue(ctx, issue, "tester"); err == nil {
+		got, _ := store.GetIssue(ctx, issue.ID)
+		t.Fatalf("CreateIssue with a 1025-character spec_id succeeded (stored %d characters), want an error", len(got.SpecID))
+	}
+}
+
+func TestSQLModeOverride(t *testing.T) {
+	store, cleanup := setupTestStoreWithConfig(t, &Config{Params: map[string]string{"sql_mode": "'NO_ENGINE_SUBSTITUTION'"}})
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	var mode string
+	if err := store.UnderlyingDB().QueryRowContext(ctx, "SELECT @@SESSION.sql_mode").Scan(&mode); err != nil {
+		t.Fatalf("failed to read sql_mode: %v", err)
+	}
+	if mode != "NO_ENGINE_SUBSTITUTION" {
+		t.Errorf("session sql_mode = %q, want the Params override", mode)
+	}
+}
+
+func TestSessionVars(t *testing.T) {
+	store, cleanup := setupTestStoreWithConfig(t, &Config{SessionVars: map[string]string{
+		"time_zone":            "+03:00",
+		"group_concat_max_len": "65536",
+	}})
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	// Hold several connections at once so each is a separate session
+	var conns []*sql.Conn
+	defer func() {
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+	}()
+	for i := 0; i < 3; i++ {
+		conn, err := store.UnderlyingDB().Conn(ctx)
+		if err != nil {
+			t.Fatalf("failed to get connection: %v", err)
+		}
+		conns = append(conns, conn)
+
+		var timeZone string
+		var maxLen int
+		if err := conn.QueryRowContext(ctx, "SELECT @@session.time_zone, @@session.group_concat_max_len").Scan(&timeZone, &maxLen); err != nil {
+			t.Fatalf("failed to read session variables: %v", err)
+		}
+		if timeZone != "+03:00" || maxLen != 65536 {
+			t.Errorf("connection %d: time_zone, group_concat_max_len = %q, %d; want +03:00, 65536", i, timeZone, maxLen)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -17,15 +18,97 @@ type mariadbTransaction struct {
 	store *MariaDBStore
 }
 
+// checkWritable returns ErrReadOnly if the store is read-only.
+func (t *mariadbTransaction) checkWritable() error {
+	if t.store.readOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
+
 // CreateIssueImport is the import-friendly issue creation hook.
 // MariaDB does not enforce prefix validation at the storage layer, so this delegates to CreateIssue.
 func (t *mariadbTransaction) CreateIssueImport(ctx context.Context, issue *types.Issue, actor string, skipPrefixValidation bool) error {
 	return t.CreateIssue(ctx, issue, actor)
 }
 
+// WithTx runs fn with a view of the store whose methods all execute in one
+// transaction, committed if fn returns nil and rolled back otherwise.
+// The transaction is replayed from the start on deadlocks and transient
+// connection errors, so fn may run more than once and should have no side
+// effects outside the store. The view must not be used after fn returns.
+// Calling WithTx on a view joins the enclosing transaction.
+func (s *MariaDBStore) WithTx(ctx context.Context, fn func(tx *MariaDBStore) error) error {
+	return s.withRetryTx(ctx, func(tx *sql.Tx) error {
+		// The view shares the store's settings and storeState
+		view := *s
+		view.tx = tx
+		return fn(&view)
+	})
+}
+
+// savepointNamePattern matches the savepoint names Savepoint accepts.
+var savepointNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Savepoint marks a point in the WithTx transaction that RollbackTo can
+// return to, so that a sub-operation can be undone without abandoning the
+// whole transaction. It fails outside WithTx.
+//
+// MariaDB savepoints follow these rules:
+//   - Setting a savepoint with the name of an existing one moves it.
+//   - RollbackTo keeps the savepoint, so it can be rolled back to again, and
+//     drops any savepoints set after it.
+//   - Commit and rollback of the transaction drop all savepoints, as does any
+//     statement that commits implicitly, such as DDL.
+//   - Row locks taken after the savepoint are held until the transaction ends,
+//     even if RollbackTo undoes the rows' changes.
+//   - A deadlock rolls back the whole transaction. WithTx then replays fn from
+//     the start, where the savepoints are set again.
+func (s *MariaDBStore) Savepoint(ctx context.Context, name string) error {
+	return s.execSavepoint(ctx, "SAVEPOINT ", name)
+}
+
+// RollbackTo undoes the changes made in the WithTx transaction since the
+// savepoint name was set (see Savepoint). The transaction stays open.
+func (s *MariaDBStore) RollbackTo(ctx context.Context, name string) error {
+	return s.execSavepoint(ctx, "ROLLBACK TO SAVEPOINT ", name)
+}
+
+// execSavepoint runs the savepoint statement stmt for name in the WithTx
+// transaction.
+func (s *MariaDBStore) execSavepoint(ctx context.Context, stmt, name string) error {
+	if s.IsClosed() {
+		return ErrStoreClosed
+	}
+	if s.tx == nil {
+		return fmt.Errorf("savepoints are only available inside WithTx")
+	}
+	if !savepointNamePattern.MatchString(name) || len(name) > maxIdentifierLength {
+		return fmt.Errorf("invalid savepoint name %q: must be letters, digits, and underscores, starting with a letter or underscore", name)
+	}
+	if _, err := s.tx.ExecContext(ctx, stmt+quoteIdentifier(name)); err != nil {
+		return fmt.Errorf("failed to %s%s: %w", strings.ToLower(stmt), name, err)
+	}
+	return nil
+}
+
 // RunInTransaction executes a function within a database transaction
 func (s *MariaDBStore) RunInTransaction(ctx context.Context, fn func(tx storage.Transaction) error) error {
-	sqlTx, err := s.db.BeginTx(ctx, nil)
+	if s.tx != nil {
+		// Already inside WithTx: run in the enclosing transaction
+		return fn(&mariadbTransaction{tx: s.tx, store: s})
+	}
+	done, err := s.track()
+	if err != nil {
+		return err
+	}
+	defer done()
+	db, release, err := s.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+	sqlTx, err := db.BeginTx(ctx, s.txOptions())
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -44,11 +127,21 @@ func (s *MariaDBStore) RunInTransaction(ctx context.Context, fn func(tx storage.
 		return err
 	}
 
-	return sqlTx.Commit()
+	if err := sqlTx.Commit(); err != nil {
+		return err
+	}
+	s.recordWrite(ctx)
+	return nil
 }
 
 // CreateIssue creates an issue within the transaction
 func (t *mariadbTransaction) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
+	if err := checkPriority(issue.Priority); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
 	now := time.Now().UTC()
 	if issue.CreatedAt.IsZero() {
 		issue.CreatedAt = now
@@ -79,12 +172,9 @@ func (t *mariadbTransaction) CreateIssue(ctx context.Context, issue *types.Issue
 			prefix = configPrefix + "-" + issue.IDPrefix
 		}
 
-		// Generate ID
-		generatedID, err := generateIssueID(ctx, t.tx, prefix, issue, actor)
-		if err != nil {
-			return fmt.Errorf("failed to generate issue ID: %w", err)
-		}
-		issue.ID = generatedID
+		return insertWithGeneratedID(ctx, t.tx, t.store.idGenerator(), prefix, issue, actor, func() error {
+			return insertIssueTx(ctx, t.tx, issue)
+		})
 	}
 
 	return insertIssueTx(ctx, t.tx, issue)
@@ -92,6 +182,9 @@ func (t *mariadbTransaction) CreateIssue(ctx context.Context, issue *types.Issue
 
 // CreateIssues creates multiple issues within the transaction
 func (t *mariadbTransaction) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
 	for _, issue := range issues {
 		if err := t.CreateIssue(ctx, issue, actor); err != nil {
 			return err
@@ -100,9 +193,14 @@ func (t *mariadbTransaction) CreateIssues(ctx context.Context, issues []*types.I
 	return nil
 }
 
-// GetIssue retrieves an issue within the transaction
+// GetIssue retrieves an issue within the transaction. Soft-deleted issues are
+// reported as not found, as by MariaDBStore.GetIssue.
 func (t *mariadbTransaction) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
-	return scanIssueTx(ctx, t.tx, id)
+	issue, err := scanIssueTx(ctx, t.tx, id)
+	if err != nil || issue == nil || isSoftDeleted(issue) {
+		return nil, err
+	}
+	return issue, nil
 }
 
 // SearchIssues searches for issues within the transaction
@@ -125,6 +223,9 @@ func (t *mariadbTransaction) SearchIssues(ctx context.Context, query string, fil
 		whereClauses = append(whereClauses, "spec_id LIKE ?")
 		args = append(args, filter.SpecIDPrefix+"%")
 	}
+	if !filter.IncludeDeleted {
+		whereClauses = append(whereClauses, notSoftDeleted)
+	}
 
 	whereSQL := ""
 	if len(whereClauses) > 0 {
@@ -158,7 +259,7 @@ func (t *mariadbTransaction) SearchIssues(ctx context.Context, query string, fil
 	// Now fetch each issue (safe since rows is closed)
 	var issues []*types.Issue
 	for _, id := range ids {
-		issue, err := t.GetIssue(ctx, id)
+		issue, err := scanIssueTx(ctx, t.tx, id)
 		if err != nil {
 			return nil, err
 		}
@@ -171,7 +272,10 @@ func (t *mariadbTransaction) SearchIssues(ctx context.Context, query string, fil
 
 // UpdateIssue updates an issue within the transaction
 func (t *mariadbTransaction) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
-	setClauses := []string{"updated_at = ?"}
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
+	setClauses := []string{"updated_at = ?", "version = version + 1"}
 	args := []interface{}{time.Now().UTC()}
 
 	for key, value := range updates {
@@ -183,34 +287,61 @@ func (t *mariadbTransaction) UpdateIssue(ctx context.Context, id string, updates
 			columnName = "ephemeral"
 		}
 		setClauses = append(setClauses, fmt.Sprintf("`%s` = ?", columnName))
+		if key == "priority" {
+			priority, err := priorityValue(value)
+			if err != nil {
+				return err
+			}
+			value = priority
+		}
 		args = append(args, value)
 	}
 
 	args = append(args, id)
 	// nolint:gosec // G201: setClauses contains only column names (e.g. "status = ?"), actual values passed via args
 	query := fmt.Sprintf("UPDATE issues SET %s WHERE id = ?", strings.Join(setClauses, ", "))
-	_, err := t.tx.ExecContext(ctx, query, args...)
-	return err
+	return updateAudited(ctx, t.tx, id, actor, auditColumns(updates), func() error {
+		_, err := t.tx.ExecContext(ctx, query, args...)
+		return err
+	})
 }
 
 // CloseIssue closes an issue within the transaction
 func (t *mariadbTransaction) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
 	now := time.Now().UTC()
-	_, err := t.tx.ExecContext(ctx, `
-		UPDATE issues SET status = ?, closed_at = ?, updated_at = ?, close_reason = ?, closed_by_session = ?
-		WHERE id = ?
-	`, types.StatusClosed, now, now, reason, session, id)
-	return err
+	return updateAudited(ctx, t.tx, id, actor, closeAuditColumns, func() error {
+		_, err := t.tx.ExecContext(ctx, `
+			UPDATE issues SET status = ?, closed_at = ?, updated_at = ?, close_reason = ?, closed_by_session = ?, version = version + 1
+			WHERE id = ?
+		`, types.StatusClosed, now, now, reason, session, id)
+		return err
+	})
 }
 
 // DeleteIssue deletes an issue within the transaction
 func (t *mariadbTransaction) DeleteIssue(ctx context.Context, id string) error {
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
+	if err := recordHardDelete(ctx, t.tx, id); err != nil {
+		return err
+	}
 	_, err := t.tx.ExecContext(ctx, "DELETE FROM issues WHERE id = ?", id)
 	return err
 }
 
-// AddDependency adds a dependency within the transaction
+// AddDependency adds a dependency within the transaction, checked as
+// MariaDBStore.AddDependencies checks it
 func (t *mariadbTransaction) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
+	if err := checkNewDependencies(ctx, t.tx, []*types.Dependency{dep}); err != nil {
+		return err
+	}
 	_, err := t.tx.ExecContext(ctx, `
 		INSERT INTO dependencies (issue_id, depends_on_id, type, created_at, created_by, thread_id)
 		VALUES (?, ?, ?, NOW(), ?, ?)
@@ -251,6 +382,9 @@ func (t *mariadbTransaction) GetDependencyRecords(ctx context.Context, issueID s
 
 // RemoveDependency removes a dependency within the transaction
 func (t *mariadbTransaction) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
 	_, err := t.tx.ExecContext(ctx, `
 		DELETE FROM dependencies WHERE issue_id = ? AND depends_on_id = ?
 	`, issueID, dependsOnID)
@@ -259,14 +393,19 @@ func (t *mariadbTransaction) RemoveDependency(ctx context.Context, issueID, depe
 
 // AddLabel adds a label within the transaction
 func (t *mariadbTransaction) AddLabel(ctx context.Context, issueID, label, actor string) error {
-	_, err := t.tx.ExecContext(ctx, `
-		INSERT IGNORE INTO labels (issue_id, label) VALUES (?, ?)
-	`, issueID, label)
-	return err
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
+	return attachLabels(ctx, t.tx, issueID, []string{label})
 }
 
 func (t *mariadbTransaction) GetLabels(ctx context.Context, issueID string) ([]string, error) {
-	rows, err := t.tx.QueryContext(ctx, `SELECT label FROM labels WHERE issue_id = ? ORDER BY label`, issueID)
+	rows, err := t.tx.QueryContext(ctx, `
+		SELECT l.name FROM issue_labels il
+		JOIN labels l ON l.id = il.label_id
+		WHERE il.issue_id = ?
+		ORDER BY l.name
+	`, issueID)
 	if err != nil {
 		return nil, err
 	}
@@ -284,14 +423,17 @@ func (t *mariadbTransaction) GetLabels(ctx context.Context, issueID string) ([]s
 
 // RemoveLabel removes a label within the transaction
 func (t *mariadbTransaction) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
-	_, err := t.tx.ExecContext(ctx, `
-		DELETE FROM labels WHERE issue_id = ? AND label = ?
-	`, issueID, label)
-	return err
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
+	return detachLabels(ctx, txQuerier{t.tx}, issueID, []string{label})
 }
 
 // SetConfig sets a config value within the transaction
 func (t *mariadbTransaction) SetConfig(ctx context.Context, key, value string) error {
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
 	_, err := t.tx.ExecContext(ctx, `
 		INSERT INTO config (`+"`key`"+`, value) VALUES (?, ?)
 		ON DUPLICATE KEY UPDATE value = VALUES(value)
@@ -311,6 +453,9 @@ func (t *mariadbTransaction) GetConfig(ctx context.Context, key string) (string,
 
 // SetMetadata sets a metadata value within the transaction
 func (t *mariadbTransaction) SetMetadata(ctx context.Context, key, value string) error {
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
 	_, err := t.tx.ExecContext(ctx, `
 		INSERT INTO metadata (`+"`key`"+`, value) VALUES (?, ?)
 		ON DUPLICATE KEY UPDATE value = VALUES(value)
@@ -329,6 +474,9 @@ func (t *mariadbTransaction) GetMetadata(ctx context.Context, key string) (strin
 }
 
 func (t *mariadbTransaction) ImportIssueComment(ctx context.Context, issueID, author, text string, createdAt time.Time) (*types.Comment, error) {
+	if err := t.checkWritable(); err != nil {
+		return nil, err
+	}
 	// Verify issue exists in tx
 	iss, err := t.GetIssue(ctx, issueID)
 	if err != nil {
@@ -387,6 +535,9 @@ func (t *mariadbTransaction) GetIssueComments(ctx context.Context, issueID strin
 
 // AddComment adds a comment within the transaction
 func (t *mariadbTransaction) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	if err := t.checkWritable(); err != nil {
+		return err
+	}
 	_, err := t.tx.ExecContext(ctx, `
 		INSERT INTO events (issue_id, event_type, actor, comment)
 		VALUES (?, ?, ?, ?)
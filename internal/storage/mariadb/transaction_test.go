@@ -0,0 +1,208 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestWithTxCommits(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	parent := &types.Issue{Title: "Parent", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}
+	child := &types.Issue{Title: "Child", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	err := store.WithTx(ctx, func(tx *MariaDBStore) error {
+		if err := tx.CreateIssue(ctx, parent, "tester"); err != nil {
+			return err
+		}
+		if err := tx.CreateIssue(ctx, child, "tester"); err != nil {
+			return err
+		}
+		// Reads inside the transaction see its own writes
+		if got, err := tx.GetIssue(ctx, parent.ID); err != nil || got == nil {
+			t.Errorf("GetIssue inside WithTx = %v, %v; want the new issue", got, err)
+		}
+		return tx.AddDependency(ctx, &types.Dependency{IssueID: child.ID, DependsOnID: parent.ID, Type: types.DepBlocks}, "tester")
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+
+	deps, err := store.GetDependencies(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].ID != parent.ID {
+		t.Errorf("GetDependencies(%s) = %v, want [%s]", child.ID, deps, parent.ID)
+	}
+}
+
+func TestWithTxRollsBackOnError(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	errAbort := errors.New("abort")
+	issue := &types.Issue{Title: "Rolled back", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	calls := 0
+	err := store.WithTx(ctx, func(tx *MariaDBStore) error {
+		calls++
+		if err := tx.CreateIssue(ctx, issue, "tester"); err != nil {
+			return err
+		}
+		if err := tx.AddLabel(ctx, issue.ID, "doomed", "tester"); err != nil {
+			return err
+		}
+		if err := tx.SetConfig(ctx, "rollback_marker", "set"); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithTx = %v, want errAbort", err)
+	}
+	if calls != 1 {
+		t.Errorf("fn ran %d times, want 1 (errors from fn are not retried)", calls)
+	}
+
+	if got, err := store.GetIssue(ctx, issue.ID); err != nil || got != nil {
+		t.Errorf("GetIssue(%s) = %v, %v; want nothing persisted", issue.ID, got, err)
+	}
+	if labels, err := store.GetLabels(ctx, issue.ID); err != nil || len(labels) != 0 {
+		t.Errorf("GetLabels(%s) = %v, %v; want none", issue.ID, labels, err)
+	}
+	if v, err := store.GetConfig(ctx, "rollback_marker"); err != nil || v != "" {
+		t.Errorf("GetConfig(rollback_marker) = %q, %v; want unset", v, err)
+	}
+	var events int
+	if err := store.UnderlyingDB().QueryRowContext(ctx, "SELECT COUNT(*) FROM events WHERE issue_id = ?", issue.ID).Scan(&events); err != nil || events != 0 {
+		t.Errorf("events for %s = %d, %v; want 0", issue.ID, events, err)
+	}
+}
+
+func TestWithTxViewCannotClose(t *testing.T) {
+	view := &MariaDBStore{storeState: newStoreState(nil, ""), dbName: "beads", tx: new(sql.Tx)}
+	if err := view.Close(); err == nil {
+		t.Error("expected error closing a WithTx view")
+	}
+}
+
+func TestSavepointArguments(t *testing.T) {
+	ctx := context.Background()
+	store := &MariaDBStore{storeState: newStoreState(nil, ""), dbName: "beads"}
+	if err := store.Savepoint(ctx, "sp"); err == nil {
+		t.Error("expected error setting a savepoint outside WithTx")
+	}
+	view := &MariaDBStore{storeState: newStoreState(nil, ""), dbName: "beads", tx: new(sql.Tx)}
+	for _, name := range []string{"", "1st", "sp; COMMIT", "sp`x", strings.Repeat("s", 65)} {
+		if err := view.Savepoint(ctx, name); err == nil {
+			t.Errorf("Savepoint(%q) succeeded, want invalid name error", name)
+		}
+		if err := view.RollbackTo(ctx, name); err == nil {
+			t.Errorf("RollbackTo(%q) succeeded, want invalid name error", name)
+		}
+	}
+}
+
+func TestWithTxSavepoint(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	create := func(tx *MariaDBStore, id string) error {
+		return tx.CreateIssue(ctx, &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}, "tester")
+	}
+	err := store.WithTx(ctx, func(tx *MariaDBStore) error {
+		if err := create(tx, "test-kept"); err != nil {
+			return err
+		}
+		if err := tx.Savepoint(ctx, "before_risky"); err != nil {
+			return err
+		}
+		if err := create(tx, "test-undone"); err != nil {
+			return err
+		}
+		if err := tx.RollbackTo(ctx, "before_risky"); err != nil {
+			return err
+		}
+		if got, err := tx.GetIssue(ctx, "test-undone"); err != nil || got != nil {
+			t.Errorf("GetIssue(test-undone) after RollbackTo = %v, %v; want nil", got, err)
+		}
+		if err := tx.RollbackTo(ctx, "never_set"); err == nil {
+			t.Error("expected error rolling back to an unknown savepoint")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+
+	if got, err := store.GetIssue(ctx, "test-kept"); err != nil || got == nil {
+		t.Errorf("GetIssue(test-kept) = %v, %v; want the issue written before the savepoint", got, err)
+	}
+	if got, err := store.GetIssue(ctx, "test-undone"); err != nil || got != nil {
+		t.Errorf("GetIssue(test-undone) = %v, %v; want it rolled back", got, err)
+	}
+}
+
+// txIsolation returns the isolation level of tx as the server reports it.
+// MariaDB before 11.1 only has tx_isolation, the older name.
+func txIsolation(ctx context.Context, tx *sql.Tx) (string, error) {
+	var level string
+	err := tx.QueryRowContext(ctx, "SELECT @@transaction_isolation").Scan(&level)
+	if err != nil {
+		err = tx.QueryRowContext(ctx, "SELECT @@tx_isolation").Scan(&level)
+	}
+	return level, err
+}
+
+func TestWithTxIsolationLevel(t *testing.T) {
+	for _, tt := range []struct {
+		level sql.IsolationLevel
+		want  string
+	}{
+		{sql.LevelReadCommitted, "READ-COMMITTED"},
+		{sql.LevelSerializable, "SERIALIZABLE"},
+	} {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			store, cleanup := setupTestStoreWithConfig(t, &Config{IsolationLevel: tt.level})
+			defer cleanup()
+
+			ctx, cancel := testContext(t)
+			defer cancel()
+
+			err := store.WithTx(ctx, func(view *MariaDBStore) error {
+				got, err := txIsolation(ctx, view.tx)
+				if err != nil {
+					return err
+				}
+				if !strings.EqualFold(got, tt.want) {
+					t.Errorf("transaction isolation = %q, want %q", got, tt.want)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("WithTx failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewRejectsUnsupportedIsolationLevel(t *testing.T) {
+	// Rejected before connecting, so no server is needed
+	if _, err := New(context.Background(), &Config{IsolationLevel: sql.LevelSnapshot}); err == nil {
+		t.Error("expected error for IsolationLevel snapshot")
+	}
+}
@@ -2,9 +2,39 @@ package mariadb
 
 import (
 	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 )
 
+// maxIdentifierLength is the MariaDB limit for database and table names.
+const maxIdentifierLength = 64
+
+// databaseNamePattern restricts database names to a portable subset of
+// MariaDB identifiers that never needs escaping inside backticks.
+var databaseNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// validateDatabaseName returns an error unless name is 1-64 characters of
+// letters, digits, and underscores.
+func validateDatabaseName(name string) error {
+	if name == "" {
+		return fmt.Errorf("invalid MariaDB database name: must not be empty")
+	}
+	if len(name) > maxIdentifierLength {
+		return fmt.Errorf("invalid MariaDB database name %q: longer than %d characters", name, maxIdentifierLength)
+	}
+	if !databaseNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid MariaDB database name %q: only letters, digits, and underscores are allowed", name)
+	}
+	return nil
+}
+
+// quoteIdentifier backtick-quotes a MariaDB identifier, doubling embedded backticks.
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
 // parseTimeString parses a time string from database TEXT columns (non-nullable).
 // Used for required timestamp fields like created_at/updated_at when stored as TEXT.
 // Returns zero time if parsing fails, which maintains backwards compatibility.
@@ -0,0 +1,61 @@
+package mariadb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateDatabaseName(t *testing.T) {
+	tests := []struct {
+		name    string
+		dbName  string
+		wantErr bool
+	}{
+		{"default", "beads", false},
+		{"underscores and digits", "beads_test_01", false},
+		{"uppercase", "Beads", false},
+		{"max length", strings.Repeat("a", 64), false},
+		{"empty", "", true},
+		{"too long", strings.Repeat("a", 65), true},
+		{"backtick", "beads`; DROP DATABASE mysql; --", true},
+		{"semicolon", "beads;DROP TABLE issues", true},
+		{"space", "my beads", true},
+		{"hyphen", "my-beads", true},
+		{"dot", "other.beads", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDatabaseName(tt.dbName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateDatabaseName(%q) error = %v, wantErr %v", tt.dbName, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"beads", "`beads`"},
+		{"a`b", "`a``b`"},
+	}
+	for _, tt := range tests {
+		if got := quoteIdentifier(tt.in); got != tt.want {
+			t.Errorf("quoteIdentifier(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNewRejectsInvalidDatabaseName(t *testing.T) {
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	// Rejected before any connection attempt, so no server is needed
+	_, err := New(ctx, &Config{Database: "beads; DROP DATABASE mysql", Port: 1})
+	if err == nil || !strings.Contains(err.Error(), "invalid MariaDB database name") {
+		t.Errorf("expected invalid database name error, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package mariadb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultWatchInterval is the default for Config.WatchInterval
+const DefaultWatchInterval = time.Second
+
+// watchLookback is how far before the newest change already seen each poll
+// looks again. updated_at is set from the writer's clock before its
+// transaction commits, so a slow transaction can become visible after later
+// ones have been polled.
+const watchLookback = 5 * time.Second
+
+// ChangeKind is the kind of change a ChangeEvent reports
+type ChangeKind string
+
+// Change kinds
+const (
+	ChangeCreated ChangeKind = "created"
+	ChangeUpdated ChangeKind = "updated"
+	ChangeDeleted ChangeKind = "deleted" // Soft-deleted (tombstoned) or removed
+)
+
+// ChangeEvent reports that an issue changed. Several changes made between
+// two polls are reported once, as the latest version.
+type ChangeEvent struct {
+	Kind      ChangeKind
+	IssueID   string
+	Version   int64
+	UpdatedAt time.Time
+}
+
+// Watch polls for issues changed after since, every Config.WatchInterval,
+// and sends one event per new issue version on the returned channel, oldest
+// first. Events are not dropped: polling waits while the channel is full.
+// The channel is closed when ctx is done or the store is closed.
+//
+// Soft deletes are reported as ChangeDeleted, and so are hard deletes
+// (DeleteIssue, BulkDeleteIssues), from the deleted_issues table, with the
+// version after the issue's last.
+func (s *MariaDBStore) Watch(ctx context.Context, since time.Time) (<-chan ChangeEvent, error) {
+	if s.IsClosed() {
+		return nil, ErrStoreClosed
+	}
+	if s.tx != nil {
+		return nil, fmt.Errorf("cannot watch inside a WithTx transaction")
+	}
+	interval := s.watchInterval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	events := make(chan ChangeEvent)
+	go func() {
+		defer close(events)
+		w := &watcher{since: since, cursor: since}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			changes, err := w.poll(ctx, s)
+			if errors.Is(err, ErrStoreClosed) || ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				s.log().Warn("failed to poll MariaDB for changes", "database", s.dbName, "error", err)
+			}
+			for _, change := range changes {
+				select {
+				case events <- change:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// watcher is the polling state of one Watch call.
+type watcher struct {
+	since  time.Time         // Changes at or before since are never reported
+	cursor time.Time         // Newest updated_at seen
+	seen   map[watchKey]bool // Changes reported since cursor-watchLookback
+}
+
+// watchKey identifies a change: an issue version, or the removal of an issue.
+// A removed issue can be created again with a lower version.
+type watchKey struct {
+	id      string
+	version int64
+	removed bool
+}
+
+// poll returns the changes not yet reported, oldest first.
+func (w *watcher) poll(ctx context.Context, s *MariaDBStore) ([]ChangeEvent, error) {
+	from := w.cursor.Add(-watchLookback)
+	if from.Before(w.since) {
+		from = w.since
+	}
+
+	var changes []ChangeEvent
+	var keys []watchKey
+	err := s.withReadRetry(ctx, func() error {
+		changes, keys = changes[:0], keys[:0]
+		rows, err := s.primaryDB().QueryContext(ctx, `
+			SELECT id, version, updated_at, deleted_at IS NOT NULL, FALSE
+			FROM issues
+			WHERE updated_at > ? AND updated_at >= ?
+			UNION ALL
+			SELECT issue_id, version, deleted_at, TRUE, TRUE
+			FROM deleted_issues
+			WHERE deleted_at > ? AND deleted_at >= ?
+			ORDER BY 3, 1
+		`, w.since, from, w.since, from)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var change ChangeEvent
+			var deleted, removed bool
+			if err := rows.Scan(&change.IssueID, &change.Version, &change.UpdatedAt, &deleted, &removed); err != nil {
+				return err
+			}
+			switch {
+			case deleted:
+				change.Kind = ChangeDeleted
+			case change.Version <= 1:
+				change.Kind = ChangeCreated
+			default:
+				change.Kind = ChangeUpdated
+			}
+			changes = append(changes, change)
+			keys = append(keys, watchKey{id: change.IssueID, version: change.Version, removed: removed})
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to poll for changed issues: %w", err)
+	}
+
+	var fresh []ChangeEvent
+	inWindow := make(map[watchKey]bool, len(changes))
+	for i, change := range changes {
+		inWindow[keys[i]] = true
+		if w.seen[keys[i]] {
+			continue
+		}
+		fresh = append(fresh, change)
+		if change.UpdatedAt.After(w.cursor) {
+			w.cursor = change.UpdatedAt
+		}
+	}
+	// Changes that left the window are never polled again
+	w.seen = inWindow
+	return fresh, nil
+}
@@ -0,0 +1,100 @@
+package mariadb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestWatch(t *testing.T) {
+	store, cleanup := setupTestStoreWithConfig(t, &Config{WatchInterval: 20 * time.Millisecond})
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	before := &types.Issue{ID: "test-old", Title: "Before watch", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, before, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	watchCtx, stop := context.WithCancel(ctx)
+	events, err := store.Watch(watchCtx, time.Now().UTC().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	stop()
+	for range events {
+		t.Error("Watch reported a change from before since")
+	}
+
+	watchCtx, stop = context.WithCancel(ctx)
+	defer stop()
+	events, err = store.Watch(watchCtx, time.Time{})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	expect := func(kind ChangeKind, id string, version int64) {
+		t.Helper()
+		select {
+		case event := <-events:
+			if event.Kind != kind || event.IssueID != id || event.Version != version {
+				t.Fatalf("event = %+v, want %s %s v%d", event, kind, id, version)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no event for %s %s", kind, id)
+		}
+	}
+	expect(ChangeCreated, "test-old", 1)
+
+	issue := &types.Issue{ID: "test-new", Title: "Watched", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	expect(ChangeCreated, "test-new", 1)
+
+	if err := store.UpdateIssue(ctx, "test-new", map[string]interface{}{"title": "Renamed"}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	expect(ChangeUpdated, "test-new", 2)
+
+	if err := store.SoftDeleteIssue(ctx, "test-new"); err != nil {
+		t.Fatalf("SoftDeleteIssue failed: %v", err)
+	}
+	expect(ChangeDeleted, "test-new", 3)
+
+	gone := &types.Issue{ID: "test-gone", Title: "Removed", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, gone, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	expect(ChangeCreated, "test-gone", 1)
+	if err := store.DeleteIssue(ctx, "test-gone"); err != nil {
+		t.Fatalf("DeleteIssue failed: %v", err)
+	}
+	expect(ChangeDeleted, "test-gone", 2)
+
+	// An ID can be reused after a hard delete
+	if err := store.CreateIssue(ctx, &types.Issue{ID: "test-gone", Title: "Back", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	expect(ChangeCreated, "test-gone", 1)
+
+	// Later polls re-read recent rows but don't repeat their events
+	select {
+	case event := <-events:
+		t.Errorf("unexpected event %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	stop()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("expected no more events after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Watch channel not closed after cancel")
+	}
+}
@@ -17,6 +17,9 @@ import (
 	"github.com/steveyegge/beads/internal/types"
 )
 
+// maxDependencyDepth limits dependency traversal, matching the SQLite backend
+const maxDependencyDepth = 100
+
 // MemoryStorage implements the Storage interface using in-memory data structures
 type MemoryStorage struct {
 	mu sync.RWMutex // Protects all maps
@@ -739,17 +742,39 @@ func (m *MemoryStorage) SearchIssues(ctx context.Context, query string, filter t
 	return results, nil
 }
 
-// AddDependency adds a dependency between issues
+// AddDependency adds a dependency between issues, validating it as the SQLite
+// backend does: both issues must exist (unless the target is an external ref),
+// and self-dependencies and cycles are rejected.
 func (m *MemoryStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Check that both issues exist
-	if _, exists := m.issues[dep.IssueID]; !exists {
+	// Validate dependency type
+	if !dep.Type.IsValid() {
+		return fmt.Errorf("invalid dependency type: %q (must be non-empty string, max 50 chars)", dep.Type)
+	}
+
+	// Check that the source issue exists
+	issue, exists := m.issues[dep.IssueID]
+	if !exists {
 		return fmt.Errorf("issue %s not found", dep.IssueID)
 	}
-	if _, exists := m.issues[dep.DependsOnID]; !exists {
-		return fmt.Errorf("issue %s not found", dep.DependsOnID)
+
+	// External refs (external:<project>:<capability>) don't need target validation
+	isExternalRef := strings.HasPrefix(dep.DependsOnID, "external:")
+	if !isExternalRef {
+		target, exists := m.issues[dep.DependsOnID]
+		if !exists {
+			return fmt.Errorf("dependency target %s not found", dep.DependsOnID)
+		}
+		if dep.IssueID == dep.DependsOnID {
+			return fmt.Errorf("issue cannot depend on itself")
+		}
+		// Child depends on parent, never the other way around
+		if dep.Type == types.DepParentChild && issue.IssueType == types.TypeEpic && target.IssueType != types.TypeEpic {
+			return fmt.Errorf("invalid parent-child dependency: parent (%s) cannot depend on child (%s). Use: bd dep add %s %s --type parent-child",
+				dep.IssueID, dep.DependsOnID, dep.DependsOnID, dep.IssueID)
+		}
 	}
 
 	// Check for duplicates
@@ -759,12 +784,52 @@ func (m *MemoryStorage) AddDependency(ctx context.Context, dep *types.Dependency
 		}
 	}
 
+	// Prevent cycles across all dependency types, as SQLite does; relates-to
+	// links are bidirectional by design
+	if dep.Type != types.DepRelatesTo && m.reachesLocked(dep.DependsOnID, dep.IssueID) {
+		return fmt.Errorf("cannot add dependency: would create a cycle (%s → %s → ... → %s)",
+			dep.IssueID, dep.DependsOnID, dep.IssueID)
+	}
+
+	if dep.CreatedAt.IsZero() {
+		dep.CreatedAt = time.Now()
+	}
+	if dep.CreatedBy == "" {
+		dep.CreatedBy = actor
+	}
+
 	m.dependencies[dep.IssueID] = append(m.dependencies[dep.IssueID], dep)
 	m.dirty[dep.IssueID] = true
+	if !isExternalRef {
+		m.dirty[dep.DependsOnID] = true
+	}
 
 	return nil
 }
 
+// reachesLocked reports whether to can be reached from from by following
+// dependencies, up to maxDependencyDepth levels deep. Caller must hold m.mu.
+func (m *MemoryStorage) reachesLocked(from, to string) bool {
+	seen := map[string]bool{from: true}
+	frontier := []string{from}
+	for depth := 0; depth < maxDependencyDepth && len(frontier) > 0; depth++ {
+		var next []string
+		for _, id := range frontier {
+			for _, dep := range m.dependencies[id] {
+				if dep.DependsOnID == to {
+					return true
+				}
+				if !seen[dep.DependsOnID] {
+					seen[dep.DependsOnID] = true
+					next = append(next, dep.DependsOnID)
+				}
+			}
+		}
+		frontier = next
+	}
+	return false
+}
+
 // RemoveDependency removes a dependency
 func (m *MemoryStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
 	m.mu.Lock()
@@ -1930,6 +1995,11 @@ func (m *MemoryStorage) UnderlyingConn(ctx context.Context) (*sql.Conn, error) {
 	return nil, fmt.Errorf("UnderlyingConn not available in memory storage")
 }
 
+// Capabilities reports no optional features for memory storage (no SQL database)
+func (m *MemoryStorage) Capabilities() storage.Capabilities {
+	return storage.Capabilities{}
+}
+
 // RunInTransaction executes a function within a transaction context.
 // For MemoryStorage, this provides basic atomicity via mutex locking.
 // If the function returns an error, changes are NOT automatically rolled back
@@ -0,0 +1,511 @@
+// Package metrics exports Prometheus metrics for a storage.Storage.
+//
+// NewMetricsStore wraps a store so that every Storage method is counted and
+// timed, labeled by operation and backend. The Collector it registers also
+// reports the connection pool statistics of the underlying database and, for
+// backends that retry transient errors, the number of retries.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"reflect"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// namespace prefixes every metric name.
+const namespace = "beads_storage"
+
+// poolStatter is implemented by backends that expose their pool statistics
+// without handing out the *sql.DB, such as the MariaDB store.
+type poolStatter interface {
+	Stats() sql.DBStats
+}
+
+// retryCounter is implemented by backends that retry transient errors.
+type retryCounter interface {
+	Retries() uint64
+}
+
+// Collector is a prometheus.Collector for one store.
+type Collector struct {
+	s       storage.Storage
+	backend string
+
+	operations *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+
+	openConns    *prometheus.Desc
+	inUseConns   *prometheus.Desc
+	idleConns    *prometheus.Desc
+	waitCount    *prometheus.Desc
+	waitDuration *prometheus.Desc
+	maxOpenConns *prometheus.Desc
+	retries      *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector returns a Collector for s. Operation metrics are only
+// recorded for calls made through the store returned by NewMetricsStore.
+func NewCollector(s storage.Storage) *Collector {
+	backend := Backend(s)
+	constLabels := prometheus.Labels{"backend": backend}
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, nil, constLabels)
+	}
+	return &Collector{
+		s:       s,
+		backend: backend,
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "operations_total",
+			Help:      "Storage operations by operation, backend, and result (ok or error).",
+		}, []string{"operation", "backend", "result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of storage operations by operation and backend.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "backend"}),
+		openConns:    desc("pool_open_connections", "Open connections, in use or idle."),
+		inUseConns:   desc("pool_in_use_connections", "Connections currently in use."),
+		idleConns:    desc("pool_idle_connections", "Idle connections."),
+		waitCount:    desc("pool_wait_total", "Times a caller waited for a connection."),
+		waitDuration: desc("pool_wait_seconds_total", "Total time spent waiting for a connection."),
+		maxOpenConns: desc("pool_max_open_connections", "Maximum number of open connections."),
+		retries:      desc("retries_total", "Operations retried after a transient error."),
+	}
+}
+
+// Backend returns the backend label for s: the name of the package that
+// implements it, such as "mariadb" or "sqlite". Tracing and cache wrappers
+// are looked through.
+func Backend(s storage.Storage) string {
+	t := reflect.TypeOf(storage.Unwrap(s))
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.PkgPath() == "" {
+		return "unknown"
+	}
+	return path.Base(t.PkgPath())
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.operations.Describe(ch)
+	c.duration.Describe(ch)
+	for _, d := range []*prometheus.Desc{c.openConns, c.inUseConns, c.idleConns, c.waitCount, c.waitDuration, c.maxOpenConns, c.retries} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector. Pool metrics are omitted for
+// stores without a connection pool, and the retry count for stores that do
+// not retry.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.operations.Collect(ch)
+	c.duration.Collect(ch)
+
+	if stats, ok := c.poolStats(); ok {
+		ch <- prometheus.MustNewConstMetric(c.openConns, prometheus.GaugeValue, float64(stats.OpenConnections))
+		ch <- prometheus.MustNewConstMetric(c.inUseConns, prometheus.GaugeValue, float64(stats.InUse))
+		ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.Idle))
+		ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+		ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+		ch <- prometheus.MustNewConstMetric(c.maxOpenConns, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	}
+	if r, ok := storage.Unwrap(c.s).(retryCounter); ok {
+		ch <- prometheus.MustNewConstMetric(c.retries, prometheus.CounterValue, float64(r.Retries()))
+	}
+}
+
+// poolStats returns the store's pool statistics, if it has a pool.
+func (c *Collector) poolStats() (sql.DBStats, bool) {
+	if p, ok := storage.Unwrap(c.s).(poolStatter); ok {
+		return p.Stats(), true
+	}
+	if db := c.s.UnderlyingDB(); db != nil {
+		return db.Stats(), true
+	}
+	return sql.DBStats{}, false
+}
+
+// observe records one call of op that started at start and returned *err.
+func (c *Collector) observe(op string, start time.Time, err *error) {
+	result := "ok"
+	if *err != nil {
+		result = "error"
+	}
+	c.operations.WithLabelValues(op, c.backend, result).Inc()
+	c.duration.WithLabelValues(op, c.backend).Observe(time.Since(start).Seconds())
+}
+
+// NewMetricsStore registers a Collector for s with registerer and returns a
+// store that records every Storage call on it. Path and UnderlyingDB are
+// passed through without being recorded, and operations inside
+// RunInTransaction are covered by its metrics but not recorded individually.
+func NewMetricsStore(s storage.Storage, registerer prometheus.Registerer) (storage.Storage, error) {
+	c := NewCollector(s)
+	if err := registerer.Register(c); err != nil {
+		return nil, err
+	}
+	return &metricsStore{s: s, c: c}, nil
+}
+
+// metricsStore implements NewMetricsStore.
+type metricsStore struct {
+	s storage.Storage
+	c *Collector
+}
+
+var _ storage.Storage = (*metricsStore)(nil)
+
+func (m *metricsStore) CreateIssue(ctx context.Context, issue *types.Issue, actor string) (err error) {
+	defer m.c.observe("CreateIssue", time.Now(), &err)
+	return m.s.CreateIssue(ctx, issue, actor)
+}
+
+func (m *metricsStore) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) (err error) {
+	defer m.c.observe("CreateIssues", time.Now(), &err)
+	return m.s.CreateIssues(ctx, issues, actor)
+}
+
+func (m *metricsStore) CreateIssuesWithFullOptions(ctx context.Context, issues []*types.Issue, actor string, opts storage.BatchCreateOptions) (err error) {
+	defer m.c.observe("CreateIssuesWithFullOptions", time.Now(), &err)
+	return m.s.CreateIssuesWithFullOptions(ctx, issues, actor, opts)
+}
+
+func (m *metricsStore) GetIssue(ctx context.Context, id string) (_ *types.Issue, err error) {
+	defer m.c.observe("GetIssue", time.Now(), &err)
+	return m.s.GetIssue(ctx, id)
+}
+
+func (m *metricsStore) GetIssueByExternalRef(ctx context.Context, externalRef string) (_ *types.Issue, err error) {
+	defer m.c.observe("GetIssueByExternalRef", time.Now(), &err)
+	return m.s.GetIssueByExternalRef(ctx, externalRef)
+}
+
+func (m *metricsStore) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) (err error) {
+	defer m.c.observe("UpdateIssue", time.Now(), &err)
+	return m.s.UpdateIssue(ctx, id, updates, actor)
+}
+
+func (m *metricsStore) ClaimIssue(ctx context.Context, id string, actor string) (err error) {
+	defer m.c.observe("ClaimIssue", time.Now(), &err)
+	return m.s.ClaimIssue(ctx, id, actor)
+}
+
+func (m *metricsStore) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) (err error) {
+	defer m.c.observe("CloseIssue", time.Now(), &err)
+	return m.s.CloseIssue(ctx, id, reason, actor, session)
+}
+
+func (m *metricsStore) DeleteIssue(ctx context.Context, id string) (err error) {
+	defer m.c.observe("DeleteIssue", time.Now(), &err)
+	return m.s.DeleteIssue(ctx, id)
+}
+
+func (m *metricsStore) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) (_ []*types.Issue, err error) {
+	defer m.c.observe("SearchIssues", time.Now(), &err)
+	return m.s.SearchIssues(ctx, query, filter)
+}
+
+func (m *metricsStore) AddDependency(ctx context.Context, dep *types.Dependency, actor string) (err error) {
+	defer m.c.observe("AddDependency", time.Now(), &err)
+	return m.s.AddDependency(ctx, dep, actor)
+}
+
+func (m *metricsStore) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) (err error) {
+	defer m.c.observe("RemoveDependency", time.Now(), &err)
+	return m.s.RemoveDependency(ctx, issueID, dependsOnID, actor)
+}
+
+func (m *metricsStore) GetDependencies(ctx context.Context, issueID string) (_ []*types.Issue, err error) {
+	defer m.c.observe("GetDependencies", time.Now(), &err)
+	return m.s.GetDependencies(ctx, issueID)
+}
+
+func (m *metricsStore) GetDependents(ctx context.Context, issueID string) (_ []*types.Issue, err error) {
+	defer m.c.observe("GetDependents", time.Now(), &err)
+	return m.s.GetDependents(ctx, issueID)
+}
+
+func (m *metricsStore) GetDependenciesWithMetadata(ctx context.Context, issueID string) (_ []*types.IssueWithDependencyMetadata, err error) {
+	defer m.c.observe("GetDependenciesWithMetadata", time.Now(), &err)
+	return m.s.GetDependenciesWithMetadata(ctx, issueID)
+}
+
+func (m *metricsStore) GetDependentsWithMetadata(ctx context.Context, issueID string) (_ []*types.IssueWithDependencyMetadata, err error) {
+	defer m.c.observe("GetDependentsWithMetadata", time.Now(), &err)
+	return m.s.GetDependentsWithMetadata(ctx, issueID)
+}
+
+func (m *metricsStore) GetDependencyRecords(ctx context.Context, issueID string) (_ []*types.Dependency, err error) {
+	defer m.c.observe("GetDependencyRecords", time.Now(), &err)
+	return m.s.GetDependencyRecords(ctx, issueID)
+}
+
+func (m *metricsStore) GetAllDependencyRecords(ctx context.Context) (_ map[string][]*types.Dependency, err error) {
+	defer m.c.observe("GetAllDependencyRecords", time.Now(), &err)
+	return m.s.GetAllDependencyRecords(ctx)
+}
+
+func (m *metricsStore) GetDependencyRecordsForIssues(ctx context.Context, issueIDs []string) (_ map[string][]*types.Dependency, err error) {
+	defer m.c.observe("GetDependencyRecordsForIssues", time.Now(), &err)
+	return m.s.GetDependencyRecordsForIssues(ctx, issueIDs)
+}
+
+func (m *metricsStore) GetDependencyCounts(ctx context.Context, issueIDs []string) (_ map[string]*types.DependencyCounts, err error) {
+	defer m.c.observe("GetDependencyCounts", time.Now(), &err)
+	return m.s.GetDependencyCounts(ctx, issueIDs)
+}
+
+func (m *metricsStore) GetDependencyTree(ctx context.Context, issueID string, maxDepth int, showAllPaths bool, reverse bool) (_ []*types.TreeNode, err error) {
+	defer m.c.observe("GetDependencyTree", time.Now(), &err)
+	return m.s.GetDependencyTree(ctx, issueID, maxDepth, showAllPaths, reverse)
+}
+
+func (m *metricsStore) DetectCycles(ctx context.Context) (_ [][]*types.Issue, err error) {
+	defer m.c.observe("DetectCycles", time.Now(), &err)
+	return m.s.DetectCycles(ctx)
+}
+
+func (m *metricsStore) AddLabel(ctx context.Context, issueID, label, actor string) (err error) {
+	defer m.c.observe("AddLabel", time.Now(), &err)
+	return m.s.AddLabel(ctx, issueID, label, actor)
+}
+
+func (m *metricsStore) RemoveLabel(ctx context.Context, issueID, label, actor string) (err error) {
+	defer m.c.observe("RemoveLabel", time.Now(), &err)
+	return m.s.RemoveLabel(ctx, issueID, label, actor)
+}
+
+func (m *metricsStore) GetLabels(ctx context.Context, issueID string) (_ []string, err error) {
+	defer m.c.observe("GetLabels", time.Now(), &err)
+	return m.s.GetLabels(ctx, issueID)
+}
+
+func (m *metricsStore) GetLabelsForIssues(ctx context.Context, issueIDs []string) (_ map[string][]string, err error) {
+	defer m.c.observe("GetLabelsForIssues", time.Now(), &err)
+	return m.s.GetLabelsForIssues(ctx, issueIDs)
+}
+
+func (m *metricsStore) GetIssuesByLabel(ctx context.Context, label string) (_ []*types.Issue, err error) {
+	defer m.c.observe("GetIssuesByLabel", time.Now(), &err)
+	return m.s.GetIssuesByLabel(ctx, label)
+}
+
+func (m *metricsStore) GetReadyWork(ctx context.Context, filter types.WorkFilter) (_ []*types.Issue, err error) {
+	defer m.c.observe("GetReadyWork", time.Now(), &err)
+	return m.s.GetReadyWork(ctx, filter)
+}
+
+func (m *metricsStore) GetBlockedIssues(ctx context.Context, filter types.WorkFilter) (_ []*types.BlockedIssue, err error) {
+	defer m.c.observe("GetBlockedIssues", time.Now(), &err)
+	return m.s.GetBlockedIssues(ctx, filter)
+}
+
+func (m *metricsStore) IsBlocked(ctx context.Context, issueID string) (_ bool, _ []string, err error) {
+	defer m.c.observe("IsBlocked", time.Now(), &err)
+	return m.s.IsBlocked(ctx, issueID)
+}
+
+func (m *metricsStore) GetEpicsEligibleForClosure(ctx context.Context) (_ []*types.EpicStatus, err error) {
+	defer m.c.observe("GetEpicsEligibleForClosure", time.Now(), &err)
+	return m.s.GetEpicsEligibleForClosure(ctx)
+}
+
+func (m *metricsStore) GetStaleIssues(ctx context.Context, filter types.StaleFilter) (_ []*types.Issue, err error) {
+	defer m.c.observe("GetStaleIssues", time.Now(), &err)
+	return m.s.GetStaleIssues(ctx, filter)
+}
+
+func (m *metricsStore) GetNewlyUnblockedByClose(ctx context.Context, closedIssueID string) (_ []*types.Issue, err error) {
+	defer m.c.observe("GetNewlyUnblockedByClose", time.Now(), &err)
+	return m.s.GetNewlyUnblockedByClose(ctx, closedIssueID)
+}
+
+func (m *metricsStore) AddComment(ctx context.Context, issueID, actor, comment string) (err error) {
+	defer m.c.observe("AddComment", time.Now(), &err)
+	return m.s.AddComment(ctx, issueID, actor, comment)
+}
+
+func (m *metricsStore) GetEvents(ctx context.Context, issueID string, limit int) (_ []*types.Event, err error) {
+	defer m.c.observe("GetEvents", time.Now(), &err)
+	return m.s.GetEvents(ctx, issueID, limit)
+}
+
+func (m *metricsStore) GetAllEventsSince(ctx context.Context, sinceID int64) (_ []*types.Event, err error) {
+	defer m.c.observe("GetAllEventsSince", time.Now(), &err)
+	return m.s.GetAllEventsSince(ctx, sinceID)
+}
+
+func (m *metricsStore) AddIssueComment(ctx context.Context, issueID, author, text string) (_ *types.Comment, err error) {
+	defer m.c.observe("AddIssueComment", time.Now(), &err)
+	return m.s.AddIssueComment(ctx, issueID, author, text)
+}
+
+func (m *metricsStore) ImportIssueComment(ctx context.Context, issueID, author, text string, createdAt time.Time) (_ *types.Comment, err error) {
+	defer m.c.observe("ImportIssueComment", time.Now(), &err)
+	return m.s.ImportIssueComment(ctx, issueID, author, text, createdAt)
+}
+
+func (m *metricsStore) GetIssueComments(ctx context.Context, issueID string) (_ []*types.Comment, err error) {
+	defer m.c.observe("GetIssueComments", time.Now(), &err)
+	return m.s.GetIssueComments(ctx, issueID)
+}
+
+func (m *metricsStore) GetCommentsForIssues(ctx context.Context, issueIDs []string) (_ map[string][]*types.Comment, err error) {
+	defer m.c.observe("GetCommentsForIssues", time.Now(), &err)
+	return m.s.GetCommentsForIssues(ctx, issueIDs)
+}
+
+func (m *metricsStore) GetCommentCounts(ctx context.Context, issueIDs []string) (_ map[string]int, err error) {
+	defer m.c.observe("GetCommentCounts", time.Now(), &err)
+	return m.s.GetCommentCounts(ctx, issueIDs)
+}
+
+func (m *metricsStore) GetStatistics(ctx context.Context) (_ *types.Statistics, err error) {
+	defer m.c.observe("GetStatistics", time.Now(), &err)
+	return m.s.GetStatistics(ctx)
+}
+
+func (m *metricsStore) GetMoleculeProgress(ctx context.Context, moleculeID string) (_ *types.MoleculeProgressStats, err error) {
+	defer m.c.observe("GetMoleculeProgress", time.Now(), &err)
+	return m.s.GetMoleculeProgress(ctx, moleculeID)
+}
+
+func (m *metricsStore) GetDirtyIssues(ctx context.Context) (_ []string, err error) {
+	defer m.c.observe("GetDirtyIssues", time.Now(), &err)
+	return m.s.GetDirtyIssues(ctx)
+}
+
+func (m *metricsStore) GetDirtyIssueHash(ctx context.Context, issueID string) (_ string, err error) {
+	defer m.c.observe("GetDirtyIssueHash", time.Now(), &err)
+	return m.s.GetDirtyIssueHash(ctx, issueID)
+}
+
+func (m *metricsStore) ClearDirtyIssuesByID(ctx context.Context, issueIDs []string) (err error) {
+	defer m.c.observe("ClearDirtyIssuesByID", time.Now(), &err)
+	return m.s.ClearDirtyIssuesByID(ctx, issueIDs)
+}
+
+func (m *metricsStore) GetExportHash(ctx context.Context, issueID string) (_ string, err error) {
+	defer m.c.observe("GetExportHash", time.Now(), &err)
+	return m.s.GetExportHash(ctx, issueID)
+}
+
+func (m *metricsStore) SetExportHash(ctx context.Context, issueID, contentHash string) (err error) {
+	defer m.c.observe("SetExportHash", time.Now(), &err)
+	return m.s.SetExportHash(ctx, issueID, contentHash)
+}
+
+func (m *metricsStore) ClearAllExportHashes(ctx context.Context) (err error) {
+	defer m.c.observe("ClearAllExportHashes", time.Now(), &err)
+	return m.s.ClearAllExportHashes(ctx)
+}
+
+func (m *metricsStore) GetJSONLFileHash(ctx context.Context) (_ string, err error) {
+	defer m.c.observe("GetJSONLFileHash", time.Now(), &err)
+	return m.s.GetJSONLFileHash(ctx)
+}
+
+func (m *metricsStore) SetJSONLFileHash(ctx context.Context, fileHash string) (err error) {
+	defer m.c.observe("SetJSONLFileHash", time.Now(), &err)
+	return m.s.SetJSONLFileHash(ctx, fileHash)
+}
+
+func (m *metricsStore) GetNextChildID(ctx context.Context, parentID string) (_ string, err error) {
+	defer m.c.observe("GetNextChildID", time.Now(), &err)
+	return m.s.GetNextChildID(ctx, parentID)
+}
+
+func (m *metricsStore) SetConfig(ctx context.Context, key, value string) (err error) {
+	defer m.c.observe("SetConfig", time.Now(), &err)
+	return m.s.SetConfig(ctx, key, value)
+}
+
+func (m *metricsStore) GetConfig(ctx context.Context, key string) (_ string, err error) {
+	defer m.c.observe("GetConfig", time.Now(), &err)
+	return m.s.GetConfig(ctx, key)
+}
+
+func (m *metricsStore) GetAllConfig(ctx context.Context) (_ map[string]string, err error) {
+	defer m.c.observe("GetAllConfig", time.Now(), &err)
+	return m.s.GetAllConfig(ctx)
+}
+
+func (m *metricsStore) DeleteConfig(ctx context.Context, key string) (err error) {
+	defer m.c.observe("DeleteConfig", time.Now(), &err)
+	return m.s.DeleteConfig(ctx, key)
+}
+
+func (m *metricsStore) GetCustomStatuses(ctx context.Context) (_ []string, err error) {
+	defer m.c.observe("GetCustomStatuses", time.Now(), &err)
+	return m.s.GetCustomStatuses(ctx)
+}
+
+func (m *metricsStore) GetCustomTypes(ctx context.Context) (_ []string, err error) {
+	defer m.c.observe("GetCustomTypes", time.Now(), &err)
+	return m.s.GetCustomTypes(ctx)
+}
+
+func (m *metricsStore) SetMetadata(ctx context.Context, key, value string) (err error) {
+	defer m.c.observe("SetMetadata", time.Now(), &err)
+	return m.s.SetMetadata(ctx, key, value)
+}
+
+func (m *metricsStore) GetMetadata(ctx context.Context, key string) (_ string, err error) {
+	defer m.c.observe("GetMetadata", time.Now(), &err)
+	return m.s.GetMetadata(ctx, key)
+}
+
+func (m *metricsStore) UpdateIssueID(ctx context.Context, oldID, newID string, issue *types.Issue, actor string) (err error) {
+	defer m.c.observe("UpdateIssueID", time.Now(), &err)
+	return m.s.UpdateIssueID(ctx, oldID, newID, issue, actor)
+}
+
+func (m *metricsStore) RenameDependencyPrefix(ctx context.Context, oldPrefix, newPrefix string) (err error) {
+	defer m.c.observe("RenameDependencyPrefix", time.Now(), &err)
+	return m.s.RenameDependencyPrefix(ctx, oldPrefix, newPrefix)
+}
+
+func (m *metricsStore) RenameCounterPrefix(ctx context.Context, oldPrefix, newPrefix string) (err error) {
+	defer m.c.observe("RenameCounterPrefix", time.Now(), &err)
+	return m.s.RenameCounterPrefix(ctx, oldPrefix, newPrefix)
+}
+
+func (m *metricsStore) RunInTransaction(ctx context.Context, fn func(tx storage.Transaction) error) (err error) {
+	defer m.c.observe("RunInTransaction", time.Now(), &err)
+	return m.s.RunInTransaction(ctx, fn)
+}
+
+func (m *metricsStore) Close() (err error) {
+	defer m.c.observe("Close", time.Now(), &err)
+	return m.s.Close()
+}
+
+func (m *metricsStore) Path() string {
+	return m.s.Path()
+}
+
+func (m *metricsStore) UnderlyingDB() *sql.DB {
+	return m.s.UnderlyingDB()
+}
+
+func (m *metricsStore) UnderlyingConn(ctx context.Context) (_ *sql.Conn, err error) {
+	defer m.c.observe("UnderlyingConn", time.Now(), &err)
+	return m.s.UnderlyingConn(ctx)
+}
+
+func (m *metricsStore) Capabilities() storage.Capabilities {
+	return m.s.Capabilities()
+}
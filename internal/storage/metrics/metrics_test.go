@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/memory"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestNewMetricsStoreCountsOperations(t *testing.T) {
+	ctx := context.Background()
+	reg := prometheus.NewPedanticRegistry()
+	store, err := NewMetricsStore(memory.New(""), reg)
+	if err != nil {
+		t.Fatalf("NewMetricsStore failed: %v", err)
+	}
+	c := store.(*metricsStore).c
+
+	if err := store.SetConfig(ctx, "issue_prefix", "test"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	for _, id := range []string{"test-a", "test-b"} {
+		issue := &types.Issue{ID: id, Title: id, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+		}
+	}
+	if _, err := store.GetIssue(ctx, "test-a"); err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, "test-missing", map[string]interface{}{"title": "x"}, "tester"); err == nil {
+		t.Fatal("UpdateIssue(missing) should fail")
+	}
+
+	tests := []struct {
+		op, result string
+		want       float64
+	}{
+		{"SetConfig", "ok", 1},
+		{"CreateIssue", "ok", 2},
+		{"GetIssue", "ok", 1},
+		{"UpdateIssue", "error", 1},
+	}
+	for _, tt := range tests {
+		got := testutil.ToFloat64(c.operations.WithLabelValues(tt.op, "memory", tt.result))
+		if got != tt.want {
+			t.Errorf("operations_total{operation=%q,result=%q} = %v, want %v", tt.op, tt.result, got, tt.want)
+		}
+	}
+	if n := testutil.CollectAndCount(c.duration); n != 4 {
+		t.Errorf("duration histogram has %d series, want 4 (one per operation)", n)
+	}
+
+	// Path is passed through without being recorded
+	_ = store.Path()
+	if n := testutil.CollectAndCount(c.operations); n != 4 {
+		t.Errorf("operations_total has %d series, want 4", n)
+	}
+
+	// The memory backend has no pool and does not retry
+	if n, err := testutil.GatherAndCount(reg, "beads_storage_pool_open_connections", "beads_storage_retries_total"); err != nil || n != 0 {
+		t.Errorf("pool and retry series = %d, %v; want none", n, err)
+	}
+
+	if _, err := NewMetricsStore(memory.New(""), reg); err == nil {
+		t.Error("registering a second store with the same registry should fail")
+	}
+}
+
+// pooledStorage reports fixed pool statistics and retries.
+type pooledStorage struct {
+	storage.Storage
+}
+
+func (pooledStorage) Stats() sql.DBStats {
+	return sql.DBStats{MaxOpenConnections: 10, OpenConnections: 3, InUse: 2, Idle: 1, WaitCount: 5}
+}
+
+func (pooledStorage) Retries() uint64 { return 7 }
+
+func (pooledStorage) Close() error { return errors.New("close failed") }
+
+func TestCollectorPoolAndRetries(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	store, err := NewMetricsStore(pooledStorage{Storage: memory.New("")}, reg)
+	if err != nil {
+		t.Fatalf("NewMetricsStore failed: %v", err)
+	}
+	if err := store.Close(); err == nil {
+		t.Fatal("Close should fail")
+	}
+
+	want := `
+# HELP beads_storage_operations_total Storage operations by operation, backend, and result (ok or error).
+# TYPE beads_storage_operations_total counter
+beads_storage_operations_total{backend="metrics",operation="Close",result="error"} 1
+# HELP beads_storage_pool_in_use_connections Connections currently in use.
+# TYPE beads_storage_pool_in_use_connections gauge
+beads_storage_pool_in_use_connections{backend="metrics"} 2
+# HELP beads_storage_pool_open_connections Open connections, in use or idle.
+# TYPE beads_storage_pool_open_connections gauge
+beads_storage_pool_open_connections{backend="metrics"} 3
+# HELP beads_storage_pool_wait_total Times a caller waited for a connection.
+# TYPE beads_storage_pool_wait_total counter
+beads_storage_pool_wait_total{backend="metrics"} 5
+# HELP beads_storage_retries_total Operations retried after a transient error.
+# TYPE beads_storage_retries_total counter
+beads_storage_retries_total{backend="metrics"} 7
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want),
+		"beads_storage_operations_total", "beads_storage_pool_in_use_connections", "beads_storage_pool_open_connections",
+		"beads_storage_pool_wait_total", "beads_storage_retries_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBackend(t *testing.T) {
+	if got := Backend(memory.New("")); got != "memory" {
+		t.Errorf("Backend(memory) = %q, want memory", got)
+	}
+}
@@ -0,0 +1,20 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/storagetest"
+)
+
+// newConformanceStore returns an empty store for the storagetest suites. The
+// suites are skipped unless BEADS_POSTGRES_TEST_DSN is set.
+func newConformanceStore(t *testing.T) storage.Storage {
+	store, cleanup := setupTestStore(t)
+	t.Cleanup(cleanup)
+	return store
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.RunConformance(t, newConformanceStore)
+}
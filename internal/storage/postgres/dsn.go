@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// buildDSN builds the pgx connection URL for cfg.
+func buildDSN(cfg *Config) string {
+	query := url.Values{}
+	if cfg.SSLMode != "" {
+		query.Set("sslmode", cfg.SSLMode)
+	}
+	if cfg.ConnectTimeout > 0 {
+		// connect_timeout is in whole seconds; round up so it never becomes 0 (no timeout)
+		secs := int((cfg.ConnectTimeout + time.Second - 1) / time.Second)
+		query.Set("connect_timeout", strconv.Itoa(secs))
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Path:     "/" + cfg.Database,
+		RawQuery: query.Encode(),
+	}
+	if cfg.Password == "" {
+		u.User = url.User(cfg.User)
+	}
+	return u.String()
+}
+
+// newConnector parses dsn and returns a connector whose connections accept
+// the ? placeholders used throughout the SQL backends (see rebind).
+//
+// Each new connection sets its search_path to schema, so unqualified table
+// names resolve to the beads tables. This is done with SET rather than a
+// startup parameter, which connection poolers such as PgBouncer reject.
+func newConnector(dsn, schema string) (driver.Connector, error) {
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Postgres connection config: %w", err)
+	}
+	setSearchPath := stdlib.OptionAfterConnect(func(ctx context.Context, conn *pgx.Conn) error {
+		if _, err := conn.Exec(ctx, "SET search_path TO "+quoteIdentifier(schema)); err != nil {
+			return fmt.Errorf("failed to set search_path to %s: %w", schema, err)
+		}
+		return nil
+	})
+	return &rebindConnector{Connector: stdlib.GetConnector(*connConfig, setSearchPath)}, nil
+}
+
+// rebindConnector wraps the pgx connector, rewriting ? placeholders to
+// Postgres's positional $1, $2, ... form before queries reach the server.
+// This keeps the query text shared with the MariaDB backend.
+type rebindConnector struct {
+	driver.Connector
+}
+
+func (c *rebindConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &rebindConn{Conn: conn.(*stdlib.Conn)}, nil
+}
+
+// rebindConn is a pgx connection that rebinds placeholders in every query.
+// All other driver interfaces are promoted from the embedded connection.
+type rebindConn struct {
+	*stdlib.Conn
+}
+
+func (c *rebindConn) Prepare(query string) (driver.Stmt, error) {
+	return c.Conn.Prepare(rebind(query))
+}
+
+func (c *rebindConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.Conn.PrepareContext(ctx, rebind(query))
+}
+
+func (c *rebindConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.Conn.ExecContext(ctx, rebind(query), args)
+}
+
+func (c *rebindConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.Conn.QueryContext(ctx, rebind(query), args)
+}
+
+// rebind replaces each ? placeholder in query with $1, $2, ... in order.
+// Question marks inside string literals, quoted identifiers, and comments
+// are left alone.
+func rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			// Copy through the closing quote; a doubled quote is an escaped
+			// quote and simply reopens the literal on the next iteration.
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+2])
+			i += end + 1
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+1])
+			i += end
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+4])
+			i += end + 3
+		case c == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestBuildDSN(t *testing.T) {
+	cfg := &Config{
+		Host: "db.example.com", Port: 5433, User: "beads", Password: "s3cr3t@pass",
+		Database: "issues", SSLMode: "require", ConnectTimeout: 1500 * time.Millisecond,
+	}
+
+	dsn := buildDSN(cfg)
+	parsed, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("ParseConfig(%q) failed: %v", dsn, err)
+	}
+	if parsed.Host != "db.example.com" || parsed.Port != 5433 {
+		t.Errorf("address = %s:%d, want db.example.com:5433", parsed.Host, parsed.Port)
+	}
+	if parsed.User != "beads" || parsed.Password != "s3cr3t@pass" || parsed.Database != "issues" {
+		t.Errorf("credentials round-trip failed: user=%q password=%q database=%q", parsed.User, parsed.Password, parsed.Database)
+	}
+	if parsed.TLSConfig == nil {
+		t.Error("sslmode=require should enable TLS")
+	}
+	// Sub-second timeouts round up rather than disabling the timeout
+	if parsed.ConnectTimeout != 2*time.Second {
+		t.Errorf("ConnectTimeout = %v, want 2s", parsed.ConnectTimeout)
+	}
+}
+
+func TestBuildDSNNoPassword(t *testing.T) {
+	dsn := buildDSN(&Config{Host: "127.0.0.1", Port: 5432, User: "postgres", Database: "beads"})
+	// No password, and unset options are left to the driver
+	if want := "postgres://postgres@127.0.0.1:5432/beads"; dsn != want {
+		t.Errorf("buildDSN = %q, want %q", dsn, want)
+	}
+}
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"SELECT 1", "SELECT 1"},
+		{"SELECT * FROM issues WHERE id = ?", "SELECT * FROM issues WHERE id = $1"},
+		{"INSERT INTO labels (issue_id, label) VALUES (?, ?)", "INSERT INTO labels (issue_id, label) VALUES ($1, $2)"},
+		{"SELECT '?' FROM t WHERE a = ?", "SELECT '?' FROM t WHERE a = $1"},
+		{"SELECT 'it''s ?' WHERE a = ?", "SELECT 'it''s ?' WHERE a = $1"},
+		{`SELECT "odd?col" FROM t WHERE a = ?`, `SELECT "odd?col" FROM t WHERE a = $1`},
+		{"SELECT a -- why?\nFROM t WHERE a = ?", "SELECT a -- why?\nFROM t WHERE a = $1"},
+		{"SELECT /* ? */ a FROM t WHERE a = ?", "SELECT /* ? */ a FROM t WHERE a = $1"},
+		{"SELECT 'unterminated ?", "SELECT 'unterminated ?"},
+	}
+	for _, tt := range tests {
+		if got := rebind(tt.in); got != tt.want {
+			t.Errorf("rebind(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// HealthCheck verifies the server is reachable by running SELECT 1.
+// Transient connection errors are retried until ctx expires.
+// Returns ErrStoreClosed if the store has been closed.
+func (s *PostgresStore) HealthCheck(ctx context.Context) error {
+	if s.IsClosed() {
+		return ErrStoreClosed
+	}
+	s.mu.RLock()
+	db := s.db
+	s.mu.RUnlock()
+	if db == nil {
+		return ErrStoreClosed
+	}
+
+	err := s.withRetry(ctx, func() error {
+		var one int
+		return db.QueryRowContext(ctx, "SELECT 1").Scan(&one)
+	})
+	if err != nil {
+		return fmt.Errorf("Postgres health check failed for database %s: %w", s.dbName, err)
+	}
+	return nil
+}
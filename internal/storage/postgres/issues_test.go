@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func TestIssueLifecycle(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	issue := &types.Issue{
+		Title:     "Lifecycle issue",
+		Status:    types.StatusOpen,
+		Priority:  2,
+		IssueType: types.TypeTask,
+	}
+	if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if issue.ID == "" {
+		t.Fatal("expected generated ID")
+	}
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"title": "Renamed"}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := store.AddLabel(ctx, issue.ID, "backend", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := store.ClaimIssue(ctx, issue.ID, "alice"); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	if err := store.CloseIssue(ctx, issue.ID, "done", "tester", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got.Title != "Renamed" || got.Assignee != "alice" || got.Status != types.StatusClosed {
+		t.Errorf("unexpected issue state: title=%q assignee=%q status=%q", got.Title, got.Assignee, got.Status)
+	}
+	if len(got.Labels) != 1 || got.Labels[0] != "backend" {
+		t.Errorf("Labels = %v, want [backend]", got.Labels)
+	}
+
+	if err := store.DeleteIssue(ctx, issue.ID); err != nil {
+		t.Fatalf("DeleteIssue failed: %v", err)
+	}
+	if err := store.DeleteIssue(ctx, issue.ID); err == nil {
+		t.Error("expected error deleting missing issue")
+	}
+}
+
+func TestReadyWorkRespectsBlockers(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	blocker := &types.Issue{Title: "Blocker", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	blocked := &types.Issue{Title: "Blocked", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{blocker, blocked} {
+		if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	dep := &types.Dependency{IssueID: blocked.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+	if err := store.AddDependency(ctx, dep, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != blocker.ID {
+		ids := make([]string, len(ready))
+		for i, issue := range ready {
+			ids[i] = issue.ID
+		}
+		t.Errorf("GetReadyWork = %v, want [%s]", ids, blocker.ID)
+	}
+}
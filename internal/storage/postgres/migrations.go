@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Migration represents a single schema migration for Postgres.
+type Migration struct {
+	Name string
+	Func func(context.Context, *sql.DB) error
+}
+
+// migrationsList is the ordered list of all Postgres schema migrations.
+// Each migration must be idempotent - safe to run multiple times.
+// New migrations should be appended to the end of this list.
+var migrationsList = []Migration{
+	{Name: "wisp_type_column", Func: migrateWispTypeColumn},
+	{Name: "spec_id_column", Func: migrateSpecIDColumn},
+}
+
+// schemaMigrationsTable records which migrations have been applied, so
+// RunMigrations can skip them.
+const schemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    name VARCHAR(255) PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`
+
+// errUndefinedTable is the SQLSTATE for a missing table.
+const errUndefinedTable = "42P01"
+
+// RunMigrations executes all registered Postgres migrations in order.
+// Migrations recorded in schema_migrations are skipped. The rest use
+// IF NOT EXISTS, so they are safe on databases that already have the change.
+// Cancelling ctx aborts the running statement and skips the remaining migrations.
+func RunMigrations(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, schemaMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := AppliedMigrations(ctx, db)
+	if err != nil {
+		return err
+	}
+	done := make(map[string]bool, len(applied))
+	for _, name := range applied {
+		done[name] = true
+	}
+
+	for _, m := range migrationsList {
+		if done[m.Name] {
+			continue
+		}
+		if err := m.Func(ctx, db); err != nil {
+			return fmt.Errorf("postgres migration %q failed: %w", m.Name, err)
+		}
+		if _, err := db.ExecContext(ctx, "INSERT INTO schema_migrations (name) VALUES (?) ON CONFLICT DO NOTHING", m.Name); err != nil {
+			return fmt.Errorf("failed to record migration %q: %w", m.Name, err)
+		}
+	}
+	return nil
+}
+
+// AppliedMigrations returns the names of migrations recorded in
+// schema_migrations, in the order they were applied. It returns an empty
+// list if the table does not exist yet.
+func AppliedMigrations(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM schema_migrations ORDER BY applied_at, name")
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == errUndefinedTable {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan migration name: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// ListMigrations returns the names of all registered migrations.
+func ListMigrations() []string {
+	names := make([]string, len(migrationsList))
+	for i, m := range migrationsList {
+		names[i] = m.Name
+	}
+	return names
+}
+
+// migrateWispTypeColumn adds the wisp_type column if it doesn't exist
+func migrateWispTypeColumn(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, "ALTER TABLE issues ADD COLUMN IF NOT EXISTS wisp_type VARCHAR(32) DEFAULT ''"); err != nil {
+		return fmt.Errorf("adding wisp_type column: %w", err)
+	}
+	return nil
+}
+
+// migrateSpecIDColumn adds the spec_id column and its index if they don't exist
+func migrateSpecIDColumn(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, "ALTER TABLE issues ADD COLUMN IF NOT EXISTS spec_id VARCHAR(1024)"); err != nil {
+		return fmt.Errorf("adding spec_id column: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, "CREATE INDEX IF NOT EXISTS idx_issues_spec_id ON issues (spec_id)"); err != nil {
+		return fmt.Errorf("creating spec_id index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package postgres
+
+import "testing"
+
+func TestRunMigrationsRecordsApplied(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	db := store.UnderlyingDB()
+
+	// New already ran migrations, so every migration should be recorded
+	applied, err := AppliedMigrations(ctx, db)
+	if err != nil {
+		t.Fatalf("AppliedMigrations failed: %v", err)
+	}
+	if len(applied) != len(migrationsList) {
+		t.Fatalf("AppliedMigrations = %v, want all of %v", applied, ListMigrations())
+	}
+
+	// Running again is a no-op
+	if err := RunMigrations(ctx, db); err != nil {
+		t.Fatalf("second RunMigrations failed: %v", err)
+	}
+}
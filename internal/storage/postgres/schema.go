@@ -0,0 +1,332 @@
+package postgres
+
+// schema defines the Postgres database schema. It mirrors the MariaDB schema,
+// with BOOLEAN flag columns, TIMESTAMPTZ timestamps, and indexes declared separately.
+const schema = `
+-- Issues table
+CREATE TABLE IF NOT EXISTS issues (
+    id VARCHAR(255) PRIMARY KEY,
+    content_hash VARCHAR(64),
+    title VARCHAR(500) NOT NULL,
+    description TEXT NOT NULL,
+    design TEXT NOT NULL,
+    acceptance_criteria TEXT NOT NULL,
+    notes TEXT NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'open',
+    priority INT NOT NULL DEFAULT 2,
+    issue_type VARCHAR(32) NOT NULL DEFAULT 'task',
+    assignee VARCHAR(255),
+    estimated_minutes INT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(255) DEFAULT '',
+    owner VARCHAR(255) DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMPTZ,
+    closed_by_session VARCHAR(255) DEFAULT '',
+    external_ref VARCHAR(255),
+    spec_id VARCHAR(1024),
+    compaction_level INT DEFAULT 0,
+    compacted_at TIMESTAMPTZ,
+    compacted_at_commit VARCHAR(64),
+    original_size INT,
+    deleted_at TIMESTAMPTZ,
+    deleted_by VARCHAR(255) DEFAULT '',
+    delete_reason TEXT DEFAULT '',
+    original_type VARCHAR(32) DEFAULT '',
+    -- Messaging fields
+    sender VARCHAR(255) DEFAULT '',
+    ephemeral BOOLEAN DEFAULT FALSE,
+    -- Wisp classification for TTL-based compaction (gt-9br)
+    wisp_type VARCHAR(32) DEFAULT '',
+    -- Pinned field
+    pinned BOOLEAN DEFAULT FALSE,
+    -- Template field
+    is_template BOOLEAN DEFAULT FALSE,
+    -- Work economics field (HOP Decision 006)
+    crystallizes BOOLEAN DEFAULT FALSE,
+    -- Molecule type field
+    mol_type VARCHAR(32) DEFAULT '',
+    -- Work type field (Decision 006: mutex vs open_competition)
+    work_type VARCHAR(32) DEFAULT 'mutex',
+    -- HOP quality score field (0.0-1.0)
+    quality_score DOUBLE PRECISION,
+    -- Federation source system field
+    source_system VARCHAR(255) DEFAULT '',
+    -- Custom metadata field (GH#1406)
+    metadata JSON DEFAULT '{}',
+    -- Source repo for multi-repo
+    source_repo VARCHAR(512) DEFAULT '',
+    -- Close reason
+    close_reason TEXT DEFAULT '',
+    -- Event fields
+    event_kind VARCHAR(32) DEFAULT '',
+    actor VARCHAR(255) DEFAULT '',
+    target VARCHAR(255) DEFAULT '',
+    payload TEXT DEFAULT '',
+    -- Gate fields
+    await_type VARCHAR(32) DEFAULT '',
+    await_id VARCHAR(255) DEFAULT '',
+    timeout_ns BIGINT DEFAULT 0,
+    waiters TEXT DEFAULT '',
+    -- Agent fields
+    hook_bead VARCHAR(255) DEFAULT '',
+    role_bead VARCHAR(255) DEFAULT '',
+    agent_state VARCHAR(32) DEFAULT '',
+    last_activity TIMESTAMPTZ,
+    role_type VARCHAR(32) DEFAULT '',
+    rig VARCHAR(255) DEFAULT '',
+    -- Time-based scheduling fields
+    due_at TIMESTAMPTZ,
+    defer_until TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_issues_status ON issues (status);
+CREATE INDEX IF NOT EXISTS idx_issues_priority ON issues (priority);
+CREATE INDEX IF NOT EXISTS idx_issues_issue_type ON issues (issue_type);
+CREATE INDEX IF NOT EXISTS idx_issues_assignee ON issues (assignee);
+CREATE INDEX IF NOT EXISTS idx_issues_created_at ON issues (created_at);
+CREATE INDEX IF NOT EXISTS idx_issues_external_ref ON issues (external_ref);
+
+-- Dependencies table (edge schema)
+-- Note: No FK on depends_on_id to allow external references (external:<rig>:<id>).
+-- See SQLite migration 025_remove_depends_on_fk.go for design context.
+CREATE TABLE IF NOT EXISTS dependencies (
+    issue_id VARCHAR(255) NOT NULL,
+    depends_on_id VARCHAR(255) NOT NULL,
+    type VARCHAR(32) NOT NULL DEFAULT 'blocks',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by VARCHAR(255) NOT NULL,
+    metadata JSON DEFAULT '{}',
+    thread_id VARCHAR(255) DEFAULT '',
+    PRIMARY KEY (issue_id, depends_on_id),
+    CONSTRAINT fk_dep_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_dependencies_issue ON dependencies (issue_id);
+CREATE INDEX IF NOT EXISTS idx_dependencies_depends_on ON dependencies (depends_on_id);
+CREATE INDEX IF NOT EXISTS idx_dependencies_depends_on_type ON dependencies (depends_on_id, type);
+CREATE INDEX IF NOT EXISTS idx_dependencies_thread ON dependencies (thread_id);
+
+-- Labels table
+CREATE TABLE IF NOT EXISTS labels (
+    issue_id VARCHAR(255) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    PRIMARY KEY (issue_id, label),
+    CONSTRAINT fk_labels_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_labels_label ON labels (label);
+
+-- Comments table
+CREATE TABLE IF NOT EXISTS comments (
+    id BIGSERIAL PRIMARY KEY,
+    issue_id VARCHAR(255) NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_comments_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_comments_issue ON comments (issue_id);
+CREATE INDEX IF NOT EXISTS idx_comments_created_at ON comments (created_at);
+
+-- Events table (audit trail)
+CREATE TABLE IF NOT EXISTS events (
+    id BIGSERIAL PRIMARY KEY,
+    issue_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(32) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    comment TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_events_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_events_issue ON events (issue_id);
+CREATE INDEX IF NOT EXISTS idx_events_created_at ON events (created_at);
+
+-- Config table
+CREATE TABLE IF NOT EXISTS config (
+    key VARCHAR(255) PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+-- Metadata table
+CREATE TABLE IF NOT EXISTS metadata (
+    key VARCHAR(255) PRIMARY KEY,
+    value TEXT NOT NULL
+);
+
+-- Dirty issues table (for incremental export)
+CREATE TABLE IF NOT EXISTS dirty_issues (
+    issue_id VARCHAR(255) PRIMARY KEY,
+    marked_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_dirty_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_dirty_issues_marked_at ON dirty_issues (marked_at);
+
+-- Export hashes table
+CREATE TABLE IF NOT EXISTS export_hashes (
+    issue_id VARCHAR(255) PRIMARY KEY,
+    content_hash VARCHAR(64) NOT NULL,
+    exported_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_export_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- Child counters table
+CREATE TABLE IF NOT EXISTS child_counters (
+    parent_id VARCHAR(255) PRIMARY KEY,
+    last_child INT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_counter_parent FOREIGN KEY (parent_id) REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- Issue snapshots table (for compaction)
+CREATE TABLE IF NOT EXISTS issue_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    issue_id VARCHAR(255) NOT NULL,
+    snapshot_time TIMESTAMPTZ NOT NULL,
+    compaction_level INT NOT NULL,
+    original_size INT NOT NULL,
+    compressed_size INT NOT NULL,
+    original_content TEXT NOT NULL,
+    archived_events TEXT,
+    CONSTRAINT fk_snapshots_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_snapshots_issue ON issue_snapshots (issue_id);
+CREATE INDEX IF NOT EXISTS idx_snapshots_level ON issue_snapshots (compaction_level);
+
+-- Compaction snapshots table
+CREATE TABLE IF NOT EXISTS compaction_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    issue_id VARCHAR(255) NOT NULL,
+    compaction_level INT NOT NULL,
+    snapshot_json BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_comp_snap_issue FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_comp_snap_issue ON compaction_snapshots (issue_id, compaction_level, created_at DESC);
+
+-- Repository mtimes table (for multi-repo)
+CREATE TABLE IF NOT EXISTS repo_mtimes (
+    repo_path VARCHAR(512) PRIMARY KEY,
+    jsonl_path VARCHAR(512) NOT NULL,
+    mtime_ns BIGINT NOT NULL,
+    last_checked TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_repo_mtimes_checked ON repo_mtimes (last_checked);
+
+-- Routes table (prefix-to-path routing configuration)
+CREATE TABLE IF NOT EXISTS routes (
+    prefix VARCHAR(32) PRIMARY KEY,
+    path VARCHAR(512) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Interactions table (agent audit log)
+CREATE TABLE IF NOT EXISTS interactions (
+    id VARCHAR(32) PRIMARY KEY,
+    kind VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    actor VARCHAR(255),
+    issue_id VARCHAR(255),
+    model VARCHAR(255),
+    prompt TEXT,
+    response TEXT,
+    error TEXT,
+    tool_name VARCHAR(255),
+    exit_code INT,
+    parent_id VARCHAR(32),
+    label VARCHAR(64),
+    reason TEXT,
+    extra JSON
+);
+CREATE INDEX IF NOT EXISTS idx_interactions_kind ON interactions (kind);
+CREATE INDEX IF NOT EXISTS idx_interactions_created_at ON interactions (created_at);
+CREATE INDEX IF NOT EXISTS idx_interactions_issue_id ON interactions (issue_id);
+CREATE INDEX IF NOT EXISTS idx_interactions_parent_id ON interactions (parent_id);
+`
+
+// tableNames lists every table created by schema, in creation order,
+// followed by schema_migrations (created by RunMigrations).
+var tableNames = []string{
+	"issues", "dependencies", "labels", "comments", "events", "config", "metadata",
+	"dirty_issues", "export_hashes", "child_counters", "issue_snapshots",
+	"compaction_snapshots", "repo_mtimes", "routes", "interactions",
+	"schema_migrations",
+}
+
+// defaultConfig contains the default configuration values
+const defaultConfig = `
+INSERT INTO config (key, value) VALUES
+    ('compaction_enabled', 'false'),
+    ('compact_tier1_days', '30'),
+    ('compact_tier1_dep_levels', '2'),
+    ('compact_tier2_days', '90'),
+    ('compact_tier2_dep_levels', '5'),
+    ('compact_tier2_commits', '100'),
+    ('compact_model', 'claude-haiku-4-5-20251001'),
+    ('compact_batch_size', '50'),
+    ('compact_parallel_workers', '5'),
+    ('auto_compact_enabled', 'false')
+ON CONFLICT DO NOTHING;
+`
+
+// readyIssuesView is the view for ready work.
+// Uses LEFT JOIN instead of NOT EXISTS to avoid potential performance issues.
+const readyIssuesView = `
+CREATE OR REPLACE VIEW ready_issues AS
+WITH RECURSIVE
+  blocked_directly AS (
+    SELECT DISTINCT d.issue_id
+    FROM dependencies d
+    WHERE d.type = 'blocks'
+      AND EXISTS (
+        SELECT 1 FROM issues blocker
+        WHERE blocker.id = d.depends_on_id
+          AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+      )
+  ),
+  blocked_transitively AS (
+    SELECT issue_id, 0 as depth
+    FROM blocked_directly
+    UNION ALL
+    SELECT d.issue_id, bt.depth + 1
+    FROM blocked_transitively bt
+    JOIN dependencies d ON d.depends_on_id = bt.issue_id
+    WHERE d.type = 'parent-child'
+      AND bt.depth < 50
+  )
+SELECT i.*
+FROM issues i
+LEFT JOIN blocked_transitively bt ON bt.issue_id = i.id
+WHERE i.status = 'open'
+  AND i.ephemeral IS NOT TRUE
+  AND bt.issue_id IS NULL;
+`
+
+// blockedIssuesView is the view for blocked issues.
+// Uses subquery instead of three-table join for better performance.
+const blockedIssuesView = `
+CREATE OR REPLACE VIEW blocked_issues AS
+SELECT
+    i.*,
+    (SELECT COUNT(*)
+     FROM dependencies d
+     WHERE d.issue_id = i.id
+       AND d.type = 'blocks'
+       AND EXISTS (
+         SELECT 1 FROM issues blocker
+         WHERE blocker.id = d.depends_on_id
+           AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+       )
+    ) as blocked_by_count
+FROM issues i
+WHERE i.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+  AND EXISTS (
+    SELECT 1 FROM dependencies d
+    WHERE d.issue_id = i.id
+      AND d.type = 'blocks'
+      AND EXISTS (
+        SELECT 1 FROM issues blocker
+        WHERE blocker.id = d.depends_on_id
+          AND blocker.status IN ('open', 'in_progress', 'blocked', 'deferred', 'hooked')
+      )
+  );
+`
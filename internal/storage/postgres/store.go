@@ -0,0 +1,429 @@
+// Package postgres implements the storage interface using PostgreSQL.
+//
+// The backend mirrors the MariaDB backend: it connects to a database server,
+// supports multiple writers, and keeps no local files. Queries are written with
+// ? placeholders and rebound to Postgres's $n form by the connection wrapper in
+// dsn.go, so query text stays close to the other SQL backends.
+//
+// Each store lives in one Postgres schema (Config.Schema) of an existing
+// database, so several beads projects can share a database.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlutil"
+)
+
+// ErrStoreClosed is returned when an operation is attempted on a closed store.
+var ErrStoreClosed = storage.ErrStoreClosed
+
+// PostgresStore implements the Storage interface using PostgreSQL
+type PostgresStore struct {
+	db       *sql.DB
+	dbName   string       // Database name
+	schema   string       // Schema holding the beads tables
+	closed   atomic.Bool  // Tracks whether Close() has been called
+	connStr  string       // Connection string for reconnection
+	mu       sync.RWMutex // Protects concurrent access
+	readOnly bool         // True if opened in read-only mode
+}
+
+// Config holds Postgres database configuration
+type Config struct {
+	Host     string // Server host (default: 127.0.0.1)
+	Port     int    // Server port (default: 5432)
+	User     string // Postgres user (default: postgres)
+	Password string // Postgres password (default: empty, can be set via BEADS_POSTGRES_PASSWORD)
+	Database string // Database name (default: beads); must already exist
+	Schema   string // Schema for the beads tables (default: public); created if missing
+	SSLMode  string // libpq sslmode, e.g. "disable" or "require" (default: driver default)
+	ReadOnly bool   // Open in read-only mode (skip schema init)
+
+	// ConnectTimeout bounds dialing the server, so an unreachable or
+	// firewalled server fails fast (default: 10s, rounded up to whole seconds)
+	ConnectTimeout time.Duration
+
+	// Connection pool options
+	MaxOpenConns    int           // Maximum open connections (default: 10)
+	MaxIdleConns    int           // Maximum idle connections (default: 5, capped at MaxOpenConns)
+	ConnMaxLifetime time.Duration // Maximum connection lifetime (default: 5m)
+}
+
+// DefaultPort is the default Postgres port
+const DefaultPort = 5432
+
+// DefaultSchema is the default for Config.Schema
+const DefaultSchema = "public"
+
+// DefaultConnectTimeout is the default for Config.ConnectTimeout
+const DefaultConnectTimeout = 10 * time.Second
+
+// Default connection pool settings, matching the MariaDB backend.
+const (
+	DefaultMaxOpenConns    = 10
+	DefaultMaxIdleConns    = 5
+	DefaultConnMaxLifetime = 5 * time.Minute
+)
+
+// Server retry configuration. We retry transient connection errors (stale
+// pool connections, brief network issues, server restarts).
+const serverRetryMaxElapsed = 30 * time.Second
+
+func newServerRetryBackoff() backoff.BackOff {
+	bo := backoff.NewExponentialBackOff()
+	bo.MaxElapsedTime = serverRetryMaxElapsed
+	return bo
+}
+
+// Postgres SQLSTATE codes for lock conflicts. Postgres aborts the whole
+// transaction on any of these, so the work can be replayed after a short backoff.
+const (
+	errDeadlockDetected     = "40P01" // deadlock_detected
+	errSerializationFailure = "40001" // serialization_failure
+	errLockNotAvailable     = "55P03" // lock_not_available
+)
+
+// errInvalidCatalogName is the SQLSTATE for an unknown database.
+const errInvalidCatalogName = "3D000"
+
+// Postgres SQLSTATE codes for a connection the server is tearing down.
+// The statement did not run, and the pool will dial a fresh connection.
+const (
+	errAdminShutdown    = "57P01" // admin_shutdown
+	errCrashShutdown    = "57P02" // crash_shutdown
+	errCannotConnectNow = "57P03" // cannot_connect_now
+)
+
+// retryableServerErrors are the SQLSTATE codes isRetryableError accepts.
+var retryableServerErrors = map[string]bool{
+	errDeadlockDetected:     true,
+	errSerializationFailure: true,
+	errLockNotAvailable:     true,
+	errAdminShutdown:        true,
+	errCrashShutdown:        true,
+	errCannotConnectNow:     true,
+}
+
+// isRetryableError returns true if the error is a transient connection error
+// or lock conflict that should be retried.
+//
+// Server errors are classified by SQLSTATE; any other server error is permanent.
+// Client-side errors are matched against driver.ErrBadConn and syscall errors,
+// with message matching as a last resort for untyped errors.
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return retryableServerErrors[pgErr.Code]
+	}
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	// Don't retry ECONNREFUSED - that means server is down.
+	if errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	return isRetryableErrorMessage(err)
+}
+
+// isRetryableErrorMessage matches transient connection errors by message.
+// It covers errors that lost their type along the way (e.g. formatted with %v).
+func isRetryableErrorMessage(err error) bool {
+	errStr := strings.ToLower(err.Error())
+	if strings.Contains(errStr, "driver: bad connection") {
+		return true
+	}
+	if strings.Contains(errStr, "broken pipe") {
+		return true
+	}
+	if strings.Contains(errStr, "connection reset") {
+		return true
+	}
+	// Don't retry "connection refused" - that means server is down
+	return false
+}
+
+// isLockConflictError returns true if err is a deadlock, serialization
+// failure, or lock_not_available error.
+func isLockConflictError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	switch pgErr.Code {
+	case errDeadlockDetected, errSerializationFailure, errLockNotAvailable:
+		return true
+	}
+	return false
+}
+
+// shouldRetry reports whether a failed operation should be retried.
+// Lock conflicts are only retried for replayable operations: Postgres has
+// aborted the enclosing transaction, so re-running a single statement of a
+// caller-managed transaction would fail or silently drop the others.
+func shouldRetry(err error, replayable bool) bool {
+	if !isRetryableError(err) {
+		return false
+	}
+	return replayable || !isLockConflictError(err)
+}
+
+// withRetry executes an operation with retry for transient connection errors.
+// Lock conflicts are not retried; use withReplayableRetry or withRetryTx for that.
+func (s *PostgresStore) withRetry(ctx context.Context, op func() error) error {
+	return s.retry(ctx, false, op)
+}
+
+// withReplayableRetry is like withRetry but also retries lock conflicts.
+// op must be safe to run again from the start: an idempotent autocommit
+// statement or a complete transaction (see withRetryTx).
+func (s *PostgresStore) withReplayableRetry(ctx context.Context, op func() error) error {
+	return s.retry(ctx, true, op)
+}
+
+// withRetryTx runs fn in its own transaction, replaying the whole transaction
+// on transient errors. fn may run more than once, so it must only write
+// through tx.
+func (s *PostgresStore) withRetryTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	return s.withReplayableRetry(ctx, func() error {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+func (s *PostgresStore) retry(ctx context.Context, replayable bool, op func() error) error {
+	bo := newServerRetryBackoff()
+	return backoff.Retry(func() error {
+		err := op()
+		if err != nil && shouldRetry(err, replayable) {
+			return err // Retryable - backoff will retry
+		}
+		if err != nil {
+			return backoff.Permanent(err) // Non-retryable - stop immediately
+		}
+		return nil
+	}, backoff.WithContext(bo, ctx))
+}
+
+// New creates a new Postgres storage backend
+func New(ctx context.Context, cfg *Config) (*PostgresStore, error) {
+	// Default values
+	if cfg.Database == "" {
+		cfg.Database = "beads"
+	}
+	if err := validateIdentifier("database", cfg.Database); err != nil {
+		return nil, err
+	}
+	if cfg.Schema == "" {
+		cfg.Schema = DefaultSchema
+	}
+	if err := validateIdentifier("schema", cfg.Schema); err != nil {
+		return nil, err
+	}
+	if cfg.Host == "" {
+		cfg.Host = "127.0.0.1"
+	}
+	if cfg.Port == 0 {
+		cfg.Port = DefaultPort
+	}
+	if cfg.User == "" {
+		cfg.User = "postgres"
+	}
+	// Check environment variable for password (more secure than command-line)
+	if cfg.Password == "" {
+		cfg.Password = os.Getenv("BEADS_POSTGRES_PASSWORD")
+	}
+	if cfg.ConnectTimeout < 0 {
+		return nil, fmt.Errorf("invalid Postgres config: ConnectTimeout must not be negative")
+	}
+	if cfg.ConnectTimeout == 0 {
+		cfg.ConnectTimeout = DefaultConnectTimeout
+	}
+	if err := applyPoolDefaults(cfg); err != nil {
+		return nil, err
+	}
+
+	connStr := buildDSN(cfg)
+	connector, err := newConnector(connStr, cfg.Schema)
+	if err != nil {
+		return nil, err
+	}
+	db := sql.OpenDB(connector)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	// Test connection
+	pingCtx := ctx
+	if pingCtx == nil {
+		pingCtx = context.Background()
+	}
+	if err := db.PingContext(pingCtx); err != nil {
+		_ = db.Close()
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == errInvalidCatalogName {
+			return nil, fmt.Errorf("Postgres database %s does not exist; create it with: CREATE DATABASE %s: %w", cfg.Database, cfg.Database, err)
+		}
+		return nil, fmt.Errorf("failed to ping Postgres database: %w", err)
+	}
+
+	store := &PostgresStore{
+		db:       db,
+		dbName:   cfg.Database,
+		schema:   cfg.Schema,
+		connStr:  connStr,
+		readOnly: cfg.ReadOnly,
+	}
+
+	// Initialize schema (idempotent)
+	if !cfg.ReadOnly {
+		if err := store.initSchema(ctx); err != nil {
+			_ = store.Close()
+			return nil, fmt.Errorf("failed to initialize schema: %w", err)
+		}
+	}
+
+	return store, nil
+}
+
+// applyPoolDefaults fills in zero-valued pool settings and validates the result.
+func applyPoolDefaults(cfg *Config) error {
+	if cfg.MaxOpenConns < 0 || cfg.MaxIdleConns < 0 || cfg.ConnMaxLifetime < 0 {
+		return fmt.Errorf("invalid Postgres pool config: values must not be negative")
+	}
+	if cfg.MaxOpenConns == 0 {
+		cfg.MaxOpenConns = DefaultMaxOpenConns
+	}
+	if cfg.MaxIdleConns == 0 {
+		cfg.MaxIdleConns = min(DefaultMaxIdleConns, cfg.MaxOpenConns)
+	}
+	if cfg.ConnMaxLifetime == 0 {
+		cfg.ConnMaxLifetime = DefaultConnMaxLifetime
+	}
+	if cfg.MaxIdleConns > cfg.MaxOpenConns {
+		return fmt.Errorf("invalid Postgres pool config: MaxIdleConns (%d) must not exceed MaxOpenConns (%d)",
+			cfg.MaxIdleConns, cfg.MaxOpenConns)
+	}
+	return nil
+}
+
+// initSchemaOnDB creates the schema and all tables if they don't exist
+func initSchemaOnDB(ctx context.Context, db *sql.DB, schemaName string) error {
+	// Connections already have schemaName on their search_path (see
+	// newConnector), but the schema itself may not exist yet.
+	if _, err := db.ExecContext(ctx, "CREATE SCHEMA IF NOT EXISTS "+quoteIdentifier(schemaName)); err != nil {
+		return fmt.Errorf("failed to create schema %s: %w", schemaName, err)
+	}
+
+	// Execute statements one at a time so a failure names the statement
+	for _, stmt := range sqlutil.SplitStatements(schema) {
+		stmt = strings.TrimSpace(stmt)
+		if stmt == "" {
+			continue
+		}
+		// Skip pure comment-only statements, but execute statements that start with comments
+		if sqlutil.IsOnlyComments(stmt) {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to create schema: %w\nStatement: %s", err, sqlutil.TruncateForError(stmt))
+		}
+	}
+
+	// Insert default config values
+	if _, err := db.ExecContext(ctx, defaultConfig); err != nil {
+		return fmt.Errorf("failed to insert default config: %w", err)
+	}
+
+	// Create views
+	if _, err := db.ExecContext(ctx, readyIssuesView); err != nil {
+		return fmt.Errorf("failed to create ready_issues view: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, blockedIssuesView); err != nil {
+		return fmt.Errorf("failed to create blocked_issues view: %w", err)
+	}
+
+	// Run schema migrations for existing databases
+	if err := RunMigrations(ctx, db); err != nil {
+		return fmt.Errorf("failed to run postgres migrations: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) initSchema(ctx context.Context) error {
+	return initSchemaOnDB(ctx, s.db, s.schema)
+}
+
+// Close closes the database connection
+func (s *PostgresStore) Close() error {
+	s.closed.Store(true)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var err error
+	if s.db != nil {
+		if cerr := s.db.Close(); cerr != nil && !errors.Is(cerr, context.Canceled) {
+			err = cerr
+		}
+	}
+	s.db = nil
+	return err
+}
+
+// Path returns the database name (for daemon validation compatibility)
+func (s *PostgresStore) Path() string {
+	return s.dbName
+}
+
+// IsClosed returns true if Close() has been called
+func (s *PostgresStore) IsClosed() bool {
+	return s.closed.Load()
+}
+
+// UnderlyingDB returns the underlying *sql.DB connection
+func (s *PostgresStore) UnderlyingDB() *sql.DB {
+	return s.db
+}
+
+// UnderlyingConn returns a connection from the pool
+func (s *PostgresStore) UnderlyingConn(ctx context.Context) (*sql.Conn, error) {
+	return s.db.Conn(ctx)
+}
+
+// Capabilities reports the features of PostgreSQL 9.5 and newer.
+func (s *PostgresStore) Capabilities() storage.Capabilities {
+	return storage.Capabilities{
+		FullTextSearch:  true,
+		RecursiveCTE:    true,
+		WindowFunctions: true,
+		SkipLocked:      true,
+	}
+}
+
+// Ensure PostgresStore implements storage.Storage
+var _ storage.Storage = (*PostgresStore)(nil)
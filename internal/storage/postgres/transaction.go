@@ -0,0 +1,478 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// postgresTransaction implements storage.Transaction for Postgres
+type postgresTransaction struct {
+	tx    *sql.Tx
+	store *PostgresStore
+}
+
+// CreateIssueImport is the import-friendly issue creation hook.
+// Postgres does not enforce prefix validation at the storage layer, so this delegates to CreateIssue.
+func (t *postgresTransaction) CreateIssueImport(ctx context.Context, issue *types.Issue, actor string, skipPrefixValidation bool) error {
+	return t.CreateIssue(ctx, issue, actor)
+}
+
+// RunInTransaction executes a function within a database transaction
+func (s *PostgresStore) RunInTransaction(ctx context.Context, fn func(tx storage.Transaction) error) error {
+	sqlTx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	tx := &postgresTransaction{tx: sqlTx, store: s}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = sqlTx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		_ = sqlTx.Rollback()
+		return err
+	}
+
+	return sqlTx.Commit()
+}
+
+// CreateIssue creates an issue within the transaction
+func (t *postgresTransaction) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	now := time.Now().UTC()
+	if issue.CreatedAt.IsZero() {
+		issue.CreatedAt = now
+	}
+	if issue.UpdatedAt.IsZero() {
+		issue.UpdatedAt = now
+	}
+	if issue.ContentHash == "" {
+		issue.ContentHash = issue.ComputeContentHash()
+	}
+
+	// Generate ID if not provided (critical for wisp creation)
+	if issue.ID == "" {
+		// Get prefix from config
+		var configPrefix string
+		err := t.tx.QueryRowContext(ctx, "SELECT value FROM config WHERE key = ?", "issue_prefix").Scan(&configPrefix)
+		if err == sql.ErrNoRows || configPrefix == "" {
+			return fmt.Errorf("database not initialized: issue_prefix config is missing")
+		} else if err != nil {
+			return fmt.Errorf("failed to get config: %w", err)
+		}
+
+		// Determine effective prefix
+		prefix := configPrefix
+		if issue.PrefixOverride != "" {
+			prefix = issue.PrefixOverride
+		} else if issue.IDPrefix != "" {
+			prefix = configPrefix + "-" + issue.IDPrefix
+		}
+
+		// Generate ID
+		generatedID, err := generateIssueID(ctx, t.tx, prefix, issue, actor)
+		if err != nil {
+			return fmt.Errorf("failed to generate issue ID: %w", err)
+		}
+		issue.ID = generatedID
+	}
+
+	return insertIssueTx(ctx, t.tx, issue)
+}
+
+// CreateIssues creates multiple issues within the transaction
+func (t *postgresTransaction) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) error {
+	for _, issue := range issues {
+		if err := t.CreateIssue(ctx, issue, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetIssue retrieves an issue within the transaction
+func (t *postgresTransaction) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	return scanIssueTx(ctx, t.tx, id)
+}
+
+// SearchIssues searches for issues within the transaction
+func (t *postgresTransaction) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	// Simplified search for transaction context
+	whereClauses := []string{}
+	args := []interface{}{}
+
+	if query != "" {
+		whereClauses = append(whereClauses, "(title ILIKE ? OR description ILIKE ? OR id ILIKE ?)")
+		pattern := "%" + query + "%"
+		args = append(args, pattern, pattern, pattern)
+	}
+
+	if filter.Status != nil {
+		whereClauses = append(whereClauses, "status = ?")
+		args = append(args, *filter.Status)
+	}
+	if filter.SpecIDPrefix != "" {
+		whereClauses = append(whereClauses, "spec_id LIKE ?")
+		args = append(args, filter.SpecIDPrefix+"%")
+	}
+
+	whereSQL := ""
+	if len(whereClauses) > 0 {
+		whereSQL = "WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	rows, err := t.tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT id FROM issues %s ORDER BY priority ASC, created_at DESC
+	`, whereSQL), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect all IDs first, then close rows before fetching full issues:
+	// a transaction runs on a single connection, which can't interleave queries.
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	_ = rows.Close()
+
+	// Now fetch each issue (safe since rows is closed)
+	var issues []*types.Issue
+	for _, id := range ids {
+		issue, err := t.GetIssue(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if issue != nil {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// UpdateIssue updates an issue within the transaction
+func (t *postgresTransaction) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	setClauses := []string{"updated_at = ?"}
+	args := []interface{}{time.Now().UTC()}
+
+	for key, value := range updates {
+		if !isAllowedUpdateField(key) {
+			return fmt.Errorf("invalid field for update: %s", key)
+		}
+		columnName := key
+		if key == "wisp" {
+			columnName = "ephemeral"
+		}
+		setClauses = append(setClauses, fmt.Sprintf("%s = ?", columnName))
+		args = append(args, value)
+	}
+
+	args = append(args, id)
+	// nolint:gosec // G201: setClauses contains only column names (e.g. "status = ?"), actual values passed via args
+	query := fmt.Sprintf("UPDATE issues SET %s WHERE id = ?", strings.Join(setClauses, ", "))
+	_, err := t.tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// CloseIssue closes an issue within the transaction
+func (t *postgresTransaction) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) error {
+	now := time.Now().UTC()
+	_, err := t.tx.ExecContext(ctx, `
+		UPDATE issues SET status = ?, closed_at = ?, updated_at = ?, close_reason = ?, closed_by_session = ?
+		WHERE id = ?
+	`, types.StatusClosed, now, now, reason, session, id)
+	return err
+}
+
+// DeleteIssue deletes an issue within the transaction
+func (t *postgresTransaction) DeleteIssue(ctx context.Context, id string) error {
+	_, err := t.tx.ExecContext(ctx, "DELETE FROM issues WHERE id = ?", id)
+	return err
+}
+
+// AddDependency adds a dependency within the transaction
+func (t *postgresTransaction) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	_, err := t.tx.ExecContext(ctx, `
+		INSERT INTO dependencies (issue_id, depends_on_id, type, created_at, created_by, thread_id)
+		VALUES (?, ?, ?, NOW(), ?, ?)
+		ON CONFLICT (issue_id, depends_on_id) DO UPDATE SET type = EXCLUDED.type
+	`, dep.IssueID, dep.DependsOnID, dep.Type, actor, dep.ThreadID)
+	return err
+}
+
+func (t *postgresTransaction) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
+	rows, err := t.tx.QueryContext(ctx, `
+		SELECT issue_id, depends_on_id, type, created_at, created_by, metadata, thread_id
+		FROM dependencies
+		WHERE issue_id = ?
+	`, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deps []*types.Dependency
+	for rows.Next() {
+		var d types.Dependency
+		var metadata sql.NullString
+		var threadID sql.NullString
+		if err := rows.Scan(&d.IssueID, &d.DependsOnID, &d.Type, &d.CreatedAt, &d.CreatedBy, &metadata, &threadID); err != nil {
+			return nil, err
+		}
+		if metadata.Valid {
+			d.Metadata = metadata.String
+		}
+		if threadID.Valid {
+			d.ThreadID = threadID.String
+		}
+		deps = append(deps, &d)
+	}
+	return deps, rows.Err()
+}
+
+// RemoveDependency removes a dependency within the transaction
+func (t *postgresTransaction) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	_, err := t.tx.ExecContext(ctx, `
+		DELETE FROM dependencies WHERE issue_id = ? AND depends_on_id = ?
+	`, issueID, dependsOnID)
+	return err
+}
+
+// AddLabel adds a label within the transaction
+func (t *postgresTransaction) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	_, err := t.tx.ExecContext(ctx, `
+		INSERT INTO labels (issue_id, label) VALUES (?, ?) ON CONFLICT DO NOTHING
+	`, issueID, label)
+	return err
+}
+
+func (t *postgresTransaction) GetLabels(ctx context.Context, issueID string) ([]string, error) {
+	rows, err := t.tx.QueryContext(ctx, `SELECT label FROM labels WHERE issue_id = ? ORDER BY label`, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var labels []string
+	for rows.Next() {
+		var l string
+		if err := rows.Scan(&l); err != nil {
+			return nil, err
+		}
+		labels = append(labels, l)
+	}
+	return labels, rows.Err()
+}
+
+// RemoveLabel removes a label within the transaction
+func (t *postgresTransaction) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	_, err := t.tx.ExecContext(ctx, `
+		DELETE FROM labels WHERE issue_id = ? AND label = ?
+	`, issueID, label)
+	return err
+}
+
+// SetConfig sets a config value within the transaction
+func (t *postgresTransaction) SetConfig(ctx context.Context, key, value string) error {
+	_, err := t.tx.ExecContext(ctx, `
+		INSERT INTO config (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value
+	`, key, value)
+	return err
+}
+
+// GetConfig gets a config value within the transaction
+func (t *postgresTransaction) GetConfig(ctx context.Context, key string) (string, error) {
+	var value string
+	err := t.tx.QueryRowContext(ctx, "SELECT value FROM config WHERE key = ?", key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
+
+// SetMetadata sets a metadata value within the transaction
+func (t *postgresTransaction) SetMetadata(ctx context.Context, key, value string) error {
+	_, err := t.tx.ExecContext(ctx, `
+		INSERT INTO metadata (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value
+	`, key, value)
+	return err
+}
+
+// GetMetadata gets a metadata value within the transaction
+func (t *postgresTransaction) GetMetadata(ctx context.Context, key string) (string, error) {
+	var value string
+	err := t.tx.QueryRowContext(ctx, "SELECT value FROM metadata WHERE key = ?", key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
+
+func (t *postgresTransaction) ImportIssueComment(ctx context.Context, issueID, author, text string, createdAt time.Time) (*types.Comment, error) {
+	// Verify issue exists in tx
+	iss, err := t.GetIssue(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if iss == nil {
+		return nil, fmt.Errorf("issue %s not found", issueID)
+	}
+
+	createdAt = createdAt.UTC()
+	var id int64
+	err = t.tx.QueryRowContext(ctx, `
+		INSERT INTO comments (issue_id, author, text, created_at)
+		VALUES (?, ?, ?, ?)
+		RETURNING id
+	`, issueID, author, text, createdAt).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add comment: %w", err)
+	}
+
+	// mark dirty in tx
+	if _, err := t.tx.ExecContext(ctx, `
+		INSERT INTO dirty_issues (issue_id, marked_at)
+		VALUES (?, ?)
+		ON CONFLICT (issue_id) DO UPDATE SET marked_at = EXCLUDED.marked_at
+	`, issueID, time.Now().UTC()); err != nil {
+		return nil, fmt.Errorf("failed to mark issue dirty: %w", err)
+	}
+
+	return &types.Comment{ID: id, IssueID: issueID, Author: author, Text: text, CreatedAt: createdAt}, nil
+}
+
+func (t *postgresTransaction) GetIssueComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
+	rows, err := t.tx.QueryContext(ctx, `
+		SELECT id, issue_id, author, text, created_at
+		FROM comments
+		WHERE issue_id = ?
+		ORDER BY created_at ASC
+	`, issueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var comments []*types.Comment
+	for rows.Next() {
+		var c types.Comment
+		if err := rows.Scan(&c.ID, &c.IssueID, &c.Author, &c.Text, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		comments = append(comments, &c)
+	}
+	return comments, rows.Err()
+}
+
+// AddComment adds a comment within the transaction
+func (t *postgresTransaction) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	_, err := t.tx.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, comment)
+		VALUES (?, ?, ?, ?)
+	`, issueID, types.EventCommented, actor, comment)
+	return err
+}
+
+// Helper functions for transaction context
+
+func insertIssueTx(ctx context.Context, tx *sql.Tx, issue *types.Issue) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO issues (
+			id, content_hash, title, description, design, acceptance_criteria, notes,
+			status, priority, issue_type, assignee, estimated_minutes,
+			created_at, created_by, owner, updated_at, closed_at,
+			sender, ephemeral, wisp_type, pinned, is_template, crystallizes
+		) VALUES (
+			?, ?, ?, ?, ?, ?, ?,
+			?, ?, ?, ?, ?,
+			?, ?, ?, ?, ?,
+			?, ?, ?, ?, ?, ?
+		)
+	`,
+		issue.ID, issue.ContentHash, issue.Title, issue.Description, issue.Design, issue.AcceptanceCriteria, issue.Notes,
+		issue.Status, issue.Priority, issue.IssueType, nullString(issue.Assignee), nullInt(issue.EstimatedMinutes),
+		issue.CreatedAt, issue.CreatedBy, issue.Owner, issue.UpdatedAt, issue.ClosedAt,
+		issue.Sender, issue.Ephemeral, string(issue.WispType), issue.Pinned, issue.IsTemplate, issue.Crystallizes,
+	)
+	return err
+}
+
+func scanIssueTx(ctx context.Context, tx *sql.Tx, id string) (*types.Issue, error) {
+	var issue types.Issue
+	var closedAt sql.NullTime
+	var estimatedMinutes sql.NullInt64
+	var assignee, owner, contentHash sql.NullString
+	var ephemeral, pinned, isTemplate, crystallizes sql.NullBool
+
+	err := tx.QueryRowContext(ctx, `
+		SELECT id, content_hash, title, description, design, acceptance_criteria, notes,
+		       status, priority, issue_type, assignee, estimated_minutes,
+		       created_at, created_by, owner, updated_at, closed_at,
+		       ephemeral, pinned, is_template, crystallizes
+		FROM issues
+		WHERE id = ?
+	`, id).Scan(
+		&issue.ID, &contentHash, &issue.Title, &issue.Description, &issue.Design,
+		&issue.AcceptanceCriteria, &issue.Notes, &issue.Status,
+		&issue.Priority, &issue.IssueType, &assignee, &estimatedMinutes,
+		&issue.CreatedAt, &issue.CreatedBy, &owner, &issue.UpdatedAt, &closedAt,
+		&ephemeral, &pinned, &isTemplate, &crystallizes,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if contentHash.Valid {
+		issue.ContentHash = contentHash.String
+	}
+	if closedAt.Valid {
+		issue.ClosedAt = &closedAt.Time
+	}
+	if estimatedMinutes.Valid {
+		mins := int(estimatedMinutes.Int64)
+		issue.EstimatedMinutes = &mins
+	}
+	if assignee.Valid {
+		issue.Assignee = assignee.String
+	}
+	if owner.Valid {
+		issue.Owner = owner.String
+	}
+	if ephemeral.Valid && ephemeral.Bool {
+		issue.Ephemeral = true
+	}
+	if pinned.Valid && pinned.Bool {
+		issue.Pinned = true
+	}
+	if isTemplate.Valid && isTemplate.Bool {
+		issue.IsTemplate = true
+	}
+	if crystallizes.Valid && crystallizes.Bool {
+		issue.Crystallizes = true
+	}
+
+	return &issue, nil
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxIdentifierLength is the Postgres limit for identifiers (NAMEDATALEN - 1).
+const maxIdentifierLength = 63
+
+// identifierPattern restricts database and schema names to lowercase
+// identifiers, which Postgres never folds or needs quoted.
+var identifierPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// validateIdentifier returns an error unless name is 1-63 characters of
+// lowercase letters, digits, and underscores, not starting with a digit.
+// kind names the identifier in the error, e.g. "database".
+func validateIdentifier(kind, name string) error {
+	if name == "" {
+		return fmt.Errorf("invalid Postgres %s name: must not be empty", kind)
+	}
+	if len(name) > maxIdentifierLength {
+		return fmt.Errorf("invalid Postgres %s name %q: longer than %d characters", kind, name, maxIdentifierLength)
+	}
+	if !identifierPattern.MatchString(name) {
+		return fmt.Errorf("invalid Postgres %s name %q: only lowercase letters, digits, and underscores are allowed", kind, name)
+	}
+	return nil
+}
+
+// quoteIdentifier double-quotes a Postgres identifier, doubling embedded quotes.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
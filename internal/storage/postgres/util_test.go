@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateIdentifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		ident   string
+		wantErr bool
+	}{
+		{"default", "beads", false},
+		{"underscores and digits", "beads_test_01", false},
+		{"leading underscore", "_beads", false},
+		{"max length", strings.Repeat("a", 63), false},
+		{"empty", "", true},
+		{"too long", strings.Repeat("a", 64), true},
+		{"uppercase", "Beads", true},
+		{"leading digit", "1beads", true},
+		{"quote", `beads"; DROP SCHEMA public; --`, true},
+		{"space", "my beads", true},
+		{"hyphen", "my-beads", true},
+		{"dot", "other.beads", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateIdentifier("schema", tt.ident)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateIdentifier(%q) error = %v, wantErr %v", tt.ident, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"beads", `"beads"`},
+		{`a"b`, `"a""b"`},
+	}
+	for _, tt := range tests {
+		if got := quoteIdentifier(tt.in); got != tt.want {
+			t.Errorf("quoteIdentifier(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNewRejectsInvalidNames(t *testing.T) {
+	ctx, cancel := testContext(t)
+	defer cancel()
+
+	// Rejected before any connection attempt, so no server is needed
+	_, err := New(ctx, &Config{Database: "beads; DROP DATABASE postgres", Port: 1})
+	if err == nil || !strings.Contains(err.Error(), "invalid Postgres database name") {
+		t.Errorf("expected invalid database name error, got %v", err)
+	}
+	_, err = New(ctx, &Config{Schema: "Team-A", Port: 1})
+	if err == nil || !strings.Contains(err.Error(), "invalid Postgres schema name") {
+		t.Errorf("expected invalid schema name error, got %v", err)
+	}
+}
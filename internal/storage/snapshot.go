@@ -0,0 +1,260 @@
+// Package storage defines the interface for issue storage backends.
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// OnConflict selects what importing an issue does when its ID already exists.
+type OnConflict int
+
+const (
+	// ConflictError fails the import with a duplicate key error
+	ConflictError OnConflict = iota
+	// ConflictSkip keeps the existing issue unchanged and records nothing for it
+	ConflictSkip
+	// ConflictReplace overwrites the existing issue's fields with the new
+	// values, preserving its created_at and created_by. A deleted issue stays
+	// deleted.
+	ConflictReplace
+	// ConflictReplaceUndelete is ConflictReplace, except that the deletion
+	// fields are also taken from the new values, so replacing a deleted issue
+	// with a live one restores it.
+	ConflictReplaceUndelete
+)
+
+// SnapshotFormatVersion is the version of the JSON snapshot format written by
+// ExportJSON. ImportJSON rejects snapshots with a different version.
+const SnapshotFormatVersion = 1
+
+// snapshotActor is recorded as the actor of the changes ImportJSON makes
+// through the Transaction interface.
+const snapshotActor = "import"
+
+// Snapshot is a whole-database JSON export: every issue (with its labels),
+// every dependency, and the config table. Timestamps are RFC 3339 in UTC.
+// Comments, events, and the audit log are not included.
+type Snapshot struct {
+	FormatVersion int                 `json:"format_version"`
+	ExportedAt    time.Time           `json:"exported_at"`
+	Issues        []*types.Issue      `json:"issues"`
+	Dependencies  []*types.Dependency `json:"dependencies"`
+	Config        map[string]string   `json:"config"`
+}
+
+// SnapshotImporter is implemented by backends that import a Snapshot
+// natively, keeping fields the Transaction interface cannot write.
+// ImportJSON uses it when the store provides it.
+type SnapshotImporter interface {
+	ImportJSON(ctx context.Context, r io.Reader, onConflict OnConflict) error
+}
+
+// ExportJSON writes a Snapshot of s to w, including tombstones and
+// soft-deleted issues. Issues and dependencies are sorted by ID so exports of
+// equal databases are identical apart from exported_at. It only uses the
+// Storage interface, so it works with any backend.
+func ExportJSON(ctx context.Context, s Storage, w io.Writer) error {
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{IncludeTombstones: true, IncludeDeleted: true})
+	if err != nil {
+		return fmt.Errorf("failed to export issues: %w", err)
+	}
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := s.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to export labels: %w", err)
+	}
+	for _, issue := range issues {
+		issue.Labels = labels[issue.ID]
+		NormalizeIssueTimes(issue)
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].ID < issues[j].ID })
+
+	records, err := s.GetAllDependencyRecords(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to export dependencies: %w", err)
+	}
+	deps := []*types.Dependency{}
+	for _, issueDeps := range records {
+		for _, dep := range issueDeps {
+			dep.CreatedAt = dep.CreatedAt.UTC()
+			deps = append(deps, dep)
+		}
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].IssueID != deps[j].IssueID {
+			return deps[i].IssueID < deps[j].IssueID
+		}
+		return deps[i].DependsOnID < deps[j].DependsOnID
+	})
+
+	config, err := s.GetAllConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to export config: %w", err)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(&Snapshot{
+		FormatVersion: SnapshotFormatVersion,
+		ExportedAt:    time.Now().UTC().Truncate(time.Second),
+		Issues:        issues,
+		Dependencies:  deps,
+		Config:        config,
+	}); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// NormalizeIssueTimes converts the issue's timestamps to UTC, so exports do
+// not depend on the connection's time zone.
+func NormalizeIssueTimes(issue *types.Issue) {
+	issue.CreatedAt = issue.CreatedAt.UTC()
+	issue.UpdatedAt = issue.UpdatedAt.UTC()
+	for _, t := range []*time.Time{issue.ClosedAt, issue.CompactedAt, issue.DeletedAt, issue.LastActivity, issue.DueAt, issue.DeferUntil} {
+		if t != nil {
+			*t = t.UTC()
+		}
+	}
+}
+
+// ImportJSON reads a Snapshot written by ExportJSON into s, so snapshots can
+// move between backends. If s implements SnapshotImporter its own import is
+// used. Otherwise the snapshot is imported in one RunInTransaction: config
+// values are set, new issues are created with their labels, and issues whose
+// ID already exists are resolved as onConflict says. A replaced issue gets
+// the snapshot's values for the fields UpdateIssue accepts, and its labels
+// and dependencies are replaced with the snapshot's; a skipped issue keeps
+// its own. Dependencies are added after all issues.
+func ImportJSON(ctx context.Context, s Storage, r io.Reader, onConflict OnConflict) error {
+	if importer, ok := s.(SnapshotImporter); ok {
+		return importer.ImportJSON(ctx, r, onConflict)
+	}
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if snapshot.FormatVersion != SnapshotFormatVersion {
+		return fmt.Errorf("unsupported snapshot format version %d (want %d)", snapshot.FormatVersion, SnapshotFormatVersion)
+	}
+
+	return s.RunInTransaction(ctx, func(tx Transaction) error {
+		for key, value := range snapshot.Config {
+			if err := tx.SetConfig(ctx, key, value); err != nil {
+				return fmt.Errorf("failed to import config %s: %w", key, err)
+			}
+		}
+
+		// written holds the issues whose dependencies come from the snapshot.
+		// Issues are sorted by ID, so a parent is created before its children.
+		written := make(map[string]bool, len(snapshot.Issues))
+		for _, issue := range snapshot.Issues {
+			existing, err := tx.GetIssue(ctx, issue.ID)
+			if err != nil {
+				return fmt.Errorf("failed to import issue %s: %w", issue.ID, err)
+			}
+			if existing == nil {
+				if err := tx.CreateIssue(ctx, issue, snapshotActor); err != nil {
+					return fmt.Errorf("failed to import issue %s: %w", issue.ID, err)
+				}
+				for _, label := range issue.Labels {
+					if err := tx.AddLabel(ctx, issue.ID, label, snapshotActor); err != nil {
+						return fmt.Errorf("failed to import labels of %s: %w", issue.ID, err)
+					}
+				}
+				written[issue.ID] = true
+				continue
+			}
+
+			switch onConflict {
+			case ConflictError:
+				return fmt.Errorf("failed to import issue %s: issue already exists", issue.ID)
+			case ConflictSkip:
+				continue
+			case ConflictReplace, ConflictReplaceUndelete:
+				if err := replaceIssue(ctx, tx, existing, issue, onConflict == ConflictReplaceUndelete); err != nil {
+					return fmt.Errorf("failed to import issue %s: %w", issue.ID, err)
+				}
+				written[issue.ID] = true
+			default:
+				return fmt.Errorf("unknown OnConflict mode %d", onConflict)
+			}
+		}
+
+		for _, dep := range snapshot.Dependencies {
+			if !written[dep.IssueID] {
+				continue
+			}
+			if err := tx.AddDependency(ctx, dep, snapshotActor); err != nil {
+				return fmt.Errorf("failed to import dependency %s -> %s: %w", dep.IssueID, dep.DependsOnID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// replaceIssue overwrites existing with the fields of issue that UpdateIssue
+// accepts, and clears existing's labels and dependencies for the snapshot's.
+// Unless undelete is set, a tombstone keeps its status.
+func replaceIssue(ctx context.Context, tx Transaction, existing, issue *types.Issue, undelete bool) error {
+	updates := map[string]interface{}{
+		"title":               issue.Title,
+		"description":         issue.Description,
+		"design":              issue.Design,
+		"acceptance_criteria": issue.AcceptanceCriteria,
+		"notes":               issue.Notes,
+		"status":              string(issue.Status),
+		"priority":            issue.Priority,
+		"issue_type":          string(issue.IssueType),
+		"assignee":            issue.Assignee,
+	}
+	if issue.EstimatedMinutes != nil {
+		updates["estimated_minutes"] = *issue.EstimatedMinutes
+	}
+	if issue.ExternalRef != nil {
+		updates["external_ref"] = *issue.ExternalRef
+	}
+	if existing.Status == types.StatusTombstone && !undelete {
+		delete(updates, "status")
+	}
+	if err := tx.UpdateIssue(ctx, issue.ID, updates, snapshotActor); err != nil {
+		return err
+	}
+
+	labels, err := tx.GetLabels(ctx, issue.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get labels: %w", err)
+	}
+	for _, label := range labels {
+		if err := tx.RemoveLabel(ctx, issue.ID, label, snapshotActor); err != nil {
+			return fmt.Errorf("failed to clear labels: %w", err)
+		}
+	}
+	for _, label := range issue.Labels {
+		if err := tx.AddLabel(ctx, issue.ID, label, snapshotActor); err != nil {
+			return fmt.Errorf("failed to import labels: %w", err)
+		}
+	}
+
+	deps, err := tx.GetDependencyRecords(ctx, issue.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get dependencies: %w", err)
+	}
+	for _, dep := range deps {
+		if err := tx.RemoveDependency(ctx, dep.IssueID, dep.DependsOnID, snapshotActor); err != nil {
+			return fmt.Errorf("failed to clear dependencies: %w", err)
+		}
+	}
+	return nil
+}
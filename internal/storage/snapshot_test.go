@@ -0,0 +1,128 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/sqlite"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+func newSQLiteStore(t *testing.T) *sqlite.SQLiteStorage {
+	t.Helper()
+	store, err := sqlite.New(context.Background(), filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatalf("failed to open sqlite store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if err := store.SetConfig(context.Background(), "issue_prefix", "test"); err != nil {
+		t.Fatalf("failed to set issue_prefix: %v", err)
+	}
+	return store
+}
+
+func decodeSnapshot(t *testing.T, data []byte) *storage.Snapshot {
+	t.Helper()
+	var snapshot storage.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	snapshot.ExportedAt = time.Time{}
+	return &snapshot
+}
+
+// TestExportImportJSONAcrossStores round-trips a snapshot through the
+// backend-neutral helpers, from one sqlite store into a fresh one.
+func TestExportImportJSONAcrossStores(t *testing.T) {
+	ctx := context.Background()
+	source := newSQLiteStore(t)
+
+	due := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, issue := range []*types.Issue{
+		{ID: "test-a", Title: "First", Description: "desc", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug, DueAt: &due},
+		{ID: "test-b", Title: "Second", Status: types.StatusClosed, Priority: 2, IssueType: types.TypeTask},
+	} {
+		if err := source.CreateIssue(ctx, issue, "tester"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", issue.ID, err)
+		}
+	}
+	if err := source.AddLabel(ctx, "test-a", "backend", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := source.AddDependency(ctx, &types.Dependency{IssueID: "test-a", DependsOnID: "test-b", Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := source.SetConfig(ctx, "custom.key", "value"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+
+	var exported bytes.Buffer
+	if err := storage.ExportJSON(ctx, source, &exported); err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+	if !strings.Contains(exported.String(), `"due_at": "2030-01-02T03:04:05Z"`) {
+		t.Errorf("export does not contain RFC 3339 due_at:\n%s", exported.String())
+	}
+
+	target := newSQLiteStore(t)
+	if err := storage.ImportJSON(ctx, target, bytes.NewReader(exported.Bytes()), storage.ConflictError); err != nil {
+		t.Fatalf("ImportJSON failed: %v", err)
+	}
+	var reexported bytes.Buffer
+	if err := storage.ExportJSON(ctx, target, &reexported); err != nil {
+		t.Fatalf("ExportJSON(target) failed: %v", err)
+	}
+	want, got := decodeSnapshot(t, exported.Bytes()), decodeSnapshot(t, reexported.Bytes())
+	if !reflect.DeepEqual(got.Issues, want.Issues) {
+		t.Errorf("imported issues differ:\n got %s\nwant %s", reexported.String(), exported.String())
+	}
+	if len(got.Dependencies) != 1 || got.Dependencies[0].IssueID != "test-a" || got.Dependencies[0].DependsOnID != "test-b" || got.Dependencies[0].Type != types.DepBlocks {
+		t.Errorf("imported dependencies = %+v, want test-a blocked by test-b", got.Dependencies)
+	}
+	if got.Config["custom.key"] != "value" {
+		t.Errorf("imported config custom.key = %q, want value", got.Config["custom.key"])
+	}
+
+	// Re-importing fails by default, but is a no-op when skipping
+	if err := storage.ImportJSON(ctx, target, bytes.NewReader(exported.Bytes()), storage.ConflictError); err == nil {
+		t.Error("ImportJSON into a populated store should fail with ConflictError")
+	}
+	if err := storage.ImportJSON(ctx, target, bytes.NewReader(exported.Bytes()), storage.ConflictSkip); err != nil {
+		t.Errorf("ImportJSON(ConflictSkip) failed: %v", err)
+	}
+
+	// Replacing takes the snapshot's fields, labels and dependencies
+	if err := target.UpdateIssue(ctx, "test-a", map[string]interface{}{"title": "Edited"}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := target.AddLabel(ctx, "test-a", "local", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+	if err := storage.ImportJSON(ctx, target, bytes.NewReader(exported.Bytes()), storage.ConflictReplace); err != nil {
+		t.Fatalf("ImportJSON(ConflictReplace) failed: %v", err)
+	}
+	issue, err := target.GetIssue(ctx, "test-a")
+	if err != nil || issue == nil {
+		t.Fatalf("GetIssue = %v, %v", issue, err)
+	}
+	if issue.Title != "First" {
+		t.Errorf("replaced title = %q, want First", issue.Title)
+	}
+	if labels, _ := target.GetLabels(ctx, "test-a"); !reflect.DeepEqual(labels, []string{"backend"}) {
+		t.Errorf("replaced labels = %v, want [backend]", labels)
+	}
+	if deps, _ := target.GetDependencyRecords(ctx, "test-a"); len(deps) != 1 {
+		t.Errorf("replaced dependencies = %+v, want one", deps)
+	}
+
+	if err := storage.ImportJSON(ctx, target, strings.NewReader(`{"format_version": 99}`), storage.ConflictSkip); err == nil {
+		t.Error("ImportJSON should reject an unknown format version")
+	}
+}
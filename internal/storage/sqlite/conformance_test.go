@@ -0,0 +1,29 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/storage/storagetest"
+)
+
+// newConformanceStore returns an empty store for the storagetest suites.
+func newConformanceStore(t *testing.T) storage.Storage {
+	store, err := New(context.Background(), filepath.Join(t.TempDir(), "beads.db"))
+	if err != nil {
+		t.Fatalf("failed to create SQLite store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.RunConformance(t, newConformanceStore)
+}
+
+func TestDependencyValidation(t *testing.T) {
+	storagetest.RunDependencyValidation(t, newConformanceStore)
+	storagetest.RunMissingTargetValidation(t, newConformanceStore)
+}
@@ -18,6 +18,7 @@ import (
 	sqlite3 "github.com/ncruces/go-sqlite3"
 	_ "github.com/ncruces/go-sqlite3/driver"
 	_ "github.com/ncruces/go-sqlite3/embed"
+	"github.com/steveyegge/beads/internal/storage"
 	"github.com/tetratelabs/wazero"
 )
 
@@ -496,6 +497,16 @@ func (s *SQLiteStorage) UnderlyingConn(ctx context.Context) (*sql.Conn, error) {
 	return s.db.Conn(ctx)
 }
 
+// Capabilities reports the features of the embedded SQLite build, which
+// includes FTS5. SQLite has no row locks, so no SKIP LOCKED.
+func (s *SQLiteStorage) Capabilities() storage.Capabilities {
+	return storage.Capabilities{
+		FullTextSearch:  true,
+		RecursiveCTE:    true,
+		WindowFunctions: true,
+	}
+}
+
 // CheckpointWAL checkpoints the WAL file to flush changes to the main database file.
 // In WAL mode, writes go to the -wal file, leaving the main .db file untouched.
 // Checkpointing:
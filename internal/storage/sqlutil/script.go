@@ -0,0 +1,124 @@
+// Package sqlutil provides helpers shared by the SQL server backends
+// (MariaDB, Postgres) for running multi-statement schema scripts.
+package sqlutil
+
+import "strings"
+
+// SplitStatements splits a SQL script into individual statements.
+// Semicolons inside string literals, quoted identifiers, -- line comments,
+// and /* */ block comments do not end a statement. Comments are kept in the
+// returned statements.
+func SplitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+
+		if n := quotedLen(script[i:]); n > 0 {
+			current.WriteString(script[i : i+n])
+			i += n - 1
+			continue
+		}
+
+		if n := commentLen(script[i:]); n > 0 {
+			current.WriteString(script[i : i+n])
+			i += n - 1
+			continue
+		}
+
+		if c == ';' {
+			stmt := strings.TrimSpace(current.String())
+			if stmt != "" {
+				statements = append(statements, stmt)
+			}
+			current.Reset()
+			continue
+		}
+
+		current.WriteByte(c)
+	}
+
+	// Handle last statement without semicolon
+	stmt := strings.TrimSpace(current.String())
+	if stmt != "" {
+		statements = append(statements, stmt)
+	}
+
+	return statements
+}
+
+// quotedLen returns the length of the quoted token at the start of s, including
+// both quotes, or 0 if s does not start with a quote. An unterminated token
+// runs to the end of s.
+//
+// A quote character written twice inside the token is an escaped quote, in
+// all three forms. String literals ('...' and "...") also honor backslash escapes, as
+// MySQL does unless NO_BACKSLASH_ESCAPES is set; backtick identifiers do not.
+func quotedLen(s string) int {
+	if s == "" {
+		return 0
+	}
+	quote := s[0]
+	if quote != '\'' && quote != '"' && quote != '`' {
+		return 0
+	}
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote != '`':
+			i++ // skip the escaped character
+		case s[i] == quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++ // doubled quote: still inside the token
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// commentLen returns the length of the comment at the start of s, or 0 if s
+// does not start with one. A -- comment runs to the end of the line (excluding
+// the newline); an unterminated /* comment runs to the end of s.
+func commentLen(s string) int {
+	switch {
+	case strings.HasPrefix(s, "--"):
+		if end := strings.IndexByte(s, '\n'); end >= 0 {
+			return end
+		}
+		return len(s)
+	case strings.HasPrefix(s, "/*"):
+		if end := strings.Index(s[2:], "*/"); end >= 0 {
+			return end + 4
+		}
+		return len(s)
+	}
+	return 0
+}
+
+// TruncateForError truncates a string for use in error messages
+func TruncateForError(s string) string {
+	if len(s) > 100 {
+		return s[:100] + "..."
+	}
+	return s
+}
+
+// IsOnlyComments returns true if the statement contains only SQL comments
+// (-- or /* */) and whitespace
+func IsOnlyComments(stmt string) bool {
+	for i := 0; i < len(stmt); i++ {
+		if n := commentLen(stmt[i:]); n > 0 {
+			i += n - 1
+			continue
+		}
+		switch stmt[i] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		// Found a non-comment, non-whitespace character
+		return false
+	}
+	return true
+}
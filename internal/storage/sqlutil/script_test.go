@@ -0,0 +1,160 @@
+package sqlutil
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "empty",
+			script: "",
+			want:   nil,
+		},
+		{
+			name:   "single without trailing semicolon",
+			script: "SELECT 1",
+			want:   []string{"SELECT 1"},
+		},
+		{
+			name:   "multiple statements",
+			script: "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n",
+			want:   []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:   "blank statements dropped",
+			script: ";; SELECT 1 ;\n ; ",
+			want:   []string{"SELECT 1"},
+		},
+		{
+			name:   "semicolon in single-quoted string",
+			script: "INSERT INTO t VALUES ('a;b'); SELECT 2",
+			want:   []string{"INSERT INTO t VALUES ('a;b')", "SELECT 2"},
+		},
+		{
+			name:   "semicolon in double-quoted string",
+			script: `SELECT "x;y"; SELECT 2`,
+			want:   []string{`SELECT "x;y"`, "SELECT 2"},
+		},
+		{
+			name:   "semicolon in backtick identifier",
+			script: "CREATE TABLE `odd;name` (`a;b` INT); SELECT 2",
+			want:   []string{"CREATE TABLE `odd;name` (`a;b` INT)", "SELECT 2"},
+		},
+		{
+			name:   "doubled single quotes with semicolons",
+			script: "INSERT INTO config VALUES('it''s; fine'); INSERT INTO config VALUES('a'';''b');SELECT 3",
+			want: []string{
+				"INSERT INTO config VALUES('it''s; fine')",
+				"INSERT INTO config VALUES('a'';''b')",
+				"SELECT 3",
+			},
+		},
+		{
+			name:   "doubled single quote at end of literal",
+			script: "SELECT 'ends with quote'''; SELECT 2",
+			want:   []string{"SELECT 'ends with quote'''", "SELECT 2"},
+		},
+		{
+			name:   "doubled double quotes",
+			script: `SELECT "say ""hi; there"""; SELECT 2`,
+			want:   []string{`SELECT "say ""hi; there"""`, "SELECT 2"},
+		},
+		{
+			name:   "escaped backslash before closing quote",
+			script: `SELECT 'C:\\'; SELECT 2`,
+			want:   []string{`SELECT 'C:\\'`, "SELECT 2"},
+		},
+		{
+			name:   "backslash-escaped quote",
+			script: `SELECT 'it\'s; fine'; SELECT 2`,
+			want:   []string{`SELECT 'it\'s; fine'`, "SELECT 2"},
+		},
+		{
+			name:   "backslash is literal in backtick identifier",
+			script: "SELECT `dir\\`; SELECT 2",
+			want:   []string{"SELECT `dir\\`", "SELECT 2"},
+		},
+		{
+			name:   "doubled backtick in identifier",
+			script: "CREATE TABLE `a``;b` (id INT); SELECT 2",
+			want:   []string{"CREATE TABLE `a``;b` (id INT)", "SELECT 2"},
+		},
+		{
+			name:   "semicolon in line comment",
+			script: "-- setup; part one\nSELECT 1; SELECT 2 -- trailing; comment\n",
+			want:   []string{"-- setup; part one\nSELECT 1", "SELECT 2 -- trailing; comment"},
+		},
+		{
+			name:   "quote in line comment",
+			script: "-- don't split here\nSELECT 1; SELECT 2",
+			want:   []string{"-- don't split here\nSELECT 1", "SELECT 2"},
+		},
+		{
+			name:   "semicolon in block comment",
+			script: "SELECT /* a; b */ 1; /* multi\nline; comment */ SELECT 2;",
+			want:   []string{"SELECT /* a; b */ 1", "/* multi\nline; comment */ SELECT 2"},
+		},
+		{
+			name:   "unterminated block comment",
+			script: "SELECT 1; /* never closed; SELECT 2",
+			want:   []string{"SELECT 1", "/* never closed; SELECT 2"},
+		},
+		{
+			name:   "comment markers inside string",
+			script: "SELECT '-- not a comment;'; SELECT '/* nor; this */'",
+			want:   []string{"SELECT '-- not a comment;'", "SELECT '/* nor; this */'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitStatements(tt.script)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitStatements(%q) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsOnlyComments(t *testing.T) {
+	tests := []struct {
+		stmt string
+		want bool
+	}{
+		{"", true},
+		{"  \n\t ", true},
+		{"-- just a comment", true},
+		{"-- one\n  -- two\n", true},
+		{"/* block */", true},
+		{"/* multi\nline */ -- and a line comment", true},
+		{"/* unterminated", true},
+		{"-- comment\nSELECT 1", false},
+		{"/* comment */ SELECT 1", false},
+		{"SELECT 1 -- trailing", false},
+	}
+	for _, tt := range tests {
+		if got := IsOnlyComments(tt.stmt); got != tt.want {
+			t.Errorf("IsOnlyComments(%q) = %v, want %v", tt.stmt, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateForError(t *testing.T) {
+	short := strings.Repeat("a", 100)
+	if got := TruncateForError(short); got != short {
+		t.Errorf("TruncateForError should leave 100 characters alone, got %q", got)
+	}
+
+	long := strings.Repeat("b", 150)
+	got := TruncateForError(long)
+	if got != strings.Repeat("b", 100)+"..." {
+		t.Errorf("TruncateForError(150 chars) = %q, want first 100 characters and ...", got)
+	}
+}
@@ -14,6 +14,29 @@ import (
 // claimed by another user. The error message contains the current assignee.
 var ErrAlreadyClaimed = errors.New("issue already claimed")
 
+// ErrStoreClosed is returned when an operation is attempted on a closed store.
+var ErrStoreClosed = errors.New("store is closed")
+
+// ErrReadOnly is returned when a write is attempted on a store opened
+// read-only.
+var ErrReadOnly = errors.New("store is read-only")
+
+// ErrCircuitOpen is returned without contacting the database while a
+// backend's circuit breaker is open after repeated failures.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// ErrVersionConflict is returned by optimistic updates when the issue was
+// changed since the caller read its version.
+var ErrVersionConflict = errors.New("issue version conflict")
+
+// ErrDependencyCycle is returned when adding a dependency would make an issue
+// depend on itself, directly or through other issues.
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// ErrInvalidPriority is returned when an issue is created or updated with a
+// priority outside the range types.ValidatePriority accepts.
+var ErrInvalidPriority = errors.New("invalid priority")
+
 // Transaction provides atomic multi-operation support within a single database transaction.
 //
 // The Transaction interface exposes a subset of Storage methods that execute within
@@ -224,6 +247,20 @@ type Storage interface {
 	// The caller MUST close the connection when done to return it to the pool.
 	// For general queries, prefer UnderlyingDB() which manages the pool automatically.
 	UnderlyingConn(ctx context.Context) (*sql.Conn, error)
+
+	// Capabilities reports the optional query features the backend supports,
+	// so callers can choose a query strategy and degrade gracefully.
+	Capabilities() Capabilities
+}
+
+// Capabilities are optional query features of a storage backend. Server
+// backends detect them from the server version when the store is opened;
+// a false field means the feature is unsupported or could not be detected.
+type Capabilities struct {
+	FullTextSearch  bool // Full-text indexes and MATCH queries
+	RecursiveCTE    bool // WITH RECURSIVE common table expressions
+	WindowFunctions bool // OVER (...) window functions such as ROW_NUMBER()
+	SkipLocked      bool // SELECT ... FOR UPDATE SKIP LOCKED
 }
 
 // Config holds database configuration
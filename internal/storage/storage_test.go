@@ -225,6 +225,9 @@ func (m *mockStorage) UnderlyingDB() *sql.DB {
 func (m *mockStorage) UnderlyingConn(ctx context.Context) (*sql.Conn, error) {
 	return nil, nil
 }
+func (m *mockStorage) Capabilities() Capabilities {
+	return Capabilities{}
+}
 
 // mockTransaction is a minimal mock for Transaction interface testing.
 type mockTransaction struct{}
@@ -417,6 +420,7 @@ func TestInterfaceDocumentation(t *testing.T) {
 		_ = s.Path
 		_ = s.UnderlyingDB
 		_ = s.UnderlyingConn
+		_ = s.Capabilities
 	})
 
 	t.Run("Transaction interface has expected methods", func(t *testing.T) {
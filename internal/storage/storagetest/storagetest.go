@@ -0,0 +1,673 @@
+// Package storagetest provides a conformance test suite for storage.Storage
+// implementations, so every backend is held to the same behavior.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/steveyegge/beads/internal/storage"
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// Prefix is the issue_prefix RunConformance configures on each store.
+const Prefix = "test"
+
+// RunConformance runs the shared storage tests against the backend created
+// by newStore. Each test gets its own store from newStore, which must return
+// an empty, writable store and arrange for it to be closed when t finishes
+// (or skip t if the backend is unavailable).
+func RunConformance(t *testing.T, newStore func(t *testing.T) storage.Storage) {
+	for _, tc := range []struct {
+		name string
+		fn   func(t *testing.T, ctx context.Context, store storage.Storage)
+	}{
+		{"Issues", testIssues},
+		{"UpdateAndClose", testUpdateAndClose},
+		{"ClaimIssue", testClaimIssue},
+		{"Dependencies", testDependencies},
+		{"ReadyAndBlocked", testReadyAndBlocked},
+		{"Labels", testLabels},
+		{"Comments", testComments},
+		{"ConfigAndMetadata", testConfigAndMetadata},
+		{"DuplicateIDs", testDuplicateIDs},
+		{"Events", testEvents},
+		{"DirtyTracking", testDirtyTracking},
+		{"ExportHashes", testExportHashes},
+		{"RunInTransaction", testRunInTransaction},
+		{"Statistics", testStatistics},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+			if err := store.SetConfig(ctx, "issue_prefix", Prefix); err != nil {
+				t.Fatalf("SetConfig(issue_prefix) failed: %v", err)
+			}
+			tc.fn(t, ctx, store)
+		})
+	}
+}
+
+// RunDependencyValidation checks that AddDependency rejects the dependencies
+// the SQLite backend rejects: missing dependent issues, self-dependencies,
+// cycles, invalid types, and parents depending on their children. A missing
+// target is checked by RunMissingTargetValidation instead, since MariaDB
+// stores it as a cross-repo reference. newStore is used as in RunConformance.
+func RunDependencyValidation(t *testing.T, newStore func(t *testing.T) storage.Storage) {
+	ctx := context.Background()
+	store := newStore(t)
+	if err := store.SetConfig(ctx, "issue_prefix", Prefix); err != nil {
+		t.Fatalf("SetConfig(issue_prefix) failed: %v", err)
+	}
+	for _, id := range []string{"test-a", "test-b", "test-c"} {
+		createIssue(t, ctx, store, id, types.TypeTask)
+	}
+	createIssue(t, ctx, store, "test-epic", types.TypeEpic)
+	addDep := func(from, to string, depType types.DependencyType) error {
+		return store.AddDependency(ctx, &types.Dependency{IssueID: from, DependsOnID: to, Type: depType}, "tester")
+	}
+
+	if err := addDep("test-b", "test-a", types.DepBlocks); err != nil {
+		t.Fatalf("AddDependency(b -> a) failed: %v", err)
+	}
+	if err := addDep("test-c", "test-b", types.DepBlocks); err != nil {
+		t.Fatalf("AddDependency(c -> b) failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name     string
+		from, to string
+		depType  types.DependencyType
+	}{
+		{"missing source", "test-missing", "test-a", types.DepBlocks},
+		{"self dependency", "test-a", "test-a", types.DepBlocks},
+		{"cycle", "test-a", "test-c", types.DepBlocks},
+		{"cross-type cycle", "test-a", "test-c", types.DepParentChild},
+		{"invalid type", "test-a", "test-epic", ""},
+		{"parent on its child", "test-epic", "test-a", types.DepParentChild},
+	} {
+		if err := addDep(tc.from, tc.to, tc.depType); err == nil {
+			t.Errorf("AddDependency with a %s should fail", tc.name)
+		}
+	}
+	if err := addDep("test-a", "test-c", types.DepRelatesTo); err != nil {
+		t.Errorf("relates-to links may go both ways: %v", err)
+	}
+}
+
+// RunMissingTargetValidation checks that AddDependency rejects a dependency
+// on an issue that does not exist, for backends that keep no cross-repo
+// references. newStore is used as in RunConformance.
+func RunMissingTargetValidation(t *testing.T, newStore func(t *testing.T) storage.Storage) {
+	ctx := context.Background()
+	store := newStore(t)
+	if err := store.SetConfig(ctx, "issue_prefix", Prefix); err != nil {
+		t.Fatalf("SetConfig(issue_prefix) failed: %v", err)
+	}
+	createIssue(t, ctx, store, "test-a", types.TypeTask)
+	dep := &types.Dependency{IssueID: "test-a", DependsOnID: "test-missing", Type: types.DepBlocks}
+	if err := store.AddDependency(ctx, dep, "tester"); err == nil {
+		t.Error("AddDependency with a missing target should fail")
+	}
+}
+
+// createIssue creates an open P2 issue with the given ID and type.
+func createIssue(t *testing.T, ctx context.Context, store storage.Storage, id string, issueType types.IssueType) {
+	t.Helper()
+	issue := &types.Issue{ID: id, Title: "Issue " + id, Status: types.StatusOpen, Priority: 2, IssueType: issueType}
+	if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue(%s) failed: %v", id, err)
+	}
+}
+
+// mustGet returns issue id, failing the test if it doesn't exist.
+func mustGet(t *testing.T, ctx context.Context, store storage.Storage, id string) *types.Issue {
+	t.Helper()
+	issue, err := store.GetIssue(ctx, id)
+	if err != nil {
+		t.Fatalf("GetIssue(%s) failed: %v", id, err)
+	}
+	if issue == nil {
+		t.Fatalf("GetIssue(%s) = nil, want the issue", id)
+	}
+	return issue
+}
+
+func issueIDs(issues []*types.Issue) []string {
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func testIssues(t *testing.T, ctx context.Context, store storage.Storage) {
+	ref := "gh-42"
+	issue := &types.Issue{
+		ID:          "test-a",
+		Title:       "Fix the frobnicator",
+		Description: "It squeaks",
+		Status:      types.StatusOpen,
+		Priority:    1,
+		IssueType:   types.TypeBug,
+		ExternalRef: &ref,
+	}
+	if err := store.CreateIssue(ctx, issue, "tester"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	got := mustGet(t, ctx, store, "test-a")
+	if got.Title != issue.Title || got.Description != issue.Description || got.Priority != 1 || got.IssueType != types.TypeBug {
+		t.Errorf("GetIssue = %+v, want the created issue", got)
+	}
+	if got.CreatedAt.IsZero() {
+		t.Error("created issue has no created_at")
+	}
+	if byRef, err := store.GetIssueByExternalRef(ctx, ref); err != nil || byRef == nil || byRef.ID != "test-a" {
+		t.Errorf("GetIssueByExternalRef = %v, %v; want test-a", byRef, err)
+	}
+
+	// IDs are unique and generated from the prefix when missing
+	dup := &types.Issue{ID: "test-a", Title: "Again", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, dup, "tester"); err == nil {
+		t.Error("CreateIssue with an existing ID should fail")
+	}
+	generated := &types.Issue{Title: "No ID", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, generated, "tester"); err != nil {
+		t.Fatalf("CreateIssue without an ID failed: %v", err)
+	}
+	if len(generated.ID) <= len(Prefix)+1 || generated.ID[:len(Prefix)+1] != Prefix+"-" {
+		t.Errorf("generated ID %q does not start with %s-", generated.ID, Prefix)
+	}
+	invalid := &types.Issue{Title: "", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, invalid, "tester"); err == nil {
+		t.Error("CreateIssue without a title should fail")
+	}
+
+	batch := []*types.Issue{
+		{ID: "test-b1", Title: "Batch one", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{ID: "test-b2", Title: "Batch two", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+	}
+	if err := store.CreateIssues(ctx, batch, "tester"); err != nil {
+		t.Fatalf("CreateIssues failed: %v", err)
+	}
+	found, err := store.SearchIssues(ctx, "Batch", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if ids := issueIDs(found); !slices.Equal(ids, []string{"test-b1", "test-b2"}) {
+		t.Errorf("SearchIssues(Batch) = %v, want [test-b1 test-b2]", ids)
+	}
+
+	if got, err := store.GetIssue(ctx, "test-missing"); err != nil || got != nil {
+		t.Errorf("GetIssue(missing) = %v, %v; want nil, nil", got, err)
+	}
+
+	if err := store.DeleteIssue(ctx, "test-b1"); err != nil {
+		t.Fatalf("DeleteIssue failed: %v", err)
+	}
+	if got, err := store.GetIssue(ctx, "test-b1"); err != nil || got != nil {
+		t.Errorf("GetIssue after DeleteIssue = %v, %v; want nil, nil", got, err)
+	}
+	if err := store.DeleteIssue(ctx, "test-missing"); err == nil {
+		t.Error("DeleteIssue of a missing issue should fail")
+	}
+}
+
+func testUpdateAndClose(t *testing.T, ctx context.Context, store storage.Storage) {
+	createIssue(t, ctx, store, "test-a", types.TypeTask)
+
+	err := store.UpdateIssue(ctx, "test-a", map[string]interface{}{
+		"title":    "Renamed",
+		"priority": 0,
+		"assignee": "alice",
+	}, "tester")
+	if err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	got := mustGet(t, ctx, store, "test-a")
+	if got.Title != "Renamed" || got.Priority != 0 || got.Assignee != "alice" {
+		t.Errorf("updated issue = %q, P%d, %q; want Renamed, P0, alice", got.Title, got.Priority, got.Assignee)
+	}
+	if err := store.UpdateIssue(ctx, "test-missing", map[string]interface{}{"title": "x"}, "tester"); err == nil {
+		t.Error("UpdateIssue of a missing issue should fail")
+	}
+
+	if err := store.CloseIssue(ctx, "test-a", "done", "tester", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	got = mustGet(t, ctx, store, "test-a")
+	if got.Status != types.StatusClosed || got.ClosedAt == nil {
+		t.Errorf("closed issue has status %q, closed_at %v; want closed with closed_at", got.Status, got.ClosedAt)
+	}
+
+	// Reopening clears closed_at
+	if err := store.UpdateIssue(ctx, "test-a", map[string]interface{}{"status": string(types.StatusOpen)}, "tester"); err != nil {
+		t.Fatalf("reopening UpdateIssue failed: %v", err)
+	}
+	got = mustGet(t, ctx, store, "test-a")
+	if got.Status != types.StatusOpen || got.ClosedAt != nil {
+		t.Errorf("reopened issue has status %q, closed_at %v; want open without closed_at", got.Status, got.ClosedAt)
+	}
+}
+
+func testClaimIssue(t *testing.T, ctx context.Context, store storage.Storage) {
+	createIssue(t, ctx, store, "test-a", types.TypeTask)
+
+	if err := store.ClaimIssue(ctx, "test-a", "alice"); err != nil {
+		t.Fatalf("ClaimIssue failed: %v", err)
+	}
+	got := mustGet(t, ctx, store, "test-a")
+	if got.Assignee != "alice" || got.Status != types.StatusInProgress {
+		t.Errorf("claimed issue has assignee %q, status %q; want alice, in_progress", got.Assignee, got.Status)
+	}
+	if err := store.ClaimIssue(ctx, "test-a", "bob"); !errors.Is(err, storage.ErrAlreadyClaimed) {
+		t.Errorf("second ClaimIssue = %v, want ErrAlreadyClaimed", err)
+	}
+}
+
+func testDependencies(t *testing.T, ctx context.Context, store storage.Storage) {
+	for _, id := range []string{"test-a", "test-b", "test-c"} {
+		createIssue(t, ctx, store, id, types.TypeTask)
+	}
+	addDep := func(from, to string, depType types.DependencyType) error {
+		return store.AddDependency(ctx, &types.Dependency{IssueID: from, DependsOnID: to, Type: depType}, "tester")
+	}
+
+	if err := addDep("test-b", "test-a", types.DepBlocks); err != nil {
+		t.Fatalf("AddDependency(b -> a) failed: %v", err)
+	}
+	if err := addDep("test-c", "test-b", types.DepBlocks); err != nil {
+		t.Fatalf("AddDependency(c -> b) failed: %v", err)
+	}
+	if err := addDep("test-a", "external:other:thing", types.DepBlocks); err != nil {
+		t.Errorf("AddDependency on an external ref failed: %v", err)
+	}
+
+	deps, err := store.GetDependencies(ctx, "test-b")
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if ids := issueIDs(deps); !slices.Equal(ids, []string{"test-a"}) {
+		t.Errorf("GetDependencies(test-b) = %v, want [test-a]", ids)
+	}
+	dependents, err := store.GetDependents(ctx, "test-b")
+	if err != nil {
+		t.Fatalf("GetDependents failed: %v", err)
+	}
+	if ids := issueIDs(dependents); !slices.Equal(ids, []string{"test-c"}) {
+		t.Errorf("GetDependents(test-b) = %v, want [test-c]", ids)
+	}
+	records, err := store.GetDependencyRecords(ctx, "test-b")
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(records) != 1 || records[0].DependsOnID != "test-a" || records[0].Type != types.DepBlocks {
+		t.Errorf("GetDependencyRecords(test-b) = %+v, want one blocks dependency on test-a", records)
+	}
+
+	if err := store.RemoveDependency(ctx, "test-b", "test-a", "tester"); err != nil {
+		t.Fatalf("RemoveDependency failed: %v", err)
+	}
+	if deps, err := store.GetDependencies(ctx, "test-b"); err != nil || len(deps) != 0 {
+		t.Errorf("GetDependencies after RemoveDependency = %v, %v; want none", issueIDs(deps), err)
+	}
+	// With b no longer depending on a, a may now depend on b
+	if err := addDep("test-a", "test-b", types.DepBlocks); err != nil {
+		t.Errorf("AddDependency after removing the cycle's edge failed: %v", err)
+	}
+}
+
+func testReadyAndBlocked(t *testing.T, ctx context.Context, store storage.Storage) {
+	for _, id := range []string{"test-a", "test-b", "test-c"} {
+		createIssue(t, ctx, store, id, types.TypeTask)
+	}
+	for _, dep := range [][2]string{{"test-b", "test-a"}, {"test-c", "test-b"}} {
+		if err := store.AddDependency(ctx, &types.Dependency{IssueID: dep[0], DependsOnID: dep[1], Type: types.DepBlocks}, "tester"); err != nil {
+			t.Fatalf("AddDependency(%s -> %s) failed: %v", dep[0], dep[1], err)
+		}
+	}
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if ids := issueIDs(ready); !slices.Equal(ids, []string{"test-a"}) {
+		t.Errorf("ready issues = %v, want [test-a]", ids)
+	}
+	blocked, err := store.GetBlockedIssues(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetBlockedIssues failed: %v", err)
+	}
+	blockedBy := map[string]int{}
+	for _, b := range blocked {
+		blockedBy[b.ID] = b.BlockedByCount
+	}
+	if blockedBy["test-b"] != 1 || blockedBy["test-c"] != 1 || len(blockedBy) != 2 {
+		t.Errorf("blocked issues = %v, want test-b and test-c blocked by one issue each", blockedBy)
+	}
+	if isBlocked, blockers, err := store.IsBlocked(ctx, "test-b"); err != nil || !isBlocked || !slices.Contains(blockers, "test-a") {
+		t.Errorf("IsBlocked(test-b) = %v, %v, %v; want blocked by test-a", isBlocked, blockers, err)
+	}
+
+	// Closing the blocker unblocks its direct dependent only
+	if err := store.CloseIssue(ctx, "test-a", "done", "tester", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	unblocked, err := store.GetNewlyUnblockedByClose(ctx, "test-a")
+	if err != nil {
+		t.Fatalf("GetNewlyUnblockedByClose failed: %v", err)
+	}
+	if ids := issueIDs(unblocked); !slices.Equal(ids, []string{"test-b"}) {
+		t.Errorf("GetNewlyUnblockedByClose(test-a) = %v, want [test-b]", ids)
+	}
+	ready, err = store.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if ids := issueIDs(ready); !slices.Equal(ids, []string{"test-b"}) {
+		t.Errorf("ready issues after closing test-a = %v, want [test-b]", ids)
+	}
+	if isBlocked, _, err := store.IsBlocked(ctx, "test-b"); err != nil || isBlocked {
+		t.Errorf("IsBlocked(test-b) after closing its blocker = %v, %v; want false", isBlocked, err)
+	}
+}
+
+func testLabels(t *testing.T, ctx context.Context, store storage.Storage) {
+	createIssue(t, ctx, store, "test-a", types.TypeTask)
+	createIssue(t, ctx, store, "test-b", types.TypeTask)
+
+	for _, label := range []string{"ui", "backend"} {
+		if err := store.AddLabel(ctx, "test-a", label, "tester"); err != nil {
+			t.Fatalf("AddLabel(%s) failed: %v", label, err)
+		}
+	}
+	if err := store.AddLabel(ctx, "test-b", "ui", "tester"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	labels, err := store.GetLabels(ctx, "test-a")
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	slices.Sort(labels)
+	if !slices.Equal(labels, []string{"backend", "ui"}) {
+		t.Errorf("GetLabels(test-a) = %v, want [backend ui]", labels)
+	}
+	byLabel, err := store.GetIssuesByLabel(ctx, "ui")
+	if err != nil {
+		t.Fatalf("GetIssuesByLabel failed: %v", err)
+	}
+	if ids := issueIDs(byLabel); !slices.Equal(ids, []string{"test-a", "test-b"}) {
+		t.Errorf("GetIssuesByLabel(ui) = %v, want [test-a test-b]", ids)
+	}
+
+	if err := store.RemoveLabel(ctx, "test-a", "ui", "tester"); err != nil {
+		t.Fatalf("RemoveLabel failed: %v", err)
+	}
+	labels, err = store.GetLabels(ctx, "test-a")
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	if !slices.Equal(labels, []string{"backend"}) {
+		t.Errorf("GetLabels(test-a) after RemoveLabel = %v, want [backend]", labels)
+	}
+}
+
+func testComments(t *testing.T, ctx context.Context, store storage.Storage) {
+	createIssue(t, ctx, store, "test-a", types.TypeTask)
+
+	for _, text := range []string{"First", "Second"} {
+		if _, err := store.AddIssueComment(ctx, "test-a", "alice", text); err != nil {
+			t.Fatalf("AddIssueComment failed: %v", err)
+		}
+	}
+	comments, err := store.GetIssueComments(ctx, "test-a")
+	if err != nil {
+		t.Fatalf("GetIssueComments failed: %v", err)
+	}
+	// Comments added within the same second may come back in either order
+	var texts []string
+	for _, c := range comments {
+		if c.Author != "alice" {
+			t.Errorf("comment %q has author %q, want alice", c.Text, c.Author)
+		}
+		texts = append(texts, c.Text)
+	}
+	slices.Sort(texts)
+	if !slices.Equal(texts, []string{"First", "Second"}) {
+		t.Errorf("GetIssueComments texts = %v, want [First Second]", texts)
+	}
+	counts, err := store.GetCommentCounts(ctx, []string{"test-a"})
+	if err != nil {
+		t.Fatalf("GetCommentCounts failed: %v", err)
+	}
+	if counts["test-a"] != 2 {
+		t.Errorf("GetCommentCounts(test-a) = %d, want 2", counts["test-a"])
+	}
+}
+
+func testConfigAndMetadata(t *testing.T, ctx context.Context, store storage.Storage) {
+	if err := store.SetConfig(ctx, "conformance.key", "one"); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	if err := store.SetConfig(ctx, "conformance.key", "two"); err != nil {
+		t.Fatalf("SetConfig overwrite failed: %v", err)
+	}
+	if value, err := store.GetConfig(ctx, "conformance.key"); err != nil || value != "two" {
+		t.Errorf("GetConfig = %q, %v; want two", value, err)
+	}
+	all, err := store.GetAllConfig(ctx)
+	if err != nil {
+		t.Fatalf("GetAllConfig failed: %v", err)
+	}
+	if all["conformance.key"] != "two" || all["issue_prefix"] != Prefix {
+		t.Errorf("GetAllConfig is missing set keys: %v", all)
+	}
+	if err := store.DeleteConfig(ctx, "conformance.key"); err != nil {
+		t.Fatalf("DeleteConfig failed: %v", err)
+	}
+	if value, err := store.GetConfig(ctx, "conformance.key"); err != nil || value != "" {
+		t.Errorf("GetConfig after DeleteConfig = %q, %v; want empty", value, err)
+	}
+
+	if value, err := store.GetMetadata(ctx, "conformance.missing"); err != nil || value != "" {
+		t.Errorf("GetMetadata(missing) = %q, %v; want empty", value, err)
+	}
+	if err := store.SetMetadata(ctx, "conformance.hash", "abc"); err != nil {
+		t.Fatalf("SetMetadata failed: %v", err)
+	}
+	if value, err := store.GetMetadata(ctx, "conformance.hash"); err != nil || value != "abc" {
+		t.Errorf("GetMetadata = %q, %v; want abc", value, err)
+	}
+}
+
+func testDuplicateIDs(t *testing.T, ctx context.Context, store storage.Storage) {
+	createIssue(t, ctx, store, "test-a", types.TypeTask)
+
+	// A batch with an existing ID, or the same ID twice, creates nothing
+	for name, batch := range map[string][]*types.Issue{
+		"existing ID": {
+			{ID: "test-new", Title: "New", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+			{ID: "test-a", Title: "Again", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		},
+		"repeated ID": {
+			{ID: "test-new", Title: "New", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+			{ID: "test-new", Title: "New again", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		},
+	} {
+		if err := store.CreateIssues(ctx, batch, "tester"); err == nil {
+			t.Errorf("CreateIssues with an %s should fail", name)
+		}
+		if got, err := store.GetIssue(ctx, "test-new"); err != nil || got != nil {
+			t.Errorf("GetIssue(test-new) after CreateIssues with an %s = %v, %v; want nil, nil", name, got, err)
+		}
+	}
+	if got := mustGet(t, ctx, store, "test-a"); got.Title != "Issue test-a" {
+		t.Errorf("existing issue title = %q after a conflicting create, want it unchanged", got.Title)
+	}
+}
+
+func testEvents(t *testing.T, ctx context.Context, store storage.Storage) {
+	createIssue(t, ctx, store, "test-a", types.TypeTask)
+	if err := store.UpdateIssue(ctx, "test-a", map[string]interface{}{"title": "Renamed"}, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	events, err := store.GetEvents(ctx, "test-a", 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	actors := map[types.EventType]string{}
+	for _, e := range events {
+		if e.IssueID != "test-a" {
+			t.Errorf("GetEvents(test-a) returned an event for %s", e.IssueID)
+		}
+		actors[e.EventType] = e.Actor
+	}
+	if actors[types.EventCreated] != "tester" || actors[types.EventUpdated] != "alice" {
+		t.Errorf("event actors = %v, want created by tester and updated by alice", actors)
+	}
+	if limited, err := store.GetEvents(ctx, "test-a", 1); err != nil || len(limited) != 1 {
+		t.Errorf("GetEvents(limit 1) returned %d events, %v; want 1", len(limited), err)
+	}
+}
+
+func testDirtyTracking(t *testing.T, ctx context.Context, store storage.Storage) {
+	createIssue(t, ctx, store, "test-a", types.TypeTask)
+	createIssue(t, ctx, store, "test-b", types.TypeTask)
+
+	dirty, err := store.GetDirtyIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetDirtyIssues failed: %v", err)
+	}
+	slices.Sort(dirty)
+	if !slices.Equal(dirty, []string{"test-a", "test-b"}) {
+		t.Errorf("dirty issues after creating = %v, want [test-a test-b]", dirty)
+	}
+	if err := store.ClearDirtyIssuesByID(ctx, dirty); err != nil {
+		t.Fatalf("ClearDirtyIssuesByID failed: %v", err)
+	}
+	if dirty, err := store.GetDirtyIssues(ctx); err != nil || len(dirty) != 0 {
+		t.Errorf("dirty issues after clearing = %v, %v; want none", dirty, err)
+	}
+
+	// Changing an issue marks it dirty again
+	if err := store.UpdateIssue(ctx, "test-b", map[string]interface{}{"title": "Renamed"}, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if dirty, err := store.GetDirtyIssues(ctx); err != nil || !slices.Equal(dirty, []string{"test-b"}) {
+		t.Errorf("dirty issues after updating test-b = %v, %v; want [test-b]", dirty, err)
+	}
+}
+
+func testExportHashes(t *testing.T, ctx context.Context, store storage.Storage) {
+	createIssue(t, ctx, store, "test-a", types.TypeTask)
+
+	if err := store.SetExportHash(ctx, "test-a", "hash-1"); err != nil {
+		t.Fatalf("SetExportHash failed: %v", err)
+	}
+	hash, err := store.GetExportHash(ctx, "test-a")
+	if err != nil {
+		t.Fatalf("GetExportHash failed: %v", err)
+	}
+	if hash == "" {
+		t.Skip("backend does not track export hashes")
+	}
+	if hash != "hash-1" {
+		t.Errorf("GetExportHash = %q, want hash-1", hash)
+	}
+	if err := store.SetExportHash(ctx, "test-a", "hash-2"); err != nil {
+		t.Fatalf("SetExportHash overwrite failed: %v", err)
+	}
+	if hash, err := store.GetExportHash(ctx, "test-a"); err != nil || hash != "hash-2" {
+		t.Errorf("GetExportHash after overwrite = %q, %v; want hash-2", hash, err)
+	}
+	if err := store.ClearAllExportHashes(ctx); err != nil {
+		t.Fatalf("ClearAllExportHashes failed: %v", err)
+	}
+	if hash, err := store.GetExportHash(ctx, "test-a"); err != nil || hash != "" {
+		t.Errorf("GetExportHash after ClearAllExportHashes = %q, %v; want empty", hash, err)
+	}
+
+	if err := store.SetJSONLFileHash(ctx, "file-hash"); err != nil {
+		t.Fatalf("SetJSONLFileHash failed: %v", err)
+	}
+	if hash, err := store.GetJSONLFileHash(ctx); err != nil || hash != "file-hash" {
+		t.Errorf("GetJSONLFileHash = %q, %v; want file-hash", hash, err)
+	}
+}
+
+func testRunInTransaction(t *testing.T, ctx context.Context, store storage.Storage) {
+	if err := store.RunInTransaction(ctx, func(tx storage.Transaction) error { return nil }); err != nil {
+		t.Skipf("backend does not support transactions: %v", err)
+	}
+
+	err := store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		issue := &types.Issue{ID: "test-a", Title: "In a transaction", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := tx.CreateIssue(ctx, issue, "tester"); err != nil {
+			return err
+		}
+		if err := tx.AddLabel(ctx, "test-a", "atomic", "tester"); err != nil {
+			return err
+		}
+		// Reads inside the transaction see its own writes
+		if got, err := tx.GetIssue(ctx, "test-a"); err != nil || got == nil {
+			t.Errorf("GetIssue inside the transaction = %v, %v; want the new issue", got, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RunInTransaction failed: %v", err)
+	}
+	mustGet(t, ctx, store, "test-a")
+	if labels, err := store.GetLabels(ctx, "test-a"); err != nil || !slices.Equal(labels, []string{"atomic"}) {
+		t.Errorf("GetLabels after commit = %v, %v; want [atomic]", labels, err)
+	}
+
+	// An error rolls back everything the function wrote
+	errRollback := errors.New("roll back")
+	err = store.RunInTransaction(ctx, func(tx storage.Transaction) error {
+		issue := &types.Issue{ID: "test-b", Title: "Rolled back", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := tx.CreateIssue(ctx, issue, "tester"); err != nil {
+			return err
+		}
+		if err := tx.UpdateIssue(ctx, "test-a", map[string]interface{}{"title": "Changed"}, "tester"); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Errorf("RunInTransaction error = %v, want the function's error", err)
+	}
+	if got, err := store.GetIssue(ctx, "test-b"); err != nil || got != nil {
+		t.Errorf("GetIssue(test-b) after rollback = %v, %v; want nil, nil", got, err)
+	}
+	if got := mustGet(t, ctx, store, "test-a"); got.Title != "In a transaction" {
+		t.Errorf("test-a title after rollback = %q, want it unchanged", got.Title)
+	}
+}
+
+func testStatistics(t *testing.T, ctx context.Context, store storage.Storage) {
+	for _, id := range []string{"test-a", "test-b", "test-c"} {
+		createIssue(t, ctx, store, id, types.TypeTask)
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: "test-b", DependsOnID: "test-a", Type: types.DepBlocks}, "tester"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := store.CloseIssue(ctx, "test-c", "done", "tester", ""); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	stats, err := store.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics failed: %v", err)
+	}
+	if stats.TotalIssues != 3 || stats.OpenIssues != 2 || stats.ClosedIssues != 1 {
+		t.Errorf("total, open, closed = %d, %d, %d; want 3, 2, 1", stats.TotalIssues, stats.OpenIssues, stats.ClosedIssues)
+	}
+	if stats.BlockedIssues != 1 || stats.ReadyIssues != 1 {
+		t.Errorf("blocked, ready = %d, %d; want 1, 1", stats.BlockedIssues, stats.ReadyIssues)
+	}
+}
@@ -0,0 +1,474 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// WithTracing wraps s so that every Storage method runs in an OpenTelemetry
+// span named "storage.<Method>". Spans carry the method name and the
+// database from s.Path() (the database name for server backends, the file
+// path for SQLite), and are marked as errored when the method fails.
+// Path, UnderlyingDB and Capabilities are passed through without a span.
+//
+// Operations inside RunInTransaction are covered by its span but not traced
+// individually. Use Unwrap to reach backend-specific methods.
+func WithTracing(s Storage, tracer trace.Tracer) Storage {
+	return &tracingStorage{
+		s:      s,
+		tracer: tracer,
+		attrs:  []attribute.KeyValue{attribute.String("db.namespace", s.Path())},
+	}
+}
+
+// Unwrap returns the storage wrapped by WithTracing or WithCache, or s itself
+// if s is neither.
+func Unwrap(s Storage) Storage {
+	switch w := s.(type) {
+	case *tracingStorage:
+		return w.s
+	case *cacheStorage:
+		return w.s
+	}
+	return s
+}
+
+// tracingStorage implements WithTracing.
+type tracingStorage struct {
+	s      Storage
+	tracer trace.Tracer
+	attrs  []attribute.KeyValue
+}
+
+var _ Storage = (*tracingStorage)(nil)
+
+func (t *tracingStorage) start(ctx context.Context, op string) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, "storage."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(t.attrs...),
+		trace.WithAttributes(attribute.String("db.operation.name", op)))
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (t *tracingStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) (err error) {
+	ctx, span := t.start(ctx, "CreateIssue")
+	defer func() { endSpan(span, err) }()
+	return t.s.CreateIssue(ctx, issue, actor)
+}
+
+func (t *tracingStorage) CreateIssues(ctx context.Context, issues []*types.Issue, actor string) (err error) {
+	ctx, span := t.start(ctx, "CreateIssues")
+	defer func() { endSpan(span, err) }()
+	return t.s.CreateIssues(ctx, issues, actor)
+}
+
+func (t *tracingStorage) CreateIssuesWithFullOptions(ctx context.Context, issues []*types.Issue, actor string, opts BatchCreateOptions) (err error) {
+	ctx, span := t.start(ctx, "CreateIssuesWithFullOptions")
+	defer func() { endSpan(span, err) }()
+	return t.s.CreateIssuesWithFullOptions(ctx, issues, actor, opts)
+}
+
+func (t *tracingStorage) GetIssue(ctx context.Context, id string) (_ *types.Issue, err error) {
+	ctx, span := t.start(ctx, "GetIssue")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetIssue(ctx, id)
+}
+
+func (t *tracingStorage) GetIssueByExternalRef(ctx context.Context, externalRef string) (_ *types.Issue, err error) {
+	ctx, span := t.start(ctx, "GetIssueByExternalRef")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetIssueByExternalRef(ctx, externalRef)
+}
+
+func (t *tracingStorage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) (err error) {
+	ctx, span := t.start(ctx, "UpdateIssue")
+	defer func() { endSpan(span, err) }()
+	return t.s.UpdateIssue(ctx, id, updates, actor)
+}
+
+func (t *tracingStorage) ClaimIssue(ctx context.Context, id string, actor string) (err error) {
+	ctx, span := t.start(ctx, "ClaimIssue")
+	defer func() { endSpan(span, err) }()
+	return t.s.ClaimIssue(ctx, id, actor)
+}
+
+func (t *tracingStorage) CloseIssue(ctx context.Context, id string, reason string, actor string, session string) (err error) {
+	ctx, span := t.start(ctx, "CloseIssue")
+	defer func() { endSpan(span, err) }()
+	return t.s.CloseIssue(ctx, id, reason, actor, session)
+}
+
+func (t *tracingStorage) DeleteIssue(ctx context.Context, id string) (err error) {
+	ctx, span := t.start(ctx, "DeleteIssue")
+	defer func() { endSpan(span, err) }()
+	return t.s.DeleteIssue(ctx, id)
+}
+
+func (t *tracingStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) (_ []*types.Issue, err error) {
+	ctx, span := t.start(ctx, "SearchIssues")
+	defer func() { endSpan(span, err) }()
+	return t.s.SearchIssues(ctx, query, filter)
+}
+
+func (t *tracingStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) (err error) {
+	ctx, span := t.start(ctx, "AddDependency")
+	defer func() { endSpan(span, err) }()
+	return t.s.AddDependency(ctx, dep, actor)
+}
+
+func (t *tracingStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) (err error) {
+	ctx, span := t.start(ctx, "RemoveDependency")
+	defer func() { endSpan(span, err) }()
+	return t.s.RemoveDependency(ctx, issueID, dependsOnID, actor)
+}
+
+func (t *tracingStorage) GetDependencies(ctx context.Context, issueID string) (_ []*types.Issue, err error) {
+	ctx, span := t.start(ctx, "GetDependencies")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetDependencies(ctx, issueID)
+}
+
+func (t *tracingStorage) GetDependents(ctx context.Context, issueID string) (_ []*types.Issue, err error) {
+	ctx, span := t.start(ctx, "GetDependents")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetDependents(ctx, issueID)
+}
+
+func (t *tracingStorage) GetDependenciesWithMetadata(ctx context.Context, issueID string) (_ []*types.IssueWithDependencyMetadata, err error) {
+	ctx, span := t.start(ctx, "GetDependenciesWithMetadata")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetDependenciesWithMetadata(ctx, issueID)
+}
+
+func (t *tracingStorage) GetDependentsWithMetadata(ctx context.Context, issueID string) (_ []*types.IssueWithDependencyMetadata, err error) {
+	ctx, span := t.start(ctx, "GetDependentsWithMetadata")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetDependentsWithMetadata(ctx, issueID)
+}
+
+func (t *tracingStorage) GetDependencyRecords(ctx context.Context, issueID string) (_ []*types.Dependency, err error) {
+	ctx, span := t.start(ctx, "GetDependencyRecords")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetDependencyRecords(ctx, issueID)
+}
+
+func (t *tracingStorage) GetAllDependencyRecords(ctx context.Context) (_ map[string][]*types.Dependency, err error) {
+	ctx, span := t.start(ctx, "GetAllDependencyRecords")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetAllDependencyRecords(ctx)
+}
+
+func (t *tracingStorage) GetDependencyRecordsForIssues(ctx context.Context, issueIDs []string) (_ map[string][]*types.Dependency, err error) {
+	ctx, span := t.start(ctx, "GetDependencyRecordsForIssues")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetDependencyRecordsForIssues(ctx, issueIDs)
+}
+
+func (t *tracingStorage) GetDependencyCounts(ctx context.Context, issueIDs []string) (_ map[string]*types.DependencyCounts, err error) {
+	ctx, span := t.start(ctx, "GetDependencyCounts")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetDependencyCounts(ctx, issueIDs)
+}
+
+func (t *tracingStorage) GetDependencyTree(ctx context.Context, issueID string, maxDepth int, showAllPaths bool, reverse bool) (_ []*types.TreeNode, err error) {
+	ctx, span := t.start(ctx, "GetDependencyTree")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetDependencyTree(ctx, issueID, maxDepth, showAllPaths, reverse)
+}
+
+func (t *tracingStorage) DetectCycles(ctx context.Context) (_ [][]*types.Issue, err error) {
+	ctx, span := t.start(ctx, "DetectCycles")
+	defer func() { endSpan(span, err) }()
+	return t.s.DetectCycles(ctx)
+}
+
+func (t *tracingStorage) AddLabel(ctx context.Context, issueID, label, actor string) (err error) {
+	ctx, span := t.start(ctx, "AddLabel")
+	defer func() { endSpan(span, err) }()
+	return t.s.AddLabel(ctx, issueID, label, actor)
+}
+
+func (t *tracingStorage) RemoveLabel(ctx context.Context, issueID, label, actor string) (err error) {
+	ctx, span := t.start(ctx, "RemoveLabel")
+	defer func() { endSpan(span, err) }()
+	return t.s.RemoveLabel(ctx, issueID, label, actor)
+}
+
+func (t *tracingStorage) GetLabels(ctx context.Context, issueID string) (_ []string, err error) {
+	ctx, span := t.start(ctx, "GetLabels")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetLabels(ctx, issueID)
+}
+
+func (t *tracingStorage) GetLabelsForIssues(ctx context.Context, issueIDs []string) (_ map[string][]string, err error) {
+	ctx, span := t.start(ctx, "GetLabelsForIssues")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetLabelsForIssues(ctx, issueIDs)
+}
+
+func (t *tracingStorage) GetIssuesByLabel(ctx context.Context, label string) (_ []*types.Issue, err error) {
+	ctx, span := t.start(ctx, "GetIssuesByLabel")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetIssuesByLabel(ctx, label)
+}
+
+func (t *tracingStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) (_ []*types.Issue, err error) {
+	ctx, span := t.start(ctx, "GetReadyWork")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetReadyWork(ctx, filter)
+}
+
+func (t *tracingStorage) GetBlockedIssues(ctx context.Context, filter types.WorkFilter) (_ []*types.BlockedIssue, err error) {
+	ctx, span := t.start(ctx, "GetBlockedIssues")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetBlockedIssues(ctx, filter)
+}
+
+func (t *tracingStorage) IsBlocked(ctx context.Context, issueID string) (_ bool, _ []string, err error) {
+	ctx, span := t.start(ctx, "IsBlocked")
+	defer func() { endSpan(span, err) }()
+	return t.s.IsBlocked(ctx, issueID)
+}
+
+func (t *tracingStorage) GetEpicsEligibleForClosure(ctx context.Context) (_ []*types.EpicStatus, err error) {
+	ctx, span := t.start(ctx, "GetEpicsEligibleForClosure")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetEpicsEligibleForClosure(ctx)
+}
+
+func (t *tracingStorage) GetStaleIssues(ctx context.Context, filter types.StaleFilter) (_ []*types.Issue, err error) {
+	ctx, span := t.start(ctx, "GetStaleIssues")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetStaleIssues(ctx, filter)
+}
+
+func (t *tracingStorage) GetNewlyUnblockedByClose(ctx context.Context, closedIssueID string) (_ []*types.Issue, err error) {
+	ctx, span := t.start(ctx, "GetNewlyUnblockedByClose")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetNewlyUnblockedByClose(ctx, closedIssueID)
+}
+
+func (t *tracingStorage) AddComment(ctx context.Context, issueID, actor, comment string) (err error) {
+	ctx, span := t.start(ctx, "AddComment")
+	defer func() { endSpan(span, err) }()
+	return t.s.AddComment(ctx, issueID, actor, comment)
+}
+
+func (t *tracingStorage) GetEvents(ctx context.Context, issueID string, limit int) (_ []*types.Event, err error) {
+	ctx, span := t.start(ctx, "GetEvents")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetEvents(ctx, issueID, limit)
+}
+
+func (t *tracingStorage) GetAllEventsSince(ctx context.Context, sinceID int64) (_ []*types.Event, err error) {
+	ctx, span := t.start(ctx, "GetAllEventsSince")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetAllEventsSince(ctx, sinceID)
+}
+
+func (t *tracingStorage) AddIssueComment(ctx context.Context, issueID, author, text string) (_ *types.Comment, err error) {
+	ctx, span := t.start(ctx, "AddIssueComment")
+	defer func() { endSpan(span, err) }()
+	return t.s.AddIssueComment(ctx, issueID, author, text)
+}
+
+func (t *tracingStorage) ImportIssueComment(ctx context.Context, issueID, author, text string, createdAt time.Time) (_ *types.Comment, err error) {
+	ctx, span := t.start(ctx, "ImportIssueComment")
+	defer func() { endSpan(span, err) }()
+	return t.s.ImportIssueComment(ctx, issueID, author, text, createdAt)
+}
+
+func (t *tracingStorage) GetIssueComments(ctx context.Context, issueID string) (_ []*types.Comment, err error) {
+	ctx, span := t.start(ctx, "GetIssueComments")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetIssueComments(ctx, issueID)
+}
+
+func (t *tracingStorage) GetCommentsForIssues(ctx context.Context, issueIDs []string) (_ map[string][]*types.Comment, err error) {
+	ctx, span := t.start(ctx, "GetCommentsForIssues")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetCommentsForIssues(ctx, issueIDs)
+}
+
+func (t *tracingStorage) GetCommentCounts(ctx context.Context, issueIDs []string) (_ map[string]int, err error) {
+	ctx, span := t.start(ctx, "GetCommentCounts")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetCommentCounts(ctx, issueIDs)
+}
+
+func (t *tracingStorage) GetStatistics(ctx context.Context) (_ *types.Statistics, err error) {
+	ctx, span := t.start(ctx, "GetStatistics")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetStatistics(ctx)
+}
+
+func (t *tracingStorage) GetMoleculeProgress(ctx context.Context, moleculeID string) (_ *types.MoleculeProgressStats, err error) {
+	ctx, span := t.start(ctx, "GetMoleculeProgress")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetMoleculeProgress(ctx, moleculeID)
+}
+
+func (t *tracingStorage) GetDirtyIssues(ctx context.Context) (_ []string, err error) {
+	ctx, span := t.start(ctx, "GetDirtyIssues")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetDirtyIssues(ctx)
+}
+
+func (t *tracingStorage) GetDirtyIssueHash(ctx context.Context, issueID string) (_ string, err error) {
+	ctx, span := t.start(ctx, "GetDirtyIssueHash")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetDirtyIssueHash(ctx, issueID)
+}
+
+func (t *tracingStorage) ClearDirtyIssuesByID(ctx context.Context, issueIDs []string) (err error) {
+	ctx, span := t.start(ctx, "ClearDirtyIssuesByID")
+	defer func() { endSpan(span, err) }()
+	return t.s.ClearDirtyIssuesByID(ctx, issueIDs)
+}
+
+func (t *tracingStorage) GetExportHash(ctx context.Context, issueID string) (_ string, err error) {
+	ctx, span := t.start(ctx, "GetExportHash")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetExportHash(ctx, issueID)
+}
+
+func (t *tracingStorage) SetExportHash(ctx context.Context, issueID, contentHash string) (err error) {
+	ctx, span := t.start(ctx, "SetExportHash")
+	defer func() { endSpan(span, err) }()
+	return t.s.SetExportHash(ctx, issueID, contentHash)
+}
+
+func (t *tracingStorage) ClearAllExportHashes(ctx context.Context) (err error) {
+	ctx, span := t.start(ctx, "ClearAllExportHashes")
+	defer func() { endSpan(span, err) }()
+	return t.s.ClearAllExportHashes(ctx)
+}
+
+func (t *tracingStorage) GetJSONLFileHash(ctx context.Context) (_ string, err error) {
+	ctx, span := t.start(ctx, "GetJSONLFileHash")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetJSONLFileHash(ctx)
+}
+
+func (t *tracingStorage) SetJSONLFileHash(ctx context.Context, fileHash string) (err error) {
+	ctx, span := t.start(ctx, "SetJSONLFileHash")
+	defer func() { endSpan(span, err) }()
+	return t.s.SetJSONLFileHash(ctx, fileHash)
+}
+
+func (t *tracingStorage) GetNextChildID(ctx context.Context, parentID string) (_ string, err error) {
+	ctx, span := t.start(ctx, "GetNextChildID")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetNextChildID(ctx, parentID)
+}
+
+func (t *tracingStorage) SetConfig(ctx context.Context, key, value string) (err error) {
+	ctx, span := t.start(ctx, "SetConfig")
+	defer func() { endSpan(span, err) }()
+	return t.s.SetConfig(ctx, key, value)
+}
+
+func (t *tracingStorage) GetConfig(ctx context.Context, key string) (_ string, err error) {
+	ctx, span := t.start(ctx, "GetConfig")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetConfig(ctx, key)
+}
+
+func (t *tracingStorage) GetAllConfig(ctx context.Context) (_ map[string]string, err error) {
+	ctx, span := t.start(ctx, "GetAllConfig")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetAllConfig(ctx)
+}
+
+func (t *tracingStorage) DeleteConfig(ctx context.Context, key string) (err error) {
+	ctx, span := t.start(ctx, "DeleteConfig")
+	defer func() { endSpan(span, err) }()
+	return t.s.DeleteConfig(ctx, key)
+}
+
+func (t *tracingStorage) GetCustomStatuses(ctx context.Context) (_ []string, err error) {
+	ctx, span := t.start(ctx, "GetCustomStatuses")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetCustomStatuses(ctx)
+}
+
+func (t *tracingStorage) GetCustomTypes(ctx context.Context) (_ []string, err error) {
+	ctx, span := t.start(ctx, "GetCustomTypes")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetCustomTypes(ctx)
+}
+
+func (t *tracingStorage) SetMetadata(ctx context.Context, key, value string) (err error) {
+	ctx, span := t.start(ctx, "SetMetadata")
+	defer func() { endSpan(span, err) }()
+	return t.s.SetMetadata(ctx, key, value)
+}
+
+func (t *tracingStorage) GetMetadata(ctx context.Context, key string) (_ string, err error) {
+	ctx, span := t.start(ctx, "GetMetadata")
+	defer func() { endSpan(span, err) }()
+	return t.s.GetMetadata(ctx, key)
+}
+
+func (t *tracingStorage) UpdateIssueID(ctx context.Context, oldID, newID string, issue *types.Issue, actor string) (err error) {
+	ctx, span := t.start(ctx, "UpdateIssueID")
+	defer func() { endSpan(span, err) }()
+	return t.s.UpdateIssueID(ctx, oldID, newID, issue, actor)
+}
+
+func (t *tracingStorage) RenameDependencyPrefix(ctx context.Context, oldPrefix, newPrefix string) (err error) {
+	ctx, span := t.start(ctx, "RenameDependencyPrefix")
+	defer func() { endSpan(span, err) }()
+	return t.s.RenameDependencyPrefix(ctx, oldPrefix, newPrefix)
+}
+
+func (t *tracingStorage) RenameCounterPrefix(ctx context.Context, oldPrefix, newPrefix string) (err error) {
+	ctx, span := t.start(ctx, "RenameCounterPrefix")
+	defer func() { endSpan(span, err) }()
+	return t.s.RenameCounterPrefix(ctx, oldPrefix, newPrefix)
+}
+
+func (t *tracingStorage) RunInTransaction(ctx context.Context, fn func(tx Transaction) error) (err error) {
+	ctx, span := t.start(ctx, "RunInTransaction")
+	defer func() { endSpan(span, err) }()
+	return t.s.RunInTransaction(ctx, fn)
+}
+
+func (t *tracingStorage) Close() (err error) {
+	_, span := t.start(context.Background(), "Close")
+	defer func() { endSpan(span, err) }()
+	return t.s.Close()
+}
+
+func (t *tracingStorage) Path() string {
+	return t.s.Path()
+}
+
+func (t *tracingStorage) UnderlyingDB() *sql.DB {
+	return t.s.UnderlyingDB()
+}
+
+func (t *tracingStorage) UnderlyingConn(ctx context.Context) (_ *sql.Conn, err error) {
+	ctx, span := t.start(ctx, "UnderlyingConn")
+	defer func() { endSpan(span, err) }()
+	return t.s.UnderlyingConn(ctx)
+}
+
+func (t *tracingStorage) Capabilities() Capabilities {
+	return t.s.Capabilities()
+}
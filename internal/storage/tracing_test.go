@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/steveyegge/beads/internal/types"
+)
+
+// namedMockStorage reports a database name and fails GetIssue.
+type namedMockStorage struct {
+	mockStorage
+}
+
+var errMockGetIssue = errors.New("get issue failed")
+
+func (m *namedMockStorage) Path() string { return "beads" }
+
+func (m *namedMockStorage) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	return nil, errMockGetIssue
+}
+
+func newTracedMock() (Storage, *tracetest.SpanRecorder) {
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	return WithTracing(&namedMockStorage{}, tp.Tracer("beads/storage")), rec
+}
+
+func TestWithTracingSpanPerOperation(t *testing.T) {
+	store, rec := newTracedMock()
+
+	// Call every Storage method with zero arguments
+	untraced := map[string]bool{"Path": true, "UnderlyingDB": true, "Capabilities": true}
+	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	iface := reflect.TypeOf((*Storage)(nil)).Elem()
+	v := reflect.ValueOf(store)
+	var traced []string
+	for i := 0; i < iface.NumMethod(); i++ {
+		m := iface.Method(i)
+		args := make([]reflect.Value, m.Type.NumIn())
+		for j := range args {
+			if in := m.Type.In(j); in == ctxType {
+				args[j] = reflect.ValueOf(context.Background())
+			} else {
+				args[j] = reflect.Zero(in)
+			}
+		}
+		v.MethodByName(m.Name).Call(args)
+		if !untraced[m.Name] {
+			traced = append(traced, m.Name)
+		}
+	}
+
+	spans := rec.Ended()
+	if len(spans) != len(traced) {
+		t.Fatalf("got %d spans, want one per operation (%d)", len(spans), len(traced))
+	}
+	for i, span := range spans {
+		if want := "storage." + traced[i]; span.Name() != want {
+			t.Errorf("span %d = %q, want %q", i, span.Name(), want)
+		}
+		attrs := map[string]string{}
+		for _, kv := range span.Attributes() {
+			attrs[string(kv.Key)] = kv.Value.Emit()
+		}
+		if attrs["db.operation.name"] != traced[i] || attrs["db.namespace"] != "beads" {
+			t.Errorf("span %s attributes = %v, want operation and database name", span.Name(), attrs)
+		}
+	}
+}
+
+func TestWithTracingRecordsErrors(t *testing.T) {
+	store, rec := newTracedMock()
+	ctx := context.Background()
+
+	if _, err := store.GetIssue(ctx, "bd-1"); !errors.Is(err, errMockGetIssue) {
+		t.Fatalf("GetIssue = %v, want the backend error", err)
+	}
+	if err := store.DeleteIssue(ctx, "bd-1"); err != nil {
+		t.Fatalf("DeleteIssue failed: %v", err)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if got := spans[0].Status(); got.Code != codes.Error || got.Description != errMockGetIssue.Error() {
+		t.Errorf("GetIssue span status = %+v, want error", got)
+	}
+	if len(spans[0].Events()) == 0 {
+		t.Error("GetIssue span should record the error as an event")
+	}
+	if got := spans[1].Status().Code; got == codes.Error {
+		t.Errorf("DeleteIssue span status = %v, want unset", got)
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	inner := &mockStorage{}
+	traced := WithTracing(inner, sdktrace.NewTracerProvider().Tracer("beads/storage"))
+	if Unwrap(traced) != inner {
+		t.Error("Unwrap should return the wrapped storage")
+	}
+	if Unwrap(inner) != inner {
+		t.Error("Unwrap of an unwrapped storage should return it unchanged")
+	}
+}
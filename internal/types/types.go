@@ -319,6 +319,15 @@ func (i *Issue) IsExpired(ttl time.Duration) bool {
 	return time.Now().After(expirationTime)
 }
 
+// ValidatePriority checks that p is a priority from 0 (P0, critical) to 4
+// (P4, backlog).
+func ValidatePriority(p int) error {
+	if p < 0 || p > 4 {
+		return fmt.Errorf("priority must be between 0 and 4 (got %d)", p)
+	}
+	return nil
+}
+
 // Validate checks if the issue has valid field values (built-in statuses only)
 func (i *Issue) Validate() error {
 	return i.ValidateWithCustomStatuses(nil)
@@ -339,8 +348,8 @@ func (i *Issue) ValidateWithCustom(customStatuses, customTypes []string) error {
 	if len(i.Title) > 500 {
 		return fmt.Errorf("title must be 500 characters or less (got %d)", len(i.Title))
 	}
-	if i.Priority < 0 || i.Priority > 4 {
-		return fmt.Errorf("priority must be between 0 and 4 (got %d)", i.Priority)
+	if err := ValidatePriority(i.Priority); err != nil {
+		return err
 	}
 	if !i.Status.IsValidWithCustom(customStatuses) {
 		return fmt.Errorf("invalid status: %s", i.Status)
@@ -390,8 +399,8 @@ func (i *Issue) ValidateForImport(customStatuses []string) error {
 	if len(i.Title) > 500 {
 		return fmt.Errorf("title must be 500 characters or less (got %d)", len(i.Title))
 	}
-	if i.Priority < 0 || i.Priority > 4 {
-		return fmt.Errorf("priority must be between 0 and 4 (got %d)", i.Priority)
+	if err := ValidatePriority(i.Priority); err != nil {
+		return err
 	}
 	if !i.Status.IsValidWithCustom(customStatuses) {
 		return fmt.Errorf("invalid status: %s", i.Status)
@@ -990,6 +999,9 @@ type IssueFilter struct {
 	// Tombstone filtering
 	IncludeTombstones bool // If false (default), exclude tombstones from results
 
+	// Soft-delete filtering (MariaDB)
+	IncludeDeleted bool // If false (default), exclude soft-deleted issues from results
+
 	// Ephemeral filtering
 	Ephemeral *bool // Filter by ephemeral flag (nil = any, true = only ephemeral, false = only persistent)
 